@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/caarlos0/env/v11"
 )
@@ -20,6 +21,12 @@ type (
 		Swagger Swagger
 		JWT     JWT
 		Admin   Admin
+		Jobs    Jobs
+		Media   Media
+		Reports Reports
+		Items   Items
+		Locale  Locale
+		Exams   Exams
 	}
 
 	// App -.
@@ -32,6 +39,7 @@ type (
 	HTTP struct {
 		Port           string `env:"HTTP_PORT,required"`
 		UsePreforkMode bool   `env:"HTTP_USE_PREFORK_MODE" envDefault:"false"`
+		BodyLimitMB    int    `env:"HTTP_BODY_LIMIT_MB" envDefault:"32"`
 	}
 
 	// Log -.
@@ -78,6 +86,10 @@ type (
 		TokenTTLMinutes int    `env:"JWT_TOKEN_TTL_MINUTES" envDefault:"1440"`
 	}
 
+	// Admin is the console account. ReviewerIDs are the admin identities
+	// that can review questions, the console account when empty. SelfReview
+	// lets reviewers review their own changes; unset, it is on only when
+	// there is a single reviewer.
 	Admin struct {
 		Username     string   `env:"ADMIN_USERNAME,required"`
 		Password     string   `env:"ADMIN_PASSWORD,required"`
@@ -88,6 +100,59 @@ type (
 		Role         string   `env:"ADMIN_ROLE" envDefault:"ADMIN"`
 		Permissions  []string `env:"ADMIN_PERMISSIONS" envDefault:"subjects.read,subjects.write" envSeparator:","`
 		CreatedAtISO string   `env:"ADMIN_CREATED_AT"`
+		ReviewerIDs  []string `env:"ADMIN_REVIEWER_IDS" envSeparator:","`
+		SelfReview   *bool    `env:"ADMIN_SELF_REVIEW"`
+	}
+
+	// Jobs -.
+	Jobs struct {
+		PracticeExpiryInterval    time.Duration `env:"JOBS_PRACTICE_EXPIRY_INTERVAL" envDefault:"1m"`
+		ExamGradingInterval       time.Duration `env:"JOBS_EXAM_GRADING_INTERVAL" envDefault:"1m"`
+		QuestionImportInterval    time.Duration `env:"JOBS_QUESTION_IMPORT_INTERVAL" envDefault:"10s"`
+		QuestionSignatureInterval time.Duration `env:"JOBS_QUESTION_SIGNATURE_INTERVAL" envDefault:"30s"`
+		ItemAnalysisInterval      time.Duration `env:"JOBS_ITEM_ANALYSIS_INTERVAL" envDefault:"24h"`
+		TranslationInterval       time.Duration `env:"JOBS_TRANSLATION_INTERVAL" envDefault:"10m"`
+	}
+
+	// Media configures question image storage. BaseURL is the public URL of
+	// the /v1/media route that serves the stored files. Without SigningKey
+	// one is derived from JWT_ADMIN_SECRET.
+	Media struct {
+		Dir        string        `env:"MEDIA_DIR" envDefault:"./data/media"`
+		BaseURL    string        `env:"MEDIA_BASE_URL" envDefault:"http://localhost:8080/v1/media"`
+		SigningKey string        `env:"MEDIA_SIGNING_KEY"`
+		URLTTL     time.Duration `env:"MEDIA_URL_TTL" envDefault:"1h"`
+		MaxSizeMB  int           `env:"MEDIA_MAX_SIZE_MB" envDefault:"5"`
+	}
+
+	// Reports tunes learner reports of question errors. AutoRetireAt is the
+	// number of open reports that retires a published question; 0 turns it off.
+	Reports struct {
+		HourlyLimit  int `env:"REPORTS_HOURLY_LIMIT" envDefault:"10"`
+		AutoRetireAt int `env:"REPORTS_AUTO_RETIRE_AT" envDefault:"0"`
+	}
+
+	// Items tunes item analysis. MinAttempts is how many first attempts a
+	// question needs before it is flagged or its difficulty recalibrated.
+	Items struct {
+		MinAttempts int `env:"ITEMS_MIN_ATTEMPTS" envDefault:"30"`
+	}
+
+	// Locale lists the languages the question bank is machine-translated
+	// into, besides English, and how many questions each translation run
+	// handles per language.
+	Locale struct {
+		Languages []string `env:"LOCALE_LANGUAGES" envDefault:"hi" envSeparator:","`
+		BatchSize int      `env:"LOCALE_BATCH_SIZE" envDefault:"50"`
+	}
+
+	// Exams sets the refund policy for cancelled registrations: the whole fee
+	// until FullRefundBefore the start, PartialRefundPercent of it until
+	// PartialRefundBefore, and nothing after.
+	Exams struct {
+		FullRefundBefore     time.Duration `env:"EXAMS_FULL_REFUND_BEFORE" envDefault:"24h"`
+		PartialRefundBefore  time.Duration `env:"EXAMS_PARTIAL_REFUND_BEFORE" envDefault:"2h"`
+		PartialRefundPercent int           `env:"EXAMS_PARTIAL_REFUND_PERCENT" envDefault:"50"`
 	}
 )
 
@@ -2,9 +2,13 @@
 package app
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"os/signal"
+	"slices"
 	"strings"
 	"syscall"
 	"time"
@@ -27,15 +31,23 @@ import (
 	"github.com/evrone/go-clean-template/internal/usecase/coupon"
 	"github.com/evrone/go-clean-template/internal/usecase/exam"
 	"github.com/evrone/go-clean-template/internal/usecase/feed"
+	"github.com/evrone/go-clean-template/internal/usecase/itemanalysis"
 	"github.com/evrone/go-clean-template/internal/usecase/leaderboard"
+	"github.com/evrone/go-clean-template/internal/usecase/localization"
+	"github.com/evrone/go-clean-template/internal/usecase/media"
+	"github.com/evrone/go-clean-template/internal/usecase/notification"
 	"github.com/evrone/go-clean-template/internal/usecase/podcast"
 	"github.com/evrone/go-clean-template/internal/usecase/practice"
 	"github.com/evrone/go-clean-template/internal/usecase/question"
 	"github.com/evrone/go-clean-template/internal/usecase/referral"
+	"github.com/evrone/go-clean-template/internal/usecase/report"
 	"github.com/evrone/go-clean-template/internal/usecase/revision"
+	"github.com/evrone/go-clean-template/internal/usecase/syllabus"
+	"github.com/evrone/go-clean-template/internal/usecase/tag"
 	"github.com/evrone/go-clean-template/internal/usecase/translation"
 	"github.com/evrone/go-clean-template/internal/usecase/user"
 	"github.com/evrone/go-clean-template/internal/usecase/wallet"
+	"github.com/evrone/go-clean-template/pkg/blob"
 	"github.com/evrone/go-clean-template/pkg/grpcserver"
 	"github.com/evrone/go-clean-template/pkg/httpserver"
 	"github.com/evrone/go-clean-template/pkg/jwt"
@@ -88,6 +100,30 @@ func Run(cfg *config.Config) { //nolint: gocyclo,cyclop,funlen,gocritic,nolintli
 		perms = []string{"subjects.read", "subjects.write"}
 	}
 
+	reviewers := make([]uuid.UUID, 0, len(cfg.Admin.ReviewerIDs))
+	for _, raw := range cfg.Admin.ReviewerIDs {
+		trimmed := strings.TrimSpace(raw)
+		if trimmed == "" {
+			continue
+		}
+
+		reviewerID, err := uuid.Parse(trimmed)
+		if err != nil {
+			l.Fatal(fmt.Errorf("app - Run - invalid ADMIN_REVIEWER_IDS: %w", err))
+		}
+		if !slices.Contains(reviewers, reviewerID) {
+			reviewers = append(reviewers, reviewerID)
+		}
+	}
+	if len(reviewers) == 0 {
+		reviewers = []uuid.UUID{adminUserID}
+	}
+
+	selfReview := len(reviewers) == 1
+	if cfg.Admin.SelfReview != nil {
+		selfReview = *cfg.Admin.SelfReview
+	}
+
 	adminCreatedAt := time.Now().UTC()
 	if cfg.Admin.CreatedAtISO != "" {
 		if parsed, err := time.Parse(time.RFC3339, cfg.Admin.CreatedAtISO); err == nil {
@@ -121,28 +157,83 @@ func Run(cfg *config.Config) { //nolint: gocyclo,cyclop,funlen,gocritic,nolintli
 
 	adminUseCase := admin.New(adminProfile)
 
+	mediaSigningKey := cfg.Media.SigningKey
+	if mediaSigningKey == "" {
+		l.Warn("app - Run - MEDIA_SIGNING_KEY is not set, deriving it from JWT_ADMIN_SECRET")
+		sum := sha256.Sum256([]byte("media:" + cfg.JWT.AdminSecret))
+		mediaSigningKey = hex.EncodeToString(sum[:])
+	}
+
+	mediaStore, err := blob.NewLocal(cfg.Media.Dir, cfg.Media.BaseURL, mediaSigningKey)
+	if err != nil {
+		l.Fatal(fmt.Errorf("app - Run - blob.NewLocal: %w", err))
+	}
+
+	mediaUseCase := media.New(repos.Media, repos.Question, mediaStore, media.Options{
+		MaxBytes: int64(cfg.Media.MaxSizeMB) << 20,
+		URLTTL:   cfg.Media.URLTTL,
+	})
+
+	notificationUseCase := notification.New(repos.Notification)
+
+	reportUseCase := report.New(repos.Report, repos.Question, report.Options{
+		HourlyLimit:  cfg.Reports.HourlyLimit,
+		AutoRetireAt: cfg.Reports.AutoRetireAt,
+	})
+
+	translationWebAPI := webapi.New()
+
+	localizationUseCase := localization.New(repos.Localization, translationWebAPI, localization.Options{
+		Languages: cfg.Locale.Languages,
+		BatchSize: cfg.Locale.BatchSize,
+	})
+
+	questionUseCase := question.New(repos.Question, repos.Subject, repos.Topic, repos.Import, repos.Signature, repos.Review,
+		question.Options{
+			Reviewers:   reviewers,
+			SelfReview:  selfReview,
+			ImportLimit: int64(cfg.HTTP.BodyLimitMB) << 20,
+		})
+
+	examUseCase := exam.New(repos.Exam, repos.Attempt, repos.Question, mediaUseCase, localizationUseCase, exam.Options{
+		FullRefundBefore:     cfg.Exams.FullRefundBefore,
+		PartialRefundBefore:  cfg.Exams.PartialRefundBefore,
+		PartialRefundPercent: cfg.Exams.PartialRefundPercent,
+	})
+
+	itemAnalysisUseCase := itemanalysis.New(repos.ItemAnalysis, repos.Question, itemanalysis.Options{
+		MinAttempts: cfg.Items.MinAttempts,
+	})
+
 	// Use-Case
 	useCases := usecase.UseCases{
-		Admin:       adminUseCase,
-		Auth:        auth.New(repos.User, userJWT, adminJWT, adminCreds),
-		User:        user.New(repos.User, repos.Subject, repos.Topic),
-		Practice:    practice.New(repos.Practice),
-		Revision:    revision.New(repos.Revision),
-		Question:    question.New(repos.Question),
-		Exam:        exam.New(repos.Exam),
-		Podcast:     podcast.New(repos.Podcast),
-		Wallet:      wallet.New(repos.Wallet),
-		Coupon:      coupon.New(repos.Coupon),
-		Referral:    referral.New(repos.Referral),
-		AI:          ai.New(repos.AI),
-		Analytics:   analytics.New(repos.Analytics),
-		Leaderboard: leaderboard.New(repos.Leaderboard),
-		Feed:        feed.New(repos.Feed),
+		Admin:        adminUseCase,
+		Auth:         auth.New(repos.User, userJWT, adminJWT, adminCreds),
+		User:         user.New(repos.User, repos.Subject, repos.Topic),
+		Syllabus:     syllabus.New(repos.Subject, repos.Topic),
+		Practice:     practice.New(repos.Practice, repos.Question, repos.Exam, repos.AI, repos.Revision, mediaUseCase, localizationUseCase),
+		Revision:     revision.New(repos.Revision, repos.AI),
+		Question:     questionUseCase,
+		Media:        mediaUseCase,
+		Tag:          tag.New(repos.Tag, repos.Question),
+		Report:       reportUseCase,
+		Notification: notificationUseCase,
+		ItemAnalysis: itemAnalysisUseCase,
+		Localization: localizationUseCase,
+		Exam:         examUseCase,
+		Podcast:      podcast.New(repos.Podcast),
+		Wallet:       wallet.New(repos.Wallet),
+		Coupon:       coupon.New(repos.Coupon),
+		Referral:     referral.New(repos.Referral),
+		AI:           ai.New(repos.AI),
+		Analytics:    analytics.New(repos.Analytics),
+		Leaderboard:  leaderboard.New(repos.Leaderboard),
+		Feed:         feed.New(repos.Feed),
 	}
 
 	translationUseCase := translation.New(
 		repos.Translation,
-		webapi.New(),
+		translationWebAPI,
 	)
 
 	// RabbitMQ RPC Server
@@ -166,9 +257,95 @@ func Run(cfg *config.Config) { //nolint: gocyclo,cyclop,funlen,gocritic,nolintli
 	grpc.NewRouter(grpcServer.App, translationUseCase, l)
 
 	// HTTP Server
-	httpServer := httpserver.New(l, httpserver.Port(cfg.HTTP.Port), httpserver.Prefork(cfg.HTTP.UsePreforkMode))
+	httpServer := httpserver.New(l,
+		httpserver.Port(cfg.HTTP.Port),
+		httpserver.Prefork(cfg.HTTP.UsePreforkMode),
+		httpserver.BodyLimit(cfg.HTTP.BodyLimitMB<<20),
+	)
 	http.NewRouter(httpServer.App, cfg, translationUseCase, useCases, userJWT, adminJWT, l)
 
+	// Background jobs
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+
+	startJobs(jobsCtx, l,
+		job{
+			name:     "practice session expiry",
+			interval: cfg.Jobs.PracticeExpiryInterval,
+			run: func(ctx context.Context) error {
+				expired, err := useCases.Practice.ExpireSessions(ctx)
+				if err != nil {
+					return err
+				}
+				if expired > 0 {
+					l.Info("app - jobs - abandoned %d expired practice sessions", expired)
+				}
+
+				return nil
+			},
+		},
+		job{
+			name:     "exam attempt grading",
+			interval: cfg.Jobs.ExamGradingInterval,
+			run: func(ctx context.Context) error {
+				graded, err := useCases.Exam.GradeExpired(ctx)
+				if graded > 0 {
+					l.Info("app - jobs - graded %d overdue exam attempts", graded)
+				}
+
+				return err
+			},
+		},
+		job{
+			name:     "question import",
+			interval: cfg.Jobs.QuestionImportInterval,
+			run: func(ctx context.Context) error {
+				finished, err := useCases.Question.RunImports(ctx)
+				if finished > 0 {
+					l.Info("app - jobs - finished %d question imports", finished)
+				}
+
+				return err
+			},
+		},
+		job{
+			name:     "question signatures",
+			interval: cfg.Jobs.QuestionSignatureInterval,
+			run: func(ctx context.Context) error {
+				signed, err := useCases.Question.SignQuestions(ctx)
+				if signed > 0 {
+					l.Info("app - jobs - signed %d questions for duplicate detection", signed)
+				}
+
+				return err
+			},
+		},
+		job{
+			name:     "item analysis",
+			interval: cfg.Jobs.ItemAnalysisInterval,
+			run: func(ctx context.Context) error {
+				analyzed, recalibrated, err := useCases.ItemAnalysis.Recalibrate(ctx)
+				if analyzed > 0 {
+					l.Info("app - jobs - analyzed %d questions, recalibrated difficulty of %d", analyzed, recalibrated)
+				}
+
+				return err
+			},
+		},
+		job{
+			name:     "question translation",
+			interval: cfg.Jobs.TranslationInterval,
+			run: func(ctx context.Context) error {
+				translated, err := useCases.Localization.Translate(ctx)
+				if translated > 0 {
+					l.Info("app - jobs - stored %d question translations", translated)
+				}
+
+				return err
+			},
+		},
+	)
+
 	// Start servers
 	rmqServer.Start()
 	natsServer.Start()
@@ -193,6 +370,8 @@ func Run(cfg *config.Config) { //nolint: gocyclo,cyclop,funlen,gocritic,nolintli
 	}
 
 	// Shutdown
+	stopJobs()
+
 	err = httpServer.Shutdown()
 	if err != nil {
 		l.Error(fmt.Errorf("app - Run - httpServer.Shutdown: %w", err))
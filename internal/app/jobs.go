@@ -0,0 +1,42 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/evrone/go-clean-template/pkg/logger"
+)
+
+// job is a unit of background work executed on a fixed interval.
+type job struct {
+	name     string
+	interval time.Duration
+	run      func(ctx context.Context) error
+}
+
+// startJobs runs every job in its own goroutine until ctx is cancelled.
+// Jobs with a non-positive interval are disabled.
+func startJobs(ctx context.Context, l logger.Interface, jobs ...job) {
+	for _, j := range jobs {
+		if j.interval <= 0 {
+			l.Info("app - jobs - %s disabled", j.name)
+			continue
+		}
+
+		go func(j job) {
+			ticker := time.NewTicker(j.interval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					if err := j.run(ctx); err != nil {
+						l.Error(err, "app - jobs - "+j.name)
+					}
+				}
+			}
+		}(j)
+	}
+}
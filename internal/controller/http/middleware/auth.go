@@ -50,6 +50,7 @@ func authMiddleware(jwtService *jwt.Service, requireAdmin bool) fiber.Handler {
 
 		ctx.Locals("userID", claims.UserID)
 		ctx.Locals("role", claims.Role)
+		ctx.Locals("exam", claims.Exam)
 
 		return ctx.Next()
 	}
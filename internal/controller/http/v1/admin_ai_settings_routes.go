@@ -10,6 +10,7 @@ import (
 func registerAdminAISettingsRoutes(api fiber.Router, r *Routes) {
 	api.Get("", r.adminGetAISettings)
 	api.Put("", r.adminUpdateAISettings)
+	api.Post("/revision-replay", r.adminReplayRevisionHistory)
 }
 
 // @Summary Get AI settings
@@ -61,3 +62,35 @@ func (r *Routes) adminUpdateAISettings(ctx *fiber.Ctx) error {
 
 	return ctx.Status(http.StatusOK).JSON(updated)
 }
+
+// @Summary Compare revision schedulers on a user's review history
+// @Tags Admin: AI Settings
+// @Security AdminAuth
+// @Accept json
+// @Produce json
+// @Param request body entity.RevisionReplayRequest true "Candidate scheduler"
+// @Success 200 {object} entity.RevisionReplayComparison
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/ai-settings/revision-replay [post]
+func (r *Routes) adminReplayRevisionHistory(ctx *fiber.Ctx) error {
+	var payload entity.RevisionReplayRequest
+	if err := ctx.BodyParser(&payload); err != nil {
+		r.l.Error(err, "http - v1 - adminReplayRevisionHistory - parse")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	if err := r.v.Struct(payload); err != nil {
+		r.l.Error(err, "http - v1 - adminReplayRevisionHistory - validation")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	comparison, err := r.uc.Revision.CompareSchedulers(ctx.UserContext(), payload)
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminReplayRevisionHistory - usecase")
+		return errorResponse(ctx, http.StatusInternalServerError, "unable to replay revision history")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(comparison)
+}
@@ -1,9 +1,11 @@
 package v1
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase/coupon"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -34,6 +36,7 @@ func (r *Routes) adminListCoupons(ctx *fiber.Ctx) error {
 }
 
 // @Summary Create coupon
+// @Description FIXED and BONUS coupons credit amount to the wallet when redeemed. ENTRY_PASS coupons pay exam entry fees, up to amount or in full when it is 0. Zero use limits mean unlimited.
 // @Tags Admin: Coupons
 // @Security AdminAuth
 // @Accept json
@@ -42,6 +45,7 @@ func (r *Routes) adminListCoupons(ctx *fiber.Ctx) error {
 // @Success 201 {object} entity.Coupon
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /admin/coupons [post]
 func (r *Routes) adminCreateCoupon(ctx *fiber.Ctx) error {
@@ -56,13 +60,12 @@ func (r *Routes) adminCreateCoupon(ctx *fiber.Ctx) error {
 		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
 	}
 
-	coupon, err := r.uc.Coupon.AdminCreate(ctx.UserContext(), payload)
+	created, err := r.uc.Coupon.AdminCreate(ctx.UserContext(), payload)
 	if err != nil {
-		r.l.Error(err, "http - v1 - adminCreateCoupon - usecase")
-		return errorResponse(ctx, http.StatusInternalServerError, "unable to create coupon")
+		return r.couponError(ctx, err, "adminCreateCoupon", "unable to create coupon")
 	}
 
-	return ctx.Status(http.StatusCreated).JSON(coupon)
+	return ctx.Status(http.StatusCreated).JSON(created)
 }
 
 // @Summary Get coupon
@@ -73,6 +76,7 @@ func (r *Routes) adminCreateCoupon(ctx *fiber.Ctx) error {
 // @Success 200 {object} entity.Coupon
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /admin/coupons/{id} [get]
 func (r *Routes) adminGetCoupon(ctx *fiber.Ctx) error {
@@ -82,13 +86,12 @@ func (r *Routes) adminGetCoupon(ctx *fiber.Ctx) error {
 		return errorResponse(ctx, http.StatusBadRequest, "invalid id")
 	}
 
-	coupon, err := r.uc.Coupon.AdminGet(ctx.UserContext(), id)
+	found, err := r.uc.Coupon.AdminGet(ctx.UserContext(), id)
 	if err != nil {
-		r.l.Error(err, "http - v1 - adminGetCoupon - usecase")
-		return errorResponse(ctx, http.StatusInternalServerError, "unable to load coupon")
+		return r.couponError(ctx, err, "adminGetCoupon", "unable to load coupon")
 	}
 
-	return ctx.Status(http.StatusOK).JSON(coupon)
+	return ctx.Status(http.StatusOK).JSON(found)
 }
 
 // @Summary Update coupon
@@ -101,6 +104,8 @@ func (r *Routes) adminGetCoupon(ctx *fiber.Ctx) error {
 // @Success 200 {object} entity.Coupon
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /admin/coupons/{id} [patch]
 func (r *Routes) adminUpdateCoupon(ctx *fiber.Ctx) error {
@@ -123,8 +128,7 @@ func (r *Routes) adminUpdateCoupon(ctx *fiber.Ctx) error {
 
 	updated, err := r.uc.Coupon.AdminUpdate(ctx.UserContext(), id, payload)
 	if err != nil {
-		r.l.Error(err, "http - v1 - adminUpdateCoupon - usecase")
-		return errorResponse(ctx, http.StatusInternalServerError, "unable to update coupon")
+		return r.couponError(ctx, err, "adminUpdateCoupon", "unable to update coupon")
 	}
 
 	return ctx.Status(http.StatusOK).JSON(updated)
@@ -137,6 +141,8 @@ func (r *Routes) adminUpdateCoupon(ctx *fiber.Ctx) error {
 // @Success 204
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
 // @Router /admin/coupons/{id} [delete]
 func (r *Routes) adminDeleteCoupon(ctx *fiber.Ctx) error {
 	id, err := parseUUID(ctx, "id")
@@ -146,9 +152,22 @@ func (r *Routes) adminDeleteCoupon(ctx *fiber.Ctx) error {
 	}
 
 	if err := r.uc.Coupon.AdminDelete(ctx.UserContext(), id); err != nil {
-		r.l.Error(err, "http - v1 - adminDeleteCoupon - usecase")
-		return errorResponse(ctx, http.StatusInternalServerError, "unable to delete coupon")
+		return r.couponError(ctx, err, "adminDeleteCoupon", "unable to delete coupon")
 	}
 
 	return ctx.SendStatus(http.StatusNoContent)
 }
+
+func (r *Routes) couponError(ctx *fiber.Ctx, err error, handler, message string) error {
+	switch {
+	case errors.Is(err, coupon.ErrUnavailable):
+		return errorResponse(ctx, http.StatusBadRequest, err.Error())
+	case errors.Is(err, coupon.ErrNotFound):
+		return errorResponse(ctx, http.StatusNotFound, err.Error())
+	case errors.Is(err, coupon.ErrCodeTaken):
+		return errorResponse(ctx, http.StatusConflict, err.Error())
+	default:
+		r.l.Error(err, "http - v1 - "+handler+" - usecase")
+		return errorResponse(ctx, http.StatusInternalServerError, message)
+	}
+}
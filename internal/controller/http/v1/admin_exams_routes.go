@@ -1,9 +1,11 @@
 package v1
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase/exam"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -15,6 +17,11 @@ func registerAdminExamsRoutes(api fiber.Router, r *Routes) {
 	api.Delete("/:id", r.adminDeleteExam)
 }
 
+func registerAdminExamCategoriesRoutes(api fiber.Router, r *Routes) {
+	api.Get("", r.adminListExamCategories)
+	api.Patch("/:exam", r.adminUpdateExamCategory)
+}
+
 // @Summary List exam configs
 // @Tags Admin: Exams
 // @Security AdminAuth
@@ -41,6 +48,7 @@ func (r *Routes) adminListExams(ctx *fiber.Ctx) error {
 }
 
 // @Summary Create exam config
+// @Description Creates a DRAFT exam. The schedule window, when given, must end after it starts.
 // @Tags Admin: Exams
 // @Security AdminAuth
 // @Accept json
@@ -65,8 +73,7 @@ func (r *Routes) adminCreateExam(ctx *fiber.Ctx) error {
 
 	config, err := r.uc.Exam.AdminCreate(ctx.UserContext(), payload)
 	if err != nil {
-		r.l.Error(err, "http - v1 - adminCreateExam - usecase")
-		return errorResponse(ctx, http.StatusInternalServerError, "unable to create exam")
+		return r.examError(ctx, err, "adminCreateExam")
 	}
 
 	return ctx.Status(http.StatusCreated).JSON(config)
@@ -80,6 +87,7 @@ func (r *Routes) adminCreateExam(ctx *fiber.Ctx) error {
 // @Success 200 {object} entity.ExamConfig
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /admin/exams/{id} [get]
 func (r *Routes) adminGetExam(ctx *fiber.Ctx) error {
@@ -91,14 +99,14 @@ func (r *Routes) adminGetExam(ctx *fiber.Ctx) error {
 
 	config, err := r.uc.Exam.AdminGet(ctx.UserContext(), id)
 	if err != nil {
-		r.l.Error(err, "http - v1 - adminGetExam - usecase")
-		return errorResponse(ctx, http.StatusInternalServerError, "unable to load exam")
+		return r.examError(ctx, err, "adminGetExam")
 	}
 
 	return ctx.Status(http.StatusOK).JSON(config)
 }
 
 // @Summary Update exam config
+// @Description Status moves DRAFT -> SCHEDULED -> ONGOING -> COMPLETED, or SCHEDULED back to DRAFT; leaving DRAFT needs a schedule window. Marking rules are fixed once the exam is ONGOING.
 // @Tags Admin: Exams
 // @Security AdminAuth
 // @Accept json
@@ -108,6 +116,8 @@ func (r *Routes) adminGetExam(ctx *fiber.Ctx) error {
 // @Success 200 {object} entity.ExamConfig
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /admin/exams/{id} [patch]
 func (r *Routes) adminUpdateExam(ctx *fiber.Ctx) error {
@@ -123,10 +133,14 @@ func (r *Routes) adminUpdateExam(ctx *fiber.Ctx) error {
 		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
 	}
 
+	if err := r.v.Struct(payload); err != nil {
+		r.l.Error(err, "http - v1 - adminUpdateExam - validation")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
 	updated, err := r.uc.Exam.AdminUpdate(ctx.UserContext(), id, payload)
 	if err != nil {
-		r.l.Error(err, "http - v1 - adminUpdateExam - usecase")
-		return errorResponse(ctx, http.StatusInternalServerError, "unable to update exam")
+		return r.examError(ctx, err, "adminUpdateExam")
 	}
 
 	return ctx.Status(http.StatusOK).JSON(updated)
@@ -139,6 +153,9 @@ func (r *Routes) adminUpdateExam(ctx *fiber.Ctx) error {
 // @Success 204
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
 // @Router /admin/exams/{id} [delete]
 func (r *Routes) adminDeleteExam(ctx *fiber.Ctx) error {
 	id, err := parseUUID(ctx, "id")
@@ -148,9 +165,82 @@ func (r *Routes) adminDeleteExam(ctx *fiber.Ctx) error {
 	}
 
 	if err := r.uc.Exam.AdminDelete(ctx.UserContext(), id); err != nil {
-		r.l.Error(err, "http - v1 - adminDeleteExam - usecase")
-		return errorResponse(ctx, http.StatusInternalServerError, "unable to delete exam")
+		return r.examError(ctx, err, "adminDeleteExam")
 	}
 
 	return ctx.SendStatus(http.StatusNoContent)
 }
+
+// @Summary List exam category settings
+// @Tags Admin: Exams
+// @Security AdminAuth
+// @Produce json
+// @Success 200 {array} entity.ExamCategorySettings
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/exam-categories [get]
+func (r *Routes) adminListExamCategories(ctx *fiber.Ctx) error {
+	categories, err := r.uc.Exam.AdminListCategories(ctx.UserContext())
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminListExamCategories")
+		return errorResponse(ctx, http.StatusInternalServerError, "unable to list exam categories")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(categories)
+}
+
+// @Summary Update exam category settings
+// @Tags Admin: Exams
+// @Security AdminAuth
+// @Accept json
+// @Produce json
+// @Param exam path string true "Exam code"
+// @Param request body entity.ExamCategorySettingsUpdateRequest true "Settings"
+// @Success 200 {object} entity.ExamCategorySettings
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/exam-categories/{exam} [patch]
+func (r *Routes) adminUpdateExamCategory(ctx *fiber.Ctx) error {
+	var payload entity.ExamCategorySettingsUpdateRequest
+	if err := ctx.BodyParser(&payload); err != nil {
+		r.l.Error(err, "http - v1 - adminUpdateExamCategory - parse")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	if err := r.v.Struct(payload); err != nil {
+		r.l.Error(err, "http - v1 - adminUpdateExamCategory - validation")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	updated, err := r.uc.Exam.AdminUpdateCategory(ctx.UserContext(), entity.ExamCategory(ctx.Params("exam")), payload)
+	if err != nil {
+		if errors.Is(err, exam.ErrCategoryNotFound) {
+			return errorResponse(ctx, http.StatusNotFound, err.Error())
+		}
+		r.l.Error(err, "http - v1 - adminUpdateExamCategory - usecase")
+		return errorResponse(ctx, http.StatusInternalServerError, "unable to update exam category")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(updated)
+}
+
+func (r *Routes) examError(ctx *fiber.Ctx, err error, handler string) error {
+	switch {
+	case errors.Is(err, exam.ErrCategoryNotFound),
+		errors.Is(err, exam.ErrInvalidSchedule),
+		errors.Is(err, exam.ErrScheduleRequired):
+		return errorResponse(ctx, http.StatusBadRequest, err.Error())
+	case errors.Is(err, exam.ErrNotFound):
+		return errorResponse(ctx, http.StatusNotFound, err.Error())
+	case errors.Is(err, exam.ErrInvalidTransition),
+		errors.Is(err, exam.ErrMarkingLocked),
+		errors.Is(err, exam.ErrStatusChanged),
+		errors.Is(err, exam.ErrHasRegistrations):
+		return errorResponse(ctx, http.StatusConflict, err.Error())
+	default:
+		r.l.Error(err, "http - v1 - "+handler+" - usecase")
+		return errorResponse(ctx, http.StatusInternalServerError, "unable to update exam")
+	}
+}
@@ -0,0 +1,162 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/repo"
+	itemanalysisusecase "github.com/evrone/go-clean-template/internal/usecase/itemanalysis"
+)
+
+// @Summary Item analysis
+// @Description Per-question statistics from each learner's first attempt: p-value, point-biserial discrimination, option selection rates and median time, with flags for poor items. Most flagged questions first. Recomputed by a scheduled job.
+// @Tags Admin: Questions
+// @Security AdminAuth
+// @Produce json
+// @Param exam query string false "Exam"
+// @Param subjectId query string false "Subject ID"
+// @Param topicId query string false "Topic ID"
+// @Param flag query string false "too_easy, too_hard, low_discrimination, negative_discrimination, possible_wrong_key or nonfunctional_distractor"
+// @Param flagged query bool false "Only questions with at least one flag"
+// @Param minAttempts query int false "Only questions with at least this many attempts"
+// @Param page query int false "Page"
+// @Param pageSize query int false "Page size, at most 100"
+// @Success 200 {object} entity.ItemAnalysisList
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/questions/analysis [get]
+func (r *Routes) adminListItemAnalyses(ctx *fiber.Ctx) error {
+	filter, err := itemAnalysisFilter(ctx)
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminListItemAnalyses - filter")
+		return errorResponse(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	list, err := r.uc.ItemAnalysis.List(ctx.UserContext(), filter)
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminListItemAnalyses - usecase")
+		return errorResponse(ctx, http.StatusInternalServerError, "unable to load item analysis")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(list)
+}
+
+// @Summary Item analysis of a question
+// @Tags Admin: Questions
+// @Security AdminAuth
+// @Produce json
+// @Param id path string true "Question ID"
+// @Success 200 {object} entity.ItemAnalysis
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/questions/{id}/analysis [get]
+func (r *Routes) adminGetItemAnalysis(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx, "id")
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminGetItemAnalysis")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id")
+	}
+
+	analysis, err := r.uc.ItemAnalysis.Get(ctx.UserContext(), id)
+	if err != nil {
+		if errors.Is(err, itemanalysisusecase.ErrNotAnalyzed) {
+			return errorResponse(ctx, http.StatusNotFound, err.Error())
+		}
+		r.l.Error(err, "http - v1 - adminGetItemAnalysis - usecase")
+		return errorResponse(ctx, http.StatusInternalServerError, "unable to load item analysis")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(analysis)
+}
+
+// @Summary Override question difficulty
+// @Description Sets the difficulty level by hand. With locked, recalibration leaves the level alone until the question is unlocked; omit difficultyLevel to only change the lock.
+// @Tags Admin: Questions
+// @Security AdminAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Question ID"
+// @Param request body entity.QuestionDifficultyRequest true "Difficulty"
+// @Success 200 {object} entity.Question
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/questions/{id}/difficulty [put]
+func (r *Routes) adminOverrideQuestionDifficulty(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx, "id")
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminOverrideQuestionDifficulty")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id")
+	}
+
+	var payload entity.QuestionDifficultyRequest
+	if err := ctx.BodyParser(&payload); err != nil {
+		r.l.Error(err, "http - v1 - adminOverrideQuestionDifficulty - parse")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	if err := r.v.Struct(payload); err != nil {
+		r.l.Error(err, "http - v1 - adminOverrideQuestionDifficulty - validation")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	question, err := r.uc.ItemAnalysis.Override(ctx.UserContext(), id, payload)
+	if err != nil {
+		if errors.Is(err, itemanalysisusecase.ErrQuestionNotFound) {
+			return errorResponse(ctx, http.StatusNotFound, err.Error())
+		}
+		r.l.Error(err, "http - v1 - adminOverrideQuestionDifficulty - usecase")
+		return errorResponse(ctx, http.StatusInternalServerError, "unable to update question difficulty")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(question)
+}
+
+func itemAnalysisFilter(ctx *fiber.Ctx) (repo.ItemAnalysisFilter, error) {
+	filter := repo.ItemAnalysisFilter{
+		MinAttempts: parseQueryInt(ctx, "minAttempts", 0),
+		Page:        parseQueryInt(ctx, "page", 1),
+		PageSize:    parseQueryInt(ctx, "pageSize", 20),
+	}
+
+	if query := ctx.Query("exam"); query != "" {
+		exam := entity.ExamCategory(query)
+		filter.Exam = &exam
+	}
+
+	var err error
+	if filter.SubjectID, err = parseQueryUUID(ctx, "subjectId"); err != nil {
+		return filter, fmt.Errorf("invalid subjectId: %w", err)
+	}
+
+	if filter.TopicID, err = parseQueryUUID(ctx, "topicId"); err != nil {
+		return filter, fmt.Errorf("invalid topicId: %w", err)
+	}
+
+	if query := ctx.Query("flag"); query != "" {
+		flag := entity.ItemFlag(query)
+		switch flag {
+		case entity.ItemFlagTooEasy, entity.ItemFlagTooHard, entity.ItemFlagLowDiscrimination,
+			entity.ItemFlagNegativeDiscrimination, entity.ItemFlagPossibleWrongKey, entity.ItemFlagNonFunctionalDistractor:
+			filter.Flag = &flag
+		default:
+			return filter, fmt.Errorf("invalid flag %q", query)
+		}
+	}
+
+	flagged, err := parseQueryBool(ctx, "flagged")
+	if err != nil {
+		return filter, fmt.Errorf("invalid flagged: %w", err)
+	}
+	filter.FlaggedOnly = flagged != nil && *flagged
+
+	return filter, nil
+}
@@ -0,0 +1,156 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/repo"
+	localizationusecase "github.com/evrone/go-clean-template/internal/usecase/localization"
+)
+
+// @Summary List question translations
+// @Description The translation review queue, most recently changed first. A scheduled job machine-translates new and edited published questions; stale translations were made from an older version and are not served.
+// @Tags Admin: Questions
+// @Security AdminAuth
+// @Produce json
+// @Param language query string false "Language code, e.g. hi"
+// @Param status query string false "machine or reviewed"
+// @Param stale query bool false "Only stale or only current translations"
+// @Param page query int false "Page"
+// @Param pageSize query int false "Page size, at most 200"
+// @Success 200 {object} entity.QuestionLocalizationList
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/questions/localizations [get]
+func (r *Routes) adminListQuestionLocalizations(ctx *fiber.Ctx) error {
+	filter, err := localizationFilter(ctx)
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminListQuestionLocalizations - filter")
+		return errorResponse(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	list, err := r.uc.Localization.List(ctx.UserContext(), filter)
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminListQuestionLocalizations - usecase")
+		return errorResponse(ctx, http.StatusInternalServerError, "unable to list translations")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(list)
+}
+
+// @Summary Get question translation
+// @Tags Admin: Questions
+// @Security AdminAuth
+// @Produce json
+// @Param id path string true "Question ID"
+// @Param language path string true "Language code, e.g. hi"
+// @Success 200 {object} entity.QuestionLocalization
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/questions/{id}/localizations/{language} [get]
+func (r *Routes) adminGetQuestionLocalization(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx, "id")
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminGetQuestionLocalization")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id")
+	}
+
+	localization, err := r.uc.Localization.Get(ctx.UserContext(), id, ctx.Params("language"))
+	if err != nil {
+		return r.localizationError(ctx, err, "adminGetQuestionLocalization")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(localization)
+}
+
+// @Summary Correct question translation
+// @Description Applies corrections and marks the translation reviewed. Omitted fields are left unchanged.
+// @Tags Admin: Questions
+// @Security AdminAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Question ID"
+// @Param language path string true "Language code, e.g. hi"
+// @Param request body entity.QuestionLocalizationUpdateRequest true "Corrected text"
+// @Success 200 {object} entity.QuestionLocalization
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/questions/{id}/localizations/{language} [put]
+func (r *Routes) adminCorrectQuestionLocalization(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx, "id")
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminCorrectQuestionLocalization")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id")
+	}
+
+	var payload entity.QuestionLocalizationUpdateRequest
+	if err := ctx.BodyParser(&payload); err != nil {
+		r.l.Error(err, "http - v1 - adminCorrectQuestionLocalization - parse")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	if err := r.v.Struct(payload); err != nil {
+		r.l.Error(err, "http - v1 - adminCorrectQuestionLocalization - validation")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	adminID, err := r.getUserID(ctx)
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminCorrectQuestionLocalization - user")
+		return errorResponse(ctx, http.StatusUnauthorized, "invalid token")
+	}
+
+	localization, err := r.uc.Localization.Correct(ctx.UserContext(), id, ctx.Params("language"), adminID, payload)
+	if err != nil {
+		return r.localizationError(ctx, err, "adminCorrectQuestionLocalization")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(localization)
+}
+
+func (r *Routes) localizationError(ctx *fiber.Ctx, err error, handler string) error {
+	switch {
+	case errors.Is(err, localizationusecase.ErrUnsupportedLanguage):
+		return errorResponse(ctx, http.StatusBadRequest, err.Error())
+	case errors.Is(err, localizationusecase.ErrNotFound), errors.Is(err, localizationusecase.ErrUserNotFound):
+		return errorResponse(ctx, http.StatusNotFound, err.Error())
+	default:
+		r.l.Error(err, "http - v1 - "+handler+" - usecase")
+		return errorResponse(ctx, http.StatusInternalServerError, "unable to load translations")
+	}
+}
+
+func localizationFilter(ctx *fiber.Ctx) (repo.LocalizationFilter, error) {
+	filter := repo.LocalizationFilter{
+		Language: ctx.Query("language"),
+		Page:     parseQueryInt(ctx, "page", 1),
+		PageSize: parseQueryInt(ctx, "pageSize", 50),
+	}
+
+	if query := ctx.Query("status"); query != "" {
+		status := entity.LocalizationStatus(query)
+		switch status {
+		case entity.LocalizationStatusMachine, entity.LocalizationStatusReviewed:
+			filter.Status = &status
+		default:
+			return filter, fmt.Errorf("invalid status %q", query)
+		}
+	}
+
+	stale, err := parseQueryBool(ctx, "stale")
+	if err != nil {
+		return filter, fmt.Errorf("invalid stale: %w", err)
+	}
+	filter.Stale = stale
+
+	return filter, nil
+}
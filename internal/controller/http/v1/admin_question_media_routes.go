@@ -0,0 +1,151 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/evrone/go-clean-template/internal/controller/http/v1/request"
+	mediausecase "github.com/evrone/go-clean-template/internal/usecase/media"
+)
+
+// @Summary List question media
+// @Description Download URLs are signed and expire; fetch the list again for fresh ones.
+// @Tags Admin: Questions
+// @Security AdminAuth
+// @Produce json
+// @Param id path string true "Question ID"
+// @Success 200 {array} entity.QuestionMedia
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/questions/{id}/media [get]
+func (r *Routes) adminListQuestionMedia(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx, "id")
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminListQuestionMedia")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id")
+	}
+
+	media, err := r.uc.Media.List(ctx.UserContext(), id)
+	if err != nil {
+		return r.questionMediaError(ctx, err, "adminListQuestionMedia")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(media)
+}
+
+// @Summary Upload question media
+// @Description Attaches a PNG, JPEG or GIF image to the stem, an option or the explanation. The type is detected from the file contents; a JPEG thumbnail is generated.
+// @Tags Admin: Questions
+// @Security AdminAuth
+// @Accept mpfd
+// @Produce json
+// @Param id path string true "Question ID"
+// @Param file formData file true "Image"
+// @Param slot formData string true "stem, option_a, option_b, option_c, option_d or explanation"
+// @Param altText formData string false "Text alternative for screen readers"
+// @Success 201 {object} entity.QuestionMedia
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 415 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/questions/{id}/media [post]
+func (r *Routes) adminUploadQuestionMedia(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx, "id")
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminUploadQuestionMedia")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id")
+	}
+
+	var payload request.AdminQuestionMediaRequest
+	if err := ctx.BodyParser(&payload); err != nil {
+		r.l.Error(err, "http - v1 - adminUploadQuestionMedia - parse")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	if err := r.v.Struct(payload); err != nil {
+		r.l.Error(err, "http - v1 - adminUploadQuestionMedia - validation")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		return errorResponse(ctx, http.StatusBadRequest, "file is required")
+	}
+
+	data, err := readFormFile(file)
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminUploadQuestionMedia - file")
+		return errorResponse(ctx, http.StatusBadRequest, "unable to read file")
+	}
+
+	adminID, err := r.getUserID(ctx)
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminUploadQuestionMedia - user")
+		return errorResponse(ctx, http.StatusUnauthorized, "invalid token")
+	}
+
+	media, err := r.uc.Media.Upload(ctx.UserContext(), adminID, mediausecase.Upload{
+		QuestionID: id,
+		Slot:       payload.Slot,
+		AltText:    payload.AltText,
+		Data:       data,
+	})
+	if err != nil {
+		return r.questionMediaError(ctx, err, "adminUploadQuestionMedia")
+	}
+
+	return ctx.Status(http.StatusCreated).JSON(media)
+}
+
+// @Summary Delete question media
+// @Tags Admin: Questions
+// @Security AdminAuth
+// @Param id path string true "Question ID"
+// @Param mediaId path string true "Media ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/questions/{id}/media/{mediaId} [delete]
+func (r *Routes) adminDeleteQuestionMedia(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx, "id")
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminDeleteQuestionMedia")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id")
+	}
+
+	mediaID, err := parseUUID(ctx, "mediaId")
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminDeleteQuestionMedia")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid media id")
+	}
+
+	if err := r.uc.Media.Delete(ctx.UserContext(), id, mediaID); err != nil {
+		return r.questionMediaError(ctx, err, "adminDeleteQuestionMedia")
+	}
+
+	return ctx.SendStatus(http.StatusNoContent)
+}
+
+func (r *Routes) questionMediaError(ctx *fiber.Ctx, err error, handler string) error {
+	switch {
+	case errors.Is(err, mediausecase.ErrQuestionNotFound), errors.Is(err, mediausecase.ErrNotFound):
+		return errorResponse(ctx, http.StatusNotFound, err.Error())
+	case errors.Is(err, mediausecase.ErrInvalidSlot):
+		return errorResponse(ctx, http.StatusBadRequest, err.Error())
+	case errors.Is(err, mediausecase.ErrTooLarge):
+		return errorResponse(ctx, http.StatusRequestEntityTooLarge, err.Error())
+	case errors.Is(err, mediausecase.ErrUnsupportedType):
+		return errorResponse(ctx, http.StatusUnsupportedMediaType, err.Error())
+	default:
+		r.l.Error(err, "http - v1 - "+handler+" - usecase")
+		return errorResponse(ctx, http.StatusInternalServerError, "unable to process media")
+	}
+}
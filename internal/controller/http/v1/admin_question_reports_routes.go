@@ -0,0 +1,143 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	reportusecase "github.com/evrone/go-clean-template/internal/usecase/report"
+)
+
+func registerAdminQuestionReportRoutes(api fiber.Router, r *Routes) {
+	api.Get("", r.adminListQuestionReports)
+	api.Get("/:questionId", r.adminGetQuestionReports)
+	api.Post("/:questionId/resolve", r.adminResolveQuestionReports)
+}
+
+// @Summary Question report queue
+// @Description Questions with reports in the given status, most reported first.
+// @Tags Admin: Question Reports
+// @Security AdminAuth
+// @Produce json
+// @Param status query string false "open, resolved or dismissed" default(open)
+// @Param page query int false "Page"
+// @Param pageSize query int false "Page size, at most 100"
+// @Success 200 {object} entity.QuestionReportGroupList
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/question-reports [get]
+func (r *Routes) adminListQuestionReports(ctx *fiber.Ctx) error {
+	status := entity.QuestionReportOpen
+	if query := ctx.Query("status"); query != "" {
+		status = entity.QuestionReportStatus(query)
+		if !validReportStatus(status) {
+			return errorResponse(ctx, http.StatusBadRequest, "invalid status")
+		}
+	}
+
+	list, err := r.uc.Report.ListGroups(ctx.UserContext(), status, parseQueryInt(ctx, "page", 1), parseQueryInt(ctx, "pageSize", 20))
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminListQuestionReports - usecase")
+		return errorResponse(ctx, http.StatusInternalServerError, "unable to load question reports")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(list)
+}
+
+// @Summary Reports of a question
+// @Tags Admin: Question Reports
+// @Security AdminAuth
+// @Produce json
+// @Param questionId path string true "Question ID"
+// @Param status query string false "open, resolved or dismissed; all when omitted"
+// @Success 200 {array} entity.QuestionReport
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/question-reports/{questionId} [get]
+func (r *Routes) adminGetQuestionReports(ctx *fiber.Ctx) error {
+	questionID, err := parseUUID(ctx, "questionId")
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminGetQuestionReports")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid question id")
+	}
+
+	var status *entity.QuestionReportStatus
+	if query := ctx.Query("status"); query != "" {
+		s := entity.QuestionReportStatus(query)
+		if !validReportStatus(s) {
+			return errorResponse(ctx, http.StatusBadRequest, "invalid status")
+		}
+		status = &s
+	}
+
+	reports, err := r.uc.Report.ListByQuestion(ctx.UserContext(), questionID, status)
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminGetQuestionReports - usecase")
+		return errorResponse(ctx, http.StatusInternalServerError, "unable to load question reports")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(reports)
+}
+
+// @Summary Resolve question reports
+// @Description Closes every open report of the question as resolved or dismissed and notifies the reporters, with the note if given. Edit or retire the question itself through the review workflow.
+// @Tags Admin: Question Reports
+// @Security AdminAuth
+// @Accept json
+// @Produce json
+// @Param questionId path string true "Question ID"
+// @Param request body entity.QuestionReportResolveRequest true "Outcome"
+// @Success 200 {object} entity.QuestionReportResolution
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/question-reports/{questionId}/resolve [post]
+func (r *Routes) adminResolveQuestionReports(ctx *fiber.Ctx) error {
+	questionID, err := parseUUID(ctx, "questionId")
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminResolveQuestionReports")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid question id")
+	}
+
+	var payload entity.QuestionReportResolveRequest
+	if err := ctx.BodyParser(&payload); err != nil {
+		r.l.Error(err, "http - v1 - adminResolveQuestionReports - parse")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	if err := r.v.Struct(payload); err != nil {
+		r.l.Error(err, "http - v1 - adminResolveQuestionReports - validation")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	adminID, err := r.getUserID(ctx)
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminResolveQuestionReports - user")
+		return errorResponse(ctx, http.StatusUnauthorized, "invalid token")
+	}
+
+	resolution, err := r.uc.Report.Resolve(ctx.UserContext(), adminID, questionID, payload)
+	if err != nil {
+		if errors.Is(err, reportusecase.ErrNoOpenReports) {
+			return errorResponse(ctx, http.StatusNotFound, err.Error())
+		}
+		r.l.Error(err, "http - v1 - adminResolveQuestionReports - usecase")
+		return errorResponse(ctx, http.StatusInternalServerError, "unable to resolve question reports")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(resolution)
+}
+
+func validReportStatus(status entity.QuestionReportStatus) bool {
+	switch status {
+	case entity.QuestionReportOpen, entity.QuestionReportResolved, entity.QuestionReportDismissed:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,235 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	questionusecase "github.com/evrone/go-clean-template/internal/usecase/question"
+)
+
+// @Summary Change question status
+// @Description Moves a question through draft → in_review → approved → published → retired. Sending it for review needs a reviewer other than the author of the current version; only that reviewer can approve and publish. Any admin can send it back to draft or retire it.
+// @Tags Admin: Questions
+// @Security AdminAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Question ID"
+// @Param request body entity.QuestionTransitionRequest true "Target status"
+// @Success 200 {object} entity.Question
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/questions/{id}/status [post]
+func (r *Routes) adminTransitionQuestion(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx, "id")
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminTransitionQuestion")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id")
+	}
+
+	var payload entity.QuestionTransitionRequest
+	if err := ctx.BodyParser(&payload); err != nil {
+		r.l.Error(err, "http - v1 - adminTransitionQuestion - parse")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	if err := r.v.Struct(payload); err != nil {
+		r.l.Error(err, "http - v1 - adminTransitionQuestion - validation")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	adminID, err := r.getUserID(ctx)
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminTransitionQuestion - user")
+		return errorResponse(ctx, http.StatusUnauthorized, "invalid token")
+	}
+
+	question, err := r.uc.Question.Transition(ctx.UserContext(), adminID, id, payload)
+	if err != nil {
+		return r.questionReviewError(ctx, err, "adminTransitionQuestion")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(question)
+}
+
+// @Summary Assign question reviewer
+// @Description Only questions in draft or in review can change reviewer.
+// @Tags Admin: Questions
+// @Security AdminAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Question ID"
+// @Param request body entity.QuestionReviewerRequest true "Reviewer"
+// @Success 200 {object} entity.Question
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/questions/{id}/reviewer [put]
+func (r *Routes) adminAssignQuestionReviewer(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx, "id")
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminAssignQuestionReviewer")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id")
+	}
+
+	var payload entity.QuestionReviewerRequest
+	if err := ctx.BodyParser(&payload); err != nil {
+		r.l.Error(err, "http - v1 - adminAssignQuestionReviewer - parse")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	if err := r.v.Struct(payload); err != nil {
+		r.l.Error(err, "http - v1 - adminAssignQuestionReviewer - validation")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	question, err := r.uc.Question.AssignReviewer(ctx.UserContext(), id, payload.ReviewerID)
+	if err != nil {
+		return r.questionReviewError(ctx, err, "adminAssignQuestionReviewer")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(question)
+}
+
+// @Summary List review comments
+// @Tags Admin: Questions
+// @Security AdminAuth
+// @Produce json
+// @Param id path string true "Question ID"
+// @Param unresolved query bool false "Only unresolved comments"
+// @Success 200 {array} entity.QuestionReviewComment
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/questions/{id}/comments [get]
+func (r *Routes) adminListQuestionComments(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx, "id")
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminListQuestionComments")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id")
+	}
+
+	unresolved, err := parseQueryBool(ctx, "unresolved")
+	if err != nil {
+		return errorResponse(ctx, http.StatusBadRequest, "invalid unresolved")
+	}
+
+	comments, err := r.uc.Question.ListComments(ctx.UserContext(), id, unresolved != nil && *unresolved)
+	if err != nil {
+		return r.questionReviewError(ctx, err, "adminListQuestionComments")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(comments)
+}
+
+// @Summary Add review comment
+// @Description Comments apply to the current version. Field, when set, anchors the comment to a field of the question, named as in version diffs.
+// @Tags Admin: Questions
+// @Security AdminAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Question ID"
+// @Param request body entity.QuestionReviewCommentRequest true "Comment"
+// @Success 201 {object} entity.QuestionReviewComment
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/questions/{id}/comments [post]
+func (r *Routes) adminAddQuestionComment(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx, "id")
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminAddQuestionComment")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id")
+	}
+
+	var payload entity.QuestionReviewCommentRequest
+	if err := ctx.BodyParser(&payload); err != nil {
+		r.l.Error(err, "http - v1 - adminAddQuestionComment - parse")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	if err := r.v.Struct(payload); err != nil {
+		r.l.Error(err, "http - v1 - adminAddQuestionComment - validation")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	adminID, err := r.getUserID(ctx)
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminAddQuestionComment - user")
+		return errorResponse(ctx, http.StatusUnauthorized, "invalid token")
+	}
+
+	comment, err := r.uc.Question.AddComment(ctx.UserContext(), adminID, id, payload)
+	if err != nil {
+		return r.questionReviewError(ctx, err, "adminAddQuestionComment")
+	}
+
+	return ctx.Status(http.StatusCreated).JSON(comment)
+}
+
+// @Summary Resolve review comment
+// @Tags Admin: Questions
+// @Security AdminAuth
+// @Produce json
+// @Param id path string true "Question ID"
+// @Param commentId path string true "Comment ID"
+// @Success 200 {object} entity.QuestionReviewComment
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/questions/{id}/comments/{commentId}/resolve [post]
+func (r *Routes) adminResolveQuestionComment(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx, "id")
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminResolveQuestionComment")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id")
+	}
+
+	commentID, err := parseUUID(ctx, "commentId")
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminResolveQuestionComment")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid comment id")
+	}
+
+	adminID, err := r.getUserID(ctx)
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminResolveQuestionComment - user")
+		return errorResponse(ctx, http.StatusUnauthorized, "invalid token")
+	}
+
+	comment, err := r.uc.Question.ResolveComment(ctx.UserContext(), adminID, id, commentID)
+	if err != nil {
+		return r.questionReviewError(ctx, err, "adminResolveQuestionComment")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(comment)
+}
+
+func (r *Routes) questionReviewError(ctx *fiber.Ctx, err error, handler string) error {
+	switch {
+	case errors.Is(err, questionusecase.ErrNotFound), errors.Is(err, questionusecase.ErrVersionNotFound),
+		errors.Is(err, questionusecase.ErrCommentNotFound):
+		return errorResponse(ctx, http.StatusNotFound, err.Error())
+	case errors.Is(err, questionusecase.ErrReviewerRequired), errors.Is(err, questionusecase.ErrSelfReview),
+		errors.Is(err, questionusecase.ErrUnknownReviewer), errors.Is(err, questionusecase.ErrInvalidField):
+		return errorResponse(ctx, http.StatusBadRequest, err.Error())
+	case errors.Is(err, questionusecase.ErrNotReviewer):
+		return errorResponse(ctx, http.StatusForbidden, err.Error())
+	case errors.Is(err, questionusecase.ErrInvalidTransition), errors.Is(err, questionusecase.ErrVersionConflict):
+		return errorResponse(ctx, http.StatusConflict, err.Error())
+	default:
+		r.l.Error(err, "http - v1 - "+handler+" - usecase")
+		return errorResponse(ctx, http.StatusInternalServerError, "unable to update question review")
+	}
+}
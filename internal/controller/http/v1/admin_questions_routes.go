@@ -1,61 +1,300 @@
 package v1
 
 import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
 
+	"github.com/evrone/go-clean-template/internal/controller/http/v1/request"
 	"github.com/evrone/go-clean-template/internal/entity"
 	"github.com/evrone/go-clean-template/internal/repo"
+	questionusecase "github.com/evrone/go-clean-template/internal/usecase/question"
+	tagusecase "github.com/evrone/go-clean-template/internal/usecase/tag"
 	"github.com/gofiber/fiber/v2"
 )
 
 func registerAdminQuestionRoutes(api fiber.Router, r *Routes) {
 	api.Get("", r.adminListQuestions)
 	api.Post("", r.adminCreateQuestion)
+	api.Get("/export", r.adminExportQuestions)
+	api.Get("/duplicates", r.adminQuestionDuplicates)
+	api.Get("/analysis", r.adminListItemAnalyses)
+	api.Get("/localizations", r.adminListQuestionLocalizations)
+	api.Post("/import", r.adminImportQuestions)
+	api.Get("/import", r.adminListQuestionImports)
+	api.Get("/import/:id", r.adminGetQuestionImport)
 	api.Get("/:id", r.adminGetQuestion)
+	api.Get("/:id/versions", r.adminListQuestionVersions)
+	api.Get("/:id/versions/diff", r.adminDiffQuestionVersions)
+	api.Get("/:id/versions/:version", r.adminGetQuestionVersion)
+	api.Post("/:id/versions/:version/rollback", r.adminRollbackQuestion)
+	api.Post("/:id/status", r.adminTransitionQuestion)
+	api.Put("/:id/reviewer", r.adminAssignQuestionReviewer)
+	api.Get("/:id/analysis", r.adminGetItemAnalysis)
+	api.Put("/:id/difficulty", r.adminOverrideQuestionDifficulty)
+	api.Put("/:id/tags", r.adminSetQuestionTags)
+	api.Get("/:id/localizations/:language", r.adminGetQuestionLocalization)
+	api.Put("/:id/localizations/:language", r.adminCorrectQuestionLocalization)
+	api.Get("/:id/comments", r.adminListQuestionComments)
+	api.Post("/:id/comments", r.adminAddQuestionComment)
+	api.Post("/:id/comments/:commentId/resolve", r.adminResolveQuestionComment)
+	api.Get("/:id/media", r.adminListQuestionMedia)
+	api.Post("/:id/media", r.adminUploadQuestionMedia)
+	api.Delete("/:id/media/:mediaId", r.adminDeleteQuestionMedia)
 	api.Patch("/:id", r.adminUpdateQuestion)
 	api.Delete("/:id", r.adminDeleteQuestion)
 }
 
 // @Summary List questions
+// @Description Pages through the bank with keyset pagination: pass meta.nextCursor back as cursor, with the same filters and sort.
 // @Tags Admin: Questions
 // @Security AdminAuth
 // @Produce json
+// @Param q query string false "Full-text search over stem, options, reason and explanation"
 // @Param exam query string false "Exam"
 // @Param subjectId query string false "Subject ID"
 // @Param topicId query string false "Topic ID"
-// @Success 200 {array} entity.Question
+// @Param isActive query bool false "Only published or other questions"
+// @Param status query string false "Comma-separated workflow states, e.g. draft,in_review"
+// @Param reviewerId query string false "Assigned reviewer ID"
+// @Param isHighYield query bool false "Only high-yield or other questions"
+// @Param isClinical query bool false "Only clinical or other questions"
+// @Param isImageBased query bool false "Only image-based or other questions"
+// @Param difficulty query string false "Comma-separated difficulty levels, e.g. 1,2"
+// @Param tags query string false "Comma-separated tag names; questions must carry all, e.g. PYQ-2019,AIIMS"
+// @Param choiceType query string false "Comma-separated choice types, e.g. single,multi"
+// @Param createdFrom query string false "Created at or after (RFC 3339 or YYYY-MM-DD)"
+// @Param createdTo query string false "Created before (RFC 3339 or YYYY-MM-DD)"
+// @Param sort query string false "createdAt, difficulty, questionText or relevance"
+// @Param order query string false "asc or desc"
+// @Param limit query int false "Page size, at most 200 (default 50)"
+// @Param cursor query string false "meta.nextCursor of the previous page"
+// @Success 200 {object} entity.QuestionList
+// @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /admin/questions [get]
 func (r *Routes) adminListQuestions(ctx *fiber.Ctx) error {
-	filter := repo.QuestionFilter{}
+	filter, err := questionFilter(ctx)
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminListQuestions - filter")
+		return errorResponse(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	query := questionusecase.ListQuery{
+		Sort:   entity.QuestionSort(ctx.Query("sort")),
+		Limit:  parseQueryInt(ctx, "limit", 0),
+		Cursor: ctx.Query("cursor"),
+	}
+	switch order := strings.ToLower(ctx.Query("order")); order {
+	case "":
+	case "asc", "desc":
+		desc := order == "desc"
+		query.Desc = &desc
+	default:
+		return errorResponse(ctx, http.StatusBadRequest, "invalid order: use asc or desc")
+	}
+
+	list, err := r.uc.Question.AdminList(ctx.UserContext(), filter, query)
+	if err != nil {
+		if errors.Is(err, questionusecase.ErrInvalidSort) || errors.Is(err, questionusecase.ErrInvalidCursor) {
+			return errorResponse(ctx, http.StatusBadRequest, err.Error())
+		}
+		r.l.Error(err, "http - v1 - adminListQuestions - usecase")
+		return errorResponse(ctx, http.StatusInternalServerError, "unable to list questions")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(list)
+}
+
+// @Summary Export questions
+// @Description Streams the matching questions as CSV (re-importable), NDJSON, Moodle GIFT or an IMS QTI 2.1 zip.
+// @Tags Admin: Questions
+// @Security AdminAuth
+// @Produce octet-stream
+// @Param format query string false "csv, ndjson, gift or qti (default csv)"
+// @Param q query string false "Full-text search over stem, options, reason and explanation"
+// @Param exam query string false "Exam"
+// @Param subjectId query string false "Subject ID"
+// @Param topicId query string false "Topic ID"
+// @Param isActive query bool false "Only published or other questions"
+// @Param status query string false "Comma-separated workflow states, e.g. draft,in_review"
+// @Param reviewerId query string false "Assigned reviewer ID"
+// @Param isHighYield query bool false "Only high-yield or other questions"
+// @Param isClinical query bool false "Only clinical or other questions"
+// @Param isImageBased query bool false "Only image-based or other questions"
+// @Param difficulty query string false "Comma-separated difficulty levels, e.g. 1,2"
+// @Param tags query string false "Comma-separated tag names; questions must carry all, e.g. PYQ-2019,AIIMS"
+// @Param choiceType query string false "Comma-separated choice types, e.g. single,multi"
+// @Param createdFrom query string false "Created at or after (RFC 3339 or YYYY-MM-DD)"
+// @Param createdTo query string false "Created before (RFC 3339 or YYYY-MM-DD)"
+// @Success 200 {file} file
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /admin/questions/export [get]
+func (r *Routes) adminExportQuestions(ctx *fiber.Ctx) error {
+	filter, err := questionFilter(ctx)
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminExportQuestions - filter")
+		return errorResponse(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	format := entity.QuestionExportFormat(strings.ToLower(ctx.Query("format", string(entity.QuestionExportCSV))))
+
+	contentType, extension, err := questionusecase.ExportFile(format)
+	if err != nil {
+		return errorResponse(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	// The body is written after the handler returns, so everything the writer
+	// needs is captured here.
+	userCtx := ctx.UserContext()
+
+	ctx.Status(http.StatusOK)
+	ctx.Set(fiber.HeaderContentType, contentType)
+	ctx.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="questions.%s"`, extension))
+	ctx.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := r.uc.Question.Export(userCtx, filter, format, w); err != nil {
+			r.l.Error(err, "http - v1 - adminExportQuestions - usecase")
+		}
+	})
+
+	return nil
+}
+
+// @Summary Duplicate question report
+// @Description Groups an exam's questions into clusters of likely duplicates, by MinHash similarity of their normalized text.
+// @Tags Admin: Questions
+// @Security AdminAuth
+// @Produce json
+// @Param exam query string true "Exam"
+// @Param threshold query number false "Minimum similarity, above 0 and at most 1 (default 0.8)"
+// @Success 200 {object} entity.QuestionDuplicateReport
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/questions/duplicates [get]
+func (r *Routes) adminQuestionDuplicates(ctx *fiber.Ctx) error {
+	exam := entity.ExamCategory(ctx.Query("exam"))
+	if exam == "" {
+		return errorResponse(ctx, http.StatusBadRequest, "exam is required")
+	}
+
+	threshold := questionusecase.DefaultDuplicateThreshold
+	if query := ctx.Query("threshold"); query != "" {
+		var err error
+		if threshold, err = strconv.ParseFloat(query, 64); err != nil {
+			return errorResponse(ctx, http.StatusBadRequest, "invalid threshold")
+		}
+	}
+
+	report, err := r.uc.Question.DuplicateReport(ctx.UserContext(), exam, threshold)
+	if err != nil {
+		if errors.Is(err, questionusecase.ErrInvalidThreshold) {
+			return errorResponse(ctx, http.StatusBadRequest, err.Error())
+		}
+		r.l.Error(err, "http - v1 - adminQuestionDuplicates - usecase")
+		return errorResponse(ctx, http.StatusInternalServerError, "unable to build duplicate report")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(report)
+}
+
+// questionFilter reads the filters shared by the question list and export.
+func questionFilter(ctx *fiber.Ctx) (repo.QuestionFilter, error) {
+	filter := repo.QuestionFilter{Search: strings.TrimSpace(ctx.Query("q"))}
 
 	if query := ctx.Query("exam"); query != "" {
 		exam := entity.ExamCategory(query)
 		filter.Exam = &exam
 	}
 
-	if value, err := parseQueryUUID(ctx, "subjectId"); err != nil {
-		r.l.Error(err, "http - v1 - adminListQuestions - subject")
-		return errorResponse(ctx, http.StatusBadRequest, "invalid subjectId")
-	} else {
-		filter.SubjectID = value
+	var err error
+	if filter.SubjectID, err = parseQueryUUID(ctx, "subjectId"); err != nil {
+		return filter, fmt.Errorf("invalid subjectId: %w", err)
 	}
 
-	if value, err := parseQueryUUID(ctx, "topicId"); err != nil {
-		r.l.Error(err, "http - v1 - adminListQuestions - topic")
-		return errorResponse(ctx, http.StatusBadRequest, "invalid topicId")
-	} else {
-		filter.TopicID = value
+	if filter.TopicID, err = parseQueryUUID(ctx, "topicId"); err != nil {
+		return filter, fmt.Errorf("invalid topicId: %w", err)
 	}
 
-	list, err := r.uc.Question.AdminList(ctx.UserContext(), filter)
-	if err != nil {
-		r.l.Error(err, "http - v1 - adminListQuestions - usecase")
-		return errorResponse(ctx, http.StatusInternalServerError, "unable to list questions")
+	if filter.ReviewerID, err = parseQueryUUID(ctx, "reviewerId"); err != nil {
+		return filter, fmt.Errorf("invalid reviewerId: %w", err)
 	}
 
-	return ctx.Status(http.StatusOK).JSON(list)
+	if query := ctx.Query("status"); query != "" {
+		for _, field := range strings.Split(query, ",") {
+			status := entity.QuestionStatus(strings.TrimSpace(field))
+			switch status {
+			case entity.QuestionStatusDraft, entity.QuestionStatusInReview, entity.QuestionStatusApproved,
+				entity.QuestionStatusPublished, entity.QuestionStatusRetired:
+				filter.Statuses = append(filter.Statuses, status)
+			default:
+				return filter, fmt.Errorf("invalid status %q", field)
+			}
+		}
+	}
+
+	flags := []struct {
+		key   string
+		value **bool
+	}{
+		{"isActive", &filter.IsActive},
+		{"isHighYield", &filter.IsHighYield},
+		{"isClinical", &filter.IsClinical},
+		{"isImageBased", &filter.IsImageBased},
+	}
+	for _, flag := range flags {
+		if *flag.value, err = parseQueryBool(ctx, flag.key); err != nil {
+			return filter, fmt.Errorf("invalid %s: %w", flag.key, err)
+		}
+	}
+
+	if query := ctx.Query("difficulty"); query != "" {
+		for _, field := range strings.Split(query, ",") {
+			level, err := strconv.Atoi(strings.TrimSpace(field))
+			if err != nil || level < 1 || level > 5 {
+				return filter, fmt.Errorf("invalid difficulty %q: levels are 1 to 5", field)
+			}
+			filter.DifficultyLevels = append(filter.DifficultyLevels, level)
+		}
+	}
+
+	if query := ctx.Query("tags"); query != "" {
+		filter.Tags = tagusecase.NormalizeAll(strings.Split(query, ","))
+		if slices.Contains(filter.Tags, "") {
+			return filter, errors.New("invalid tags: blank tag name")
+		}
+	}
+
+	if query := ctx.Query("choiceType"); query != "" {
+		for _, field := range strings.Split(query, ",") {
+			choiceType := entity.QuestionChoiceType(strings.TrimSpace(field))
+			switch choiceType {
+			case entity.ChoiceTypeSingle, entity.ChoiceTypeMulti, entity.ChoiceTypeNumerical,
+				entity.ChoiceTypeMatrixMatch, entity.ChoiceTypeAssertionReason:
+				filter.ChoiceTypes = append(filter.ChoiceTypes, choiceType)
+			default:
+				return filter, fmt.Errorf("invalid choiceType %q", field)
+			}
+		}
+	}
+
+	if filter.CreatedFrom, err = parseQueryTime(ctx, "createdFrom"); err != nil {
+		return filter, fmt.Errorf("invalid createdFrom: %w", err)
+	}
+
+	if filter.CreatedTo, err = parseQueryTime(ctx, "createdTo"); err != nil {
+		return filter, fmt.Errorf("invalid createdTo: %w", err)
+	}
+
+	return filter, nil
 }
 
 // @Summary Create question
@@ -63,8 +302,9 @@ func (r *Routes) adminListQuestions(ctx *fiber.Ctx) error {
 // @Security AdminAuth
 // @Accept json
 // @Produce json
+// @Description The response lists signed questions of the same exam that look like the new one; they are warnings only.
 // @Param request body entity.QuestionCreateRequest true "Question payload"
-// @Success 201 {object} entity.Question
+// @Success 201 {object} entity.QuestionCreateResult
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
@@ -81,8 +321,17 @@ func (r *Routes) adminCreateQuestion(ctx *fiber.Ctx) error {
 		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
 	}
 
-	created, err := r.uc.Question.AdminCreate(ctx.UserContext(), payload)
+	adminID, err := r.getUserID(ctx)
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminCreateQuestion - user")
+		return errorResponse(ctx, http.StatusUnauthorized, "invalid token")
+	}
+
+	created, err := r.uc.Question.AdminCreate(ctx.UserContext(), adminID, payload)
 	if err != nil {
+		if errors.Is(err, questionusecase.ErrInvalidAnswerKey) {
+			return errorResponse(ctx, http.StatusBadRequest, err.Error())
+		}
 		r.l.Error(err, "http - v1 - adminCreateQuestion - usecase")
 		return errorResponse(ctx, http.StatusInternalServerError, "unable to create question")
 	}
@@ -98,6 +347,7 @@ func (r *Routes) adminCreateQuestion(ctx *fiber.Ctx) error {
 // @Success 200 {object} entity.Question
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /admin/questions/{id} [get]
 func (r *Routes) adminGetQuestion(ctx *fiber.Ctx) error {
@@ -109,6 +359,9 @@ func (r *Routes) adminGetQuestion(ctx *fiber.Ctx) error {
 
 	question, err := r.uc.Question.AdminGet(ctx.UserContext(), id)
 	if err != nil {
+		if errors.Is(err, questionusecase.ErrNotFound) {
+			return errorResponse(ctx, http.StatusNotFound, err.Error())
+		}
 		r.l.Error(err, "http - v1 - adminGetQuestion - usecase")
 		return errorResponse(ctx, http.StatusInternalServerError, "unable to find question")
 	}
@@ -126,6 +379,8 @@ func (r *Routes) adminGetQuestion(ctx *fiber.Ctx) error {
 // @Success 200 {object} entity.Question
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /admin/questions/{id} [patch]
 func (r *Routes) adminUpdateQuestion(ctx *fiber.Ctx) error {
@@ -141,10 +396,30 @@ func (r *Routes) adminUpdateQuestion(ctx *fiber.Ctx) error {
 		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
 	}
 
-	updated, err := r.uc.Question.AdminUpdate(ctx.UserContext(), id, payload)
+	if err := r.v.Struct(payload); err != nil {
+		r.l.Error(err, "http - v1 - adminUpdateQuestion - validation")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	adminID, err := r.getUserID(ctx)
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminUpdateQuestion - user")
+		return errorResponse(ctx, http.StatusUnauthorized, "invalid token")
+	}
+
+	updated, err := r.uc.Question.AdminUpdate(ctx.UserContext(), adminID, id, payload)
 	if err != nil {
-		r.l.Error(err, "http - v1 - adminUpdateQuestion - usecase")
-		return errorResponse(ctx, http.StatusInternalServerError, "unable to update question")
+		switch {
+		case errors.Is(err, questionusecase.ErrNotFound):
+			return errorResponse(ctx, http.StatusNotFound, err.Error())
+		case errors.Is(err, questionusecase.ErrInvalidAnswerKey):
+			return errorResponse(ctx, http.StatusBadRequest, err.Error())
+		case errors.Is(err, questionusecase.ErrVersionConflict):
+			return errorResponse(ctx, http.StatusConflict, err.Error())
+		default:
+			r.l.Error(err, "http - v1 - adminUpdateQuestion - usecase")
+			return errorResponse(ctx, http.StatusInternalServerError, "unable to update question")
+		}
 	}
 
 	return ctx.Status(http.StatusOK).JSON(updated)
@@ -172,3 +447,292 @@ func (r *Routes) adminDeleteQuestion(ctx *fiber.Ctx) error {
 
 	return ctx.SendStatus(http.StatusNoContent)
 }
+
+// @Summary Import questions
+// @Description Uploads a CSV, NDJSON (MedMCQA) or XLSX file. With dryRun the file is only validated and a report returned; otherwise a background import job is queued.
+// @Tags Admin: Questions
+// @Security AdminAuth
+// @Accept mpfd
+// @Produce json
+// @Param file formData file true "Import file"
+// @Param exam formData string true "Exam"
+// @Param format formData string false "csv, ndjson or xlsx; taken from the file extension when omitted"
+// @Param createMissing formData bool false "Create subjects and topics the bank lacks"
+// @Param dryRun formData bool false "Validate only"
+// @Success 200 {object} entity.QuestionImportReport
+// @Success 202 {object} entity.QuestionImportJob
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/questions/import [post]
+func (r *Routes) adminImportQuestions(ctx *fiber.Ctx) error {
+	var payload request.AdminQuestionImportRequest
+	if err := ctx.BodyParser(&payload); err != nil {
+		r.l.Error(err, "http - v1 - adminImportQuestions - parse")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	if err := r.v.Struct(payload); err != nil {
+		r.l.Error(err, "http - v1 - adminImportQuestions - validation")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		return errorResponse(ctx, http.StatusBadRequest, "file is required")
+	}
+
+	data, err := readFormFile(file)
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminImportQuestions - file")
+		return errorResponse(ctx, http.StatusBadRequest, "unable to read file")
+	}
+
+	opts := entity.QuestionImportOptions{
+		Exam:          payload.Exam,
+		Format:        payload.Format,
+		CreateMissing: payload.CreateMissing,
+	}
+
+	if payload.DryRun {
+		report, err := r.uc.Question.DryRunImport(ctx.UserContext(), opts, file.Filename, data)
+		if err != nil {
+			return r.importError(ctx, err)
+		}
+
+		return ctx.Status(http.StatusOK).JSON(report)
+	}
+
+	adminID, err := r.getUserID(ctx)
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminImportQuestions - user")
+		return errorResponse(ctx, http.StatusUnauthorized, "invalid token")
+	}
+
+	job, err := r.uc.Question.StartImport(ctx.UserContext(), adminID, opts, file.Filename, data)
+	if err != nil {
+		return r.importError(ctx, err)
+	}
+
+	return ctx.Status(http.StatusAccepted).JSON(job)
+}
+
+func (r *Routes) importError(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, questionusecase.ErrUnsupportedFormat),
+		errors.Is(err, questionusecase.ErrInvalidImportFile),
+		errors.Is(err, questionusecase.ErrUnknownExam):
+		return errorResponse(ctx, http.StatusBadRequest, err.Error())
+	default:
+		r.l.Error(err, "http - v1 - adminImportQuestions - usecase")
+		return errorResponse(ctx, http.StatusInternalServerError, "unable to import questions")
+	}
+}
+
+// @Summary List question imports
+// @Tags Admin: Questions
+// @Security AdminAuth
+// @Produce json
+// @Success 200 {array} entity.QuestionImportJob
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/questions/import [get]
+func (r *Routes) adminListQuestionImports(ctx *fiber.Ctx) error {
+	jobs, err := r.uc.Question.ListImports(ctx.UserContext())
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminListQuestionImports - usecase")
+		return errorResponse(ctx, http.StatusInternalServerError, "unable to list imports")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(jobs)
+}
+
+// @Summary Get question import
+// @Tags Admin: Questions
+// @Security AdminAuth
+// @Produce json
+// @Param id path string true "Import job ID"
+// @Success 200 {object} entity.QuestionImportJob
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/questions/import/{id} [get]
+func (r *Routes) adminGetQuestionImport(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx, "id")
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminGetQuestionImport")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id")
+	}
+
+	job, err := r.uc.Question.GetImport(ctx.UserContext(), id)
+	if err != nil {
+		if errors.Is(err, questionusecase.ErrImportNotFound) {
+			return errorResponse(ctx, http.StatusNotFound, err.Error())
+		}
+		r.l.Error(err, "http - v1 - adminGetQuestionImport - usecase")
+		return errorResponse(ctx, http.StatusInternalServerError, "unable to find import")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(job)
+}
+
+func readFormFile(header *multipart.FileHeader) ([]byte, error) {
+	file, err := header.Open()
+	if err != nil {
+		return nil, fmt.Errorf("open: %w", err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("read: %w", err)
+	}
+
+	return data, nil
+}
+
+// @Summary List question versions
+// @Description Every create, update and rollback is kept as an immutable version, newest first.
+// @Tags Admin: Questions
+// @Security AdminAuth
+// @Produce json
+// @Param id path string true "Question ID"
+// @Success 200 {array} entity.QuestionVersion
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/questions/{id}/versions [get]
+func (r *Routes) adminListQuestionVersions(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx, "id")
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminListQuestionVersions")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id")
+	}
+
+	versions, err := r.uc.Question.ListVersions(ctx.UserContext(), id)
+	if err != nil {
+		return r.questionVersionError(ctx, err, "adminListQuestionVersions")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(versions)
+}
+
+// @Summary Get question version
+// @Tags Admin: Questions
+// @Security AdminAuth
+// @Produce json
+// @Param id path string true "Question ID"
+// @Param version path int true "Version"
+// @Success 200 {object} entity.QuestionVersion
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/questions/{id}/versions/{version} [get]
+func (r *Routes) adminGetQuestionVersion(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx, "id")
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminGetQuestionVersion")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id")
+	}
+
+	version, err := ctx.ParamsInt("version")
+	if err != nil || version < 1 {
+		return errorResponse(ctx, http.StatusBadRequest, "invalid version")
+	}
+
+	v, err := r.uc.Question.GetVersion(ctx.UserContext(), id, version)
+	if err != nil {
+		return r.questionVersionError(ctx, err, "adminGetQuestionVersion")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(v)
+}
+
+// @Summary Diff question versions
+// @Tags Admin: Questions
+// @Security AdminAuth
+// @Produce json
+// @Param id path string true "Question ID"
+// @Param from query int true "Older version"
+// @Param to query int true "Newer version"
+// @Success 200 {object} entity.QuestionVersionDiff
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/questions/{id}/versions/diff [get]
+func (r *Routes) adminDiffQuestionVersions(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx, "id")
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminDiffQuestionVersions")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id")
+	}
+
+	from, errFrom := strconv.Atoi(ctx.Query("from"))
+	to, errTo := strconv.Atoi(ctx.Query("to"))
+	if errFrom != nil || errTo != nil || from < 1 || to < 1 {
+		return errorResponse(ctx, http.StatusBadRequest, "from and to must be version numbers")
+	}
+
+	diff, err := r.uc.Question.DiffVersions(ctx.UserContext(), id, from, to)
+	if err != nil {
+		return r.questionVersionError(ctx, err, "adminDiffQuestionVersions")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(diff)
+}
+
+// @Summary Roll back question
+// @Description Restores the content of an earlier version as a new version.
+// @Tags Admin: Questions
+// @Security AdminAuth
+// @Produce json
+// @Param id path string true "Question ID"
+// @Param version path int true "Version to restore"
+// @Success 200 {object} entity.Question
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/questions/{id}/versions/{version}/rollback [post]
+func (r *Routes) adminRollbackQuestion(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx, "id")
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminRollbackQuestion")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id")
+	}
+
+	version, err := ctx.ParamsInt("version")
+	if err != nil || version < 1 {
+		return errorResponse(ctx, http.StatusBadRequest, "invalid version")
+	}
+
+	adminID, err := r.getUserID(ctx)
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminRollbackQuestion - user")
+		return errorResponse(ctx, http.StatusUnauthorized, "invalid token")
+	}
+
+	question, err := r.uc.Question.Rollback(ctx.UserContext(), adminID, id, version)
+	if err != nil {
+		return r.questionVersionError(ctx, err, "adminRollbackQuestion")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(question)
+}
+
+func (r *Routes) questionVersionError(ctx *fiber.Ctx, err error, handler string) error {
+	switch {
+	case errors.Is(err, questionusecase.ErrNotFound), errors.Is(err, questionusecase.ErrVersionNotFound):
+		return errorResponse(ctx, http.StatusNotFound, err.Error())
+	case errors.Is(err, questionusecase.ErrVersionConflict):
+		return errorResponse(ctx, http.StatusConflict, err.Error())
+	default:
+		r.l.Error(err, "http - v1 - "+handler+" - usecase")
+		return errorResponse(ctx, http.StatusInternalServerError, "unable to load question versions")
+	}
+}
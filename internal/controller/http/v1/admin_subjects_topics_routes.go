@@ -1,39 +1,51 @@
 package v1
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/evrone/go-clean-template/internal/controller/http/v1/request"
 	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase/syllabus"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 )
 
-var (
-	_ = entity.Subject{}
-	_ = entity.Topic{}
-)
-
 func registerAdminSubjectsTopicsRoutes(api fiber.Router, r *Routes) {
 	subjects := api.Group("/subjects")
 	subjects.Get("", r.adminListSubjects)
 	subjects.Post("", r.adminCreateSubject)
+	subjects.Put("/order", r.adminReorderSubjects)
+	subjects.Patch("/:id", r.adminUpdateSubject)
+	subjects.Delete("/:id", r.adminDeleteSubject)
 
 	topics := api.Group("/topics")
 	topics.Get("", r.adminListTopics)
 	topics.Post("", r.adminCreateTopic)
+	topics.Put("/order", r.adminReorderTopics)
+	topics.Patch("/:id", r.adminUpdateTopic)
+	topics.Delete("/:id", r.adminDeleteTopic)
+	topics.Post("/:id/merge", r.adminMergeTopic)
 }
 
 // @Summary List subjects
+// @Description Every subject, active or not, in display order.
 // @Tags Admin: Subjects
 // @Security AdminAuth
 // @Produce json
+// @Param exam query string false "Exam category"
 // @Success 200 {array} entity.Subject
 // @Failure 401 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /admin/subjects [get]
 func (r *Routes) adminListSubjects(ctx *fiber.Ctx) error {
-	subjects, err := r.uc.User.ListSubjects(ctx.UserContext(), nil)
+	var exam *entity.ExamCategory
+	if query := ctx.Query("exam"); query != "" {
+		value := entity.ExamCategory(query)
+		exam = &value
+	}
+
+	subjects, err := r.uc.Syllabus.ListSubjects(ctx.UserContext(), exam)
 	if err != nil {
 		r.l.Error(err, "http - v1 - adminListSubjects")
 		return errorResponse(ctx, http.StatusInternalServerError, "unable to list subjects")
@@ -51,6 +63,8 @@ func (r *Routes) adminListSubjects(ctx *fiber.Ctx) error {
 // @Success 201 {object} entity.Subject
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /admin/subjects [post]
 func (r *Routes) adminCreateSubject(ctx *fiber.Ctx) error {
@@ -65,16 +79,115 @@ func (r *Routes) adminCreateSubject(ctx *fiber.Ctx) error {
 		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
 	}
 
-	subject, err := r.uc.User.CreateSubject(ctx.UserContext(), payload.Exam, payload.Name)
+	subject, err := r.uc.Syllabus.CreateSubject(ctx.UserContext(), payload.Exam, payload.Name)
 	if err != nil {
-		r.l.Error(err, "http - v1 - adminCreateSubject - usecase")
-		return errorResponse(ctx, http.StatusInternalServerError, "unable to create subject")
+		return r.syllabusError(ctx, err, "adminCreateSubject")
 	}
 
 	return ctx.Status(http.StatusCreated).JSON(subject)
 }
 
+// @Summary Update subject
+// @Description Renames or (de)activates a subject. Learners never see an inactive subject and practice skips its questions.
+// @Tags Admin: Subjects
+// @Security AdminAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Subject ID"
+// @Param request body entity.SubjectUpdateRequest true "Fields to change"
+// @Success 200 {object} entity.Subject
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/subjects/{id} [patch]
+func (r *Routes) adminUpdateSubject(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx, "id")
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminUpdateSubject")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id")
+	}
+
+	var payload entity.SubjectUpdateRequest
+	if err := ctx.BodyParser(&payload); err != nil {
+		r.l.Error(err, "http - v1 - adminUpdateSubject - parse")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	if err := r.v.Struct(payload); err != nil {
+		r.l.Error(err, "http - v1 - adminUpdateSubject - validation")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	subject, err := r.uc.Syllabus.UpdateSubject(ctx.UserContext(), id, payload)
+	if err != nil {
+		return r.syllabusError(ctx, err, "adminUpdateSubject")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(subject)
+}
+
+// @Summary Reorder subjects
+// @Description Sets the display order of an exam's subjects. ids must list every subject of the exam exactly once.
+// @Tags Admin: Subjects
+// @Security AdminAuth
+// @Accept json
+// @Produce json
+// @Param request body entity.SubjectReorderRequest true "Subjects in display order"
+// @Success 200 {array} entity.Subject
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/subjects/order [put]
+func (r *Routes) adminReorderSubjects(ctx *fiber.Ctx) error {
+	var payload entity.SubjectReorderRequest
+	if err := ctx.BodyParser(&payload); err != nil {
+		r.l.Error(err, "http - v1 - adminReorderSubjects - parse")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	if err := r.v.Struct(payload); err != nil {
+		r.l.Error(err, "http - v1 - adminReorderSubjects - validation")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	subjects, err := r.uc.Syllabus.ReorderSubjects(ctx.UserContext(), payload)
+	if err != nil {
+		return r.syllabusError(ctx, err, "adminReorderSubjects")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(subjects)
+}
+
+// @Summary Delete subject
+// @Description Only empty subjects can be deleted; delete or merge their topics first.
+// @Tags Admin: Subjects
+// @Security AdminAuth
+// @Param id path string true "Subject ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/subjects/{id} [delete]
+func (r *Routes) adminDeleteSubject(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx, "id")
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminDeleteSubject")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id")
+	}
+
+	if err := r.uc.Syllabus.DeleteSubject(ctx.UserContext(), id); err != nil {
+		return r.syllabusError(ctx, err, "adminDeleteSubject")
+	}
+
+	return ctx.SendStatus(http.StatusNoContent)
+}
+
 // @Summary List topics
+// @Description Every topic and subtopic of the subject, active or not, in display order. Subtopics carry parentId.
 // @Tags Admin: Topics
 // @Security AdminAuth
 // @Produce json
@@ -95,7 +208,7 @@ func (r *Routes) adminListTopics(ctx *fiber.Ctx) error {
 		id = *subjectID
 	}
 
-	topics, err := r.uc.User.ListTopics(ctx.UserContext(), id)
+	topics, err := r.uc.Syllabus.ListTopics(ctx.UserContext(), id)
 	if err != nil {
 		r.l.Error(err, "http - v1 - adminListTopics - usecase")
 		return errorResponse(ctx, http.StatusInternalServerError, "unable to list topics")
@@ -105,6 +218,7 @@ func (r *Routes) adminListTopics(ctx *fiber.Ctx) error {
 }
 
 // @Summary Create topic
+// @Description Adds a topic, or a subtopic when parentId is set, after its existing siblings.
 // @Tags Admin: Topics
 // @Security AdminAuth
 // @Accept json
@@ -113,6 +227,8 @@ func (r *Routes) adminListTopics(ctx *fiber.Ctx) error {
 // @Success 201 {object} entity.Topic
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /admin/topics [post]
 func (r *Routes) adminCreateTopic(ctx *fiber.Ctx) error {
@@ -127,11 +243,169 @@ func (r *Routes) adminCreateTopic(ctx *fiber.Ctx) error {
 		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
 	}
 
-	topic, err := r.uc.User.CreateTopic(ctx.UserContext(), payload.SubjectID, payload.Name)
+	topic, err := r.uc.Syllabus.CreateTopic(ctx.UserContext(), payload.SubjectID, payload.ParentID, payload.Name)
 	if err != nil {
-		r.l.Error(err, "http - v1 - adminCreateTopic - usecase")
-		return errorResponse(ctx, http.StatusInternalServerError, "unable to create topic")
+		return r.syllabusError(ctx, err, "adminCreateTopic")
 	}
 
 	return ctx.Status(http.StatusCreated).JSON(topic)
 }
+
+// @Summary Update topic
+// @Description Renames, moves or (de)activates a topic. parentId moves it under another topic of the same subject, makeRoot to the top level. Deactivating a topic deactivates its subtopics.
+// @Tags Admin: Topics
+// @Security AdminAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Topic ID"
+// @Param request body entity.TopicUpdateRequest true "Fields to change"
+// @Success 200 {object} entity.Topic
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/topics/{id} [patch]
+func (r *Routes) adminUpdateTopic(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx, "id")
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminUpdateTopic")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id")
+	}
+
+	var payload entity.TopicUpdateRequest
+	if err := ctx.BodyParser(&payload); err != nil {
+		r.l.Error(err, "http - v1 - adminUpdateTopic - parse")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	if err := r.v.Struct(payload); err != nil {
+		r.l.Error(err, "http - v1 - adminUpdateTopic - validation")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	topic, err := r.uc.Syllabus.UpdateTopic(ctx.UserContext(), id, payload)
+	if err != nil {
+		return r.syllabusError(ctx, err, "adminUpdateTopic")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(topic)
+}
+
+// @Summary Reorder topics
+// @Description Sets the display order of the children of parentId, or of the subject's top-level topics when parentId is omitted. ids must list every such sibling exactly once.
+// @Tags Admin: Topics
+// @Security AdminAuth
+// @Accept json
+// @Produce json
+// @Param request body entity.TopicReorderRequest true "Sibling topics in display order"
+// @Success 200 {array} entity.Topic
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/topics/order [put]
+func (r *Routes) adminReorderTopics(ctx *fiber.Ctx) error {
+	var payload entity.TopicReorderRequest
+	if err := ctx.BodyParser(&payload); err != nil {
+		r.l.Error(err, "http - v1 - adminReorderTopics - parse")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	if err := r.v.Struct(payload); err != nil {
+		r.l.Error(err, "http - v1 - adminReorderTopics - validation")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	topics, err := r.uc.Syllabus.ReorderTopics(ctx.UserContext(), payload)
+	if err != nil {
+		return r.syllabusError(ctx, err, "adminReorderTopics")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(topics)
+}
+
+// @Summary Delete topic
+// @Description Only topics without questions, podcasts or subtopics can be deleted; merge the others into another topic.
+// @Tags Admin: Topics
+// @Security AdminAuth
+// @Param id path string true "Topic ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/topics/{id} [delete]
+func (r *Routes) adminDeleteTopic(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx, "id")
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminDeleteTopic")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id")
+	}
+
+	if err := r.uc.Syllabus.DeleteTopic(ctx.UserContext(), id); err != nil {
+		return r.syllabusError(ctx, err, "adminDeleteTopic")
+	}
+
+	return ctx.SendStatus(http.StatusNoContent)
+}
+
+// @Summary Merge topic
+// @Description Moves every question, podcast and subtopic of the topic into targetId and deletes the topic, in one transaction. The target may be in another subject of the same exam.
+// @Tags Admin: Topics
+// @Security AdminAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Topic ID to merge away"
+// @Param request body entity.TopicMergeRequest true "Target topic"
+// @Success 200 {object} entity.TopicMergeResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/topics/{id}/merge [post]
+func (r *Routes) adminMergeTopic(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx, "id")
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminMergeTopic")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id")
+	}
+
+	var payload entity.TopicMergeRequest
+	if err := ctx.BodyParser(&payload); err != nil {
+		r.l.Error(err, "http - v1 - adminMergeTopic - parse")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	if err := r.v.Struct(payload); err != nil {
+		r.l.Error(err, "http - v1 - adminMergeTopic - validation")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	result, err := r.uc.Syllabus.MergeTopics(ctx.UserContext(), id, payload)
+	if err != nil {
+		return r.syllabusError(ctx, err, "adminMergeTopic")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(result)
+}
+
+func (r *Routes) syllabusError(ctx *fiber.Ctx, err error, handler string) error {
+	switch {
+	case errors.Is(err, syllabus.ErrBlankName),
+		errors.Is(err, syllabus.ErrInvalidParent),
+		errors.Is(err, syllabus.ErrInvalidOrder),
+		errors.Is(err, syllabus.ErrInvalidMerge):
+		return errorResponse(ctx, http.StatusBadRequest, err.Error())
+	case errors.Is(err, syllabus.ErrSubjectNotFound),
+		errors.Is(err, syllabus.ErrTopicNotFound),
+		errors.Is(err, syllabus.ErrExamNotFound):
+		return errorResponse(ctx, http.StatusNotFound, err.Error())
+	case errors.Is(err, syllabus.ErrNameTaken), errors.Is(err, syllabus.ErrInUse):
+		return errorResponse(ctx, http.StatusConflict, err.Error())
+	default:
+		r.l.Error(err, "http - v1 - "+handler+" - usecase")
+		return errorResponse(ctx, http.StatusInternalServerError, "unable to update syllabus")
+	}
+}
@@ -0,0 +1,218 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/repo"
+	tagusecase "github.com/evrone/go-clean-template/internal/usecase/tag"
+)
+
+func registerAdminTagRoutes(api fiber.Router, r *Routes) {
+	api.Get("", r.adminListTags)
+	api.Post("", r.adminCreateTag)
+	api.Patch("/:id", r.adminUpdateTag)
+	api.Delete("/:id", r.adminDeleteTag)
+}
+
+// @Summary List tags
+// @Description The tag vocabulary with question counts, by kind, newest year first, then name.
+// @Tags Admin: Tags
+// @Security AdminAuth
+// @Produce json
+// @Param kind query string false "pyq, source, concept or free"
+// @Param q query string false "Name prefix, in any case"
+// @Param page query int false "Page"
+// @Param pageSize query int false "Page size, at most 200"
+// @Success 200 {object} entity.TagList
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/tags [get]
+func (r *Routes) adminListTags(ctx *fiber.Ctx) error {
+	filter := repo.TagFilter{
+		Search:   ctx.Query("q"),
+		Page:     parseQueryInt(ctx, "page", 1),
+		PageSize: parseQueryInt(ctx, "pageSize", 50),
+	}
+	if query := ctx.Query("kind"); query != "" {
+		kind := entity.TagKind(query)
+		if !validTagKind(kind) {
+			return errorResponse(ctx, http.StatusBadRequest, "invalid kind")
+		}
+		filter.Kind = &kind
+	}
+
+	list, err := r.uc.Tag.List(ctx.UserContext(), filter)
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminListTags - usecase")
+		return errorResponse(ctx, http.StatusInternalServerError, "unable to list tags")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(list)
+}
+
+// @Summary Create tag
+// @Description Adds a tag to the controlled vocabulary. pyq tags need the year of the paper.
+// @Tags Admin: Tags
+// @Security AdminAuth
+// @Accept json
+// @Produce json
+// @Param request body entity.TagCreateRequest true "Tag"
+// @Success 201 {object} entity.Tag
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/tags [post]
+func (r *Routes) adminCreateTag(ctx *fiber.Ctx) error {
+	var payload entity.TagCreateRequest
+	if err := ctx.BodyParser(&payload); err != nil {
+		r.l.Error(err, "http - v1 - adminCreateTag - parse")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	if err := r.v.Struct(payload); err != nil {
+		r.l.Error(err, "http - v1 - adminCreateTag - validation")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	tag, err := r.uc.Tag.Create(ctx.UserContext(), payload)
+	if err != nil {
+		return r.tagError(ctx, err, "adminCreateTag")
+	}
+
+	return ctx.Status(http.StatusCreated).JSON(tag)
+}
+
+// @Summary Update tag
+// @Description Renames a tag or changes its kind or year, e.g. to promote a free tag into the vocabulary. Questions keep the tag.
+// @Tags Admin: Tags
+// @Security AdminAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Tag ID"
+// @Param request body entity.TagUpdateRequest true "Changes"
+// @Success 200 {object} entity.Tag
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/tags/{id} [patch]
+func (r *Routes) adminUpdateTag(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx, "id")
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminUpdateTag")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id")
+	}
+
+	var payload entity.TagUpdateRequest
+	if err := ctx.BodyParser(&payload); err != nil {
+		r.l.Error(err, "http - v1 - adminUpdateTag - parse")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	if err := r.v.Struct(payload); err != nil {
+		r.l.Error(err, "http - v1 - adminUpdateTag - validation")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	tag, err := r.uc.Tag.Update(ctx.UserContext(), id, payload)
+	if err != nil {
+		return r.tagError(ctx, err, "adminUpdateTag")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(tag)
+}
+
+// @Summary Delete tag
+// @Description Removes the tag from the vocabulary and from every question.
+// @Tags Admin: Tags
+// @Security AdminAuth
+// @Param id path string true "Tag ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/tags/{id} [delete]
+func (r *Routes) adminDeleteTag(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx, "id")
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminDeleteTag")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id")
+	}
+
+	if err := r.uc.Tag.Delete(ctx.UserContext(), id); err != nil {
+		return r.tagError(ctx, err, "adminDeleteTag")
+	}
+
+	return ctx.SendStatus(http.StatusNoContent)
+}
+
+// @Summary Set question tags
+// @Description Replaces the tags of a question. Names match existing tags in any case; unknown names are created as free tags. Not recorded in question versions.
+// @Tags Admin: Questions
+// @Security AdminAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Question ID"
+// @Param request body entity.QuestionTagsRequest true "Tag names"
+// @Success 200 {object} entity.Question
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/questions/{id}/tags [put]
+func (r *Routes) adminSetQuestionTags(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx, "id")
+	if err != nil {
+		r.l.Error(err, "http - v1 - adminSetQuestionTags")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id")
+	}
+
+	var payload entity.QuestionTagsRequest
+	if err := ctx.BodyParser(&payload); err != nil {
+		r.l.Error(err, "http - v1 - adminSetQuestionTags - parse")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	if err := r.v.Struct(payload); err != nil {
+		r.l.Error(err, "http - v1 - adminSetQuestionTags - validation")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	question, err := r.uc.Tag.SetQuestionTags(ctx.UserContext(), id, payload)
+	if err != nil {
+		return r.tagError(ctx, err, "adminSetQuestionTags")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(question)
+}
+
+func (r *Routes) tagError(ctx *fiber.Ctx, err error, handler string) error {
+	switch {
+	case errors.Is(err, tagusecase.ErrInvalidTag):
+		return errorResponse(ctx, http.StatusBadRequest, err.Error())
+	case errors.Is(err, tagusecase.ErrNotFound), errors.Is(err, tagusecase.ErrQuestionNotFound):
+		return errorResponse(ctx, http.StatusNotFound, err.Error())
+	case errors.Is(err, tagusecase.ErrNameTaken):
+		return errorResponse(ctx, http.StatusConflict, err.Error())
+	default:
+		r.l.Error(err, "http - v1 - "+handler+" - usecase")
+		return errorResponse(ctx, http.StatusInternalServerError, "unable to update tags")
+	}
+}
+
+func validTagKind(kind entity.TagKind) bool {
+	switch kind {
+	case entity.TagKindPYQ, entity.TagKindSource, entity.TagKindConcept, entity.TagKindFree:
+		return true
+	default:
+		return false
+	}
+}
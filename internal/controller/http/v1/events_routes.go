@@ -1,19 +1,26 @@
 package v1
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase/exam"
 	"github.com/gofiber/fiber/v2"
 )
 
-var _ = entity.ExamSummary{}
-
 func registerEventsRoutes(api fiber.Router, r *Routes) {
 	api.Get("", r.listEvents)
+	api.Post("/:id/register", r.registerForEvent)
+	api.Delete("/:id/register", r.cancelEventRegistration)
+	api.Post("/:id/attempt", r.startExamAttempt)
+	api.Get("/:id/attempt", r.getExamAttempt)
+	api.Put("/:id/attempt/answers", r.saveExamAnswer)
+	api.Post("/:id/attempt/submit", r.submitExamAttempt)
 }
 
 // @Summary List exams/events
+// @Description Scheduled and ongoing exams, soonest first, with the caller's registration and the seats left.
 // @Tags App: Exams
 // @Security UserAuth
 // @Produce json
@@ -22,7 +29,13 @@ func registerEventsRoutes(api fiber.Router, r *Routes) {
 // @Failure 500 {object} ErrorResponse
 // @Router /events [get]
 func (r *Routes) listEvents(ctx *fiber.Ctx) error {
-	events, err := r.uc.Exam.ListEvents(ctx.UserContext())
+	userID, err := r.getUserID(ctx)
+	if err != nil {
+		r.l.Error(err, "http - v1 - listEvents - user")
+		return errorResponse(ctx, http.StatusUnauthorized, "invalid token")
+	}
+
+	events, err := r.uc.Exam.ListEvents(ctx.UserContext(), userID)
 	if err != nil {
 		r.l.Error(err, "http - v1 - listEvents")
 		return errorResponse(ctx, http.StatusInternalServerError, "unable to load events")
@@ -30,3 +43,285 @@ func (r *Routes) listEvents(ctx *fiber.Ctx) error {
 
 	return ctx.Status(http.StatusOK).JSON(events)
 }
+
+// @Summary Register for an exam
+// @Description Takes a seat, paying the entry fee with an optional entry-pass coupon and the wallet. Repeating the request returns the existing registration without charging again.
+// @Tags App: Exams
+// @Security UserAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Exam ID"
+// @Param request body entity.ExamRegisterRequest false "Entry pass"
+// @Success 200 {object} entity.ExamRegistration "Already registered"
+// @Success 201 {object} entity.ExamRegistration
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 402 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /events/{id}/register [post]
+func (r *Routes) registerForEvent(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx, "id")
+	if err != nil {
+		r.l.Error(err, "http - v1 - registerForEvent")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id")
+	}
+
+	var payload entity.ExamRegisterRequest
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(&payload); err != nil {
+			r.l.Error(err, "http - v1 - registerForEvent - parse")
+			return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+		}
+	}
+
+	if err := r.v.Struct(payload); err != nil {
+		r.l.Error(err, "http - v1 - registerForEvent - validation")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	userID, err := r.getUserID(ctx)
+	if err != nil {
+		r.l.Error(err, "http - v1 - registerForEvent - user")
+		return errorResponse(ctx, http.StatusUnauthorized, "invalid token")
+	}
+
+	registration, created, err := r.uc.Exam.Register(ctx.UserContext(), userID, id, payload)
+	if err != nil {
+		return r.registrationError(ctx, err, "registerForEvent")
+	}
+
+	if created {
+		return ctx.Status(http.StatusCreated).JSON(registration)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(registration)
+}
+
+// @Summary Cancel exam registration
+// @Description Gives up the seat before the exam starts. The wallet-paid fee is refunded according to the refund policy and an entry pass can be used again.
+// @Tags App: Exams
+// @Security UserAuth
+// @Produce json
+// @Param id path string true "Exam ID"
+// @Success 200 {object} entity.ExamRegistration
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /events/{id}/register [delete]
+func (r *Routes) cancelEventRegistration(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx, "id")
+	if err != nil {
+		r.l.Error(err, "http - v1 - cancelEventRegistration")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id")
+	}
+
+	userID, err := r.getUserID(ctx)
+	if err != nil {
+		r.l.Error(err, "http - v1 - cancelEventRegistration - user")
+		return errorResponse(ctx, http.StatusUnauthorized, "invalid token")
+	}
+
+	registration, err := r.uc.Exam.Cancel(ctx.UserContext(), userID, id)
+	if err != nil {
+		return r.registrationError(ctx, err, "cancelEventRegistration")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(registration)
+}
+
+func (r *Routes) registrationError(ctx *fiber.Ctx, err error, handler string) error {
+	switch {
+	case errors.Is(err, exam.ErrCouponUnavailable):
+		return errorResponse(ctx, http.StatusBadRequest, err.Error())
+	case errors.Is(err, exam.ErrInsufficientFunds):
+		return errorResponse(ctx, http.StatusPaymentRequired, err.Error())
+	case errors.Is(err, exam.ErrNotFound), errors.Is(err, exam.ErrNotRegistered):
+		return errorResponse(ctx, http.StatusNotFound, err.Error())
+	case errors.Is(err, exam.ErrRegistrationClosed),
+		errors.Is(err, exam.ErrFull),
+		errors.Is(err, exam.ErrCancelClosed):
+		return errorResponse(ctx, http.StatusConflict, err.Error())
+	default:
+		r.l.Error(err, "http - v1 - "+handler+" - usecase")
+		return errorResponse(ctx, http.StatusInternalServerError, "unable to update registration")
+	}
+}
+
+// @Summary Start an exam attempt
+// @Description Opens the caller's attempt while the exam window is open and serves the frozen paper without answer keys. Repeating the request resumes the existing attempt.
+// @Tags App: Exams
+// @Security UserAuth
+// @Produce json
+// @Param id path string true "Exam ID"
+// @Success 200 {object} entity.ExamAttemptDetail "Resumed"
+// @Success 201 {object} entity.ExamAttemptDetail
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /events/{id}/attempt [post]
+func (r *Routes) startExamAttempt(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx, "id")
+	if err != nil {
+		r.l.Error(err, "http - v1 - startExamAttempt")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id")
+	}
+
+	userID, err := r.getUserID(ctx)
+	if err != nil {
+		r.l.Error(err, "http - v1 - startExamAttempt - user")
+		return errorResponse(ctx, http.StatusUnauthorized, "invalid token")
+	}
+
+	detail, created, err := r.uc.Exam.StartAttempt(ctx.UserContext(), userID, id)
+	if err != nil {
+		return r.attemptError(ctx, err, "startExamAttempt")
+	}
+
+	if created {
+		return ctx.Status(http.StatusCreated).JSON(detail)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(detail)
+}
+
+// @Summary Get exam attempt
+// @Description The caller's attempt with its paper and saved answers. An attempt past its deadline is graded first. Answer keys and per-question marks are shown once the exam window has ended.
+// @Tags App: Exams
+// @Security UserAuth
+// @Produce json
+// @Param id path string true "Exam ID"
+// @Success 200 {object} entity.ExamAttemptDetail
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /events/{id}/attempt [get]
+func (r *Routes) getExamAttempt(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx, "id")
+	if err != nil {
+		r.l.Error(err, "http - v1 - getExamAttempt")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id")
+	}
+
+	userID, err := r.getUserID(ctx)
+	if err != nil {
+		r.l.Error(err, "http - v1 - getExamAttempt - user")
+		return errorResponse(ctx, http.StatusUnauthorized, "invalid token")
+	}
+
+	detail, err := r.uc.Exam.GetAttempt(ctx.UserContext(), userID, id)
+	if err != nil {
+		return r.attemptError(ctx, err, "getExamAttempt")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(detail)
+}
+
+// @Summary Save exam answer
+// @Description Autosaves the answer to one question of the caller's attempt. Answers can be changed or cleared until the deadline.
+// @Tags App: Exams
+// @Security UserAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Exam ID"
+// @Param request body entity.ExamAnswerRequest true "Answer"
+// @Success 200 {object} entity.ExamAttemptQuestion
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /events/{id}/attempt/answers [put]
+func (r *Routes) saveExamAnswer(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx, "id")
+	if err != nil {
+		r.l.Error(err, "http - v1 - saveExamAnswer")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id")
+	}
+
+	var payload entity.ExamAnswerRequest
+	if err := ctx.BodyParser(&payload); err != nil {
+		r.l.Error(err, "http - v1 - saveExamAnswer - parse")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	if err := r.v.Struct(payload); err != nil {
+		r.l.Error(err, "http - v1 - saveExamAnswer - validation")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	userID, err := r.getUserID(ctx)
+	if err != nil {
+		r.l.Error(err, "http - v1 - saveExamAnswer - user")
+		return errorResponse(ctx, http.StatusUnauthorized, "invalid token")
+	}
+
+	question, err := r.uc.Exam.SaveAnswer(ctx.UserContext(), userID, id, payload)
+	if err != nil {
+		return r.attemptError(ctx, err, "saveExamAnswer")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(question)
+}
+
+// @Summary Submit exam attempt
+// @Description Closes the caller's attempt and grades the saved answers with the exam's marking. After the deadline the attempt closes as timed out.
+// @Tags App: Exams
+// @Security UserAuth
+// @Produce json
+// @Param id path string true "Exam ID"
+// @Success 200 {object} entity.ExamAttemptDetail
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /events/{id}/attempt/submit [post]
+func (r *Routes) submitExamAttempt(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx, "id")
+	if err != nil {
+		r.l.Error(err, "http - v1 - submitExamAttempt")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id")
+	}
+
+	userID, err := r.getUserID(ctx)
+	if err != nil {
+		r.l.Error(err, "http - v1 - submitExamAttempt - user")
+		return errorResponse(ctx, http.StatusUnauthorized, "invalid token")
+	}
+
+	detail, err := r.uc.Exam.SubmitAttempt(ctx.UserContext(), userID, id)
+	if err != nil {
+		return r.attemptError(ctx, err, "submitExamAttempt")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(detail)
+}
+
+func (r *Routes) attemptError(ctx *fiber.Ctx, err error, handler string) error {
+	switch {
+	case errors.Is(err, exam.ErrInvalidAnswer):
+		return errorResponse(ctx, http.StatusBadRequest, err.Error())
+	case errors.Is(err, exam.ErrNotRegistered):
+		return errorResponse(ctx, http.StatusForbidden, err.Error())
+	case errors.Is(err, exam.ErrNotFound),
+		errors.Is(err, exam.ErrNoAttempt),
+		errors.Is(err, exam.ErrQuestionNotFound):
+		return errorResponse(ctx, http.StatusNotFound, err.Error())
+	case errors.Is(err, exam.ErrNotOpen),
+		errors.Is(err, exam.ErrNoQuestions),
+		errors.Is(err, exam.ErrAttemptClosed),
+		errors.Is(err, exam.ErrTimeUp):
+		return errorResponse(ctx, http.StatusConflict, err.Error())
+	default:
+		r.l.Error(err, "http - v1 - "+handler+" - usecase")
+		return errorResponse(ctx, http.StatusInternalServerError, "unable to process attempt")
+	}
+}
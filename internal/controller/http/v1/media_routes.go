@@ -0,0 +1,50 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	mediausecase "github.com/evrone/go-clean-template/internal/usecase/media"
+)
+
+func registerMediaRoutes(api fiber.Router, r *Routes) {
+	api.Get("/*", r.getMedia)
+}
+
+// @Summary Download media
+// @Description Serves a question image or thumbnail from a signed URL, as found in question media. No token is needed; the signature is checked instead.
+// @Tags App: Media
+// @Produce png,jpeg,gif
+// @Param key path string true "Media key"
+// @Param expires query int true "Expiry, Unix seconds"
+// @Param signature query string true "URL signature"
+// @Success 200 {file} binary
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /media/{key} [get]
+func (r *Routes) getMedia(ctx *fiber.Ctx) error {
+	expires, err := strconv.ParseInt(ctx.Query("expires"), 10, 64)
+	if err != nil {
+		return errorResponse(ctx, http.StatusNotFound, mediausecase.ErrNotFound.Error())
+	}
+
+	file, contentType, err := r.uc.Media.Open(ctx.UserContext(), ctx.Params("*"), expires, ctx.Query("signature"))
+	if errors.Is(err, mediausecase.ErrNotFound) {
+		return errorResponse(ctx, http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		r.l.Error(err, "http - v1 - getMedia - usecase")
+		return errorResponse(ctx, http.StatusInternalServerError, "unable to load media")
+	}
+
+	maxAge := max(0, expires-time.Now().Unix())
+	ctx.Set(fiber.HeaderCacheControl, "private, max-age="+strconv.FormatInt(maxAge, 10))
+	ctx.Set(fiber.HeaderContentType, contentType)
+
+	// The stream is closed once sent.
+	return ctx.Status(http.StatusOK).SendStream(file)
+}
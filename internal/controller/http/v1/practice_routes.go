@@ -1,9 +1,13 @@
 package v1
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase/practice"
+	"github.com/evrone/go-clean-template/internal/usecase/revision"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -12,10 +16,17 @@ func registerPracticeRoutes(api fiber.Router, r *Routes) {
 	api.Get("/sessions", r.listPracticeSessions)
 	api.Get("/sessions/:id", r.getPracticeSession)
 	api.Post("/sessions/:id/answers", r.answerPracticeQuestion)
+	api.Post("/sessions/:id/complete", r.completePracticeSession)
+	api.Post("/sessions/:id/abandon", r.abandonPracticeSession)
+	api.Get("/sessions/:id/result", r.practiceSessionResult)
 }
 
 func registerPracticeRevisionRoutes(api fiber.Router, r *Routes) {
 	api.Get("/queue", r.revisionQueue)
+	api.Get("/forecast", r.revisionForecast)
+	api.Get("/daily-cap", r.revisionDailyCap)
+	api.Put("/daily-cap", r.setRevisionDailyCap)
+	api.Post("/:id/review", r.reviewRevisionItem)
 }
 
 // @Summary Create new practice session
@@ -27,6 +38,7 @@ func registerPracticeRevisionRoutes(api fiber.Router, r *Routes) {
 // @Success 201 {object} entity.PracticeSession
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /practice/sessions [post]
 func (r *Routes) createPracticeSession(ctx *fiber.Ctx) error {
@@ -48,10 +60,21 @@ func (r *Routes) createPracticeSession(ctx *fiber.Ctx) error {
 		return errorResponse(ctx, http.StatusUnauthorized, "missing user")
 	}
 
+	if payload.Exam == "" {
+		payload.Exam = r.getUserExam(ctx)
+	}
+
 	session, err := r.uc.Practice.CreateSession(ctx.UserContext(), userID, payload)
 	if err != nil {
-		r.l.Error(err, "http - v1 - createPracticeSession - usecase")
-		return errorResponse(ctx, http.StatusInternalServerError, "unable to create session")
+		switch {
+		case errors.Is(err, practice.ErrExamRequired):
+			return errorResponse(ctx, http.StatusBadRequest, err.Error())
+		case errors.Is(err, practice.ErrNoQuestions):
+			return errorResponse(ctx, http.StatusUnprocessableEntity, err.Error())
+		default:
+			r.l.Error(err, "http - v1 - createPracticeSession - usecase")
+			return errorResponse(ctx, http.StatusInternalServerError, "unable to create session")
+		}
 	}
 
 	return ctx.Status(http.StatusCreated).JSON(session)
@@ -89,6 +112,8 @@ func (r *Routes) listPracticeSessions(ctx *fiber.Ctx) error {
 // @Success 200 {object} entity.PracticeSessionDetail
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /practice/sessions/{id} [get]
 func (r *Routes) getPracticeSession(ctx *fiber.Ctx) error {
@@ -106,8 +131,15 @@ func (r *Routes) getPracticeSession(ctx *fiber.Ctx) error {
 
 	detail, err := r.uc.Practice.GetSessionDetail(ctx.UserContext(), sessionID, userID)
 	if err != nil {
-		r.l.Error(err, "http - v1 - getPracticeSession - usecase")
-		return errorResponse(ctx, http.StatusInternalServerError, "unable to load session")
+		switch {
+		case errors.Is(err, practice.ErrNotFound):
+			return errorResponse(ctx, http.StatusNotFound, err.Error())
+		case errors.Is(err, practice.ErrForbidden):
+			return errorResponse(ctx, http.StatusForbidden, err.Error())
+		default:
+			r.l.Error(err, "http - v1 - getPracticeSession - usecase")
+			return errorResponse(ctx, http.StatusInternalServerError, "unable to load session")
+		}
 	}
 
 	return ctx.Status(http.StatusOK).JSON(detail)
@@ -123,6 +155,9 @@ func (r *Routes) getPracticeSession(ctx *fiber.Ctx) error {
 // @Success 200 {object} entity.PracticeSessionQuestion
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /practice/sessions/{id}/answers [post]
 func (r *Routes) answerPracticeQuestion(ctx *fiber.Ctx) error {
@@ -151,13 +186,153 @@ func (r *Routes) answerPracticeQuestion(ctx *fiber.Ctx) error {
 
 	question, err := r.uc.Practice.AnswerQuestion(ctx.UserContext(), sessionID, payload, userID)
 	if err != nil {
-		r.l.Error(err, "http - v1 - answerPracticeQuestion - usecase")
-		return errorResponse(ctx, http.StatusInternalServerError, "unable to record answer")
+		switch {
+		case errors.Is(err, practice.ErrNotFound):
+			return errorResponse(ctx, http.StatusNotFound, err.Error())
+		case errors.Is(err, practice.ErrForbidden):
+			return errorResponse(ctx, http.StatusForbidden, err.Error())
+		case errors.Is(err, practice.ErrInvalidAnswer):
+			return errorResponse(ctx, http.StatusBadRequest, err.Error())
+		case errors.Is(err, practice.ErrSessionClosed), errors.Is(err, practice.ErrAlreadyAnswered):
+			return errorResponse(ctx, http.StatusConflict, err.Error())
+		default:
+			r.l.Error(err, "http - v1 - answerPracticeQuestion - usecase")
+			return errorResponse(ctx, http.StatusInternalServerError, "unable to record answer")
+		}
 	}
 
 	return ctx.Status(http.StatusOK).JSON(question)
 }
 
+// @Summary Complete practice session
+// @Tags App: Practice
+// @Security UserAuth
+// @Produce json
+// @Param id path string true "Session ID"
+// @Success 200 {object} entity.PracticeSessionResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /practice/sessions/{id}/complete [post]
+func (r *Routes) completePracticeSession(ctx *fiber.Ctx) error {
+	sessionID, err := parseUUID(ctx, "id")
+	if err != nil {
+		r.l.Error(err, "http - v1 - completePracticeSession")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid session id")
+	}
+
+	userID, err := r.getUserID(ctx)
+	if err != nil {
+		r.l.Error(err, "http - v1 - completePracticeSession - user")
+		return errorResponse(ctx, http.StatusUnauthorized, "missing user")
+	}
+
+	result, err := r.uc.Practice.CompleteSession(ctx.UserContext(), sessionID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, practice.ErrNotFound):
+			return errorResponse(ctx, http.StatusNotFound, err.Error())
+		case errors.Is(err, practice.ErrForbidden):
+			return errorResponse(ctx, http.StatusForbidden, err.Error())
+		case errors.Is(err, practice.ErrSessionClosed):
+			return errorResponse(ctx, http.StatusConflict, err.Error())
+		default:
+			r.l.Error(err, "http - v1 - completePracticeSession - usecase")
+			return errorResponse(ctx, http.StatusInternalServerError, "unable to complete session")
+		}
+	}
+
+	return ctx.Status(http.StatusOK).JSON(result)
+}
+
+// @Summary Abandon practice session
+// @Tags App: Practice
+// @Security UserAuth
+// @Produce json
+// @Param id path string true "Session ID"
+// @Success 200 {object} entity.PracticeSessionResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /practice/sessions/{id}/abandon [post]
+func (r *Routes) abandonPracticeSession(ctx *fiber.Ctx) error {
+	sessionID, err := parseUUID(ctx, "id")
+	if err != nil {
+		r.l.Error(err, "http - v1 - abandonPracticeSession")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid session id")
+	}
+
+	userID, err := r.getUserID(ctx)
+	if err != nil {
+		r.l.Error(err, "http - v1 - abandonPracticeSession - user")
+		return errorResponse(ctx, http.StatusUnauthorized, "missing user")
+	}
+
+	result, err := r.uc.Practice.AbandonSession(ctx.UserContext(), sessionID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, practice.ErrNotFound):
+			return errorResponse(ctx, http.StatusNotFound, err.Error())
+		case errors.Is(err, practice.ErrForbidden):
+			return errorResponse(ctx, http.StatusForbidden, err.Error())
+		case errors.Is(err, practice.ErrSessionClosed):
+			return errorResponse(ctx, http.StatusConflict, err.Error())
+		default:
+			r.l.Error(err, "http - v1 - abandonPracticeSession - usecase")
+			return errorResponse(ctx, http.StatusInternalServerError, "unable to abandon session")
+		}
+	}
+
+	return ctx.Status(http.StatusOK).JSON(result)
+}
+
+// @Summary Get practice session result summary
+// @Tags App: Practice
+// @Security UserAuth
+// @Produce json
+// @Param id path string true "Session ID"
+// @Success 200 {object} entity.PracticeSessionResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /practice/sessions/{id}/result [get]
+func (r *Routes) practiceSessionResult(ctx *fiber.Ctx) error {
+	sessionID, err := parseUUID(ctx, "id")
+	if err != nil {
+		r.l.Error(err, "http - v1 - practiceSessionResult")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid session id")
+	}
+
+	userID, err := r.getUserID(ctx)
+	if err != nil {
+		r.l.Error(err, "http - v1 - practiceSessionResult - user")
+		return errorResponse(ctx, http.StatusUnauthorized, "missing user")
+	}
+
+	result, err := r.uc.Practice.GetSessionResult(ctx.UserContext(), sessionID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, practice.ErrNotFound):
+			return errorResponse(ctx, http.StatusNotFound, err.Error())
+		case errors.Is(err, practice.ErrForbidden):
+			return errorResponse(ctx, http.StatusForbidden, err.Error())
+		default:
+			r.l.Error(err, "http - v1 - practiceSessionResult - usecase")
+			return errorResponse(ctx, http.StatusInternalServerError, "unable to load result")
+		}
+	}
+
+	return ctx.Status(http.StatusOK).JSON(result)
+}
+
 // @Summary Get revision queue
 // @Tags App: Revision
 // @Security UserAuth
@@ -181,3 +356,166 @@ func (r *Routes) revisionQueue(ctx *fiber.Ctx) error {
 
 	return ctx.Status(http.StatusOK).JSON(items)
 }
+
+// @Summary Forecast revision workload
+// @Tags App: Revision
+// @Security UserAuth
+// @Produce json
+// @Param days query int false "Number of days, today included (default 14, max 90)"
+// @Success 200 {array} entity.RevisionForecastDay
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /revision/forecast [get]
+func (r *Routes) revisionForecast(ctx *fiber.Ctx) error {
+	userID, err := r.getUserID(ctx)
+	if err != nil {
+		r.l.Error(err, "http - v1 - revisionForecast")
+		return errorResponse(ctx, http.StatusUnauthorized, "missing user")
+	}
+
+	var days int
+	if raw := ctx.Query("days"); raw != "" {
+		days, err = strconv.Atoi(raw)
+		if err != nil || days <= 0 {
+			return errorResponse(ctx, http.StatusBadRequest, "invalid days")
+		}
+	}
+
+	forecast, err := r.uc.Revision.Forecast(ctx.UserContext(), userID, days)
+	if err != nil {
+		r.l.Error(err, "http - v1 - revisionForecast - usecase")
+		return errorResponse(ctx, http.StatusInternalServerError, "unable to load revision forecast")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(forecast)
+}
+
+// @Summary Get revision daily cap
+// @Description The learner's own daily cap, null while the global one applies.
+// @Tags App: Revision
+// @Security UserAuth
+// @Produce json
+// @Success 200 {object} entity.RevisionDailyCap
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /revision/daily-cap [get]
+func (r *Routes) revisionDailyCap(ctx *fiber.Ctx) error {
+	userID, err := r.getUserID(ctx)
+	if err != nil {
+		r.l.Error(err, "http - v1 - revisionDailyCap")
+		return errorResponse(ctx, http.StatusUnauthorized, "missing user")
+	}
+
+	dailyCap, err := r.uc.Revision.GetDailyCap(ctx.UserContext(), userID)
+	if err != nil {
+		r.l.Error(err, "http - v1 - revisionDailyCap - usecase")
+		return errorResponse(ctx, http.StatusInternalServerError, "unable to load revision daily cap")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(dailyCap)
+}
+
+// @Summary Set revision daily cap
+// @Description Limits the learner's reviews per UTC day. A null dailyCap restores the global cap.
+// @Tags App: Revision
+// @Security UserAuth
+// @Accept json
+// @Produce json
+// @Param request body entity.RevisionDailyCapRequest true "Daily cap"
+// @Success 200 {object} entity.RevisionDailyCap
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /revision/daily-cap [put]
+func (r *Routes) setRevisionDailyCap(ctx *fiber.Ctx) error {
+	var payload entity.RevisionDailyCapRequest
+	if err := ctx.BodyParser(&payload); err != nil {
+		r.l.Error(err, "http - v1 - setRevisionDailyCap - parse")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	if err := r.v.Struct(payload); err != nil {
+		r.l.Error(err, "http - v1 - setRevisionDailyCap - validation")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	userID, err := r.getUserID(ctx)
+	if err != nil {
+		r.l.Error(err, "http - v1 - setRevisionDailyCap - user")
+		return errorResponse(ctx, http.StatusUnauthorized, "missing user")
+	}
+
+	dailyCap, err := r.uc.Revision.SetDailyCap(ctx.UserContext(), userID, payload.DailyCap)
+	if err != nil {
+		if errors.Is(err, revision.ErrUserNotFound) {
+			return errorResponse(ctx, http.StatusNotFound, err.Error())
+		}
+
+		r.l.Error(err, "http - v1 - setRevisionDailyCap - usecase")
+		return errorResponse(ctx, http.StatusInternalServerError, "unable to update revision daily cap")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(dailyCap)
+}
+
+// @Summary Review revision item
+// @Tags App: Revision
+// @Security UserAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Revision item ID"
+// @Param request body entity.RevisionReviewRequest true "Review outcome"
+// @Success 200 {object} entity.RevisionItem
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /revision/{id}/review [post]
+func (r *Routes) reviewRevisionItem(ctx *fiber.Ctx) error {
+	itemID, err := parseUUID(ctx, "id")
+	if err != nil {
+		r.l.Error(err, "http - v1 - reviewRevisionItem")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid revision item id")
+	}
+
+	var payload entity.RevisionReviewRequest
+	if err := ctx.BodyParser(&payload); err != nil {
+		r.l.Error(err, "http - v1 - reviewRevisionItem - parse")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	if err := r.v.Struct(payload); err != nil {
+		r.l.Error(err, "http - v1 - reviewRevisionItem - validation")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	userID, err := r.getUserID(ctx)
+	if err != nil {
+		r.l.Error(err, "http - v1 - reviewRevisionItem - user")
+		return errorResponse(ctx, http.StatusUnauthorized, "missing user")
+	}
+
+	item, err := r.uc.Revision.Review(ctx.UserContext(), userID, itemID, payload.Result)
+	if err != nil {
+		switch {
+		case errors.Is(err, revision.ErrNotFound):
+			return errorResponse(ctx, http.StatusNotFound, err.Error())
+		case errors.Is(err, revision.ErrForbidden):
+			return errorResponse(ctx, http.StatusForbidden, err.Error())
+		case errors.Is(err, revision.ErrDisabled):
+			return errorResponse(ctx, http.StatusConflict, err.Error())
+		case errors.Is(err, revision.ErrDailyCapReached):
+			return errorResponse(ctx, http.StatusTooManyRequests, err.Error())
+		default:
+			r.l.Error(err, "http - v1 - reviewRevisionItem - usecase")
+			return errorResponse(ctx, http.StatusInternalServerError, "unable to record review")
+		}
+	}
+
+	return ctx.Status(http.StatusOK).JSON(item)
+}
@@ -0,0 +1,141 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	notificationusecase "github.com/evrone/go-clean-template/internal/usecase/notification"
+	reportusecase "github.com/evrone/go-clean-template/internal/usecase/report"
+)
+
+func registerQuestionReportRoutes(api fiber.Router, r *Routes) {
+	api.Post("/questions/:id/report", r.reportQuestion)
+	api.Get("/notifications", r.notifications)
+	api.Post("/notifications/:id/read", r.readNotification)
+}
+
+// @Summary Report a question
+// @Description Flags an error in a question for the editors. One open report per question; the number of reports per hour is limited. The outcome arrives as a notification.
+// @Tags App: Questions
+// @Security UserAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Question ID"
+// @Param request body entity.QuestionReportRequest true "Report"
+// @Success 201 {object} entity.QuestionReport
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /questions/{id}/report [post]
+func (r *Routes) reportQuestion(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx, "id")
+	if err != nil {
+		r.l.Error(err, "http - v1 - reportQuestion")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id")
+	}
+
+	var payload entity.QuestionReportRequest
+	if err := ctx.BodyParser(&payload); err != nil {
+		r.l.Error(err, "http - v1 - reportQuestion - parse")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	if err := r.v.Struct(payload); err != nil {
+		r.l.Error(err, "http - v1 - reportQuestion - validation")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	userID, err := r.getUserID(ctx)
+	if err != nil {
+		r.l.Error(err, "http - v1 - reportQuestion - user")
+		return errorResponse(ctx, http.StatusUnauthorized, "invalid token")
+	}
+
+	report, err := r.uc.Report.Report(ctx.UserContext(), userID, id, payload)
+	if err != nil {
+		switch {
+		case errors.Is(err, reportusecase.ErrQuestionNotFound):
+			return errorResponse(ctx, http.StatusNotFound, err.Error())
+		case errors.Is(err, reportusecase.ErrAlreadyReported):
+			return errorResponse(ctx, http.StatusConflict, err.Error())
+		case errors.Is(err, reportusecase.ErrRateLimited):
+			return errorResponse(ctx, http.StatusTooManyRequests, err.Error())
+		}
+		r.l.Error(err, "http - v1 - reportQuestion - usecase")
+		return errorResponse(ctx, http.StatusInternalServerError, "unable to report question")
+	}
+
+	return ctx.Status(http.StatusCreated).JSON(report)
+}
+
+// @Summary List notifications
+// @Tags App: Notifications
+// @Security UserAuth
+// @Produce json
+// @Param unread query bool false "Only unread notifications"
+// @Param limit query int false "Max entries, at most 200" default(50)
+// @Success 200 {array} entity.Notification
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /notifications [get]
+func (r *Routes) notifications(ctx *fiber.Ctx) error {
+	unread, err := parseQueryBool(ctx, "unread")
+	if err != nil {
+		return errorResponse(ctx, http.StatusBadRequest, "invalid unread")
+	}
+
+	userID, err := r.getUserID(ctx)
+	if err != nil {
+		r.l.Error(err, "http - v1 - notifications - user")
+		return errorResponse(ctx, http.StatusUnauthorized, "invalid token")
+	}
+
+	notifications, err := r.uc.Notification.List(ctx.UserContext(), userID, unread != nil && *unread, parseQueryInt(ctx, "limit", 0))
+	if err != nil {
+		r.l.Error(err, "http - v1 - notifications - usecase")
+		return errorResponse(ctx, http.StatusInternalServerError, "unable to load notifications")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(notifications)
+}
+
+// @Summary Mark notification read
+// @Tags App: Notifications
+// @Security UserAuth
+// @Param id path string true "Notification ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /notifications/{id}/read [post]
+func (r *Routes) readNotification(ctx *fiber.Ctx) error {
+	id, err := parseUUID(ctx, "id")
+	if err != nil {
+		r.l.Error(err, "http - v1 - readNotification")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid id")
+	}
+
+	userID, err := r.getUserID(ctx)
+	if err != nil {
+		r.l.Error(err, "http - v1 - readNotification - user")
+		return errorResponse(ctx, http.StatusUnauthorized, "invalid token")
+	}
+
+	if err := r.uc.Notification.MarkRead(ctx.UserContext(), userID, id); err != nil {
+		if errors.Is(err, notificationusecase.ErrNotFound) {
+			return errorResponse(ctx, http.StatusNotFound, err.Error())
+		}
+		r.l.Error(err, "http - v1 - readNotification - usecase")
+		return errorResponse(ctx, http.StatusInternalServerError, "unable to update notification")
+	}
+
+	return ctx.SendStatus(http.StatusNoContent)
+}
@@ -0,0 +1,11 @@
+package request
+
+import "github.com/evrone/go-clean-template/internal/entity"
+
+// AdminQuestionImportRequest holds the form fields sent with an import file.
+type AdminQuestionImportRequest struct {
+	Exam          entity.ExamCategory         `form:"exam" validate:"required"`
+	Format        entity.QuestionImportFormat `form:"format" validate:"omitempty,oneof=csv ndjson xlsx"`
+	CreateMissing bool                        `form:"createMissing"`
+	DryRun        bool                        `form:"dryRun"`
+}
@@ -0,0 +1,9 @@
+package request
+
+import "github.com/evrone/go-clean-template/internal/entity"
+
+// AdminQuestionMediaRequest holds the form fields sent with a question image.
+type AdminQuestionMediaRequest struct {
+	Slot    entity.QuestionMediaSlot `form:"slot" validate:"required,oneof=stem option_a option_b option_c option_d explanation"`
+	AltText string                   `form:"altText" validate:"max=500"`
+}
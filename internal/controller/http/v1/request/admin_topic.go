@@ -4,8 +4,10 @@ import (
 	"github.com/google/uuid"
 )
 
-// AdminTopicCreateRequest is payload for creating topics.
+// AdminTopicCreateRequest is payload for creating topics. ParentID makes the
+// topic a subtopic of another topic in the same subject.
 type AdminTopicCreateRequest struct {
-	SubjectID uuid.UUID `json:"subjectId" validate:"required"`
-	Name      string    `json:"name" validate:"required"`
+	SubjectID uuid.UUID  `json:"subjectId" validate:"required"`
+	ParentID  *uuid.UUID `json:"parentId,omitempty"`
+	Name      string     `json:"name" validate:"required"`
 }
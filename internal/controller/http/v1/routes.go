@@ -2,12 +2,15 @@ package v1
 
 import (
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 
 	"github.com/evrone/go-clean-template/internal/controller/http/middleware"
+	"github.com/evrone/go-clean-template/internal/entity"
 	"github.com/evrone/go-clean-template/internal/usecase"
 	"github.com/evrone/go-clean-template/pkg/jwt"
 	"github.com/evrone/go-clean-template/pkg/logger"
@@ -31,11 +34,16 @@ func RegisterRoutes(api fiber.Router, uc usecase.UseCases, userJWT, adminJWT *jw
 	adminAuthGroup.Use(middleware.AdminAuth(adminJWT))
 	registerAdminAuthRoutes(adminAuthGroup, r)
 
+	// Media links carry their own signature, so they are served without a
+	// token. Registered before userGroup, whose middleware covers every path.
+	registerMediaRoutes(api.Group("/media"), r)
+
 	userGroup := api.Group("/")
 	userGroup.Use(middleware.UserAuth(userJWT))
 	registerUserRoutes(userGroup, r)
 	registerLeaderboardRoutes(userGroup, r)
 	registerFeedRoutes(userGroup, r)
+	registerQuestionReportRoutes(userGroup, r)
 
 	practiceGroup := api.Group("/practice")
 	practiceGroup.Use(middleware.UserAuth(userJWT))
@@ -59,8 +67,11 @@ func RegisterRoutes(api fiber.Router, uc usecase.UseCases, userJWT, adminJWT *jw
 	adminGroup.Use(middleware.AdminAuth(adminJWT))
 
 	registerAdminQuestionRoutes(adminGroup.Group("/questions"), r)
+	registerAdminQuestionReportRoutes(adminGroup.Group("/question-reports"), r)
+	registerAdminTagRoutes(adminGroup.Group("/tags"), r)
 	registerAdminSubjectsTopicsRoutes(adminGroup, r)
 	registerAdminExamsRoutes(adminGroup.Group("/exams"), r)
+	registerAdminExamCategoriesRoutes(adminGroup.Group("/exam-categories"), r)
 	registerAdminPodcastsRoutes(adminGroup.Group("/podcasts"), r)
 	registerAdminCouponsRoutes(adminGroup.Group("/coupons"), r)
 	registerAdminAISettingsRoutes(adminGroup.Group("/ai-settings"), r)
@@ -84,6 +95,12 @@ func (r *Routes) getUserID(ctx *fiber.Ctx) (uuid.UUID, error) {
 	return uuid.Parse(str)
 }
 
+// getUserExam returns the primary exam carried by the user's token, if any.
+func (r *Routes) getUserExam(ctx *fiber.Ctx) entity.ExamCategory {
+	exam, _ := ctx.Locals("exam").(entity.ExamCategory)
+	return exam
+}
+
 func parseUUID(ctx *fiber.Ctx, key string) (uuid.UUID, error) {
 	value := ctx.Params(key)
 	if value == "" {
@@ -106,3 +123,33 @@ func parseQueryUUID(ctx *fiber.Ctx, key string) (*uuid.UUID, error) {
 
 	return &parsed, nil
 }
+
+func parseQueryBool(ctx *fiber.Ctx, key string) (*bool, error) {
+	value := ctx.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &parsed, nil
+}
+
+// parseQueryTime reads an RFC 3339 time or a YYYY-MM-DD date (midnight UTC).
+func parseQueryTime(ctx *fiber.Ctx, key string) (*time.Time, error) {
+	value := ctx.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return &parsed, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%q is neither RFC 3339 nor YYYY-MM-DD", value)
+}
@@ -9,6 +9,8 @@ import (
 
 func registerUserRoutes(api fiber.Router, r *Routes) {
 	api.Get("/me", r.me)
+	api.Get("/me/language", r.meLanguage)
+	api.Put("/me/language", r.setMeLanguage)
 	api.Get("/subjects", r.subjects)
 	api.Get("/topics", r.topics)
 }
@@ -37,6 +39,69 @@ func (r *Routes) me(ctx *fiber.Ctx) error {
 	return ctx.Status(http.StatusOK).JSON(result)
 }
 
+// @Summary Get preferred language
+// @Description The language questions are served in, and the available ones.
+// @Tags App: User
+// @Security UserAuth
+// @Produce json
+// @Success 200 {object} entity.LanguagePreference
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /me/language [get]
+func (r *Routes) meLanguage(ctx *fiber.Ctx) error {
+	userID, err := r.getUserID(ctx)
+	if err != nil {
+		r.l.Error(err, "http - v1 - meLanguage - user")
+		return errorResponse(ctx, http.StatusUnauthorized, "invalid token")
+	}
+
+	preference, err := r.uc.Localization.Preference(ctx.UserContext(), userID)
+	if err != nil {
+		return r.localizationError(ctx, err, "meLanguage")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(preference)
+}
+
+// @Summary Set preferred language
+// @Description Practice questions are served in this language where a translation exists, otherwise in English.
+// @Tags App: User
+// @Security UserAuth
+// @Accept json
+// @Produce json
+// @Param request body entity.LanguagePreferenceRequest true "Language code, e.g. hi"
+// @Success 200 {object} entity.LanguagePreference
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /me/language [put]
+func (r *Routes) setMeLanguage(ctx *fiber.Ctx) error {
+	var payload entity.LanguagePreferenceRequest
+	if err := ctx.BodyParser(&payload); err != nil {
+		r.l.Error(err, "http - v1 - setMeLanguage - parse")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	if err := r.v.Struct(payload); err != nil {
+		r.l.Error(err, "http - v1 - setMeLanguage - validation")
+		return errorResponse(ctx, http.StatusBadRequest, "invalid body")
+	}
+
+	userID, err := r.getUserID(ctx)
+	if err != nil {
+		r.l.Error(err, "http - v1 - setMeLanguage - user")
+		return errorResponse(ctx, http.StatusUnauthorized, "invalid token")
+	}
+
+	preference, err := r.uc.Localization.SetPreference(ctx.UserContext(), userID, payload.Language)
+	if err != nil {
+		return r.localizationError(ctx, err, "setMeLanguage")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(preference)
+}
+
 // @Summary List subjects for current exam
 // @Tags App: User
 // @Security UserAuth
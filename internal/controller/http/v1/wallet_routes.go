@@ -81,6 +81,7 @@ func (r *Routes) walletTransactions(ctx *fiber.Ctx) error {
 // @Success 200 {object} entity.WalletSummary
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /coupons/redeem [post]
 func (r *Routes) redeemCoupon(ctx *fiber.Ctx) error {
@@ -103,8 +104,7 @@ func (r *Routes) redeemCoupon(ctx *fiber.Ctx) error {
 
 	summary, err := r.uc.Coupon.Redeem(ctx.UserContext(), userID, payload)
 	if err != nil {
-		r.l.Error(err, "http - v1 - redeemCoupon - usecase")
-		return errorResponse(ctx, http.StatusInternalServerError, "unable to redeem")
+		return r.couponError(ctx, err, "redeemCoupon", "unable to redeem coupon")
 	}
 
 	return ctx.Status(http.StatusOK).JSON(summary)
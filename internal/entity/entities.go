@@ -25,12 +25,40 @@ const (
 	UserRoleSuperAdmin UserRole = "SUPER_ADMIN"
 )
 
-// QuestionChoiceType determines single/multi choice.
+// QuestionChoiceType determines how a question is answered and graded.
 type QuestionChoiceType string
 
 const (
 	ChoiceTypeSingle QuestionChoiceType = "single"
 	ChoiceTypeMulti  QuestionChoiceType = "multi"
+	// ChoiceTypeNumerical takes a number, accepted within NumericTolerance.
+	ChoiceTypeNumerical QuestionChoiceType = "numerical"
+	// ChoiceTypeMatrixMatch pairs each List-I row with one or more List-II columns.
+	ChoiceTypeMatrixMatch QuestionChoiceType = "matrix_match"
+	// ChoiceTypeAssertionReason is a single-choice question over an assertion and a reason.
+	ChoiceTypeAssertionReason QuestionChoiceType = "assertion_reason"
+)
+
+// QuestionStatus is a question's place in the editorial workflow. Only
+// published questions are served to learners.
+type QuestionStatus string
+
+const (
+	QuestionStatusDraft     QuestionStatus = "draft"
+	QuestionStatusInReview  QuestionStatus = "in_review"
+	QuestionStatusApproved  QuestionStatus = "approved"
+	QuestionStatusPublished QuestionStatus = "published"
+	QuestionStatusRetired   QuestionStatus = "retired"
+)
+
+// MultiSelectScoring decides how multi-select answers earn credit.
+type MultiSelectScoring string
+
+const (
+	// MultiSelectAllOrNothing credits only the exact set of correct options.
+	MultiSelectAllOrNothing MultiSelectScoring = "all_or_nothing"
+	// MultiSelectPartial credits each correct option picked, unless a wrong one is picked too.
+	MultiSelectPartial MultiSelectScoring = "partial"
 )
 
 // PracticeMode describes session modes.
@@ -41,6 +69,8 @@ const (
 	PracticeModeCustom   PracticeMode = "custom"
 	PracticeModeRevision PracticeMode = "revision"
 	PracticeModeExam     PracticeMode = "exam"
+	// PracticeModePYQ draws only previous-year questions.
+	PracticeModePYQ PracticeMode = "pyq"
 )
 
 // PracticeSessionStatus defines session lifecycle.
@@ -52,6 +82,27 @@ const (
 	PracticeStatusAbandoned  PracticeSessionStatus = "abandoned"
 )
 
+// SelectionReason explains why a smart session served a question.
+type SelectionReason string
+
+const (
+	SelectionWeakTopic      SelectionReason = "weak_topic"
+	SelectionUnseen         SelectionReason = "unseen"
+	SelectionSpacedReview   SelectionReason = "spaced_review"
+	SelectionDifficultyUp   SelectionReason = "difficulty_up"
+	SelectionDifficultyDown SelectionReason = "difficulty_down"
+	SelectionFill           SelectionReason = "fill"
+)
+
+// AttemptSource tells where a user_question_attempt row came from.
+type AttemptSource string
+
+const (
+	AttemptSourcePractice AttemptSource = "practice"
+	AttemptSourceExam     AttemptSource = "exam"
+	AttemptSourceRevision AttemptSource = "revision"
+)
+
 // ExamConfigType for different exam kinds.
 type ExamConfigType string
 
@@ -78,6 +129,7 @@ type WalletTxType string
 const (
 	WalletTxReward     WalletTxType = "REWARD"
 	WalletTxExamEntry  WalletTxType = "EXAM_ENTRY"
+	WalletTxExamRefund WalletTxType = "EXAM_REFUND"
 	WalletTxCoupon     WalletTxType = "COUPON"
 	WalletTxAdjustment WalletTxType = "ADJUSTMENT"
 	WalletTxReferral   WalletTxType = "REFERRAL"
@@ -102,6 +154,15 @@ const (
 	RevisionResultIncorrect RevisionResult = "INCORRECT"
 )
 
+// RevisionAlgorithm selects the spaced-repetition scheduler.
+type RevisionAlgorithm string
+
+const (
+	RevisionAlgorithmFixed RevisionAlgorithm = "fixed"
+	RevisionAlgorithmSM2   RevisionAlgorithm = "sm2"
+	RevisionAlgorithmFSRS  RevisionAlgorithm = "fsrs"
+)
+
 // User represents a learner or admin.
 type User struct {
 	ID          uuid.UUID    `json:"id"`
@@ -343,18 +404,65 @@ type AdminInviteResponse struct {
 
 // Subject describes a subject.
 type Subject struct {
-	ID       uuid.UUID    `json:"id"`
-	Exam     ExamCategory `json:"exam"`
-	Name     string       `json:"name"`
-	IsActive bool         `json:"isActive"`
+	ID        uuid.UUID    `json:"id"`
+	Exam      ExamCategory `json:"exam"`
+	Name      string       `json:"name"`
+	IsActive  bool         `json:"isActive"`
+	SortOrder int          `json:"sortOrder"`
 }
 
-// Topic describes a topic under a subject.
+// Topic describes a topic under a subject. Subtopics point at their parent
+// topic, which is always in the same subject.
 type Topic struct {
-	ID        uuid.UUID `json:"id"`
-	SubjectID uuid.UUID `json:"subjectId"`
-	Name      string    `json:"name"`
-	IsActive  bool      `json:"isActive"`
+	ID        uuid.UUID  `json:"id"`
+	SubjectID uuid.UUID  `json:"subjectId"`
+	ParentID  *uuid.UUID `json:"parentId,omitempty"`
+	Name      string     `json:"name"`
+	IsActive  bool       `json:"isActive"`
+	SortOrder int        `json:"sortOrder"`
+}
+
+// SubjectUpdateRequest body; nil fields are left unchanged.
+type SubjectUpdateRequest struct {
+	Name     *string `json:"name,omitempty" validate:"omitempty,min=1,max=128"`
+	IsActive *bool   `json:"isActive,omitempty"`
+}
+
+// TopicUpdateRequest body; nil fields are left unchanged. ParentID moves the
+// topic under another topic of the same subject, MakeRoot moves it to the top
+// level. Deactivating a topic deactivates its subtopics too.
+type TopicUpdateRequest struct {
+	Name     *string    `json:"name,omitempty" validate:"omitempty,min=1,max=128"`
+	ParentID *uuid.UUID `json:"parentId,omitempty"`
+	MakeRoot bool       `json:"makeRoot,omitempty"`
+	IsActive *bool      `json:"isActive,omitempty"`
+}
+
+// SubjectReorderRequest lists every subject of an exam in display order.
+type SubjectReorderRequest struct {
+	Exam ExamCategory `json:"exam" validate:"required"`
+	IDs  []uuid.UUID  `json:"ids" validate:"required,min=1"`
+}
+
+// TopicReorderRequest lists every child of ParentID (or every top-level topic
+// of the subject when nil) in display order.
+type TopicReorderRequest struct {
+	SubjectID uuid.UUID   `json:"subjectId" validate:"required"`
+	ParentID  *uuid.UUID  `json:"parentId,omitempty"`
+	IDs       []uuid.UUID `json:"ids" validate:"required,min=1"`
+}
+
+// TopicMergeRequest names the topic that absorbs the merged one.
+type TopicMergeRequest struct {
+	TargetID uuid.UUID `json:"targetId" validate:"required"`
+}
+
+// TopicMergeResult reports what a merge moved into the target topic.
+type TopicMergeResult struct {
+	Target    Topic `json:"target"`
+	Questions int   `json:"questions"`
+	Podcasts  int   `json:"podcasts"`
+	Subtopics int   `json:"subtopics"`
 }
 
 // LeaderboardEntry represents ranked user data.
@@ -394,95 +502,705 @@ type FeedPost struct {
 
 // Question represents a practice question.
 type Question struct {
-	ID              uuid.UUID          `json:"id"`
-	Exam            ExamCategory       `json:"exam"`
-	SubjectID       uuid.UUID          `json:"subjectId"`
-	TopicID         uuid.UUID          `json:"topicId"`
-	QuestionText    string             `json:"questionText"`
-	OptionA         string             `json:"optionA"`
-	OptionB         string             `json:"optionB"`
-	OptionC         string             `json:"optionC"`
-	OptionD         string             `json:"optionD"`
-	CorrectOption   int                `json:"correctOption"`
-	Explanation     *string            `json:"explanation,omitempty"`
-	DifficultyLevel int                `json:"difficultyLevel"`
-	ChoiceType      QuestionChoiceType `json:"choiceType"`
-	IsClinical      bool               `json:"isClinical"`
-	IsImageBased    bool               `json:"isImageBased"`
-	IsHighYield     bool               `json:"isHighYield"`
-	IsActive        bool               `json:"isActive"`
+	ID               uuid.UUID          `json:"id"`
+	Exam             ExamCategory       `json:"exam"`
+	SubjectID        uuid.UUID          `json:"subjectId"`
+	TopicID          uuid.UUID          `json:"topicId"`
+	QuestionText     string             `json:"questionText"`
+	OptionA          string             `json:"optionA"`
+	OptionB          string             `json:"optionB"`
+	OptionC          string             `json:"optionC"`
+	OptionD          string             `json:"optionD"`
+	CorrectOption    int                `json:"correctOption"`
+	CorrectOptions   []int              `json:"correctOptions"`
+	ReasonText       *string            `json:"reasonText,omitempty"`
+	NumericAnswer    *float64           `json:"numericAnswer,omitempty"`
+	NumericTolerance *float64           `json:"numericTolerance,omitempty"`
+	Matrix           *MatrixMatch       `json:"matrix,omitempty"`
+	Explanation      *string            `json:"explanation,omitempty"`
+	DifficultyLevel  int                `json:"difficultyLevel"`
+	ChoiceType       QuestionChoiceType `json:"choiceType"`
+	IsClinical       bool               `json:"isClinical"`
+	IsImageBased     bool               `json:"isImageBased"`
+	IsHighYield      bool               `json:"isHighYield"`
+	// IsActive is true exactly when Status is published.
+	IsActive bool `json:"isActive"`
+	// Version is the question's current version, or the one a learner was served.
+	Version int `json:"version"`
+	// Status and ReviewerID are the current workflow state. They are not
+	// part of versions, nor of questions served to learners.
+	Status     QuestionStatus `json:"status,omitempty"`
+	ReviewerID *uuid.UUID     `json:"reviewerId,omitempty"`
+	// DifficultyLocked keeps recalibration from changing DifficultyLevel.
+	DifficultyLocked bool `json:"difficultyLocked,omitempty"`
+	// Tags are the names of the question's tags, sorted.
+	Tags []string `json:"tags,omitempty"`
+	// Media lists the attached images. It is only loaded for practice sessions.
+	Media []QuestionMedia `json:"media,omitempty"`
+	// Language is set when a learner is served a translation of the text.
+	Language string `json:"language,omitempty"`
+}
+
+// MatrixMatch describes a matrix-match question. Answer[i] lists the 1-based
+// Columns that match Rows[i].
+type MatrixMatch struct {
+	Rows    []string `json:"rows" validate:"min=2,max=6,dive,required"`
+	Columns []string `json:"columns" validate:"min=2,max=8,dive,required"`
+	Answer  [][]int  `json:"answer"`
+}
+
+// QuestionVersion is an immutable snapshot of a question. One is stored on
+// every create, update and rollback.
+type QuestionVersion struct {
+	QuestionID    uuid.UUID  `json:"questionId"`
+	Version       int        `json:"version"`
+	Question      Question   `json:"question"`
+	ChangedFields []string   `json:"changedFields"`
+	RestoredFrom  *int       `json:"restoredFrom,omitempty"`
+	AuthorID      *uuid.UUID `json:"authorId,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+}
+
+// QuestionFieldChange is a field that differs between two versions.
+type QuestionFieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+// QuestionVersionDiff lists the fields changed from one version to another.
+type QuestionVersionDiff struct {
+	QuestionID uuid.UUID             `json:"questionId"`
+	From       int                   `json:"from"`
+	To         int                   `json:"to"`
+	Changes    []QuestionFieldChange `json:"changes"`
+}
+
+// QuestionSignature is the stored MinHash signature of a question's text, as
+// of Version. BandKeys index it for candidate lookups.
+type QuestionSignature struct {
+	QuestionID   uuid.UUID    `json:"questionId"`
+	Exam         ExamCategory `json:"exam"`
+	Version      int          `json:"version"`
+	QuestionText string       `json:"questionText"`
+	Signature    []byte       `json:"-"`
+	BandKeys     []int64      `json:"-"`
+}
+
+// SimilarQuestion is a likely duplicate of another question, with the
+// estimated similarity of their normalized texts (0 to 1).
+type SimilarQuestion struct {
+	QuestionID   uuid.UUID `json:"questionId"`
+	QuestionText string    `json:"questionText"`
+	Similarity   float64   `json:"similarity"`
+	// Row is set when the match is an earlier row of the same import file.
+	Row int `json:"row,omitempty"`
+}
+
+// QuestionCreateResult is a created question with the existing questions it
+// likely duplicates.
+type QuestionCreateResult struct {
+	Question
+	Duplicates []SimilarQuestion `json:"duplicates"`
+}
+
+// QuestionDuplicateCluster is a group of questions that are likely copies of
+// each other. Similarity is the lowest score among the pairs that joined it.
+type QuestionDuplicateCluster struct {
+	Questions  []SimilarQuestion `json:"questions"`
+	Similarity float64           `json:"similarity"`
+}
+
+// QuestionDuplicateReport lists the duplicate clusters of an exam's bank.
+// Compared counts the questions that shared a band with another and were
+// compared closely. Questions edited since the last signature run are
+// compared on their previous text.
+type QuestionDuplicateReport struct {
+	Exam      ExamCategory               `json:"exam"`
+	Threshold float64                    `json:"threshold"`
+	Compared  int                        `json:"compared"`
+	Clusters  []QuestionDuplicateCluster `json:"clusters"`
+}
+
+// QuestionMediaSlot is the part of a question an image illustrates.
+type QuestionMediaSlot string
+
+const (
+	QuestionMediaStem        QuestionMediaSlot = "stem"
+	QuestionMediaOptionA     QuestionMediaSlot = "option_a"
+	QuestionMediaOptionB     QuestionMediaSlot = "option_b"
+	QuestionMediaOptionC     QuestionMediaSlot = "option_c"
+	QuestionMediaOptionD     QuestionMediaSlot = "option_d"
+	QuestionMediaExplanation QuestionMediaSlot = "explanation"
+)
+
+// QuestionMedia is an image attached to a question. URL and ThumbnailURL are
+// signed download links that expire; Key and ThumbnailKey locate the blobs.
+type QuestionMedia struct {
+	ID           uuid.UUID         `json:"id"`
+	QuestionID   uuid.UUID         `json:"questionId"`
+	Slot         QuestionMediaSlot `json:"slot"`
+	ContentType  string            `json:"contentType"`
+	SizeBytes    int64             `json:"sizeBytes"`
+	Width        int               `json:"width"`
+	Height       int               `json:"height"`
+	AltText      string            `json:"altText,omitempty"`
+	Key          string            `json:"-"`
+	ThumbnailKey string            `json:"-"`
+	URL          string            `json:"url"`
+	ThumbnailURL string            `json:"thumbnailUrl"`
+	CreatedBy    *uuid.UUID        `json:"createdBy,omitempty"`
+	CreatedAt    time.Time         `json:"createdAt"`
+}
+
+// Answer is a learner's response to a question of any type.
+type Answer struct {
+	SelectedOptions []int    `json:"selectedOptions,omitempty"`
+	NumericAnswer   *float64 `json:"numericAnswer,omitempty"`
+	MatrixAnswer    [][]int  `json:"matrixAnswer,omitempty"`
 }
 
 // QuestionCreateRequest body.
 type QuestionCreateRequest struct {
-	Exam            ExamCategory       `json:"exam" validate:"required"`
-	SubjectID       uuid.UUID          `json:"subjectId" validate:"required"`
-	TopicID         uuid.UUID          `json:"topicId" validate:"required"`
-	QuestionText    string             `json:"questionText" validate:"required"`
-	OptionA         string             `json:"optionA" validate:"required"`
-	OptionB         string             `json:"optionB" validate:"required"`
-	OptionC         string             `json:"optionC" validate:"required"`
-	OptionD         string             `json:"optionD" validate:"required"`
-	CorrectOption   int                `json:"correctOption" validate:"required,min=1,max=4"`
-	Explanation     string             `json:"explanation"`
-	DifficultyLevel int                `json:"difficultyLevel"`
-	ChoiceType      QuestionChoiceType `json:"choiceType"`
-	IsClinical      bool               `json:"isClinical"`
-	IsImageBased    bool               `json:"isImageBased"`
-	IsHighYield     bool               `json:"isHighYield"`
-	IsActive        bool               `json:"isActive"`
+	Exam             ExamCategory       `json:"exam" validate:"required"`
+	SubjectID        uuid.UUID          `json:"subjectId" validate:"required"`
+	TopicID          uuid.UUID          `json:"topicId" validate:"required"`
+	QuestionText     string             `json:"questionText" validate:"required"`
+	OptionA          string             `json:"optionA"`
+	OptionB          string             `json:"optionB"`
+	OptionC          string             `json:"optionC"`
+	OptionD          string             `json:"optionD"`
+	CorrectOption    int                `json:"correctOption" validate:"omitempty,min=1,max=4"`
+	CorrectOptions   []int              `json:"correctOptions" validate:"omitempty,max=4,unique,dive,min=1,max=4"`
+	ReasonText       string             `json:"reasonText"`
+	NumericAnswer    *float64           `json:"numericAnswer,omitempty"`
+	NumericTolerance *float64           `json:"numericTolerance,omitempty" validate:"omitempty,min=0"`
+	Matrix           *MatrixMatch       `json:"matrix,omitempty"`
+	Explanation      string             `json:"explanation"`
+	DifficultyLevel  int                `json:"difficultyLevel"`
+	ChoiceType       QuestionChoiceType `json:"choiceType" validate:"omitempty,oneof=single multi numerical matrix_match assertion_reason"`
+	IsClinical       bool               `json:"isClinical"`
+	IsImageBased     bool               `json:"isImageBased"`
+	IsHighYield      bool               `json:"isHighYield"`
 }
 
 // QuestionUpdateRequest body.
 type QuestionUpdateRequest struct {
-	SubjectID       *uuid.UUID          `json:"subjectId,omitempty"`
-	TopicID         *uuid.UUID          `json:"topicId,omitempty"`
-	QuestionText    *string             `json:"questionText,omitempty"`
-	OptionA         *string             `json:"optionA,omitempty"`
-	OptionB         *string             `json:"optionB,omitempty"`
-	OptionC         *string             `json:"optionC,omitempty"`
-	OptionD         *string             `json:"optionD,omitempty"`
-	CorrectOption   *int                `json:"correctOption,omitempty"`
-	Explanation     *string             `json:"explanation,omitempty"`
-	DifficultyLevel *int                `json:"difficultyLevel,omitempty"`
-	ChoiceType      *QuestionChoiceType `json:"choiceType,omitempty"`
-	IsClinical      *bool               `json:"isClinical,omitempty"`
-	IsImageBased    *bool               `json:"isImageBased,omitempty"`
-	IsHighYield     *bool               `json:"isHighYield,omitempty"`
-	IsActive        *bool               `json:"isActive,omitempty"`
+	SubjectID        *uuid.UUID          `json:"subjectId,omitempty"`
+	TopicID          *uuid.UUID          `json:"topicId,omitempty"`
+	QuestionText     *string             `json:"questionText,omitempty"`
+	OptionA          *string             `json:"optionA,omitempty"`
+	OptionB          *string             `json:"optionB,omitempty"`
+	OptionC          *string             `json:"optionC,omitempty"`
+	OptionD          *string             `json:"optionD,omitempty"`
+	CorrectOption    *int                `json:"correctOption,omitempty" validate:"omitempty,min=1,max=4"`
+	CorrectOptions   []int               `json:"correctOptions,omitempty" validate:"omitempty,max=4,unique,dive,min=1,max=4"`
+	ReasonText       *string             `json:"reasonText,omitempty"`
+	NumericAnswer    *float64            `json:"numericAnswer,omitempty"`
+	NumericTolerance *float64            `json:"numericTolerance,omitempty" validate:"omitempty,min=0"`
+	Matrix           *MatrixMatch        `json:"matrix,omitempty"`
+	Explanation      *string             `json:"explanation,omitempty"`
+	DifficultyLevel  *int                `json:"difficultyLevel,omitempty"`
+	ChoiceType       *QuestionChoiceType `json:"choiceType,omitempty" validate:"omitempty,oneof=single multi numerical matrix_match assertion_reason"`
+	IsClinical       *bool               `json:"isClinical,omitempty"`
+	IsImageBased     *bool               `json:"isImageBased,omitempty"`
+	IsHighYield      *bool               `json:"isHighYield,omitempty"`
+	// Version, when set, rejects the update if the question has moved past it.
+	Version *int `json:"version,omitempty"`
+}
+
+// QuestionTransitionRequest moves a question to another workflow state.
+// ReviewerID assigns the reviewer when submitting for review; Version, when
+// set, rejects the move if the question was edited since.
+type QuestionTransitionRequest struct {
+	Status     QuestionStatus `json:"status" validate:"required,oneof=draft in_review approved published retired"`
+	ReviewerID *uuid.UUID     `json:"reviewerId,omitempty"`
+	Version    *int           `json:"version,omitempty"`
+}
+
+// QuestionReviewerRequest assigns the reviewer of a question.
+type QuestionReviewerRequest struct {
+	ReviewerID uuid.UUID `json:"reviewerId" validate:"required"`
+}
+
+// QuestionReviewComment is a review note on a version of a question. Field,
+// when set, anchors it to one field of the question, named as in
+// QuestionFieldChange.
+type QuestionReviewComment struct {
+	ID         uuid.UUID  `json:"id"`
+	QuestionID uuid.UUID  `json:"questionId"`
+	Version    int        `json:"version"`
+	Field      string     `json:"field,omitempty"`
+	Body       string     `json:"body"`
+	AuthorID   uuid.UUID  `json:"authorId"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ResolvedBy *uuid.UUID `json:"resolvedBy,omitempty"`
+	ResolvedAt *time.Time `json:"resolvedAt,omitempty"`
+}
+
+// QuestionReviewCommentRequest body.
+type QuestionReviewCommentRequest struct {
+	Field string `json:"field"`
+	Body  string `json:"body" validate:"required,max=4000"`
+}
+
+// QuestionReportReason says what a learner thinks is wrong with a question.
+type QuestionReportReason string
+
+const (
+	QuestionReportWrongAnswer      QuestionReportReason = "wrong_answer"
+	QuestionReportWrongExplanation QuestionReportReason = "wrong_explanation"
+	QuestionReportTypo             QuestionReportReason = "typo"
+	QuestionReportUnclear          QuestionReportReason = "unclear"
+	QuestionReportOutdated         QuestionReportReason = "outdated"
+	QuestionReportOther            QuestionReportReason = "other"
+)
+
+// QuestionReportStatus is where a report is in triage.
+type QuestionReportStatus string
+
+const (
+	QuestionReportOpen      QuestionReportStatus = "open"
+	QuestionReportResolved  QuestionReportStatus = "resolved"
+	QuestionReportDismissed QuestionReportStatus = "dismissed"
+)
+
+// QuestionReport is a learner's report of an error in a question, against
+// the version they were shown.
+type QuestionReport struct {
+	ID              uuid.UUID            `json:"id"`
+	QuestionID      uuid.UUID            `json:"questionId"`
+	QuestionVersion int                  `json:"questionVersion"`
+	UserID          uuid.UUID            `json:"userId"`
+	Reason          QuestionReportReason `json:"reason"`
+	Details         string               `json:"details,omitempty"`
+	Status          QuestionReportStatus `json:"status"`
+	ResolutionNote  string               `json:"resolutionNote,omitempty"`
+	ResolvedBy      *uuid.UUID           `json:"resolvedBy,omitempty"`
+	ResolvedAt      *time.Time           `json:"resolvedAt,omitempty"`
+	CreatedAt       time.Time            `json:"createdAt"`
+}
+
+// QuestionReportRequest body.
+type QuestionReportRequest struct {
+	Reason  QuestionReportReason `json:"reason" validate:"required,oneof=wrong_answer wrong_explanation typo unclear outdated other"`
+	Details string               `json:"details" validate:"max=2000"`
+}
+
+// QuestionReportGroup summarizes the reports of one question in the triage
+// queue.
+type QuestionReportGroup struct {
+	QuestionID      uuid.UUID                    `json:"questionId"`
+	QuestionText    string                       `json:"questionText"`
+	QuestionStatus  QuestionStatus               `json:"questionStatus"`
+	Reports         int                          `json:"reports"`
+	Reasons         map[QuestionReportReason]int `json:"reasons"`
+	FirstReportedAt time.Time                    `json:"firstReportedAt"`
+	LastReportedAt  time.Time                    `json:"lastReportedAt"`
+}
+
+// QuestionReportGroupList envelope for the triage queue.
+type QuestionReportGroupList struct {
+	Items []QuestionReportGroup `json:"items"`
+	Meta  AdminUsersMeta        `json:"meta"`
+}
+
+// QuestionReportResolveRequest closes the open reports of a question.
+// Note is shown to the reporters.
+type QuestionReportResolveRequest struct {
+	Status QuestionReportStatus `json:"status" validate:"required,oneof=resolved dismissed"`
+	Note   string               `json:"note" validate:"max=2000"`
+}
+
+// QuestionReportResolution is the outcome of closing a question's reports.
+type QuestionReportResolution struct {
+	QuestionID uuid.UUID            `json:"questionId"`
+	Status     QuestionReportStatus `json:"status"`
+	Closed     int                  `json:"closed"`
+}
+
+// Notification kinds.
+const (
+	NotificationReportResolved  = "question_report_resolved"
+	NotificationReportDismissed = "question_report_dismissed"
+)
+
+// Notification is an in-app message to a user. Data carries the IDs a
+// client needs to link to what it is about.
+type Notification struct {
+	ID        uuid.UUID         `json:"id"`
+	UserID    uuid.UUID         `json:"userId"`
+	Kind      string            `json:"kind"`
+	Title     string            `json:"title"`
+	Body      string            `json:"body"`
+	Data      map[string]string `json:"data,omitempty"`
+	CreatedAt time.Time         `json:"createdAt"`
+	ReadAt    *time.Time        `json:"readAt,omitempty"`
+}
+
+// ItemFlag marks a question whose attempt statistics suggest a problem.
+type ItemFlag string
+
+const (
+	ItemFlagTooEasy                 ItemFlag = "too_easy"
+	ItemFlagTooHard                 ItemFlag = "too_hard"
+	ItemFlagLowDiscrimination       ItemFlag = "low_discrimination"
+	ItemFlagNegativeDiscrimination  ItemFlag = "negative_discrimination"
+	ItemFlagPossibleWrongKey        ItemFlag = "possible_wrong_key"
+	ItemFlagNonFunctionalDistractor ItemFlag = "nonfunctional_distractor"
+)
+
+// OptionAnalysis is how often an option was chosen and how well choosing it
+// tracks ability. Discrimination is nil when it cannot be computed.
+type OptionAnalysis struct {
+	Option         int      `json:"option"`
+	IsKey          bool     `json:"isKey"`
+	Rate           float64  `json:"rate"`
+	Discrimination *float64 `json:"discrimination,omitempty"`
+}
+
+// ItemAnalysis is the classical test theory analysis of a question, from
+// each learner's first attempt. PValue is the share answered correctly;
+// Discrimination is the point-biserial correlation of a correct answer with
+// the learner's accuracy on other questions.
+type ItemAnalysis struct {
+	QuestionID       uuid.UUID          `json:"questionId"`
+	QuestionText     string             `json:"questionText,omitempty"`
+	ChoiceType       QuestionChoiceType `json:"choiceType"`
+	DifficultyLevel  int                `json:"difficultyLevel"`
+	DifficultyLocked bool               `json:"difficultyLocked"`
+	// CalibratedLevel is the difficulty the data suggests, once there are
+	// enough attempts.
+	CalibratedLevel *int             `json:"calibratedLevel,omitempty"`
+	Attempts        int              `json:"attempts"`
+	PValue          float64          `json:"pValue"`
+	Discrimination  *float64         `json:"discrimination,omitempty"`
+	MedianTimeMs    *int             `json:"medianTimeMs,omitempty"`
+	Options         []OptionAnalysis `json:"options,omitempty"`
+	Flags           []ItemFlag       `json:"flags"`
+	ComputedAt      time.Time        `json:"computedAt"`
+}
+
+// ItemAnalysisList envelope for the item analysis report.
+type ItemAnalysisList struct {
+	Items []ItemAnalysis `json:"items"`
+	Meta  AdminUsersMeta `json:"meta"`
+}
+
+// QuestionDifficultyRequest overrides the difficulty of a question. Locked
+// questions keep their level when difficulty is recalibrated.
+type QuestionDifficultyRequest struct {
+	DifficultyLevel *int `json:"difficultyLevel" validate:"omitempty,min=1,max=5"`
+	Locked          bool `json:"locked"`
+}
+
+// TagKind separates the controlled vocabulary from free-form tags.
+type TagKind string
+
+const (
+	// TagKindPYQ marks a question from a previous year's paper; such tags
+	// carry the year.
+	TagKindPYQ     TagKind = "pyq"
+	TagKindSource  TagKind = "source"
+	TagKindConcept TagKind = "concept"
+	// TagKindFree tags are created on the fly when first assigned.
+	TagKindFree TagKind = "free"
+)
+
+// Tag labels questions, e.g. "PYQ-2019", "AIIMS" or "one-liner".
+type Tag struct {
+	ID            uuid.UUID `json:"id"`
+	Name          string    `json:"name"`
+	Kind          TagKind   `json:"kind"`
+	Year          *int      `json:"year,omitempty"`
+	QuestionCount int       `json:"questionCount"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+// TagList envelope for the admin tag list.
+type TagList struct {
+	Items []Tag          `json:"items"`
+	Meta  AdminUsersMeta `json:"meta"`
+}
+
+// TagCreateRequest body. Year is required for pyq tags.
+type TagCreateRequest struct {
+	Name string  `json:"name" validate:"required,max=64"`
+	Kind TagKind `json:"kind" validate:"required,oneof=pyq source concept free"`
+	Year *int    `json:"year,omitempty" validate:"omitempty,min=1950,max=2100"`
+}
+
+// TagUpdateRequest body; nil fields are left unchanged.
+type TagUpdateRequest struct {
+	Name *string  `json:"name,omitempty" validate:"omitempty,min=1,max=64"`
+	Kind *TagKind `json:"kind,omitempty" validate:"omitempty,oneof=pyq source concept free"`
+	Year *int     `json:"year,omitempty" validate:"omitempty,min=1950,max=2100"`
+}
+
+// QuestionTagsRequest replaces the tags of a question. Unknown names are
+// created as free tags.
+type QuestionTagsRequest struct {
+	Tags []string `json:"tags" validate:"max=20,dive,required,max=64"`
+}
+
+// LanguageEnglish is the language questions are written in.
+const LanguageEnglish = "en"
+
+// LocalizationStatus is the review state of a translated question.
+type LocalizationStatus string
+
+const (
+	LocalizationStatusMachine  LocalizationStatus = "machine"
+	LocalizationStatusReviewed LocalizationStatus = "reviewed"
+)
+
+// QuestionLocalization is a question's text in another language, translated
+// from SourceVersion of the question. Stale is true once the question has
+// moved past that version; stale variants are not served.
+type QuestionLocalization struct {
+	QuestionID    uuid.UUID          `json:"questionId"`
+	Language      string             `json:"language"`
+	QuestionText  string             `json:"questionText"`
+	OptionA       string             `json:"optionA"`
+	OptionB       string             `json:"optionB"`
+	OptionC       string             `json:"optionC"`
+	OptionD       string             `json:"optionD"`
+	ReasonText    *string            `json:"reasonText,omitempty"`
+	Explanation   *string            `json:"explanation,omitempty"`
+	MatrixRows    []string           `json:"matrixRows,omitempty"`
+	MatrixColumns []string           `json:"matrixColumns,omitempty"`
+	SourceVersion int                `json:"sourceVersion"`
+	Status        LocalizationStatus `json:"status"`
+	ReviewerID    *uuid.UUID         `json:"reviewerId,omitempty"`
+	ReviewedAt    *time.Time         `json:"reviewedAt,omitempty"`
+	UpdatedAt     time.Time          `json:"updatedAt"`
+	Stale         bool               `json:"stale"`
+}
+
+// QuestionLocalizationList is a page of the translation review queue.
+type QuestionLocalizationList struct {
+	Items []QuestionLocalization `json:"items"`
+	Meta  AdminUsersMeta         `json:"meta"`
+}
+
+// QuestionLocalizationUpdateRequest corrects a translation; nil fields are
+// left unchanged. Saving marks the translation reviewed.
+type QuestionLocalizationUpdateRequest struct {
+	QuestionText  *string  `json:"questionText,omitempty" validate:"omitempty,min=1"`
+	OptionA       *string  `json:"optionA,omitempty" validate:"omitempty,min=1"`
+	OptionB       *string  `json:"optionB,omitempty" validate:"omitempty,min=1"`
+	OptionC       *string  `json:"optionC,omitempty" validate:"omitempty,min=1"`
+	OptionD       *string  `json:"optionD,omitempty" validate:"omitempty,min=1"`
+	ReasonText    *string  `json:"reasonText,omitempty"`
+	Explanation   *string  `json:"explanation,omitempty"`
+	MatrixRows    []string `json:"matrixRows,omitempty" validate:"omitempty,dive,required"`
+	MatrixColumns []string `json:"matrixColumns,omitempty" validate:"omitempty,dive,required"`
+}
+
+// LanguagePreference is the language a learner is served questions in, and
+// the languages available.
+type LanguagePreference struct {
+	Language  string   `json:"language"`
+	Available []string `json:"available"`
+}
+
+// LanguagePreferenceRequest body.
+type LanguagePreferenceRequest struct {
+	Language string `json:"language" validate:"required"`
+}
+
+// QuestionSort is the order of the admin question list.
+type QuestionSort string
+
+const (
+	QuestionSortCreatedAt  QuestionSort = "createdAt"
+	QuestionSortDifficulty QuestionSort = "difficulty"
+	QuestionSortText       QuestionSort = "questionText"
+	// QuestionSortRelevance ranks full-text search matches; it needs a search.
+	QuestionSortRelevance QuestionSort = "relevance"
+)
+
+// QuestionListItem is a question in the admin list. SortKey is the text form
+// of the value the list is sorted by, used to build the next cursor.
+type QuestionListItem struct {
+	Question
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	SortKey   string    `json:"-"`
+}
+
+// QuestionListMeta describes a page of the admin question list. Total counts
+// every matching question; NextCursor is empty on the last page.
+type QuestionListMeta struct {
+	Total      int          `json:"total"`
+	Limit      int          `json:"limit"`
+	Sort       QuestionSort `json:"sort"`
+	Desc       bool         `json:"desc"`
+	NextCursor string       `json:"nextCursor,omitempty"`
+}
+
+// QuestionList envelope for the admin question list.
+type QuestionList struct {
+	Items []QuestionListItem `json:"items"`
+	Meta  QuestionListMeta   `json:"meta"`
+}
+
+// QuestionImportFormat is the file format of a bulk question import.
+type QuestionImportFormat string
+
+const (
+	QuestionImportCSV QuestionImportFormat = "csv"
+	// QuestionImportNDJSON takes one MedMCQA-shaped JSON object per line.
+	QuestionImportNDJSON QuestionImportFormat = "ndjson"
+	// QuestionImportXLSX reads the first worksheet, laid out like the CSV format.
+	QuestionImportXLSX QuestionImportFormat = "xlsx"
+)
+
+// QuestionExportFormat is the file format of a question bank export.
+type QuestionExportFormat string
+
+const (
+	// QuestionExportCSV uses the import's CSV columns, so exports can be re-imported.
+	QuestionExportCSV QuestionExportFormat = "csv"
+	// QuestionExportNDJSON writes one QuestionExport per line.
+	QuestionExportNDJSON QuestionExportFormat = "ndjson"
+	// QuestionExportGIFT is Moodle's GIFT text format.
+	QuestionExportGIFT QuestionExportFormat = "gift"
+	// QuestionExportQTI is an IMS QTI 2.1 content package (zip).
+	QuestionExportQTI QuestionExportFormat = "qti"
+)
+
+// QuestionExport is a question with the names of its subject and topic.
+type QuestionExport struct {
+	Question
+	SubjectName string `json:"subjectName"`
+	TopicName   string `json:"topicName"`
+}
+
+// QuestionImportStatus tracks a background import job.
+type QuestionImportStatus string
+
+const (
+	QuestionImportQueued    QuestionImportStatus = "queued"
+	QuestionImportRunning   QuestionImportStatus = "running"
+	QuestionImportCompleted QuestionImportStatus = "completed"
+	QuestionImportFailed    QuestionImportStatus = "failed"
+)
+
+// QuestionImportOptions control how an uploaded file is imported.
+// CreateMissing creates subjects and topics the file names but the bank lacks.
+type QuestionImportOptions struct {
+	Exam          ExamCategory         `json:"exam" validate:"required"`
+	Format        QuestionImportFormat `json:"format"`
+	CreateMissing bool                 `json:"createMissing"`
+}
+
+// QuestionImportRowError explains why a row of an import file was rejected.
+// Row is 1-based and counts the header row of CSV and XLSX files.
+type QuestionImportRowError struct {
+	Row     int    `json:"row"`
+	Message string `json:"message"`
+}
+
+// QuestionImportDuplicate warns that an imported row looks like questions
+// already in the bank or on earlier rows of the file. The row is imported
+// anyway.
+type QuestionImportDuplicate struct {
+	Row     int               `json:"row"`
+	Similar []SimilarQuestion `json:"similar"`
+}
+
+// QuestionImportReport is the outcome of validating an import file without
+// writing it. Rows whose question ID is already in the bank are skipped.
+type QuestionImportReport struct {
+	Exam        ExamCategory             `json:"exam"`
+	Format      QuestionImportFormat     `json:"format"`
+	TotalRows   int                      `json:"totalRows"`
+	ValidRows   int                      `json:"validRows"`
+	SkippedRows int                      `json:"skippedRows"`
+	FailedRows  int                      `json:"failedRows"`
+	NewSubjects []string                 `json:"newSubjects"`
+	NewTopics   []string                 `json:"newTopics"`
+	Errors      []QuestionImportRowError `json:"errors"`
+	// DuplicateRows counts valid rows that look like existing questions;
+	// Duplicates lists the first of them.
+	DuplicateRows int                       `json:"duplicateRows"`
+	Duplicates    []QuestionImportDuplicate `json:"duplicates"`
+}
+
+// QuestionImportJob tracks a background import. Error is set when the whole
+// file could not be processed; per-row problems are listed in Errors.
+type QuestionImportJob struct {
+	ID            uuid.UUID                 `json:"id"`
+	Exam          ExamCategory              `json:"exam"`
+	Format        QuestionImportFormat      `json:"format"`
+	FileName      string                    `json:"fileName"`
+	CreateMissing bool                      `json:"createMissing"`
+	Status        QuestionImportStatus      `json:"status"`
+	TotalRows     int                       `json:"totalRows"`
+	ProcessedRows int                       `json:"processedRows"`
+	ImportedRows  int                       `json:"importedRows"`
+	SkippedRows   int                       `json:"skippedRows"`
+	FailedRows    int                       `json:"failedRows"`
+	Errors        []QuestionImportRowError  `json:"errors"`
+	DuplicateRows int                       `json:"duplicateRows"`
+	Duplicates    []QuestionImportDuplicate `json:"duplicates"`
+	Error         *string                   `json:"error,omitempty"`
+	CreatedBy     uuid.UUID                 `json:"createdBy"`
+	CreatedAt     time.Time                 `json:"createdAt"`
+	StartedAt     *time.Time                `json:"startedAt,omitempty"`
+	FinishedAt    *time.Time                `json:"finishedAt,omitempty"`
 }
 
 // PracticeSession tracks a session.
 type PracticeSession struct {
 	ID                    uuid.UUID             `json:"id"`
+	UserID                uuid.UUID             `json:"userId"`
 	Mode                  PracticeMode          `json:"mode"`
 	Exam                  ExamCategory          `json:"exam"`
 	Status                PracticeSessionStatus `json:"status"`
 	TotalQuestionsPlanned *int                  `json:"totalQuestionsPlanned,omitempty"`
+	TimeLimitMinutes      *int                  `json:"timeLimitMinutes,omitempty"`
 	StartedAt             time.Time             `json:"startedAt"`
 	CompletedAt           *time.Time            `json:"completedAt,omitempty"`
 }
 
 // PracticeSessionCreateRequest body.
 type PracticeSessionCreateRequest struct {
-	Mode             PracticeMode `json:"mode" validate:"required"`
+	Mode             PracticeMode `json:"mode" validate:"required,oneof=smart custom revision exam pyq"`
 	Exam             ExamCategory `json:"exam"`
 	SubjectIDs       []uuid.UUID  `json:"subjectIds"`
 	TopicIDs         []uuid.UUID  `json:"topicIds"`
-	DifficultyLevels []int        `json:"difficultyLevels"`
-	NumQuestions     int          `json:"numQuestions"`
-	TimeLimitMinutes *int         `json:"timeLimitMinutes,omitempty"`
+	DifficultyLevels []int        `json:"difficultyLevels" validate:"dive,min=1,max=5"`
+	NumQuestions     int          `json:"numQuestions" validate:"omitempty,min=1,max=200"`
+	TimeLimitMinutes *int         `json:"timeLimitMinutes,omitempty" validate:"omitempty,min=1"`
+	// Tags keeps questions carrying all of these tags.
+	Tags []string `json:"tags" validate:"max=20,dive,required,max=64"`
+	// PYQYears narrows pyq sessions to papers of these years.
+	PYQYears []int `json:"pyqYears" validate:"dive,min=1950,max=2100"`
 }
 
 // PracticeSessionQuestion holds question within a session.
 type PracticeSessionQuestion struct {
-	ID             uuid.UUID  `json:"id"`
-	SequenceIndex  int        `json:"sequenceIndex"`
-	Question       Question   `json:"question"`
-	SelectedOption *int       `json:"selectedOption,omitempty"`
-	IsCorrect      *bool      `json:"isCorrect,omitempty"`
-	TimeTakenMs    *int       `json:"timeTakenMs,omitempty"`
-	AnsweredAt     *time.Time `json:"answeredAt,omitempty"`
+	ID              uuid.UUID          `json:"id"`
+	SequenceIndex   int                `json:"sequenceIndex"`
+	Question        Question           `json:"question"`
+	SelectedOption  *int               `json:"selectedOption,omitempty"`
+	SelectedOptions []int              `json:"selectedOptions,omitempty"`
+	NumericAnswer   *float64           `json:"numericAnswer,omitempty"`
+	MatrixAnswer    [][]int            `json:"matrixAnswer,omitempty"`
+	IsCorrect       *bool              `json:"isCorrect,omitempty"`
+	Score           *float64           `json:"score,omitempty"`
+	TimeTakenMs     *int               `json:"timeTakenMs,omitempty"`
+	IsGuess         bool               `json:"isGuess"`
+	AnsweredAt      *time.Time         `json:"answeredAt,omitempty"`
+	Rationale       *QuestionRationale `json:"rationale,omitempty"`
+}
+
+// QuestionRationale is the "why this question" shown for smart-session questions.
+type QuestionRationale struct {
+	Reason SelectionReason `json:"reason"`
+	Note   string          `json:"note"`
 }
 
 // PracticeSessionDetail includes questions.
@@ -491,20 +1209,117 @@ type PracticeSessionDetail struct {
 	Questions []PracticeSessionQuestion `json:"questions"`
 }
 
+// PracticeBreakdownItem aggregates session results for one subject or topic.
+type PracticeBreakdownItem struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Total       int       `json:"total"`
+	Answered    int       `json:"answered"`
+	Correct     int       `json:"correct"`
+	Accuracy    float64   `json:"accuracy"`
+	TimeTakenMs int       `json:"timeTakenMs"`
+}
+
+// PracticeSessionResult summarizes a finished (or in-flight) session.
+type PracticeSessionResult struct {
+	Session        PracticeSession         `json:"session"`
+	TotalQuestions int                     `json:"totalQuestions"`
+	Answered       int                     `json:"answered"`
+	Correct        int                     `json:"correct"`
+	Incorrect      int                     `json:"incorrect"`
+	Skipped        int                     `json:"skipped"`
+	Score          float64                 `json:"score"`
+	Accuracy       float64                 `json:"accuracy"`
+	TotalTimeMs    int                     `json:"totalTimeMs"`
+	Subjects       []PracticeBreakdownItem `json:"subjects"`
+	Topics         []PracticeBreakdownItem `json:"topics"`
+}
+
 // PracticeAnswerRequest payload.
 type PracticeAnswerRequest struct {
 	SessionQuestionID uuid.UUID `json:"sessionQuestionId" validate:"required"`
-	SelectedOption    int       `json:"selectedOption" validate:"required"`
+	SelectedOption    int       `json:"selectedOption" validate:"omitempty,min=1,max=4"`
+	SelectedOptions   []int     `json:"selectedOptions,omitempty" validate:"omitempty,max=4,unique,dive,min=1,max=4"`
+	NumericAnswer     *float64  `json:"numericAnswer,omitempty"`
+	MatrixAnswer      [][]int   `json:"matrixAnswer,omitempty"`
 	TimeTakenMs       *int      `json:"timeTakenMs,omitempty"`
+	IsGuess           bool      `json:"isGuess"`
 }
 
-// RevisionItem for SRS queue.
+// RevisionItem for SRS queue. Scheduler state: IntervalIndex is the fixed
+// ladder step or the SM-2 repetition count, Ease is the SM-2 ease factor, and
+// Stability and Difficulty are the FSRS memory state.
 type RevisionItem struct {
-	ID            uuid.UUID `json:"id"`
-	Question      Question  `json:"question"`
-	NextReviewAt  time.Time `json:"nextReviewAt"`
-	IntervalIndex int       `json:"intervalIndex"`
-	TimesReviewed int       `json:"timesReviewed"`
+	ID             uuid.UUID         `json:"id"`
+	UserID         uuid.UUID         `json:"userId"`
+	Question       Question          `json:"question"`
+	Algorithm      RevisionAlgorithm `json:"algorithm"`
+	NextReviewAt   time.Time         `json:"nextReviewAt"`
+	IntervalIndex  int               `json:"intervalIndex"`
+	IntervalDays   int               `json:"intervalDays"`
+	Ease           float64           `json:"ease"`
+	Stability      float64           `json:"stability"`
+	Difficulty     float64           `json:"difficulty"`
+	Lapses         int               `json:"lapses"`
+	TimesReviewed  int               `json:"timesReviewed"`
+	LastResult     *RevisionResult   `json:"lastResult,omitempty"`
+	LastReviewedAt *time.Time        `json:"lastReviewedAt,omitempty"`
+}
+
+// RevisionForecastDay is the revision workload of one UTC day. Scheduled
+// counts items falling due that day (overdue ones on the first day), Due what
+// the daily cap lets through and Deferred what carries over to the next day.
+type RevisionForecastDay struct {
+	Date      string `json:"date"`
+	Scheduled int    `json:"scheduled"`
+	Due       int    `json:"due"`
+	Deferred  int    `json:"deferred"`
+}
+
+// RevisionDailyCap is a learner's revision cap. DailyCap is their own cap, nil
+// while they follow Default, the global one; Effective is the one applied, 0
+// for unlimited.
+type RevisionDailyCap struct {
+	DailyCap  *int `json:"dailyCap"`
+	Default   int  `json:"default"`
+	Effective int  `json:"effective"`
+}
+
+// RevisionDailyCapRequest body. A null dailyCap restores the global cap.
+type RevisionDailyCapRequest struct {
+	DailyCap *int `json:"dailyCap" validate:"omitempty,min=1"`
+}
+
+// RevisionReplayRequest body: the scheduler to replay a user's history through.
+type RevisionReplayRequest struct {
+	UserID    uuid.UUID         `json:"userId" validate:"required"`
+	Algorithm RevisionAlgorithm `json:"algorithm" validate:"required,oneof=fixed sm2 fsrs"`
+	SM2       *SM2Params        `json:"sm2,omitempty"`
+	FSRS      *FSRSParams       `json:"fsrs,omitempty"`
+}
+
+// RevisionReplayReport summarizes a scheduler replayed over logged reviews.
+// RetentionWhenDue only counts reviews the scheduler would have had due by
+// then; AverageIntervalDays is a proxy for its review workload.
+type RevisionReplayReport struct {
+	Algorithm           RevisionAlgorithm `json:"algorithm"`
+	Items               int               `json:"items"`
+	Reviews             int               `json:"reviews"`
+	ObservedRetention   float64           `json:"observedRetention"`
+	DueReviews          int               `json:"dueReviews"`
+	RetentionWhenDue    float64           `json:"retentionWhenDue"`
+	AverageIntervalDays float64           `json:"averageIntervalDays"`
+}
+
+// RevisionReplayComparison sets the current scheduler against a candidate.
+type RevisionReplayComparison struct {
+	Current   RevisionReplayReport `json:"current"`
+	Candidate RevisionReplayReport `json:"candidate"`
+}
+
+// RevisionReviewRequest body.
+type RevisionReviewRequest struct {
+	Result RevisionResult `json:"result" validate:"required,oneof=CORRECT INCORRECT"`
 }
 
 // ExamConfig describes an exam event.
@@ -521,37 +1336,57 @@ type ExamConfig struct {
 	EntryFee         int            `json:"entryFee"`
 	ScheduleStartAt  *time.Time     `json:"scheduleStartAt,omitempty"`
 	ScheduleEndAt    *time.Time     `json:"scheduleEndAt,omitempty"`
+	RegisterUntil    *time.Time     `json:"registerUntil,omitempty"`
+	Capacity         *int           `json:"capacity,omitempty"`
 	Status           ExamStatus     `json:"status"`
+	CreatedAt        time.Time      `json:"createdAt"`
+	UpdatedAt        time.Time      `json:"updatedAt"`
+}
+
+// ExamCategorySettings holds marking options shared by everything under one exam.
+type ExamCategorySettings struct {
+	Exam               ExamCategory       `json:"exam"`
+	Name               string             `json:"name"`
+	MultiSelectScoring MultiSelectScoring `json:"multiSelectScoring"`
+}
+
+// ExamCategorySettingsUpdateRequest body.
+type ExamCategorySettingsUpdateRequest struct {
+	MultiSelectScoring MultiSelectScoring `json:"multiSelectScoring" validate:"required,oneof=all_or_nothing partial"`
 }
 
 // ExamConfigCreateRequest body.
 type ExamConfigCreateRequest struct {
 	Exam             ExamCategory   `json:"exam" validate:"required"`
-	Name             string         `json:"name" validate:"required"`
-	Type             ExamConfigType `json:"type" validate:"required"`
+	Name             string         `json:"name" validate:"required,max=200"`
+	Type             ExamConfigType `json:"type" validate:"required,oneof=MOCK SUBJECT_TEST REWARD_EVENT DAILY_TEST"`
 	Description      string         `json:"description"`
-	NumQuestions     int            `json:"numQuestions" validate:"required"`
-	TimeLimitMinutes int            `json:"timeLimitMinutes" validate:"required"`
-	MarksPerCorrect  float64        `json:"marksPerCorrect"`
-	NegativePerWrong float64        `json:"negativePerWrong"`
-	EntryFee         int            `json:"entryFee"`
+	NumQuestions     int            `json:"numQuestions" validate:"required,min=1,max=1000"`
+	TimeLimitMinutes int            `json:"timeLimitMinutes" validate:"required,min=1,max=1440"`
+	MarksPerCorrect  float64        `json:"marksPerCorrect" validate:"min=0,max=100"`
+	NegativePerWrong float64        `json:"negativePerWrong" validate:"min=-100,max=0"`
+	EntryFee         int            `json:"entryFee" validate:"min=0"`
 	ScheduleStartAt  *time.Time     `json:"scheduleStartAt,omitempty"`
 	ScheduleEndAt    *time.Time     `json:"scheduleEndAt,omitempty"`
+	RegisterUntil    *time.Time     `json:"registerUntil,omitempty"`
+	Capacity         *int           `json:"capacity,omitempty" validate:"omitempty,min=1"`
 }
 
 // ExamConfigUpdateRequest body.
 type ExamConfigUpdateRequest struct {
-	Name             *string         `json:"name,omitempty"`
-	Type             *ExamConfigType `json:"type,omitempty"`
+	Name             *string         `json:"name,omitempty" validate:"omitempty,min=1,max=200"`
+	Type             *ExamConfigType `json:"type,omitempty" validate:"omitempty,oneof=MOCK SUBJECT_TEST REWARD_EVENT DAILY_TEST"`
 	Description      *string         `json:"description,omitempty"`
-	NumQuestions     *int            `json:"numQuestions,omitempty"`
-	TimeLimitMinutes *int            `json:"timeLimitMinutes,omitempty"`
-	MarksPerCorrect  *float64        `json:"marksPerCorrect,omitempty"`
-	NegativePerWrong *float64        `json:"negativePerWrong,omitempty"`
-	EntryFee         *int            `json:"entryFee,omitempty"`
+	NumQuestions     *int            `json:"numQuestions,omitempty" validate:"omitempty,min=1,max=1000"`
+	TimeLimitMinutes *int            `json:"timeLimitMinutes,omitempty" validate:"omitempty,min=1,max=1440"`
+	MarksPerCorrect  *float64        `json:"marksPerCorrect,omitempty" validate:"omitempty,min=0,max=100"`
+	NegativePerWrong *float64        `json:"negativePerWrong,omitempty" validate:"omitempty,min=-100,max=0"`
+	EntryFee         *int            `json:"entryFee,omitempty" validate:"omitempty,min=0"`
 	ScheduleStartAt  *time.Time      `json:"scheduleStartAt,omitempty"`
 	ScheduleEndAt    *time.Time      `json:"scheduleEndAt,omitempty"`
-	Status           *ExamStatus     `json:"status,omitempty"`
+	RegisterUntil    *time.Time      `json:"registerUntil,omitempty"`
+	Capacity         *int            `json:"capacity,omitempty" validate:"omitempty,min=1"`
+	Status           *ExamStatus     `json:"status,omitempty" validate:"omitempty,oneof=DRAFT SCHEDULED ONGOING COMPLETED"`
 }
 
 // ExamSummary returned by events list.
@@ -560,6 +1395,90 @@ type ExamSummary struct {
 	IsRegistered bool       `json:"isRegistered"`
 	IsCompleted  bool       `json:"isCompleted"`
 	BestScore    *float64   `json:"bestScore,omitempty"`
+	SeatsLeft    *int       `json:"seatsLeft,omitempty"`
+}
+
+// ExamRegistrationStatus is the state of a registration.
+type ExamRegistrationStatus string
+
+const (
+	ExamRegistrationRegistered ExamRegistrationStatus = "REGISTERED"
+	ExamRegistrationCancelled  ExamRegistrationStatus = "CANCELLED"
+)
+
+// ExamRegistration is a user's seat in an exam. Fee is what the wallet paid
+// and Covered what an entry pass paid.
+type ExamRegistration struct {
+	ID           uuid.UUID              `json:"id"`
+	ExamID       uuid.UUID              `json:"examId"`
+	UserID       uuid.UUID              `json:"userId"`
+	Status       ExamRegistrationStatus `json:"status"`
+	Fee          int                    `json:"fee"`
+	CouponID     *uuid.UUID             `json:"couponId,omitempty"`
+	Covered      int                    `json:"covered"`
+	Refund       int                    `json:"refund"`
+	RegisteredAt time.Time              `json:"registeredAt"`
+	CancelledAt  *time.Time             `json:"cancelledAt,omitempty"`
+}
+
+// ExamRegisterRequest body. CouponCode names an entry pass paying the fee.
+type ExamRegisterRequest struct {
+	CouponCode string `json:"couponCode,omitempty" validate:"omitempty,max=64"`
+}
+
+// ExamAttemptStatus is the state of an exam attempt.
+type ExamAttemptStatus string
+
+const (
+	ExamAttemptInProgress ExamAttemptStatus = "IN_PROGRESS"
+	ExamAttemptSubmitted  ExamAttemptStatus = "SUBMITTED"
+	// ExamAttemptTimedOut attempts were closed at their deadline.
+	ExamAttemptTimedOut ExamAttemptStatus = "TIMED_OUT"
+)
+
+// ExamAttempt is a user's sitting of an exam. Answers are accepted until
+// DeadlineAt; Score and the counts are set once the attempt is graded.
+type ExamAttempt struct {
+	ID          uuid.UUID         `json:"id"`
+	ExamID      uuid.UUID         `json:"examId"`
+	UserID      uuid.UUID         `json:"userId"`
+	Status      ExamAttemptStatus `json:"status"`
+	StartedAt   time.Time         `json:"startedAt"`
+	DeadlineAt  time.Time         `json:"deadlineAt"`
+	SubmittedAt *time.Time        `json:"submittedAt,omitempty"`
+	Score       *float64          `json:"score,omitempty"`
+	Correct     int               `json:"correct"`
+	Incorrect   int               `json:"incorrect"`
+	Skipped     int               `json:"skipped"`
+}
+
+// ExamAttemptQuestion is one question of the paper with the saved answer.
+// IsCorrect and Marks are set once the attempt is graded.
+type ExamAttemptQuestion struct {
+	ID              uuid.UUID  `json:"id"`
+	SequenceIndex   int        `json:"sequenceIndex"`
+	Question        Question   `json:"question"`
+	SelectedOptions []int      `json:"selectedOptions,omitempty"`
+	NumericAnswer   *float64   `json:"numericAnswer,omitempty"`
+	MatrixAnswer    [][]int    `json:"matrixAnswer,omitempty"`
+	AnsweredAt      *time.Time `json:"answeredAt,omitempty"`
+	IsCorrect       *bool      `json:"isCorrect,omitempty"`
+	Marks           *float64   `json:"marks,omitempty"`
+}
+
+// ExamAttemptDetail is an attempt with its paper.
+type ExamAttemptDetail struct {
+	Attempt   ExamAttempt           `json:"attempt"`
+	Questions []ExamAttemptQuestion `json:"questions"`
+}
+
+// ExamAnswerRequest saves the answer to one question of an attempt. An
+// answer without any selection clears the saved one.
+type ExamAnswerRequest struct {
+	AttemptQuestionID uuid.UUID `json:"attemptQuestionId" validate:"required"`
+	SelectedOptions   []int     `json:"selectedOptions,omitempty" validate:"omitempty,max=4,unique,dive,min=1,max=4"`
+	NumericAnswer     *float64  `json:"numericAnswer,omitempty"`
+	MatrixAnswer      [][]int   `json:"matrixAnswer,omitempty"`
 }
 
 // PodcastEpisode describes audio content.
@@ -605,6 +1524,14 @@ type WalletTransaction struct {
 	CreatedAt   time.Time    `json:"createdAt"`
 }
 
+// Coupon types. FIXED and BONUS coupons credit the wallet when redeemed;
+// ENTRY_PASS coupons pay exam entry fees, up to Amount or in full when it is 0.
+const (
+	CouponTypeFixed     = "FIXED"
+	CouponTypeEntryPass = "ENTRY_PASS"
+	CouponTypeBonus     = "BONUS"
+)
+
 // Coupon describes a coupon.
 type Coupon struct {
 	ID             uuid.UUID  `json:"id"`
@@ -620,12 +1547,12 @@ type Coupon struct {
 
 // CouponCreateRequest body.
 type CouponCreateRequest struct {
-	Code           string     `json:"code" validate:"required"`
+	Code           string     `json:"code" validate:"required,max=64"`
 	Description    string     `json:"description"`
-	Type           string     `json:"type" validate:"required"`
-	Amount         int        `json:"amount"`
-	MaxUsesTotal   int        `json:"maxUsesTotal"`
-	MaxUsesPerUser int        `json:"maxUsesPerUser"`
+	Type           string     `json:"type" validate:"required,oneof=FIXED ENTRY_PASS BONUS"`
+	Amount         int        `json:"amount" validate:"min=0"`
+	MaxUsesTotal   int        `json:"maxUsesTotal" validate:"min=0"`
+	MaxUsesPerUser int        `json:"maxUsesPerUser" validate:"min=0"`
 	ExpiresAt      *time.Time `json:"expiresAt,omitempty"`
 	IsActive       bool       `json:"isActive"`
 }
@@ -644,14 +1571,36 @@ type ReferralSummary struct {
 	TotalEarned  int    `json:"totalEarned"`
 }
 
-// AISettings drives adaptive algorithms.
+// AISettings drives adaptive algorithms. RevisionDailyCap limits the revision
+// items each user is served per day (zero means no cap); RevisionLoadSmoothing
+// spreads newly queued items over nearby days to keep future days level.
 type AISettings struct {
-	WeaknessMinAttempts      int   `json:"weaknessMinAttempts"`
-	WeaknessThresholdPercent int   `json:"weaknessThresholdPercent"`
-	StrongThresholdPercent   int   `json:"strongThresholdPercent"`
-	RevisionIntervalsDays    []int `json:"revisionIntervalsDays"`
-	IncludeGuessedCorrect    bool  `json:"includeGuessedCorrect"`
-	RevisionEnabled          bool  `json:"revisionEnabled"`
+	WeaknessMinAttempts      int               `json:"weaknessMinAttempts" validate:"min=0"`
+	WeaknessThresholdPercent int               `json:"weaknessThresholdPercent" validate:"min=0,max=100"`
+	StrongThresholdPercent   int               `json:"strongThresholdPercent" validate:"min=0,max=100"`
+	RevisionIntervalsDays    []int             `json:"revisionIntervalsDays" validate:"dive,min=1"`
+	IncludeGuessedCorrect    bool              `json:"includeGuessedCorrect"`
+	RevisionEnabled          bool              `json:"revisionEnabled"`
+	RevisionAlgorithm        RevisionAlgorithm `json:"revisionAlgorithm" validate:"omitempty,oneof=fixed sm2 fsrs"`
+	RevisionDailyCap         int               `json:"revisionDailyCap" validate:"min=0"`
+	RevisionLoadSmoothing    bool              `json:"revisionLoadSmoothing"`
+	SM2                      SM2Params         `json:"sm2"`
+	FSRS                     FSRSParams        `json:"fsrs"`
+}
+
+// SM2Params tunes the SM-2 scheduler; zero values use the classic defaults.
+type SM2Params struct {
+	InitialEase        float64 `json:"initialEase" validate:"omitempty,min=1.3"`
+	MinEase            float64 `json:"minEase" validate:"omitempty,min=1"`
+	FirstIntervalDays  int     `json:"firstIntervalDays" validate:"omitempty,min=1"`
+	SecondIntervalDays int     `json:"secondIntervalDays" validate:"omitempty,min=1"`
+}
+
+// FSRSParams tunes the FSRS scheduler; zero values use the published defaults.
+type FSRSParams struct {
+	RequestRetention    float64   `json:"requestRetention" validate:"omitempty,gt=0,lt=1"`
+	MaximumIntervalDays int       `json:"maximumIntervalDays" validate:"omitempty,min=1"`
+	Weights             []float64 `json:"weights,omitempty" validate:"omitempty,len=17"`
 }
 
 // AnalyticsOverview returns dashboard metrics.
@@ -2,6 +2,8 @@ package repo
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -21,11 +23,169 @@ type (
 	}
 )
 
+// ErrNotFound is returned when the requested row does not exist or a
+// conditional update matched nothing.
+var ErrNotFound = errors.New("not found")
+
+// ErrConflict is returned when a row changed after the caller read it.
+var ErrConflict = errors.New("conflict")
+
+// ErrInsufficientFunds is returned when a wallet balance cannot cover a debit.
+var ErrInsufficientFunds = errors.New("insufficient funds")
+
+// ErrCapacity is returned when an exam has no seats left.
+var ErrCapacity = errors.New("capacity reached")
+
+// ErrCouponUnavailable is returned when a coupon is inactive, expired, used up
+// or of the wrong type for its use.
+var ErrCouponUnavailable = errors.New("coupon unavailable")
+
+// ErrLimitReached is returned when a user has used up a rate limit.
+var ErrLimitReached = errors.New("limit reached")
+
 // QuestionFilter carries optional filters.
 type QuestionFilter struct {
-	Exam      *entity.ExamCategory
-	SubjectID *uuid.UUID
-	TopicID   *uuid.UUID
+	Exam             *entity.ExamCategory
+	SubjectID        *uuid.UUID
+	TopicID          *uuid.UUID
+	IsActive         *bool
+	IsHighYield      *bool
+	IsClinical       *bool
+	IsImageBased     *bool
+	DifficultyLevels []int
+	ChoiceTypes      []entity.QuestionChoiceType
+	Statuses         []entity.QuestionStatus
+	ReviewerID       *uuid.UUID
+	// Search is a web-style full-text query over the stem, options, reason
+	// and explanation: words, "quoted phrases", or and -excluded words.
+	Search string
+	// CreatedFrom and CreatedTo bound the creation time, CreatedTo exclusive.
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	// Tags keeps questions carrying all of these tags, by name in any case.
+	Tags []string
+}
+
+// TagFilter selects tags for the admin list. Search matches a name prefix.
+type TagFilter struct {
+	Kind     *entity.TagKind
+	Search   string
+	Page     int
+	PageSize int
+}
+
+// ItemAttemptStats aggregates the first attempt of each learner at a
+// question. Ability is a learner's accuracy on their other questions; it is
+// missing for learners with no other attempts.
+type ItemAttemptStats struct {
+	QuestionID     uuid.UUID
+	ChoiceType     entity.QuestionChoiceType
+	CorrectOptions []int
+	Attempts       int
+	Correct        int
+	// Discrimination is the correlation of correctness with ability, nil
+	// when either does not vary.
+	Discrimination *float64
+	MedianTimeMs   *float64
+	AbilityMean    float64
+	AbilitySD      float64
+	Options        []OptionAttemptStats
+}
+
+// OptionAttemptStats counts the attempts that chose an option and the mean
+// ability of those learners.
+type OptionAttemptStats struct {
+	Option      int     `json:"option"`
+	Count       int     `json:"count"`
+	AbilityMean float64 `json:"abilityMean"`
+}
+
+// LocalizationFilter selects translations for the review queue. Zero values
+// do not filter.
+type LocalizationFilter struct {
+	Language string
+	Status   *entity.LocalizationStatus
+	Stale    *bool
+	Page     int
+	PageSize int
+}
+
+// ItemAnalysisFilter selects stored item analyses. Zero values do not filter.
+type ItemAnalysisFilter struct {
+	Exam        *entity.ExamCategory
+	SubjectID   *uuid.UUID
+	TopicID     *uuid.UUID
+	Flag        *entity.ItemFlag
+	FlaggedOnly bool
+	MinAttempts int
+	Page        int
+	PageSize    int
+}
+
+// QuestionPage selects one page of the question list, sorted by Sort and
+// then ID. After resumes from the last question of the previous page.
+type QuestionPage struct {
+	Sort  entity.QuestionSort
+	Desc  bool
+	Limit int
+	After *QuestionCursor
+}
+
+// QuestionCursor is the position of a question in a sorted list: its
+// QuestionListItem.SortKey and ID.
+type QuestionCursor struct {
+	Key string
+	ID  uuid.UUID
+}
+
+// QuestionPoolFilter narrows the pool practice sessions draw questions from.
+type QuestionPoolFilter struct {
+	UserID           uuid.UUID
+	Exam             entity.ExamCategory
+	SubjectIDs       []uuid.UUID
+	TopicIDs         []uuid.UUID
+	DifficultyLevels []int
+	ExcludeIDs       []uuid.UUID
+	// Tags keeps questions carrying all of these tags, by name in any case.
+	Tags []string
+	// PYQ keeps questions with a pyq tag, of one of PYQYears when given.
+	PYQ      bool
+	PYQYears []int
+	// SeenBefore keeps only questions the user has not been served or attempted since this instant.
+	SeenBefore *time.Time
+	// Unseen keeps only questions the user has never been served or attempted.
+	Unseen bool
+	Limit  int
+}
+
+// RevisionReviewLog is one logged review of a revision item queued at QueuedAt.
+type RevisionReviewLog struct {
+	ItemID     uuid.UUID
+	QueuedAt   time.Time
+	Result     entity.RevisionResult
+	ReviewedAt time.Time
+}
+
+// TopicMastery summarizes a user's attempt history in one topic.
+type TopicMastery struct {
+	SubjectID     uuid.UUID
+	TopicID       uuid.UUID
+	Attempts      int
+	Correct       int
+	LastAttemptAt time.Time
+}
+
+// SessionTopicStat aggregates a practice session's questions for one topic.
+type SessionTopicStat struct {
+	SubjectID   uuid.UUID
+	SubjectName string
+	TopicID     uuid.UUID
+	TopicName   string
+	Total       int
+	Answered    int
+	Correct     int
+	Score       float64
+	TimeTakenMs int
 }
 
 // PodcastFilter describes query args.
@@ -52,42 +212,320 @@ type (
 
 	SubjectRepository interface {
 		ListByExam(ctx context.Context, exam *entity.ExamCategory) ([]entity.Subject, error)
+		Get(ctx context.Context, id uuid.UUID) (entity.Subject, error)
+		// Create returns ErrNotFound for an unknown exam and ErrConflict when
+		// the exam already has a subject with the name.
 		Create(ctx context.Context, subject entity.Subject) (entity.Subject, error)
+		// Update saves the name and active flag, returning ErrConflict when
+		// another subject of the exam has the name.
+		Update(ctx context.Context, subject entity.Subject) (entity.Subject, error)
+		// Reorder sets sort_order to each subject's position in ids.
+		Reorder(ctx context.Context, ids []uuid.UUID) error
+		// Delete returns ErrConflict while the subject still has topics.
+		Delete(ctx context.Context, id uuid.UUID) error
 	}
 
 	TopicRepository interface {
 		ListBySubject(ctx context.Context, subjectID uuid.UUID) ([]entity.Topic, error)
+		Get(ctx context.Context, id uuid.UUID) (entity.Topic, error)
+		// Create returns ErrConflict when the subject already has a topic with
+		// the name.
 		Create(ctx context.Context, topic entity.Topic) (entity.Topic, error)
+		// Update saves the name and parent, and applies the active flag to the
+		// whole subtree. It returns ErrConflict when the name is taken.
+		Update(ctx context.Context, topic entity.Topic) (entity.Topic, error)
+		// Reorder sets sort_order to each topic's position in ids.
+		Reorder(ctx context.Context, ids []uuid.UUID) error
+		// Delete returns ErrConflict while questions, podcasts or subtopics
+		// reference the topic.
+		Delete(ctx context.Context, id uuid.UUID) error
+		// Merge moves the questions, podcasts and subtopics of source into
+		// target and deletes source, in one transaction. It returns
+		// ErrConflict when a moved subtopic's name is taken in the target's
+		// subject.
+		Merge(ctx context.Context, sourceID, targetID uuid.UUID) (entity.TopicMergeResult, error)
 	}
 
 	QuestionRepository interface {
-		List(ctx context.Context, filter QuestionFilter) ([]entity.Question, error)
-		Create(ctx context.Context, question entity.Question) (entity.Question, error)
+		// List returns a page of the questions matching filter.
+		List(ctx context.Context, filter QuestionFilter, page QuestionPage) ([]entity.QuestionListItem, error)
+		Count(ctx context.Context, filter QuestionFilter) (int, error)
+		// Stream calls fn for every matching question, ordered by subject, topic
+		// and ID, without holding the result set in memory. It stops at the
+		// first error fn returns.
+		Stream(ctx context.Context, filter QuestionFilter, fn func(entity.Question) error) error
+		// Create stores version.Question as version 1, with the version's author.
+		Create(ctx context.Context, version entity.QuestionVersion) (entity.Question, error)
 		GetByID(ctx context.Context, id uuid.UUID) (entity.Question, error)
-		Update(ctx context.Context, question entity.Question) (entity.Question, error)
+		// Update stores version.Question as the next version. version.Question.Version
+		// is the version the change was made against; ErrConflict is returned
+		// when the question has moved on since. difficulty, unless nil, sets the
+		// level in the same write without being versioned; with no
+		// ChangedFields only the level is set and no version is added.
+		Update(ctx context.Context, version entity.QuestionVersion, difficulty *int) (entity.Question, error)
+		// ListVersions returns every version of a question, newest first.
+		ListVersions(ctx context.Context, questionID uuid.UUID) ([]entity.QuestionVersion, error)
+		GetVersion(ctx context.Context, questionID uuid.UUID, version int) (entity.QuestionVersion, error)
+		// Transition moves a question from one workflow state to another and
+		// sets its reviewer. ErrConflict is returned when the question is no
+		// longer in from.
+		Transition(ctx context.Context, id uuid.UUID, from, to entity.QuestionStatus, reviewerID *uuid.UUID) (entity.Question, error)
+		// SetDifficulty sets the difficulty lock, and the level unless it is
+		// nil, outside versioning.
+		SetDifficulty(ctx context.Context, id uuid.UUID, level *int, locked bool) (entity.Question, error)
 		Delete(ctx context.Context, id uuid.UUID) error
+		// ListPoolIDs returns published questions only.
+		ListPoolIDs(ctx context.Context, filter QuestionPoolFilter) ([]uuid.UUID, error)
+		// ExistingIDs returns those of ids that are already in the bank.
+		ExistingIDs(ctx context.Context, ids []uuid.UUID) ([]uuid.UUID, error)
+	}
+
+	// TagRepository manages tags and their assignment to questions. Names are
+	// unique in any case.
+	TagRepository interface {
+		// List returns a page of tags, by kind, year and name, with their
+		// question counts, and the number matching.
+		List(ctx context.Context, filter TagFilter) ([]entity.Tag, int, error)
+		Get(ctx context.Context, id uuid.UUID) (entity.Tag, error)
+		// Create returns ErrConflict when the name is taken.
+		Create(ctx context.Context, tag entity.Tag) (entity.Tag, error)
+		// Update returns ErrNotFound for an unknown tag and ErrConflict when
+		// the name is taken.
+		Update(ctx context.Context, tag entity.Tag) (entity.Tag, error)
+		// Delete removes the tag from every question.
+		Delete(ctx context.Context, id uuid.UUID) error
+		// SetQuestionTags replaces the tags of a question, creating unknown
+		// names as free tags, and returns ErrNotFound for an unknown question.
+		SetQuestionTags(ctx context.Context, questionID uuid.UUID, names []string) error
+	}
+
+	// LocalizationRepository stores translated question variants and the
+	// language each learner prefers.
+	LocalizationRepository interface {
+		// ListPending returns up to limit published questions, at their
+		// current version, that have no translation into language or only a
+		// stale one.
+		ListPending(ctx context.Context, language string, limit int) ([]entity.Question, error)
+		// Save inserts or replaces a translation.
+		Save(ctx context.Context, localization entity.QuestionLocalization) error
+		// List returns a page of translations, most recently changed first,
+		// and the number matching.
+		List(ctx context.Context, filter LocalizationFilter) ([]entity.QuestionLocalization, int, error)
+		Get(ctx context.Context, questionID uuid.UUID, language string) (entity.QuestionLocalization, error)
+		// ListForQuestions returns the translations of the questions into
+		// language, stale ones included.
+		ListForQuestions(ctx context.Context, questionIDs []uuid.UUID, language string) ([]entity.QuestionLocalization, error)
+		// GetLanguage returns the learner's preferred language, English for
+		// unknown users.
+		GetLanguage(ctx context.Context, userID uuid.UUID) (string, error)
+		// SetLanguage returns ErrNotFound for an unknown user.
+		SetLanguage(ctx context.Context, userID uuid.UUID, language string) error
+	}
+
+	// ItemAnalysisRepository aggregates attempts into item statistics and
+	// stores the resulting analyses.
+	ItemAnalysisRepository interface {
+		// RefreshLearners recomputes each learner's first-attempt record,
+		// which ListAttemptStats scores learners by. Call it once per run.
+		RefreshLearners(ctx context.Context) error
+		// ListAttemptStats returns the attempt statistics of up to limit
+		// questions with attempts, by ID after the given one.
+		ListAttemptStats(ctx context.Context, after uuid.UUID, limit int) ([]ItemAttemptStats, error)
+		// Save stores analyses and moves the difficulty of unlocked questions
+		// to their CalibratedLevel, returning how many questions changed level.
+		// Difficulty is not versioned, so this adds no question version.
+		Save(ctx context.Context, analyses []entity.ItemAnalysis) (int, error)
+		// List returns a page of stored analyses and the number matching.
+		List(ctx context.Context, filter ItemAnalysisFilter) ([]entity.ItemAnalysis, int, error)
+		Get(ctx context.Context, questionID uuid.UUID) (entity.ItemAnalysis, error)
+	}
+
+	// QuestionSignatureRepository stores the MinHash signatures used to find
+	// near-duplicate questions.
+	QuestionSignatureRepository interface {
+		// ListStale returns up to limit questions whose signature is missing
+		// or older than their current version. Signature and BandKeys are unset.
+		ListStale(ctx context.Context, limit int) ([]entity.QuestionSignature, error)
+		// Save stores signatures, replacing older ones of the same questions.
+		Save(ctx context.Context, signatures []entity.QuestionSignature) error
+		// Candidates returns the signatures of exam's questions that share at
+		// least one band key with bandKeys.
+		Candidates(ctx context.Context, exam entity.ExamCategory, bandKeys []int64) ([]entity.QuestionSignature, error)
+		Get(ctx context.Context, ids []uuid.UUID) ([]entity.QuestionSignature, error)
+		// Collisions calls fn once per band key shared by several of exam's
+		// questions, with their IDs. It stops at the first error fn returns.
+		Collisions(ctx context.Context, exam entity.ExamCategory, fn func([]uuid.UUID) error) error
+	}
+
+	// QuestionReviewRepository stores review comments on questions.
+	QuestionReviewRepository interface {
+		CreateComment(ctx context.Context, comment entity.QuestionReviewComment) (entity.QuestionReviewComment, error)
+		// ListComments returns a question's comments, oldest first.
+		ListComments(ctx context.Context, questionID uuid.UUID, unresolvedOnly bool) ([]entity.QuestionReviewComment, error)
+		// ResolveComment marks a comment resolved; resolving it again changes
+		// nothing.
+		ResolveComment(ctx context.Context, questionID, id, resolvedBy uuid.UUID) (entity.QuestionReviewComment, error)
+	}
+
+	// QuestionReportRepository stores learners' reports of errors in questions.
+	QuestionReportRepository interface {
+		// Create stores a report against the current version of its question.
+		// ErrNotFound is returned for an unknown question, ErrConflict when
+		// the user already has an open report on it and ErrLimitReached when
+		// they filed hourlyLimit reports in the past hour; 0 means no limit.
+		Create(ctx context.Context, report entity.QuestionReport, hourlyLimit int) (entity.QuestionReport, error)
+		// CountOpen counts the open reports of a question.
+		CountOpen(ctx context.Context, questionID uuid.UUID) (int, error)
+		// ListGroups pages through questions with reports in a status, most
+		// reported first, and returns the total number of such questions.
+		ListGroups(ctx context.Context, status entity.QuestionReportStatus, page, pageSize int) ([]entity.QuestionReportGroup, int, error)
+		// ListByQuestion returns a question's reports, newest first, in any
+		// status when status is nil.
+		ListByQuestion(ctx context.Context, questionID uuid.UUID, status *entity.QuestionReportStatus) ([]entity.QuestionReport, error)
+		// Close gives the open reports of a question an outcome and returns
+		// them. In the same transaction it stores the notification notify
+		// builds for each closed report.
+		Close(ctx context.Context, questionID uuid.UUID, status entity.QuestionReportStatus, note string, resolvedBy uuid.UUID,
+			notify func(entity.QuestionReport) entity.Notification) ([]entity.QuestionReport, error)
+	}
+
+	// NotificationRepository stores users' in-app notifications.
+	NotificationRepository interface {
+		Create(ctx context.Context, notifications []entity.Notification) error
+		// ListByUser returns up to limit of a user's notifications, newest first.
+		ListByUser(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit int) ([]entity.Notification, error)
+		// MarkRead marks a user's notification read; ErrNotFound if the user
+		// has no such notification.
+		MarkRead(ctx context.Context, userID, id uuid.UUID) error
+	}
+
+	// QuestionMediaRepository stores the images attached to questions. The
+	// blobs themselves live in a blob.Store.
+	QuestionMediaRepository interface {
+		Create(ctx context.Context, media entity.QuestionMedia) (entity.QuestionMedia, error)
+		// ListByQuestions returns the media of the questions, oldest first.
+		ListByQuestions(ctx context.Context, questionIDs []uuid.UUID) ([]entity.QuestionMedia, error)
+		// Delete removes a media row of a question and returns it, so the
+		// caller can delete its blobs.
+		Delete(ctx context.Context, questionID, id uuid.UUID) (entity.QuestionMedia, error)
+	}
+
+	QuestionImportRepository interface {
+		// Create queues a job together with the uploaded file.
+		Create(ctx context.Context, job entity.QuestionImportJob, payload []byte) (entity.QuestionImportJob, error)
+		Get(ctx context.Context, id uuid.UUID) (entity.QuestionImportJob, error)
+		List(ctx context.Context, limit int) ([]entity.QuestionImportJob, error)
+		// ClaimNext marks the oldest queued job running and returns it with its
+		// file. Running jobs without progress since staleBefore are claimed
+		// again, as their worker is presumed gone. It returns ErrNotFound when
+		// there is nothing to run.
+		ClaimNext(ctx context.Context, staleBefore time.Time) (entity.QuestionImportJob, []byte, error)
+		// Save stores the job's status, counters and errors.
+		Save(ctx context.Context, job entity.QuestionImportJob) error
 	}
 
 	PracticeSessionRepository interface {
-		CreateSession(ctx context.Context, session entity.PracticeSession) (entity.PracticeSession, error)
+		// CreateSession stores the session and its questions in order; only
+		// Question.ID and Rationale of each question are used.
+		CreateSession(ctx context.Context, session entity.PracticeSession, questions []entity.PracticeSessionQuestion) (entity.PracticeSession, error)
 		ListSessions(ctx context.Context, userID uuid.UUID) ([]entity.PracticeSession, error)
 		GetSession(ctx context.Context, id uuid.UUID) (entity.PracticeSession, error)
 		ListSessionQuestions(ctx context.Context, sessionID uuid.UUID) ([]entity.PracticeSessionQuestion, error)
-		GetSessionQuestion(ctx context.Context, id uuid.UUID) (entity.PracticeSessionQuestion, error)
-		UpdateSessionQuestion(ctx context.Context, question entity.PracticeSessionQuestion) (entity.PracticeSessionQuestion, error)
+		GetSessionQuestion(ctx context.Context, sessionID, id uuid.UUID) (entity.PracticeSessionQuestion, error)
+		// AnswerSessionQuestion stores the first answer to a session question and
+		// appends its user_question_attempt row in one transaction. A non-nil
+		// revision also queues the question with that scheduler state; a question
+		// already queued restarts at that due date and counts a lapse.
+		// It returns ErrNotFound when the question was already answered.
+		AnswerSessionQuestion(ctx context.Context, sessionID, userID uuid.UUID, question entity.PracticeSessionQuestion, revision *entity.RevisionItem) (entity.PracticeSessionQuestion, error)
+		FinishSession(ctx context.Context, id uuid.UUID, status entity.PracticeSessionStatus, at time.Time) (entity.PracticeSession, error)
+		ExpireSessions(ctx context.Context, now time.Time) (int, error)
+		SessionTopicStats(ctx context.Context, sessionID uuid.UUID) ([]SessionTopicStat, error)
+		// ReplaceSessionQuestion swaps the question behind an unanswered session
+		// question. It returns ErrNotFound when the question was answered meanwhile.
+		ReplaceSessionQuestion(ctx context.Context, sessionID, id, questionID uuid.UUID, rationale entity.QuestionRationale) error
+		// TopicMastery groups the user's attempts per topic. topicIDs cover
+		// their subtopics, which report their own rows.
+		TopicMastery(ctx context.Context, userID uuid.UUID, exam entity.ExamCategory, subjectIDs, topicIDs []uuid.UUID) ([]TopicMastery, error)
 	}
 
 	RevisionRepository interface {
 		ListDue(ctx context.Context, userID uuid.UUID) ([]entity.RevisionItem, error)
+		Get(ctx context.Context, id uuid.UUID) (entity.RevisionItem, error)
+		// Review stores the scheduler state after a review and appends the
+		// review to the item's history. With a positive dailyCap it returns
+		// ErrLimitReached once the user has that many reviews since since.
+		Review(ctx context.Context, item entity.RevisionItem, dailyCap int, since time.Time) (entity.RevisionItem, error)
+		// ListReviewHistory returns the user's reviews ordered by item, then time.
+		ListReviewHistory(ctx context.Context, userID uuid.UUID) ([]RevisionReviewLog, error)
+		// ListSchedule returns due times of the user's items due before until,
+		// in the same order as ListDue.
+		ListSchedule(ctx context.Context, userID uuid.UUID, until time.Time) ([]time.Time, error)
+		CountReviewsSince(ctx context.Context, userID uuid.UUID, since time.Time) (int, error)
+		// GetDailyCap returns the user's own daily cap, nil when they follow
+		// the global one or are unknown.
+		GetDailyCap(ctx context.Context, userID uuid.UUID) (*int, error)
+		// SetDailyCap returns ErrNotFound for an unknown user.
+		SetDailyCap(ctx context.Context, userID uuid.UUID, dailyCap *int) error
 	}
 
 	ExamRepository interface {
-		ListConfigs(ctx context.Context) ([]entity.ExamConfig, error)
+		// ListConfigs returns the configs of one exam, or of all when exam is
+		// nil, soonest scheduled first.
+		ListConfigs(ctx context.Context, exam *entity.ExamCategory) ([]entity.ExamConfig, error)
+		// CreateConfig returns ErrNotFound for an unknown exam category.
 		CreateConfig(ctx context.Context, config entity.ExamConfig) (entity.ExamConfig, error)
 		GetConfig(ctx context.Context, id uuid.UUID) (entity.ExamConfig, error)
-		UpdateConfig(ctx context.Context, config entity.ExamConfig) (entity.ExamConfig, error)
+		// UpdateConfig saves a config still in status from. ErrConflict is
+		// returned when its status has changed since it was read.
+		UpdateConfig(ctx context.Context, config entity.ExamConfig, from entity.ExamStatus) (entity.ExamConfig, error)
+		// DeleteConfig returns ErrConflict while the exam has registrations.
 		DeleteConfig(ctx context.Context, id uuid.UUID) error
-		ListSummaries(ctx context.Context) ([]entity.ExamSummary, error)
+		// ListSummaries returns scheduled and ongoing exams as seen by userID.
+		ListSummaries(ctx context.Context, userID uuid.UUID) ([]entity.ExamSummary, error)
+		// Register seats userID in an exam in one transaction, paying the fee
+		// with the entry pass named by couponCode, if any, and the wallet for
+		// the rest. An existing registration is returned unchanged with
+		// created false. ErrCapacity, ErrCouponUnavailable and
+		// ErrInsufficientFunds are returned when the exam is full, the pass is
+		// unknown or cannot be used, or the wallet is short.
+		Register(ctx context.Context, examID, userID uuid.UUID, couponCode string) (reg entity.ExamRegistration, created bool, err error)
+		GetRegistration(ctx context.Context, examID, userID uuid.UUID) (entity.ExamRegistration, error)
+		// Cancel cancels a registration, refunding percent of what the wallet
+		// paid and releasing its entry pass. ErrNotFound is returned when
+		// userID is not registered.
+		Cancel(ctx context.Context, examID, userID uuid.UUID, percent int) (entity.ExamRegistration, error)
+		ListCategories(ctx context.Context) ([]entity.ExamCategorySettings, error)
+		GetCategory(ctx context.Context, exam entity.ExamCategory) (entity.ExamCategorySettings, error)
+		UpdateCategory(ctx context.Context, settings entity.ExamCategorySettings) (entity.ExamCategorySettings, error)
+	}
+
+	ExamAttemptRepository interface {
+		// ListPaper returns the question ids of the exam's frozen paper in
+		// order, or none before its first attempt.
+		ListPaper(ctx context.Context, examID uuid.UUID) ([]uuid.UUID, error)
+		// Start opens the attempt on the exam's paper, first freezing paper at
+		// the questions' current versions when the exam has none yet. An
+		// existing attempt of the user is returned unchanged with created false.
+		Start(ctx context.Context, attempt entity.ExamAttempt, paper []uuid.UUID) (started entity.ExamAttempt, created bool, err error)
+		Get(ctx context.Context, examID, userID uuid.UUID) (entity.ExamAttempt, error)
+		// ListQuestions returns the attempt's questions at their frozen versions, in order.
+		ListQuestions(ctx context.Context, attemptID uuid.UUID) ([]entity.ExamAttemptQuestion, error)
+		GetQuestion(ctx context.Context, attemptID, id uuid.UUID) (entity.ExamAttemptQuestion, error)
+		// SaveAnswer replaces the answer to an attempt question. It returns
+		// ErrConflict when the attempt is closed or its deadline has passed at
+		// now, and ErrNotFound when the question is not part of the attempt.
+		SaveAnswer(ctx context.Context, attemptID uuid.UUID, question entity.ExamAttemptQuestion, now time.Time) (entity.ExamAttemptQuestion, error)
+		// Close ends an in-progress attempt with status at; no answer changes
+		// once it returns. ErrNotFound is returned when it is not in progress.
+		Close(ctx context.Context, id uuid.UUID, status entity.ExamAttemptStatus, at time.Time) (entity.ExamAttempt, error)
+		// Grade stores the marks of each question and the totals of a closed
+		// attempt, and logs its answers as user_question_attempt rows.
+		// ErrNotFound is returned when the attempt was graded already.
+		Grade(ctx context.Context, attempt entity.ExamAttempt, questions []entity.ExamAttemptQuestion) (entity.ExamAttempt, error)
+		// ListUngraded returns up to limit attempts that are closed but not
+		// graded, or still in progress past their deadline at now.
+		ListUngraded(ctx context.Context, now time.Time, limit int) ([]entity.ExamAttempt, error)
 	}
 
 	PodcastRepository interface {
@@ -109,6 +547,9 @@ type (
 		Get(ctx context.Context, id uuid.UUID) (entity.Coupon, error)
 		Update(ctx context.Context, coupon entity.Coupon) (entity.Coupon, error)
 		Delete(ctx context.Context, id uuid.UUID) error
+		// Redeem credits a FIXED or BONUS coupon to the wallet. ErrNotFound is
+		// returned for an unknown code and ErrCouponUnavailable when it cannot
+		// be redeemed.
 		Redeem(ctx context.Context, code string, userID uuid.UUID) (entity.WalletSummary, error)
 	}
 
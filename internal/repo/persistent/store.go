@@ -3,11 +3,15 @@ package persistent
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 
 	"github.com/evrone/go-clean-template/internal/entity"
 	"github.com/evrone/go-clean-template/internal/repo"
@@ -16,43 +20,63 @@ import (
 
 // Repositories holds concrete repository implementations.
 type Repositories struct {
-	User        repoUser
-	Subject     repoSubject
-	Topic       repoTopic
-	Question    repoQuestion
-	Practice    repoPracticeSession
-	Revision    repoRevision
-	Exam        repoExam
-	Podcast     repoPodcast
-	Wallet      repoWallet
-	Coupon      repoCoupon
-	Referral    repoReferral
-	AI          repoAISettings
-	Analytics   repoAnalytics
-	Translation repoTranslation
-	Leaderboard repoLeaderboard
-	Feed        repoFeed
+	User         repoUser
+	Subject      repoSubject
+	Topic        repoTopic
+	Question     repoQuestion
+	Practice     repoPracticeSession
+	Revision     repoRevision
+	Import       repoQuestionImport
+	Signature    repoQuestionSignature
+	Media        repoQuestionMedia
+	Review       repoQuestionReview
+	Report       repoQuestionReport
+	Notification repoNotification
+	ItemAnalysis repoItemAnalysis
+	Localization repoLocalization
+	Tag          repoTag
+	Exam         repoExam
+	Attempt      repoExamAttempt
+	Podcast      repoPodcast
+	Wallet       repoWallet
+	Coupon       repoCoupon
+	Referral     repoReferral
+	AI           repoAISettings
+	Analytics    repoAnalytics
+	Translation  repoTranslation
+	Leaderboard  repoLeaderboard
+	Feed         repoFeed
 }
 
 // New wires repository implementations.
 func New(pg *postgres.Postgres) *Repositories {
 	return &Repositories{
-		User:        repoUser{pg},
-		Subject:     repoSubject{pg},
-		Topic:       repoTopic{pg},
-		Question:    repoQuestion{pg},
-		Practice:    repoPracticeSession{pg},
-		Revision:    repoRevision{pg},
-		Exam:        repoExam{pg},
-		Podcast:     repoPodcast{pg},
-		Wallet:      repoWallet{pg},
-		Coupon:      repoCoupon{pg},
-		Referral:    repoReferral{pg},
-		AI:          repoAISettings{pg},
-		Analytics:   repoAnalytics{pg},
-		Translation: repoTranslation{pg},
-		Leaderboard: repoLeaderboard{pg},
-		Feed:        repoFeed{pg},
+		User:         repoUser{pg},
+		Subject:      repoSubject{pg},
+		Topic:        repoTopic{pg},
+		Question:     repoQuestion{pg},
+		Practice:     repoPracticeSession{pg},
+		Revision:     repoRevision{pg},
+		Import:       repoQuestionImport{pg},
+		Signature:    repoQuestionSignature{pg},
+		Media:        repoQuestionMedia{pg},
+		Review:       repoQuestionReview{pg},
+		Report:       repoQuestionReport{pg},
+		Notification: repoNotification{pg},
+		ItemAnalysis: repoItemAnalysis{pg},
+		Localization: repoLocalization{pg},
+		Tag:          repoTag{pg},
+		Exam:         repoExam{pg},
+		Attempt:      repoExamAttempt{pg},
+		Podcast:      repoPodcast{pg},
+		Wallet:       repoWallet{pg},
+		Coupon:       repoCoupon{pg},
+		Referral:     repoReferral{pg},
+		AI:           repoAISettings{pg},
+		Analytics:    repoAnalytics{pg},
+		Translation:  repoTranslation{pg},
+		Leaderboard:  repoLeaderboard{pg},
+		Feed:         repoFeed{pg},
 	}
 }
 
@@ -84,9 +108,18 @@ func (r repoUser) GetExamProfile(ctx context.Context, userID uuid.UUID) (entity.
 // repoSubject implements SubjectRepository.
 type repoSubject struct{ *postgres.Postgres }
 
+const _subjectColumns = `s.id, e.code, s.name, s.is_active, s.sort_order`
+
+func scanSubject(row rowScanner) (entity.Subject, error) {
+	var s entity.Subject
+	err := row.Scan(&s.ID, &s.Exam, &s.Name, &s.IsActive, &s.SortOrder)
+
+	return s, err
+}
+
 func (r repoSubject) ListByExam(ctx context.Context, exam *entity.ExamCategory) ([]entity.Subject, error) {
 	builder := r.Builder.
-		Select("s.id", "e.code", "s.name", "s.is_active").
+		Select(_subjectColumns).
 		From("subject s").
 		Join("exam_type_lookup e ON e.id = s.exam_type_id")
 
@@ -94,7 +127,7 @@ func (r repoSubject) ListByExam(ctx context.Context, exam *entity.ExamCategory)
 		builder = builder.Where("e.code = ?", string(*exam))
 	}
 
-	builder = builder.OrderBy("s.name ASC")
+	builder = builder.OrderBy("s.sort_order ASC", "s.name ASC")
 
 	querySQL, args, err := builder.ToSql()
 	if err != nil {
@@ -109,36 +142,165 @@ func (r repoSubject) ListByExam(ctx context.Context, exam *entity.ExamCategory)
 
 	var subjects []entity.Subject
 	for rows.Next() {
-		var s entity.Subject
-		var code string
-
-		if err := rows.Scan(&s.ID, &code, &s.Name, &s.IsActive); err != nil {
+		s, err := scanSubject(rows)
+		if err != nil {
 			return nil, fmt.Errorf("subject - ListByExam - scan: %w", err)
 		}
 
-		s.Exam = entity.ExamCategory(code)
 		subjects = append(subjects, s)
 	}
 
 	return subjects, nil
 }
 
+func (r repoSubject) Get(ctx context.Context, id uuid.UUID) (entity.Subject, error) {
+	s, err := scanSubject(r.Pool.QueryRow(ctx, `
+SELECT `+_subjectColumns+` FROM subject s
+JOIN exam_type_lookup e ON e.id = s.exam_type_id
+WHERE s.id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Subject{}, repo.ErrNotFound
+	}
+	if err != nil {
+		return entity.Subject{}, fmt.Errorf("subject - Get - scan: %w", err)
+	}
+
+	return s, nil
+}
+
+// Create appends the subject after the exam's existing ones.
 func (r repoSubject) Create(ctx context.Context, subject entity.Subject) (entity.Subject, error) {
 	if subject.ID == uuid.Nil {
 		subject.ID = uuid.New()
 	}
+
+	err := r.Pool.QueryRow(ctx, `
+INSERT INTO subject (id, exam_type_id, name, is_active, sort_order)
+SELECT $1, e.id, $3, $4, COALESCE((SELECT MAX(o.sort_order) + 1 FROM subject o WHERE o.exam_type_id = e.id), 0)
+FROM exam_type_lookup e WHERE e.code = $2
+ON CONFLICT (exam_type_id, name) DO NOTHING
+RETURNING sort_order
+`, subject.ID, string(subject.Exam), subject.Name, subject.IsActive).Scan(&subject.SortOrder)
+	if errors.Is(err, pgx.ErrNoRows) {
+		var exists bool
+		if err := r.Pool.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM exam_type_lookup WHERE code = $1)", string(subject.Exam)).
+			Scan(&exists); err != nil {
+			return entity.Subject{}, fmt.Errorf("subject - Create - exists: %w", err)
+		}
+		if exists {
+			return entity.Subject{}, repo.ErrConflict
+		}
+
+		return entity.Subject{}, fmt.Errorf("subject - Create - exam %s: %w", subject.Exam, repo.ErrNotFound)
+	}
+	if err != nil {
+		return entity.Subject{}, fmt.Errorf("subject - Create - scan: %w", err)
+	}
+
+	return subject, nil
+}
+
+func (r repoSubject) Update(ctx context.Context, subject entity.Subject) (entity.Subject, error) {
+	subject, err := scanSubject(r.Pool.QueryRow(ctx, `
+UPDATE subject s SET name = $2, is_active = $3
+FROM exam_type_lookup e
+WHERE s.id = $1 AND e.id = s.exam_type_id
+  AND NOT EXISTS (SELECT 1 FROM subject o WHERE o.exam_type_id = s.exam_type_id AND o.name = $2 AND o.id <> $1)
+RETURNING `+_subjectColumns, subject.ID, subject.Name, subject.IsActive))
+	if errors.Is(err, pgx.ErrNoRows) {
+		var exists bool
+		if err := r.Pool.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM subject WHERE id = $1)", subject.ID).Scan(&exists); err != nil {
+			return entity.Subject{}, fmt.Errorf("subject - Update - exists: %w", err)
+		}
+		if exists {
+			return entity.Subject{}, repo.ErrConflict
+		}
+
+		return entity.Subject{}, repo.ErrNotFound
+	}
+	if err != nil {
+		return entity.Subject{}, fmt.Errorf("subject - Update - scan: %w", err)
+	}
+
 	return subject, nil
 }
 
+func (r repoSubject) Reorder(ctx context.Context, ids []uuid.UUID) error {
+	_, err := r.Pool.Exec(ctx, `
+UPDATE subject s SET sort_order = o.n - 1
+FROM unnest($1::uuid[]) WITH ORDINALITY AS o(id, n)
+WHERE s.id = o.id
+`, ids)
+	if err != nil {
+		return fmt.Errorf("subject - Reorder - exec: %w", err)
+	}
+
+	return nil
+}
+
+// Delete only removes empty subjects; topics are deleted or merged away first.
+func (r repoSubject) Delete(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.Pool.Exec(ctx, `
+DELETE FROM subject s
+WHERE s.id = $1
+  AND NOT EXISTS (SELECT 1 FROM topic t WHERE t.subject_id = s.id)
+  AND NOT EXISTS (SELECT 1 FROM podcast_episode p WHERE p.subject_id = s.id)
+`, id)
+	if err != nil {
+		return fmt.Errorf("subject - Delete - exec: %w", err)
+	}
+	if tag.RowsAffected() > 0 {
+		return nil
+	}
+
+	var exists bool
+	if err := r.Pool.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM subject WHERE id = $1)", id).Scan(&exists); err != nil {
+		return fmt.Errorf("subject - Delete - exists: %w", err)
+	}
+	if exists {
+		return repo.ErrConflict
+	}
+
+	return repo.ErrNotFound
+}
+
 // repoTopic implements TopicRepository.
 type repoTopic struct{ *postgres.Postgres }
 
+const _topicColumns = `t.id, t.subject_id, t.parent_id, t.name, t.is_active, t.sort_order`
+
+// _topicSubtree is a recursive CTE naming $1 and every topic below it "sub".
+const _topicSubtree = `WITH RECURSIVE sub AS (
+  SELECT id FROM topic WHERE id = $1
+  UNION ALL
+  SELECT c.id FROM topic c JOIN sub ON c.parent_id = sub.id
+)
+`
+
+// _topicsUnder selects the topics of a ? id array and every topic below them,
+// so filters on a topic cover its subtopics.
+const _topicsUnder = `(
+  WITH RECURSIVE sub AS (
+    SELECT id FROM topic WHERE id = ANY(?)
+    UNION
+    SELECT c.id FROM topic c JOIN sub ON c.parent_id = sub.id
+  )
+  SELECT id FROM sub
+)`
+
+func scanTopic(row rowScanner) (entity.Topic, error) {
+	var t entity.Topic
+	err := row.Scan(&t.ID, &t.SubjectID, &t.ParentID, &t.Name, &t.IsActive, &t.SortOrder)
+
+	return t, err
+}
+
 func (r repoTopic) ListBySubject(ctx context.Context, subjectID uuid.UUID) ([]entity.Topic, error) {
 	builder := r.Builder.
-		Select("t.id", "t.subject_id", "t.name", "t.is_active").
+		Select(_topicColumns).
 		From("topic t").
 		Where("t.subject_id = ?", subjectID).
-		OrderBy("t.name ASC")
+		OrderBy("t.sort_order ASC", "t.name ASC")
 
 	querySQL, args, err := builder.ToSql()
 	if err != nil {
@@ -153,8 +315,8 @@ func (r repoTopic) ListBySubject(ctx context.Context, subjectID uuid.UUID) ([]en
 
 	var topics []entity.Topic
 	for rows.Next() {
-		var t entity.Topic
-		if err := rows.Scan(&t.ID, &t.SubjectID, &t.Name, &t.IsActive); err != nil {
+		t, err := scanTopic(rows)
+		if err != nil {
 			return nil, fmt.Errorf("topic - ListBySubject - scan: %w", err)
 		}
 		topics = append(topics, t)
@@ -163,614 +325,4261 @@ func (r repoTopic) ListBySubject(ctx context.Context, subjectID uuid.UUID) ([]en
 	return topics, nil
 }
 
+func (r repoTopic) Get(ctx context.Context, id uuid.UUID) (entity.Topic, error) {
+	t, err := scanTopic(r.Pool.QueryRow(ctx, `SELECT `+_topicColumns+` FROM topic t WHERE t.id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Topic{}, repo.ErrNotFound
+	}
+	if err != nil {
+		return entity.Topic{}, fmt.Errorf("topic - Get - scan: %w", err)
+	}
+
+	return t, nil
+}
+
+// Create appends the topic after its existing siblings.
 func (r repoTopic) Create(ctx context.Context, topic entity.Topic) (entity.Topic, error) {
 	if topic.ID == uuid.Nil {
 		topic.ID = uuid.New()
 	}
-	return topic, nil
-}
-
-// repoQuestion implements QuestionRepository.
-type repoQuestion struct{ *postgres.Postgres }
-
-func (r repoQuestion) List(ctx context.Context, filter repo.QuestionFilter) ([]entity.Question, error) {
-	builder := r.Builder.
-		Select(
-			"q.id",
-			"e.code",
-			"q.subject_id",
-			"q.topic_id",
-			"q.question_text",
-			"q.option_a",
-			"q.option_b",
-			"q.option_c",
-			"q.option_d",
-			"q.correct_option",
-			"q.explanation",
-			"q.choice_type",
-			"q.difficulty_level",
-			"q.is_clinical",
-			"q.is_image_based",
-			"q.is_high_yield",
-			"q.is_active",
-		).
-		From("question q").
-		Join("exam_type_lookup e ON e.id = q.exam_type_id")
 
-	if filter.Exam != nil {
-		builder = builder.Where("e.code = ?", string(*filter.Exam))
+	err := r.Pool.QueryRow(ctx, `
+INSERT INTO topic (id, subject_id, parent_id, name, is_active, sort_order)
+SELECT $1, $2, $3, $4, $5, COALESCE(MAX(o.sort_order) + 1, 0)
+FROM topic o WHERE o.subject_id = $2 AND o.parent_id IS NOT DISTINCT FROM $3
+ON CONFLICT (subject_id, name) DO NOTHING
+RETURNING sort_order
+`, topic.ID, topic.SubjectID, topic.ParentID, topic.Name, topic.IsActive).Scan(&topic.SortOrder)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Topic{}, repo.ErrConflict
 	}
-	if filter.SubjectID != nil {
-		builder = builder.Where("q.subject_id = ?", *filter.SubjectID)
-	}
-	if filter.TopicID != nil {
-		builder = builder.Where("q.topic_id = ?", *filter.TopicID)
+	if err != nil {
+		return entity.Topic{}, fmt.Errorf("topic - Create - scan: %w", err)
 	}
 
-	builder = builder.OrderBy("q.question_text ASC")
+	return topic, nil
+}
 
-	querySQL, args, err := builder.ToSql()
+func (r repoTopic) Update(ctx context.Context, topic entity.Topic) (entity.Topic, error) {
+	tx, err := r.Pool.Begin(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("question - List - build: %w", err)
+		return entity.Topic{}, fmt.Errorf("topic - Update - begin: %w", err)
 	}
+	defer func() { _ = tx.Rollback(ctx) }()
 
-	rows, err := r.Pool.Query(ctx, querySQL, args...)
+	var active bool
+	err = tx.QueryRow(ctx, "SELECT is_active FROM topic WHERE id = $1 FOR UPDATE", topic.ID).Scan(&active)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Topic{}, repo.ErrNotFound
+	}
 	if err != nil {
-		return nil, fmt.Errorf("question - List - query: %w", err)
+		return entity.Topic{}, fmt.Errorf("topic - Update - lock: %w", err)
 	}
-	defer rows.Close()
 
-	var questions []entity.Question
-	for rows.Next() {
-		var q entity.Question
-		var choiceType string
-		if err := rows.Scan(
-			&q.ID,
-			&q.Exam,
-			&q.SubjectID,
-			&q.TopicID,
-			&q.QuestionText,
-			&q.OptionA,
-			&q.OptionB,
-			&q.OptionC,
-			&q.OptionD,
-			&q.CorrectOption,
-			&q.Explanation,
-			&choiceType,
-			&q.DifficultyLevel,
-			&q.IsClinical,
-			&q.IsImageBased,
-			&q.IsHighYield,
-			&q.IsActive,
-		); err != nil {
-			return nil, fmt.Errorf("question - List - scan: %w", err)
+	// Only a change of the flag cascades, so renaming a topic does not
+	// reactivate subtopics that were switched off on their own.
+	if active != topic.IsActive {
+		_, err = tx.Exec(ctx, _topicSubtree+`UPDATE topic SET is_active = $2 WHERE id IN (SELECT id FROM sub)`,
+			topic.ID, topic.IsActive)
+		if err != nil {
+			return entity.Topic{}, fmt.Errorf("topic - Update - cascade: %w", err)
 		}
-		q.ChoiceType = entity.QuestionChoiceType(choiceType)
-		questions = append(questions, q)
 	}
 
-	return questions, nil
-}
+	updated, err := scanTopic(tx.QueryRow(ctx, `
+UPDATE topic t SET name = $2, parent_id = $3
+WHERE t.id = $1 AND NOT EXISTS (SELECT 1 FROM topic o WHERE o.subject_id = t.subject_id AND o.name = $2 AND o.id <> $1)
+RETURNING `+_topicColumns, topic.ID, topic.Name, topic.ParentID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Topic{}, repo.ErrConflict
+	}
+	if err != nil {
+		return entity.Topic{}, fmt.Errorf("topic - Update - scan: %w", err)
+	}
 
-func (r repoQuestion) Create(ctx context.Context, question entity.Question) (entity.Question, error) {
-	if question.ID == uuid.Nil {
-		question.ID = uuid.New()
+	if err := tx.Commit(ctx); err != nil {
+		return entity.Topic{}, fmt.Errorf("topic - Update - commit: %w", err)
 	}
 
-	examTypeID, err := r.examTypeID(ctx, question.Exam)
+	return updated, nil
+}
+
+func (r repoTopic) Reorder(ctx context.Context, ids []uuid.UUID) error {
+	_, err := r.Pool.Exec(ctx, `
+UPDATE topic t SET sort_order = o.n - 1
+FROM unnest($1::uuid[]) WITH ORDINALITY AS o(id, n)
+WHERE t.id = o.id
+`, ids)
 	if err != nil {
-		return entity.Question{}, err
+		return fmt.Errorf("topic - Reorder - exec: %w", err)
 	}
 
-	sql, args, err := r.Builder.
-		Insert("question").
-		Columns(
-			"id", "exam_type_id", "subject_id", "topic_id", "question_text",
-			"option_a", "option_b", "option_c", "option_d", "correct_option",
-			"explanation", "choice_type", "difficulty_level",
-			"is_clinical", "is_image_based", "is_high_yield", "is_active",
-		).
-		Values(
-			question.ID, examTypeID, question.SubjectID, question.TopicID,
-			question.QuestionText, question.OptionA, question.OptionB, question.OptionC,
-			question.OptionD, question.CorrectOption, question.Explanation,
-			question.ChoiceType, question.DifficultyLevel, question.IsClinical,
-			question.IsImageBased, question.IsHighYield, question.IsActive,
-		).
-		ToSql()
+	return nil
+}
+
+// Delete only removes unused leaf topics; question versions count as use
+// because they keep pointing at the topic.
+func (r repoTopic) Delete(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.Pool.Exec(ctx, `
+DELETE FROM topic t
+WHERE t.id = $1
+  AND NOT EXISTS (SELECT 1 FROM topic c WHERE c.parent_id = t.id)
+  AND NOT EXISTS (SELECT 1 FROM question q WHERE q.topic_id = t.id)
+  AND NOT EXISTS (SELECT 1 FROM question_version v WHERE v.topic_id = t.id)
+  AND NOT EXISTS (SELECT 1 FROM podcast_episode p WHERE p.topic_id = t.id)
+`, id)
 	if err != nil {
-		return entity.Question{}, fmt.Errorf("question - Create - build: %w", err)
+		return fmt.Errorf("topic - Delete - exec: %w", err)
+	}
+	if tag.RowsAffected() > 0 {
+		return nil
 	}
 
-	_, err = r.Pool.Exec(ctx, sql, args...)
-	if err != nil {
-		return entity.Question{}, fmt.Errorf("question - Create - exec: %w", err)
+	var exists bool
+	if err := r.Pool.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM topic WHERE id = $1)", id).Scan(&exists); err != nil {
+		return fmt.Errorf("topic - Delete - exists: %w", err)
+	}
+	if exists {
+		return repo.ErrConflict
 	}
 
-	return question, nil
+	return repo.ErrNotFound
 }
 
-func (r repoQuestion) GetByID(ctx context.Context, id uuid.UUID) (entity.Question, error) {
-	return entity.Question{ID: id}, nil
-}
+// Merge folds source into target. Subtopics of source become children of
+// target; when target is in another subject the whole moved subtree, with its
+// questions, versions and podcasts, changes subject too. Revision items follow
+// their questions.
+func (r repoTopic) Merge(ctx context.Context, sourceID, targetID uuid.UUID) (entity.TopicMergeResult, error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return entity.TopicMergeResult{}, fmt.Errorf("topic - Merge - begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
 
-func (r repoQuestion) Update(ctx context.Context, question entity.Question) (entity.Question, error) {
-	return question, nil
-}
+	rows, err := tx.Query(ctx, "SELECT id, subject_id FROM topic WHERE id = ANY($1) FOR UPDATE", []uuid.UUID{sourceID, targetID})
+	if err != nil {
+		return entity.TopicMergeResult{}, fmt.Errorf("topic - Merge - lock: %w", err)
+	}
 
-func (r repoQuestion) Delete(ctx context.Context, id uuid.UUID) error {
-	return nil
-}
+	subjects := make(map[uuid.UUID]uuid.UUID, 2)
+	for rows.Next() {
+		var id, subjectID uuid.UUID
+		if err := rows.Scan(&id, &subjectID); err != nil {
+			rows.Close()
 
-func (r repoQuestion) examTypeID(ctx context.Context, exam entity.ExamCategory) (int, error) {
-	var id int
-	row := r.Pool.QueryRow(ctx, "SELECT id FROM exam_type_lookup WHERE code = $1", string(exam))
-	if err := row.Scan(&id); err != nil {
-		return 0, fmt.Errorf("question - examTypeID - scan: %w", err)
+			return entity.TopicMergeResult{}, fmt.Errorf("topic - Merge - scan: %w", err)
+		}
+		subjects[id] = subjectID
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return entity.TopicMergeResult{}, fmt.Errorf("topic - Merge - rows: %w", err)
+	}
+	if len(subjects) < 2 {
+		return entity.TopicMergeResult{}, repo.ErrNotFound
 	}
-	return id, nil
-}
 
-type repoLeaderboard struct{ *postgres.Postgres }
+	subjectID := subjects[targetID]
+	if subjects[sourceID] != subjectID {
+		var taken bool
+		err := tx.QueryRow(ctx, _topicSubtree+`
+SELECT EXISTS (
+  SELECT 1 FROM topic t JOIN topic o ON o.subject_id = $2 AND o.name = t.name
+  WHERE t.id IN (SELECT id FROM sub) AND t.id <> $1
+)`, sourceID, subjectID).Scan(&taken)
+		if err != nil {
+			return entity.TopicMergeResult{}, fmt.Errorf("topic - Merge - names: %w", err)
+		}
+		if taken {
+			return entity.TopicMergeResult{}, repo.ErrConflict
+		}
 
-func (r repoLeaderboard) List(ctx context.Context, limit int) ([]entity.LeaderboardEntry, error) {
-	builder := r.Builder.
-		Select(
-			`u.id`,
-			`u.display_name`,
-			`SUM(CASE WHEN a.is_correct THEN 1 ELSE 0 END) AS total_correct`,
-			`COUNT(*) AS total_attempt`,
-			`SUM(CASE WHEN a.is_correct THEN 1 ELSE 0 END) AS score`,
-			`COALESCE(SUM(CASE WHEN a.is_correct THEN 1 ELSE 0 END),0)*10 AS earned_rewards`,
-		).
-		From(`user_question_attempt a`).
-		Join(`"user" u ON u.id = a.user_id`).
-		GroupBy(`u.id`, `u.display_name`).
-		OrderBy(`score DESC`, `total_correct DESC`).
-		Limit(uint64(limit))
+		for _, stmt := range []string{
+			`UPDATE question SET subject_id = $2, updated_at = now() WHERE topic_id IN (SELECT id FROM sub) AND topic_id <> $1`,
+			`UPDATE question_version SET subject_id = $2 WHERE topic_id IN (SELECT id FROM sub) AND topic_id <> $1`,
+			`UPDATE podcast_episode SET subject_id = $2 WHERE topic_id IN (SELECT id FROM sub) AND topic_id <> $1`,
+			`UPDATE topic SET subject_id = $2 WHERE id IN (SELECT id FROM sub) AND id <> $1`,
+		} {
+			if _, err := tx.Exec(ctx, _topicSubtree+stmt, sourceID, subjectID); err != nil {
+				return entity.TopicMergeResult{}, fmt.Errorf("topic - Merge - move subtree: %w", err)
+			}
+		}
+	}
 
-	querySQL, args, err := builder.ToSql()
+	var result entity.TopicMergeResult
+
+	tag, err := tx.Exec(ctx, `UPDATE question SET topic_id = $2, subject_id = $3, updated_at = now() WHERE topic_id = $1`,
+		sourceID, targetID, subjectID)
 	if err != nil {
-		return nil, fmt.Errorf("leaderboard - List - build: %w", err)
+		return entity.TopicMergeResult{}, fmt.Errorf("topic - Merge - questions: %w", err)
 	}
+	result.Questions = int(tag.RowsAffected())
 
-	rows, err := r.Pool.Query(ctx, querySQL, args...)
+	_, err = tx.Exec(ctx, `UPDATE question_version SET topic_id = $2, subject_id = $3 WHERE topic_id = $1`,
+		sourceID, targetID, subjectID)
 	if err != nil {
-		return nil, fmt.Errorf("leaderboard - List - query: %w", err)
+		return entity.TopicMergeResult{}, fmt.Errorf("topic - Merge - versions: %w", err)
 	}
-	defer rows.Close()
 
-	var entries []entity.LeaderboardEntry
-	for rows.Next() {
-		var e entity.LeaderboardEntry
-		var earned int
-		if err := rows.Scan(
-			&e.ID,
-			&e.DisplayName,
-			&e.TotalCorrect,
-			&e.TotalAttempt,
-			&e.Score,
-			&earned,
-		); err != nil {
-			return nil, fmt.Errorf("leaderboard - List - scan: %w", err)
-		}
-		e.EarnedRewards = earned
-		e.DayStreak = 0
-		entries = append(entries, e)
+	tag, err = tx.Exec(ctx, `UPDATE podcast_episode SET topic_id = $2, subject_id = $3 WHERE topic_id = $1`,
+		sourceID, targetID, subjectID)
+	if err != nil {
+		return entity.TopicMergeResult{}, fmt.Errorf("topic - Merge - podcasts: %w", err)
 	}
+	result.Podcasts = int(tag.RowsAffected())
 
-	return entries, nil
-}
+	tag, err = tx.Exec(ctx, `UPDATE topic SET parent_id = $2 WHERE parent_id = $1`, sourceID, targetID)
+	if err != nil {
+		return entity.TopicMergeResult{}, fmt.Errorf("topic - Merge - subtopics: %w", err)
+	}
+	result.Subtopics = int(tag.RowsAffected())
 
-func (r repoLeaderboard) Stats(ctx context.Context) (entity.LeaderboardStats, error) {
-	builder := r.Builder.
-		Select(
-			"COUNT(DISTINCT user_id) AS total_users",
-			"COALESCE(AVG(CASE WHEN is_correct THEN 1.0 ELSE 0 END),0) AS average_accuracy",
-			"COALESCE(MAX(CASE WHEN is_correct THEN 1 ELSE 0 END), 0) AS range_value",
-		).
-		From("user_question_attempt")
+	if _, err := tx.Exec(ctx, `DELETE FROM topic WHERE id = $1`, sourceID); err != nil {
+		return entity.TopicMergeResult{}, fmt.Errorf("topic - Merge - delete: %w", err)
+	}
 
-	querySQL, args, err := builder.ToSql()
+	result.Target, err = scanTopic(tx.QueryRow(ctx, `SELECT `+_topicColumns+` FROM topic t WHERE t.id = $1`, targetID))
 	if err != nil {
-		return entity.LeaderboardStats{}, fmt.Errorf("leaderboard - Stats - build: %w", err)
+		return entity.TopicMergeResult{}, fmt.Errorf("topic - Merge - target: %w", err)
 	}
 
-	var stats entity.LeaderboardStats
-	var rangeValue int
-	if err := r.Pool.QueryRow(ctx, querySQL, args...).Scan(&stats.TotalUsers, &stats.AverageAccuracy, &rangeValue); err != nil {
-		return entity.LeaderboardStats{}, fmt.Errorf("leaderboard - Stats - query: %w", err)
+	if err := tx.Commit(ctx); err != nil {
+		return entity.TopicMergeResult{}, fmt.Errorf("topic - Merge - commit: %w", err)
 	}
-	stats.LeaderboardRange = fmt.Sprintf("Top %d", rangeValue)
 
-	return stats, nil
+	return result, nil
 }
 
-type repoFeed struct{ *postgres.Postgres }
+// repoQuestion implements QuestionRepository.
+type repoQuestion struct{ *postgres.Postgres }
 
-func (r repoFeed) List(ctx context.Context) ([]entity.FeedPost, error) {
-	rows, err := r.Pool.Query(ctx, `
-SELECT id, type, title, body, image_url, tags, created_at, author, cta, likes, comments, read_time
-FROM feed_post
-ORDER BY created_at DESC
-`)
-	if err != nil {
-		return nil, fmt.Errorf("feed - List - query: %w", err)
+func (r repoQuestion) selectQuestions() squirrel.SelectBuilder {
+	return r.Builder.
+		Select(append(append([]string{"q.id", "e.code"}, questionColumns("q")...),
+			"q.status", "q.reviewer_id", "q.difficulty_locked", _questionTagsColumn)...).
+		From("question q").
+		Join("exam_type_lookup e ON e.id = q.exam_type_id")
+}
+
+// questionColumns lists the content columns shared by question and
+// question_version, read from the table aliased as t. Difficulty is not
+// versioned, so it is always read from the question aliased as q.
+func questionColumns(t string) []string {
+	columns := []string{
+		"subject_id",
+		"topic_id",
+		"question_text",
+		"option_a",
+		"option_b",
+		"option_c",
+		"option_d",
+		"COALESCE(%s.correct_option, 0)",
+		"correct_options",
+		"reason_text",
+		"numeric_answer",
+		"numeric_tolerance",
+		"matrix",
+		"explanation",
+		"choice_type",
+		"q.difficulty_level",
+		"is_clinical",
+		"is_image_based",
+		"is_high_yield",
+		"is_active",
+		"version",
+	}
+	for i, column := range columns {
+		switch {
+		case strings.Contains(column, "%s"):
+			columns[i] = fmt.Sprintf(column, t)
+		case !strings.Contains(column, "."):
+			columns[i] = t + "." + column
+		}
 	}
-	defer rows.Close()
 
-	var posts []entity.FeedPost
-	for rows.Next() {
-		var p entity.FeedPost
-		var tagStr string
-		var image sql.NullString
-		if err := rows.Scan(
-			&p.ID,
-			&p.Type,
-			&p.Title,
-			&p.Body,
-			&image,
-			&tagStr,
-			&p.CreatedAt,
-			&p.Author,
-			&p.CTA,
-			&p.Likes,
-			&p.Comments,
-			&p.ReadTime,
-		); err != nil {
-			return nil, fmt.Errorf("feed - List - scan: %w", err)
-		}
-		if image.Valid {
-			p.ImageURL = image.String
+	return columns
+}
+
+func scanQuestion(row rowScanner) (entity.Question, error) {
+	var q entity.Question
+	var choiceType string
+	if err := row.Scan(questionDest(&q, &choiceType)...); err != nil {
+		return entity.Question{}, err
+	}
+	q.ChoiceType = entity.QuestionChoiceType(choiceType)
+
+	return q, nil
+}
+
+// questionDest lists scan targets for the columns of selectQuestions, so
+// queries that append their own columns can scan both in one call. The
+// workflow and calibration columns come last.
+func questionDest(q *entity.Question, choiceType *string) []any {
+	return []any{
+		&q.ID,
+		&q.Exam,
+		&q.SubjectID,
+		&q.TopicID,
+		&q.QuestionText,
+		&q.OptionA,
+		&q.OptionB,
+		&q.OptionC,
+		&q.OptionD,
+		&q.CorrectOption,
+		&q.CorrectOptions,
+		&q.ReasonText,
+		&q.NumericAnswer,
+		&q.NumericTolerance,
+		&q.Matrix,
+		&q.Explanation,
+		choiceType,
+		&q.DifficultyLevel,
+		&q.IsClinical,
+		&q.IsImageBased,
+		&q.IsHighYield,
+		&q.IsActive,
+		&q.Version,
+		&q.Status,
+		&q.ReviewerID,
+		&q.DifficultyLocked,
+		&q.Tags,
+	}
+}
+
+// nullableOption stores questions without an option key, such as numerical
+// ones, with a NULL correct_option.
+func nullableOption(option int) *int {
+	if option == 0 {
+		return nil
+	}
+
+	return &option
+}
+
+// matrixAnswer keeps a missing matrix answer NULL instead of JSON null.
+func matrixAnswer(answer [][]int) any {
+	if answer == nil {
+		return nil
+	}
+
+	return answer
+}
+
+func filterQuestions(builder squirrel.SelectBuilder, filter repo.QuestionFilter) squirrel.SelectBuilder {
+	if filter.Exam != nil {
+		builder = builder.Where("e.code = ?", string(*filter.Exam))
+	}
+	if filter.SubjectID != nil {
+		builder = builder.Where("q.subject_id = ?", *filter.SubjectID)
+	}
+	if filter.TopicID != nil {
+		builder = builder.Where("q.topic_id = ?", *filter.TopicID)
+	}
+	if filter.IsActive != nil {
+		builder = builder.Where("q.is_active = ?", *filter.IsActive)
+	}
+	if filter.IsHighYield != nil {
+		builder = builder.Where("q.is_high_yield = ?", *filter.IsHighYield)
+	}
+	if filter.IsClinical != nil {
+		builder = builder.Where("q.is_clinical = ?", *filter.IsClinical)
+	}
+	if filter.IsImageBased != nil {
+		builder = builder.Where("q.is_image_based = ?", *filter.IsImageBased)
+	}
+	if len(filter.DifficultyLevels) > 0 {
+		builder = builder.Where(squirrel.Eq{"q.difficulty_level": filter.DifficultyLevels})
+	}
+	if len(filter.ChoiceTypes) > 0 {
+		types := make([]string, len(filter.ChoiceTypes))
+		for i, t := range filter.ChoiceTypes {
+			types[i] = string(t)
 		}
-		if tagStr != "" {
-			p.Tags = strings.Split(tagStr, ",")
+		builder = builder.Where(squirrel.Eq{"q.choice_type::text": types})
+	}
+	if len(filter.Statuses) > 0 {
+		statuses := make([]string, len(filter.Statuses))
+		for i, status := range filter.Statuses {
+			statuses[i] = string(status)
 		}
-		posts = append(posts, p)
+		builder = builder.Where(squirrel.Eq{"q.status": statuses})
+	}
+	if filter.ReviewerID != nil {
+		builder = builder.Where("q.reviewer_id = ?", *filter.ReviewerID)
+	}
+	if filter.Search != "" {
+		builder = builder.Where("q.search @@ websearch_to_tsquery('english', ?)", filter.Search)
+	}
+	if filter.CreatedFrom != nil {
+		builder = builder.Where("q.created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		builder = builder.Where("q.created_at < ?", *filter.CreatedTo)
+	}
+	if len(filter.Tags) > 0 {
+		builder = whereTagged(builder, filter.Tags)
 	}
 
-	return posts, nil
+	return builder
 }
 
-// repoPracticeSession implements PracticeSessionRepository.
-type repoPracticeSession struct{ *postgres.Postgres }
+// _questionTagsColumn reads the sorted tag names of the question aliased q.
+const _questionTagsColumn = `ARRAY(
+	SELECT t.name FROM question_tag qt JOIN tag t ON t.id = qt.tag_id
+	WHERE qt.question_id = q.id ORDER BY lower(t.name)
+) AS tags`
 
-func (r repoPracticeSession) CreateSession(ctx context.Context, session entity.PracticeSession) (entity.PracticeSession, error) {
-	if session.ID == uuid.Nil {
-		session.ID = uuid.New()
-	}
-	if session.Status == "" {
-		session.Status = entity.PracticeStatusInProgress
+// whereTagged keeps the questions aliased q that carry every named tag.
+func whereTagged(builder squirrel.SelectBuilder, names []string) squirrel.SelectBuilder {
+	lowered := lowerNames(names)
+
+	return builder.Where(`q.id IN (
+	SELECT qt.question_id FROM question_tag qt JOIN tag t ON t.id = qt.tag_id
+	WHERE lower(t.name) = ANY(?)
+	GROUP BY qt.question_id
+	HAVING COUNT(*) = ?
+)`, lowered, len(lowered))
+}
+
+// lowerNames lowercases tag names and drops duplicates.
+func lowerNames(names []string) []string {
+	out := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.ToLower(name)
+		if !slices.Contains(out, name) {
+			out = append(out, name)
+		}
 	}
-	if session.StartedAt.IsZero() {
-		session.StartedAt = time.Now().UTC()
+
+	return out
+}
+
+// questionSortKey returns the expression a question list is sorted by, with
+// its arguments and the type its text form is cast back to in a cursor.
+func questionSortKey(sort entity.QuestionSort, filter repo.QuestionFilter) (expr string, args []any, cast string) {
+	switch sort {
+	case entity.QuestionSortDifficulty:
+		return "q.difficulty_level", nil, "smallint"
+	case entity.QuestionSortText:
+		return "q.question_text", nil, "text"
+	case entity.QuestionSortRelevance:
+		return "ts_rank(q.search, websearch_to_tsquery('english', ?))", []any{filter.Search}, "real"
+	default:
+		return "q.created_at", nil, "timestamptz"
 	}
-	return session, nil
 }
 
-func (r repoPracticeSession) ListSessions(ctx context.Context, userID uuid.UUID) ([]entity.PracticeSession, error) {
-	builder := r.Builder.
-		Select("ps.id", "e.code", "ps.mode", "ps.status", "ps.total_questions_planned", "ps.started_at", "ps.completed_at").
-		From("practice_session ps").
-		Join("exam_type_lookup e ON e.id = ps.exam_type_id").
-		Where("ps.user_id = ?", userID).
-		OrderBy("ps.started_at DESC")
+func (r repoQuestion) List(ctx context.Context, filter repo.QuestionFilter, page repo.QuestionPage) ([]entity.QuestionListItem, error) {
+	key, keyArgs, cast := questionSortKey(page.Sort, filter)
+
+	direction, compare := "ASC", ">"
+	if page.Desc {
+		direction, compare = "DESC", "<"
+	}
+
+	builder := filterQuestions(r.selectQuestions(), filter).
+		Column("q.created_at").
+		Column("q.updated_at").
+		Column(squirrel.Expr("("+key+")::text", keyArgs...)).
+		OrderByClause(key+" "+direction, keyArgs...).
+		OrderBy("q.id " + direction).
+		Limit(uint64(page.Limit))
+	if page.After != nil {
+		builder = builder.Where(
+			"("+key+", q.id) "+compare+" (?::"+cast+", ?)",
+			append(keyArgs, page.After.Key, page.After.ID)...,
+		)
+	}
 
 	querySQL, args, err := builder.ToSql()
 	if err != nil {
-		return nil, fmt.Errorf("practice - ListSessions - build: %w", err)
+		return nil, fmt.Errorf("question - List - build: %w", err)
 	}
 
 	rows, err := r.Pool.Query(ctx, querySQL, args...)
 	if err != nil {
-		return nil, fmt.Errorf("practice - ListSessions - query: %w", err)
+		return nil, fmt.Errorf("question - List - query: %w", err)
 	}
 	defer rows.Close()
 
-	var sessions []entity.PracticeSession
+	var items []entity.QuestionListItem
 	for rows.Next() {
-		var s entity.PracticeSession
-		var examCode string
-		if err := rows.Scan(&s.ID, &examCode, &s.Mode, &s.Status, &s.TotalQuestionsPlanned, &s.StartedAt, &s.CompletedAt); err != nil {
-			return nil, fmt.Errorf("practice - ListSessions - scan: %w", err)
+		var item entity.QuestionListItem
+		var choiceType string
+		dest := append(questionDest(&item.Question, &choiceType), &item.CreatedAt, &item.UpdatedAt, &item.SortKey)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("question - List - scan: %w", err)
 		}
-		s.Exam = entity.ExamCategory(examCode)
-		sessions = append(sessions, s)
+		item.ChoiceType = entity.QuestionChoiceType(choiceType)
+		items = append(items, item)
 	}
 
-	return sessions, nil
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("question - List - rows: %w", err)
+	}
+
+	return items, nil
 }
 
-func (r repoPracticeSession) GetSession(ctx context.Context, id uuid.UUID) (entity.PracticeSession, error) {
-	builder := r.Builder.
-		Select("ps.id", "e.code", "ps.mode", "ps.status", "ps.total_questions_planned", "ps.started_at", "ps.completed_at").
-		From("practice_session ps").
-		Join("exam_type_lookup e ON e.id = ps.exam_type_id").
-		Where("ps.id = ?", id).
-		Limit(1)
+func (r repoQuestion) Count(ctx context.Context, filter repo.QuestionFilter) (int, error) {
+	builder := filterQuestions(r.Builder.
+		Select("count(*)").
+		From("question q").
+		Join("exam_type_lookup e ON e.id = q.exam_type_id"), filter)
 
 	querySQL, args, err := builder.ToSql()
 	if err != nil {
-		return entity.PracticeSession{}, fmt.Errorf("practice - GetSession - build: %w", err)
+		return 0, fmt.Errorf("question - Count - build: %w", err)
 	}
 
-	var s entity.PracticeSession
-	var examCode string
-	row := r.Pool.QueryRow(ctx, querySQL, args...)
-	if err := row.Scan(&s.ID, &examCode, &s.Mode, &s.Status, &s.TotalQuestionsPlanned, &s.StartedAt, &s.CompletedAt); err != nil {
-		return entity.PracticeSession{}, fmt.Errorf("practice - GetSession - scan: %w", err)
+	var total int
+	if err := r.Pool.QueryRow(ctx, querySQL, args...).Scan(&total); err != nil {
+		return 0, fmt.Errorf("question - Count - scan: %w", err)
 	}
-	s.Exam = entity.ExamCategory(examCode)
-	return s, nil
-}
 
-func (r repoPracticeSession) ListSessionQuestions(ctx context.Context, sessionID uuid.UUID) ([]entity.PracticeSessionQuestion, error) {
-	builder := r.Builder.
-		Select(
-			"psq.id",
-			"psq.sequence_index",
-			"psq.selected_option",
-			"psq.is_correct",
-			"psq.time_taken_ms",
-			"q.id",
-			"e.code",
-			"q.subject_id",
-			"q.topic_id",
-			"q.question_text",
-			"q.option_a",
-			"q.option_b",
-			"q.option_c",
-			"q.option_d",
-			"q.correct_option",
-			"q.explanation",
-			"q.difficulty_level",
-			"q.choice_type",
-			"q.is_clinical",
-			"q.is_image_based",
-			"q.is_high_yield",
-			"q.is_active",
-		).
-		From("practice_session_question psq").
-		Join("question q ON q.id = psq.question_id").
-		Join("exam_type_lookup e ON e.id = q.exam_type_id").
-		Where("psq.session_id = ?", sessionID).
-		OrderBy("psq.sequence_index ASC")
+	return total, nil
+}
 
-	querySQL, args, err := builder.ToSql()
+func (r repoQuestion) Stream(ctx context.Context, filter repo.QuestionFilter, fn func(entity.Question) error) error {
+	querySQL, args, err := filterQuestions(r.selectQuestions(), filter).
+		OrderBy("q.subject_id", "q.topic_id", "q.id").
+		ToSql()
 	if err != nil {
-		return nil, fmt.Errorf("practice - ListSessionQuestions - build: %w", err)
+		return fmt.Errorf("question - Stream - build: %w", err)
 	}
 
 	rows, err := r.Pool.Query(ctx, querySQL, args...)
 	if err != nil {
-		return nil, fmt.Errorf("practice - ListSessionQuestions - query: %w", err)
+		return fmt.Errorf("question - Stream - query: %w", err)
 	}
 	defer rows.Close()
 
-	var questions []entity.PracticeSessionQuestion
 	for rows.Next() {
-		var psq entity.PracticeSessionQuestion
-		var q entity.Question
-		var examCode string
-		var choiceType string
-		var explanation sql.NullString
-		var selectedOption sql.NullInt32
-		var timeTaken sql.NullInt32
-		if err := rows.Scan(
-			&psq.ID,
-			&psq.SequenceIndex,
-			&selectedOption,
-			&psq.IsCorrect,
-			&timeTaken,
-			&q.ID,
-			&examCode,
-			&q.SubjectID,
-			&q.TopicID,
-			&q.QuestionText,
-			&q.OptionA,
-			&q.OptionB,
-			&q.OptionC,
-			&q.OptionD,
-			&q.CorrectOption,
-			&explanation,
-			&q.DifficultyLevel,
-			&choiceType,
-			&q.IsClinical,
-			&q.IsImageBased,
-			&q.IsHighYield,
-			&q.IsActive,
-		); err != nil {
-			return nil, fmt.Errorf("practice - ListSessionQuestions - scan: %w", err)
-		}
-		if selectedOption.Valid {
-			val := int(selectedOption.Int32)
-			psq.SelectedOption = &val
-		}
-		if timeTaken.Valid {
-			val := int(timeTaken.Int32)
-			psq.TimeTakenMs = &val
+		q, err := scanQuestion(rows)
+		if err != nil {
+			return fmt.Errorf("question - Stream - scan: %w", err)
 		}
-		if explanation.Valid {
-			q.Explanation = &explanation.String
+		if err := fn(q); err != nil {
+			return err
 		}
-		q.Exam = entity.ExamCategory(examCode)
-		q.ChoiceType = entity.QuestionChoiceType(choiceType)
-		psq.Question = q
-		questions = append(questions, psq)
 	}
 
-	return questions, nil
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("question - Stream - rows: %w", err)
+	}
+
+	return nil
+}
+
+func (r repoQuestion) Create(ctx context.Context, version entity.QuestionVersion) (entity.Question, error) {
+	question := version.Question
+	if question.ID == uuid.Nil {
+		question.ID = uuid.New()
+	}
+	question.Version = 1
+
+	examTypeID, err := r.examTypeID(ctx, question.Exam)
+	if err != nil {
+		return entity.Question{}, err
+	}
+
+	sql, args, err := r.Builder.
+		Insert("question").
+		Columns(
+			"id", "exam_type_id", "subject_id", "topic_id", "question_text",
+			"option_a", "option_b", "option_c", "option_d", "correct_option",
+			"correct_options", "reason_text", "numeric_answer", "numeric_tolerance", "matrix",
+			"explanation", "choice_type", "difficulty_level",
+			"is_clinical", "is_image_based", "is_high_yield", "version", "status", "reviewer_id",
+		).
+		Values(
+			question.ID, examTypeID, question.SubjectID, question.TopicID,
+			question.QuestionText, question.OptionA, question.OptionB, question.OptionC,
+			question.OptionD, nullableOption(question.CorrectOption), question.CorrectOptions,
+			question.ReasonText, question.NumericAnswer, question.NumericTolerance, question.Matrix,
+			question.Explanation,
+			question.ChoiceType, question.DifficultyLevel, question.IsClinical,
+			question.IsImageBased, question.IsHighYield, question.Version, question.Status, question.ReviewerID,
+		).
+		ToSql()
+	if err != nil {
+		return entity.Question{}, fmt.Errorf("question - Create - build: %w", err)
+	}
+
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return entity.Question{}, fmt.Errorf("question - Create - begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, sql, args...); err != nil {
+		return entity.Question{}, fmt.Errorf("question - Create - exec: %w", err)
+	}
+	question.IsActive = question.Status == entity.QuestionStatusPublished
+
+	version.Question = question
+	if err := r.insertVersion(ctx, tx, version); err != nil {
+		return entity.Question{}, fmt.Errorf("question - Create - %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return entity.Question{}, fmt.Errorf("question - Create - commit: %w", err)
+	}
+
+	return question, nil
+}
+
+func (r repoQuestion) GetByID(ctx context.Context, id uuid.UUID) (entity.Question, error) {
+	querySQL, args, err := r.selectQuestions().Where("q.id = ?", id).ToSql()
+	if err != nil {
+		return entity.Question{}, fmt.Errorf("question - GetByID - build: %w", err)
+	}
+
+	q, err := scanQuestion(r.Pool.QueryRow(ctx, querySQL, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Question{}, repo.ErrNotFound
+	}
+	if err != nil {
+		return entity.Question{}, fmt.Errorf("question - GetByID - scan: %w", err)
+	}
+
+	return q, nil
+}
+
+func (r repoQuestion) Update(ctx context.Context, version entity.QuestionVersion, difficulty *int) (entity.Question, error) {
+	question := version.Question
+	content := len(version.ChangedFields) > 0
+
+	builder := r.Builder.
+		Update("question").
+		Set("difficulty_level", squirrel.Expr("COALESCE(?::smallint, difficulty_level)", difficulty)).
+		Set("updated_at", squirrel.Expr("now()"))
+	if content {
+		builder = builder.
+			Set("subject_id", question.SubjectID).
+			Set("topic_id", question.TopicID).
+			Set("question_text", question.QuestionText).
+			Set("option_a", question.OptionA).
+			Set("option_b", question.OptionB).
+			Set("option_c", question.OptionC).
+			Set("option_d", question.OptionD).
+			Set("correct_option", nullableOption(question.CorrectOption)).
+			Set("correct_options", question.CorrectOptions).
+			Set("reason_text", question.ReasonText).
+			Set("numeric_answer", question.NumericAnswer).
+			Set("numeric_tolerance", question.NumericTolerance).
+			Set("matrix", question.Matrix).
+			Set("explanation", question.Explanation).
+			Set("choice_type", question.ChoiceType).
+			Set("is_clinical", question.IsClinical).
+			Set("is_image_based", question.IsImageBased).
+			Set("is_high_yield", question.IsHighYield).
+			Set("status", question.Status).
+			Set("version", squirrel.Expr("version + 1"))
+	}
+
+	querySQL, args, err := builder.
+		Where("id = ? AND version = ?", question.ID, question.Version).
+		Suffix("RETURNING version, is_active, difficulty_level").
+		ToSql()
+	if err != nil {
+		return entity.Question{}, fmt.Errorf("question - Update - build: %w", err)
+	}
+
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return entity.Question{}, fmt.Errorf("question - Update - begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	err = tx.QueryRow(ctx, querySQL, args...).Scan(&question.Version, &question.IsActive, &question.DifficultyLevel)
+	if errors.Is(err, pgx.ErrNoRows) {
+		var exists bool
+		if err := tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM question WHERE id = $1)", question.ID).Scan(&exists); err != nil {
+			return entity.Question{}, fmt.Errorf("question - Update - exists: %w", err)
+		}
+		if exists {
+			return entity.Question{}, repo.ErrConflict
+		}
+
+		return entity.Question{}, repo.ErrNotFound
+	}
+	if err != nil {
+		return entity.Question{}, fmt.Errorf("question - Update - exec: %w", err)
+	}
+
+	version.Question = question
+	if content {
+		if err := r.insertVersion(ctx, tx, version); err != nil {
+			return entity.Question{}, fmt.Errorf("question - Update - %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return entity.Question{}, fmt.Errorf("question - Update - commit: %w", err)
+	}
+
+	return question, nil
+}
+
+// insertVersion stores version.Question as version.Question.Version.
+func (r repoQuestion) insertVersion(ctx context.Context, tx pgx.Tx, version entity.QuestionVersion) error {
+	q := version.Question
+
+	changed := version.ChangedFields
+	if changed == nil {
+		changed = []string{}
+	}
+
+	querySQL, args, err := r.Builder.
+		Insert("question_version").
+		Columns(
+			"question_id", "version", "subject_id", "topic_id", "question_text",
+			"option_a", "option_b", "option_c", "option_d", "correct_option",
+			"correct_options", "reason_text", "numeric_answer", "numeric_tolerance", "matrix",
+			"explanation", "choice_type",
+			"is_clinical", "is_image_based", "is_high_yield", "is_active",
+			"changed_fields", "restored_from", "author_id",
+		).
+		Values(
+			q.ID, q.Version, q.SubjectID, q.TopicID, q.QuestionText,
+			q.OptionA, q.OptionB, q.OptionC, q.OptionD, nullableOption(q.CorrectOption),
+			q.CorrectOptions, q.ReasonText, q.NumericAnswer, q.NumericTolerance, q.Matrix,
+			q.Explanation, q.ChoiceType,
+			q.IsClinical, q.IsImageBased, q.IsHighYield, q.IsActive,
+			changed, version.RestoredFrom, version.AuthorID,
+		).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("build version: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, querySQL, args...); err != nil {
+		return fmt.Errorf("insert version: %w", err)
+	}
+
+	return nil
+}
+
+func (r repoQuestion) selectVersions() squirrel.SelectBuilder {
+	return r.Builder.
+		Select(append(append([]string{"v.question_id", "e.code"}, questionColumns("v")...),
+			// Versions have no workflow state.
+			"'' AS status", "NULL::uuid AS reviewer_id", "false AS difficulty_locked", "'{}'::text[] AS tags",
+			"v.changed_fields", "v.restored_from", "v.author_id", "v.created_at")...).
+		From("question_version v").
+		Join("question q ON q.id = v.question_id").
+		Join("exam_type_lookup e ON e.id = q.exam_type_id")
+}
+
+func scanQuestionVersion(row rowScanner) (entity.QuestionVersion, error) {
+	var v entity.QuestionVersion
+	var choiceType string
+	dest := append(questionDest(&v.Question, &choiceType),
+		&v.ChangedFields,
+		&v.RestoredFrom,
+		&v.AuthorID,
+		&v.CreatedAt,
+	)
+	if err := row.Scan(dest...); err != nil {
+		return entity.QuestionVersion{}, err
+	}
+	v.Question.ChoiceType = entity.QuestionChoiceType(choiceType)
+	v.QuestionID = v.Question.ID
+	v.Version = v.Question.Version
+
+	return v, nil
+}
+
+func (r repoQuestion) ListVersions(ctx context.Context, questionID uuid.UUID) ([]entity.QuestionVersion, error) {
+	querySQL, args, err := r.selectVersions().
+		Where("v.question_id = ?", questionID).
+		OrderBy("v.version DESC").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("question - ListVersions - build: %w", err)
+	}
+
+	rows, err := r.Pool.Query(ctx, querySQL, args...)
+	if err != nil {
+		return nil, fmt.Errorf("question - ListVersions - query: %w", err)
+	}
+	defer rows.Close()
+
+	var versions []entity.QuestionVersion
+	for rows.Next() {
+		v, err := scanQuestionVersion(rows)
+		if err != nil {
+			return nil, fmt.Errorf("question - ListVersions - scan: %w", err)
+		}
+		versions = append(versions, v)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("question - ListVersions - rows: %w", err)
+	}
+
+	return versions, nil
+}
+
+func (r repoQuestion) GetVersion(ctx context.Context, questionID uuid.UUID, version int) (entity.QuestionVersion, error) {
+	querySQL, args, err := r.selectVersions().
+		Where("v.question_id = ? AND v.version = ?", questionID, version).
+		ToSql()
+	if err != nil {
+		return entity.QuestionVersion{}, fmt.Errorf("question - GetVersion - build: %w", err)
+	}
+
+	v, err := scanQuestionVersion(r.Pool.QueryRow(ctx, querySQL, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.QuestionVersion{}, repo.ErrNotFound
+	}
+	if err != nil {
+		return entity.QuestionVersion{}, fmt.Errorf("question - GetVersion - scan: %w", err)
+	}
+
+	return v, nil
+}
+
+func (r repoQuestion) Transition(ctx context.Context, id uuid.UUID, from, to entity.QuestionStatus, reviewerID *uuid.UUID) (entity.Question, error) {
+	tag, err := r.Pool.Exec(ctx, `
+UPDATE question
+SET status = $3, reviewer_id = $4, updated_at = now()
+WHERE id = $1 AND status = $2
+`, id, string(from), string(to), reviewerID)
+	if err != nil {
+		return entity.Question{}, fmt.Errorf("question - Transition - exec: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		var exists bool
+		if err := r.Pool.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM question WHERE id = $1)", id).Scan(&exists); err != nil {
+			return entity.Question{}, fmt.Errorf("question - Transition - exists: %w", err)
+		}
+		if exists {
+			return entity.Question{}, repo.ErrConflict
+		}
+
+		return entity.Question{}, repo.ErrNotFound
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+func (r repoQuestion) SetDifficulty(ctx context.Context, id uuid.UUID, level *int, locked bool) (entity.Question, error) {
+	tag, err := r.Pool.Exec(ctx, `
+UPDATE question
+SET difficulty_level = COALESCE($2, difficulty_level), difficulty_locked = $3, updated_at = now()
+WHERE id = $1
+`, id, level, locked)
+	if err != nil {
+		return entity.Question{}, fmt.Errorf("question - SetDifficulty - exec: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return entity.Question{}, repo.ErrNotFound
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+func (r repoQuestion) Delete(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+// _seenQuestionsJoin exposes, per question, the last time the user was served or attempted it.
+const _seenQuestionsJoin = `LEFT JOIN (
+	SELECT question_id, MAX(seen_at) AS last_seen
+	FROM (
+		SELECT a.question_id, a.created_at AS seen_at
+		FROM user_question_attempt a
+		WHERE a.user_id = ?
+		UNION ALL
+		SELECT psq.question_id, ps.started_at AS seen_at
+		FROM practice_session_question psq
+		JOIN practice_session ps ON ps.id = psq.session_id
+		WHERE ps.user_id = ?
+	) history
+	GROUP BY question_id
+) seen ON seen.question_id = q.id`
+
+func (r repoQuestion) ListPoolIDs(ctx context.Context, filter repo.QuestionPoolFilter) ([]uuid.UUID, error) {
+	builder := r.Builder.
+		Select("q.id").
+		From("question q").
+		Join("exam_type_lookup e ON e.id = q.exam_type_id").
+		Join("subject sj ON sj.id = q.subject_id AND sj.is_active").
+		Join("topic tp ON tp.id = q.topic_id AND tp.is_active").
+		JoinClause(_seenQuestionsJoin, filter.UserID, filter.UserID).
+		Where("q.status = 'published'").
+		Where("e.code = ?", string(filter.Exam))
+
+	if len(filter.SubjectIDs) > 0 {
+		builder = builder.Where(squirrel.Eq{"q.subject_id": filter.SubjectIDs})
+	}
+	if len(filter.TopicIDs) > 0 {
+		builder = builder.Where("q.topic_id IN "+_topicsUnder, filter.TopicIDs)
+	}
+	if len(filter.DifficultyLevels) > 0 {
+		builder = builder.Where(squirrel.Eq{"q.difficulty_level": filter.DifficultyLevels})
+	}
+	if len(filter.ExcludeIDs) > 0 {
+		builder = builder.Where(squirrel.NotEq{"q.id": filter.ExcludeIDs})
+	}
+	if filter.SeenBefore != nil {
+		builder = builder.Where("(seen.last_seen IS NULL OR seen.last_seen < ?)", *filter.SeenBefore)
+	}
+	if filter.Unseen {
+		builder = builder.Where("seen.last_seen IS NULL")
+	}
+	if len(filter.Tags) > 0 {
+		builder = whereTagged(builder, filter.Tags)
+	}
+	if filter.PYQ {
+		pyq := "SELECT 1 FROM question_tag qt JOIN tag t ON t.id = qt.tag_id WHERE qt.question_id = q.id AND t.kind = 'pyq'"
+		var args []any
+		if len(filter.PYQYears) > 0 {
+			pyq += " AND t.year = ANY(?)"
+			args = append(args, filter.PYQYears)
+		}
+		builder = builder.Where("EXISTS ("+pyq+")", args...)
+	}
+
+	// Never-seen questions first, then the ones seen longest ago; random within each tier.
+	builder = builder.OrderBy("seen.last_seen ASC NULLS FIRST", "random()")
+	if filter.Limit > 0 {
+		builder = builder.Limit(uint64(filter.Limit))
+	}
+
+	querySQL, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("question - ListPoolIDs - build: %w", err)
+	}
+
+	rows, err := r.Pool.Query(ctx, querySQL, args...)
+	if err != nil {
+		return nil, fmt.Errorf("question - ListPoolIDs - query: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("question - ListPoolIDs - scan: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func (r repoQuestion) ExistingIDs(ctx context.Context, ids []uuid.UUID) ([]uuid.UUID, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	rows, err := r.Pool.Query(ctx, "SELECT id FROM question WHERE id = ANY($1)", ids)
+	if err != nil {
+		return nil, fmt.Errorf("question - ExistingIDs - query: %w", err)
+	}
+	defer rows.Close()
+
+	var existing []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("question - ExistingIDs - scan: %w", err)
+		}
+		existing = append(existing, id)
+	}
+
+	return existing, nil
+}
+
+func (r repoQuestion) examTypeID(ctx context.Context, exam entity.ExamCategory) (int, error) {
+	var id int
+	row := r.Pool.QueryRow(ctx, "SELECT id FROM exam_type_lookup WHERE code = $1", string(exam))
+	if err := row.Scan(&id); err != nil {
+		return 0, fmt.Errorf("question - examTypeID - scan: %w", err)
+	}
+	return id, nil
+}
+
+type repoLeaderboard struct{ *postgres.Postgres }
+
+func (r repoLeaderboard) List(ctx context.Context, limit int) ([]entity.LeaderboardEntry, error) {
+	builder := r.Builder.
+		Select(
+			`u.id`,
+			`u.display_name`,
+			`SUM(CASE WHEN a.is_correct THEN 1 ELSE 0 END) AS total_correct`,
+			`COUNT(*) AS total_attempt`,
+			`SUM(CASE WHEN a.is_correct THEN 1 ELSE 0 END) AS score`,
+			`COALESCE(SUM(CASE WHEN a.is_correct THEN 1 ELSE 0 END),0)*10 AS earned_rewards`,
+		).
+		From(`user_question_attempt a`).
+		Join(`"user" u ON u.id = a.user_id`).
+		GroupBy(`u.id`, `u.display_name`).
+		OrderBy(`score DESC`, `total_correct DESC`).
+		Limit(uint64(limit))
+
+	querySQL, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("leaderboard - List - build: %w", err)
+	}
+
+	rows, err := r.Pool.Query(ctx, querySQL, args...)
+	if err != nil {
+		return nil, fmt.Errorf("leaderboard - List - query: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []entity.LeaderboardEntry
+	for rows.Next() {
+		var e entity.LeaderboardEntry
+		var earned int
+		if err := rows.Scan(
+			&e.ID,
+			&e.DisplayName,
+			&e.TotalCorrect,
+			&e.TotalAttempt,
+			&e.Score,
+			&earned,
+		); err != nil {
+			return nil, fmt.Errorf("leaderboard - List - scan: %w", err)
+		}
+		e.EarnedRewards = earned
+		e.DayStreak = 0
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
+
+func (r repoLeaderboard) Stats(ctx context.Context) (entity.LeaderboardStats, error) {
+	builder := r.Builder.
+		Select(
+			"COUNT(DISTINCT user_id) AS total_users",
+			"COALESCE(AVG(CASE WHEN is_correct THEN 1.0 ELSE 0 END),0) AS average_accuracy",
+			"COALESCE(MAX(CASE WHEN is_correct THEN 1 ELSE 0 END), 0) AS range_value",
+		).
+		From("user_question_attempt")
+
+	querySQL, args, err := builder.ToSql()
+	if err != nil {
+		return entity.LeaderboardStats{}, fmt.Errorf("leaderboard - Stats - build: %w", err)
+	}
+
+	var stats entity.LeaderboardStats
+	var rangeValue int
+	if err := r.Pool.QueryRow(ctx, querySQL, args...).Scan(&stats.TotalUsers, &stats.AverageAccuracy, &rangeValue); err != nil {
+		return entity.LeaderboardStats{}, fmt.Errorf("leaderboard - Stats - query: %w", err)
+	}
+	stats.LeaderboardRange = fmt.Sprintf("Top %d", rangeValue)
+
+	return stats, nil
+}
+
+type repoFeed struct{ *postgres.Postgres }
+
+func (r repoFeed) List(ctx context.Context) ([]entity.FeedPost, error) {
+	rows, err := r.Pool.Query(ctx, `
+SELECT id, type, title, body, image_url, tags, created_at, author, cta, likes, comments, read_time
+FROM feed_post
+ORDER BY created_at DESC
+`)
+	if err != nil {
+		return nil, fmt.Errorf("feed - List - query: %w", err)
+	}
+	defer rows.Close()
+
+	var posts []entity.FeedPost
+	for rows.Next() {
+		var p entity.FeedPost
+		var tagStr string
+		var image sql.NullString
+		if err := rows.Scan(
+			&p.ID,
+			&p.Type,
+			&p.Title,
+			&p.Body,
+			&image,
+			&tagStr,
+			&p.CreatedAt,
+			&p.Author,
+			&p.CTA,
+			&p.Likes,
+			&p.Comments,
+			&p.ReadTime,
+		); err != nil {
+			return nil, fmt.Errorf("feed - List - scan: %w", err)
+		}
+		if image.Valid {
+			p.ImageURL = image.String
+		}
+		if tagStr != "" {
+			p.Tags = strings.Split(tagStr, ",")
+		}
+		posts = append(posts, p)
+	}
+
+	return posts, nil
+}
+
+// repoPracticeSession implements PracticeSessionRepository.
+type repoPracticeSession struct{ *postgres.Postgres }
+
+// rowScanner is satisfied by both pgx.Row and pgx.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanPracticeSession(row rowScanner) (entity.PracticeSession, error) {
+	var s entity.PracticeSession
+	var examCode string
+	var mode, status sql.NullString
+	if err := row.Scan(
+		&s.ID,
+		&s.UserID,
+		&examCode,
+		&mode,
+		&status,
+		&s.TotalQuestionsPlanned,
+		&s.TimeLimitMinutes,
+		&s.StartedAt,
+		&s.CompletedAt,
+	); err != nil {
+		return entity.PracticeSession{}, err
+	}
+	s.Exam = entity.ExamCategory(examCode)
+	s.Mode = entity.PracticeMode(mode.String)
+	s.Status = entity.PracticeSessionStatus(status.String)
+
+	return s, nil
+}
+
+// rationaleColumns splits a rationale into its nullable columns.
+func rationaleColumns(rationale *entity.QuestionRationale) (reason, note *string) {
+	if rationale == nil {
+		return nil, nil
+	}
+
+	r := string(rationale.Reason)
+
+	return &r, &rationale.Note
+}
+
+func sessionRationale(reason, note sql.NullString) *entity.QuestionRationale {
+	if !reason.Valid {
+		return nil
+	}
+
+	return &entity.QuestionRationale{Reason: entity.SelectionReason(reason.String), Note: note.String}
+}
+
+func (r repoPracticeSession) CreateSession(ctx context.Context, session entity.PracticeSession, questions []entity.PracticeSessionQuestion) (entity.PracticeSession, error) {
+	if session.ID == uuid.Nil {
+		session.ID = uuid.New()
+	}
+	if session.Status == "" {
+		session.Status = entity.PracticeStatusInProgress
+	}
+	if session.StartedAt.IsZero() {
+		session.StartedAt = time.Now().UTC()
+	}
+
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return entity.PracticeSession{}, fmt.Errorf("practice - CreateSession - begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	querySQL, args, err := r.Builder.
+		Insert("practice_session").
+		Columns("id", "user_id", "exam_type_id", "mode", "status", "total_questions_planned", "time_limit_minutes", "started_at").
+		Values(
+			session.ID,
+			session.UserID,
+			squirrel.Expr("(SELECT id FROM exam_type_lookup WHERE code = ?)", string(session.Exam)),
+			string(session.Mode),
+			string(session.Status),
+			session.TotalQuestionsPlanned,
+			session.TimeLimitMinutes,
+			session.StartedAt,
+		).
+		ToSql()
+	if err != nil {
+		return entity.PracticeSession{}, fmt.Errorf("practice - CreateSession - build session: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, querySQL, args...); err != nil {
+		return entity.PracticeSession{}, fmt.Errorf("practice - CreateSession - insert session: %w", err)
+	}
+
+	if len(questions) > 0 {
+		builder := r.Builder.
+			Insert("practice_session_question").
+			Columns("id", "session_id", "question_id", "question_version", "sequence_index", "selection_reason", "selection_note")
+		for i, question := range questions {
+			reason, note := rationaleColumns(question.Rationale)
+			builder = builder.Values(
+				uuid.New(), session.ID, question.Question.ID,
+				squirrel.Expr("(SELECT version FROM question WHERE id = ?)", question.Question.ID),
+				i+1, reason, note,
+			)
+		}
+
+		querySQL, args, err = builder.ToSql()
+		if err != nil {
+			return entity.PracticeSession{}, fmt.Errorf("practice - CreateSession - build questions: %w", err)
+		}
+
+		if _, err := tx.Exec(ctx, querySQL, args...); err != nil {
+			return entity.PracticeSession{}, fmt.Errorf("practice - CreateSession - insert questions: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return entity.PracticeSession{}, fmt.Errorf("practice - CreateSession - commit: %w", err)
+	}
+
+	return session, nil
+}
+
+func (r repoPracticeSession) ListSessions(ctx context.Context, userID uuid.UUID) ([]entity.PracticeSession, error) {
+	builder := r.Builder.
+		Select(
+			"ps.id",
+			"ps.user_id",
+			"e.code",
+			"ps.mode",
+			"ps.status",
+			"ps.total_questions_planned",
+			"ps.time_limit_minutes",
+			"ps.started_at",
+			"ps.completed_at",
+		).
+		From("practice_session ps").
+		Join("exam_type_lookup e ON e.id = ps.exam_type_id").
+		Where("ps.user_id = ?", userID).
+		OrderBy("ps.started_at DESC")
+
+	querySQL, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("practice - ListSessions - build: %w", err)
+	}
+
+	rows, err := r.Pool.Query(ctx, querySQL, args...)
+	if err != nil {
+		return nil, fmt.Errorf("practice - ListSessions - query: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []entity.PracticeSession
+	for rows.Next() {
+		s, err := scanPracticeSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("practice - ListSessions - scan: %w", err)
+		}
+		sessions = append(sessions, s)
+	}
+
+	return sessions, nil
+}
+
+func (r repoPracticeSession) GetSession(ctx context.Context, id uuid.UUID) (entity.PracticeSession, error) {
+	builder := r.Builder.
+		Select(
+			"ps.id",
+			"ps.user_id",
+			"e.code",
+			"ps.mode",
+			"ps.status",
+			"ps.total_questions_planned",
+			"ps.time_limit_minutes",
+			"ps.started_at",
+			"ps.completed_at",
+		).
+		From("practice_session ps").
+		Join("exam_type_lookup e ON e.id = ps.exam_type_id").
+		Where("ps.id = ?", id).
+		Limit(1)
+
+	querySQL, args, err := builder.ToSql()
+	if err != nil {
+		return entity.PracticeSession{}, fmt.Errorf("practice - GetSession - build: %w", err)
+	}
+
+	s, err := scanPracticeSession(r.Pool.QueryRow(ctx, querySQL, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.PracticeSession{}, repo.ErrNotFound
+	}
+	if err != nil {
+		return entity.PracticeSession{}, fmt.Errorf("practice - GetSession - scan: %w", err)
+	}
+
+	return s, nil
+}
+
+func (r repoPracticeSession) ListSessionQuestions(ctx context.Context, sessionID uuid.UUID) ([]entity.PracticeSessionQuestion, error) {
+	builder := r.Builder.
+		Select(
+			"psq.id",
+			"psq.sequence_index",
+			"psq.selected_option",
+			"psq.selected_options",
+			"psq.numeric_answer",
+			"psq.matrix_answer",
+			"psq.is_correct",
+			"psq.score",
+			"psq.time_taken_ms",
+			"psq.is_guess",
+			"psq.answered_at",
+			"psq.selection_reason",
+			"psq.selection_note",
+			"q.id",
+			"e.code",
+			"qv.subject_id",
+			"qv.topic_id",
+			"qv.question_text",
+			"qv.option_a",
+			"qv.option_b",
+			"qv.option_c",
+			"qv.option_d",
+			"COALESCE(qv.correct_option, 0)",
+			"qv.correct_options",
+			"qv.reason_text",
+			"qv.numeric_answer",
+			"qv.numeric_tolerance",
+			"qv.matrix",
+			"qv.explanation",
+			"q.difficulty_level",
+			"qv.choice_type",
+			"qv.is_clinical",
+			"qv.is_image_based",
+			"qv.is_high_yield",
+			"qv.is_active",
+			"qv.version",
+		).
+		From("practice_session_question psq").
+		Join("question q ON q.id = psq.question_id").
+		Join("question_version qv ON qv.question_id = psq.question_id AND qv.version = psq.question_version").
+		Join("exam_type_lookup e ON e.id = q.exam_type_id").
+		Where("psq.session_id = ?", sessionID).
+		OrderBy("psq.sequence_index ASC")
+
+	querySQL, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("practice - ListSessionQuestions - build: %w", err)
+	}
+
+	rows, err := r.Pool.Query(ctx, querySQL, args...)
+	if err != nil {
+		return nil, fmt.Errorf("practice - ListSessionQuestions - query: %w", err)
+	}
+	defer rows.Close()
+
+	var questions []entity.PracticeSessionQuestion
+	for rows.Next() {
+		var psq entity.PracticeSessionQuestion
+		var q entity.Question
+		var examCode string
+		var choiceType string
+		var explanation sql.NullString
+		var selectedOption sql.NullInt32
+		var timeTaken sql.NullInt32
+		var answeredAt sql.NullTime
+		var reason, note sql.NullString
+		if err := rows.Scan(
+			&psq.ID,
+			&psq.SequenceIndex,
+			&selectedOption,
+			&psq.SelectedOptions,
+			&psq.NumericAnswer,
+			&psq.MatrixAnswer,
+			&psq.IsCorrect,
+			&psq.Score,
+			&timeTaken,
+			&psq.IsGuess,
+			&answeredAt,
+			&reason,
+			&note,
+			&q.ID,
+			&examCode,
+			&q.SubjectID,
+			&q.TopicID,
+			&q.QuestionText,
+			&q.OptionA,
+			&q.OptionB,
+			&q.OptionC,
+			&q.OptionD,
+			&q.CorrectOption,
+			&q.CorrectOptions,
+			&q.ReasonText,
+			&q.NumericAnswer,
+			&q.NumericTolerance,
+			&q.Matrix,
+			&explanation,
+			&q.DifficultyLevel,
+			&choiceType,
+			&q.IsClinical,
+			&q.IsImageBased,
+			&q.IsHighYield,
+			&q.IsActive,
+			&q.Version,
+		); err != nil {
+			return nil, fmt.Errorf("practice - ListSessionQuestions - scan: %w", err)
+		}
+		if selectedOption.Valid {
+			val := int(selectedOption.Int32)
+			psq.SelectedOption = &val
+		}
+		if timeTaken.Valid {
+			val := int(timeTaken.Int32)
+			psq.TimeTakenMs = &val
+		}
+		if answeredAt.Valid {
+			psq.AnsweredAt = &answeredAt.Time
+		}
+		psq.Rationale = sessionRationale(reason, note)
+		if explanation.Valid {
+			q.Explanation = &explanation.String
+		}
+		q.Exam = entity.ExamCategory(examCode)
+		q.ChoiceType = entity.QuestionChoiceType(choiceType)
+		psq.Question = q
+		questions = append(questions, psq)
+	}
+
+	return questions, nil
+}
+
+func (r repoPracticeSession) GetSessionQuestion(ctx context.Context, sessionID, id uuid.UUID) (entity.PracticeSessionQuestion, error) {
+	builder := r.Builder.
+		Select(
+			"psq.id",
+			"psq.sequence_index",
+			"psq.selected_option",
+			"psq.selected_options",
+			"psq.numeric_answer",
+			"psq.matrix_answer",
+			"psq.is_correct",
+			"psq.score",
+			"psq.time_taken_ms",
+			"psq.is_guess",
+			"psq.answered_at",
+			"psq.selection_reason",
+			"psq.selection_note",
+			"q.id",
+			"e.code",
+			"qv.subject_id",
+			"qv.topic_id",
+			"qv.question_text",
+			"qv.option_a",
+			"qv.option_b",
+			"qv.option_c",
+			"qv.option_d",
+			"COALESCE(qv.correct_option, 0)",
+			"qv.correct_options",
+			"qv.reason_text",
+			"qv.numeric_answer",
+			"qv.numeric_tolerance",
+			"qv.matrix",
+			"qv.explanation",
+			"q.difficulty_level",
+			"qv.choice_type",
+			"qv.is_clinical",
+			"qv.is_image_based",
+			"qv.is_high_yield",
+			"qv.is_active",
+			"qv.version",
+		).
+		From("practice_session_question psq").
+		Join("question q ON q.id = psq.question_id").
+		Join("question_version qv ON qv.question_id = psq.question_id AND qv.version = psq.question_version").
+		Join("exam_type_lookup e ON e.id = q.exam_type_id").
+		Where("psq.id = ? AND psq.session_id = ?", id, sessionID).
+		Limit(1)
+
+	querySQL, args, err := builder.ToSql()
+	if err != nil {
+		return entity.PracticeSessionQuestion{}, fmt.Errorf("practice - GetSessionQuestion - build: %w", err)
+	}
+
+	row := r.Pool.QueryRow(ctx, querySQL, args...)
+	var psq entity.PracticeSessionQuestion
+	var q entity.Question
+	var examCode string
+	var choiceType string
+	var explanation sql.NullString
+	var selectedOption sql.NullInt32
+	var timeTaken sql.NullInt32
+	var answeredAt sql.NullTime
+	var reason, note sql.NullString
+	err = row.Scan(
+		&psq.ID,
+		&psq.SequenceIndex,
+		&selectedOption,
+		&psq.SelectedOptions,
+		&psq.NumericAnswer,
+		&psq.MatrixAnswer,
+		&psq.IsCorrect,
+		&psq.Score,
+		&timeTaken,
+		&psq.IsGuess,
+		&answeredAt,
+		&reason,
+		&note,
+		&q.ID,
+		&examCode,
+		&q.SubjectID,
+		&q.TopicID,
+		&q.QuestionText,
+		&q.OptionA,
+		&q.OptionB,
+		&q.OptionC,
+		&q.OptionD,
+		&q.CorrectOption,
+		&q.CorrectOptions,
+		&q.ReasonText,
+		&q.NumericAnswer,
+		&q.NumericTolerance,
+		&q.Matrix,
+		&explanation,
+		&q.DifficultyLevel,
+		&choiceType,
+		&q.IsClinical,
+		&q.IsImageBased,
+		&q.IsHighYield,
+		&q.IsActive,
+		&q.Version,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.PracticeSessionQuestion{}, repo.ErrNotFound
+	}
+	if err != nil {
+		return entity.PracticeSessionQuestion{}, fmt.Errorf("practice - GetSessionQuestion - scan: %w", err)
+	}
+
+	if selectedOption.Valid {
+		val := int(selectedOption.Int32)
+		psq.SelectedOption = &val
+	}
+	if timeTaken.Valid {
+		val := int(timeTaken.Int32)
+		psq.TimeTakenMs = &val
+	}
+	if answeredAt.Valid {
+		psq.AnsweredAt = &answeredAt.Time
+	}
+	psq.Rationale = sessionRationale(reason, note)
+	if explanation.Valid {
+		q.Explanation = &explanation.String
+	}
+	q.Exam = entity.ExamCategory(examCode)
+	q.ChoiceType = entity.QuestionChoiceType(choiceType)
+	psq.Question = q
+
+	return psq, nil
+}
+
+func (r repoPracticeSession) AnswerSessionQuestion(ctx context.Context, sessionID, userID uuid.UUID, question entity.PracticeSessionQuestion, revision *entity.RevisionItem) (entity.PracticeSessionQuestion, error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return entity.PracticeSessionQuestion{}, fmt.Errorf("practice - AnswerSessionQuestion - begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// The answered_at guard makes the first answer win; retries and
+	// concurrent submissions match nothing.
+	var questionID uuid.UUID
+	err = tx.QueryRow(ctx, `
+UPDATE practice_session_question
+SET selected_option = $3, selected_options = $4, numeric_answer = $5, matrix_answer = $6,
+  is_correct = $7, score = $8, time_taken_ms = $9, answered_at = $10, is_guess = $11
+WHERE id = $1 AND session_id = $2 AND answered_at IS NULL
+RETURNING question_id
+`, question.ID, sessionID, question.SelectedOption, question.SelectedOptions, question.NumericAnswer,
+		matrixAnswer(question.MatrixAnswer), question.IsCorrect, question.Score, question.TimeTakenMs,
+		question.AnsweredAt, question.IsGuess).Scan(&questionID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.PracticeSessionQuestion{}, repo.ErrNotFound
+	}
+	if err != nil {
+		return entity.PracticeSessionQuestion{}, fmt.Errorf("practice - AnswerSessionQuestion - update: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+INSERT INTO user_question_attempt
+  (user_id, exam_type_id, question_id, session_id, session_question_id, is_correct, selected_option,
+   selected_options, numeric_answer, matrix_answer, score, time_taken_ms, is_guess, source, created_at)
+SELECT $1, q.exam_type_id, q.id, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+FROM question q
+WHERE q.id = $14
+ON CONFLICT (session_question_id) WHERE session_question_id IS NOT NULL DO NOTHING
+`, userID, sessionID, question.ID, question.IsCorrect, question.SelectedOption, question.SelectedOptions,
+		question.NumericAnswer, matrixAnswer(question.MatrixAnswer), question.Score, question.TimeTakenMs,
+		question.IsGuess, string(entity.AttemptSourcePractice), question.AnsweredAt, questionID)
+	if err != nil {
+		return entity.PracticeSessionQuestion{}, fmt.Errorf("practice - AnswerSessionQuestion - attempt: %w", err)
+	}
+
+	if revision != nil {
+		// Missing a queued question again restarts its schedule as a lapse but
+		// keeps the ease and memory state the scheduler has learned. A guessed
+		// correct answer is no lapse: it only pulls the next review earlier.
+		missed := question.IsCorrect == nil || !*question.IsCorrect
+		_, err = tx.Exec(ctx, `
+INSERT INTO revision_item (user_id, exam_type_id, question_id, algorithm, next_review_at, interval_index,
+  interval_days, ease, stability, difficulty, last_reviewed_at)
+SELECT $1, q.exam_type_id, q.id, $2, $3, $4, $5, $6, $7, $8, $9
+FROM question q
+WHERE q.id = $10
+ON CONFLICT (user_id, question_id) DO UPDATE
+SET next_review_at = CASE WHEN $11 THEN EXCLUDED.next_review_at
+    ELSE LEAST(revision_item.next_review_at, EXCLUDED.next_review_at) END,
+  interval_index = CASE WHEN $11 THEN 0 ELSE revision_item.interval_index END,
+  interval_days = CASE WHEN $11 THEN EXCLUDED.interval_days ELSE revision_item.interval_days END,
+  lapses = revision_item.lapses + CASE WHEN $11 THEN 1 ELSE 0 END,
+  updated_at = now()
+`, userID, string(revision.Algorithm), revision.NextReviewAt, revision.IntervalIndex, revision.IntervalDays,
+			revision.Ease, revision.Stability, revision.Difficulty, revision.LastReviewedAt, questionID, missed)
+		if err != nil {
+			return entity.PracticeSessionQuestion{}, fmt.Errorf("practice - AnswerSessionQuestion - revision: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return entity.PracticeSessionQuestion{}, fmt.Errorf("practice - AnswerSessionQuestion - commit: %w", err)
+	}
+
+	return question, nil
+}
+
+func (r repoPracticeSession) ReplaceSessionQuestion(ctx context.Context, sessionID, id, questionID uuid.UUID, rationale entity.QuestionRationale) error {
+	reason, note := rationaleColumns(&rationale)
+
+	tag, err := r.Pool.Exec(ctx, `
+UPDATE practice_session_question
+SET question_id = $3,
+    question_version = (SELECT version FROM question WHERE id = $3),
+    selection_reason = $4,
+    selection_note = $5
+WHERE id = $1 AND session_id = $2 AND answered_at IS NULL
+`, id, sessionID, questionID, reason, note)
+	if err != nil {
+		return fmt.Errorf("practice - ReplaceSessionQuestion - exec: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return repo.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r repoPracticeSession) TopicMastery(ctx context.Context, userID uuid.UUID, exam entity.ExamCategory, subjectIDs, topicIDs []uuid.UUID) ([]repo.TopicMastery, error) {
+	builder := r.Builder.
+		Select(
+			"q.subject_id",
+			"q.topic_id",
+			"COUNT(*)",
+			"COUNT(*) FILTER (WHERE a.is_correct)",
+			"MAX(a.created_at)",
+		).
+		From("user_question_attempt a").
+		Join("question q ON q.id = a.question_id").
+		Join("exam_type_lookup e ON e.id = a.exam_type_id").
+		Where("a.user_id = ?", userID).
+		Where("e.code::text = ?", string(exam)).
+		GroupBy("q.subject_id", "q.topic_id")
+
+	if len(subjectIDs) > 0 {
+		builder = builder.Where(squirrel.Eq{"q.subject_id": subjectIDs})
+	}
+	if len(topicIDs) > 0 {
+		builder = builder.Where("q.topic_id IN "+_topicsUnder, topicIDs)
+	}
+
+	querySQL, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("practice - TopicMastery - build: %w", err)
+	}
+
+	rows, err := r.Pool.Query(ctx, querySQL, args...)
+	if err != nil {
+		return nil, fmt.Errorf("practice - TopicMastery - query: %w", err)
+	}
+	defer rows.Close()
+
+	var mastery []repo.TopicMastery
+	for rows.Next() {
+		var m repo.TopicMastery
+		if err := rows.Scan(&m.SubjectID, &m.TopicID, &m.Attempts, &m.Correct, &m.LastAttemptAt); err != nil {
+			return nil, fmt.Errorf("practice - TopicMastery - scan: %w", err)
+		}
+		mastery = append(mastery, m)
+	}
+
+	return mastery, nil
+}
+
+func (r repoPracticeSession) FinishSession(ctx context.Context, id uuid.UUID, status entity.PracticeSessionStatus, at time.Time) (entity.PracticeSession, error) {
+	row := r.Pool.QueryRow(ctx, `
+UPDATE practice_session ps
+SET status = $2, completed_at = $3
+FROM exam_type_lookup e
+WHERE e.id = ps.exam_type_id AND ps.id = $1 AND ps.status = $4
+RETURNING ps.id, ps.user_id, e.code, ps.mode, ps.status, ps.total_questions_planned,
+  ps.time_limit_minutes, ps.started_at, ps.completed_at
+`, id, string(status), at, string(entity.PracticeStatusInProgress))
+
+	session, err := scanPracticeSession(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.PracticeSession{}, repo.ErrNotFound
+	}
+	if err != nil {
+		return entity.PracticeSession{}, fmt.Errorf("practice - FinishSession - scan: %w", err)
+	}
+
+	return session, nil
+}
+
+func (r repoPracticeSession) ExpireSessions(ctx context.Context, now time.Time) (int, error) {
+	tag, err := r.Pool.Exec(ctx, `
+UPDATE practice_session
+SET status = $1, completed_at = started_at + make_interval(mins => time_limit_minutes)
+WHERE status = $2
+  AND time_limit_minutes IS NOT NULL
+  AND started_at + make_interval(mins => time_limit_minutes) <= $3
+`, string(entity.PracticeStatusAbandoned), string(entity.PracticeStatusInProgress), now)
+	if err != nil {
+		return 0, fmt.Errorf("practice - ExpireSessions - exec: %w", err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+func (r repoPracticeSession) SessionTopicStats(ctx context.Context, sessionID uuid.UUID) ([]repo.SessionTopicStat, error) {
+	builder := r.Builder.
+		Select(
+			"s.id",
+			"s.name",
+			"t.id",
+			"t.name",
+			"COUNT(*)",
+			"COUNT(*) FILTER (WHERE psq.is_correct IS NOT NULL)",
+			"COUNT(*) FILTER (WHERE psq.is_correct)",
+			"COALESCE(SUM(psq.score), 0)",
+			"COALESCE(SUM(psq.time_taken_ms), 0)",
+		).
+		From("practice_session_question psq").
+		Join("question q ON q.id = psq.question_id").
+		Join("subject s ON s.id = q.subject_id").
+		Join("topic t ON t.id = q.topic_id").
+		Where("psq.session_id = ?", sessionID).
+		GroupBy("s.id", "s.name", "t.id", "t.name").
+		OrderBy("s.name ASC", "t.name ASC")
+
+	querySQL, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("practice - SessionTopicStats - build: %w", err)
+	}
+
+	rows, err := r.Pool.Query(ctx, querySQL, args...)
+	if err != nil {
+		return nil, fmt.Errorf("practice - SessionTopicStats - query: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []repo.SessionTopicStat
+	for rows.Next() {
+		var st repo.SessionTopicStat
+		if err := rows.Scan(
+			&st.SubjectID,
+			&st.SubjectName,
+			&st.TopicID,
+			&st.TopicName,
+			&st.Total,
+			&st.Answered,
+			&st.Correct,
+			&st.Score,
+			&st.TimeTakenMs,
+		); err != nil {
+			return nil, fmt.Errorf("practice - SessionTopicStats - scan: %w", err)
+		}
+		stats = append(stats, st)
+	}
+
+	return stats, nil
+}
+
+// repoRevision implements RevisionRepository.
+type repoRevision struct{ *postgres.Postgres }
+
+func (r repoRevision) selectItems() squirrel.SelectBuilder {
+	return repoQuestion(r).selectQuestions().
+		Columns(
+			"ri.id",
+			"ri.user_id",
+			"ri.algorithm",
+			"ri.next_review_at",
+			"ri.interval_index",
+			"ri.interval_days",
+			"ri.ease",
+			"ri.stability",
+			"ri.difficulty",
+			"ri.lapses",
+			"ri.times_reviewed",
+			"ri.last_result",
+			"ri.last_reviewed_at",
+		).
+		Join("revision_item ri ON ri.question_id = q.id")
+}
+
+func scanRevisionItem(row rowScanner) (entity.RevisionItem, error) {
+	var item entity.RevisionItem
+	var choiceType string
+	var lastResult sql.NullString
+	dest := append(questionDest(&item.Question, &choiceType),
+		&item.ID,
+		&item.UserID,
+		&item.Algorithm,
+		&item.NextReviewAt,
+		&item.IntervalIndex,
+		&item.IntervalDays,
+		&item.Ease,
+		&item.Stability,
+		&item.Difficulty,
+		&item.Lapses,
+		&item.TimesReviewed,
+		&lastResult,
+		&item.LastReviewedAt,
+	)
+	if err := row.Scan(dest...); err != nil {
+		return entity.RevisionItem{}, err
+	}
+	item.Question.ChoiceType = entity.QuestionChoiceType(choiceType)
+	if lastResult.Valid {
+		result := entity.RevisionResult(lastResult.String)
+		item.LastResult = &result
+	}
+
+	return item, nil
+}
+
+func (r repoRevision) ListDue(ctx context.Context, userID uuid.UUID) ([]entity.RevisionItem, error) {
+	querySQL, args, err := r.selectItems().
+		Where("ri.user_id = ?", userID).
+		Where("ri.next_review_at <= now()").
+		Where("q.is_active").
+		OrderBy("ri.next_review_at ASC", "ri.id ASC").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("revision - ListDue - build: %w", err)
+	}
+
+	rows, err := r.Pool.Query(ctx, querySQL, args...)
+	if err != nil {
+		return nil, fmt.Errorf("revision - ListDue - query: %w", err)
+	}
+	defer rows.Close()
+
+	items := []entity.RevisionItem{}
+	for rows.Next() {
+		item, err := scanRevisionItem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("revision - ListDue - scan: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func (r repoRevision) Get(ctx context.Context, id uuid.UUID) (entity.RevisionItem, error) {
+	querySQL, args, err := r.selectItems().
+		Where("ri.id = ?", id).
+		ToSql()
+	if err != nil {
+		return entity.RevisionItem{}, fmt.Errorf("revision - Get - build: %w", err)
+	}
+
+	item, err := scanRevisionItem(r.Pool.QueryRow(ctx, querySQL, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.RevisionItem{}, repo.ErrNotFound
+	}
+	if err != nil {
+		return entity.RevisionItem{}, fmt.Errorf("revision - Get - scan: %w", err)
+	}
+
+	return item, nil
+}
+
+func (r repoRevision) Review(ctx context.Context, item entity.RevisionItem, dailyCap int, since time.Time) (entity.RevisionItem, error) {
+	var lastResult *string
+	if item.LastResult != nil {
+		result := string(*item.LastResult)
+		lastResult = &result
+	}
+
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return entity.RevisionItem{}, fmt.Errorf("revision - Review - begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if dailyCap > 0 {
+		// The lock serializes a user's reviews, so concurrent ones cannot
+		// all pass the count.
+		if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock(hashtextextended('revision_review:' || $1::text, 0))",
+			item.UserID); err != nil {
+			return entity.RevisionItem{}, fmt.Errorf("revision - Review - lock: %w", err)
+		}
+
+		var reviewed int
+		if err := tx.QueryRow(ctx, `
+SELECT COUNT(*) FROM revision_review WHERE user_id = $1 AND reviewed_at >= $2
+`, item.UserID, since).Scan(&reviewed); err != nil {
+			return entity.RevisionItem{}, fmt.Errorf("revision - Review - count: %w", err)
+		}
+		if reviewed >= dailyCap {
+			return entity.RevisionItem{}, repo.ErrLimitReached
+		}
+	}
+
+	tag, err := tx.Exec(ctx, `
+UPDATE revision_item
+SET algorithm = $2, next_review_at = $3, interval_index = $4, interval_days = $5, ease = $6, stability = $7,
+  difficulty = $8, lapses = $9, times_reviewed = $10, last_result = $11, last_reviewed_at = $12, updated_at = now()
+WHERE id = $1
+`, item.ID, string(item.Algorithm), item.NextReviewAt, item.IntervalIndex, item.IntervalDays, item.Ease,
+		item.Stability, item.Difficulty, item.Lapses, item.TimesReviewed, lastResult, item.LastReviewedAt)
+	if err != nil {
+		return entity.RevisionItem{}, fmt.Errorf("revision - Review - update: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return entity.RevisionItem{}, repo.ErrNotFound
+	}
+
+	_, err = tx.Exec(ctx, `
+INSERT INTO revision_review (item_id, user_id, result, algorithm, interval_days, reviewed_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+`, item.ID, item.UserID, lastResult, string(item.Algorithm), item.IntervalDays, item.LastReviewedAt)
+	if err != nil {
+		return entity.RevisionItem{}, fmt.Errorf("revision - Review - log: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return entity.RevisionItem{}, fmt.Errorf("revision - Review - commit: %w", err)
+	}
+
+	return item, nil
+}
+
+func (r repoRevision) ListSchedule(ctx context.Context, userID uuid.UUID, until time.Time) ([]time.Time, error) {
+	rows, err := r.Pool.Query(ctx, `
+SELECT ri.next_review_at
+FROM revision_item ri
+JOIN question q ON q.id = ri.question_id
+WHERE ri.user_id = $1 AND ri.next_review_at < $2 AND q.is_active
+ORDER BY ri.next_review_at, ri.id
+`, userID, until)
+	if err != nil {
+		return nil, fmt.Errorf("revision - ListSchedule - query: %w", err)
+	}
+	defer rows.Close()
+
+	var schedule []time.Time
+	for rows.Next() {
+		var due time.Time
+		if err := rows.Scan(&due); err != nil {
+			return nil, fmt.Errorf("revision - ListSchedule - scan: %w", err)
+		}
+		schedule = append(schedule, due)
+	}
+
+	return schedule, nil
+}
+
+func (r repoRevision) CountReviewsSince(ctx context.Context, userID uuid.UUID, since time.Time) (int, error) {
+	var count int
+	err := r.Pool.QueryRow(ctx, `
+SELECT COUNT(*) FROM revision_review WHERE user_id = $1 AND reviewed_at >= $2
+`, userID, since).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("revision - CountReviewsSince - scan: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r repoRevision) GetDailyCap(ctx context.Context, userID uuid.UUID) (*int, error) {
+	var dailyCap *int
+	err := r.Pool.QueryRow(ctx, `SELECT revision_daily_cap FROM "user" WHERE id = $1`, userID).Scan(&dailyCap)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("revision - GetDailyCap - scan: %w", err)
+	}
+
+	return dailyCap, nil
+}
+
+func (r repoRevision) SetDailyCap(ctx context.Context, userID uuid.UUID, dailyCap *int) error {
+	tag, err := r.Pool.Exec(ctx, `UPDATE "user" SET revision_daily_cap = $2, updated_at = now() WHERE id = $1`, userID, dailyCap)
+	if err != nil {
+		return fmt.Errorf("revision - SetDailyCap - exec: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repo.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r repoRevision) ListReviewHistory(ctx context.Context, userID uuid.UUID) ([]repo.RevisionReviewLog, error) {
+	rows, err := r.Pool.Query(ctx, `
+SELECT rr.item_id, ri.created_at, rr.result, rr.reviewed_at
+FROM revision_review rr
+JOIN revision_item ri ON ri.id = rr.item_id
+WHERE rr.user_id = $1
+ORDER BY ri.created_at, rr.item_id, rr.reviewed_at
+`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("revision - ListReviewHistory - query: %w", err)
+	}
+	defer rows.Close()
+
+	var history []repo.RevisionReviewLog
+	for rows.Next() {
+		var entry repo.RevisionReviewLog
+		if err := rows.Scan(&entry.ItemID, &entry.QueuedAt, &entry.Result, &entry.ReviewedAt); err != nil {
+			return nil, fmt.Errorf("revision - ListReviewHistory - scan: %w", err)
+		}
+		history = append(history, entry)
+	}
+
+	return history, nil
+}
+
+// repoQuestionImport implements QuestionImportRepository.
+type repoQuestionImport struct{ *postgres.Postgres }
+
+const _importJobColumns = `j.id, e.code, j.format, j.file_name, j.create_missing, j.status, j.total_rows,
+  j.processed_rows, j.imported_rows, j.skipped_rows, j.failed_rows, j.errors, j.duplicate_rows, j.duplicates,
+  j.error, j.created_by, j.created_at, j.started_at, j.finished_at`
+
+func scanImportJob(row rowScanner, extra ...any) (entity.QuestionImportJob, error) {
+	var job entity.QuestionImportJob
+	dest := append([]any{
+		&job.ID, &job.Exam, &job.Format, &job.FileName, &job.CreateMissing, &job.Status, &job.TotalRows,
+		&job.ProcessedRows, &job.ImportedRows, &job.SkippedRows, &job.FailedRows, &job.Errors, &job.DuplicateRows,
+		&job.Duplicates, &job.Error, &job.CreatedBy, &job.CreatedAt, &job.StartedAt, &job.FinishedAt,
+	}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return entity.QuestionImportJob{}, err
+	}
+
+	return job, nil
+}
+
+func (r repoQuestionImport) Create(ctx context.Context, job entity.QuestionImportJob, payload []byte) (entity.QuestionImportJob, error) {
+	if job.ID == uuid.Nil {
+		job.ID = uuid.New()
+	}
+
+	row := r.Pool.QueryRow(ctx, `
+INSERT INTO question_import_job (id, exam_type_id, format, file_name, create_missing, payload, status, created_by)
+SELECT $1, e.id, $3, $4, $5, $6, 'queued', $7 FROM exam_type_lookup e WHERE e.code = $2
+RETURNING created_at
+`, job.ID, string(job.Exam), string(job.Format), job.FileName, job.CreateMissing, payload, job.CreatedBy)
+	if err := row.Scan(&job.CreatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.QuestionImportJob{}, fmt.Errorf("import - Create - exam %s: %w", job.Exam, repo.ErrNotFound)
+		}
+
+		return entity.QuestionImportJob{}, fmt.Errorf("import - Create - scan: %w", err)
+	}
+
+	job.Status = entity.QuestionImportQueued
+	job.Errors = []entity.QuestionImportRowError{}
+	job.Duplicates = []entity.QuestionImportDuplicate{}
+
+	return job, nil
+}
+
+func (r repoQuestionImport) Get(ctx context.Context, id uuid.UUID) (entity.QuestionImportJob, error) {
+	job, err := scanImportJob(r.Pool.QueryRow(ctx, `
+SELECT `+_importJobColumns+`
+FROM question_import_job j
+JOIN exam_type_lookup e ON e.id = j.exam_type_id
+WHERE j.id = $1
+`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.QuestionImportJob{}, repo.ErrNotFound
+	}
+	if err != nil {
+		return entity.QuestionImportJob{}, fmt.Errorf("import - Get - scan: %w", err)
+	}
+
+	return job, nil
+}
+
+func (r repoQuestionImport) List(ctx context.Context, limit int) ([]entity.QuestionImportJob, error) {
+	rows, err := r.Pool.Query(ctx, `
+SELECT `+_importJobColumns+`
+FROM question_import_job j
+JOIN exam_type_lookup e ON e.id = j.exam_type_id
+ORDER BY j.created_at DESC
+LIMIT $1
+`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("import - List - query: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []entity.QuestionImportJob
+	for rows.Next() {
+		job, err := scanImportJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("import - List - scan: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
+
+func (r repoQuestionImport) ClaimNext(ctx context.Context, staleBefore time.Time) (entity.QuestionImportJob, []byte, error) {
+	var payload []byte
+	job, err := scanImportJob(r.Pool.QueryRow(ctx, `
+WITH next AS (
+  SELECT id FROM question_import_job
+  WHERE status = 'queued' OR (status = 'running' AND updated_at < $1)
+  ORDER BY created_at
+  LIMIT 1
+  FOR UPDATE SKIP LOCKED
+)
+UPDATE question_import_job j
+SET status = 'running', started_at = COALESCE(j.started_at, now()), updated_at = now()
+FROM next, exam_type_lookup e
+WHERE j.id = next.id AND e.id = j.exam_type_id
+RETURNING `+_importJobColumns+`, j.payload
+`, staleBefore), &payload)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.QuestionImportJob{}, nil, repo.ErrNotFound
+	}
+	if err != nil {
+		return entity.QuestionImportJob{}, nil, fmt.Errorf("import - ClaimNext - scan: %w", err)
+	}
+
+	return job, payload, nil
+}
+
+// Save drops the uploaded file once the job has finished.
+func (r repoQuestionImport) Save(ctx context.Context, job entity.QuestionImportJob) error {
+	errs := job.Errors
+	if errs == nil {
+		errs = []entity.QuestionImportRowError{}
+	}
+	duplicates := job.Duplicates
+	if duplicates == nil {
+		duplicates = []entity.QuestionImportDuplicate{}
+	}
+
+	tag, err := r.Pool.Exec(ctx, `
+UPDATE question_import_job
+SET status = $2, total_rows = $3, processed_rows = $4, imported_rows = $5, skipped_rows = $6,
+  failed_rows = $7, errors = $8, duplicate_rows = $9, duplicates = $10, error = $11, finished_at = $12,
+  payload = CASE WHEN $12::timestamptz IS NULL THEN payload END, updated_at = now()
+WHERE id = $1
+`, job.ID, string(job.Status), job.TotalRows, job.ProcessedRows, job.ImportedRows, job.SkippedRows,
+		job.FailedRows, errs, job.DuplicateRows, duplicates, job.Error, job.FinishedAt)
+	if err != nil {
+		return fmt.Errorf("import - Save - exec: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repo.ErrNotFound
+	}
+
+	return nil
+}
+
+// repoQuestionSignature implements QuestionSignatureRepository.
+type repoQuestionSignature struct{ *postgres.Postgres }
+
+func (r repoQuestionSignature) ListStale(ctx context.Context, limit int) ([]entity.QuestionSignature, error) {
+	rows, err := r.Pool.Query(ctx, `
+SELECT q.id, e.code, q.version, q.question_text
+FROM question q
+JOIN exam_type_lookup e ON e.id = q.exam_type_id
+LEFT JOIN question_signature s ON s.question_id = q.id
+WHERE s.question_id IS NULL OR s.version <> q.version
+LIMIT $1
+`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("signature - ListStale - query: %w", err)
+	}
+	defer rows.Close()
+
+	var stale []entity.QuestionSignature
+	for rows.Next() {
+		var s entity.QuestionSignature
+		if err := rows.Scan(&s.QuestionID, &s.Exam, &s.Version, &s.QuestionText); err != nil {
+			return nil, fmt.Errorf("signature - ListStale - scan: %w", err)
+		}
+		stale = append(stale, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("signature - ListStale - rows: %w", err)
+	}
+
+	return stale, nil
+}
+
+func (r repoQuestionSignature) Save(ctx context.Context, signatures []entity.QuestionSignature) error {
+	if len(signatures) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	for _, s := range signatures {
+		batch.Queue(`
+INSERT INTO question_signature (question_id, exam_type_id, version, signature, band_keys)
+SELECT $1, e.id, $3, $4, $5 FROM exam_type_lookup e WHERE e.code = $2
+ON CONFLICT (question_id) DO UPDATE
+SET version = EXCLUDED.version, signature = EXCLUDED.signature, band_keys = EXCLUDED.band_keys, updated_at = now()
+`, s.QuestionID, string(s.Exam), s.Version, s.Signature, s.BandKeys)
+	}
+
+	if err := r.Pool.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("signature - Save - exec: %w", err)
+	}
+
+	return nil
+}
+
+const _signatureColumns = `s.question_id, e.code, s.version, q.question_text, s.signature, s.band_keys`
+
+func (r repoQuestionSignature) Candidates(ctx context.Context, exam entity.ExamCategory, bandKeys []int64) ([]entity.QuestionSignature, error) {
+	return r.query(ctx, "Candidates", `
+SELECT `+_signatureColumns+`
+FROM question_signature s
+JOIN exam_type_lookup e ON e.id = s.exam_type_id
+JOIN question q ON q.id = s.question_id
+WHERE e.code = $1 AND s.band_keys && $2
+`, string(exam), bandKeys)
+}
+
+func (r repoQuestionSignature) Get(ctx context.Context, ids []uuid.UUID) ([]entity.QuestionSignature, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	return r.query(ctx, "Get", `
+SELECT `+_signatureColumns+`
+FROM question_signature s
+JOIN exam_type_lookup e ON e.id = s.exam_type_id
+JOIN question q ON q.id = s.question_id
+WHERE s.question_id = ANY($1)
+`, ids)
+}
+
+func (r repoQuestionSignature) query(ctx context.Context, method, sql string, args ...any) ([]entity.QuestionSignature, error) {
+	rows, err := r.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("signature - %s - query: %w", method, err)
+	}
+	defer rows.Close()
+
+	var signatures []entity.QuestionSignature
+	for rows.Next() {
+		var s entity.QuestionSignature
+		if err := rows.Scan(&s.QuestionID, &s.Exam, &s.Version, &s.QuestionText, &s.Signature, &s.BandKeys); err != nil {
+			return nil, fmt.Errorf("signature - %s - scan: %w", method, err)
+		}
+		signatures = append(signatures, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("signature - %s - rows: %w", method, err)
+	}
+
+	return signatures, nil
+}
+
+func (r repoQuestionSignature) Collisions(ctx context.Context, exam entity.ExamCategory, fn func([]uuid.UUID) error) error {
+	rows, err := r.Pool.Query(ctx, `
+SELECT array_agg(s.question_id)
+FROM question_signature s
+JOIN exam_type_lookup e ON e.id = s.exam_type_id
+CROSS JOIN LATERAL unnest(s.band_keys) AS k(key)
+WHERE e.code = $1
+GROUP BY k.key
+HAVING count(*) > 1
+`, string(exam))
+	if err != nil {
+		return fmt.Errorf("signature - Collisions - query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var ids []uuid.UUID
+		if err := rows.Scan(&ids); err != nil {
+			return fmt.Errorf("signature - Collisions - scan: %w", err)
+		}
+		if err := fn(ids); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("signature - Collisions - rows: %w", err)
+	}
+
+	return nil
+}
+
+// repoQuestionMedia implements QuestionMediaRepository.
+type repoQuestionMedia struct{ *postgres.Postgres }
+
+const _mediaColumns = `id, question_id, slot, content_type, size_bytes, width, height, alt_text, blob_key, thumbnail_key, created_by, created_at`
+
+func scanQuestionMedia(row rowScanner) (entity.QuestionMedia, error) {
+	var m entity.QuestionMedia
+	err := row.Scan(&m.ID, &m.QuestionID, &m.Slot, &m.ContentType, &m.SizeBytes, &m.Width, &m.Height,
+		&m.AltText, &m.Key, &m.ThumbnailKey, &m.CreatedBy, &m.CreatedAt)
+
+	return m, err
+}
+
+func (r repoQuestionMedia) Create(ctx context.Context, media entity.QuestionMedia) (entity.QuestionMedia, error) {
+	if media.ID == uuid.Nil {
+		media.ID = uuid.New()
+	}
+
+	row := r.Pool.QueryRow(ctx, `
+INSERT INTO question_media (id, question_id, slot, content_type, size_bytes, width, height, alt_text, blob_key, thumbnail_key, created_by)
+SELECT $1, q.id, $3, $4, $5, $6, $7, $8, $9, $10, $11 FROM question q WHERE q.id = $2
+RETURNING created_at
+`, media.ID, media.QuestionID, string(media.Slot), media.ContentType, media.SizeBytes, media.Width, media.Height,
+		media.AltText, media.Key, media.ThumbnailKey, media.CreatedBy)
+	if err := row.Scan(&media.CreatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.QuestionMedia{}, fmt.Errorf("media - Create - question %s: %w", media.QuestionID, repo.ErrNotFound)
+		}
+
+		return entity.QuestionMedia{}, fmt.Errorf("media - Create - scan: %w", err)
+	}
+
+	return media, nil
+}
+
+func (r repoQuestionMedia) ListByQuestions(ctx context.Context, questionIDs []uuid.UUID) ([]entity.QuestionMedia, error) {
+	if len(questionIDs) == 0 {
+		return nil, nil
+	}
+
+	rows, err := r.Pool.Query(ctx, `
+SELECT `+_mediaColumns+`
+FROM question_media
+WHERE question_id = ANY($1)
+ORDER BY question_id, created_at, id
+`, questionIDs)
+	if err != nil {
+		return nil, fmt.Errorf("media - ListByQuestions - query: %w", err)
+	}
+	defer rows.Close()
+
+	var media []entity.QuestionMedia
+	for rows.Next() {
+		m, err := scanQuestionMedia(rows)
+		if err != nil {
+			return nil, fmt.Errorf("media - ListByQuestions - scan: %w", err)
+		}
+		media = append(media, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("media - ListByQuestions - rows: %w", err)
+	}
+
+	return media, nil
+}
+
+func (r repoQuestionMedia) Delete(ctx context.Context, questionID, id uuid.UUID) (entity.QuestionMedia, error) {
+	m, err := scanQuestionMedia(r.Pool.QueryRow(ctx, `
+DELETE FROM question_media
+WHERE question_id = $1 AND id = $2
+RETURNING `+_mediaColumns, questionID, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.QuestionMedia{}, repo.ErrNotFound
+	}
+	if err != nil {
+		return entity.QuestionMedia{}, fmt.Errorf("media - Delete - scan: %w", err)
+	}
+
+	return m, nil
+}
+
+// repoQuestionReview implements QuestionReviewRepository.
+type repoQuestionReview struct{ *postgres.Postgres }
+
+const _reviewCommentColumns = `id, question_id, version, field, body, author_id, created_at, resolved_by, resolved_at`
+
+func scanReviewComment(row rowScanner) (entity.QuestionReviewComment, error) {
+	var c entity.QuestionReviewComment
+	err := row.Scan(&c.ID, &c.QuestionID, &c.Version, &c.Field, &c.Body, &c.AuthorID, &c.CreatedAt,
+		&c.ResolvedBy, &c.ResolvedAt)
+
+	return c, err
+}
+
+func (r repoQuestionReview) CreateComment(ctx context.Context, comment entity.QuestionReviewComment) (entity.QuestionReviewComment, error) {
+	if comment.ID == uuid.Nil {
+		comment.ID = uuid.New()
+	}
+
+	row := r.Pool.QueryRow(ctx, `
+INSERT INTO question_review_comment (id, question_id, version, field, body, author_id)
+SELECT $1, q.id, $3, $4, $5, $6 FROM question q WHERE q.id = $2
+RETURNING created_at
+`, comment.ID, comment.QuestionID, comment.Version, comment.Field, comment.Body, comment.AuthorID)
+	if err := row.Scan(&comment.CreatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.QuestionReviewComment{}, fmt.Errorf("review - CreateComment - question %s: %w", comment.QuestionID, repo.ErrNotFound)
+		}
+
+		return entity.QuestionReviewComment{}, fmt.Errorf("review - CreateComment - scan: %w", err)
+	}
+
+	return comment, nil
+}
+
+func (r repoQuestionReview) ListComments(ctx context.Context, questionID uuid.UUID, unresolvedOnly bool) ([]entity.QuestionReviewComment, error) {
+	rows, err := r.Pool.Query(ctx, `
+SELECT `+_reviewCommentColumns+`
+FROM question_review_comment
+WHERE question_id = $1 AND (NOT $2 OR resolved_at IS NULL)
+ORDER BY created_at, id
+`, questionID, unresolvedOnly)
+	if err != nil {
+		return nil, fmt.Errorf("review - ListComments - query: %w", err)
+	}
+	defer rows.Close()
+
+	comments := []entity.QuestionReviewComment{}
+	for rows.Next() {
+		c, err := scanReviewComment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("review - ListComments - scan: %w", err)
+		}
+		comments = append(comments, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("review - ListComments - rows: %w", err)
+	}
+
+	return comments, nil
+}
+
+func (r repoQuestionReview) ResolveComment(ctx context.Context, questionID, id, resolvedBy uuid.UUID) (entity.QuestionReviewComment, error) {
+	c, err := scanReviewComment(r.Pool.QueryRow(ctx, `
+UPDATE question_review_comment
+SET resolved_by = COALESCE(resolved_by, $3), resolved_at = COALESCE(resolved_at, now())
+WHERE question_id = $1 AND id = $2
+RETURNING `+_reviewCommentColumns, questionID, id, resolvedBy))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.QuestionReviewComment{}, repo.ErrNotFound
+	}
+	if err != nil {
+		return entity.QuestionReviewComment{}, fmt.Errorf("review - ResolveComment - scan: %w", err)
+	}
+
+	return c, nil
+}
+
+// repoQuestionReport implements QuestionReportRepository.
+type repoQuestionReport struct{ *postgres.Postgres }
+
+const _reportColumns = `id, question_id, question_version, user_id, reason, details, status, resolution_note, resolved_by, resolved_at, created_at`
+
+func scanQuestionReport(row rowScanner) (entity.QuestionReport, error) {
+	var r entity.QuestionReport
+	err := row.Scan(&r.ID, &r.QuestionID, &r.QuestionVersion, &r.UserID, &r.Reason, &r.Details, &r.Status,
+		&r.ResolutionNote, &r.ResolvedBy, &r.ResolvedAt, &r.CreatedAt)
+
+	return r, err
+}
+
+func (r repoQuestionReport) Create(ctx context.Context, report entity.QuestionReport, hourlyLimit int) (entity.QuestionReport, error) {
+	if report.ID == uuid.Nil {
+		report.ID = uuid.New()
+	}
+
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return entity.QuestionReport{}, fmt.Errorf("report - Create - begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if hourlyLimit > 0 {
+		// The lock serializes a user's reports, so concurrent ones cannot
+		// all pass the count.
+		if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock(hashtextextended('question_report:' || $1::text, 0))",
+			report.UserID); err != nil {
+			return entity.QuestionReport{}, fmt.Errorf("report - Create - lock: %w", err)
+		}
+
+		var recent int
+		if err := tx.QueryRow(ctx, `
+SELECT COUNT(*) FROM question_report WHERE user_id = $1 AND created_at >= now() - interval '1 hour'
+`, report.UserID).Scan(&recent); err != nil {
+			return entity.QuestionReport{}, fmt.Errorf("report - Create - count: %w", err)
+		}
+		if recent >= hourlyLimit {
+			return entity.QuestionReport{}, repo.ErrLimitReached
+		}
+	}
+
+	created, err := scanQuestionReport(tx.QueryRow(ctx, `
+INSERT INTO question_report (id, question_id, question_version, user_id, reason, details)
+SELECT $1, q.id, q.version, $3, $4, $5 FROM question q WHERE q.id = $2
+ON CONFLICT (question_id, user_id) WHERE status = 'open' DO NOTHING
+RETURNING `+_reportColumns, report.ID, report.QuestionID, report.UserID, string(report.Reason), report.Details))
+	if errors.Is(err, pgx.ErrNoRows) {
+		var exists bool
+		if err := tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM question WHERE id = $1)", report.QuestionID).Scan(&exists); err != nil {
+			return entity.QuestionReport{}, fmt.Errorf("report - Create - exists: %w", err)
+		}
+		if exists {
+			return entity.QuestionReport{}, repo.ErrConflict
+		}
+
+		return entity.QuestionReport{}, repo.ErrNotFound
+	}
+	if err != nil {
+		return entity.QuestionReport{}, fmt.Errorf("report - Create - scan: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return entity.QuestionReport{}, fmt.Errorf("report - Create - commit: %w", err)
+	}
+
+	return created, nil
+}
+
+func (r repoQuestionReport) CountOpen(ctx context.Context, questionID uuid.UUID) (int, error) {
+	var count int
+	err := r.Pool.QueryRow(ctx, `
+SELECT COUNT(*) FROM question_report WHERE question_id = $1 AND status = 'open'
+`, questionID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("report - CountOpen - scan: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r repoQuestionReport) ListGroups(ctx context.Context, status entity.QuestionReportStatus, page, pageSize int) ([]entity.QuestionReportGroup, int, error) {
+	rows, err := r.Pool.Query(ctx, `
+SELECT r.question_id, q.question_text, q.status, COUNT(*), MIN(r.created_at), MAX(r.created_at),
+  (SELECT jsonb_object_agg(reason, n) FROM (
+    SELECT reason, COUNT(*) AS n FROM question_report
+    WHERE question_id = r.question_id AND status = $1
+    GROUP BY reason
+  ) reasons),
+  COUNT(*) OVER ()
+FROM question_report r
+JOIN question q ON q.id = r.question_id
+WHERE r.status = $1
+GROUP BY r.question_id, q.question_text, q.status
+ORDER BY COUNT(*) DESC, MAX(r.created_at) DESC, r.question_id
+LIMIT $2 OFFSET $3
+`, string(status), pageSize, (page-1)*pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("report - ListGroups - query: %w", err)
+	}
+	defer rows.Close()
+
+	groups := []entity.QuestionReportGroup{}
+	total := 0
+	for rows.Next() {
+		var g entity.QuestionReportGroup
+		if err := rows.Scan(&g.QuestionID, &g.QuestionText, &g.QuestionStatus, &g.Reports,
+			&g.FirstReportedAt, &g.LastReportedAt, &g.Reasons, &total); err != nil {
+			return nil, 0, fmt.Errorf("report - ListGroups - scan: %w", err)
+		}
+		groups = append(groups, g)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("report - ListGroups - rows: %w", err)
+	}
+
+	// A page past the end has no rows to carry the total.
+	if len(groups) == 0 && page > 1 {
+		err := r.Pool.QueryRow(ctx, `
+SELECT COUNT(DISTINCT question_id) FROM question_report WHERE status = $1
+`, string(status)).Scan(&total)
+		if err != nil {
+			return nil, 0, fmt.Errorf("report - ListGroups - count: %w", err)
+		}
+	}
+
+	return groups, total, nil
+}
+
+func (r repoQuestionReport) ListByQuestion(ctx context.Context, questionID uuid.UUID, status *entity.QuestionReportStatus) ([]entity.QuestionReport, error) {
+	var statusFilter *string
+	if status != nil {
+		value := string(*status)
+		statusFilter = &value
+	}
+
+	rows, err := r.Pool.Query(ctx, `
+SELECT `+_reportColumns+`
+FROM question_report
+WHERE question_id = $1 AND ($2::text IS NULL OR status = $2)
+ORDER BY created_at DESC, id
+`, questionID, statusFilter)
+	if err != nil {
+		return nil, fmt.Errorf("report - ListByQuestion - query: %w", err)
+	}
+	defer rows.Close()
+
+	reports := []entity.QuestionReport{}
+	for rows.Next() {
+		report, err := scanQuestionReport(rows)
+		if err != nil {
+			return nil, fmt.Errorf("report - ListByQuestion - scan: %w", err)
+		}
+		reports = append(reports, report)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("report - ListByQuestion - rows: %w", err)
+	}
+
+	return reports, nil
+}
+
+func (r repoQuestionReport) Close(
+	ctx context.Context,
+	questionID uuid.UUID,
+	status entity.QuestionReportStatus,
+	note string,
+	resolvedBy uuid.UUID,
+	notify func(entity.QuestionReport) entity.Notification,
+) ([]entity.QuestionReport, error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("report - Close - begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	rows, err := tx.Query(ctx, `
+UPDATE question_report
+SET status = $2, resolution_note = $3, resolved_by = $4, resolved_at = now()
+WHERE question_id = $1 AND status = 'open'
+RETURNING `+_reportColumns, questionID, string(status), note, resolvedBy)
+	if err != nil {
+		return nil, fmt.Errorf("report - Close - query: %w", err)
+	}
+	defer rows.Close()
+
+	var reports []entity.QuestionReport
+	for rows.Next() {
+		report, err := scanQuestionReport(rows)
+		if err != nil {
+			return nil, fmt.Errorf("report - Close - scan: %w", err)
+		}
+		reports = append(reports, report)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("report - Close - rows: %w", err)
+	}
+
+	notifications := make([]entity.Notification, len(reports))
+	for i, report := range reports {
+		notifications[i] = notify(report)
+	}
+
+	if len(notifications) > 0 {
+		sql, args, err := notificationInsert(r.Builder, notifications).ToSql()
+		if err != nil {
+			return nil, fmt.Errorf("report - Close - build: %w", err)
+		}
+		if _, err := tx.Exec(ctx, sql, args...); err != nil {
+			return nil, fmt.Errorf("report - Close - notify: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("report - Close - commit: %w", err)
+	}
+
+	return reports, nil
+}
+
+// repoNotification implements NotificationRepository.
+type repoNotification struct{ *postgres.Postgres }
+
+func (r repoNotification) Create(ctx context.Context, notifications []entity.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	sql, args, err := notificationInsert(r.Builder, notifications).ToSql()
+	if err != nil {
+		return fmt.Errorf("notification - Create - build: %w", err)
+	}
+
+	if _, err := r.Pool.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("notification - Create - exec: %w", err)
+	}
+
+	return nil
+}
+
+// notificationInsert builds the insert of notifications, shared with repos
+// that notify inside their own transactions.
+func notificationInsert(builder squirrel.StatementBuilderType, notifications []entity.Notification) squirrel.InsertBuilder {
+	insert := builder.
+		Insert("notification").
+		Columns("id", "user_id", "kind", "title", "body", "data")
+	for _, n := range notifications {
+		if n.ID == uuid.Nil {
+			n.ID = uuid.New()
+		}
+		data := n.Data
+		if data == nil {
+			data = map[string]string{}
+		}
+		insert = insert.Values(n.ID, n.UserID, n.Kind, n.Title, n.Body, data)
+	}
+
+	return insert
+}
+
+func (r repoNotification) ListByUser(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit int) ([]entity.Notification, error) {
+	rows, err := r.Pool.Query(ctx, `
+SELECT id, user_id, kind, title, body, data, created_at, read_at
+FROM notification
+WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL)
+ORDER BY created_at DESC, id
+LIMIT $3
+`, userID, unreadOnly, limit)
+	if err != nil {
+		return nil, fmt.Errorf("notification - ListByUser - query: %w", err)
+	}
+	defer rows.Close()
+
+	notifications := []entity.Notification{}
+	for rows.Next() {
+		var n entity.Notification
+		if err := rows.Scan(&n.ID, &n.UserID, &n.Kind, &n.Title, &n.Body, &n.Data, &n.CreatedAt, &n.ReadAt); err != nil {
+			return nil, fmt.Errorf("notification - ListByUser - scan: %w", err)
+		}
+		notifications = append(notifications, n)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("notification - ListByUser - rows: %w", err)
+	}
+
+	return notifications, nil
+}
+
+func (r repoNotification) MarkRead(ctx context.Context, userID, id uuid.UUID) error {
+	tag, err := r.Pool.Exec(ctx, `
+UPDATE notification SET read_at = COALESCE(read_at, now()) WHERE user_id = $1 AND id = $2
+`, userID, id)
+	if err != nil {
+		return fmt.Errorf("notification - MarkRead - exec: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repo.ErrNotFound
+	}
+
+	return nil
+}
+
+// repoTag implements TagRepository.
+type repoTag struct{ *postgres.Postgres }
+
+const _tagColumns = `t.id, t.name, t.kind, t.year, t.created_at,
+  (SELECT COUNT(*) FROM question_tag qt WHERE qt.tag_id = t.id)`
+
+func scanTag(row rowScanner) (entity.Tag, error) {
+	var t entity.Tag
+	err := row.Scan(&t.ID, &t.Name, &t.Kind, &t.Year, &t.CreatedAt, &t.QuestionCount)
+
+	return t, err
+}
+
+func filterTags(builder squirrel.SelectBuilder, filter repo.TagFilter) squirrel.SelectBuilder {
+	if filter.Kind != nil {
+		builder = builder.Where("t.kind = ?", string(*filter.Kind))
+	}
+	if filter.Search != "" {
+		builder = builder.Where("starts_with(lower(t.name), lower(?))", filter.Search)
+	}
+
+	return builder
+}
+
+func (r repoTag) List(ctx context.Context, filter repo.TagFilter) ([]entity.Tag, int, error) {
+	countSQL, countArgs, err := filterTags(r.Builder.Select("count(*)").From("tag t"), filter).ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("tag - List - build count: %w", err)
+	}
+
+	var total int
+	if err := r.Pool.QueryRow(ctx, countSQL, countArgs...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("tag - List - count: %w", err)
+	}
+
+	querySQL, args, err := filterTags(r.Builder.Select(_tagColumns).From("tag t"), filter).
+		OrderBy("t.kind", "t.year DESC NULLS LAST", "lower(t.name)").
+		Limit(uint64(filter.PageSize)).
+		Offset(uint64((filter.Page - 1) * filter.PageSize)).
+		ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("tag - List - build: %w", err)
+	}
+
+	rows, err := r.Pool.Query(ctx, querySQL, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("tag - List - query: %w", err)
+	}
+	defer rows.Close()
+
+	tags := []entity.Tag{}
+	for rows.Next() {
+		t, err := scanTag(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("tag - List - scan: %w", err)
+		}
+		tags = append(tags, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("tag - List - rows: %w", err)
+	}
+
+	return tags, total, nil
+}
+
+func (r repoTag) Get(ctx context.Context, id uuid.UUID) (entity.Tag, error) {
+	t, err := scanTag(r.Pool.QueryRow(ctx, `SELECT `+_tagColumns+` FROM tag t WHERE t.id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Tag{}, repo.ErrNotFound
+	}
+	if err != nil {
+		return entity.Tag{}, fmt.Errorf("tag - Get - scan: %w", err)
+	}
+
+	return t, nil
+}
+
+func (r repoTag) Create(ctx context.Context, tag entity.Tag) (entity.Tag, error) {
+	if tag.ID == uuid.Nil {
+		tag.ID = uuid.New()
+	}
+
+	err := r.Pool.QueryRow(ctx, `
+INSERT INTO tag (id, name, kind, year)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (lower(name)) DO NOTHING
+RETURNING created_at
+`, tag.ID, tag.Name, string(tag.Kind), tag.Year).Scan(&tag.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Tag{}, repo.ErrConflict
+	}
+	if err != nil {
+		return entity.Tag{}, fmt.Errorf("tag - Create - scan: %w", err)
+	}
+
+	return tag, nil
+}
+
+func (r repoTag) Update(ctx context.Context, tag entity.Tag) (entity.Tag, error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return entity.Tag{}, fmt.Errorf("tag - Update - begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err = scanTag(tx.QueryRow(ctx, `
+UPDATE tag t SET name = $2, kind = $3, year = $4
+WHERE t.id = $1 AND NOT EXISTS (SELECT 1 FROM tag o WHERE lower(o.name) = lower($2) AND o.id <> $1)
+RETURNING `+_tagColumns, tag.ID, tag.Name, string(tag.Kind), tag.Year))
+	if errors.Is(err, pgx.ErrNoRows) {
+		var exists bool
+		if err := tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM tag WHERE id = $1)", tag.ID).Scan(&exists); err != nil {
+			return entity.Tag{}, fmt.Errorf("tag - Update - exists: %w", err)
+		}
+		if exists {
+			return entity.Tag{}, repo.ErrConflict
+		}
+
+		return entity.Tag{}, repo.ErrNotFound
+	}
+	if err != nil {
+		return entity.Tag{}, fmt.Errorf("tag - Update - scan: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return entity.Tag{}, fmt.Errorf("tag - Update - commit: %w", err)
+	}
+
+	return tag, nil
+}
+
+func (r repoTag) Delete(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.Pool.Exec(ctx, `DELETE FROM tag WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("tag - Delete - exec: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repo.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r repoTag) SetQuestionTags(ctx context.Context, questionID uuid.UUID, names []string) error {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("tag - SetQuestionTags - begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var exists bool
+	if err := tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM question WHERE id = $1)", questionID).Scan(&exists); err != nil {
+		return fmt.Errorf("tag - SetQuestionTags - exists: %w", err)
+	}
+	if !exists {
+		return repo.ErrNotFound
+	}
+
+	if len(names) > 0 {
+		_, err = tx.Exec(ctx, `
+INSERT INTO tag (id, name)
+SELECT gen_random_uuid(), name FROM unnest($1::text[]) AS name
+ON CONFLICT (lower(name)) DO NOTHING
+`, names)
+		if err != nil {
+			return fmt.Errorf("tag - SetQuestionTags - create: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM question_tag WHERE question_id = $1`, questionID); err != nil {
+		return fmt.Errorf("tag - SetQuestionTags - clear: %w", err)
+	}
+
+	if len(names) > 0 {
+		_, err = tx.Exec(ctx, `
+INSERT INTO question_tag (question_id, tag_id)
+SELECT $1, id FROM tag WHERE lower(name) = ANY($2)
+`, questionID, lowerNames(names))
+		if err != nil {
+			return fmt.Errorf("tag - SetQuestionTags - link: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("tag - SetQuestionTags - commit: %w", err)
+	}
+
+	return nil
+}
+
+// repoLocalization implements LocalizationRepository.
+type repoLocalization struct{ *postgres.Postgres }
+
+const _localizationColumns = `l.question_id, l.language, l.question_text, l.option_a, l.option_b, l.option_c, l.option_d,
+  l.reason_text, l.explanation, l.matrix_rows, l.matrix_columns, l.source_version, l.status,
+  l.reviewer_id, l.reviewed_at, l.updated_at, l.source_version < q.version`
+
+func scanLocalization(row rowScanner) (entity.QuestionLocalization, error) {
+	var l entity.QuestionLocalization
+	err := row.Scan(&l.QuestionID, &l.Language, &l.QuestionText, &l.OptionA, &l.OptionB, &l.OptionC, &l.OptionD,
+		&l.ReasonText, &l.Explanation, &l.MatrixRows, &l.MatrixColumns, &l.SourceVersion, &l.Status,
+		&l.ReviewerID, &l.ReviewedAt, &l.UpdatedAt, &l.Stale)
+
+	return l, err
+}
+
+func (r repoLocalization) ListPending(ctx context.Context, language string, limit int) ([]entity.Question, error) {
+	querySQL, args, err := repoQuestion(r).selectQuestions().
+		LeftJoin("question_localization l ON l.question_id = q.id AND l.language = ?", language).
+		Where("q.status = 'published'").
+		Where("(l.question_id IS NULL OR l.source_version < q.version)").
+		OrderBy("l.question_id IS NOT NULL", "q.created_at", "q.id").
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("localization - ListPending - build: %w", err)
+	}
+
+	rows, err := r.Pool.Query(ctx, querySQL, args...)
+	if err != nil {
+		return nil, fmt.Errorf("localization - ListPending - query: %w", err)
+	}
+	defer rows.Close()
+
+	var questions []entity.Question
+	for rows.Next() {
+		q, err := scanQuestion(rows)
+		if err != nil {
+			return nil, fmt.Errorf("localization - ListPending - scan: %w", err)
+		}
+		questions = append(questions, q)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("localization - ListPending - rows: %w", err)
+	}
+
+	return questions, nil
+}
+
+func (r repoLocalization) Save(ctx context.Context, l entity.QuestionLocalization) error {
+	_, err := r.Pool.Exec(ctx, `
+INSERT INTO question_localization (
+  question_id, language, question_text, option_a, option_b, option_c, option_d,
+  reason_text, explanation, matrix_rows, matrix_columns, source_version, status,
+  reviewer_id, reviewed_at, updated_at
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, now())
+ON CONFLICT (question_id, language) DO UPDATE SET
+  question_text = EXCLUDED.question_text,
+  option_a = EXCLUDED.option_a,
+  option_b = EXCLUDED.option_b,
+  option_c = EXCLUDED.option_c,
+  option_d = EXCLUDED.option_d,
+  reason_text = EXCLUDED.reason_text,
+  explanation = EXCLUDED.explanation,
+  matrix_rows = EXCLUDED.matrix_rows,
+  matrix_columns = EXCLUDED.matrix_columns,
+  source_version = EXCLUDED.source_version,
+  status = EXCLUDED.status,
+  reviewer_id = EXCLUDED.reviewer_id,
+  reviewed_at = EXCLUDED.reviewed_at,
+  updated_at = now()
+`, l.QuestionID, l.Language, l.QuestionText, l.OptionA, l.OptionB, l.OptionC, l.OptionD,
+		l.ReasonText, l.Explanation, l.MatrixRows, l.MatrixColumns, l.SourceVersion, string(l.Status),
+		l.ReviewerID, l.ReviewedAt)
+	if err != nil {
+		return fmt.Errorf("localization - Save - exec: %w", err)
+	}
+
+	return nil
+}
+
+func filterLocalizations(builder squirrel.SelectBuilder, filter repo.LocalizationFilter) squirrel.SelectBuilder {
+	if filter.Language != "" {
+		builder = builder.Where("l.language = ?", filter.Language)
+	}
+	if filter.Status != nil {
+		builder = builder.Where("l.status = ?", string(*filter.Status))
+	}
+	if filter.Stale != nil {
+		builder = builder.Where("(l.source_version < q.version) = ?", *filter.Stale)
+	}
+
+	return builder
+}
+
+func (r repoLocalization) List(ctx context.Context, filter repo.LocalizationFilter) ([]entity.QuestionLocalization, int, error) {
+	from := "question_localization l JOIN question q ON q.id = l.question_id"
+
+	countSQL, countArgs, err := filterLocalizations(r.Builder.Select("COUNT(*)").From(from), filter).ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("localization - List - build count: %w", err)
+	}
+
+	var total int
+	if err := r.Pool.QueryRow(ctx, countSQL, countArgs...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("localization - List - count: %w", err)
+	}
+
+	querySQL, args, err := filterLocalizations(r.Builder.Select(_localizationColumns).From(from), filter).
+		OrderBy("l.updated_at DESC", "l.question_id", "l.language").
+		Limit(uint64(filter.PageSize)).
+		Offset(uint64((filter.Page - 1) * filter.PageSize)).
+		ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("localization - List - build: %w", err)
+	}
+
+	rows, err := r.Pool.Query(ctx, querySQL, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("localization - List - query: %w", err)
+	}
+	defer rows.Close()
+
+	items := []entity.QuestionLocalization{}
+	for rows.Next() {
+		l, err := scanLocalization(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("localization - List - scan: %w", err)
+		}
+		items = append(items, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("localization - List - rows: %w", err)
+	}
+
+	return items, total, nil
+}
+
+func (r repoLocalization) Get(ctx context.Context, questionID uuid.UUID, language string) (entity.QuestionLocalization, error) {
+	l, err := scanLocalization(r.Pool.QueryRow(ctx, `
+SELECT `+_localizationColumns+`
+FROM question_localization l JOIN question q ON q.id = l.question_id
+WHERE l.question_id = $1 AND l.language = $2`, questionID, language))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.QuestionLocalization{}, repo.ErrNotFound
+	}
+	if err != nil {
+		return entity.QuestionLocalization{}, fmt.Errorf("localization - Get - scan: %w", err)
+	}
+
+	return l, nil
+}
+
+func (r repoLocalization) ListForQuestions(ctx context.Context, questionIDs []uuid.UUID, language string) ([]entity.QuestionLocalization, error) {
+	if len(questionIDs) == 0 {
+		return nil, nil
+	}
+
+	rows, err := r.Pool.Query(ctx, `
+SELECT `+_localizationColumns+`
+FROM question_localization l JOIN question q ON q.id = l.question_id
+WHERE l.question_id = ANY($1) AND l.language = $2`, questionIDs, language)
+	if err != nil {
+		return nil, fmt.Errorf("localization - ListForQuestions - query: %w", err)
+	}
+	defer rows.Close()
+
+	var items []entity.QuestionLocalization
+	for rows.Next() {
+		l, err := scanLocalization(rows)
+		if err != nil {
+			return nil, fmt.Errorf("localization - ListForQuestions - scan: %w", err)
+		}
+		items = append(items, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("localization - ListForQuestions - rows: %w", err)
+	}
+
+	return items, nil
+}
+
+func (r repoLocalization) GetLanguage(ctx context.Context, userID uuid.UUID) (string, error) {
+	var language string
+	err := r.Pool.QueryRow(ctx, `SELECT preferred_language FROM "user" WHERE id = $1`, userID).Scan(&language)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.LanguageEnglish, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("localization - GetLanguage - scan: %w", err)
+	}
+
+	return language, nil
+}
+
+func (r repoLocalization) SetLanguage(ctx context.Context, userID uuid.UUID, language string) error {
+	tag, err := r.Pool.Exec(ctx, `UPDATE "user" SET preferred_language = $2, updated_at = now() WHERE id = $1`, userID, language)
+	if err != nil {
+		return fmt.Errorf("localization - SetLanguage - exec: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repo.ErrNotFound
+	}
+
+	return nil
+}
+
+// repoItemAnalysis implements ItemAnalysisRepository.
+type repoItemAnalysis struct{ *postgres.Postgres }
+
+func (r repoItemAnalysis) RefreshLearners(ctx context.Context) error {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("item analysis - RefreshLearners - begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, "DELETE FROM item_analysis_learner"); err != nil {
+		return fmt.Errorf("item analysis - RefreshLearners - delete: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+INSERT INTO item_analysis_learner (user_id, attempts, correct)
+SELECT user_id, COUNT(*), COUNT(*) FILTER (WHERE is_correct)
+FROM (
+  SELECT DISTINCT ON (a.user_id, a.question_id) a.user_id, a.is_correct
+  FROM user_question_attempt a
+  ORDER BY a.user_id, a.question_id, a.created_at, a.id
+) first
+GROUP BY user_id
+`)
+	if err != nil {
+		return fmt.Errorf("item analysis - RefreshLearners - insert: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("item analysis - RefreshLearners - commit: %w", err)
+	}
+
+	return nil
+}
+
+// ListAttemptStats scores each learner by their accuracy on their other
+// first attempts, leaving one question out so an item is not correlated
+// with itself. Only the page's attempts are read; learner totals come from
+// item_analysis_learner, and learners newer than it have no ability.
+func (r repoItemAnalysis) ListAttemptStats(ctx context.Context, after uuid.UUID, limit int) ([]repo.ItemAttemptStats, error) {
+	rows, err := r.Pool.Query(ctx, `
+WITH page AS (
+  SELECT DISTINCT question_id FROM user_question_attempt
+  WHERE question_id > $1
+  ORDER BY question_id
+  LIMIT $2
+), first AS (
+  SELECT DISTINCT ON (a.user_id, a.question_id)
+    a.user_id, a.question_id, a.is_correct, a.time_taken_ms,
+    COALESCE(a.selected_options, CASE WHEN a.selected_option IS NOT NULL THEN ARRAY[a.selected_option] END) AS selected
+  FROM user_question_attempt a
+  JOIN page p USING (question_id)
+  ORDER BY a.user_id, a.question_id, a.created_at, a.id
+), scored AS (
+  SELECT f.*,
+    CASE WHEN l.attempts > 1 THEN (l.correct - f.is_correct::int)::float8 / (l.attempts - 1) END AS ability
+  FROM first f
+  LEFT JOIN item_analysis_learner l USING (user_id)
+)
+SELECT s.question_id, q.choice_type, q.correct_options,
+  COUNT(*), COUNT(*) FILTER (WHERE s.is_correct),
+  corr(s.is_correct::int::float8, s.ability),
+  percentile_cont(0.5) WITHIN GROUP (ORDER BY s.time_taken_ms),
+  COALESCE(avg(s.ability), 0), COALESCE(stddev_pop(s.ability), 0),
+  (SELECT COALESCE(jsonb_agg(jsonb_build_object('option', o.option, 'count', o.count, 'abilityMean', o.ability_mean) ORDER BY o.option), '[]')
+   FROM (
+     SELECT opt AS option, COUNT(*) AS count, COALESCE(avg(x.ability), 0) AS ability_mean
+     FROM scored x, unnest(x.selected) AS opt
+     WHERE x.question_id = s.question_id
+     GROUP BY opt
+   ) o)
+FROM scored s
+JOIN question q ON q.id = s.question_id
+GROUP BY s.question_id, q.choice_type, q.correct_options
+ORDER BY s.question_id
+`, after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("item analysis - ListAttemptStats - query: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []repo.ItemAttemptStats
+	for rows.Next() {
+		var s repo.ItemAttemptStats
+		var choiceType string
+		if err := rows.Scan(&s.QuestionID, &choiceType, &s.CorrectOptions, &s.Attempts, &s.Correct,
+			&s.Discrimination, &s.MedianTimeMs, &s.AbilityMean, &s.AbilitySD, &s.Options); err != nil {
+			return nil, fmt.Errorf("item analysis - ListAttemptStats - scan: %w", err)
+		}
+		s.ChoiceType = entity.QuestionChoiceType(choiceType)
+		stats = append(stats, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("item analysis - ListAttemptStats - rows: %w", err)
+	}
+
+	return stats, nil
+}
+
+func (r repoItemAnalysis) Save(ctx context.Context, analyses []entity.ItemAnalysis) (int, error) {
+	if len(analyses) == 0 {
+		return 0, nil
+	}
+
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("item analysis - Save - begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	ids := make([]uuid.UUID, len(analyses))
+	for i, a := range analyses {
+		ids[i] = a.QuestionID
+
+		options := a.Options
+		if options == nil {
+			options = []entity.OptionAnalysis{}
+		}
+		flags := make([]string, len(a.Flags))
+		for j, flag := range a.Flags {
+			flags[j] = string(flag)
+		}
+
+		_, err := tx.Exec(ctx, `
+INSERT INTO question_item_stats (question_id, attempts, p_value, discrimination, median_time_ms, options, flags, calibrated_level, computed_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+ON CONFLICT (question_id) DO UPDATE SET
+  attempts = EXCLUDED.attempts,
+  p_value = EXCLUDED.p_value,
+  discrimination = EXCLUDED.discrimination,
+  median_time_ms = EXCLUDED.median_time_ms,
+  options = EXCLUDED.options,
+  flags = EXCLUDED.flags,
+  calibrated_level = EXCLUDED.calibrated_level,
+  computed_at = EXCLUDED.computed_at
+`, a.QuestionID, a.Attempts, a.PValue, a.Discrimination, a.MedianTimeMs, options, flags, a.CalibratedLevel, a.ComputedAt)
+		if err != nil {
+			return 0, fmt.Errorf("item analysis - Save - upsert: %w", err)
+		}
+	}
+
+	tag, err := tx.Exec(ctx, `
+UPDATE question q
+SET difficulty_level = s.calibrated_level
+FROM question_item_stats s
+WHERE s.question_id = q.id
+  AND q.id = ANY($1)
+  AND s.calibrated_level IS NOT NULL
+  AND NOT q.difficulty_locked
+  AND q.difficulty_level <> s.calibrated_level
+`, ids)
+	if err != nil {
+		return 0, fmt.Errorf("item analysis - Save - recalibrate: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("item analysis - Save - commit: %w", err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+func (r repoItemAnalysis) selectAnalyses() squirrel.SelectBuilder {
+	return r.Builder.
+		Select("s.question_id", "q.question_text", "q.choice_type", "q.difficulty_level", "q.difficulty_locked",
+			"s.calibrated_level", "s.attempts", "s.p_value", "s.discrimination", "s.median_time_ms",
+			"s.options", "s.flags", "s.computed_at").
+		From("question_item_stats s").
+		Join("question q ON q.id = s.question_id").
+		Join("exam_type_lookup e ON e.id = q.exam_type_id")
+}
+
+func filterItemAnalyses(builder squirrel.SelectBuilder, filter repo.ItemAnalysisFilter) squirrel.SelectBuilder {
+	if filter.Exam != nil {
+		builder = builder.Where("e.code = ?", string(*filter.Exam))
+	}
+	if filter.SubjectID != nil {
+		builder = builder.Where("q.subject_id = ?", *filter.SubjectID)
+	}
+	if filter.TopicID != nil {
+		builder = builder.Where("q.topic_id = ?", *filter.TopicID)
+	}
+	if filter.Flag != nil {
+		builder = builder.Where("? = ANY(s.flags)", string(*filter.Flag))
+	}
+	if filter.FlaggedOnly {
+		builder = builder.Where("cardinality(s.flags) > 0")
+	}
+	if filter.MinAttempts > 0 {
+		builder = builder.Where("s.attempts >= ?", filter.MinAttempts)
+	}
+
+	return builder
+}
+
+func scanItemAnalysis(row rowScanner) (entity.ItemAnalysis, error) {
+	var a entity.ItemAnalysis
+	var choiceType string
+	var flags []string
+	err := row.Scan(&a.QuestionID, &a.QuestionText, &choiceType, &a.DifficultyLevel, &a.DifficultyLocked,
+		&a.CalibratedLevel, &a.Attempts, &a.PValue, &a.Discrimination, &a.MedianTimeMs,
+		&a.Options, &flags, &a.ComputedAt)
+	if err != nil {
+		return entity.ItemAnalysis{}, err
+	}
+	a.ChoiceType = entity.QuestionChoiceType(choiceType)
+	a.Flags = make([]entity.ItemFlag, len(flags))
+	for i, flag := range flags {
+		a.Flags[i] = entity.ItemFlag(flag)
+	}
+
+	return a, nil
+}
+
+// List puts the questions with the most flags first, then the most attempted.
+func (r repoItemAnalysis) List(ctx context.Context, filter repo.ItemAnalysisFilter) ([]entity.ItemAnalysis, int, error) {
+	countSQL, countArgs, err := filterItemAnalyses(r.Builder.
+		Select("count(*)").
+		From("question_item_stats s").
+		Join("question q ON q.id = s.question_id").
+		Join("exam_type_lookup e ON e.id = q.exam_type_id"), filter).
+		ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("item analysis - List - build count: %w", err)
+	}
+
+	var total int
+	if err := r.Pool.QueryRow(ctx, countSQL, countArgs...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("item analysis - List - count: %w", err)
+	}
+
+	querySQL, args, err := filterItemAnalyses(r.selectAnalyses(), filter).
+		OrderBy("cardinality(s.flags) DESC", "s.attempts DESC", "s.question_id").
+		Limit(uint64(filter.PageSize)).
+		Offset(uint64((filter.Page - 1) * filter.PageSize)).
+		ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("item analysis - List - build: %w", err)
+	}
+
+	rows, err := r.Pool.Query(ctx, querySQL, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("item analysis - List - query: %w", err)
+	}
+	defer rows.Close()
+
+	analyses := []entity.ItemAnalysis{}
+	for rows.Next() {
+		a, err := scanItemAnalysis(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("item analysis - List - scan: %w", err)
+		}
+		analyses = append(analyses, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("item analysis - List - rows: %w", err)
+	}
+
+	return analyses, total, nil
+}
+
+func (r repoItemAnalysis) Get(ctx context.Context, questionID uuid.UUID) (entity.ItemAnalysis, error) {
+	querySQL, args, err := r.selectAnalyses().Where("s.question_id = ?", questionID).ToSql()
+	if err != nil {
+		return entity.ItemAnalysis{}, fmt.Errorf("item analysis - Get - build: %w", err)
+	}
+
+	a, err := scanItemAnalysis(r.Pool.QueryRow(ctx, querySQL, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.ItemAnalysis{}, repo.ErrNotFound
+	}
+	if err != nil {
+		return entity.ItemAnalysis{}, fmt.Errorf("item analysis - Get - scan: %w", err)
+	}
+
+	return a, nil
+}
+
+// repoExam implements ExamRepository.
+type repoExam struct{ *postgres.Postgres }
+
+const _examConfigColumns = `c.id, e.code, c.name, c.type, COALESCE(c.description, ''), c.num_questions,
+c.time_limit_minutes, c.marks_per_correct::float8, c.negative_per_wrong::float8, c.entry_fee_cents,
+c.schedule_start_at, c.schedule_end_at, c.register_until, c.capacity, c.status, c.created_at, c.updated_at`
+
+// scanExamConfig scans _examConfigColumns followed by extra columns.
+func scanExamConfig(row rowScanner, extra ...any) (entity.ExamConfig, error) {
+	var c entity.ExamConfig
+	err := row.Scan(append([]any{&c.ID, &c.Exam, &c.Name, &c.Type, &c.Description, &c.NumQuestions,
+		&c.TimeLimitMinutes, &c.MarksPerCorrect, &c.NegativePerWrong, &c.EntryFee,
+		&c.ScheduleStartAt, &c.ScheduleEndAt, &c.RegisterUntil, &c.Capacity, &c.Status, &c.CreatedAt, &c.UpdatedAt}, extra...)...)
+
+	return c, err
+}
+
+func (r repoExam) ListConfigs(ctx context.Context, exam *entity.ExamCategory) ([]entity.ExamConfig, error) {
+	builder := r.Builder.
+		Select(_examConfigColumns).
+		From("exam_config c").
+		Join("exam_type_lookup e ON e.id = c.exam_type_id")
+
+	if exam != nil {
+		builder = builder.Where("e.code = ?", string(*exam))
+	}
+
+	querySQL, args, err := builder.
+		OrderBy("c.schedule_start_at ASC NULLS LAST", "c.created_at DESC").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("exam - ListConfigs - build: %w", err)
+	}
+
+	rows, err := r.Pool.Query(ctx, querySQL, args...)
+	if err != nil {
+		return nil, fmt.Errorf("exam - ListConfigs - query: %w", err)
+	}
+	defer rows.Close()
+
+	configs := []entity.ExamConfig{}
+	for rows.Next() {
+		c, err := scanExamConfig(rows)
+		if err != nil {
+			return nil, fmt.Errorf("exam - ListConfigs - scan: %w", err)
+		}
+		configs = append(configs, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("exam - ListConfigs - rows: %w", err)
+	}
+
+	return configs, nil
+}
+
+func (r repoExam) CreateConfig(ctx context.Context, config entity.ExamConfig) (entity.ExamConfig, error) {
+	if config.ID == uuid.Nil {
+		config.ID = uuid.New()
+	}
+
+	created, err := scanExamConfig(r.Pool.QueryRow(ctx, `
+WITH c AS (
+  INSERT INTO exam_config (id, exam_type_id, name, type, description, num_questions, time_limit_minutes,
+    marks_per_correct, negative_per_wrong, entry_fee_cents, schedule_start_at, schedule_end_at, register_until,
+    capacity, status)
+  SELECT $1, e.id, $3, $4, NULLIF($5, ''), $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
+  FROM exam_type_lookup e WHERE e.code = $2
+  RETURNING *
+)
+SELECT `+_examConfigColumns+` FROM c
+JOIN exam_type_lookup e ON e.id = c.exam_type_id
+`, config.ID, string(config.Exam), config.Name, string(config.Type), config.Description, config.NumQuestions,
+		config.TimeLimitMinutes, config.MarksPerCorrect, config.NegativePerWrong, config.EntryFee,
+		config.ScheduleStartAt, config.ScheduleEndAt, config.RegisterUntil, config.Capacity, string(config.Status)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.ExamConfig{}, fmt.Errorf("exam - CreateConfig - exam %s: %w", config.Exam, repo.ErrNotFound)
+	}
+	if err != nil {
+		return entity.ExamConfig{}, fmt.Errorf("exam - CreateConfig - scan: %w", err)
+	}
+
+	return created, nil
+}
+
+func (r repoExam) GetConfig(ctx context.Context, id uuid.UUID) (entity.ExamConfig, error) {
+	c, err := scanExamConfig(r.Pool.QueryRow(ctx, `
+SELECT `+_examConfigColumns+` FROM exam_config c
+JOIN exam_type_lookup e ON e.id = c.exam_type_id
+WHERE c.id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.ExamConfig{}, repo.ErrNotFound
+	}
+	if err != nil {
+		return entity.ExamConfig{}, fmt.Errorf("exam - GetConfig - scan: %w", err)
+	}
+
+	return c, nil
+}
+
+func (r repoExam) UpdateConfig(ctx context.Context, config entity.ExamConfig, from entity.ExamStatus) (entity.ExamConfig, error) {
+	updated, err := scanExamConfig(r.Pool.QueryRow(ctx, `
+WITH c AS (
+  UPDATE exam_config
+  SET name = $3, type = $4, description = NULLIF($5, ''), num_questions = $6, time_limit_minutes = $7,
+    marks_per_correct = $8, negative_per_wrong = $9, entry_fee_cents = $10,
+    schedule_start_at = $11, schedule_end_at = $12, register_until = $13, capacity = $14, status = $15,
+    updated_at = now()
+  WHERE id = $1 AND status = $2
+  RETURNING *
+)
+SELECT `+_examConfigColumns+` FROM c
+JOIN exam_type_lookup e ON e.id = c.exam_type_id
+`, config.ID, string(from), config.Name, string(config.Type), config.Description, config.NumQuestions,
+		config.TimeLimitMinutes, config.MarksPerCorrect, config.NegativePerWrong, config.EntryFee,
+		config.ScheduleStartAt, config.ScheduleEndAt, config.RegisterUntil, config.Capacity, string(config.Status)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		var exists bool
+		if err := r.Pool.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM exam_config WHERE id = $1)", config.ID).Scan(&exists); err != nil {
+			return entity.ExamConfig{}, fmt.Errorf("exam - UpdateConfig - exists: %w", err)
+		}
+		if exists {
+			return entity.ExamConfig{}, repo.ErrConflict
+		}
+
+		return entity.ExamConfig{}, repo.ErrNotFound
+	}
+	if err != nil {
+		return entity.ExamConfig{}, fmt.Errorf("exam - UpdateConfig - scan: %w", err)
+	}
+
+	return updated, nil
+}
+
+func (r repoExam) DeleteConfig(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.Pool.Exec(ctx, `
+DELETE FROM exam_config c
+WHERE c.id = $1 AND NOT EXISTS (SELECT 1 FROM exam_registration g WHERE g.exam_config_id = c.id)`, id)
+	if err != nil {
+		return fmt.Errorf("exam - DeleteConfig - exec: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		var exists bool
+		if err := r.Pool.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM exam_config WHERE id = $1)", id).Scan(&exists); err != nil {
+			return fmt.Errorf("exam - DeleteConfig - exists: %w", err)
+		}
+		if exists {
+			return repo.ErrConflict
+		}
+
+		return repo.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r repoExam) ListSummaries(ctx context.Context, userID uuid.UUID) ([]entity.ExamSummary, error) {
+	rows, err := r.Pool.Query(ctx, `
+SELECT `+_examConfigColumns+`,
+  EXISTS (SELECT 1 FROM exam_registration g WHERE g.exam_config_id = c.id AND g.user_id = $1 AND g.status = 'REGISTERED'),
+  GREATEST(c.capacity - (SELECT count(*) FROM exam_registration g WHERE g.exam_config_id = c.id AND g.status = 'REGISTERED'), 0),
+  EXISTS (SELECT 1 FROM exam_attempt a WHERE a.exam_config_id = c.id AND a.user_id = $1 AND a.score IS NOT NULL),
+  (SELECT max(a.score) FROM exam_attempt a WHERE a.exam_config_id = c.id AND a.user_id = $1)
+FROM exam_config c
+JOIN exam_type_lookup e ON e.id = c.exam_type_id
+WHERE c.status IN ('SCHEDULED', 'ONGOING')
+ORDER BY c.schedule_start_at ASC NULLS LAST, c.created_at DESC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("exam - ListSummaries - query: %w", err)
+	}
+	defer rows.Close()
+
+	summaries := []entity.ExamSummary{}
+	for rows.Next() {
+		var summary entity.ExamSummary
+		summary.Config, err = scanExamConfig(rows, &summary.IsRegistered, &summary.SeatsLeft,
+			&summary.IsCompleted, &summary.BestScore)
+		if err != nil {
+			return nil, fmt.Errorf("exam - ListSummaries - scan: %w", err)
+		}
+		summaries = append(summaries, summary)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("exam - ListSummaries - rows: %w", err)
+	}
+
+	return summaries, nil
+}
+
+const _registrationColumns = `g.id, g.exam_config_id, g.user_id, g.status, g.fee_cents, g.coupon_id, g.covered_cents,
+g.refund_cents, g.registered_at, g.cancelled_at`
+
+// scanRegistration scans _registrationColumns followed by extra columns.
+func scanRegistration(row rowScanner, extra ...any) (entity.ExamRegistration, error) {
+	var g entity.ExamRegistration
+	err := row.Scan(append([]any{&g.ID, &g.ExamID, &g.UserID, &g.Status, &g.Fee, &g.CouponID, &g.Covered,
+		&g.Refund, &g.RegisteredAt, &g.CancelledAt}, extra...)...)
+
+	return g, err
+}
+
+func (r repoExam) Register(ctx context.Context, examID, userID uuid.UUID, couponCode string) (entity.ExamRegistration, bool, error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return entity.ExamRegistration{}, false, fmt.Errorf("exam - Register - begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// The exam row lock serializes registrations, so seats are counted and
+	// repeated requests seen consistently.
+	var (
+		name     string
+		fee      int
+		capacity *int
+	)
+	err = tx.QueryRow(ctx, "SELECT name, entry_fee_cents, capacity FROM exam_config WHERE id = $1 FOR UPDATE", examID).
+		Scan(&name, &fee, &capacity)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.ExamRegistration{}, false, repo.ErrNotFound
+	}
+	if err != nil {
+		return entity.ExamRegistration{}, false, fmt.Errorf("exam - Register - lock: %w", err)
+	}
+
+	existing, err := scanRegistration(tx.QueryRow(ctx, `
+SELECT `+_registrationColumns+` FROM exam_registration g
+WHERE g.exam_config_id = $1 AND g.user_id = $2`, examID, userID))
+	switch {
+	case err == nil && existing.Status == entity.ExamRegistrationRegistered:
+		return existing, false, nil
+	case err != nil && !errors.Is(err, pgx.ErrNoRows):
+		return entity.ExamRegistration{}, false, fmt.Errorf("exam - Register - existing: %w", err)
+	}
+
+	if capacity != nil {
+		var taken int
+		if err := tx.QueryRow(ctx, "SELECT count(*) FROM exam_registration WHERE exam_config_id = $1 AND status = 'REGISTERED'", examID).
+			Scan(&taken); err != nil {
+			return entity.ExamRegistration{}, false, fmt.Errorf("exam - Register - seats: %w", err)
+		}
+		if taken >= *capacity {
+			return entity.ExamRegistration{}, false, repo.ErrCapacity
+		}
+	}
+
+	var (
+		couponID *uuid.UUID
+		covered  int
+	)
+	if couponCode != "" {
+		coupon, err := claimCoupon(ctx, tx, couponCode, userID, entity.CouponTypeEntryPass)
+		if errors.Is(err, repo.ErrNotFound) {
+			return entity.ExamRegistration{}, false, repo.ErrCouponUnavailable
+		}
+		if err != nil {
+			return entity.ExamRegistration{}, false, err
+		}
+		covered = fee
+		if coupon.Amount > 0 && coupon.Amount < fee {
+			covered = coupon.Amount
+		}
+		couponID = &coupon.ID
+	}
+
+	paid := fee - covered
+	if paid > 0 {
+		if err := debitWallet(ctx, tx, userID, paid); err != nil {
+			return entity.ExamRegistration{}, false, err
+		}
+		if err := insertWalletTx(ctx, tx, userID, &examID, -paid, entity.WalletTxExamEntry, "Entry fee: "+name); err != nil {
+			return entity.ExamRegistration{}, false, err
+		}
+	}
+
+	// A cancelled registration is reused, keeping one row per user and exam.
+	reg, err := scanRegistration(tx.QueryRow(ctx, `
+INSERT INTO exam_registration AS g (id, exam_config_id, user_id, fee_cents, coupon_id, covered_cents)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (exam_config_id, user_id) DO UPDATE
+SET status = 'REGISTERED', fee_cents = EXCLUDED.fee_cents, coupon_id = EXCLUDED.coupon_id,
+  covered_cents = EXCLUDED.covered_cents, refund_cents = 0, registered_at = now(), cancelled_at = NULL
+RETURNING `+_registrationColumns, uuid.New(), examID, userID, paid, couponID, covered))
+	if err != nil {
+		return entity.ExamRegistration{}, false, fmt.Errorf("exam - Register - insert: %w", err)
+	}
+
+	if couponID != nil {
+		if _, err := tx.Exec(ctx, `
+INSERT INTO coupon_redemption (id, coupon_id, user_id, exam_registration_id, amount_cents)
+VALUES ($1, $2, $3, $4, $5)`, uuid.New(), *couponID, userID, reg.ID, covered); err != nil {
+			return entity.ExamRegistration{}, false, fmt.Errorf("exam - Register - redemption: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return entity.ExamRegistration{}, false, fmt.Errorf("exam - Register - commit: %w", err)
+	}
+
+	return reg, true, nil
+}
+
+func (r repoExam) GetRegistration(ctx context.Context, examID, userID uuid.UUID) (entity.ExamRegistration, error) {
+	reg, err := scanRegistration(r.Pool.QueryRow(ctx, `
+SELECT `+_registrationColumns+` FROM exam_registration g
+WHERE g.exam_config_id = $1 AND g.user_id = $2`, examID, userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.ExamRegistration{}, repo.ErrNotFound
+	}
+	if err != nil {
+		return entity.ExamRegistration{}, fmt.Errorf("exam - GetRegistration - scan: %w", err)
+	}
+
+	return reg, nil
+}
+
+func (r repoExam) Cancel(ctx context.Context, examID, userID uuid.UUID, percent int) (entity.ExamRegistration, error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return entity.ExamRegistration{}, fmt.Errorf("exam - Cancel - begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var name string
+	reg, err := scanRegistration(tx.QueryRow(ctx, `
+SELECT `+_registrationColumns+`, c.name FROM exam_registration g
+JOIN exam_config c ON c.id = g.exam_config_id
+WHERE g.exam_config_id = $1 AND g.user_id = $2 AND g.status = 'REGISTERED'
+FOR UPDATE OF g`, examID, userID), &name)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.ExamRegistration{}, repo.ErrNotFound
+	}
+	if err != nil {
+		return entity.ExamRegistration{}, fmt.Errorf("exam - Cancel - lock: %w", err)
+	}
+
+	refund := reg.Fee * percent / 100
+	if refund > 0 {
+		if err := insertWalletTx(ctx, tx, userID, &examID, refund, entity.WalletTxExamRefund, "Refund: "+name); err != nil {
+			return entity.ExamRegistration{}, err
+		}
+	}
+
+	if _, err := tx.Exec(ctx, "DELETE FROM coupon_redemption WHERE exam_registration_id = $1", reg.ID); err != nil {
+		return entity.ExamRegistration{}, fmt.Errorf("exam - Cancel - release coupon: %w", err)
+	}
+
+	reg, err = scanRegistration(tx.QueryRow(ctx, `
+UPDATE exam_registration g SET status = 'CANCELLED', refund_cents = $2, cancelled_at = now()
+WHERE g.id = $1
+RETURNING `+_registrationColumns, reg.ID, refund))
+	if err != nil {
+		return entity.ExamRegistration{}, fmt.Errorf("exam - Cancel - update: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return entity.ExamRegistration{}, fmt.Errorf("exam - Cancel - commit: %w", err)
+	}
+
+	return reg, nil
+}
+
+func (r repoExam) ListCategories(ctx context.Context) ([]entity.ExamCategorySettings, error) {
+	querySQL, args, err := r.Builder.
+		Select("code", "name", "multi_select_scoring").
+		From("exam_type_lookup").
+		OrderBy("id ASC").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("exam - ListCategories - build: %w", err)
+	}
+
+	rows, err := r.Pool.Query(ctx, querySQL, args...)
+	if err != nil {
+		return nil, fmt.Errorf("exam - ListCategories - query: %w", err)
+	}
+	defer rows.Close()
+
+	var categories []entity.ExamCategorySettings
+	for rows.Next() {
+		var c entity.ExamCategorySettings
+		if err := rows.Scan(&c.Exam, &c.Name, &c.MultiSelectScoring); err != nil {
+			return nil, fmt.Errorf("exam - ListCategories - scan: %w", err)
+		}
+		categories = append(categories, c)
+	}
+
+	return categories, nil
+}
+
+func (r repoExam) GetCategory(ctx context.Context, exam entity.ExamCategory) (entity.ExamCategorySettings, error) {
+	querySQL, args, err := r.Builder.
+		Select("code", "name", "multi_select_scoring").
+		From("exam_type_lookup").
+		Where("code::text = ?", string(exam)).
+		ToSql()
+	if err != nil {
+		return entity.ExamCategorySettings{}, fmt.Errorf("exam - GetCategory - build: %w", err)
+	}
+
+	var c entity.ExamCategorySettings
+	err = r.Pool.QueryRow(ctx, querySQL, args...).Scan(&c.Exam, &c.Name, &c.MultiSelectScoring)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.ExamCategorySettings{}, repo.ErrNotFound
+	}
+	if err != nil {
+		return entity.ExamCategorySettings{}, fmt.Errorf("exam - GetCategory - scan: %w", err)
+	}
+
+	return c, nil
+}
+
+func (r repoExam) UpdateCategory(ctx context.Context, settings entity.ExamCategorySettings) (entity.ExamCategorySettings, error) {
+	querySQL, args, err := r.Builder.
+		Update("exam_type_lookup").
+		Set("multi_select_scoring", string(settings.MultiSelectScoring)).
+		Where("code::text = ?", string(settings.Exam)).
+		Suffix("RETURNING code, name, multi_select_scoring").
+		ToSql()
+	if err != nil {
+		return entity.ExamCategorySettings{}, fmt.Errorf("exam - UpdateCategory - build: %w", err)
+	}
+
+	var c entity.ExamCategorySettings
+	err = r.Pool.QueryRow(ctx, querySQL, args...).Scan(&c.Exam, &c.Name, &c.MultiSelectScoring)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.ExamCategorySettings{}, repo.ErrNotFound
+	}
+	if err != nil {
+		return entity.ExamCategorySettings{}, fmt.Errorf("exam - UpdateCategory - scan: %w", err)
+	}
+
+	return c, nil
+}
+
+// repoExamAttempt implements ExamAttemptRepository.
+type repoExamAttempt struct{ *postgres.Postgres }
+
+const _attemptColumns = `a.id, a.exam_config_id, a.user_id, a.status, a.started_at, a.deadline_at, a.submitted_at,
+a.score, a.correct, a.incorrect, a.skipped`
+
+func scanAttempt(row rowScanner) (entity.ExamAttempt, error) {
+	var a entity.ExamAttempt
+	err := row.Scan(&a.ID, &a.ExamID, &a.UserID, &a.Status, &a.StartedAt, &a.DeadlineAt, &a.SubmittedAt,
+		&a.Score, &a.Correct, &a.Incorrect, &a.Skipped)
+
+	return a, err
+}
+
+func (r repoExamAttempt) ListPaper(ctx context.Context, examID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := r.Pool.Query(ctx,
+		"SELECT question_id FROM exam_paper WHERE exam_config_id = $1 ORDER BY sequence_index", examID)
+	if err != nil {
+		return nil, fmt.Errorf("exam attempt - ListPaper - query: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("exam attempt - ListPaper - scan: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("exam attempt - ListPaper - rows: %w", err)
+	}
+
+	return ids, nil
+}
+
+func (r repoExamAttempt) Start(ctx context.Context, attempt entity.ExamAttempt, paper []uuid.UUID) (entity.ExamAttempt, bool, error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return entity.ExamAttempt{}, false, fmt.Errorf("exam attempt - Start - begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := freezePaper(ctx, tx, attempt.ExamID, paper); err != nil {
+		return entity.ExamAttempt{}, false, fmt.Errorf("exam attempt - Start - %w", err)
+	}
+
+	started, err := scanAttempt(tx.QueryRow(ctx, `
+INSERT INTO exam_attempt AS a (id, exam_config_id, user_id, status, started_at, deadline_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (exam_config_id, user_id) DO NOTHING
+RETURNING `+_attemptColumns, attempt.ID, attempt.ExamID, attempt.UserID, string(entity.ExamAttemptInProgress),
+		attempt.StartedAt, attempt.DeadlineAt))
+	if errors.Is(err, pgx.ErrNoRows) {
+		existing, err := r.Get(ctx, attempt.ExamID, attempt.UserID)
+		if err != nil {
+			return entity.ExamAttempt{}, false, fmt.Errorf("exam attempt - Start - existing: %w", err)
+		}
+
+		return existing, false, nil
+	}
+	if err != nil {
+		return entity.ExamAttempt{}, false, fmt.Errorf("exam attempt - Start - insert: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+INSERT INTO exam_attempt_question (attempt_id, sequence_index, question_id, question_version)
+SELECT $1, p.sequence_index, p.question_id, p.question_version
+FROM exam_paper p
+WHERE p.exam_config_id = $2`, started.ID, started.ExamID)
+	if err != nil {
+		return entity.ExamAttempt{}, false, fmt.Errorf("exam attempt - Start - questions: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return entity.ExamAttempt{}, false, fmt.Errorf("exam attempt - Start - commit: %w", err)
+	}
+
+	return started, true, nil
+}
+
+// freezePaper stores paper as the exam's paper unless it has one. The exam
+// row lock makes concurrent first attempts agree on a single paper.
+func freezePaper(ctx context.Context, tx pgx.Tx, examID uuid.UUID, paper []uuid.UUID) error {
+	const frozenSQL = "SELECT EXISTS (SELECT 1 FROM exam_paper WHERE exam_config_id = $1)"
+
+	var frozen bool
+	if err := tx.QueryRow(ctx, frozenSQL, examID).Scan(&frozen); err != nil {
+		return fmt.Errorf("paper: %w", err)
+	}
+	if frozen {
+		return nil
+	}
+
+	if _, err := tx.Exec(ctx, "SELECT 1 FROM exam_config WHERE id = $1 FOR UPDATE", examID); err != nil {
+		return fmt.Errorf("lock exam: %w", err)
+	}
+
+	if err := tx.QueryRow(ctx, frozenSQL, examID).Scan(&frozen); err != nil {
+		return fmt.Errorf("paper: %w", err)
+	}
+	if frozen {
+		return nil
+	}
+
+	_, err := tx.Exec(ctx, `
+INSERT INTO exam_paper (exam_config_id, sequence_index, question_id, question_version)
+SELECT $1, p.n, q.id, q.version
+FROM unnest($2::uuid[]) WITH ORDINALITY AS p(id, n)
+JOIN question q ON q.id = p.id`, examID, paper)
+	if err != nil {
+		return fmt.Errorf("freeze paper: %w", err)
+	}
+
+	return nil
 }
 
-func (r repoPracticeSession) GetSessionQuestion(ctx context.Context, id uuid.UUID) (entity.PracticeSessionQuestion, error) {
-	builder := r.Builder.
-		Select(
-			"psq.id",
-			"psq.sequence_index",
-			"psq.selected_option",
-			"psq.is_correct",
-			"psq.time_taken_ms",
-			"psq.answered_at",
-			"q.id",
-			"e.code",
-			"q.subject_id",
-			"q.topic_id",
-			"q.question_text",
-			"q.option_a",
-			"q.option_b",
-			"q.option_c",
-			"q.option_d",
-			"q.correct_option",
-			"q.explanation",
-			"q.difficulty_level",
-			"q.choice_type",
-			"q.is_clinical",
-			"q.is_image_based",
-			"q.is_high_yield",
-			"q.is_active",
-		).
-		From("practice_session_question psq").
-		Join("question q ON q.id = psq.question_id").
-		Join("exam_type_lookup e ON e.id = q.exam_type_id").
-		Where("psq.id = ?", id).
-		Limit(1)
-
-	querySQL, args, err := builder.ToSql()
+func (r repoExamAttempt) Get(ctx context.Context, examID, userID uuid.UUID) (entity.ExamAttempt, error) {
+	attempt, err := scanAttempt(r.Pool.QueryRow(ctx,
+		"SELECT "+_attemptColumns+" FROM exam_attempt a WHERE a.exam_config_id = $1 AND a.user_id = $2", examID, userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.ExamAttempt{}, repo.ErrNotFound
+	}
 	if err != nil {
-		return entity.PracticeSessionQuestion{}, fmt.Errorf("practice - GetSessionQuestion - build: %w", err)
+		return entity.ExamAttempt{}, fmt.Errorf("exam attempt - Get - scan: %w", err)
 	}
 
-	row := r.Pool.QueryRow(ctx, querySQL, args...)
-	var psq entity.PracticeSessionQuestion
-	var q entity.Question
-	var examCode string
+	return attempt, nil
+}
+
+func (r repoExamAttempt) selectQuestions() squirrel.SelectBuilder {
+	return r.Builder.
+		Select(append(append([]string{"q.id", "e.code"}, questionColumns("qv")...),
+			// Served questions carry no workflow state.
+			"'' AS status", "NULL::uuid AS reviewer_id", "false AS difficulty_locked", "'{}'::text[] AS tags",
+			"aq.id", "aq.sequence_index", "aq.selected_options", "aq.numeric_answer", "aq.matrix_answer",
+			"aq.answered_at", "aq.is_correct", "aq.marks")...).
+		From("exam_attempt_question aq").
+		Join("question q ON q.id = aq.question_id").
+		Join("question_version qv ON qv.question_id = aq.question_id AND qv.version = aq.question_version").
+		Join("exam_type_lookup e ON e.id = q.exam_type_id")
+}
+
+func scanAttemptQuestion(row rowScanner) (entity.ExamAttemptQuestion, error) {
+	var aq entity.ExamAttemptQuestion
 	var choiceType string
-	var explanation sql.NullString
-	var selectedOption sql.NullInt32
-	var timeTaken sql.NullInt32
-	var answeredAt sql.NullTime
-	if err := row.Scan(
-		&psq.ID,
-		&psq.SequenceIndex,
-		&selectedOption,
-		&psq.IsCorrect,
-		&timeTaken,
-		&answeredAt,
-		&q.ID,
-		&examCode,
-		&q.SubjectID,
-		&q.TopicID,
-		&q.QuestionText,
-		&q.OptionA,
-		&q.OptionB,
-		&q.OptionC,
-		&q.OptionD,
-		&q.CorrectOption,
-		&explanation,
-		&q.DifficultyLevel,
-		&choiceType,
-		&q.IsClinical,
-		&q.IsImageBased,
-		&q.IsHighYield,
-		&q.IsActive,
-	); err != nil {
-		return entity.PracticeSessionQuestion{}, fmt.Errorf("practice - GetSessionQuestion - scan: %w", err)
+	dest := append(questionDest(&aq.Question, &choiceType),
+		&aq.ID,
+		&aq.SequenceIndex,
+		&aq.SelectedOptions,
+		&aq.NumericAnswer,
+		&aq.MatrixAnswer,
+		&aq.AnsweredAt,
+		&aq.IsCorrect,
+		&aq.Marks,
+	)
+	if err := row.Scan(dest...); err != nil {
+		return entity.ExamAttemptQuestion{}, err
 	}
+	aq.Question.ChoiceType = entity.QuestionChoiceType(choiceType)
 
-	if selectedOption.Valid {
-		val := int(selectedOption.Int32)
-		psq.SelectedOption = &val
+	return aq, nil
+}
+
+func (r repoExamAttempt) ListQuestions(ctx context.Context, attemptID uuid.UUID) ([]entity.ExamAttemptQuestion, error) {
+	querySQL, args, err := r.selectQuestions().
+		Where("aq.attempt_id = ?", attemptID).
+		OrderBy("aq.sequence_index ASC").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("exam attempt - ListQuestions - build: %w", err)
 	}
-	if timeTaken.Valid {
-		val := int(timeTaken.Int32)
-		psq.TimeTakenMs = &val
+
+	rows, err := r.Pool.Query(ctx, querySQL, args...)
+	if err != nil {
+		return nil, fmt.Errorf("exam attempt - ListQuestions - query: %w", err)
 	}
-	if answeredAt.Valid {
-		psq.AnsweredAt = &answeredAt.Time
+	defer rows.Close()
+
+	var questions []entity.ExamAttemptQuestion
+	for rows.Next() {
+		aq, err := scanAttemptQuestion(rows)
+		if err != nil {
+			return nil, fmt.Errorf("exam attempt - ListQuestions - scan: %w", err)
+		}
+		questions = append(questions, aq)
 	}
-	if explanation.Valid {
-		q.Explanation = &explanation.String
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("exam attempt - ListQuestions - rows: %w", err)
 	}
-	q.Exam = entity.ExamCategory(examCode)
-	q.ChoiceType = entity.QuestionChoiceType(choiceType)
-	psq.Question = q
 
-	return psq, nil
+	return questions, nil
 }
 
-func (r repoPracticeSession) UpdateSessionQuestion(ctx context.Context, question entity.PracticeSessionQuestion) (entity.PracticeSessionQuestion, error) {
-	builder := r.Builder.
-		Update("practice_session_question").
-		Set("selected_option", question.SelectedOption).
-		Set("is_correct", question.IsCorrect).
-		Set("time_taken_ms", question.TimeTakenMs).
-		Set("answered_at", question.AnsweredAt).
-		Where("id = ?", question.ID).
-		Suffix("RETURNING selected_option, is_correct, time_taken_ms, answered_at")
+func (r repoExamAttempt) GetQuestion(ctx context.Context, attemptID, id uuid.UUID) (entity.ExamAttemptQuestion, error) {
+	querySQL, args, err := r.selectQuestions().
+		Where("aq.id = ? AND aq.attempt_id = ?", id, attemptID).
+		ToSql()
+	if err != nil {
+		return entity.ExamAttemptQuestion{}, fmt.Errorf("exam attempt - GetQuestion - build: %w", err)
+	}
 
-	querySQL, args, err := builder.ToSql()
+	aq, err := scanAttemptQuestion(r.Pool.QueryRow(ctx, querySQL, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.ExamAttemptQuestion{}, repo.ErrNotFound
+	}
 	if err != nil {
-		return entity.PracticeSessionQuestion{}, fmt.Errorf("practice - UpdateSessionQuestion - build: %w", err)
+		return entity.ExamAttemptQuestion{}, fmt.Errorf("exam attempt - GetQuestion - scan: %w", err)
 	}
 
-	var selectedOption sql.NullInt32
-	var timeTaken sql.NullInt32
-	var answeredAt sql.NullTime
-	row := r.Pool.QueryRow(ctx, querySQL, args...)
-	if err := row.Scan(&selectedOption, &question.IsCorrect, &timeTaken, &answeredAt); err != nil {
-		return entity.PracticeSessionQuestion{}, fmt.Errorf("practice - UpdateSessionQuestion - scan: %w", err)
+	return aq, nil
+}
+
+func (r repoExamAttempt) SaveAnswer(ctx context.Context, attemptID uuid.UUID, question entity.ExamAttemptQuestion, now time.Time) (entity.ExamAttemptQuestion, error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return entity.ExamAttemptQuestion{}, fmt.Errorf("exam attempt - SaveAnswer - begin: %w", err)
 	}
+	defer func() { _ = tx.Rollback(ctx) }()
 
-	if selectedOption.Valid {
-		val := int(selectedOption.Int32)
-		question.SelectedOption = &val
+	// The share lock holds off Close until the answer is stored, so an
+	// attempt is graded on the answers saved before it closed.
+	var open bool
+	err = tx.QueryRow(ctx, `
+SELECT true FROM exam_attempt
+WHERE id = $1 AND status = $2 AND deadline_at > $3
+FOR SHARE`, attemptID, string(entity.ExamAttemptInProgress), now).Scan(&open)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.ExamAttemptQuestion{}, repo.ErrConflict
 	}
-	if timeTaken.Valid {
-		val := int(timeTaken.Int32)
-		question.TimeTakenMs = &val
+	if err != nil {
+		return entity.ExamAttemptQuestion{}, fmt.Errorf("exam attempt - SaveAnswer - lock: %w", err)
 	}
-	if answeredAt.Valid {
-		question.AnsweredAt = &answeredAt.Time
+
+	tag, err := tx.Exec(ctx, `
+UPDATE exam_attempt_question
+SET selected_options = $3, numeric_answer = $4, matrix_answer = $5, answered_at = $6
+WHERE id = $1 AND attempt_id = $2`, question.ID, attemptID, question.SelectedOptions, question.NumericAnswer,
+		matrixAnswer(question.MatrixAnswer), question.AnsweredAt)
+	if err != nil {
+		return entity.ExamAttemptQuestion{}, fmt.Errorf("exam attempt - SaveAnswer - update: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return entity.ExamAttemptQuestion{}, repo.ErrNotFound
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return entity.ExamAttemptQuestion{}, fmt.Errorf("exam attempt - SaveAnswer - commit: %w", err)
 	}
 
 	return question, nil
 }
 
-// repoRevision implements RevisionRepository.
-type repoRevision struct{ *postgres.Postgres }
+func (r repoExamAttempt) Close(ctx context.Context, id uuid.UUID, status entity.ExamAttemptStatus, at time.Time) (entity.ExamAttempt, error) {
+	attempt, err := scanAttempt(r.Pool.QueryRow(ctx, `
+UPDATE exam_attempt a
+SET status = $2, submitted_at = $3
+WHERE a.id = $1 AND a.status = $4
+RETURNING `+_attemptColumns, id, string(status), at, string(entity.ExamAttemptInProgress)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.ExamAttempt{}, repo.ErrNotFound
+	}
+	if err != nil {
+		return entity.ExamAttempt{}, fmt.Errorf("exam attempt - Close - scan: %w", err)
+	}
 
-func (r repoRevision) ListDue(ctx context.Context, userID uuid.UUID) ([]entity.RevisionItem, error) {
-	return []entity.RevisionItem{}, nil
+	return attempt, nil
 }
 
-// repoExam implements ExamRepository.
-type repoExam struct{ *postgres.Postgres }
+func (r repoExamAttempt) Grade(ctx context.Context, attempt entity.ExamAttempt, questions []entity.ExamAttemptQuestion) (entity.ExamAttempt, error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return entity.ExamAttempt{}, fmt.Errorf("exam attempt - Grade - begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
 
-func (r repoExam) ListConfigs(ctx context.Context) ([]entity.ExamConfig, error) {
-	return []entity.ExamConfig{}, nil
-}
+	graded, err := scanAttempt(tx.QueryRow(ctx, `
+UPDATE exam_attempt a
+SET score = $2, correct = $3, incorrect = $4, skipped = $5
+WHERE a.id = $1 AND a.status <> $6 AND a.score IS NULL
+RETURNING `+_attemptColumns, attempt.ID, attempt.Score, attempt.Correct, attempt.Incorrect, attempt.Skipped,
+		string(entity.ExamAttemptInProgress)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.ExamAttempt{}, repo.ErrNotFound
+	}
+	if err != nil {
+		return entity.ExamAttempt{}, fmt.Errorf("exam attempt - Grade - attempt: %w", err)
+	}
 
-func (r repoExam) CreateConfig(ctx context.Context, config entity.ExamConfig) (entity.ExamConfig, error) {
-	if config.ID == uuid.Nil {
-		config.ID = uuid.New()
+	ids := make([]uuid.UUID, len(questions))
+	correct := make([]*bool, len(questions))
+	marks := make([]*float64, len(questions))
+	for i, question := range questions {
+		ids[i], correct[i], marks[i] = question.ID, question.IsCorrect, question.Marks
 	}
-	return config, nil
-}
 
-func (r repoExam) GetConfig(ctx context.Context, id uuid.UUID) (entity.ExamConfig, error) {
-	return entity.ExamConfig{ID: id}, nil
-}
+	_, err = tx.Exec(ctx, `
+UPDATE exam_attempt_question aq
+SET is_correct = g.is_correct, marks = g.marks
+FROM unnest($2::uuid[], $3::bool[], $4::float8[]) AS g(id, is_correct, marks)
+WHERE aq.id = g.id AND aq.attempt_id = $1`, attempt.ID, ids, correct, marks)
+	if err != nil {
+		return entity.ExamAttempt{}, fmt.Errorf("exam attempt - Grade - questions: %w", err)
+	}
 
-func (r repoExam) UpdateConfig(ctx context.Context, config entity.ExamConfig) (entity.ExamConfig, error) {
-	return config, nil
-}
+	// Answers count as attempts with the share of full marks they earned.
+	_, err = tx.Exec(ctx, `
+INSERT INTO user_question_attempt
+  (user_id, exam_type_id, question_id, session_id, is_correct, selected_option, selected_options,
+   numeric_answer, matrix_answer, score, source, created_at)
+SELECT a.user_id, q.exam_type_id, q.id, a.id, aq.is_correct, aq.selected_options[1], aq.selected_options,
+  aq.numeric_answer, aq.matrix_answer,
+  CASE WHEN aq.is_correct THEN 1 ELSE GREATEST(aq.marks, 0) / NULLIF(c.marks_per_correct::float8, 0) END,
+  $2, aq.answered_at
+FROM exam_attempt_question aq
+JOIN exam_attempt a ON a.id = aq.attempt_id
+JOIN exam_config c ON c.id = a.exam_config_id
+JOIN question q ON q.id = aq.question_id
+WHERE aq.attempt_id = $1 AND aq.answered_at IS NOT NULL AND aq.is_correct IS NOT NULL`,
+		attempt.ID, string(entity.AttemptSourceExam))
+	if err != nil {
+		return entity.ExamAttempt{}, fmt.Errorf("exam attempt - Grade - attempts: %w", err)
+	}
 
-func (r repoExam) DeleteConfig(ctx context.Context, id uuid.UUID) error {
-	return nil
+	if err := tx.Commit(ctx); err != nil {
+		return entity.ExamAttempt{}, fmt.Errorf("exam attempt - Grade - commit: %w", err)
+	}
+
+	return graded, nil
 }
 
-func (r repoExam) ListSummaries(ctx context.Context) ([]entity.ExamSummary, error) {
-	return []entity.ExamSummary{}, nil
+func (r repoExamAttempt) ListUngraded(ctx context.Context, now time.Time, limit int) ([]entity.ExamAttempt, error) {
+	rows, err := r.Pool.Query(ctx, `
+SELECT `+_attemptColumns+`
+FROM exam_attempt a
+WHERE a.score IS NULL AND (a.status <> $1 OR a.deadline_at <= $2)
+ORDER BY a.deadline_at
+LIMIT $3`, string(entity.ExamAttemptInProgress), now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("exam attempt - ListUngraded - query: %w", err)
+	}
+	defer rows.Close()
+
+	var attempts []entity.ExamAttempt
+	for rows.Next() {
+		attempt, err := scanAttempt(rows)
+		if err != nil {
+			return nil, fmt.Errorf("exam attempt - ListUngraded - scan: %w", err)
+		}
+		attempts = append(attempts, attempt)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("exam attempt - ListUngraded - rows: %w", err)
+	}
+
+	return attempts, nil
 }
 
 // repoPodcast implements PodcastRepository.
@@ -802,42 +4611,249 @@ func (r repoPodcast) Delete(ctx context.Context, id uuid.UUID) error {
 // repoWallet implements WalletRepository.
 type repoWallet struct{ *postgres.Postgres }
 
+// GetSummary derives the balance from the ledger. Refunds count against
+// spending rather than as earnings.
 func (r repoWallet) GetSummary(ctx context.Context, userID uuid.UUID) (entity.WalletSummary, error) {
-	return entity.WalletSummary{}, nil
+	var summary entity.WalletSummary
+	err := r.Pool.QueryRow(ctx, `
+SELECT COALESCE(SUM(amount_cents), 0),
+  COALESCE(SUM(amount_cents) FILTER (WHERE amount_cents > 0 AND tx_type <> 'EXAM_REFUND'), 0),
+  COALESCE(-SUM(amount_cents) FILTER (WHERE amount_cents < 0 OR tx_type = 'EXAM_REFUND'), 0)
+FROM wallet_transaction WHERE user_id = $1`, userID).Scan(&summary.Balance, &summary.LifetimeEarned, &summary.LifetimeSpent)
+	if err != nil {
+		return entity.WalletSummary{}, fmt.Errorf("wallet - GetSummary - scan: %w", err)
+	}
+
+	return summary, nil
 }
 
 func (r repoWallet) ListTransactions(ctx context.Context, userID uuid.UUID) ([]entity.WalletTransaction, error) {
-	return []entity.WalletTransaction{}, nil
+	rows, err := r.Pool.Query(ctx, `
+SELECT id, amount_cents, tx_type, COALESCE(description, ''), created_at
+FROM wallet_transaction WHERE user_id = $1
+ORDER BY created_at DESC, id`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("wallet - ListTransactions - query: %w", err)
+	}
+	defer rows.Close()
+
+	txs := []entity.WalletTransaction{}
+	for rows.Next() {
+		var t entity.WalletTransaction
+		if err := rows.Scan(&t.ID, &t.Amount, &t.Type, &t.Description, &t.CreatedAt); err != nil {
+			return nil, fmt.Errorf("wallet - ListTransactions - scan: %w", err)
+		}
+		txs = append(txs, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("wallet - ListTransactions - rows: %w", err)
+	}
+
+	return txs, nil
+}
+
+// debitWallet locks the user's wallet for the rest of tx and checks that the
+// balance covers amount. The lock keeps concurrent debits from overdrawing it.
+func debitWallet(ctx context.Context, tx pgx.Tx, userID uuid.UUID, amount int) error {
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock(hashtextextended($1::text, 0))", userID); err != nil {
+		return fmt.Errorf("wallet - debit - lock: %w", err)
+	}
+
+	var balance int
+	if err := tx.QueryRow(ctx, "SELECT COALESCE(SUM(amount_cents), 0) FROM wallet_transaction WHERE user_id = $1", userID).
+		Scan(&balance); err != nil {
+		return fmt.Errorf("wallet - debit - balance: %w", err)
+	}
+	if balance < amount {
+		return repo.ErrInsufficientFunds
+	}
+
+	return nil
+}
+
+// insertWalletTx writes a ledger entry, tied to an exam when examID is set.
+func insertWalletTx(ctx context.Context, tx pgx.Tx, userID uuid.UUID, examID *uuid.UUID, amount int, kind entity.WalletTxType, description string) error {
+	_, err := tx.Exec(ctx, `
+INSERT INTO wallet_transaction (id, user_id, exam_type_id, exam_config_id, amount_cents, tx_type, description)
+VALUES ($1, $2, (SELECT exam_type_id FROM exam_config WHERE id = $3), $3, $4, $5, $6)
+`, uuid.New(), userID, examID, amount, string(kind), description)
+	if err != nil {
+		return fmt.Errorf("wallet - insert %s: %w", kind, err)
+	}
+
+	return nil
 }
 
 // repoCoupon implements CouponRepository.
 type repoCoupon struct{ *postgres.Postgres }
 
+const _couponColumns = `c.id, c.code, COALESCE(c.description, ''), c.type, c.amount_cents, c.max_uses_total,
+c.max_uses_per_user, c.expires_at, c.is_active`
+
+func scanCoupon(row rowScanner) (entity.Coupon, error) {
+	var c entity.Coupon
+	err := row.Scan(&c.ID, &c.Code, &c.Description, &c.Type, &c.Amount, &c.MaxUsesTotal,
+		&c.MaxUsesPerUser, &c.ExpiresAt, &c.IsActive)
+
+	return c, err
+}
+
 func (r repoCoupon) List(ctx context.Context) ([]entity.Coupon, error) {
-	return []entity.Coupon{}, nil
+	rows, err := r.Pool.Query(ctx, "SELECT "+_couponColumns+" FROM coupon c ORDER BY c.created_at DESC")
+	if err != nil {
+		return nil, fmt.Errorf("coupon - List - query: %w", err)
+	}
+	defer rows.Close()
+
+	coupons := []entity.Coupon{}
+	for rows.Next() {
+		c, err := scanCoupon(rows)
+		if err != nil {
+			return nil, fmt.Errorf("coupon - List - scan: %w", err)
+		}
+		coupons = append(coupons, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("coupon - List - rows: %w", err)
+	}
+
+	return coupons, nil
 }
 
+// Create returns ErrConflict when the code is taken in any case.
 func (r repoCoupon) Create(ctx context.Context, coupon entity.Coupon) (entity.Coupon, error) {
 	if coupon.ID == uuid.Nil {
 		coupon.ID = uuid.New()
 	}
-	return coupon, nil
+
+	created, err := scanCoupon(r.Pool.QueryRow(ctx, `
+INSERT INTO coupon AS c (id, code, description, type, amount_cents, max_uses_total, max_uses_per_user, expires_at, is_active)
+VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, $7, $8, $9)
+ON CONFLICT DO NOTHING
+RETURNING `+_couponColumns, coupon.ID, coupon.Code, coupon.Description, coupon.Type, coupon.Amount,
+		coupon.MaxUsesTotal, coupon.MaxUsesPerUser, coupon.ExpiresAt, coupon.IsActive))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Coupon{}, repo.ErrConflict
+	}
+	if err != nil {
+		return entity.Coupon{}, fmt.Errorf("coupon - Create - scan: %w", err)
+	}
+
+	return created, nil
 }
 
 func (r repoCoupon) Get(ctx context.Context, id uuid.UUID) (entity.Coupon, error) {
-	return entity.Coupon{ID: id}, nil
+	c, err := scanCoupon(r.Pool.QueryRow(ctx, "SELECT "+_couponColumns+" FROM coupon c WHERE c.id = $1", id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Coupon{}, repo.ErrNotFound
+	}
+	if err != nil {
+		return entity.Coupon{}, fmt.Errorf("coupon - Get - scan: %w", err)
+	}
+
+	return c, nil
 }
 
+// Update returns ErrConflict when the new code is taken by another coupon.
 func (r repoCoupon) Update(ctx context.Context, coupon entity.Coupon) (entity.Coupon, error) {
-	return coupon, nil
+	updated, err := scanCoupon(r.Pool.QueryRow(ctx, `
+UPDATE coupon c
+SET code = $2, description = NULLIF($3, ''), type = $4, amount_cents = $5, max_uses_total = $6,
+  max_uses_per_user = $7, expires_at = $8, is_active = $9
+WHERE c.id = $1 AND NOT EXISTS (SELECT 1 FROM coupon o WHERE upper(o.code) = upper($2) AND o.id <> $1)
+RETURNING `+_couponColumns, coupon.ID, coupon.Code, coupon.Description, coupon.Type, coupon.Amount,
+		coupon.MaxUsesTotal, coupon.MaxUsesPerUser, coupon.ExpiresAt, coupon.IsActive))
+	if errors.Is(err, pgx.ErrNoRows) {
+		var exists bool
+		if err := r.Pool.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM coupon WHERE id = $1)", coupon.ID).Scan(&exists); err != nil {
+			return entity.Coupon{}, fmt.Errorf("coupon - Update - exists: %w", err)
+		}
+		if exists {
+			return entity.Coupon{}, repo.ErrConflict
+		}
+
+		return entity.Coupon{}, repo.ErrNotFound
+	}
+	if err != nil {
+		return entity.Coupon{}, fmt.Errorf("coupon - Update - scan: %w", err)
+	}
+
+	return updated, nil
 }
 
 func (r repoCoupon) Delete(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.Pool.Exec(ctx, "DELETE FROM coupon WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("coupon - Delete - exec: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repo.ErrNotFound
+	}
+
 	return nil
 }
 
 func (r repoCoupon) Redeem(ctx context.Context, code string, userID uuid.UUID) (entity.WalletSummary, error) {
-	return entity.WalletSummary{}, nil
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return entity.WalletSummary{}, fmt.Errorf("coupon - Redeem - begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	coupon, err := claimCoupon(ctx, tx, code, userID, entity.CouponTypeFixed, entity.CouponTypeBonus)
+	if err != nil {
+		return entity.WalletSummary{}, err
+	}
+
+	if _, err := tx.Exec(ctx, `
+INSERT INTO coupon_redemption (id, coupon_id, user_id, amount_cents)
+VALUES ($1, $2, $3, $4)`, uuid.New(), coupon.ID, userID, coupon.Amount); err != nil {
+		return entity.WalletSummary{}, fmt.Errorf("coupon - Redeem - redemption: %w", err)
+	}
+
+	kind := entity.WalletTxCoupon
+	if coupon.Type == entity.CouponTypeBonus {
+		kind = entity.WalletTxBonus
+	}
+	if err := insertWalletTx(ctx, tx, userID, nil, coupon.Amount, kind, "Coupon "+coupon.Code); err != nil {
+		return entity.WalletSummary{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return entity.WalletSummary{}, fmt.Errorf("coupon - Redeem - commit: %w", err)
+	}
+
+	return repoWallet(r).GetSummary(ctx, userID)
+}
+
+// claimCoupon locks the coupon with code, in any case, for the rest of tx and
+// checks that userID can use it now as one of types.
+func claimCoupon(ctx context.Context, tx pgx.Tx, code string, userID uuid.UUID, types ...string) (entity.Coupon, error) {
+	coupon, err := scanCoupon(tx.QueryRow(ctx, "SELECT "+_couponColumns+" FROM coupon c WHERE upper(c.code) = upper($1) FOR UPDATE", code))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Coupon{}, repo.ErrNotFound
+	}
+	if err != nil {
+		return entity.Coupon{}, fmt.Errorf("coupon - claim - scan: %w", err)
+	}
+
+	if !coupon.IsActive || !slices.Contains(types, coupon.Type) ||
+		(coupon.ExpiresAt != nil && !coupon.ExpiresAt.After(time.Now())) {
+		return entity.Coupon{}, repo.ErrCouponUnavailable
+	}
+
+	var total, mine int
+	if err := tx.QueryRow(ctx, `
+SELECT count(*), count(*) FILTER (WHERE user_id = $2)
+FROM coupon_redemption WHERE coupon_id = $1`, coupon.ID, userID).Scan(&total, &mine); err != nil {
+		return entity.Coupon{}, fmt.Errorf("coupon - claim - uses: %w", err)
+	}
+	if (coupon.MaxUsesTotal > 0 && total >= coupon.MaxUsesTotal) ||
+		(coupon.MaxUsesPerUser > 0 && mine >= coupon.MaxUsesPerUser) {
+		return entity.Coupon{}, repo.ErrCouponUnavailable
+	}
+
+	return coupon, nil
 }
 
 // repoReferral implements ReferralRepository.
@@ -851,11 +4867,78 @@ func (r repoReferral) GetSummary(ctx context.Context, userID uuid.UUID) (entity.
 type repoAISettings struct{ *postgres.Postgres }
 
 func (r repoAISettings) Get(ctx context.Context) (entity.AISettings, error) {
-	return entity.AISettings{RevisionEnabled: true}, nil
+	row := r.Pool.QueryRow(ctx, `
+SELECT weakness_min_attempts, weakness_threshold_percent, strong_threshold_percent,
+  revision_intervals_days, include_guessed_correct, revision_enabled, revision_algorithm, revision_daily_cap,
+  revision_load_smoothing, sm2_params, fsrs_params
+FROM ai_settings
+WHERE id
+`)
+
+	settings, err := scanAISettings(row)
+	if err != nil {
+		return entity.AISettings{}, fmt.Errorf("ai - Get - scan: %w", err)
+	}
+
+	return settings, nil
 }
 
 func (r repoAISettings) Update(ctx context.Context, settings entity.AISettings) (entity.AISettings, error) {
-	return settings, nil
+	algorithm := settings.RevisionAlgorithm
+	if algorithm == "" {
+		algorithm = entity.RevisionAlgorithmFixed
+	}
+
+	row := r.Pool.QueryRow(ctx, `
+INSERT INTO ai_settings (id, weakness_min_attempts, weakness_threshold_percent, strong_threshold_percent,
+  revision_intervals_days, include_guessed_correct, revision_enabled, revision_algorithm, revision_daily_cap,
+  revision_load_smoothing, sm2_params, fsrs_params, updated_at)
+VALUES (TRUE, $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, now())
+ON CONFLICT (id) DO UPDATE SET
+  weakness_min_attempts = EXCLUDED.weakness_min_attempts,
+  weakness_threshold_percent = EXCLUDED.weakness_threshold_percent,
+  strong_threshold_percent = EXCLUDED.strong_threshold_percent,
+  revision_intervals_days = EXCLUDED.revision_intervals_days,
+  include_guessed_correct = EXCLUDED.include_guessed_correct,
+  revision_enabled = EXCLUDED.revision_enabled,
+  revision_algorithm = EXCLUDED.revision_algorithm,
+  revision_daily_cap = EXCLUDED.revision_daily_cap,
+  revision_load_smoothing = EXCLUDED.revision_load_smoothing,
+  sm2_params = EXCLUDED.sm2_params,
+  fsrs_params = EXCLUDED.fsrs_params,
+  updated_at = EXCLUDED.updated_at
+RETURNING weakness_min_attempts, weakness_threshold_percent, strong_threshold_percent,
+  revision_intervals_days, include_guessed_correct, revision_enabled, revision_algorithm, revision_daily_cap,
+  revision_load_smoothing, sm2_params, fsrs_params
+`, settings.WeaknessMinAttempts, settings.WeaknessThresholdPercent, settings.StrongThresholdPercent,
+		settings.RevisionIntervalsDays, settings.IncludeGuessedCorrect, settings.RevisionEnabled,
+		string(algorithm), settings.RevisionDailyCap, settings.RevisionLoadSmoothing, settings.SM2, settings.FSRS)
+
+	updated, err := scanAISettings(row)
+	if err != nil {
+		return entity.AISettings{}, fmt.Errorf("ai - Update - scan: %w", err)
+	}
+
+	return updated, nil
+}
+
+func scanAISettings(row rowScanner) (entity.AISettings, error) {
+	var s entity.AISettings
+	err := row.Scan(
+		&s.WeaknessMinAttempts,
+		&s.WeaknessThresholdPercent,
+		&s.StrongThresholdPercent,
+		&s.RevisionIntervalsDays,
+		&s.IncludeGuessedCorrect,
+		&s.RevisionEnabled,
+		&s.RevisionAlgorithm,
+		&s.RevisionDailyCap,
+		&s.RevisionLoadSmoothing,
+		&s.SM2,
+		&s.FSRS,
+	)
+
+	return s, err
 }
 
 // repoAnalytics implements AnalyticsRepository.
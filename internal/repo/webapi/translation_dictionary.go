@@ -0,0 +1,57 @@
+package webapi
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+)
+
+// DictionaryTranslationWebAPI translates offline from a fixed phrase
+// dictionary, for tests and local runs without network access.
+type DictionaryTranslationWebAPI struct {
+	// phrases maps a destination language to lowercase source phrases and
+	// their translations.
+	phrases map[string]map[string]string
+}
+
+// NewDictionary builds a translator from phrases per destination language.
+// Source phrases match in any case.
+func NewDictionary(phrases map[string]map[string]string) *DictionaryTranslationWebAPI {
+	lowered := make(map[string]map[string]string, len(phrases))
+	for language, entries := range phrases {
+		lowered[language] = make(map[string]string, len(entries))
+		for source, target := range entries {
+			lowered[language][strings.ToLower(source)] = target
+		}
+	}
+
+	return &DictionaryTranslationWebAPI{phrases: lowered}
+}
+
+// Translate looks the whole text up first, then translates word by word,
+// keeping punctuation and leaving unknown words as they are.
+func (t *DictionaryTranslationWebAPI) Translate(translation entity.Translation) (entity.Translation, error) {
+	entries, ok := t.phrases[translation.Destination]
+	if !ok {
+		return entity.Translation{}, fmt.Errorf("DictionaryTranslationWebAPI - Translate - no dictionary for %q", translation.Destination)
+	}
+
+	if whole, ok := entries[strings.ToLower(strings.TrimSpace(translation.Original))]; ok {
+		translation.Translation = whole
+		return translation, nil
+	}
+
+	words := strings.Fields(translation.Original)
+	for i, word := range words {
+		core := strings.TrimFunc(word, unicode.IsPunct)
+		if target, ok := entries[strings.ToLower(core)]; ok && core != "" {
+			words[i] = strings.Replace(word, core, target, 1)
+		}
+	}
+
+	translation.Translation = strings.Join(words, " ")
+
+	return translation, nil
+}
@@ -2,13 +2,26 @@ package coupon
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+
 	"github.com/google/uuid"
 
 	"github.com/evrone/go-clean-template/internal/entity"
 	"github.com/evrone/go-clean-template/internal/repo"
 )
 
+var (
+	// ErrNotFound when no coupon has the id or code.
+	ErrNotFound = errors.New("coupon not found")
+	// ErrCodeTaken when another coupon has the code, in any case.
+	ErrCodeTaken = errors.New("coupon code already exists")
+	// ErrUnavailable when the coupon is inactive, expired, used up, or an
+	// entry pass, which pays exam fees instead.
+	ErrUnavailable = errors.New("coupon cannot be redeemed")
+)
+
 // UseCase handles coupons.
 type UseCase struct {
 	repo repo.CouponRepository
@@ -21,8 +34,13 @@ func New(repo repo.CouponRepository) *UseCase {
 
 // Redeem applies a coupon code.
 func (uc *UseCase) Redeem(ctx context.Context, userID uuid.UUID, req entity.CouponRedeemRequest) (entity.WalletSummary, error) {
-	summary, err := uc.repo.Redeem(ctx, req.Code, userID)
-	if err != nil {
+	summary, err := uc.repo.Redeem(ctx, strings.TrimSpace(req.Code), userID)
+	switch {
+	case errors.Is(err, repo.ErrNotFound):
+		return entity.WalletSummary{}, ErrNotFound
+	case errors.Is(err, repo.ErrCouponUnavailable):
+		return entity.WalletSummary{}, ErrUnavailable
+	case err != nil:
 		return entity.WalletSummary{}, fmt.Errorf("coupon - Redeem: %w", err)
 	}
 
@@ -43,7 +61,7 @@ func (uc *UseCase) AdminList(ctx context.Context) ([]entity.Coupon, error) {
 func (uc *UseCase) AdminCreate(ctx context.Context, req entity.CouponCreateRequest) (entity.Coupon, error) {
 	coupon := entity.Coupon{
 		ID:             uuid.New(),
-		Code:           req.Code,
+		Code:           strings.TrimSpace(req.Code),
 		Description:    req.Description,
 		Type:           req.Type,
 		Amount:         req.Amount,
@@ -54,6 +72,9 @@ func (uc *UseCase) AdminCreate(ctx context.Context, req entity.CouponCreateReque
 	}
 
 	created, err := uc.repo.Create(ctx, coupon)
+	if errors.Is(err, repo.ErrConflict) {
+		return entity.Coupon{}, ErrCodeTaken
+	}
 	if err != nil {
 		return entity.Coupon{}, fmt.Errorf("coupon - Create: %w", err)
 	}
@@ -64,6 +85,9 @@ func (uc *UseCase) AdminCreate(ctx context.Context, req entity.CouponCreateReque
 // AdminGet returns coupon by id.
 func (uc *UseCase) AdminGet(ctx context.Context, id uuid.UUID) (entity.Coupon, error) {
 	coupon, err := uc.repo.Get(ctx, id)
+	if errors.Is(err, repo.ErrNotFound) {
+		return entity.Coupon{}, ErrNotFound
+	}
 	if err != nil {
 		return entity.Coupon{}, fmt.Errorf("coupon - Get: %w", err)
 	}
@@ -73,12 +97,12 @@ func (uc *UseCase) AdminGet(ctx context.Context, id uuid.UUID) (entity.Coupon, e
 
 // AdminUpdate modifies coupon details.
 func (uc *UseCase) AdminUpdate(ctx context.Context, id uuid.UUID, req entity.CouponCreateRequest) (entity.Coupon, error) {
-	coupon, err := uc.repo.Get(ctx, id)
+	coupon, err := uc.AdminGet(ctx, id)
 	if err != nil {
-		return entity.Coupon{}, fmt.Errorf("coupon - Get: %w", err)
+		return entity.Coupon{}, err
 	}
 
-	coupon.Code = req.Code
+	coupon.Code = strings.TrimSpace(req.Code)
 	coupon.Description = req.Description
 	coupon.Type = req.Type
 	coupon.Amount = req.Amount
@@ -88,7 +112,12 @@ func (uc *UseCase) AdminUpdate(ctx context.Context, id uuid.UUID, req entity.Cou
 	coupon.IsActive = req.IsActive
 
 	updated, err := uc.repo.Update(ctx, coupon)
-	if err != nil {
+	switch {
+	case errors.Is(err, repo.ErrNotFound):
+		return entity.Coupon{}, ErrNotFound
+	case errors.Is(err, repo.ErrConflict):
+		return entity.Coupon{}, ErrCodeTaken
+	case err != nil:
 		return entity.Coupon{}, fmt.Errorf("coupon - Update: %w", err)
 	}
 
@@ -97,7 +126,11 @@ func (uc *UseCase) AdminUpdate(ctx context.Context, id uuid.UUID, req entity.Cou
 
 // AdminDelete removes a coupon.
 func (uc *UseCase) AdminDelete(ctx context.Context, id uuid.UUID) error {
-	if err := uc.repo.Delete(ctx, id); err != nil {
+	err := uc.repo.Delete(ctx, id)
+	if errors.Is(err, repo.ErrNotFound) {
+		return ErrNotFound
+	}
+	if err != nil {
 		return fmt.Errorf("coupon - Delete: %w", err)
 	}
 
@@ -0,0 +1,480 @@
+package exam
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/repo"
+	"github.com/evrone/go-clean-template/internal/usecase/scoring"
+)
+
+// _gradeBatch is how many overdue attempts one GradeExpired run handles.
+const _gradeBatch = 100
+
+var (
+	// ErrNotOpen when an attempt is started outside the exam's window.
+	ErrNotOpen = errors.New("exam is not open for attempts")
+	// ErrNoQuestions when no published question can go into the paper.
+	ErrNoQuestions = errors.New("no questions available for the exam")
+	// ErrNoAttempt when the user has not started the exam.
+	ErrNoAttempt = errors.New("exam not attempted")
+	// ErrAttemptClosed when a submitted or timed-out attempt is modified.
+	ErrAttemptClosed = errors.New("attempt already submitted")
+	// ErrTimeUp when an answer arrives after the attempt's deadline.
+	ErrTimeUp = errors.New("time is up")
+	// ErrQuestionNotFound when the question is not part of the attempt.
+	ErrQuestionNotFound = errors.New("question is not part of the attempt")
+	// ErrInvalidAnswer when the answer does not fit the question type.
+	ErrInvalidAnswer = errors.New("answer does not match the question type")
+)
+
+// StartAttempt opens userID's attempt while the exam's window is open. The
+// first attempt freezes the paper every later attempt is served. Each
+// attempt has TimeLimitMinutes, cut short by the end of the window.
+// Starting again returns the existing attempt with created false.
+func (uc *UseCase) StartAttempt(ctx context.Context, userID, examID uuid.UUID) (detail entity.ExamAttemptDetail, created bool, err error) {
+	config, err := uc.AdminGet(ctx, examID)
+	if err != nil {
+		return entity.ExamAttemptDetail{}, false, err
+	}
+
+	reg, err := uc.repo.GetRegistration(ctx, examID, userID)
+	switch {
+	case errors.Is(err, repo.ErrNotFound):
+		return entity.ExamAttemptDetail{}, false, ErrNotRegistered
+	case err != nil:
+		return entity.ExamAttemptDetail{}, false, fmt.Errorf("exam - GetRegistration: %w", err)
+	case reg.Status != entity.ExamRegistrationRegistered:
+		return entity.ExamAttemptDetail{}, false, ErrNotRegistered
+	}
+
+	existing, err := uc.attempts.Get(ctx, examID, userID)
+	if err == nil {
+		detail, err := uc.attemptDetail(ctx, userID, config, existing)
+
+		return detail, false, err
+	}
+	if !errors.Is(err, repo.ErrNotFound) {
+		return entity.ExamAttemptDetail{}, false, fmt.Errorf("exam - attempts.Get: %w", err)
+	}
+
+	now := time.Now().UTC()
+	if !attemptOpen(config, now) {
+		return entity.ExamAttemptDetail{}, false, ErrNotOpen
+	}
+
+	paper, err := uc.attempts.ListPaper(ctx, examID)
+	if err != nil {
+		return entity.ExamAttemptDetail{}, false, fmt.Errorf("exam - ListPaper: %w", err)
+	}
+	if len(paper) == 0 {
+		// No user id: the paper must not depend on who starts first.
+		paper, err = uc.questions.ListPoolIDs(ctx, repo.QuestionPoolFilter{Exam: config.Exam, Limit: config.NumQuestions})
+		if err != nil {
+			return entity.ExamAttemptDetail{}, false, fmt.Errorf("exam - ListPoolIDs: %w", err)
+		}
+		if len(paper) == 0 {
+			return entity.ExamAttemptDetail{}, false, ErrNoQuestions
+		}
+	}
+
+	deadline := now.Add(time.Duration(config.TimeLimitMinutes) * time.Minute)
+	if config.ScheduleEndAt.Before(deadline) {
+		deadline = *config.ScheduleEndAt
+	}
+
+	attempt, created, err := uc.attempts.Start(ctx, entity.ExamAttempt{
+		ID:         uuid.New(),
+		ExamID:     examID,
+		UserID:     userID,
+		Status:     entity.ExamAttemptInProgress,
+		StartedAt:  now,
+		DeadlineAt: deadline,
+	}, paper)
+	if err != nil {
+		return entity.ExamAttemptDetail{}, false, fmt.Errorf("exam - attempts.Start: %w", err)
+	}
+
+	detail, err = uc.attemptDetail(ctx, userID, config, attempt)
+
+	return detail, created, err
+}
+
+// GetAttempt returns userID's attempt with its paper, grading it first when
+// its deadline has passed.
+func (uc *UseCase) GetAttempt(ctx context.Context, userID, examID uuid.UUID) (entity.ExamAttemptDetail, error) {
+	config, err := uc.AdminGet(ctx, examID)
+	if err != nil {
+		return entity.ExamAttemptDetail{}, err
+	}
+
+	attempt, err := uc.attempt(ctx, examID, userID)
+	if err != nil {
+		return entity.ExamAttemptDetail{}, err
+	}
+
+	return uc.attemptDetail(ctx, userID, config, attempt)
+}
+
+// SaveAnswer autosaves the answer to one question of userID's attempt until
+// its deadline. An answer can be changed or cleared any number of times;
+// only the last one saved is graded.
+func (uc *UseCase) SaveAnswer(ctx context.Context, userID, examID uuid.UUID, req entity.ExamAnswerRequest) (entity.ExamAttemptQuestion, error) {
+	attempt, err := uc.attempts.Get(ctx, examID, userID)
+	if errors.Is(err, repo.ErrNotFound) {
+		return entity.ExamAttemptQuestion{}, ErrNoAttempt
+	}
+	if err != nil {
+		return entity.ExamAttemptQuestion{}, fmt.Errorf("exam - attempts.Get: %w", err)
+	}
+
+	if attempt.Status != entity.ExamAttemptInProgress {
+		return entity.ExamAttemptQuestion{}, ErrAttemptClosed
+	}
+
+	now := time.Now().UTC()
+	if !now.Before(attempt.DeadlineAt) {
+		return entity.ExamAttemptQuestion{}, ErrTimeUp
+	}
+
+	question, err := uc.attempts.GetQuestion(ctx, attempt.ID, req.AttemptQuestionID)
+	if errors.Is(err, repo.ErrNotFound) {
+		return entity.ExamAttemptQuestion{}, ErrQuestionNotFound
+	}
+	if err != nil {
+		return entity.ExamAttemptQuestion{}, fmt.Errorf("exam - attempts.GetQuestion: %w", err)
+	}
+
+	answer := scoring.NormalizeAnswer(entity.Answer{
+		SelectedOptions: req.SelectedOptions,
+		NumericAnswer:   req.NumericAnswer,
+		MatrixAnswer:    req.MatrixAnswer,
+	})
+
+	question.AnsweredAt = nil
+	if answered(answer) {
+		if err := scoring.Validate(question.Question, answer); err != nil {
+			return entity.ExamAttemptQuestion{}, ErrInvalidAnswer
+		}
+		question.AnsweredAt = &now
+	}
+	question.SelectedOptions = answer.SelectedOptions
+	question.NumericAnswer = answer.NumericAnswer
+	question.MatrixAnswer = answer.MatrixAnswer
+
+	saved, err := uc.attempts.SaveAnswer(ctx, attempt.ID, question, now)
+	if errors.Is(err, repo.ErrConflict) {
+		// Submitted from elsewhere, or the deadline passed meanwhile.
+		if current, err := uc.attempts.Get(ctx, examID, userID); err == nil && current.Status != entity.ExamAttemptInProgress {
+			return entity.ExamAttemptQuestion{}, ErrAttemptClosed
+		}
+
+		return entity.ExamAttemptQuestion{}, ErrTimeUp
+	}
+	if errors.Is(err, repo.ErrNotFound) {
+		return entity.ExamAttemptQuestion{}, ErrQuestionNotFound
+	}
+	if err != nil {
+		return entity.ExamAttemptQuestion{}, fmt.Errorf("exam - attempts.SaveAnswer: %w", err)
+	}
+
+	served := []entity.ExamAttemptQuestion{saved}
+	if err := uc.serve(ctx, userID, served, false); err != nil {
+		return entity.ExamAttemptQuestion{}, err
+	}
+
+	return served[0], nil
+}
+
+// SubmitAttempt closes userID's attempt and grades it. Submitting after the
+// deadline closes the attempt as timed out at its deadline.
+func (uc *UseCase) SubmitAttempt(ctx context.Context, userID, examID uuid.UUID) (entity.ExamAttemptDetail, error) {
+	config, err := uc.AdminGet(ctx, examID)
+	if err != nil {
+		return entity.ExamAttemptDetail{}, err
+	}
+
+	attempt, err := uc.attempts.Get(ctx, examID, userID)
+	if errors.Is(err, repo.ErrNotFound) {
+		return entity.ExamAttemptDetail{}, ErrNoAttempt
+	}
+	if err != nil {
+		return entity.ExamAttemptDetail{}, fmt.Errorf("exam - attempts.Get: %w", err)
+	}
+
+	if attempt.Status != entity.ExamAttemptInProgress {
+		return entity.ExamAttemptDetail{}, ErrAttemptClosed
+	}
+
+	status, at := entity.ExamAttemptSubmitted, time.Now().UTC()
+	if !at.Before(attempt.DeadlineAt) {
+		status, at = entity.ExamAttemptTimedOut, attempt.DeadlineAt
+	}
+
+	attempt, err = uc.finish(ctx, config, attempt, status, at)
+	if err != nil {
+		return entity.ExamAttemptDetail{}, err
+	}
+
+	return uc.attemptDetail(ctx, userID, config, attempt)
+}
+
+// GradeExpired times out attempts whose deadline passed without a submit
+// and grades closed attempts left ungraded, returning how many it graded.
+func (uc *UseCase) GradeExpired(ctx context.Context) (int, error) {
+	overdue, err := uc.attempts.ListUngraded(ctx, time.Now().UTC(), _gradeBatch)
+	if err != nil {
+		return 0, fmt.Errorf("exam - ListUngraded: %w", err)
+	}
+
+	configs := make(map[uuid.UUID]entity.ExamConfig)
+	graded := 0
+	for _, attempt := range overdue {
+		config, ok := configs[attempt.ExamID]
+		if !ok {
+			config, err = uc.repo.GetConfig(ctx, attempt.ExamID)
+			if err != nil {
+				return graded, fmt.Errorf("exam - GetConfig: %w", err)
+			}
+			configs[attempt.ExamID] = config
+		}
+
+		if attempt.Status == entity.ExamAttemptInProgress {
+			_, err = uc.finish(ctx, config, attempt, entity.ExamAttemptTimedOut, attempt.DeadlineAt)
+		} else {
+			_, err = uc.grade(ctx, config, attempt)
+		}
+		if errors.Is(err, ErrAttemptClosed) {
+			// Submitted meanwhile; that request grades it.
+			continue
+		}
+		if err != nil {
+			return graded, err
+		}
+		graded++
+	}
+
+	return graded, nil
+}
+
+// attempt loads userID's attempt, timing it out and grading it once its
+// deadline has passed.
+func (uc *UseCase) attempt(ctx context.Context, examID, userID uuid.UUID) (entity.ExamAttempt, error) {
+	attempt, err := uc.attempts.Get(ctx, examID, userID)
+	if errors.Is(err, repo.ErrNotFound) {
+		return entity.ExamAttempt{}, ErrNoAttempt
+	}
+	if err != nil {
+		return entity.ExamAttempt{}, fmt.Errorf("exam - attempts.Get: %w", err)
+	}
+
+	if attempt.Status != entity.ExamAttemptInProgress || time.Now().Before(attempt.DeadlineAt) {
+		return attempt, nil
+	}
+
+	config, err := uc.AdminGet(ctx, examID)
+	if err != nil {
+		return entity.ExamAttempt{}, err
+	}
+
+	graded, err := uc.finish(ctx, config, attempt, entity.ExamAttemptTimedOut, attempt.DeadlineAt)
+	if errors.Is(err, ErrAttemptClosed) {
+		return uc.attempt(ctx, examID, userID)
+	}
+
+	return graded, err
+}
+
+// finish closes the attempt with status at and grades it. ErrAttemptClosed
+// is returned when another request closed it first.
+func (uc *UseCase) finish(ctx context.Context, config entity.ExamConfig, attempt entity.ExamAttempt, status entity.ExamAttemptStatus, at time.Time) (entity.ExamAttempt, error) {
+	closed, err := uc.attempts.Close(ctx, attempt.ID, status, at)
+	if errors.Is(err, repo.ErrNotFound) {
+		return entity.ExamAttempt{}, ErrAttemptClosed
+	}
+	if err != nil {
+		return entity.ExamAttempt{}, fmt.Errorf("exam - attempts.Close: %w", err)
+	}
+
+	return uc.grade(ctx, config, closed)
+}
+
+// grade scores the saved answers of a closed attempt with the exam's marking.
+func (uc *UseCase) grade(ctx context.Context, config entity.ExamConfig, attempt entity.ExamAttempt) (entity.ExamAttempt, error) {
+	questions, err := uc.attempts.ListQuestions(ctx, attempt.ID)
+	if err != nil {
+		return entity.ExamAttempt{}, fmt.Errorf("exam - attempts.ListQuestions: %w", err)
+	}
+
+	category, err := uc.repo.GetCategory(ctx, config.Exam)
+	if err != nil {
+		return entity.ExamAttempt{}, fmt.Errorf("exam - GetCategory: %w", err)
+	}
+
+	attempt = score(config, category.MultiSelectScoring, attempt, questions)
+
+	graded, err := uc.attempts.Grade(ctx, attempt, questions)
+	if errors.Is(err, repo.ErrNotFound) {
+		// Graded by a concurrent request or the grading job.
+		current, err := uc.attempts.Get(ctx, attempt.ExamID, attempt.UserID)
+		if err != nil {
+			return entity.ExamAttempt{}, fmt.Errorf("exam - attempts.Get: %w", err)
+		}
+
+		return current, nil
+	}
+	if err != nil {
+		return entity.ExamAttempt{}, fmt.Errorf("exam - attempts.Grade: %w", err)
+	}
+
+	return graded, nil
+}
+
+// score marks each question and totals the attempt. A correct answer earns
+// MarksPerCorrect and a wrong one NegativePerWrong; partial credit earns
+// its share of MarksPerCorrect with no penalty, and skipped questions
+// earn nothing.
+func score(config entity.ExamConfig, mode entity.MultiSelectScoring, attempt entity.ExamAttempt, questions []entity.ExamAttemptQuestion) entity.ExamAttempt {
+	attempt.Correct, attempt.Incorrect, attempt.Skipped = 0, 0, 0
+
+	total := 0.0
+	for i := range questions {
+		q := &questions[i]
+
+		if q.AnsweredAt == nil {
+			attempt.Skipped++
+			q.IsCorrect, q.Marks = nil, new(float64)
+
+			continue
+		}
+
+		credit, correct := scoring.Grade(q.Question, entity.Answer{
+			SelectedOptions: q.SelectedOptions,
+			NumericAnswer:   q.NumericAnswer,
+			MatrixAnswer:    q.MatrixAnswer,
+		}, mode)
+
+		var marks float64
+		switch {
+		case correct:
+			attempt.Correct++
+			marks = config.MarksPerCorrect
+		case credit > 0:
+			attempt.Incorrect++
+			marks = round2(credit * config.MarksPerCorrect)
+		default:
+			attempt.Incorrect++
+			marks = config.NegativePerWrong
+		}
+
+		q.IsCorrect, q.Marks = &correct, &marks
+		total += marks
+	}
+
+	total = round2(total)
+	attempt.Score = &total
+
+	return attempt
+}
+
+// attemptDetail loads the attempt's paper as served to userID.
+func (uc *UseCase) attemptDetail(ctx context.Context, userID uuid.UUID, config entity.ExamConfig, attempt entity.ExamAttempt) (entity.ExamAttemptDetail, error) {
+	questions, err := uc.attempts.ListQuestions(ctx, attempt.ID)
+	if err != nil {
+		return entity.ExamAttemptDetail{}, fmt.Errorf("exam - attempts.ListQuestions: %w", err)
+	}
+
+	reveal := attempt.Status != entity.ExamAttemptInProgress && resultsReleased(config, time.Now())
+	if err := uc.serve(ctx, userID, questions, reveal); err != nil {
+		return entity.ExamAttemptDetail{}, err
+	}
+
+	if questions == nil {
+		questions = []entity.ExamAttemptQuestion{}
+	}
+
+	return entity.ExamAttemptDetail{Attempt: attempt, Questions: questions}, nil
+}
+
+// serve attaches media and translations to the questions. Unless reveal is
+// set, the answer keys and per-question marks are withheld, so nobody can
+// pass them on while the exam window is still open.
+func (uc *UseCase) serve(ctx context.Context, userID uuid.UUID, questions []entity.ExamAttemptQuestion, reveal bool) error {
+	ids := make([]uuid.UUID, len(questions))
+	served := make([]*entity.Question, len(questions))
+	for i := range questions {
+		ids[i] = questions[i].Question.ID
+		served[i] = &questions[i].Question
+	}
+
+	byQuestion, err := uc.media.ForQuestions(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("exam - media.ForQuestions: %w", err)
+	}
+
+	for i := range questions {
+		questions[i].Question.Media = byQuestion[questions[i].Question.ID]
+	}
+
+	if err := uc.localizer.Localize(ctx, userID, served); err != nil {
+		return fmt.Errorf("exam - localizer.Localize: %w", err)
+	}
+
+	if reveal {
+		return nil
+	}
+
+	for i := range questions {
+		hideKey(&questions[i].Question)
+		questions[i].IsCorrect, questions[i].Marks = nil, nil
+	}
+
+	return nil
+}
+
+// hideKey strips everything that gives the answer away.
+func hideKey(q *entity.Question) {
+	q.CorrectOption = 0
+	q.CorrectOptions = nil
+	q.NumericAnswer = nil
+	q.NumericTolerance = nil
+	q.Explanation = nil
+	if q.Matrix != nil {
+		matrix := *q.Matrix
+		matrix.Answer = nil
+		q.Matrix = &matrix
+	}
+}
+
+// attemptOpen reports whether attempts can start at now: inside the
+// schedule window of a scheduled or ongoing exam.
+func attemptOpen(config entity.ExamConfig, now time.Time) bool {
+	if config.Status != entity.ExamStatusScheduled && config.Status != entity.ExamStatusOngoing {
+		return false
+	}
+
+	return config.ScheduleStartAt != nil && config.ScheduleEndAt != nil &&
+		!now.Before(*config.ScheduleStartAt) && now.Before(*config.ScheduleEndAt)
+}
+
+// resultsReleased reports whether answer keys and per-question marks can
+// be shown at now: once the window has ended or the exam is completed.
+func resultsReleased(config entity.ExamConfig, now time.Time) bool {
+	return config.Status == entity.ExamStatusCompleted ||
+		config.ScheduleEndAt != nil && !now.Before(*config.ScheduleEndAt)
+}
+
+// answered reports whether answer selects anything.
+func answered(answer entity.Answer) bool {
+	return len(answer.SelectedOptions) > 0 || answer.NumericAnswer != nil || len(answer.MatrixAnswer) > 0
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
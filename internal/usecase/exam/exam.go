@@ -2,43 +2,99 @@ package exam
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/google/uuid"
 
 	"github.com/evrone/go-clean-template/internal/entity"
 	"github.com/evrone/go-clean-template/internal/repo"
+	"github.com/evrone/go-clean-template/internal/usecase/localization"
+	"github.com/evrone/go-clean-template/internal/usecase/media"
 )
 
-// UseCase manages exam config.
+var (
+	// ErrCategoryNotFound when the exam category does not exist.
+	ErrCategoryNotFound = errors.New("exam category not found")
+	// ErrNotFound when the exam config does not exist.
+	ErrNotFound = errors.New("exam not found")
+	// ErrInvalidSchedule when the schedule window ends before it starts, or
+	// registration closes after it ends.
+	ErrInvalidSchedule = errors.New("schedule end must be after its start and registration close")
+	// ErrScheduleRequired when an exam leaves draft without a schedule window.
+	ErrScheduleRequired = errors.New("a scheduled exam needs a start and an end")
+	// ErrInvalidTransition when the exam cannot move to the requested status.
+	ErrInvalidTransition = errors.New("invalid exam status transition")
+	// ErrMarkingLocked when the marking rules of a started exam are edited.
+	ErrMarkingLocked = errors.New("marking rules cannot change once the exam is ongoing")
+	// ErrStatusChanged when the exam changed status while being updated.
+	ErrStatusChanged = errors.New("exam status changed, reload and retry")
+	// ErrHasRegistrations when deleting an exam users registered for.
+	ErrHasRegistrations = errors.New("exam has registrations")
+)
+
+// transitions lists the statuses each exam status can move to.
+func transitions() map[entity.ExamStatus][]entity.ExamStatus {
+	return map[entity.ExamStatus][]entity.ExamStatus{
+		entity.ExamStatusDraft:     {entity.ExamStatusScheduled},
+		entity.ExamStatusScheduled: {entity.ExamStatusDraft, entity.ExamStatusOngoing},
+		entity.ExamStatusOngoing:   {entity.ExamStatusCompleted},
+		entity.ExamStatusCompleted: {},
+	}
+}
+
+// Options set the refund policy for cancelled registrations: the whole fee
+// back until FullRefundBefore the start, PartialRefundPercent of it until
+// PartialRefundBefore, and nothing after that.
+type Options struct {
+	FullRefundBefore     time.Duration
+	PartialRefundBefore  time.Duration
+	PartialRefundPercent int
+}
+
+// UseCase manages exam config, registrations and attempts.
 type UseCase struct {
-	repo repo.ExamRepository
+	repo      repo.ExamRepository
+	attempts  repo.ExamAttemptRepository
+	questions repo.QuestionRepository
+	media     *media.UseCase
+	localizer *localization.UseCase
+	opts      Options
 }
 
 // New constructs UseCase.
-func New(repo repo.ExamRepository) *UseCase {
-	return &UseCase{repo: repo}
+func New(
+	repo repo.ExamRepository,
+	attempts repo.ExamAttemptRepository,
+	questions repo.QuestionRepository,
+	media *media.UseCase,
+	localizer *localization.UseCase,
+	opts Options,
+) *UseCase {
+	return &UseCase{
+		repo:      repo,
+		attempts:  attempts,
+		questions: questions,
+		media:     media,
+		localizer: localizer,
+		opts:      opts,
+	}
 }
 
 // AdminList returns configs with optional exam filter.
 func (uc *UseCase) AdminList(ctx context.Context, exam *entity.ExamCategory) ([]entity.ExamConfig, error) {
-	configs, err := uc.repo.ListConfigs(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("exam - ListConfigs: %w", err)
+	if exam != nil && *exam == "" {
+		exam = nil
 	}
 
-	if exam == nil || *exam == "" {
-		return configs, nil
-	}
-
-	filtered := make([]entity.ExamConfig, 0, len(configs))
-	for _, cfg := range configs {
-		if cfg.Exam == *exam {
-			filtered = append(filtered, cfg)
-		}
+	configs, err := uc.repo.ListConfigs(ctx, exam)
+	if err != nil {
+		return nil, fmt.Errorf("exam - ListConfigs: %w", err)
 	}
 
-	return filtered, nil
+	return configs, nil
 }
 
 // AdminCreate stores a config.
@@ -56,10 +112,18 @@ func (uc *UseCase) AdminCreate(ctx context.Context, req entity.ExamConfigCreateR
 		EntryFee:         req.EntryFee,
 		ScheduleStartAt:  req.ScheduleStartAt,
 		ScheduleEndAt:    req.ScheduleEndAt,
+		RegisterUntil:    req.RegisterUntil,
+		Capacity:         req.Capacity,
 		Status:           entity.ExamStatusDraft,
 	}
+	if err := checkSchedule(config); err != nil {
+		return entity.ExamConfig{}, err
+	}
 
 	created, err := uc.repo.CreateConfig(ctx, config)
+	if errors.Is(err, repo.ErrNotFound) {
+		return entity.ExamConfig{}, ErrCategoryNotFound
+	}
 	if err != nil {
 		return entity.ExamConfig{}, fmt.Errorf("exam - CreateConfig: %w", err)
 	}
@@ -70,6 +134,9 @@ func (uc *UseCase) AdminCreate(ctx context.Context, req entity.ExamConfigCreateR
 // AdminGet retrieves config.
 func (uc *UseCase) AdminGet(ctx context.Context, id uuid.UUID) (entity.ExamConfig, error) {
 	config, err := uc.repo.GetConfig(ctx, id)
+	if errors.Is(err, repo.ErrNotFound) {
+		return entity.ExamConfig{}, ErrNotFound
+	}
 	if err != nil {
 		return entity.ExamConfig{}, fmt.Errorf("exam - GetConfig: %w", err)
 	}
@@ -77,13 +144,18 @@ func (uc *UseCase) AdminGet(ctx context.Context, id uuid.UUID) (entity.ExamConfi
 	return config, nil
 }
 
-// AdminUpdate modifies config.
+// AdminUpdate modifies config. Statuses move DRAFT -> SCHEDULED -> ONGOING
+// -> COMPLETED, and a scheduled exam can go back to draft. Scheduling needs
+// a complete window, and the marking rules are fixed from the moment the
+// exam goes ongoing.
 func (uc *UseCase) AdminUpdate(ctx context.Context, id uuid.UUID, req entity.ExamConfigUpdateRequest) (entity.ExamConfig, error) {
-	config, err := uc.repo.GetConfig(ctx, id)
+	current, err := uc.AdminGet(ctx, id)
 	if err != nil {
-		return entity.ExamConfig{}, fmt.Errorf("exam - GetConfig: %w", err)
+		return entity.ExamConfig{}, err
 	}
 
+	config := current
+
 	if req.Name != nil {
 		config.Name = *req.Name
 	}
@@ -114,33 +186,111 @@ func (uc *UseCase) AdminUpdate(ctx context.Context, id uuid.UUID, req entity.Exa
 	if req.ScheduleEndAt != nil {
 		config.ScheduleEndAt = req.ScheduleEndAt
 	}
-	if req.Status != nil {
+	if req.RegisterUntil != nil {
+		config.RegisterUntil = req.RegisterUntil
+	}
+	if req.Capacity != nil {
+		config.Capacity = req.Capacity
+	}
+	if req.Status != nil && *req.Status != current.Status {
+		if !slices.Contains(transitions()[current.Status], *req.Status) {
+			return entity.ExamConfig{}, ErrInvalidTransition
+		}
 		config.Status = *req.Status
 	}
 
-	updated, err := uc.repo.UpdateConfig(ctx, config)
-	if err != nil {
+	if locked(current.Status) || locked(config.Status) {
+		if config.MarksPerCorrect != current.MarksPerCorrect || config.NegativePerWrong != current.NegativePerWrong {
+			return entity.ExamConfig{}, ErrMarkingLocked
+		}
+	}
+	if err := checkSchedule(config); err != nil {
+		return entity.ExamConfig{}, err
+	}
+
+	updated, err := uc.repo.UpdateConfig(ctx, config, current.Status)
+	switch {
+	case errors.Is(err, repo.ErrNotFound):
+		return entity.ExamConfig{}, ErrNotFound
+	case errors.Is(err, repo.ErrConflict):
+		return entity.ExamConfig{}, ErrStatusChanged
+	case err != nil:
 		return entity.ExamConfig{}, fmt.Errorf("exam - UpdateConfig: %w", err)
 	}
 
 	return updated, nil
 }
 
-// AdminDelete removes config.
+// AdminDelete removes config, unless users registered for it.
 func (uc *UseCase) AdminDelete(ctx context.Context, id uuid.UUID) error {
-	if err := uc.repo.DeleteConfig(ctx, id); err != nil {
+	err := uc.repo.DeleteConfig(ctx, id)
+	switch {
+	case errors.Is(err, repo.ErrNotFound):
+		return ErrNotFound
+	case errors.Is(err, repo.ErrConflict):
+		return ErrHasRegistrations
+	case err != nil:
 		return fmt.Errorf("exam - DeleteConfig: %w", err)
 	}
 
 	return nil
 }
 
-// ListEvents returns exam summaries.
-func (uc *UseCase) ListEvents(ctx context.Context) ([]entity.ExamSummary, error) {
-	summaries, err := uc.repo.ListSummaries(ctx)
+// checkSchedule requires the window to end after it starts and after
+// registration closes, and a scheduled or running exam to have both ends.
+func checkSchedule(config entity.ExamConfig) error {
+	start, end := config.ScheduleStartAt, config.ScheduleEndAt
+	if start != nil && end != nil && !end.After(*start) {
+		return ErrInvalidSchedule
+	}
+	if until := config.RegisterUntil; until != nil && end != nil && until.After(*end) {
+		return ErrInvalidSchedule
+	}
+	if config.Status != entity.ExamStatusDraft && (start == nil || end == nil) {
+		return ErrScheduleRequired
+	}
+
+	return nil
+}
+
+// locked reports whether an exam in status has started, fixing its marking
+// rules.
+func locked(status entity.ExamStatus) bool {
+	return status == entity.ExamStatusOngoing || status == entity.ExamStatusCompleted
+}
+
+// ListEvents returns the scheduled and ongoing exams as seen by userID.
+func (uc *UseCase) ListEvents(ctx context.Context, userID uuid.UUID) ([]entity.ExamSummary, error) {
+	summaries, err := uc.repo.ListSummaries(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("exam - ListSummaries: %w", err)
 	}
 
 	return summaries, nil
 }
+
+// AdminListCategories returns the marking settings of every exam category.
+func (uc *UseCase) AdminListCategories(ctx context.Context) ([]entity.ExamCategorySettings, error) {
+	categories, err := uc.repo.ListCategories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("exam - ListCategories: %w", err)
+	}
+
+	return categories, nil
+}
+
+// AdminUpdateCategory changes the marking settings of one exam category.
+func (uc *UseCase) AdminUpdateCategory(ctx context.Context, exam entity.ExamCategory, req entity.ExamCategorySettingsUpdateRequest) (entity.ExamCategorySettings, error) {
+	updated, err := uc.repo.UpdateCategory(ctx, entity.ExamCategorySettings{
+		Exam:               exam,
+		MultiSelectScoring: req.MultiSelectScoring,
+	})
+	if errors.Is(err, repo.ErrNotFound) {
+		return entity.ExamCategorySettings{}, ErrCategoryNotFound
+	}
+	if err != nil {
+		return entity.ExamCategorySettings{}, fmt.Errorf("exam - UpdateCategory: %w", err)
+	}
+
+	return updated, nil
+}
@@ -0,0 +1,121 @@
+package exam
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/repo"
+)
+
+var (
+	// ErrRegistrationClosed when the exam is not taking registrations.
+	ErrRegistrationClosed = errors.New("registration is closed")
+	// ErrFull when every seat of the exam is taken.
+	ErrFull = errors.New("exam is full")
+	// ErrCouponUnavailable when the coupon is not a usable entry pass.
+	ErrCouponUnavailable = errors.New("coupon is not a valid entry pass")
+	// ErrInsufficientFunds when the wallet cannot pay the entry fee.
+	ErrInsufficientFunds = errors.New("insufficient wallet balance")
+	// ErrNotRegistered when the user holds no registration to cancel or sit.
+	ErrNotRegistered = errors.New("not registered")
+	// ErrCancelClosed when the exam has started or is not scheduled.
+	ErrCancelClosed = errors.New("registration can only be cancelled before the exam starts")
+)
+
+// Register seats userID in a scheduled or ongoing exam until registration
+// closes, paying the entry fee with the entry pass in req, if any, and the
+// wallet for the rest. Registering again returns the existing registration
+// with created false and charges nothing, also after registration closed.
+func (uc *UseCase) Register(ctx context.Context, userID, examID uuid.UUID, req entity.ExamRegisterRequest) (reg entity.ExamRegistration, created bool, err error) {
+	config, err := uc.AdminGet(ctx, examID)
+	if err != nil {
+		return entity.ExamRegistration{}, false, err
+	}
+
+	if !registrationOpen(config, time.Now()) {
+		existing, err := uc.repo.GetRegistration(ctx, examID, userID)
+		if err == nil && existing.Status == entity.ExamRegistrationRegistered {
+			return existing, false, nil
+		}
+		if err != nil && !errors.Is(err, repo.ErrNotFound) {
+			return entity.ExamRegistration{}, false, fmt.Errorf("exam - GetRegistration: %w", err)
+		}
+
+		return entity.ExamRegistration{}, false, ErrRegistrationClosed
+	}
+
+	reg, created, err = uc.repo.Register(ctx, examID, userID, strings.TrimSpace(req.CouponCode))
+	switch {
+	case errors.Is(err, repo.ErrNotFound):
+		return entity.ExamRegistration{}, false, ErrNotFound
+	case errors.Is(err, repo.ErrCapacity):
+		return entity.ExamRegistration{}, false, ErrFull
+	case errors.Is(err, repo.ErrCouponUnavailable):
+		return entity.ExamRegistration{}, false, ErrCouponUnavailable
+	case errors.Is(err, repo.ErrInsufficientFunds):
+		return entity.ExamRegistration{}, false, ErrInsufficientFunds
+	case err != nil:
+		return entity.ExamRegistration{}, false, fmt.Errorf("exam - Register: %w", err)
+	}
+
+	return reg, created, nil
+}
+
+// Cancel gives up userID's seat before the exam starts, refunding the fee
+// paid from the wallet according to the refund policy. An entry pass used
+// for the fee can be used again.
+func (uc *UseCase) Cancel(ctx context.Context, userID, examID uuid.UUID) (entity.ExamRegistration, error) {
+	config, err := uc.AdminGet(ctx, examID)
+	if err != nil {
+		return entity.ExamRegistration{}, err
+	}
+
+	now := time.Now()
+	if config.Status != entity.ExamStatusScheduled || config.ScheduleStartAt == nil || !now.Before(*config.ScheduleStartAt) {
+		return entity.ExamRegistration{}, ErrCancelClosed
+	}
+
+	reg, err := uc.repo.Cancel(ctx, examID, userID, uc.refundPercent(config.ScheduleStartAt.Sub(now)))
+	if errors.Is(err, repo.ErrNotFound) {
+		return entity.ExamRegistration{}, ErrNotRegistered
+	}
+	if err != nil {
+		return entity.ExamRegistration{}, fmt.Errorf("exam - Cancel: %w", err)
+	}
+
+	return reg, nil
+}
+
+// refundPercent is the share of the fee refunded when cancelling this long
+// before the start.
+func (uc *UseCase) refundPercent(beforeStart time.Duration) int {
+	switch {
+	case beforeStart >= uc.opts.FullRefundBefore:
+		return 100
+	case beforeStart >= uc.opts.PartialRefundBefore:
+		return min(max(uc.opts.PartialRefundPercent, 0), 100)
+	default:
+		return 0
+	}
+}
+
+// registrationOpen reports whether config takes registrations at now: once
+// scheduled, until RegisterUntil or else the start.
+func registrationOpen(config entity.ExamConfig, now time.Time) bool {
+	if config.Status != entity.ExamStatusScheduled && config.Status != entity.ExamStatusOngoing {
+		return false
+	}
+
+	closes := config.RegisterUntil
+	if closes == nil {
+		closes = config.ScheduleStartAt
+	}
+
+	return closes != nil && now.Before(*closes)
+}
@@ -0,0 +1,714 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/repo"
+	"github.com/evrone/go-clean-template/internal/repo/webapi"
+	"github.com/evrone/go-clean-template/internal/usecase/exam"
+	"github.com/evrone/go-clean-template/internal/usecase/localization"
+	"github.com/evrone/go-clean-template/internal/usecase/media"
+	"github.com/evrone/go-clean-template/pkg/blob"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type examMocks struct {
+	exams         *MockExamRepository
+	attempts      *MockExamAttemptRepository
+	questions     *MockQuestionRepository
+	media         *MockQuestionMediaRepository
+	localizations *MockLocalizationRepository
+}
+
+func examUseCase(t *testing.T) (*exam.UseCase, *MockExamRepository) {
+	t.Helper()
+
+	uc, m := attemptUseCase(t)
+
+	return uc, m.exams
+}
+
+// attemptUseCase serves papers with no media to learners who kept the
+// default language.
+func attemptUseCase(t *testing.T) (*exam.UseCase, examMocks) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+
+	m := examMocks{
+		exams:         NewMockExamRepository(mockCtl),
+		attempts:      NewMockExamAttemptRepository(mockCtl),
+		questions:     NewMockQuestionRepository(mockCtl),
+		media:         NewMockQuestionMediaRepository(mockCtl),
+		localizations: NewMockLocalizationRepository(mockCtl),
+	}
+
+	m.media.EXPECT().ListByQuestions(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	m.localizations.EXPECT().GetLanguage(gomock.Any(), gomock.Any()).Return(entity.LanguageEnglish, nil).AnyTimes()
+
+	store, err := blob.NewLocal(t.TempDir(), "http://localhost/v1/media", "secret")
+	require.NoError(t, err)
+
+	attachments := media.New(m.media, m.questions, store, media.Options{MaxBytes: 1 << 20, URLTTL: time.Hour})
+	localizer := localization.New(m.localizations, webapi.NewDictionary(nil), localization.Options{Languages: []string{"hi"}})
+
+	return exam.New(m.exams, m.attempts, m.questions, attachments, localizer, exam.Options{
+		FullRefundBefore:     24 * time.Hour,
+		PartialRefundBefore:  2 * time.Hour,
+		PartialRefundPercent: 50,
+	}), m
+}
+
+func examConfig(status entity.ExamStatus) entity.ExamConfig {
+	start := time.Date(2025, 12, 20, 10, 0, 0, 0, time.UTC)
+	end := start.Add(3 * time.Hour)
+
+	return entity.ExamConfig{
+		ID:               uuid.New(),
+		Exam:             entity.ExamCategoryNEETPG,
+		Name:             "Grand Test 1",
+		Type:             entity.ExamTypeMock,
+		NumQuestions:     200,
+		TimeLimitMinutes: 210,
+		MarksPerCorrect:  4,
+		NegativePerWrong: -1,
+		ScheduleStartAt:  &start,
+		ScheduleEndAt:    &end,
+		Status:           status,
+	}
+}
+
+func TestCreateExam(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, 12, 20, 10, 0, 0, 0, time.UTC)
+	before := start.Add(-time.Hour)
+
+	t.Run("window must end after it starts", func(t *testing.T) {
+		t.Parallel()
+
+		uc, _ := examUseCase(t)
+
+		_, err := uc.AdminCreate(context.Background(), entity.ExamConfigCreateRequest{
+			Exam: entity.ExamCategoryNEETPG, Name: "Mock", Type: entity.ExamTypeMock, NumQuestions: 10, TimeLimitMinutes: 15,
+			ScheduleStartAt: &start, ScheduleEndAt: &before,
+		})
+		require.ErrorIs(t, err, exam.ErrInvalidSchedule)
+	})
+
+	t.Run("unknown exam", func(t *testing.T) {
+		t.Parallel()
+
+		uc, exams := examUseCase(t)
+		exams.EXPECT().CreateConfig(gomock.Any(), gomock.Any()).Return(entity.ExamConfig{}, repo.ErrNotFound)
+
+		_, err := uc.AdminCreate(context.Background(), entity.ExamConfigCreateRequest{
+			Exam: "GATE", Name: "Mock", Type: entity.ExamTypeMock, NumQuestions: 10, TimeLimitMinutes: 15,
+		})
+		require.ErrorIs(t, err, exam.ErrCategoryNotFound)
+	})
+}
+
+func TestUpdateExam(t *testing.T) {
+	t.Parallel()
+
+	scheduled, ongoing := entity.ExamStatusScheduled, entity.ExamStatusOngoing
+	completed, draft := entity.ExamStatusCompleted, entity.ExamStatusDraft
+
+	t.Run("starts a scheduled exam", func(t *testing.T) {
+		t.Parallel()
+
+		uc, exams := examUseCase(t)
+		current := examConfig(scheduled)
+		exams.EXPECT().GetConfig(gomock.Any(), current.ID).Return(current, nil)
+		exams.EXPECT().UpdateConfig(gomock.Any(), gomock.Any(), scheduled).
+			DoAndReturn(func(_ context.Context, c entity.ExamConfig, _ entity.ExamStatus) (entity.ExamConfig, error) {
+				return c, nil
+			})
+
+		updated, err := uc.AdminUpdate(context.Background(), current.ID, entity.ExamConfigUpdateRequest{Status: &ongoing})
+		require.NoError(t, err)
+		require.Equal(t, ongoing, updated.Status)
+	})
+
+	t.Run("cannot skip or reverse statuses", func(t *testing.T) {
+		t.Parallel()
+
+		for _, tc := range []struct {
+			from entity.ExamStatus
+			to   entity.ExamStatus
+		}{
+			{draft, ongoing},
+			{ongoing, scheduled},
+			{completed, ongoing},
+		} {
+			uc, exams := examUseCase(t)
+			current := examConfig(tc.from)
+			exams.EXPECT().GetConfig(gomock.Any(), current.ID).Return(current, nil)
+
+			_, err := uc.AdminUpdate(context.Background(), current.ID, entity.ExamConfigUpdateRequest{Status: &tc.to})
+			require.ErrorIs(t, err, exam.ErrInvalidTransition, "%s -> %s", tc.from, tc.to)
+		}
+	})
+
+	t.Run("scheduling needs a window", func(t *testing.T) {
+		t.Parallel()
+
+		uc, exams := examUseCase(t)
+		current := examConfig(draft)
+		current.ScheduleEndAt = nil
+		exams.EXPECT().GetConfig(gomock.Any(), current.ID).Return(current, nil)
+
+		_, err := uc.AdminUpdate(context.Background(), current.ID, entity.ExamConfigUpdateRequest{Status: &scheduled})
+		require.ErrorIs(t, err, exam.ErrScheduleRequired)
+	})
+
+	t.Run("window must end after it starts", func(t *testing.T) {
+		t.Parallel()
+
+		uc, exams := examUseCase(t)
+		current := examConfig(draft)
+		end := current.ScheduleStartAt.Add(-time.Minute)
+		exams.EXPECT().GetConfig(gomock.Any(), current.ID).Return(current, nil)
+
+		_, err := uc.AdminUpdate(context.Background(), current.ID, entity.ExamConfigUpdateRequest{ScheduleEndAt: &end})
+		require.ErrorIs(t, err, exam.ErrInvalidSchedule)
+	})
+
+	t.Run("marking rules are fixed once ongoing", func(t *testing.T) {
+		t.Parallel()
+
+		uc, exams := examUseCase(t)
+		current := examConfig(ongoing)
+		negative := -0.25
+		exams.EXPECT().GetConfig(gomock.Any(), current.ID).Return(current, nil)
+
+		_, err := uc.AdminUpdate(context.Background(), current.ID, entity.ExamConfigUpdateRequest{NegativePerWrong: &negative})
+		require.ErrorIs(t, err, exam.ErrMarkingLocked)
+	})
+
+	t.Run("other fields of an ongoing exam can change", func(t *testing.T) {
+		t.Parallel()
+
+		uc, exams := examUseCase(t)
+		current := examConfig(ongoing)
+		name, marks := "Grand Test 1 (rescheduled)", 4.0
+		exams.EXPECT().GetConfig(gomock.Any(), current.ID).Return(current, nil)
+		exams.EXPECT().UpdateConfig(gomock.Any(), gomock.Any(), ongoing).
+			DoAndReturn(func(_ context.Context, c entity.ExamConfig, _ entity.ExamStatus) (entity.ExamConfig, error) {
+				return c, nil
+			})
+
+		updated, err := uc.AdminUpdate(context.Background(), current.ID, entity.ExamConfigUpdateRequest{Name: &name, MarksPerCorrect: &marks})
+		require.NoError(t, err)
+		require.Equal(t, name, updated.Name)
+	})
+
+	t.Run("status changed concurrently", func(t *testing.T) {
+		t.Parallel()
+
+		uc, exams := examUseCase(t)
+		current := examConfig(scheduled)
+		exams.EXPECT().GetConfig(gomock.Any(), current.ID).Return(current, nil)
+		exams.EXPECT().UpdateConfig(gomock.Any(), gomock.Any(), scheduled).Return(entity.ExamConfig{}, repo.ErrConflict)
+
+		_, err := uc.AdminUpdate(context.Background(), current.ID, entity.ExamConfigUpdateRequest{Status: &ongoing})
+		require.ErrorIs(t, err, exam.ErrStatusChanged)
+	})
+
+	t.Run("unknown exam", func(t *testing.T) {
+		t.Parallel()
+
+		uc, exams := examUseCase(t)
+		id := uuid.New()
+		exams.EXPECT().GetConfig(gomock.Any(), id).Return(entity.ExamConfig{}, repo.ErrNotFound)
+
+		_, err := uc.AdminUpdate(context.Background(), id, entity.ExamConfigUpdateRequest{Status: &ongoing})
+		require.ErrorIs(t, err, exam.ErrNotFound)
+	})
+}
+
+func TestRegisterForExam(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	open := func() entity.ExamConfig {
+		config := examConfig(entity.ExamStatusScheduled)
+		start := time.Now().Add(48 * time.Hour)
+		end := start.Add(3 * time.Hour)
+		config.ScheduleStartAt, config.ScheduleEndAt = &start, &end
+		config.EntryFee = 4900
+
+		return config
+	}
+
+	t.Run("pays with an entry pass", func(t *testing.T) {
+		t.Parallel()
+
+		uc, exams := examUseCase(t)
+		config := open()
+		exams.EXPECT().GetConfig(gomock.Any(), config.ID).Return(config, nil)
+		exams.EXPECT().Register(gomock.Any(), config.ID, userID, "FREEMOCK").
+			Return(entity.ExamRegistration{ExamID: config.ID, UserID: userID, Status: entity.ExamRegistrationRegistered, Covered: 4900}, true, nil)
+
+		reg, created, err := uc.Register(context.Background(), userID, config.ID, entity.ExamRegisterRequest{CouponCode: " FREEMOCK "})
+		require.NoError(t, err)
+		require.True(t, created)
+		require.Equal(t, 4900, reg.Covered)
+	})
+
+	t.Run("registering again after closing returns the seat", func(t *testing.T) {
+		t.Parallel()
+
+		uc, exams := examUseCase(t)
+		config := open()
+		closed := time.Now().Add(-time.Minute)
+		config.RegisterUntil = &closed
+		existing := entity.ExamRegistration{ExamID: config.ID, UserID: userID, Status: entity.ExamRegistrationRegistered, Fee: 4900}
+		exams.EXPECT().GetConfig(gomock.Any(), config.ID).Return(config, nil)
+		exams.EXPECT().GetRegistration(gomock.Any(), config.ID, userID).Return(existing, nil)
+
+		reg, created, err := uc.Register(context.Background(), userID, config.ID, entity.ExamRegisterRequest{})
+		require.NoError(t, err)
+		require.False(t, created)
+		require.Equal(t, existing, reg)
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		t.Parallel()
+
+		for _, config := range []entity.ExamConfig{examConfig(entity.ExamStatusDraft), examConfig(entity.ExamStatusCompleted), open()} {
+			if config.Status == entity.ExamStatusScheduled {
+				closed := time.Now().Add(-time.Minute)
+				config.RegisterUntil = &closed
+			}
+
+			uc, exams := examUseCase(t)
+			exams.EXPECT().GetConfig(gomock.Any(), config.ID).Return(config, nil)
+			exams.EXPECT().GetRegistration(gomock.Any(), config.ID, userID).Return(entity.ExamRegistration{}, repo.ErrNotFound)
+
+			_, _, err := uc.Register(context.Background(), userID, config.ID, entity.ExamRegisterRequest{})
+			require.ErrorIs(t, err, exam.ErrRegistrationClosed, config.Status)
+		}
+	})
+
+	t.Run("payment failures", func(t *testing.T) {
+		t.Parallel()
+
+		for repoErr, want := range map[error]error{
+			repo.ErrCapacity:          exam.ErrFull,
+			repo.ErrInsufficientFunds: exam.ErrInsufficientFunds,
+			repo.ErrCouponUnavailable: exam.ErrCouponUnavailable,
+		} {
+			uc, exams := examUseCase(t)
+			config := open()
+			exams.EXPECT().GetConfig(gomock.Any(), config.ID).Return(config, nil)
+			exams.EXPECT().Register(gomock.Any(), config.ID, userID, "").Return(entity.ExamRegistration{}, false, repoErr)
+
+			_, _, err := uc.Register(context.Background(), userID, config.ID, entity.ExamRegisterRequest{})
+			require.ErrorIs(t, err, want)
+		}
+	})
+}
+
+func TestCancelExamRegistration(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	startingIn := func(d time.Duration) entity.ExamConfig {
+		config := examConfig(entity.ExamStatusScheduled)
+		start := time.Now().Add(d)
+		end := start.Add(3 * time.Hour)
+		config.ScheduleStartAt, config.ScheduleEndAt = &start, &end
+
+		return config
+	}
+
+	t.Run("refund policy", func(t *testing.T) {
+		t.Parallel()
+
+		for before, percent := range map[time.Duration]int{
+			48 * time.Hour:   100,
+			5 * time.Hour:    50,
+			30 * time.Minute: 0,
+		} {
+			uc, exams := examUseCase(t)
+			config := startingIn(before)
+			exams.EXPECT().GetConfig(gomock.Any(), config.ID).Return(config, nil)
+			exams.EXPECT().Cancel(gomock.Any(), config.ID, userID, percent).
+				Return(entity.ExamRegistration{Status: entity.ExamRegistrationCancelled}, nil)
+
+			reg, err := uc.Cancel(context.Background(), userID, config.ID)
+			require.NoError(t, err)
+			require.Equal(t, entity.ExamRegistrationCancelled, reg.Status)
+		}
+	})
+
+	t.Run("not after the start", func(t *testing.T) {
+		t.Parallel()
+
+		uc, exams := examUseCase(t)
+		config := startingIn(-time.Minute)
+		exams.EXPECT().GetConfig(gomock.Any(), config.ID).Return(config, nil)
+
+		_, err := uc.Cancel(context.Background(), userID, config.ID)
+		require.ErrorIs(t, err, exam.ErrCancelClosed)
+	})
+
+	t.Run("not registered", func(t *testing.T) {
+		t.Parallel()
+
+		uc, exams := examUseCase(t)
+		config := startingIn(48 * time.Hour)
+		exams.EXPECT().GetConfig(gomock.Any(), config.ID).Return(config, nil)
+		exams.EXPECT().Cancel(gomock.Any(), config.ID, userID, 100).Return(entity.ExamRegistration{}, repo.ErrNotFound)
+
+		_, err := uc.Cancel(context.Background(), userID, config.ID)
+		require.ErrorIs(t, err, exam.ErrNotRegistered)
+	})
+}
+
+// liveExam is a scheduled exam whose window opened an hour ago and closes
+// in an hour, with 4 marks per correct answer and -1 per wrong one.
+func liveExam() entity.ExamConfig {
+	config := examConfig(entity.ExamStatusOngoing)
+	start := time.Now().Add(-time.Hour)
+	end := time.Now().Add(time.Hour)
+	config.ScheduleStartAt, config.ScheduleEndAt = &start, &end
+
+	return config
+}
+
+func singleChoice(correct int) entity.Question {
+	return entity.Question{
+		ID:             uuid.New(),
+		ChoiceType:     entity.ChoiceTypeSingle,
+		CorrectOption:  correct,
+		CorrectOptions: []int{correct},
+		Explanation:    new(string),
+	}
+}
+
+func TestStartExamAttempt(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	registered := entity.ExamRegistration{Status: entity.ExamRegistrationRegistered}
+
+	t.Run("freezes a paper and ends with the window", func(t *testing.T) {
+		t.Parallel()
+
+		uc, m := attemptUseCase(t)
+		config := liveExam()
+		paper := []uuid.UUID{uuid.New(), uuid.New()}
+
+		m.exams.EXPECT().GetConfig(gomock.Any(), config.ID).Return(config, nil)
+		m.exams.EXPECT().GetRegistration(gomock.Any(), config.ID, userID).Return(registered, nil)
+		m.attempts.EXPECT().Get(gomock.Any(), config.ID, userID).Return(entity.ExamAttempt{}, repo.ErrNotFound)
+		m.attempts.EXPECT().ListPaper(gomock.Any(), config.ID).Return(nil, nil)
+		m.questions.EXPECT().ListPoolIDs(gomock.Any(), repo.QuestionPoolFilter{Exam: config.Exam, Limit: config.NumQuestions}).
+			Return(paper, nil)
+		m.attempts.EXPECT().Start(gomock.Any(), gomock.Any(), paper).DoAndReturn(
+			func(_ context.Context, attempt entity.ExamAttempt, _ []uuid.UUID) (entity.ExamAttempt, bool, error) {
+				// The 210 minute limit is cut short by the window.
+				require.Equal(t, *config.ScheduleEndAt, attempt.DeadlineAt)
+				require.Equal(t, entity.ExamAttemptInProgress, attempt.Status)
+
+				return attempt, true, nil
+			})
+		m.attempts.EXPECT().ListQuestions(gomock.Any(), gomock.Any()).Return([]entity.ExamAttemptQuestion{
+			{ID: uuid.New(), SequenceIndex: 1, Question: singleChoice(2)},
+		}, nil)
+
+		detail, created, err := uc.StartAttempt(context.Background(), userID, config.ID)
+		require.NoError(t, err)
+		require.True(t, created)
+		require.Len(t, detail.Questions, 1)
+
+		served := detail.Questions[0].Question
+		require.Zero(t, served.CorrectOption)
+		require.Empty(t, served.CorrectOptions)
+		require.Nil(t, served.Explanation)
+	})
+
+	t.Run("resumes the existing attempt", func(t *testing.T) {
+		t.Parallel()
+
+		uc, m := attemptUseCase(t)
+		config := liveExam()
+		existing := entity.ExamAttempt{ID: uuid.New(), Status: entity.ExamAttemptInProgress}
+
+		m.exams.EXPECT().GetConfig(gomock.Any(), config.ID).Return(config, nil)
+		m.exams.EXPECT().GetRegistration(gomock.Any(), config.ID, userID).Return(registered, nil)
+		m.attempts.EXPECT().Get(gomock.Any(), config.ID, userID).Return(existing, nil)
+		m.attempts.EXPECT().ListQuestions(gomock.Any(), existing.ID).Return(nil, nil)
+
+		detail, created, err := uc.StartAttempt(context.Background(), userID, config.ID)
+		require.NoError(t, err)
+		require.False(t, created)
+		require.Equal(t, existing.ID, detail.Attempt.ID)
+	})
+
+	t.Run("requires a registration", func(t *testing.T) {
+		t.Parallel()
+
+		for _, reg := range []struct {
+			reg entity.ExamRegistration
+			err error
+		}{
+			{err: repo.ErrNotFound},
+			{reg: entity.ExamRegistration{Status: entity.ExamRegistrationCancelled}},
+		} {
+			uc, m := attemptUseCase(t)
+			config := liveExam()
+			m.exams.EXPECT().GetConfig(gomock.Any(), config.ID).Return(config, nil)
+			m.exams.EXPECT().GetRegistration(gomock.Any(), config.ID, userID).Return(reg.reg, reg.err)
+
+			_, _, err := uc.StartAttempt(context.Background(), userID, config.ID)
+			require.ErrorIs(t, err, exam.ErrNotRegistered)
+		}
+	})
+
+	t.Run("only inside the window", func(t *testing.T) {
+		t.Parallel()
+
+		for _, shift := range []time.Duration{2 * time.Hour, -2 * time.Hour} {
+			uc, m := attemptUseCase(t)
+			config := liveExam()
+			start, end := config.ScheduleStartAt.Add(shift), config.ScheduleEndAt.Add(shift)
+			config.ScheduleStartAt, config.ScheduleEndAt = &start, &end
+
+			m.exams.EXPECT().GetConfig(gomock.Any(), config.ID).Return(config, nil)
+			m.exams.EXPECT().GetRegistration(gomock.Any(), config.ID, userID).Return(registered, nil)
+			m.attempts.EXPECT().Get(gomock.Any(), config.ID, userID).Return(entity.ExamAttempt{}, repo.ErrNotFound)
+
+			_, _, err := uc.StartAttempt(context.Background(), userID, config.ID)
+			require.ErrorIs(t, err, exam.ErrNotOpen, shift)
+		}
+	})
+}
+
+func TestSaveExamAnswer(t *testing.T) {
+	t.Parallel()
+
+	userID, examID := uuid.New(), uuid.New()
+	open := entity.ExamAttempt{ID: uuid.New(), Status: entity.ExamAttemptInProgress, DeadlineAt: time.Now().Add(time.Hour)}
+
+	t.Run("saves and clears", func(t *testing.T) {
+		t.Parallel()
+
+		uc, m := attemptUseCase(t)
+		question := entity.ExamAttemptQuestion{ID: uuid.New(), Question: singleChoice(3)}
+
+		m.attempts.EXPECT().Get(gomock.Any(), examID, userID).Return(open, nil).Times(2)
+		m.attempts.EXPECT().GetQuestion(gomock.Any(), open.ID, question.ID).Return(question, nil).Times(2)
+		gomock.InOrder(
+			m.attempts.EXPECT().SaveAnswer(gomock.Any(), open.ID, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, _ uuid.UUID, q entity.ExamAttemptQuestion, _ time.Time) (entity.ExamAttemptQuestion, error) {
+					require.Equal(t, []int{2}, q.SelectedOptions)
+					require.NotNil(t, q.AnsweredAt)
+
+					return q, nil
+				}),
+			m.attempts.EXPECT().SaveAnswer(gomock.Any(), open.ID, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, _ uuid.UUID, q entity.ExamAttemptQuestion, _ time.Time) (entity.ExamAttemptQuestion, error) {
+					require.Empty(t, q.SelectedOptions)
+					require.Nil(t, q.AnsweredAt)
+
+					return q, nil
+				}),
+		)
+
+		saved, err := uc.SaveAnswer(context.Background(), userID, examID, entity.ExamAnswerRequest{
+			AttemptQuestionID: question.ID,
+			SelectedOptions:   []int{2},
+		})
+		require.NoError(t, err)
+		require.Zero(t, saved.Question.CorrectOption)
+
+		_, err = uc.SaveAnswer(context.Background(), userID, examID, entity.ExamAnswerRequest{AttemptQuestionID: question.ID})
+		require.NoError(t, err)
+	})
+
+	t.Run("refused after the deadline", func(t *testing.T) {
+		t.Parallel()
+
+		uc, m := attemptUseCase(t)
+		overdue := open
+		overdue.DeadlineAt = time.Now().Add(-time.Second)
+		m.attempts.EXPECT().Get(gomock.Any(), examID, userID).Return(overdue, nil)
+
+		_, err := uc.SaveAnswer(context.Background(), userID, examID, entity.ExamAnswerRequest{
+			AttemptQuestionID: uuid.New(),
+			SelectedOptions:   []int{1},
+		})
+		require.ErrorIs(t, err, exam.ErrTimeUp)
+	})
+
+	t.Run("deadline passing while saving", func(t *testing.T) {
+		t.Parallel()
+
+		uc, m := attemptUseCase(t)
+		question := entity.ExamAttemptQuestion{ID: uuid.New(), Question: singleChoice(3)}
+		m.attempts.EXPECT().Get(gomock.Any(), examID, userID).Return(open, nil).Times(2)
+		m.attempts.EXPECT().GetQuestion(gomock.Any(), open.ID, question.ID).Return(question, nil)
+		m.attempts.EXPECT().SaveAnswer(gomock.Any(), open.ID, gomock.Any(), gomock.Any()).
+			Return(entity.ExamAttemptQuestion{}, repo.ErrConflict)
+
+		_, err := uc.SaveAnswer(context.Background(), userID, examID, entity.ExamAnswerRequest{
+			AttemptQuestionID: question.ID,
+			SelectedOptions:   []int{1},
+		})
+		require.ErrorIs(t, err, exam.ErrTimeUp)
+	})
+
+	t.Run("answer must fit the question", func(t *testing.T) {
+		t.Parallel()
+
+		uc, m := attemptUseCase(t)
+		question := entity.ExamAttemptQuestion{ID: uuid.New(), Question: singleChoice(3)}
+		m.attempts.EXPECT().Get(gomock.Any(), examID, userID).Return(open, nil)
+		m.attempts.EXPECT().GetQuestion(gomock.Any(), open.ID, question.ID).Return(question, nil)
+
+		_, err := uc.SaveAnswer(context.Background(), userID, examID, entity.ExamAnswerRequest{
+			AttemptQuestionID: question.ID,
+			SelectedOptions:   []int{1, 2},
+		})
+		require.ErrorIs(t, err, exam.ErrInvalidAnswer)
+	})
+}
+
+func TestSubmitExamAttempt(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	answeredAt := time.Now().Add(-time.Minute)
+
+	// Right, wrong, skipped and half-right multi-select answers.
+	paper := func() []entity.ExamAttemptQuestion {
+		multi := entity.Question{ID: uuid.New(), ChoiceType: entity.ChoiceTypeMulti, CorrectOptions: []int{1, 3}}
+
+		return []entity.ExamAttemptQuestion{
+			{ID: uuid.New(), Question: singleChoice(1), SelectedOptions: []int{1}, AnsweredAt: &answeredAt},
+			{ID: uuid.New(), Question: singleChoice(1), SelectedOptions: []int{2}, AnsweredAt: &answeredAt},
+			{ID: uuid.New(), Question: singleChoice(1)},
+			{ID: uuid.New(), Question: multi, SelectedOptions: []int{3}, AnsweredAt: &answeredAt},
+		}
+	}
+
+	t.Run("grades with negative marking", func(t *testing.T) {
+		t.Parallel()
+
+		uc, m := attemptUseCase(t)
+		config := liveExam()
+		attempt := entity.ExamAttempt{ID: uuid.New(), ExamID: config.ID, UserID: userID,
+			Status: entity.ExamAttemptInProgress, DeadlineAt: time.Now().Add(time.Hour)}
+
+		m.exams.EXPECT().GetConfig(gomock.Any(), config.ID).Return(config, nil)
+		m.attempts.EXPECT().Get(gomock.Any(), config.ID, userID).Return(attempt, nil)
+		m.attempts.EXPECT().Close(gomock.Any(), attempt.ID, entity.ExamAttemptSubmitted, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ uuid.UUID, status entity.ExamAttemptStatus, _ time.Time) (entity.ExamAttempt, error) {
+				attempt.Status = status
+
+				return attempt, nil
+			})
+		m.attempts.EXPECT().ListQuestions(gomock.Any(), attempt.ID).Return(paper(), nil).Times(2)
+		m.exams.EXPECT().GetCategory(gomock.Any(), config.Exam).
+			Return(entity.ExamCategorySettings{MultiSelectScoring: entity.MultiSelectPartial}, nil)
+		m.attempts.EXPECT().Grade(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, graded entity.ExamAttempt, questions []entity.ExamAttemptQuestion) (entity.ExamAttempt, error) {
+				require.Equal(t, 1, graded.Correct)
+				require.Equal(t, 2, graded.Incorrect)
+				require.Equal(t, 1, graded.Skipped)
+
+				marks := make([]float64, len(questions))
+				for i, q := range questions {
+					marks[i] = *q.Marks
+				}
+				require.Equal(t, []float64{4, -1, 0, 2}, marks)
+
+				return graded, nil
+			})
+
+		detail, err := uc.SubmitAttempt(context.Background(), userID, config.ID)
+		require.NoError(t, err)
+		require.Equal(t, 5.0, *detail.Attempt.Score)
+
+		// The window is still open, so marks and keys stay hidden.
+		require.Nil(t, detail.Questions[0].Marks)
+		require.Zero(t, detail.Questions[0].Question.CorrectOption)
+	})
+
+	t.Run("late submit times out at the deadline", func(t *testing.T) {
+		t.Parallel()
+
+		uc, m := attemptUseCase(t)
+		config := liveExam()
+		attempt := entity.ExamAttempt{ID: uuid.New(), ExamID: config.ID, UserID: userID,
+			Status: entity.ExamAttemptInProgress, DeadlineAt: time.Now().Add(-time.Minute)}
+
+		m.exams.EXPECT().GetConfig(gomock.Any(), config.ID).Return(config, nil)
+		m.attempts.EXPECT().Get(gomock.Any(), config.ID, userID).Return(attempt, nil)
+		m.attempts.EXPECT().Close(gomock.Any(), attempt.ID, entity.ExamAttemptTimedOut, attempt.DeadlineAt).
+			Return(attempt, nil)
+		m.attempts.EXPECT().ListQuestions(gomock.Any(), attempt.ID).Return(nil, nil).Times(2)
+		m.exams.EXPECT().GetCategory(gomock.Any(), config.Exam).Return(entity.ExamCategorySettings{}, nil)
+		m.attempts.EXPECT().Grade(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, graded entity.ExamAttempt, _ []entity.ExamAttemptQuestion) (entity.ExamAttempt, error) {
+				return graded, nil
+			})
+
+		_, err := uc.SubmitAttempt(context.Background(), userID, config.ID)
+		require.NoError(t, err)
+	})
+
+	t.Run("only once", func(t *testing.T) {
+		t.Parallel()
+
+		uc, m := attemptUseCase(t)
+		config := liveExam()
+		m.exams.EXPECT().GetConfig(gomock.Any(), config.ID).Return(config, nil)
+		m.attempts.EXPECT().Get(gomock.Any(), config.ID, userID).
+			Return(entity.ExamAttempt{Status: entity.ExamAttemptSubmitted}, nil)
+
+		_, err := uc.SubmitAttempt(context.Background(), userID, config.ID)
+		require.ErrorIs(t, err, exam.ErrAttemptClosed)
+	})
+}
+
+func TestGradeExpiredExamAttempts(t *testing.T) {
+	t.Parallel()
+
+	uc, m := attemptUseCase(t)
+	config := liveExam()
+	overdue := entity.ExamAttempt{ID: uuid.New(), ExamID: config.ID, Status: entity.ExamAttemptInProgress,
+		DeadlineAt: time.Now().Add(-time.Minute)}
+	ungraded := entity.ExamAttempt{ID: uuid.New(), ExamID: config.ID, Status: entity.ExamAttemptSubmitted}
+	submitted := entity.ExamAttempt{ID: uuid.New(), ExamID: config.ID, Status: entity.ExamAttemptInProgress,
+		DeadlineAt: time.Now().Add(-time.Minute)}
+
+	m.attempts.EXPECT().ListUngraded(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return([]entity.ExamAttempt{overdue, ungraded, submitted}, nil)
+	m.exams.EXPECT().GetConfig(gomock.Any(), config.ID).Return(config, nil)
+	m.attempts.EXPECT().Close(gomock.Any(), overdue.ID, entity.ExamAttemptTimedOut, overdue.DeadlineAt).Return(overdue, nil)
+	// Submitted after it was listed; the submit grades it.
+	m.attempts.EXPECT().Close(gomock.Any(), submitted.ID, entity.ExamAttemptTimedOut, submitted.DeadlineAt).
+		Return(entity.ExamAttempt{}, repo.ErrNotFound)
+	m.attempts.EXPECT().ListQuestions(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+	m.exams.EXPECT().GetCategory(gomock.Any(), config.Exam).Return(entity.ExamCategorySettings{}, nil).Times(2)
+	m.attempts.EXPECT().Grade(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, graded entity.ExamAttempt, _ []entity.ExamAttemptQuestion) (entity.ExamAttempt, error) {
+			return graded, nil
+		}).Times(2)
+
+	graded, err := uc.GradeExpired(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, graded)
+}
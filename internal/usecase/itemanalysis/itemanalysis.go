@@ -0,0 +1,295 @@
+package itemanalysis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/repo"
+)
+
+const (
+	// DefaultMinAttempts is how many first attempts a question needs before
+	// it is flagged or recalibrated.
+	DefaultMinAttempts = 30
+	// _batchSize is how many questions Recalibrate analyzes per save.
+	_batchSize = 200
+
+	_defaultPageSize = 20
+	_maxPageSize     = 100
+)
+
+// Flag thresholds, from common classical test theory practice.
+const (
+	_tooEasyPValue        = 0.9
+	_tooHardPValue        = 0.2
+	_lowDiscrimination    = 0.15
+	_attractiveDistractor = 0.1
+	_functionalDistractor = 0.05
+)
+
+var (
+	// ErrQuestionNotFound when the question does not exist.
+	ErrQuestionNotFound = errors.New("question not found")
+	// ErrNotAnalyzed when the question has not been analyzed yet.
+	ErrNotAnalyzed = errors.New("question has not been analyzed yet")
+)
+
+// Options tune item analysis.
+type Options struct {
+	// MinAttempts is how many first attempts a question needs before it is
+	// flagged or recalibrated; DefaultMinAttempts when 0.
+	MinAttempts int
+}
+
+// UseCase analyzes how learners answer questions and calibrates question
+// difficulty from it.
+type UseCase struct {
+	repo      repo.ItemAnalysisRepository
+	questions repo.QuestionRepository
+	opts      Options
+}
+
+// New constructs UseCase.
+func New(repo repo.ItemAnalysisRepository, questions repo.QuestionRepository, opts Options) *UseCase {
+	if opts.MinAttempts <= 0 {
+		opts.MinAttempts = DefaultMinAttempts
+	}
+
+	return &UseCase{repo: repo, questions: questions, opts: opts}
+}
+
+// Recalibrate analyzes every attempted question and moves the difficulty of
+// those not locked by an admin to the level their p-value suggests. It
+// returns how many questions it analyzed and how many changed level.
+// Learner ability is computed once up front, then questions are paged.
+func (uc *UseCase) Recalibrate(ctx context.Context) (analyzed, recalibrated int, err error) {
+	if err := uc.repo.RefreshLearners(ctx); err != nil {
+		return 0, 0, fmt.Errorf("itemanalysis - RefreshLearners: %w", err)
+	}
+
+	after := uuid.Nil
+	for ctx.Err() == nil {
+		stats, err := uc.repo.ListAttemptStats(ctx, after, _batchSize)
+		if err != nil {
+			return analyzed, recalibrated, fmt.Errorf("itemanalysis - ListAttemptStats: %w", err)
+		}
+		if len(stats) == 0 {
+			break
+		}
+
+		now := time.Now()
+		analyses := make([]entity.ItemAnalysis, len(stats))
+		for i, s := range stats {
+			analyses[i] = uc.analyze(s, now)
+		}
+
+		changed, err := uc.repo.Save(ctx, analyses)
+		if err != nil {
+			return analyzed, recalibrated, fmt.Errorf("itemanalysis - Save: %w", err)
+		}
+		analyzed += len(stats)
+		recalibrated += changed
+
+		if len(stats) < _batchSize {
+			break
+		}
+		after = stats[len(stats)-1].QuestionID
+	}
+
+	return analyzed, recalibrated, nil
+}
+
+// List pages through the stored analyses, most flagged first.
+func (uc *UseCase) List(ctx context.Context, filter repo.ItemAnalysisFilter) (entity.ItemAnalysisList, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 || filter.PageSize > _maxPageSize {
+		filter.PageSize = _defaultPageSize
+	}
+
+	items, total, err := uc.repo.List(ctx, filter)
+	if err != nil {
+		return entity.ItemAnalysisList{}, fmt.Errorf("itemanalysis - List: %w", err)
+	}
+
+	return entity.ItemAnalysisList{
+		Items: items,
+		Meta:  entity.AdminUsersMeta{Page: filter.Page, PageSize: filter.PageSize, Total: total},
+	}, nil
+}
+
+// Get returns the stored analysis of a question.
+func (uc *UseCase) Get(ctx context.Context, questionID uuid.UUID) (entity.ItemAnalysis, error) {
+	analysis, err := uc.repo.Get(ctx, questionID)
+	if errors.Is(err, repo.ErrNotFound) {
+		return entity.ItemAnalysis{}, ErrNotAnalyzed
+	}
+	if err != nil {
+		return entity.ItemAnalysis{}, fmt.Errorf("itemanalysis - Get: %w", err)
+	}
+
+	return analysis, nil
+}
+
+// Override sets the difficulty of a question by hand. A locked question
+// keeps its level through recalibration until it is unlocked again; the
+// level is left as is when none is given.
+func (uc *UseCase) Override(ctx context.Context, questionID uuid.UUID, req entity.QuestionDifficultyRequest) (entity.Question, error) {
+	question, err := uc.questions.SetDifficulty(ctx, questionID, req.DifficultyLevel, req.Locked)
+	if errors.Is(err, repo.ErrNotFound) {
+		return entity.Question{}, ErrQuestionNotFound
+	}
+	if err != nil {
+		return entity.Question{}, fmt.Errorf("itemanalysis - Questions.SetDifficulty: %w", err)
+	}
+
+	return question, nil
+}
+
+// analyze turns attempt statistics into an item analysis. Flags and the
+// calibrated level need MinAttempts, so a handful of lucky guesses does not
+// move a question.
+func (uc *UseCase) analyze(s repo.ItemAttemptStats, now time.Time) entity.ItemAnalysis {
+	a := entity.ItemAnalysis{
+		QuestionID:     s.QuestionID,
+		ChoiceType:     s.ChoiceType,
+		Attempts:       s.Attempts,
+		Discrimination: s.Discrimination,
+		Flags:          []entity.ItemFlag{},
+		ComputedAt:     now,
+	}
+	if s.Attempts > 0 {
+		a.PValue = float64(s.Correct) / float64(s.Attempts)
+	}
+	if s.MedianTimeMs != nil {
+		ms := int(math.Round(*s.MedianTimeMs))
+		a.MedianTimeMs = &ms
+	}
+	if hasOptions(s.ChoiceType) {
+		a.Options = options(s)
+	}
+
+	if s.Attempts < uc.opts.MinAttempts {
+		return a
+	}
+
+	level := calibratedLevel(a.PValue)
+	a.CalibratedLevel = &level
+	a.Flags = flags(a)
+
+	return a
+}
+
+// hasOptions reports whether answers to the choice type pick among the four
+// options.
+func hasOptions(choiceType entity.QuestionChoiceType) bool {
+	switch choiceType {
+	case entity.ChoiceTypeSingle, entity.ChoiceTypeMulti, entity.ChoiceTypeAssertionReason:
+		return true
+	default:
+		return false
+	}
+}
+
+// options computes how often each of the four options was chosen and the
+// point-biserial correlation of choosing it with ability.
+func options(s repo.ItemAttemptStats) []entity.OptionAnalysis {
+	analyses := make([]entity.OptionAnalysis, 4)
+	for i := range analyses {
+		analyses[i] = entity.OptionAnalysis{Option: i + 1, IsKey: slices.Contains(s.CorrectOptions, i+1)}
+	}
+
+	for _, o := range s.Options {
+		if o.Option < 1 || o.Option > len(analyses) || s.Attempts == 0 {
+			continue
+		}
+
+		rate := float64(o.Count) / float64(s.Attempts)
+		analyses[o.Option-1].Rate = rate
+		analyses[o.Option-1].Discrimination = pointBiserial(o.AbilityMean, s.AbilityMean, s.AbilitySD, rate)
+	}
+
+	return analyses
+}
+
+// pointBiserial correlates a dichotomous choice made by share p of learners,
+// whose mean ability is chosenMean, with ability. It is nil when either
+// does not vary.
+func pointBiserial(chosenMean, mean, sd, p float64) *float64 {
+	if sd == 0 || p <= 0 || p >= 1 {
+		return nil
+	}
+
+	r := (chosenMean - mean) / sd * math.Sqrt(p/(1-p))
+
+	return &r
+}
+
+// calibratedLevel maps the share of correct answers to a difficulty level
+// from 1, easiest, to 5.
+func calibratedLevel(pValue float64) int {
+	switch {
+	case pValue >= 0.85:
+		return 1
+	case pValue >= 0.7:
+		return 2
+	case pValue >= 0.5:
+		return 3
+	case pValue >= 0.3:
+		return 4
+	default:
+		return 5
+	}
+}
+
+func flags(a entity.ItemAnalysis) []entity.ItemFlag {
+	flags := []entity.ItemFlag{}
+
+	switch {
+	case a.PValue >= _tooEasyPValue:
+		flags = append(flags, entity.ItemFlagTooEasy)
+	case a.PValue < _tooHardPValue:
+		flags = append(flags, entity.ItemFlagTooHard)
+	}
+
+	if a.Discrimination != nil {
+		switch {
+		case *a.Discrimination < 0:
+			flags = append(flags, entity.ItemFlagNegativeDiscrimination)
+		case *a.Discrimination < _lowDiscrimination:
+			flags = append(flags, entity.ItemFlagLowDiscrimination)
+		}
+	}
+
+	// Stronger learners preferring a distractor to the key suggests the key
+	// is wrong.
+	wrongKey, nonFunctional := false, false
+	for _, o := range a.Options {
+		if o.IsKey {
+			continue
+		}
+		if a.Discrimination != nil && *a.Discrimination < 0 &&
+			o.Discrimination != nil && *o.Discrimination > _attractiveDistractor {
+			wrongKey = true
+		}
+		if o.Rate < _functionalDistractor {
+			nonFunctional = true
+		}
+	}
+	if wrongKey {
+		flags = append(flags, entity.ItemFlagPossibleWrongKey)
+	}
+	if nonFunctional {
+		flags = append(flags, entity.ItemFlagNonFunctionalDistractor)
+	}
+
+	return flags
+}
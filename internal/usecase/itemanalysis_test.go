@@ -0,0 +1,203 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/repo"
+	"github.com/evrone/go-clean-template/internal/usecase/itemanalysis"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type itemAnalysisMocks struct {
+	items     *MockItemAnalysisRepository
+	questions *MockQuestionRepository
+}
+
+func itemAnalysisUseCase(t *testing.T, opts itemanalysis.Options) (*itemanalysis.UseCase, itemAnalysisMocks) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+
+	m := itemAnalysisMocks{
+		items:     NewMockItemAnalysisRepository(mockCtl),
+		questions: NewMockQuestionRepository(mockCtl),
+	}
+
+	return itemanalysis.New(m.items, m.questions, opts), m
+}
+
+func floatPtr(v float64) *float64 { return &v }
+
+// recalibrate analyzes a single question and returns its analysis.
+func recalibrate(t *testing.T, opts itemanalysis.Options, stats repo.ItemAttemptStats) entity.ItemAnalysis {
+	t.Helper()
+
+	uc, m := itemAnalysisUseCase(t, opts)
+
+	var saved []entity.ItemAnalysis
+	m.items.EXPECT().RefreshLearners(gomock.Any()).Return(nil)
+	m.items.EXPECT().ListAttemptStats(gomock.Any(), uuid.Nil, gomock.Any()).Return([]repo.ItemAttemptStats{stats}, nil)
+	m.items.EXPECT().Save(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, analyses []entity.ItemAnalysis) (int, error) {
+			saved = analyses
+
+			return 1, nil
+		})
+
+	analyzed, recalibrated, err := uc.Recalibrate(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, analyzed)
+	require.Equal(t, 1, recalibrated)
+	require.Len(t, saved, 1)
+
+	return saved[0]
+}
+
+func TestItemAnalysis(t *testing.T) {
+	t.Parallel()
+
+	t.Run("healthy item", func(t *testing.T) {
+		t.Parallel()
+
+		a := recalibrate(t, itemanalysis.Options{MinAttempts: 10}, repo.ItemAttemptStats{
+			QuestionID:     uuid.New(),
+			ChoiceType:     entity.ChoiceTypeSingle,
+			CorrectOptions: []int{2},
+			Attempts:       100,
+			Correct:        60,
+			Discrimination: floatPtr(0.4),
+			MedianTimeMs:   floatPtr(41250.5),
+			AbilityMean:    0.6,
+			AbilitySD:      0.2,
+			Options: []repo.OptionAttemptStats{
+				{Option: 1, Count: 15, AbilityMean: 0.5},
+				{Option: 2, Count: 60, AbilityMean: 0.7},
+				{Option: 3, Count: 15, AbilityMean: 0.45},
+				{Option: 4, Count: 10, AbilityMean: 0.4},
+			},
+		})
+
+		require.InDelta(t, 0.6, a.PValue, 1e-9)
+		require.Equal(t, 41251, *a.MedianTimeMs)
+		require.Equal(t, 3, *a.CalibratedLevel)
+		require.Empty(t, a.Flags)
+		require.Len(t, a.Options, 4)
+		require.True(t, a.Options[1].IsKey)
+		require.InDelta(t, 0.15, a.Options[0].Rate, 1e-9)
+		// (0.7 - 0.6) / 0.2 * sqrt(0.6 / 0.4)
+		require.InDelta(t, 0.612, *a.Options[1].Discrimination, 1e-3)
+		require.Negative(t, *a.Options[3].Discrimination)
+	})
+
+	t.Run("flags a probably wrong key", func(t *testing.T) {
+		t.Parallel()
+
+		a := recalibrate(t, itemanalysis.Options{MinAttempts: 10}, repo.ItemAttemptStats{
+			QuestionID:     uuid.New(),
+			ChoiceType:     entity.ChoiceTypeSingle,
+			CorrectOptions: []int{1},
+			Attempts:       50,
+			Correct:        10,
+			Discrimination: floatPtr(-0.3),
+			AbilityMean:    0.6,
+			AbilitySD:      0.2,
+			Options: []repo.OptionAttemptStats{
+				{Option: 1, Count: 10, AbilityMean: 0.45},
+				{Option: 2, Count: 35, AbilityMean: 0.68},
+				{Option: 3, Count: 5, AbilityMean: 0.4},
+			},
+		})
+
+		require.Equal(t, 5, *a.CalibratedLevel)
+		require.ElementsMatch(t, []entity.ItemFlag{
+			entity.ItemFlagNegativeDiscrimination,
+			entity.ItemFlagPossibleWrongKey,
+			entity.ItemFlagNonFunctionalDistractor,
+		}, a.Flags)
+		require.Nil(t, a.Options[3].Discrimination)
+	})
+
+	t.Run("too easy and not discriminating", func(t *testing.T) {
+		t.Parallel()
+
+		a := recalibrate(t, itemanalysis.Options{MinAttempts: 10}, repo.ItemAttemptStats{
+			QuestionID:     uuid.New(),
+			ChoiceType:     entity.ChoiceTypeNumerical,
+			Attempts:       40,
+			Correct:        38,
+			Discrimination: floatPtr(0.05),
+		})
+
+		require.Equal(t, 1, *a.CalibratedLevel)
+		require.Empty(t, a.Options)
+		require.ElementsMatch(t, []entity.ItemFlag{entity.ItemFlagTooEasy, entity.ItemFlagLowDiscrimination}, a.Flags)
+	})
+
+	t.Run("too few attempts are not judged", func(t *testing.T) {
+		t.Parallel()
+
+		a := recalibrate(t, itemanalysis.Options{}, repo.ItemAttemptStats{
+			QuestionID: uuid.New(),
+			ChoiceType: entity.ChoiceTypeSingle,
+			Attempts:   itemanalysis.DefaultMinAttempts - 1,
+			Correct:    1,
+		})
+
+		require.Nil(t, a.CalibratedLevel)
+		require.Empty(t, a.Flags)
+	})
+}
+
+func TestRecalibratePages(t *testing.T) {
+	t.Parallel()
+
+	uc, m := itemAnalysisUseCase(t, itemanalysis.Options{})
+
+	page := make([]repo.ItemAttemptStats, 200)
+	for i := range page {
+		page[i] = repo.ItemAttemptStats{QuestionID: uuid.New(), Attempts: 1}
+	}
+	last := page[len(page)-1].QuestionID
+
+	// Learners are scored once, before the first page.
+	gomock.InOrder(
+		m.items.EXPECT().RefreshLearners(gomock.Any()).Return(nil),
+		m.items.EXPECT().ListAttemptStats(gomock.Any(), uuid.Nil, 200).Return(page, nil),
+		m.items.EXPECT().Save(gomock.Any(), gomock.Len(200)).Return(3, nil),
+		m.items.EXPECT().ListAttemptStats(gomock.Any(), last, 200).Return(nil, nil),
+	)
+
+	analyzed, recalibrated, err := uc.Recalibrate(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 200, analyzed)
+	require.Equal(t, 3, recalibrated)
+}
+
+func TestOverrideDifficulty(t *testing.T) {
+	t.Parallel()
+
+	uc, m := itemAnalysisUseCase(t, itemanalysis.Options{})
+	id := uuid.New()
+	level := 4
+
+	m.questions.EXPECT().SetDifficulty(gomock.Any(), id, &level, true).
+		Return(entity.Question{ID: id, DifficultyLevel: 4, DifficultyLocked: true}, nil)
+
+	question, err := uc.Override(context.Background(), id, entity.QuestionDifficultyRequest{DifficultyLevel: &level, Locked: true})
+	require.NoError(t, err)
+	require.True(t, question.DifficultyLocked)
+
+	m.questions.EXPECT().SetDifficulty(gomock.Any(), id, nil, false).Return(entity.Question{}, repo.ErrNotFound)
+
+	_, err = uc.Override(context.Background(), id, entity.QuestionDifficultyRequest{})
+	require.ErrorIs(t, err, itemanalysis.ErrQuestionNotFound)
+
+	m.items.EXPECT().Get(gomock.Any(), id).Return(entity.ItemAnalysis{}, repo.ErrNotFound)
+
+	_, err = uc.Get(context.Background(), id)
+	require.ErrorIs(t, err, itemanalysis.ErrNotAnalyzed)
+}
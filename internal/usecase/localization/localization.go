@@ -0,0 +1,380 @@
+package localization
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/repo"
+)
+
+const (
+	_defaultPageSize  = 50
+	_maxPageSize      = 200
+	_defaultBatchSize = 50
+)
+
+var (
+	// ErrNotFound when the question has no translation into the language.
+	ErrNotFound = errors.New("translation not found")
+	// ErrUnsupportedLanguage when the language is not one of Options.Languages.
+	ErrUnsupportedLanguage = errors.New("unsupported language")
+	// ErrUserNotFound when setting the language of an unknown user.
+	ErrUserNotFound = errors.New("user not found")
+)
+
+// Options configures UseCase. Languages are the codes questions are
+// translated into, e.g. "hi"; BatchSize caps the questions translated into
+// each language per run.
+type Options struct {
+	Languages []string
+	BatchSize int
+}
+
+// UseCase translates the question bank, lets editors correct translations and
+// serves learners questions in their preferred language.
+type UseCase struct {
+	repo       repo.LocalizationRepository
+	translator repo.TranslationWebAPI
+	opts       Options
+}
+
+// New constructs UseCase.
+func New(repo repo.LocalizationRepository, translator repo.TranslationWebAPI, opts Options) *UseCase {
+	if opts.BatchSize < 1 {
+		opts.BatchSize = _defaultBatchSize
+	}
+
+	return &UseCase{repo: repo, translator: translator, opts: opts}
+}
+
+// Translate machine-translates a batch of untranslated or stale questions
+// into every language and returns how many translations it stored. A stale
+// translation is replaced even if it was reviewed, since the English it was
+// corrected against has changed; it goes back to the review queue. Questions
+// that fail are skipped and retried on the next run.
+func (uc *UseCase) Translate(ctx context.Context) (int, error) {
+	var (
+		saved int
+		errs  []error
+	)
+
+	for _, language := range uc.opts.Languages {
+		questions, err := uc.repo.ListPending(ctx, language, uc.opts.BatchSize)
+		if err != nil {
+			return saved, fmt.Errorf("localization - ListPending: %w", err)
+		}
+
+		for i := range questions {
+			if err := ctx.Err(); err != nil {
+				return saved, err
+			}
+
+			localization, err := uc.translate(questions[i], language)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("localization - translate %s into %s: %w", questions[i].ID, language, err))
+				continue
+			}
+
+			if err := uc.repo.Save(ctx, localization); err != nil {
+				return saved, fmt.Errorf("localization - Save: %w", err)
+			}
+			saved++
+		}
+	}
+
+	return saved, errors.Join(errs...)
+}
+
+// translate translates the learner-facing text of q field by field.
+func (uc *UseCase) translate(q entity.Question, language string) (entity.QuestionLocalization, error) {
+	l := entity.QuestionLocalization{
+		QuestionID:    q.ID,
+		Language:      language,
+		SourceVersion: q.Version,
+		Status:        entity.LocalizationStatusMachine,
+	}
+
+	text := func(original string) (string, error) {
+		if original == "" {
+			return "", nil
+		}
+
+		result, err := uc.translator.Translate(entity.Translation{
+			Source:      entity.LanguageEnglish,
+			Destination: language,
+			Original:    original,
+		})
+		if err != nil {
+			return "", err
+		}
+
+		return result.Translation, nil
+	}
+
+	optional := func(original *string) (*string, error) {
+		if original == nil {
+			return nil, nil
+		}
+
+		translated, err := text(*original)
+		if err != nil {
+			return nil, err
+		}
+
+		return &translated, nil
+	}
+
+	all := func(originals []string) ([]string, error) {
+		if originals == nil {
+			return nil, nil
+		}
+
+		translated := make([]string, len(originals))
+		for i, original := range originals {
+			var err error
+			if translated[i], err = text(original); err != nil {
+				return nil, err
+			}
+		}
+
+		return translated, nil
+	}
+
+	var err error
+	for _, field := range []struct {
+		dst *string
+		src string
+	}{
+		{&l.QuestionText, q.QuestionText},
+		{&l.OptionA, q.OptionA},
+		{&l.OptionB, q.OptionB},
+		{&l.OptionC, q.OptionC},
+		{&l.OptionD, q.OptionD},
+	} {
+		if *field.dst, err = text(field.src); err != nil {
+			return entity.QuestionLocalization{}, err
+		}
+	}
+
+	if l.ReasonText, err = optional(q.ReasonText); err != nil {
+		return entity.QuestionLocalization{}, err
+	}
+	if l.Explanation, err = optional(q.Explanation); err != nil {
+		return entity.QuestionLocalization{}, err
+	}
+
+	if q.Matrix != nil {
+		if l.MatrixRows, err = all(q.Matrix.Rows); err != nil {
+			return entity.QuestionLocalization{}, err
+		}
+		if l.MatrixColumns, err = all(q.Matrix.Columns); err != nil {
+			return entity.QuestionLocalization{}, err
+		}
+	}
+
+	return l, nil
+}
+
+// List pages through the translation review queue.
+func (uc *UseCase) List(ctx context.Context, filter repo.LocalizationFilter) (entity.QuestionLocalizationList, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 || filter.PageSize > _maxPageSize {
+		filter.PageSize = _defaultPageSize
+	}
+
+	items, total, err := uc.repo.List(ctx, filter)
+	if err != nil {
+		return entity.QuestionLocalizationList{}, fmt.Errorf("localization - List: %w", err)
+	}
+
+	return entity.QuestionLocalizationList{
+		Items: items,
+		Meta:  entity.AdminUsersMeta{Page: filter.Page, PageSize: filter.PageSize, Total: total},
+	}, nil
+}
+
+// Get returns the translation of a question into language.
+func (uc *UseCase) Get(ctx context.Context, questionID uuid.UUID, language string) (entity.QuestionLocalization, error) {
+	l, err := uc.repo.Get(ctx, questionID, language)
+	if errors.Is(err, repo.ErrNotFound) {
+		return entity.QuestionLocalization{}, ErrNotFound
+	}
+	if err != nil {
+		return entity.QuestionLocalization{}, fmt.Errorf("localization - Get: %w", err)
+	}
+
+	return l, nil
+}
+
+// Correct applies an editor's corrections to a translation and marks it
+// reviewed. A stale translation stays stale: correcting it does not make it
+// match the current English.
+func (uc *UseCase) Correct(
+	ctx context.Context,
+	questionID uuid.UUID,
+	language string,
+	reviewerID uuid.UUID,
+	req entity.QuestionLocalizationUpdateRequest,
+) (entity.QuestionLocalization, error) {
+	l, err := uc.Get(ctx, questionID, language)
+	if err != nil {
+		return entity.QuestionLocalization{}, err
+	}
+
+	for _, field := range []struct {
+		dst *string
+		src *string
+	}{
+		{&l.QuestionText, req.QuestionText},
+		{&l.OptionA, req.OptionA},
+		{&l.OptionB, req.OptionB},
+		{&l.OptionC, req.OptionC},
+		{&l.OptionD, req.OptionD},
+	} {
+		if field.src != nil {
+			*field.dst = *field.src
+		}
+	}
+	if req.ReasonText != nil {
+		l.ReasonText = req.ReasonText
+	}
+	if req.Explanation != nil {
+		l.Explanation = req.Explanation
+	}
+	if req.MatrixRows != nil {
+		l.MatrixRows = req.MatrixRows
+	}
+	if req.MatrixColumns != nil {
+		l.MatrixColumns = req.MatrixColumns
+	}
+
+	now := time.Now().UTC()
+	l.Status = entity.LocalizationStatusReviewed
+	l.ReviewerID = &reviewerID
+	l.ReviewedAt = &now
+	l.UpdatedAt = now
+
+	if err := uc.repo.Save(ctx, l); err != nil {
+		return entity.QuestionLocalization{}, fmt.Errorf("localization - Save: %w", err)
+	}
+
+	return l, nil
+}
+
+// Supported reports whether questions are served in language.
+func (uc *UseCase) Supported(language string) bool {
+	return language == entity.LanguageEnglish || slices.Contains(uc.opts.Languages, language)
+}
+
+// Preference returns the learner's language and the available ones. A
+// language that is no longer offered falls back to English.
+func (uc *UseCase) Preference(ctx context.Context, userID uuid.UUID) (entity.LanguagePreference, error) {
+	language, err := uc.language(ctx, userID)
+	if err != nil {
+		return entity.LanguagePreference{}, err
+	}
+
+	return entity.LanguagePreference{Language: language, Available: uc.available()}, nil
+}
+
+// SetPreference stores the language the learner is served questions in.
+func (uc *UseCase) SetPreference(ctx context.Context, userID uuid.UUID, language string) (entity.LanguagePreference, error) {
+	if !uc.Supported(language) {
+		return entity.LanguagePreference{}, ErrUnsupportedLanguage
+	}
+
+	err := uc.repo.SetLanguage(ctx, userID, language)
+	if errors.Is(err, repo.ErrNotFound) {
+		return entity.LanguagePreference{}, ErrUserNotFound
+	}
+	if err != nil {
+		return entity.LanguagePreference{}, fmt.Errorf("localization - SetLanguage: %w", err)
+	}
+
+	return entity.LanguagePreference{Language: language, Available: uc.available()}, nil
+}
+
+// Localize replaces the text of questions with their translation into the
+// learner's language. A question keeps its English text when it has no
+// translation for the version it was served at.
+func (uc *UseCase) Localize(ctx context.Context, userID uuid.UUID, questions []*entity.Question) error {
+	if len(questions) == 0 {
+		return nil
+	}
+
+	language, err := uc.language(ctx, userID)
+	if err != nil || language == entity.LanguageEnglish {
+		return err
+	}
+
+	ids := make([]uuid.UUID, len(questions))
+	for i, q := range questions {
+		ids[i] = q.ID
+	}
+
+	localizations, err := uc.repo.ListForQuestions(ctx, ids, language)
+	if err != nil {
+		return fmt.Errorf("localization - ListForQuestions: %w", err)
+	}
+
+	byQuestion := make(map[uuid.UUID]entity.QuestionLocalization, len(localizations))
+	for _, l := range localizations {
+		byQuestion[l.QuestionID] = l
+	}
+
+	for _, q := range questions {
+		if l, ok := byQuestion[q.ID]; ok && l.SourceVersion == q.Version {
+			apply(q, l)
+		}
+	}
+
+	return nil
+}
+
+func (uc *UseCase) language(ctx context.Context, userID uuid.UUID) (string, error) {
+	language, err := uc.repo.GetLanguage(ctx, userID)
+	if err != nil {
+		return "", fmt.Errorf("localization - GetLanguage: %w", err)
+	}
+
+	if !uc.Supported(language) {
+		return entity.LanguageEnglish, nil
+	}
+
+	return language, nil
+}
+
+func (uc *UseCase) available() []string {
+	return append([]string{entity.LanguageEnglish}, uc.opts.Languages...)
+}
+
+// apply overlays a translation on a question. Fields the translation lacks
+// stay English.
+func apply(q *entity.Question, l entity.QuestionLocalization) {
+	q.QuestionText = l.QuestionText
+	q.OptionA = l.OptionA
+	q.OptionB = l.OptionB
+	q.OptionC = l.OptionC
+	q.OptionD = l.OptionD
+	if q.ReasonText != nil && l.ReasonText != nil {
+		q.ReasonText = l.ReasonText
+	}
+	if q.Explanation != nil && l.Explanation != nil {
+		q.Explanation = l.Explanation
+	}
+	if q.Matrix != nil && len(l.MatrixRows) == len(q.Matrix.Rows) && len(l.MatrixColumns) == len(q.Matrix.Columns) {
+		matrix := *q.Matrix
+		matrix.Rows = l.MatrixRows
+		matrix.Columns = l.MatrixColumns
+		q.Matrix = &matrix
+	}
+	q.Language = l.Language
+}
@@ -0,0 +1,163 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/repo"
+	"github.com/evrone/go-clean-template/internal/repo/webapi"
+	"github.com/evrone/go-clean-template/internal/usecase/localization"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// _hindi is an offline dictionary for the phrases used below.
+var _hindi = map[string]map[string]string{
+	"hi": {
+		"Which vitamin deficiency causes scurvy?": "किस विटामिन की कमी से स्कर्वी होता है?",
+		"vitamin":    "विटामिन",
+		"collagen":   "कोलेजन",
+		"is":         "है",
+		"needed":     "आवश्यक",
+		"for":        "के लिए",
+		"Drug":       "दवा",
+		"Mechanism":  "क्रियाविधि",
+		"Aspirin":    "एस्पिरिन",
+		"COX":        "COX",
+		"inhibition": "निषेध",
+	},
+}
+
+func localizationUseCase(t *testing.T, languages ...string) (*localization.UseCase, *MockLocalizationRepository) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+	localizations := NewMockLocalizationRepository(mockCtl)
+
+	return localization.New(localizations, webapi.NewDictionary(_hindi), localization.Options{Languages: languages}), localizations
+}
+
+func TestTranslateQuestions(t *testing.T) {
+	t.Parallel()
+
+	uc, localizations := localizationUseCase(t, "hi", "ta")
+
+	explanation := "Vitamin C is needed for collagen."
+	question := entity.Question{
+		ID:           uuid.New(),
+		QuestionText: "Which vitamin deficiency causes scurvy?",
+		OptionA:      "A",
+		OptionB:      "B",
+		OptionC:      "C",
+		OptionD:      "D",
+		Explanation:  &explanation,
+		Matrix:       &entity.MatrixMatch{Rows: []string{"Aspirin"}, Columns: []string{"COX inhibition", "Drug"}},
+		Version:      3,
+	}
+
+	localizations.EXPECT().ListPending(gomock.Any(), "hi", 50).Return([]entity.Question{question}, nil)
+	localizations.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, l entity.QuestionLocalization) error {
+		require.Equal(t, question.ID, l.QuestionID)
+		require.Equal(t, "hi", l.Language)
+		require.Equal(t, 3, l.SourceVersion)
+		require.Equal(t, entity.LocalizationStatusMachine, l.Status)
+		require.Equal(t, "किस विटामिन की कमी से स्कर्वी होता है?", l.QuestionText)
+		require.Equal(t, "विटामिन C है आवश्यक के लिए कोलेजन.", *l.Explanation)
+		require.Nil(t, l.ReasonText)
+		require.Equal(t, []string{"एस्पिरिन"}, l.MatrixRows)
+		require.Equal(t, []string{"COX निषेध", "दवा"}, l.MatrixColumns)
+
+		return nil
+	})
+	// The translator has no Tamil dictionary, so the question is skipped.
+	localizations.EXPECT().ListPending(gomock.Any(), "ta", 50).Return([]entity.Question{question}, nil)
+
+	saved, err := uc.Translate(context.Background())
+	require.Error(t, err)
+	require.Equal(t, 1, saved)
+}
+
+func TestCorrectTranslation(t *testing.T) {
+	t.Parallel()
+
+	questionID, reviewerID := uuid.New(), uuid.New()
+
+	t.Run("marks it reviewed", func(t *testing.T) {
+		t.Parallel()
+
+		uc, localizations := localizationUseCase(t, "hi")
+		machine := entity.QuestionLocalization{
+			QuestionID:    questionID,
+			Language:      "hi",
+			QuestionText:  "प्रश्न",
+			OptionA:       "ए",
+			OptionB:       "बी",
+			OptionC:       "सी",
+			OptionD:       "डी",
+			SourceVersion: 2,
+			Status:        entity.LocalizationStatusMachine,
+		}
+		fixed := "सही विकल्प"
+
+		localizations.EXPECT().Get(gomock.Any(), questionID, "hi").Return(machine, nil)
+		localizations.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+
+		corrected, err := uc.Correct(context.Background(), questionID, "hi", reviewerID, entity.QuestionLocalizationUpdateRequest{OptionB: &fixed})
+		require.NoError(t, err)
+		require.Equal(t, entity.LocalizationStatusReviewed, corrected.Status)
+		require.Equal(t, &reviewerID, corrected.ReviewerID)
+		require.NotNil(t, corrected.ReviewedAt)
+		require.Equal(t, "सही विकल्प", corrected.OptionB)
+		require.Equal(t, "प्रश्न", corrected.QuestionText)
+		require.Equal(t, 2, corrected.SourceVersion)
+	})
+
+	t.Run("untranslated question", func(t *testing.T) {
+		t.Parallel()
+
+		uc, localizations := localizationUseCase(t, "hi")
+		localizations.EXPECT().Get(gomock.Any(), questionID, "hi").Return(entity.QuestionLocalization{}, repo.ErrNotFound)
+
+		_, err := uc.Correct(context.Background(), questionID, "hi", reviewerID, entity.QuestionLocalizationUpdateRequest{})
+		require.ErrorIs(t, err, localization.ErrNotFound)
+	})
+}
+
+func TestLanguagePreference(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	t.Run("only offered languages", func(t *testing.T) {
+		t.Parallel()
+
+		uc, _ := localizationUseCase(t, "hi")
+
+		_, err := uc.SetPreference(context.Background(), userID, "ta")
+		require.ErrorIs(t, err, localization.ErrUnsupportedLanguage)
+	})
+
+	t.Run("stored", func(t *testing.T) {
+		t.Parallel()
+
+		uc, localizations := localizationUseCase(t, "hi")
+		localizations.EXPECT().SetLanguage(gomock.Any(), userID, "hi").Return(nil)
+
+		preference, err := uc.SetPreference(context.Background(), userID, "hi")
+		require.NoError(t, err)
+		require.Equal(t, entity.LanguagePreference{Language: "hi", Available: []string{"en", "hi"}}, preference)
+	})
+
+	t.Run("withdrawn language falls back to English", func(t *testing.T) {
+		t.Parallel()
+
+		uc, localizations := localizationUseCase(t, "hi")
+		localizations.EXPECT().GetLanguage(gomock.Any(), userID).Return("ta", nil)
+
+		preference, err := uc.Preference(context.Background(), userID)
+		require.NoError(t, err)
+		require.Equal(t, "en", preference.Language)
+	})
+}
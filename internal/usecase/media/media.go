@@ -0,0 +1,286 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/repo"
+	"github.com/evrone/go-clean-template/pkg/blob"
+	"github.com/evrone/go-clean-template/pkg/thumbnail"
+)
+
+const (
+	// _thumbnailSide is the longest side of generated thumbnails, in pixels.
+	_thumbnailSide = 320
+	// _maxPixels bounds the decoded size of an upload, so a small file cannot
+	// expand into gigabytes of pixels.
+	_maxPixels = 40_000_000
+)
+
+var (
+	// ErrQuestionNotFound when media is attached to a question that does not exist.
+	ErrQuestionNotFound = errors.New("question not found")
+	// ErrNotFound when the media does not exist or its link is invalid or expired.
+	ErrNotFound = errors.New("media not found")
+	// ErrInvalidSlot when the slot is not a part of a question.
+	ErrInvalidSlot = errors.New("invalid media slot")
+	// ErrUnsupportedType when the file is not a PNG, JPEG or GIF image.
+	ErrUnsupportedType = errors.New("unsupported media type")
+	// ErrTooLarge when the file or its pixel count is over the limit.
+	ErrTooLarge = errors.New("media is too large")
+)
+
+// Options bound uploads and download links.
+type Options struct {
+	// MaxBytes is the largest file accepted.
+	MaxBytes int64
+	// URLTTL is how long signed download URLs stay valid.
+	URLTTL time.Duration
+}
+
+// Upload is an image to attach to a question.
+type Upload struct {
+	QuestionID uuid.UUID
+	Slot       entity.QuestionMediaSlot
+	AltText    string
+	Data       []byte
+}
+
+// UseCase manages the images attached to questions.
+type UseCase struct {
+	repo      repo.QuestionMediaRepository
+	questions repo.QuestionRepository
+	store     blob.Store
+	opts      Options
+}
+
+// New constructs UseCase.
+func New(repo repo.QuestionMediaRepository, questions repo.QuestionRepository, store blob.Store, opts Options) *UseCase {
+	return &UseCase{repo: repo, questions: questions, store: store, opts: opts}
+}
+
+// extensions maps the accepted content types to the file extension they are
+// stored under, which is also how their type is known when served.
+func extensions() map[string]string {
+	return map[string]string{
+		"image/png":  ".png",
+		"image/jpeg": ".jpg",
+		"image/gif":  ".gif",
+	}
+}
+
+// Upload validates an image, stores it with a JPEG thumbnail and records it
+// against the question. The content type is sniffed from the data; whatever
+// the client claimed is ignored.
+func (uc *UseCase) Upload(ctx context.Context, authorID uuid.UUID, upload Upload) (entity.QuestionMedia, error) {
+	if !validSlot(upload.Slot) {
+		return entity.QuestionMedia{}, ErrInvalidSlot
+	}
+
+	if int64(len(upload.Data)) > uc.opts.MaxBytes {
+		return entity.QuestionMedia{}, ErrTooLarge
+	}
+
+	contentType := http.DetectContentType(upload.Data)
+	ext, ok := extensions()[contentType]
+	if !ok {
+		return entity.QuestionMedia{}, ErrUnsupportedType
+	}
+
+	_, width, height, err := thumbnail.Size(bytes.NewReader(upload.Data))
+	if err != nil {
+		return entity.QuestionMedia{}, ErrUnsupportedType
+	}
+	if width*height > _maxPixels {
+		return entity.QuestionMedia{}, ErrTooLarge
+	}
+
+	if _, err := uc.questions.GetByID(ctx, upload.QuestionID); err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return entity.QuestionMedia{}, ErrQuestionNotFound
+		}
+
+		return entity.QuestionMedia{}, fmt.Errorf("media - Questions.GetByID: %w", err)
+	}
+
+	thumb, err := thumbnail.JPEG(bytes.NewReader(upload.Data), _thumbnailSide)
+	if err != nil {
+		return entity.QuestionMedia{}, ErrUnsupportedType
+	}
+
+	id := uuid.New()
+	prefix := path.Join("questions", upload.QuestionID.String(), id.String())
+	media := entity.QuestionMedia{
+		ID:           id,
+		QuestionID:   upload.QuestionID,
+		Slot:         upload.Slot,
+		ContentType:  contentType,
+		SizeBytes:    int64(len(upload.Data)),
+		Width:        width,
+		Height:       height,
+		AltText:      upload.AltText,
+		Key:          prefix + ext,
+		ThumbnailKey: prefix + "_thumb.jpg",
+		CreatedBy:    &authorID,
+	}
+
+	if err := uc.store.Put(ctx, media.Key, contentType, bytes.NewReader(upload.Data)); err != nil {
+		return entity.QuestionMedia{}, fmt.Errorf("media - Store.Put: %w", err)
+	}
+
+	if err := uc.store.Put(ctx, media.ThumbnailKey, "image/jpeg", bytes.NewReader(thumb)); err != nil {
+		// The upload error is the one to report.
+		_ = uc.deleteBlobs(ctx, media)
+
+		return entity.QuestionMedia{}, fmt.Errorf("media - Store.Put: %w", err)
+	}
+
+	created, err := uc.repo.Create(ctx, media)
+	if err != nil {
+		// The insert error is the one to report.
+		_ = uc.deleteBlobs(ctx, media)
+
+		if errors.Is(err, repo.ErrNotFound) {
+			return entity.QuestionMedia{}, ErrQuestionNotFound
+		}
+
+		return entity.QuestionMedia{}, fmt.Errorf("media - Create: %w", err)
+	}
+
+	if err := uc.sign(ctx, &created); err != nil {
+		return entity.QuestionMedia{}, err
+	}
+
+	return created, nil
+}
+
+// List returns a question's media with fresh download URLs.
+func (uc *UseCase) List(ctx context.Context, questionID uuid.UUID) ([]entity.QuestionMedia, error) {
+	if _, err := uc.questions.GetByID(ctx, questionID); err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return nil, ErrQuestionNotFound
+		}
+
+		return nil, fmt.Errorf("media - Questions.GetByID: %w", err)
+	}
+
+	byQuestion, err := uc.ForQuestions(ctx, []uuid.UUID{questionID})
+	if err != nil {
+		return nil, err
+	}
+
+	if byQuestion[questionID] == nil {
+		return []entity.QuestionMedia{}, nil
+	}
+
+	return byQuestion[questionID], nil
+}
+
+// ForQuestions returns the media of each question, with fresh download URLs.
+// Questions without media are left out of the map.
+func (uc *UseCase) ForQuestions(ctx context.Context, questionIDs []uuid.UUID) (map[uuid.UUID][]entity.QuestionMedia, error) {
+	media, err := uc.repo.ListByQuestions(ctx, questionIDs)
+	if err != nil {
+		return nil, fmt.Errorf("media - ListByQuestions: %w", err)
+	}
+
+	out := make(map[uuid.UUID][]entity.QuestionMedia)
+	for i := range media {
+		if err := uc.sign(ctx, &media[i]); err != nil {
+			return nil, err
+		}
+		out[media[i].QuestionID] = append(out[media[i].QuestionID], media[i])
+	}
+
+	return out, nil
+}
+
+// Delete detaches media from its question and removes its files. The row
+// goes first, so the media is gone for learners even if storage fails.
+func (uc *UseCase) Delete(ctx context.Context, questionID, id uuid.UUID) error {
+	media, err := uc.repo.Delete(ctx, questionID, id)
+	if errors.Is(err, repo.ErrNotFound) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("media - Delete: %w", err)
+	}
+
+	return uc.deleteBlobs(ctx, media)
+}
+
+// Open checks a signed download link and opens the file it points at,
+// returning it with its content type. It only serves stores whose links
+// point back at this service.
+func (uc *UseCase) Open(ctx context.Context, key string, expires int64, signature string) (io.ReadCloser, string, error) {
+	verifier, ok := uc.store.(blob.Verifier)
+	if !ok {
+		return nil, "", ErrNotFound
+	}
+
+	if err := verifier.Verify(key, expires, signature); err != nil {
+		return nil, "", ErrNotFound
+	}
+
+	contentType := ""
+	for t, ext := range extensions() {
+		if path.Ext(key) == ext {
+			contentType = t
+		}
+	}
+	if contentType == "" {
+		return nil, "", ErrNotFound
+	}
+
+	file, err := uc.store.Open(ctx, key)
+	if errors.Is(err, blob.ErrNotFound) || errors.Is(err, blob.ErrInvalidKey) {
+		return nil, "", ErrNotFound
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("media - Store.Open: %w", err)
+	}
+
+	return file, contentType, nil
+}
+
+func (uc *UseCase) sign(ctx context.Context, media *entity.QuestionMedia) error {
+	var err error
+	if media.URL, err = uc.store.SignedURL(ctx, media.Key, uc.opts.URLTTL); err != nil {
+		return fmt.Errorf("media - Store.SignedURL: %w", err)
+	}
+	if media.ThumbnailURL, err = uc.store.SignedURL(ctx, media.ThumbnailKey, uc.opts.URLTTL); err != nil {
+		return fmt.Errorf("media - Store.SignedURL: %w", err)
+	}
+
+	return nil
+}
+
+func (uc *UseCase) deleteBlobs(ctx context.Context, media entity.QuestionMedia) error {
+	var errs []error
+	for _, key := range []string{media.Key, media.ThumbnailKey} {
+		if err := uc.store.Delete(ctx, key); err != nil {
+			errs = append(errs, fmt.Errorf("media - Store.Delete: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func validSlot(slot entity.QuestionMediaSlot) bool {
+	switch slot {
+	case entity.QuestionMediaStem, entity.QuestionMediaOptionA, entity.QuestionMediaOptionB,
+		entity.QuestionMediaOptionC, entity.QuestionMediaOptionD, entity.QuestionMediaExplanation:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,178 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/repo"
+	"github.com/evrone/go-clean-template/internal/usecase/media"
+	"github.com/evrone/go-clean-template/pkg/blob"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type mediaMocks struct {
+	media     *MockQuestionMediaRepository
+	questions *MockQuestionRepository
+}
+
+func mediaUseCase(t *testing.T) (*media.UseCase, mediaMocks) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+
+	m := mediaMocks{
+		media:     NewMockQuestionMediaRepository(mockCtl),
+		questions: NewMockQuestionRepository(mockCtl),
+	}
+
+	store, err := blob.NewLocal(t.TempDir(), "http://localhost/v1/media", "secret")
+	require.NoError(t, err)
+
+	return media.New(m.media, m.questions, store, media.Options{MaxBytes: 1 << 20, URLTTL: time.Hour}), m
+}
+
+func pngImage(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := range height {
+		for x := range width {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 0x80, A: 0xff})
+		}
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+
+	return buf.Bytes()
+}
+
+// openSigned downloads a signed URL the way the media route does.
+func openSigned(t *testing.T, uc *media.UseCase, signed string) (io.ReadCloser, string, error) {
+	t.Helper()
+
+	u, err := url.Parse(signed)
+	require.NoError(t, err)
+
+	expires, err := strconv.ParseInt(u.Query().Get("expires"), 10, 64)
+	require.NoError(t, err)
+
+	return uc.Open(context.Background(), strings.TrimPrefix(u.Path, "/v1/media/"), expires, u.Query().Get("signature"))
+}
+
+func TestUploadQuestionMedia(t *testing.T) {
+	t.Parallel()
+
+	uc, m := mediaUseCase(t)
+
+	authorID, questionID := uuid.New(), uuid.New()
+	data := pngImage(t, 640, 480)
+
+	m.questions.EXPECT().GetByID(gomock.Any(), questionID).Return(entity.Question{ID: questionID}, nil)
+	m.media.EXPECT().Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, q entity.QuestionMedia) (entity.QuestionMedia, error) {
+			require.Equal(t, "image/png", q.ContentType)
+			require.Equal(t, []int{640, 480}, []int{q.Width, q.Height})
+			require.Equal(t, authorID, *q.CreatedBy)
+			require.True(t, strings.HasPrefix(q.Key, "questions/"+questionID.String()+"/"))
+
+			return q, nil
+		})
+
+	uploaded, err := uc.Upload(context.Background(), authorID, media.Upload{
+		QuestionID: questionID,
+		Slot:       entity.QuestionMediaStem,
+		AltText:    "Chest X-ray",
+		Data:       data,
+	})
+	require.NoError(t, err)
+
+	file, contentType, err := openSigned(t, uc, uploaded.URL)
+	require.NoError(t, err)
+	stored, err := io.ReadAll(file)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	require.Equal(t, "image/png", contentType)
+	require.Equal(t, data, stored)
+
+	file, contentType, err = openSigned(t, uc, uploaded.ThumbnailURL)
+	require.NoError(t, err)
+	thumb, _, err := image.DecodeConfig(file)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	require.Equal(t, "image/jpeg", contentType)
+	require.Equal(t, []int{320, 240}, []int{thumb.Width, thumb.Height})
+
+	tampered := strings.Replace(uploaded.URL, "signature=", "signature=x", 1)
+	_, _, err = openSigned(t, uc, tampered)
+	require.ErrorIs(t, err, media.ErrNotFound)
+}
+
+func TestUploadQuestionMediaRejects(t *testing.T) {
+	t.Parallel()
+
+	uc, m := mediaUseCase(t)
+
+	questionID, missing := uuid.New(), uuid.New()
+	m.questions.EXPECT().GetByID(gomock.Any(), missing).Return(entity.Question{}, repo.ErrNotFound)
+
+	tests := []struct {
+		name   string
+		upload media.Upload
+		err    error
+	}{
+		{"unknown slot", media.Upload{QuestionID: questionID, Slot: "footer", Data: pngImage(t, 2, 2)}, media.ErrInvalidSlot},
+		{"not an image", media.Upload{QuestionID: questionID, Slot: entity.QuestionMediaStem, Data: []byte("<svg></svg>")}, media.ErrUnsupportedType},
+		{"over the size limit", media.Upload{QuestionID: questionID, Slot: entity.QuestionMediaStem, Data: make([]byte, 2<<20)}, media.ErrTooLarge},
+		{"missing question", media.Upload{QuestionID: missing, Slot: entity.QuestionMediaOptionA, Data: pngImage(t, 2, 2)}, media.ErrQuestionNotFound},
+	}
+
+	for _, tc := range tests {
+		_, err := uc.Upload(context.Background(), uuid.New(), tc.upload)
+		require.ErrorIs(t, err, tc.err, tc.name)
+	}
+}
+
+func TestSessionDetailIncludesMedia(t *testing.T) {
+	t.Parallel()
+
+	uc, m := practiceUseCase(t)
+	inEnglish(m)
+
+	session := entity.PracticeSession{ID: uuid.New(), UserID: uuid.New(), Status: entity.PracticeStatusInProgress, StartedAt: time.Now().UTC()}
+	withImage, plain := uuid.New(), uuid.New()
+	attached := entity.QuestionMedia{
+		ID:           uuid.New(),
+		QuestionID:   withImage,
+		Slot:         entity.QuestionMediaStem,
+		Key:          "questions/a/b.png",
+		ThumbnailKey: "questions/a/b_thumb.jpg",
+	}
+
+	m.sessions.EXPECT().GetSession(gomock.Any(), session.ID).Return(session, nil)
+	m.sessions.EXPECT().ListSessionQuestions(gomock.Any(), session.ID).Return([]entity.PracticeSessionQuestion{
+		{ID: uuid.New(), Question: entity.Question{ID: withImage}},
+		{ID: uuid.New(), Question: entity.Question{ID: plain}},
+	}, nil)
+	m.media.EXPECT().ListByQuestions(gomock.Any(), []uuid.UUID{withImage, plain}).Return([]entity.QuestionMedia{attached}, nil)
+
+	detail, err := uc.GetSessionDetail(context.Background(), session.ID, session.UserID)
+	require.NoError(t, err)
+
+	require.Len(t, detail.Questions[0].Question.Media, 1)
+	require.Contains(t, detail.Questions[0].Question.Media[0].URL, "http://localhost/v1/media/questions/a/b.png?")
+	require.Contains(t, detail.Questions[0].Question.Media[0].ThumbnailURL, "signature=")
+	require.Empty(t, detail.Questions[1].Question.Media)
+}
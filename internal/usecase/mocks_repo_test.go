@@ -12,8 +12,11 @@ package usecase_test
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	entity "github.com/evrone/go-clean-template/internal/entity"
+	repo "github.com/evrone/go-clean-template/internal/repo"
+	uuid "github.com/google/uuid"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -108,3 +111,2569 @@ func (mr *MockTranslationWebAPIMockRecorder) Translate(arg0 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Translate", reflect.TypeOf((*MockTranslationWebAPI)(nil).Translate), arg0)
 }
+
+// MockUserRepository is a mock of UserRepository interface.
+type MockUserRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockUserRepositoryMockRecorder is the mock recorder for MockUserRepository.
+type MockUserRepositoryMockRecorder struct {
+	mock *MockUserRepository
+}
+
+// NewMockUserRepository creates a new mock instance.
+func NewMockUserRepository(ctrl *gomock.Controller) *MockUserRepository {
+	mock := &MockUserRepository{ctrl: ctrl}
+	mock.recorder = &MockUserRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserRepository) EXPECT() *MockUserRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockUserRepository) Create(ctx context.Context, user entity.User) (entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, user)
+	ret0, _ := ret[0].(entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockUserRepositoryMockRecorder) Create(ctx, user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUserRepository)(nil).Create), ctx, user)
+}
+
+// GetByID mocks base method.
+func (m *MockUserRepository) GetByID(ctx context.Context, userID uuid.UUID) (entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, userID)
+	ret0, _ := ret[0].(entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockUserRepositoryMockRecorder) GetByID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockUserRepository)(nil).GetByID), ctx, userID)
+}
+
+// GetByTelegramID mocks base method.
+func (m *MockUserRepository) GetByTelegramID(ctx context.Context, telegramID string) (entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByTelegramID", ctx, telegramID)
+	ret0, _ := ret[0].(entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByTelegramID indicates an expected call of GetByTelegramID.
+func (mr *MockUserRepositoryMockRecorder) GetByTelegramID(ctx, telegramID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByTelegramID", reflect.TypeOf((*MockUserRepository)(nil).GetByTelegramID), ctx, telegramID)
+}
+
+// GetExamProfile mocks base method.
+func (m *MockUserRepository) GetExamProfile(ctx context.Context, userID uuid.UUID) (entity.ExamProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExamProfile", ctx, userID)
+	ret0, _ := ret[0].(entity.ExamProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExamProfile indicates an expected call of GetExamProfile.
+func (mr *MockUserRepositoryMockRecorder) GetExamProfile(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExamProfile", reflect.TypeOf((*MockUserRepository)(nil).GetExamProfile), ctx, userID)
+}
+
+// MockSubjectRepository is a mock of SubjectRepository interface.
+type MockSubjectRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSubjectRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockSubjectRepositoryMockRecorder is the mock recorder for MockSubjectRepository.
+type MockSubjectRepositoryMockRecorder struct {
+	mock *MockSubjectRepository
+}
+
+// NewMockSubjectRepository creates a new mock instance.
+func NewMockSubjectRepository(ctrl *gomock.Controller) *MockSubjectRepository {
+	mock := &MockSubjectRepository{ctrl: ctrl}
+	mock.recorder = &MockSubjectRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSubjectRepository) EXPECT() *MockSubjectRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockSubjectRepository) Create(ctx context.Context, subject entity.Subject) (entity.Subject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, subject)
+	ret0, _ := ret[0].(entity.Subject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockSubjectRepositoryMockRecorder) Create(ctx, subject any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSubjectRepository)(nil).Create), ctx, subject)
+}
+
+// Delete mocks base method.
+func (m *MockSubjectRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockSubjectRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSubjectRepository)(nil).Delete), ctx, id)
+}
+
+// Get mocks base method.
+func (m *MockSubjectRepository) Get(ctx context.Context, id uuid.UUID) (entity.Subject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(entity.Subject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockSubjectRepositoryMockRecorder) Get(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockSubjectRepository)(nil).Get), ctx, id)
+}
+
+// ListByExam mocks base method.
+func (m *MockSubjectRepository) ListByExam(ctx context.Context, exam *entity.ExamCategory) ([]entity.Subject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByExam", ctx, exam)
+	ret0, _ := ret[0].([]entity.Subject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByExam indicates an expected call of ListByExam.
+func (mr *MockSubjectRepositoryMockRecorder) ListByExam(ctx, exam any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByExam", reflect.TypeOf((*MockSubjectRepository)(nil).ListByExam), ctx, exam)
+}
+
+// Reorder mocks base method.
+func (m *MockSubjectRepository) Reorder(ctx context.Context, ids []uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reorder", ctx, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reorder indicates an expected call of Reorder.
+func (mr *MockSubjectRepositoryMockRecorder) Reorder(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reorder", reflect.TypeOf((*MockSubjectRepository)(nil).Reorder), ctx, ids)
+}
+
+// Update mocks base method.
+func (m *MockSubjectRepository) Update(ctx context.Context, subject entity.Subject) (entity.Subject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, subject)
+	ret0, _ := ret[0].(entity.Subject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockSubjectRepositoryMockRecorder) Update(ctx, subject any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockSubjectRepository)(nil).Update), ctx, subject)
+}
+
+// MockTopicRepository is a mock of TopicRepository interface.
+type MockTopicRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTopicRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockTopicRepositoryMockRecorder is the mock recorder for MockTopicRepository.
+type MockTopicRepositoryMockRecorder struct {
+	mock *MockTopicRepository
+}
+
+// NewMockTopicRepository creates a new mock instance.
+func NewMockTopicRepository(ctrl *gomock.Controller) *MockTopicRepository {
+	mock := &MockTopicRepository{ctrl: ctrl}
+	mock.recorder = &MockTopicRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTopicRepository) EXPECT() *MockTopicRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockTopicRepository) Create(ctx context.Context, topic entity.Topic) (entity.Topic, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, topic)
+	ret0, _ := ret[0].(entity.Topic)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTopicRepositoryMockRecorder) Create(ctx, topic any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTopicRepository)(nil).Create), ctx, topic)
+}
+
+// Delete mocks base method.
+func (m *MockTopicRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTopicRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTopicRepository)(nil).Delete), ctx, id)
+}
+
+// Get mocks base method.
+func (m *MockTopicRepository) Get(ctx context.Context, id uuid.UUID) (entity.Topic, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(entity.Topic)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockTopicRepositoryMockRecorder) Get(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockTopicRepository)(nil).Get), ctx, id)
+}
+
+// ListBySubject mocks base method.
+func (m *MockTopicRepository) ListBySubject(ctx context.Context, subjectID uuid.UUID) ([]entity.Topic, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBySubject", ctx, subjectID)
+	ret0, _ := ret[0].([]entity.Topic)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBySubject indicates an expected call of ListBySubject.
+func (mr *MockTopicRepositoryMockRecorder) ListBySubject(ctx, subjectID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBySubject", reflect.TypeOf((*MockTopicRepository)(nil).ListBySubject), ctx, subjectID)
+}
+
+// Merge mocks base method.
+func (m *MockTopicRepository) Merge(ctx context.Context, sourceID, targetID uuid.UUID) (entity.TopicMergeResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Merge", ctx, sourceID, targetID)
+	ret0, _ := ret[0].(entity.TopicMergeResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Merge indicates an expected call of Merge.
+func (mr *MockTopicRepositoryMockRecorder) Merge(ctx, sourceID, targetID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockTopicRepository)(nil).Merge), ctx, sourceID, targetID)
+}
+
+// Reorder mocks base method.
+func (m *MockTopicRepository) Reorder(ctx context.Context, ids []uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reorder", ctx, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reorder indicates an expected call of Reorder.
+func (mr *MockTopicRepositoryMockRecorder) Reorder(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reorder", reflect.TypeOf((*MockTopicRepository)(nil).Reorder), ctx, ids)
+}
+
+// Update mocks base method.
+func (m *MockTopicRepository) Update(ctx context.Context, topic entity.Topic) (entity.Topic, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, topic)
+	ret0, _ := ret[0].(entity.Topic)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockTopicRepositoryMockRecorder) Update(ctx, topic any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTopicRepository)(nil).Update), ctx, topic)
+}
+
+// MockQuestionRepository is a mock of QuestionRepository interface.
+type MockQuestionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockQuestionRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockQuestionRepositoryMockRecorder is the mock recorder for MockQuestionRepository.
+type MockQuestionRepositoryMockRecorder struct {
+	mock *MockQuestionRepository
+}
+
+// NewMockQuestionRepository creates a new mock instance.
+func NewMockQuestionRepository(ctrl *gomock.Controller) *MockQuestionRepository {
+	mock := &MockQuestionRepository{ctrl: ctrl}
+	mock.recorder = &MockQuestionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQuestionRepository) EXPECT() *MockQuestionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Count mocks base method.
+func (m *MockQuestionRepository) Count(ctx context.Context, filter repo.QuestionFilter) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", ctx, filter)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockQuestionRepositoryMockRecorder) Count(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockQuestionRepository)(nil).Count), ctx, filter)
+}
+
+// Create mocks base method.
+func (m *MockQuestionRepository) Create(ctx context.Context, version entity.QuestionVersion) (entity.Question, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, version)
+	ret0, _ := ret[0].(entity.Question)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockQuestionRepositoryMockRecorder) Create(ctx, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockQuestionRepository)(nil).Create), ctx, version)
+}
+
+// Delete mocks base method.
+func (m *MockQuestionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockQuestionRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockQuestionRepository)(nil).Delete), ctx, id)
+}
+
+// ExistingIDs mocks base method.
+func (m *MockQuestionRepository) ExistingIDs(ctx context.Context, ids []uuid.UUID) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExistingIDs", ctx, ids)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExistingIDs indicates an expected call of ExistingIDs.
+func (mr *MockQuestionRepositoryMockRecorder) ExistingIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistingIDs", reflect.TypeOf((*MockQuestionRepository)(nil).ExistingIDs), ctx, ids)
+}
+
+// GetByID mocks base method.
+func (m *MockQuestionRepository) GetByID(ctx context.Context, id uuid.UUID) (entity.Question, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(entity.Question)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockQuestionRepositoryMockRecorder) GetByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockQuestionRepository)(nil).GetByID), ctx, id)
+}
+
+// GetVersion mocks base method.
+func (m *MockQuestionRepository) GetVersion(ctx context.Context, questionID uuid.UUID, version int) (entity.QuestionVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVersion", ctx, questionID, version)
+	ret0, _ := ret[0].(entity.QuestionVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVersion indicates an expected call of GetVersion.
+func (mr *MockQuestionRepositoryMockRecorder) GetVersion(ctx, questionID, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVersion", reflect.TypeOf((*MockQuestionRepository)(nil).GetVersion), ctx, questionID, version)
+}
+
+// List mocks base method.
+func (m *MockQuestionRepository) List(ctx context.Context, filter repo.QuestionFilter, page repo.QuestionPage) ([]entity.QuestionListItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filter, page)
+	ret0, _ := ret[0].([]entity.QuestionListItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockQuestionRepositoryMockRecorder) List(ctx, filter, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockQuestionRepository)(nil).List), ctx, filter, page)
+}
+
+// ListPoolIDs mocks base method.
+func (m *MockQuestionRepository) ListPoolIDs(ctx context.Context, filter repo.QuestionPoolFilter) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPoolIDs", ctx, filter)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPoolIDs indicates an expected call of ListPoolIDs.
+func (mr *MockQuestionRepositoryMockRecorder) ListPoolIDs(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPoolIDs", reflect.TypeOf((*MockQuestionRepository)(nil).ListPoolIDs), ctx, filter)
+}
+
+// ListVersions mocks base method.
+func (m *MockQuestionRepository) ListVersions(ctx context.Context, questionID uuid.UUID) ([]entity.QuestionVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVersions", ctx, questionID)
+	ret0, _ := ret[0].([]entity.QuestionVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVersions indicates an expected call of ListVersions.
+func (mr *MockQuestionRepositoryMockRecorder) ListVersions(ctx, questionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVersions", reflect.TypeOf((*MockQuestionRepository)(nil).ListVersions), ctx, questionID)
+}
+
+// SetDifficulty mocks base method.
+func (m *MockQuestionRepository) SetDifficulty(ctx context.Context, id uuid.UUID, level *int, locked bool) (entity.Question, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDifficulty", ctx, id, level, locked)
+	ret0, _ := ret[0].(entity.Question)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetDifficulty indicates an expected call of SetDifficulty.
+func (mr *MockQuestionRepositoryMockRecorder) SetDifficulty(ctx, id, level, locked any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDifficulty", reflect.TypeOf((*MockQuestionRepository)(nil).SetDifficulty), ctx, id, level, locked)
+}
+
+// Stream mocks base method.
+func (m *MockQuestionRepository) Stream(ctx context.Context, filter repo.QuestionFilter, fn func(entity.Question) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stream", ctx, filter, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Stream indicates an expected call of Stream.
+func (mr *MockQuestionRepositoryMockRecorder) Stream(ctx, filter, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stream", reflect.TypeOf((*MockQuestionRepository)(nil).Stream), ctx, filter, fn)
+}
+
+// Transition mocks base method.
+func (m *MockQuestionRepository) Transition(ctx context.Context, id uuid.UUID, from, to entity.QuestionStatus, reviewerID *uuid.UUID) (entity.Question, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transition", ctx, id, from, to, reviewerID)
+	ret0, _ := ret[0].(entity.Question)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Transition indicates an expected call of Transition.
+func (mr *MockQuestionRepositoryMockRecorder) Transition(ctx, id, from, to, reviewerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transition", reflect.TypeOf((*MockQuestionRepository)(nil).Transition), ctx, id, from, to, reviewerID)
+}
+
+// Update mocks base method.
+func (m *MockQuestionRepository) Update(ctx context.Context, version entity.QuestionVersion, difficulty *int) (entity.Question, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, version, difficulty)
+	ret0, _ := ret[0].(entity.Question)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockQuestionRepositoryMockRecorder) Update(ctx, version, difficulty any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockQuestionRepository)(nil).Update), ctx, version, difficulty)
+}
+
+// MockTagRepository is a mock of TagRepository interface.
+type MockTagRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTagRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockTagRepositoryMockRecorder is the mock recorder for MockTagRepository.
+type MockTagRepositoryMockRecorder struct {
+	mock *MockTagRepository
+}
+
+// NewMockTagRepository creates a new mock instance.
+func NewMockTagRepository(ctrl *gomock.Controller) *MockTagRepository {
+	mock := &MockTagRepository{ctrl: ctrl}
+	mock.recorder = &MockTagRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTagRepository) EXPECT() *MockTagRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockTagRepository) Create(ctx context.Context, tag entity.Tag) (entity.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, tag)
+	ret0, _ := ret[0].(entity.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTagRepositoryMockRecorder) Create(ctx, tag any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTagRepository)(nil).Create), ctx, tag)
+}
+
+// Delete mocks base method.
+func (m *MockTagRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTagRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTagRepository)(nil).Delete), ctx, id)
+}
+
+// Get mocks base method.
+func (m *MockTagRepository) Get(ctx context.Context, id uuid.UUID) (entity.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(entity.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockTagRepositoryMockRecorder) Get(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockTagRepository)(nil).Get), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockTagRepository) List(ctx context.Context, filter repo.TagFilter) ([]entity.Tag, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filter)
+	ret0, _ := ret[0].([]entity.Tag)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockTagRepositoryMockRecorder) List(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTagRepository)(nil).List), ctx, filter)
+}
+
+// SetQuestionTags mocks base method.
+func (m *MockTagRepository) SetQuestionTags(ctx context.Context, questionID uuid.UUID, names []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetQuestionTags", ctx, questionID, names)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetQuestionTags indicates an expected call of SetQuestionTags.
+func (mr *MockTagRepositoryMockRecorder) SetQuestionTags(ctx, questionID, names any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetQuestionTags", reflect.TypeOf((*MockTagRepository)(nil).SetQuestionTags), ctx, questionID, names)
+}
+
+// Update mocks base method.
+func (m *MockTagRepository) Update(ctx context.Context, tag entity.Tag) (entity.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, tag)
+	ret0, _ := ret[0].(entity.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockTagRepositoryMockRecorder) Update(ctx, tag any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTagRepository)(nil).Update), ctx, tag)
+}
+
+// MockLocalizationRepository is a mock of LocalizationRepository interface.
+type MockLocalizationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLocalizationRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockLocalizationRepositoryMockRecorder is the mock recorder for MockLocalizationRepository.
+type MockLocalizationRepositoryMockRecorder struct {
+	mock *MockLocalizationRepository
+}
+
+// NewMockLocalizationRepository creates a new mock instance.
+func NewMockLocalizationRepository(ctrl *gomock.Controller) *MockLocalizationRepository {
+	mock := &MockLocalizationRepository{ctrl: ctrl}
+	mock.recorder = &MockLocalizationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLocalizationRepository) EXPECT() *MockLocalizationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockLocalizationRepository) Get(ctx context.Context, questionID uuid.UUID, language string) (entity.QuestionLocalization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, questionID, language)
+	ret0, _ := ret[0].(entity.QuestionLocalization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockLocalizationRepositoryMockRecorder) Get(ctx, questionID, language any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockLocalizationRepository)(nil).Get), ctx, questionID, language)
+}
+
+// GetLanguage mocks base method.
+func (m *MockLocalizationRepository) GetLanguage(ctx context.Context, userID uuid.UUID) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLanguage", ctx, userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLanguage indicates an expected call of GetLanguage.
+func (mr *MockLocalizationRepositoryMockRecorder) GetLanguage(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLanguage", reflect.TypeOf((*MockLocalizationRepository)(nil).GetLanguage), ctx, userID)
+}
+
+// List mocks base method.
+func (m *MockLocalizationRepository) List(ctx context.Context, filter repo.LocalizationFilter) ([]entity.QuestionLocalization, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filter)
+	ret0, _ := ret[0].([]entity.QuestionLocalization)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockLocalizationRepositoryMockRecorder) List(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockLocalizationRepository)(nil).List), ctx, filter)
+}
+
+// ListForQuestions mocks base method.
+func (m *MockLocalizationRepository) ListForQuestions(ctx context.Context, questionIDs []uuid.UUID, language string) ([]entity.QuestionLocalization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListForQuestions", ctx, questionIDs, language)
+	ret0, _ := ret[0].([]entity.QuestionLocalization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListForQuestions indicates an expected call of ListForQuestions.
+func (mr *MockLocalizationRepositoryMockRecorder) ListForQuestions(ctx, questionIDs, language any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListForQuestions", reflect.TypeOf((*MockLocalizationRepository)(nil).ListForQuestions), ctx, questionIDs, language)
+}
+
+// ListPending mocks base method.
+func (m *MockLocalizationRepository) ListPending(ctx context.Context, language string, limit int) ([]entity.Question, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPending", ctx, language, limit)
+	ret0, _ := ret[0].([]entity.Question)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPending indicates an expected call of ListPending.
+func (mr *MockLocalizationRepositoryMockRecorder) ListPending(ctx, language, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPending", reflect.TypeOf((*MockLocalizationRepository)(nil).ListPending), ctx, language, limit)
+}
+
+// Save mocks base method.
+func (m *MockLocalizationRepository) Save(ctx context.Context, localization entity.QuestionLocalization) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, localization)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockLocalizationRepositoryMockRecorder) Save(ctx, localization any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockLocalizationRepository)(nil).Save), ctx, localization)
+}
+
+// SetLanguage mocks base method.
+func (m *MockLocalizationRepository) SetLanguage(ctx context.Context, userID uuid.UUID, language string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLanguage", ctx, userID, language)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetLanguage indicates an expected call of SetLanguage.
+func (mr *MockLocalizationRepositoryMockRecorder) SetLanguage(ctx, userID, language any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLanguage", reflect.TypeOf((*MockLocalizationRepository)(nil).SetLanguage), ctx, userID, language)
+}
+
+// MockItemAnalysisRepository is a mock of ItemAnalysisRepository interface.
+type MockItemAnalysisRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockItemAnalysisRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockItemAnalysisRepositoryMockRecorder is the mock recorder for MockItemAnalysisRepository.
+type MockItemAnalysisRepositoryMockRecorder struct {
+	mock *MockItemAnalysisRepository
+}
+
+// NewMockItemAnalysisRepository creates a new mock instance.
+func NewMockItemAnalysisRepository(ctrl *gomock.Controller) *MockItemAnalysisRepository {
+	mock := &MockItemAnalysisRepository{ctrl: ctrl}
+	mock.recorder = &MockItemAnalysisRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockItemAnalysisRepository) EXPECT() *MockItemAnalysisRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockItemAnalysisRepository) Get(ctx context.Context, questionID uuid.UUID) (entity.ItemAnalysis, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, questionID)
+	ret0, _ := ret[0].(entity.ItemAnalysis)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockItemAnalysisRepositoryMockRecorder) Get(ctx, questionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockItemAnalysisRepository)(nil).Get), ctx, questionID)
+}
+
+// List mocks base method.
+func (m *MockItemAnalysisRepository) List(ctx context.Context, filter repo.ItemAnalysisFilter) ([]entity.ItemAnalysis, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filter)
+	ret0, _ := ret[0].([]entity.ItemAnalysis)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockItemAnalysisRepositoryMockRecorder) List(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockItemAnalysisRepository)(nil).List), ctx, filter)
+}
+
+// ListAttemptStats mocks base method.
+func (m *MockItemAnalysisRepository) ListAttemptStats(ctx context.Context, after uuid.UUID, limit int) ([]repo.ItemAttemptStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAttemptStats", ctx, after, limit)
+	ret0, _ := ret[0].([]repo.ItemAttemptStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAttemptStats indicates an expected call of ListAttemptStats.
+func (mr *MockItemAnalysisRepositoryMockRecorder) ListAttemptStats(ctx, after, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAttemptStats", reflect.TypeOf((*MockItemAnalysisRepository)(nil).ListAttemptStats), ctx, after, limit)
+}
+
+// RefreshLearners mocks base method.
+func (m *MockItemAnalysisRepository) RefreshLearners(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshLearners", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefreshLearners indicates an expected call of RefreshLearners.
+func (mr *MockItemAnalysisRepositoryMockRecorder) RefreshLearners(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshLearners", reflect.TypeOf((*MockItemAnalysisRepository)(nil).RefreshLearners), ctx)
+}
+
+// Save mocks base method.
+func (m *MockItemAnalysisRepository) Save(ctx context.Context, analyses []entity.ItemAnalysis) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, analyses)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockItemAnalysisRepositoryMockRecorder) Save(ctx, analyses any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockItemAnalysisRepository)(nil).Save), ctx, analyses)
+}
+
+// MockQuestionSignatureRepository is a mock of QuestionSignatureRepository interface.
+type MockQuestionSignatureRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockQuestionSignatureRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockQuestionSignatureRepositoryMockRecorder is the mock recorder for MockQuestionSignatureRepository.
+type MockQuestionSignatureRepositoryMockRecorder struct {
+	mock *MockQuestionSignatureRepository
+}
+
+// NewMockQuestionSignatureRepository creates a new mock instance.
+func NewMockQuestionSignatureRepository(ctrl *gomock.Controller) *MockQuestionSignatureRepository {
+	mock := &MockQuestionSignatureRepository{ctrl: ctrl}
+	mock.recorder = &MockQuestionSignatureRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQuestionSignatureRepository) EXPECT() *MockQuestionSignatureRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Candidates mocks base method.
+func (m *MockQuestionSignatureRepository) Candidates(ctx context.Context, exam entity.ExamCategory, bandKeys []int64) ([]entity.QuestionSignature, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Candidates", ctx, exam, bandKeys)
+	ret0, _ := ret[0].([]entity.QuestionSignature)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Candidates indicates an expected call of Candidates.
+func (mr *MockQuestionSignatureRepositoryMockRecorder) Candidates(ctx, exam, bandKeys any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Candidates", reflect.TypeOf((*MockQuestionSignatureRepository)(nil).Candidates), ctx, exam, bandKeys)
+}
+
+// Collisions mocks base method.
+func (m *MockQuestionSignatureRepository) Collisions(ctx context.Context, exam entity.ExamCategory, fn func([]uuid.UUID) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Collisions", ctx, exam, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Collisions indicates an expected call of Collisions.
+func (mr *MockQuestionSignatureRepositoryMockRecorder) Collisions(ctx, exam, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Collisions", reflect.TypeOf((*MockQuestionSignatureRepository)(nil).Collisions), ctx, exam, fn)
+}
+
+// Get mocks base method.
+func (m *MockQuestionSignatureRepository) Get(ctx context.Context, ids []uuid.UUID) ([]entity.QuestionSignature, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, ids)
+	ret0, _ := ret[0].([]entity.QuestionSignature)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockQuestionSignatureRepositoryMockRecorder) Get(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockQuestionSignatureRepository)(nil).Get), ctx, ids)
+}
+
+// ListStale mocks base method.
+func (m *MockQuestionSignatureRepository) ListStale(ctx context.Context, limit int) ([]entity.QuestionSignature, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStale", ctx, limit)
+	ret0, _ := ret[0].([]entity.QuestionSignature)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStale indicates an expected call of ListStale.
+func (mr *MockQuestionSignatureRepositoryMockRecorder) ListStale(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStale", reflect.TypeOf((*MockQuestionSignatureRepository)(nil).ListStale), ctx, limit)
+}
+
+// Save mocks base method.
+func (m *MockQuestionSignatureRepository) Save(ctx context.Context, signatures []entity.QuestionSignature) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, signatures)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockQuestionSignatureRepositoryMockRecorder) Save(ctx, signatures any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockQuestionSignatureRepository)(nil).Save), ctx, signatures)
+}
+
+// MockQuestionReviewRepository is a mock of QuestionReviewRepository interface.
+type MockQuestionReviewRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockQuestionReviewRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockQuestionReviewRepositoryMockRecorder is the mock recorder for MockQuestionReviewRepository.
+type MockQuestionReviewRepositoryMockRecorder struct {
+	mock *MockQuestionReviewRepository
+}
+
+// NewMockQuestionReviewRepository creates a new mock instance.
+func NewMockQuestionReviewRepository(ctrl *gomock.Controller) *MockQuestionReviewRepository {
+	mock := &MockQuestionReviewRepository{ctrl: ctrl}
+	mock.recorder = &MockQuestionReviewRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQuestionReviewRepository) EXPECT() *MockQuestionReviewRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateComment mocks base method.
+func (m *MockQuestionReviewRepository) CreateComment(ctx context.Context, comment entity.QuestionReviewComment) (entity.QuestionReviewComment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateComment", ctx, comment)
+	ret0, _ := ret[0].(entity.QuestionReviewComment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateComment indicates an expected call of CreateComment.
+func (mr *MockQuestionReviewRepositoryMockRecorder) CreateComment(ctx, comment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateComment", reflect.TypeOf((*MockQuestionReviewRepository)(nil).CreateComment), ctx, comment)
+}
+
+// ListComments mocks base method.
+func (m *MockQuestionReviewRepository) ListComments(ctx context.Context, questionID uuid.UUID, unresolvedOnly bool) ([]entity.QuestionReviewComment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListComments", ctx, questionID, unresolvedOnly)
+	ret0, _ := ret[0].([]entity.QuestionReviewComment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListComments indicates an expected call of ListComments.
+func (mr *MockQuestionReviewRepositoryMockRecorder) ListComments(ctx, questionID, unresolvedOnly any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListComments", reflect.TypeOf((*MockQuestionReviewRepository)(nil).ListComments), ctx, questionID, unresolvedOnly)
+}
+
+// ResolveComment mocks base method.
+func (m *MockQuestionReviewRepository) ResolveComment(ctx context.Context, questionID, id, resolvedBy uuid.UUID) (entity.QuestionReviewComment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveComment", ctx, questionID, id, resolvedBy)
+	ret0, _ := ret[0].(entity.QuestionReviewComment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveComment indicates an expected call of ResolveComment.
+func (mr *MockQuestionReviewRepositoryMockRecorder) ResolveComment(ctx, questionID, id, resolvedBy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveComment", reflect.TypeOf((*MockQuestionReviewRepository)(nil).ResolveComment), ctx, questionID, id, resolvedBy)
+}
+
+// MockQuestionReportRepository is a mock of QuestionReportRepository interface.
+type MockQuestionReportRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockQuestionReportRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockQuestionReportRepositoryMockRecorder is the mock recorder for MockQuestionReportRepository.
+type MockQuestionReportRepositoryMockRecorder struct {
+	mock *MockQuestionReportRepository
+}
+
+// NewMockQuestionReportRepository creates a new mock instance.
+func NewMockQuestionReportRepository(ctrl *gomock.Controller) *MockQuestionReportRepository {
+	mock := &MockQuestionReportRepository{ctrl: ctrl}
+	mock.recorder = &MockQuestionReportRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQuestionReportRepository) EXPECT() *MockQuestionReportRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockQuestionReportRepository) Close(ctx context.Context, questionID uuid.UUID, status entity.QuestionReportStatus, note string, resolvedBy uuid.UUID, notify func(entity.QuestionReport) entity.Notification) ([]entity.QuestionReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close", ctx, questionID, status, note, resolvedBy, notify)
+	ret0, _ := ret[0].([]entity.QuestionReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockQuestionReportRepositoryMockRecorder) Close(ctx, questionID, status, note, resolvedBy, notify any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockQuestionReportRepository)(nil).Close), ctx, questionID, status, note, resolvedBy, notify)
+}
+
+// CountOpen mocks base method.
+func (m *MockQuestionReportRepository) CountOpen(ctx context.Context, questionID uuid.UUID) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountOpen", ctx, questionID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountOpen indicates an expected call of CountOpen.
+func (mr *MockQuestionReportRepositoryMockRecorder) CountOpen(ctx, questionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountOpen", reflect.TypeOf((*MockQuestionReportRepository)(nil).CountOpen), ctx, questionID)
+}
+
+// Create mocks base method.
+func (m *MockQuestionReportRepository) Create(ctx context.Context, report entity.QuestionReport, hourlyLimit int) (entity.QuestionReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, report, hourlyLimit)
+	ret0, _ := ret[0].(entity.QuestionReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockQuestionReportRepositoryMockRecorder) Create(ctx, report, hourlyLimit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockQuestionReportRepository)(nil).Create), ctx, report, hourlyLimit)
+}
+
+// ListByQuestion mocks base method.
+func (m *MockQuestionReportRepository) ListByQuestion(ctx context.Context, questionID uuid.UUID, status *entity.QuestionReportStatus) ([]entity.QuestionReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByQuestion", ctx, questionID, status)
+	ret0, _ := ret[0].([]entity.QuestionReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByQuestion indicates an expected call of ListByQuestion.
+func (mr *MockQuestionReportRepositoryMockRecorder) ListByQuestion(ctx, questionID, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByQuestion", reflect.TypeOf((*MockQuestionReportRepository)(nil).ListByQuestion), ctx, questionID, status)
+}
+
+// ListGroups mocks base method.
+func (m *MockQuestionReportRepository) ListGroups(ctx context.Context, status entity.QuestionReportStatus, page, pageSize int) ([]entity.QuestionReportGroup, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListGroups", ctx, status, page, pageSize)
+	ret0, _ := ret[0].([]entity.QuestionReportGroup)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListGroups indicates an expected call of ListGroups.
+func (mr *MockQuestionReportRepositoryMockRecorder) ListGroups(ctx, status, page, pageSize any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGroups", reflect.TypeOf((*MockQuestionReportRepository)(nil).ListGroups), ctx, status, page, pageSize)
+}
+
+// MockNotificationRepository is a mock of NotificationRepository interface.
+type MockNotificationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotificationRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockNotificationRepositoryMockRecorder is the mock recorder for MockNotificationRepository.
+type MockNotificationRepositoryMockRecorder struct {
+	mock *MockNotificationRepository
+}
+
+// NewMockNotificationRepository creates a new mock instance.
+func NewMockNotificationRepository(ctrl *gomock.Controller) *MockNotificationRepository {
+	mock := &MockNotificationRepository{ctrl: ctrl}
+	mock.recorder = &MockNotificationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotificationRepository) EXPECT() *MockNotificationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockNotificationRepository) Create(ctx context.Context, notifications []entity.Notification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, notifications)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockNotificationRepositoryMockRecorder) Create(ctx, notifications any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockNotificationRepository)(nil).Create), ctx, notifications)
+}
+
+// ListByUser mocks base method.
+func (m *MockNotificationRepository) ListByUser(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit int) ([]entity.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUser", ctx, userID, unreadOnly, limit)
+	ret0, _ := ret[0].([]entity.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUser indicates an expected call of ListByUser.
+func (mr *MockNotificationRepositoryMockRecorder) ListByUser(ctx, userID, unreadOnly, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockNotificationRepository)(nil).ListByUser), ctx, userID, unreadOnly, limit)
+}
+
+// MarkRead mocks base method.
+func (m *MockNotificationRepository) MarkRead(ctx context.Context, userID, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRead", ctx, userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkRead indicates an expected call of MarkRead.
+func (mr *MockNotificationRepositoryMockRecorder) MarkRead(ctx, userID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRead", reflect.TypeOf((*MockNotificationRepository)(nil).MarkRead), ctx, userID, id)
+}
+
+// MockQuestionMediaRepository is a mock of QuestionMediaRepository interface.
+type MockQuestionMediaRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockQuestionMediaRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockQuestionMediaRepositoryMockRecorder is the mock recorder for MockQuestionMediaRepository.
+type MockQuestionMediaRepositoryMockRecorder struct {
+	mock *MockQuestionMediaRepository
+}
+
+// NewMockQuestionMediaRepository creates a new mock instance.
+func NewMockQuestionMediaRepository(ctrl *gomock.Controller) *MockQuestionMediaRepository {
+	mock := &MockQuestionMediaRepository{ctrl: ctrl}
+	mock.recorder = &MockQuestionMediaRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQuestionMediaRepository) EXPECT() *MockQuestionMediaRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockQuestionMediaRepository) Create(ctx context.Context, media entity.QuestionMedia) (entity.QuestionMedia, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, media)
+	ret0, _ := ret[0].(entity.QuestionMedia)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockQuestionMediaRepositoryMockRecorder) Create(ctx, media any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockQuestionMediaRepository)(nil).Create), ctx, media)
+}
+
+// Delete mocks base method.
+func (m *MockQuestionMediaRepository) Delete(ctx context.Context, questionID, id uuid.UUID) (entity.QuestionMedia, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, questionID, id)
+	ret0, _ := ret[0].(entity.QuestionMedia)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockQuestionMediaRepositoryMockRecorder) Delete(ctx, questionID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockQuestionMediaRepository)(nil).Delete), ctx, questionID, id)
+}
+
+// ListByQuestions mocks base method.
+func (m *MockQuestionMediaRepository) ListByQuestions(ctx context.Context, questionIDs []uuid.UUID) ([]entity.QuestionMedia, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByQuestions", ctx, questionIDs)
+	ret0, _ := ret[0].([]entity.QuestionMedia)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByQuestions indicates an expected call of ListByQuestions.
+func (mr *MockQuestionMediaRepositoryMockRecorder) ListByQuestions(ctx, questionIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByQuestions", reflect.TypeOf((*MockQuestionMediaRepository)(nil).ListByQuestions), ctx, questionIDs)
+}
+
+// MockQuestionImportRepository is a mock of QuestionImportRepository interface.
+type MockQuestionImportRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockQuestionImportRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockQuestionImportRepositoryMockRecorder is the mock recorder for MockQuestionImportRepository.
+type MockQuestionImportRepositoryMockRecorder struct {
+	mock *MockQuestionImportRepository
+}
+
+// NewMockQuestionImportRepository creates a new mock instance.
+func NewMockQuestionImportRepository(ctrl *gomock.Controller) *MockQuestionImportRepository {
+	mock := &MockQuestionImportRepository{ctrl: ctrl}
+	mock.recorder = &MockQuestionImportRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQuestionImportRepository) EXPECT() *MockQuestionImportRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimNext mocks base method.
+func (m *MockQuestionImportRepository) ClaimNext(ctx context.Context, staleBefore time.Time) (entity.QuestionImportJob, []byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimNext", ctx, staleBefore)
+	ret0, _ := ret[0].(entity.QuestionImportJob)
+	ret1, _ := ret[1].([]byte)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ClaimNext indicates an expected call of ClaimNext.
+func (mr *MockQuestionImportRepositoryMockRecorder) ClaimNext(ctx, staleBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimNext", reflect.TypeOf((*MockQuestionImportRepository)(nil).ClaimNext), ctx, staleBefore)
+}
+
+// Create mocks base method.
+func (m *MockQuestionImportRepository) Create(ctx context.Context, job entity.QuestionImportJob, payload []byte) (entity.QuestionImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, job, payload)
+	ret0, _ := ret[0].(entity.QuestionImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockQuestionImportRepositoryMockRecorder) Create(ctx, job, payload any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockQuestionImportRepository)(nil).Create), ctx, job, payload)
+}
+
+// Get mocks base method.
+func (m *MockQuestionImportRepository) Get(ctx context.Context, id uuid.UUID) (entity.QuestionImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(entity.QuestionImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockQuestionImportRepositoryMockRecorder) Get(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockQuestionImportRepository)(nil).Get), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockQuestionImportRepository) List(ctx context.Context, limit int) ([]entity.QuestionImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, limit)
+	ret0, _ := ret[0].([]entity.QuestionImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockQuestionImportRepositoryMockRecorder) List(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockQuestionImportRepository)(nil).List), ctx, limit)
+}
+
+// Save mocks base method.
+func (m *MockQuestionImportRepository) Save(ctx context.Context, job entity.QuestionImportJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, job)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockQuestionImportRepositoryMockRecorder) Save(ctx, job any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockQuestionImportRepository)(nil).Save), ctx, job)
+}
+
+// MockPracticeSessionRepository is a mock of PracticeSessionRepository interface.
+type MockPracticeSessionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPracticeSessionRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockPracticeSessionRepositoryMockRecorder is the mock recorder for MockPracticeSessionRepository.
+type MockPracticeSessionRepositoryMockRecorder struct {
+	mock *MockPracticeSessionRepository
+}
+
+// NewMockPracticeSessionRepository creates a new mock instance.
+func NewMockPracticeSessionRepository(ctrl *gomock.Controller) *MockPracticeSessionRepository {
+	mock := &MockPracticeSessionRepository{ctrl: ctrl}
+	mock.recorder = &MockPracticeSessionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPracticeSessionRepository) EXPECT() *MockPracticeSessionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AnswerSessionQuestion mocks base method.
+func (m *MockPracticeSessionRepository) AnswerSessionQuestion(ctx context.Context, sessionID, userID uuid.UUID, question entity.PracticeSessionQuestion, revision *entity.RevisionItem) (entity.PracticeSessionQuestion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AnswerSessionQuestion", ctx, sessionID, userID, question, revision)
+	ret0, _ := ret[0].(entity.PracticeSessionQuestion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AnswerSessionQuestion indicates an expected call of AnswerSessionQuestion.
+func (mr *MockPracticeSessionRepositoryMockRecorder) AnswerSessionQuestion(ctx, sessionID, userID, question, revision any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnswerSessionQuestion", reflect.TypeOf((*MockPracticeSessionRepository)(nil).AnswerSessionQuestion), ctx, sessionID, userID, question, revision)
+}
+
+// CreateSession mocks base method.
+func (m *MockPracticeSessionRepository) CreateSession(ctx context.Context, session entity.PracticeSession, questions []entity.PracticeSessionQuestion) (entity.PracticeSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSession", ctx, session, questions)
+	ret0, _ := ret[0].(entity.PracticeSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSession indicates an expected call of CreateSession.
+func (mr *MockPracticeSessionRepositoryMockRecorder) CreateSession(ctx, session, questions any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockPracticeSessionRepository)(nil).CreateSession), ctx, session, questions)
+}
+
+// ExpireSessions mocks base method.
+func (m *MockPracticeSessionRepository) ExpireSessions(ctx context.Context, now time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireSessions", ctx, now)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireSessions indicates an expected call of ExpireSessions.
+func (mr *MockPracticeSessionRepositoryMockRecorder) ExpireSessions(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireSessions", reflect.TypeOf((*MockPracticeSessionRepository)(nil).ExpireSessions), ctx, now)
+}
+
+// FinishSession mocks base method.
+func (m *MockPracticeSessionRepository) FinishSession(ctx context.Context, id uuid.UUID, status entity.PracticeSessionStatus, at time.Time) (entity.PracticeSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishSession", ctx, id, status, at)
+	ret0, _ := ret[0].(entity.PracticeSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinishSession indicates an expected call of FinishSession.
+func (mr *MockPracticeSessionRepositoryMockRecorder) FinishSession(ctx, id, status, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishSession", reflect.TypeOf((*MockPracticeSessionRepository)(nil).FinishSession), ctx, id, status, at)
+}
+
+// GetSession mocks base method.
+func (m *MockPracticeSessionRepository) GetSession(ctx context.Context, id uuid.UUID) (entity.PracticeSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSession", ctx, id)
+	ret0, _ := ret[0].(entity.PracticeSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSession indicates an expected call of GetSession.
+func (mr *MockPracticeSessionRepositoryMockRecorder) GetSession(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockPracticeSessionRepository)(nil).GetSession), ctx, id)
+}
+
+// GetSessionQuestion mocks base method.
+func (m *MockPracticeSessionRepository) GetSessionQuestion(ctx context.Context, sessionID, id uuid.UUID) (entity.PracticeSessionQuestion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSessionQuestion", ctx, sessionID, id)
+	ret0, _ := ret[0].(entity.PracticeSessionQuestion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSessionQuestion indicates an expected call of GetSessionQuestion.
+func (mr *MockPracticeSessionRepositoryMockRecorder) GetSessionQuestion(ctx, sessionID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessionQuestion", reflect.TypeOf((*MockPracticeSessionRepository)(nil).GetSessionQuestion), ctx, sessionID, id)
+}
+
+// ListSessionQuestions mocks base method.
+func (m *MockPracticeSessionRepository) ListSessionQuestions(ctx context.Context, sessionID uuid.UUID) ([]entity.PracticeSessionQuestion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSessionQuestions", ctx, sessionID)
+	ret0, _ := ret[0].([]entity.PracticeSessionQuestion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSessionQuestions indicates an expected call of ListSessionQuestions.
+func (mr *MockPracticeSessionRepositoryMockRecorder) ListSessionQuestions(ctx, sessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSessionQuestions", reflect.TypeOf((*MockPracticeSessionRepository)(nil).ListSessionQuestions), ctx, sessionID)
+}
+
+// ListSessions mocks base method.
+func (m *MockPracticeSessionRepository) ListSessions(ctx context.Context, userID uuid.UUID) ([]entity.PracticeSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSessions", ctx, userID)
+	ret0, _ := ret[0].([]entity.PracticeSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSessions indicates an expected call of ListSessions.
+func (mr *MockPracticeSessionRepositoryMockRecorder) ListSessions(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSessions", reflect.TypeOf((*MockPracticeSessionRepository)(nil).ListSessions), ctx, userID)
+}
+
+// ReplaceSessionQuestion mocks base method.
+func (m *MockPracticeSessionRepository) ReplaceSessionQuestion(ctx context.Context, sessionID, id, questionID uuid.UUID, rationale entity.QuestionRationale) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceSessionQuestion", ctx, sessionID, id, questionID, rationale)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceSessionQuestion indicates an expected call of ReplaceSessionQuestion.
+func (mr *MockPracticeSessionRepositoryMockRecorder) ReplaceSessionQuestion(ctx, sessionID, id, questionID, rationale any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceSessionQuestion", reflect.TypeOf((*MockPracticeSessionRepository)(nil).ReplaceSessionQuestion), ctx, sessionID, id, questionID, rationale)
+}
+
+// SessionTopicStats mocks base method.
+func (m *MockPracticeSessionRepository) SessionTopicStats(ctx context.Context, sessionID uuid.UUID) ([]repo.SessionTopicStat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SessionTopicStats", ctx, sessionID)
+	ret0, _ := ret[0].([]repo.SessionTopicStat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SessionTopicStats indicates an expected call of SessionTopicStats.
+func (mr *MockPracticeSessionRepositoryMockRecorder) SessionTopicStats(ctx, sessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SessionTopicStats", reflect.TypeOf((*MockPracticeSessionRepository)(nil).SessionTopicStats), ctx, sessionID)
+}
+
+// TopicMastery mocks base method.
+func (m *MockPracticeSessionRepository) TopicMastery(ctx context.Context, userID uuid.UUID, exam entity.ExamCategory, subjectIDs, topicIDs []uuid.UUID) ([]repo.TopicMastery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TopicMastery", ctx, userID, exam, subjectIDs, topicIDs)
+	ret0, _ := ret[0].([]repo.TopicMastery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TopicMastery indicates an expected call of TopicMastery.
+func (mr *MockPracticeSessionRepositoryMockRecorder) TopicMastery(ctx, userID, exam, subjectIDs, topicIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TopicMastery", reflect.TypeOf((*MockPracticeSessionRepository)(nil).TopicMastery), ctx, userID, exam, subjectIDs, topicIDs)
+}
+
+// MockRevisionRepository is a mock of RevisionRepository interface.
+type MockRevisionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRevisionRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRevisionRepositoryMockRecorder is the mock recorder for MockRevisionRepository.
+type MockRevisionRepositoryMockRecorder struct {
+	mock *MockRevisionRepository
+}
+
+// NewMockRevisionRepository creates a new mock instance.
+func NewMockRevisionRepository(ctrl *gomock.Controller) *MockRevisionRepository {
+	mock := &MockRevisionRepository{ctrl: ctrl}
+	mock.recorder = &MockRevisionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRevisionRepository) EXPECT() *MockRevisionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountReviewsSince mocks base method.
+func (m *MockRevisionRepository) CountReviewsSince(ctx context.Context, userID uuid.UUID, since time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountReviewsSince", ctx, userID, since)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountReviewsSince indicates an expected call of CountReviewsSince.
+func (mr *MockRevisionRepositoryMockRecorder) CountReviewsSince(ctx, userID, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountReviewsSince", reflect.TypeOf((*MockRevisionRepository)(nil).CountReviewsSince), ctx, userID, since)
+}
+
+// Get mocks base method.
+func (m *MockRevisionRepository) Get(ctx context.Context, id uuid.UUID) (entity.RevisionItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(entity.RevisionItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockRevisionRepositoryMockRecorder) Get(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRevisionRepository)(nil).Get), ctx, id)
+}
+
+// GetDailyCap mocks base method.
+func (m *MockRevisionRepository) GetDailyCap(ctx context.Context, userID uuid.UUID) (*int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDailyCap", ctx, userID)
+	ret0, _ := ret[0].(*int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDailyCap indicates an expected call of GetDailyCap.
+func (mr *MockRevisionRepositoryMockRecorder) GetDailyCap(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDailyCap", reflect.TypeOf((*MockRevisionRepository)(nil).GetDailyCap), ctx, userID)
+}
+
+// ListDue mocks base method.
+func (m *MockRevisionRepository) ListDue(ctx context.Context, userID uuid.UUID) ([]entity.RevisionItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDue", ctx, userID)
+	ret0, _ := ret[0].([]entity.RevisionItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDue indicates an expected call of ListDue.
+func (mr *MockRevisionRepositoryMockRecorder) ListDue(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDue", reflect.TypeOf((*MockRevisionRepository)(nil).ListDue), ctx, userID)
+}
+
+// ListReviewHistory mocks base method.
+func (m *MockRevisionRepository) ListReviewHistory(ctx context.Context, userID uuid.UUID) ([]repo.RevisionReviewLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReviewHistory", ctx, userID)
+	ret0, _ := ret[0].([]repo.RevisionReviewLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReviewHistory indicates an expected call of ListReviewHistory.
+func (mr *MockRevisionRepositoryMockRecorder) ListReviewHistory(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReviewHistory", reflect.TypeOf((*MockRevisionRepository)(nil).ListReviewHistory), ctx, userID)
+}
+
+// ListSchedule mocks base method.
+func (m *MockRevisionRepository) ListSchedule(ctx context.Context, userID uuid.UUID, until time.Time) ([]time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSchedule", ctx, userID, until)
+	ret0, _ := ret[0].([]time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSchedule indicates an expected call of ListSchedule.
+func (mr *MockRevisionRepositoryMockRecorder) ListSchedule(ctx, userID, until any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSchedule", reflect.TypeOf((*MockRevisionRepository)(nil).ListSchedule), ctx, userID, until)
+}
+
+// Review mocks base method.
+func (m *MockRevisionRepository) Review(ctx context.Context, item entity.RevisionItem, dailyCap int, since time.Time) (entity.RevisionItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Review", ctx, item, dailyCap, since)
+	ret0, _ := ret[0].(entity.RevisionItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Review indicates an expected call of Review.
+func (mr *MockRevisionRepositoryMockRecorder) Review(ctx, item, dailyCap, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Review", reflect.TypeOf((*MockRevisionRepository)(nil).Review), ctx, item, dailyCap, since)
+}
+
+// SetDailyCap mocks base method.
+func (m *MockRevisionRepository) SetDailyCap(ctx context.Context, userID uuid.UUID, dailyCap *int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDailyCap", ctx, userID, dailyCap)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDailyCap indicates an expected call of SetDailyCap.
+func (mr *MockRevisionRepositoryMockRecorder) SetDailyCap(ctx, userID, dailyCap any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDailyCap", reflect.TypeOf((*MockRevisionRepository)(nil).SetDailyCap), ctx, userID, dailyCap)
+}
+
+// MockExamRepository is a mock of ExamRepository interface.
+type MockExamRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockExamRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockExamRepositoryMockRecorder is the mock recorder for MockExamRepository.
+type MockExamRepositoryMockRecorder struct {
+	mock *MockExamRepository
+}
+
+// NewMockExamRepository creates a new mock instance.
+func NewMockExamRepository(ctrl *gomock.Controller) *MockExamRepository {
+	mock := &MockExamRepository{ctrl: ctrl}
+	mock.recorder = &MockExamRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExamRepository) EXPECT() *MockExamRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Cancel mocks base method.
+func (m *MockExamRepository) Cancel(ctx context.Context, examID, userID uuid.UUID, percent int) (entity.ExamRegistration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", ctx, examID, userID, percent)
+	ret0, _ := ret[0].(entity.ExamRegistration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockExamRepositoryMockRecorder) Cancel(ctx, examID, userID, percent any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockExamRepository)(nil).Cancel), ctx, examID, userID, percent)
+}
+
+// CreateConfig mocks base method.
+func (m *MockExamRepository) CreateConfig(ctx context.Context, config entity.ExamConfig) (entity.ExamConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateConfig", ctx, config)
+	ret0, _ := ret[0].(entity.ExamConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateConfig indicates an expected call of CreateConfig.
+func (mr *MockExamRepositoryMockRecorder) CreateConfig(ctx, config any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateConfig", reflect.TypeOf((*MockExamRepository)(nil).CreateConfig), ctx, config)
+}
+
+// DeleteConfig mocks base method.
+func (m *MockExamRepository) DeleteConfig(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteConfig", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteConfig indicates an expected call of DeleteConfig.
+func (mr *MockExamRepositoryMockRecorder) DeleteConfig(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteConfig", reflect.TypeOf((*MockExamRepository)(nil).DeleteConfig), ctx, id)
+}
+
+// GetCategory mocks base method.
+func (m *MockExamRepository) GetCategory(ctx context.Context, exam entity.ExamCategory) (entity.ExamCategorySettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCategory", ctx, exam)
+	ret0, _ := ret[0].(entity.ExamCategorySettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCategory indicates an expected call of GetCategory.
+func (mr *MockExamRepositoryMockRecorder) GetCategory(ctx, exam any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategory", reflect.TypeOf((*MockExamRepository)(nil).GetCategory), ctx, exam)
+}
+
+// GetConfig mocks base method.
+func (m *MockExamRepository) GetConfig(ctx context.Context, id uuid.UUID) (entity.ExamConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfig", ctx, id)
+	ret0, _ := ret[0].(entity.ExamConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfig indicates an expected call of GetConfig.
+func (mr *MockExamRepositoryMockRecorder) GetConfig(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfig", reflect.TypeOf((*MockExamRepository)(nil).GetConfig), ctx, id)
+}
+
+// GetRegistration mocks base method.
+func (m *MockExamRepository) GetRegistration(ctx context.Context, examID, userID uuid.UUID) (entity.ExamRegistration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRegistration", ctx, examID, userID)
+	ret0, _ := ret[0].(entity.ExamRegistration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRegistration indicates an expected call of GetRegistration.
+func (mr *MockExamRepositoryMockRecorder) GetRegistration(ctx, examID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRegistration", reflect.TypeOf((*MockExamRepository)(nil).GetRegistration), ctx, examID, userID)
+}
+
+// ListCategories mocks base method.
+func (m *MockExamRepository) ListCategories(ctx context.Context) ([]entity.ExamCategorySettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCategories", ctx)
+	ret0, _ := ret[0].([]entity.ExamCategorySettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCategories indicates an expected call of ListCategories.
+func (mr *MockExamRepositoryMockRecorder) ListCategories(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCategories", reflect.TypeOf((*MockExamRepository)(nil).ListCategories), ctx)
+}
+
+// ListConfigs mocks base method.
+func (m *MockExamRepository) ListConfigs(ctx context.Context, exam *entity.ExamCategory) ([]entity.ExamConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListConfigs", ctx, exam)
+	ret0, _ := ret[0].([]entity.ExamConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListConfigs indicates an expected call of ListConfigs.
+func (mr *MockExamRepositoryMockRecorder) ListConfigs(ctx, exam any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListConfigs", reflect.TypeOf((*MockExamRepository)(nil).ListConfigs), ctx, exam)
+}
+
+// ListSummaries mocks base method.
+func (m *MockExamRepository) ListSummaries(ctx context.Context, userID uuid.UUID) ([]entity.ExamSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSummaries", ctx, userID)
+	ret0, _ := ret[0].([]entity.ExamSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSummaries indicates an expected call of ListSummaries.
+func (mr *MockExamRepositoryMockRecorder) ListSummaries(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSummaries", reflect.TypeOf((*MockExamRepository)(nil).ListSummaries), ctx, userID)
+}
+
+// Register mocks base method.
+func (m *MockExamRepository) Register(ctx context.Context, examID, userID uuid.UUID, couponCode string) (entity.ExamRegistration, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Register", ctx, examID, userID, couponCode)
+	ret0, _ := ret[0].(entity.ExamRegistration)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Register indicates an expected call of Register.
+func (mr *MockExamRepositoryMockRecorder) Register(ctx, examID, userID, couponCode any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockExamRepository)(nil).Register), ctx, examID, userID, couponCode)
+}
+
+// UpdateCategory mocks base method.
+func (m *MockExamRepository) UpdateCategory(ctx context.Context, settings entity.ExamCategorySettings) (entity.ExamCategorySettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCategory", ctx, settings)
+	ret0, _ := ret[0].(entity.ExamCategorySettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCategory indicates an expected call of UpdateCategory.
+func (mr *MockExamRepositoryMockRecorder) UpdateCategory(ctx, settings any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCategory", reflect.TypeOf((*MockExamRepository)(nil).UpdateCategory), ctx, settings)
+}
+
+// UpdateConfig mocks base method.
+func (m *MockExamRepository) UpdateConfig(ctx context.Context, config entity.ExamConfig, from entity.ExamStatus) (entity.ExamConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateConfig", ctx, config, from)
+	ret0, _ := ret[0].(entity.ExamConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateConfig indicates an expected call of UpdateConfig.
+func (mr *MockExamRepositoryMockRecorder) UpdateConfig(ctx, config, from any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateConfig", reflect.TypeOf((*MockExamRepository)(nil).UpdateConfig), ctx, config, from)
+}
+
+// MockExamAttemptRepository is a mock of ExamAttemptRepository interface.
+type MockExamAttemptRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockExamAttemptRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockExamAttemptRepositoryMockRecorder is the mock recorder for MockExamAttemptRepository.
+type MockExamAttemptRepositoryMockRecorder struct {
+	mock *MockExamAttemptRepository
+}
+
+// NewMockExamAttemptRepository creates a new mock instance.
+func NewMockExamAttemptRepository(ctrl *gomock.Controller) *MockExamAttemptRepository {
+	mock := &MockExamAttemptRepository{ctrl: ctrl}
+	mock.recorder = &MockExamAttemptRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExamAttemptRepository) EXPECT() *MockExamAttemptRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockExamAttemptRepository) Close(ctx context.Context, id uuid.UUID, status entity.ExamAttemptStatus, at time.Time) (entity.ExamAttempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close", ctx, id, status, at)
+	ret0, _ := ret[0].(entity.ExamAttempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockExamAttemptRepositoryMockRecorder) Close(ctx, id, status, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockExamAttemptRepository)(nil).Close), ctx, id, status, at)
+}
+
+// Get mocks base method.
+func (m *MockExamAttemptRepository) Get(ctx context.Context, examID, userID uuid.UUID) (entity.ExamAttempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, examID, userID)
+	ret0, _ := ret[0].(entity.ExamAttempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockExamAttemptRepositoryMockRecorder) Get(ctx, examID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockExamAttemptRepository)(nil).Get), ctx, examID, userID)
+}
+
+// GetQuestion mocks base method.
+func (m *MockExamAttemptRepository) GetQuestion(ctx context.Context, attemptID, id uuid.UUID) (entity.ExamAttemptQuestion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQuestion", ctx, attemptID, id)
+	ret0, _ := ret[0].(entity.ExamAttemptQuestion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQuestion indicates an expected call of GetQuestion.
+func (mr *MockExamAttemptRepositoryMockRecorder) GetQuestion(ctx, attemptID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuestion", reflect.TypeOf((*MockExamAttemptRepository)(nil).GetQuestion), ctx, attemptID, id)
+}
+
+// Grade mocks base method.
+func (m *MockExamAttemptRepository) Grade(ctx context.Context, attempt entity.ExamAttempt, questions []entity.ExamAttemptQuestion) (entity.ExamAttempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Grade", ctx, attempt, questions)
+	ret0, _ := ret[0].(entity.ExamAttempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Grade indicates an expected call of Grade.
+func (mr *MockExamAttemptRepositoryMockRecorder) Grade(ctx, attempt, questions any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Grade", reflect.TypeOf((*MockExamAttemptRepository)(nil).Grade), ctx, attempt, questions)
+}
+
+// ListPaper mocks base method.
+func (m *MockExamAttemptRepository) ListPaper(ctx context.Context, examID uuid.UUID) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPaper", ctx, examID)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPaper indicates an expected call of ListPaper.
+func (mr *MockExamAttemptRepositoryMockRecorder) ListPaper(ctx, examID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPaper", reflect.TypeOf((*MockExamAttemptRepository)(nil).ListPaper), ctx, examID)
+}
+
+// ListQuestions mocks base method.
+func (m *MockExamAttemptRepository) ListQuestions(ctx context.Context, attemptID uuid.UUID) ([]entity.ExamAttemptQuestion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListQuestions", ctx, attemptID)
+	ret0, _ := ret[0].([]entity.ExamAttemptQuestion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListQuestions indicates an expected call of ListQuestions.
+func (mr *MockExamAttemptRepositoryMockRecorder) ListQuestions(ctx, attemptID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListQuestions", reflect.TypeOf((*MockExamAttemptRepository)(nil).ListQuestions), ctx, attemptID)
+}
+
+// ListUngraded mocks base method.
+func (m *MockExamAttemptRepository) ListUngraded(ctx context.Context, now time.Time, limit int) ([]entity.ExamAttempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUngraded", ctx, now, limit)
+	ret0, _ := ret[0].([]entity.ExamAttempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUngraded indicates an expected call of ListUngraded.
+func (mr *MockExamAttemptRepositoryMockRecorder) ListUngraded(ctx, now, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUngraded", reflect.TypeOf((*MockExamAttemptRepository)(nil).ListUngraded), ctx, now, limit)
+}
+
+// SaveAnswer mocks base method.
+func (m *MockExamAttemptRepository) SaveAnswer(ctx context.Context, attemptID uuid.UUID, question entity.ExamAttemptQuestion, now time.Time) (entity.ExamAttemptQuestion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveAnswer", ctx, attemptID, question, now)
+	ret0, _ := ret[0].(entity.ExamAttemptQuestion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveAnswer indicates an expected call of SaveAnswer.
+func (mr *MockExamAttemptRepositoryMockRecorder) SaveAnswer(ctx, attemptID, question, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveAnswer", reflect.TypeOf((*MockExamAttemptRepository)(nil).SaveAnswer), ctx, attemptID, question, now)
+}
+
+// Start mocks base method.
+func (m *MockExamAttemptRepository) Start(ctx context.Context, attempt entity.ExamAttempt, paper []uuid.UUID) (entity.ExamAttempt, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Start", ctx, attempt, paper)
+	ret0, _ := ret[0].(entity.ExamAttempt)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockExamAttemptRepositoryMockRecorder) Start(ctx, attempt, paper any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockExamAttemptRepository)(nil).Start), ctx, attempt, paper)
+}
+
+// MockPodcastRepository is a mock of PodcastRepository interface.
+type MockPodcastRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPodcastRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockPodcastRepositoryMockRecorder is the mock recorder for MockPodcastRepository.
+type MockPodcastRepositoryMockRecorder struct {
+	mock *MockPodcastRepository
+}
+
+// NewMockPodcastRepository creates a new mock instance.
+func NewMockPodcastRepository(ctrl *gomock.Controller) *MockPodcastRepository {
+	mock := &MockPodcastRepository{ctrl: ctrl}
+	mock.recorder = &MockPodcastRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPodcastRepository) EXPECT() *MockPodcastRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockPodcastRepository) Create(ctx context.Context, episode entity.PodcastEpisode) (entity.PodcastEpisode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, episode)
+	ret0, _ := ret[0].(entity.PodcastEpisode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPodcastRepositoryMockRecorder) Create(ctx, episode any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPodcastRepository)(nil).Create), ctx, episode)
+}
+
+// Delete mocks base method.
+func (m *MockPodcastRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockPodcastRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPodcastRepository)(nil).Delete), ctx, id)
+}
+
+// Get mocks base method.
+func (m *MockPodcastRepository) Get(ctx context.Context, id uuid.UUID) (entity.PodcastEpisode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(entity.PodcastEpisode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockPodcastRepositoryMockRecorder) Get(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockPodcastRepository)(nil).Get), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockPodcastRepository) List(ctx context.Context, filter repo.PodcastFilter) ([]entity.PodcastEpisode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filter)
+	ret0, _ := ret[0].([]entity.PodcastEpisode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockPodcastRepositoryMockRecorder) List(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockPodcastRepository)(nil).List), ctx, filter)
+}
+
+// Update mocks base method.
+func (m *MockPodcastRepository) Update(ctx context.Context, episode entity.PodcastEpisode) (entity.PodcastEpisode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, episode)
+	ret0, _ := ret[0].(entity.PodcastEpisode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockPodcastRepositoryMockRecorder) Update(ctx, episode any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPodcastRepository)(nil).Update), ctx, episode)
+}
+
+// MockWalletRepository is a mock of WalletRepository interface.
+type MockWalletRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWalletRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockWalletRepositoryMockRecorder is the mock recorder for MockWalletRepository.
+type MockWalletRepositoryMockRecorder struct {
+	mock *MockWalletRepository
+}
+
+// NewMockWalletRepository creates a new mock instance.
+func NewMockWalletRepository(ctrl *gomock.Controller) *MockWalletRepository {
+	mock := &MockWalletRepository{ctrl: ctrl}
+	mock.recorder = &MockWalletRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWalletRepository) EXPECT() *MockWalletRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetSummary mocks base method.
+func (m *MockWalletRepository) GetSummary(ctx context.Context, userID uuid.UUID) (entity.WalletSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSummary", ctx, userID)
+	ret0, _ := ret[0].(entity.WalletSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSummary indicates an expected call of GetSummary.
+func (mr *MockWalletRepositoryMockRecorder) GetSummary(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSummary", reflect.TypeOf((*MockWalletRepository)(nil).GetSummary), ctx, userID)
+}
+
+// ListTransactions mocks base method.
+func (m *MockWalletRepository) ListTransactions(ctx context.Context, userID uuid.UUID) ([]entity.WalletTransaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransactions", ctx, userID)
+	ret0, _ := ret[0].([]entity.WalletTransaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransactions indicates an expected call of ListTransactions.
+func (mr *MockWalletRepositoryMockRecorder) ListTransactions(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransactions", reflect.TypeOf((*MockWalletRepository)(nil).ListTransactions), ctx, userID)
+}
+
+// MockCouponRepository is a mock of CouponRepository interface.
+type MockCouponRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCouponRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockCouponRepositoryMockRecorder is the mock recorder for MockCouponRepository.
+type MockCouponRepositoryMockRecorder struct {
+	mock *MockCouponRepository
+}
+
+// NewMockCouponRepository creates a new mock instance.
+func NewMockCouponRepository(ctrl *gomock.Controller) *MockCouponRepository {
+	mock := &MockCouponRepository{ctrl: ctrl}
+	mock.recorder = &MockCouponRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCouponRepository) EXPECT() *MockCouponRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockCouponRepository) Create(ctx context.Context, coupon entity.Coupon) (entity.Coupon, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, coupon)
+	ret0, _ := ret[0].(entity.Coupon)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCouponRepositoryMockRecorder) Create(ctx, coupon any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCouponRepository)(nil).Create), ctx, coupon)
+}
+
+// Delete mocks base method.
+func (m *MockCouponRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockCouponRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCouponRepository)(nil).Delete), ctx, id)
+}
+
+// Get mocks base method.
+func (m *MockCouponRepository) Get(ctx context.Context, id uuid.UUID) (entity.Coupon, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(entity.Coupon)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockCouponRepositoryMockRecorder) Get(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockCouponRepository)(nil).Get), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockCouponRepository) List(ctx context.Context) ([]entity.Coupon, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]entity.Coupon)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockCouponRepositoryMockRecorder) List(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockCouponRepository)(nil).List), ctx)
+}
+
+// Redeem mocks base method.
+func (m *MockCouponRepository) Redeem(ctx context.Context, code string, userID uuid.UUID) (entity.WalletSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Redeem", ctx, code, userID)
+	ret0, _ := ret[0].(entity.WalletSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Redeem indicates an expected call of Redeem.
+func (mr *MockCouponRepositoryMockRecorder) Redeem(ctx, code, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Redeem", reflect.TypeOf((*MockCouponRepository)(nil).Redeem), ctx, code, userID)
+}
+
+// Update mocks base method.
+func (m *MockCouponRepository) Update(ctx context.Context, coupon entity.Coupon) (entity.Coupon, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, coupon)
+	ret0, _ := ret[0].(entity.Coupon)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockCouponRepositoryMockRecorder) Update(ctx, coupon any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCouponRepository)(nil).Update), ctx, coupon)
+}
+
+// MockReferralRepository is a mock of ReferralRepository interface.
+type MockReferralRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockReferralRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockReferralRepositoryMockRecorder is the mock recorder for MockReferralRepository.
+type MockReferralRepositoryMockRecorder struct {
+	mock *MockReferralRepository
+}
+
+// NewMockReferralRepository creates a new mock instance.
+func NewMockReferralRepository(ctrl *gomock.Controller) *MockReferralRepository {
+	mock := &MockReferralRepository{ctrl: ctrl}
+	mock.recorder = &MockReferralRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReferralRepository) EXPECT() *MockReferralRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetSummary mocks base method.
+func (m *MockReferralRepository) GetSummary(ctx context.Context, userID uuid.UUID) (entity.ReferralSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSummary", ctx, userID)
+	ret0, _ := ret[0].(entity.ReferralSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSummary indicates an expected call of GetSummary.
+func (mr *MockReferralRepositoryMockRecorder) GetSummary(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSummary", reflect.TypeOf((*MockReferralRepository)(nil).GetSummary), ctx, userID)
+}
+
+// MockAISettingsRepository is a mock of AISettingsRepository interface.
+type MockAISettingsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAISettingsRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAISettingsRepositoryMockRecorder is the mock recorder for MockAISettingsRepository.
+type MockAISettingsRepositoryMockRecorder struct {
+	mock *MockAISettingsRepository
+}
+
+// NewMockAISettingsRepository creates a new mock instance.
+func NewMockAISettingsRepository(ctrl *gomock.Controller) *MockAISettingsRepository {
+	mock := &MockAISettingsRepository{ctrl: ctrl}
+	mock.recorder = &MockAISettingsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAISettingsRepository) EXPECT() *MockAISettingsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockAISettingsRepository) Get(ctx context.Context) (entity.AISettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx)
+	ret0, _ := ret[0].(entity.AISettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockAISettingsRepositoryMockRecorder) Get(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockAISettingsRepository)(nil).Get), ctx)
+}
+
+// Update mocks base method.
+func (m *MockAISettingsRepository) Update(ctx context.Context, settings entity.AISettings) (entity.AISettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, settings)
+	ret0, _ := ret[0].(entity.AISettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockAISettingsRepositoryMockRecorder) Update(ctx, settings any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockAISettingsRepository)(nil).Update), ctx, settings)
+}
+
+// MockAnalyticsRepository is a mock of AnalyticsRepository interface.
+type MockAnalyticsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAnalyticsRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAnalyticsRepositoryMockRecorder is the mock recorder for MockAnalyticsRepository.
+type MockAnalyticsRepositoryMockRecorder struct {
+	mock *MockAnalyticsRepository
+}
+
+// NewMockAnalyticsRepository creates a new mock instance.
+func NewMockAnalyticsRepository(ctrl *gomock.Controller) *MockAnalyticsRepository {
+	mock := &MockAnalyticsRepository{ctrl: ctrl}
+	mock.recorder = &MockAnalyticsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAnalyticsRepository) EXPECT() *MockAnalyticsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Overview mocks base method.
+func (m *MockAnalyticsRepository) Overview(ctx context.Context, filter repo.AnalyticsFilter) (entity.AnalyticsOverview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Overview", ctx, filter)
+	ret0, _ := ret[0].(entity.AnalyticsOverview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Overview indicates an expected call of Overview.
+func (mr *MockAnalyticsRepositoryMockRecorder) Overview(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Overview", reflect.TypeOf((*MockAnalyticsRepository)(nil).Overview), ctx, filter)
+}
+
+// MockLeaderboardRepository is a mock of LeaderboardRepository interface.
+type MockLeaderboardRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLeaderboardRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockLeaderboardRepositoryMockRecorder is the mock recorder for MockLeaderboardRepository.
+type MockLeaderboardRepositoryMockRecorder struct {
+	mock *MockLeaderboardRepository
+}
+
+// NewMockLeaderboardRepository creates a new mock instance.
+func NewMockLeaderboardRepository(ctrl *gomock.Controller) *MockLeaderboardRepository {
+	mock := &MockLeaderboardRepository{ctrl: ctrl}
+	mock.recorder = &MockLeaderboardRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLeaderboardRepository) EXPECT() *MockLeaderboardRepositoryMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockLeaderboardRepository) List(ctx context.Context, limit int) ([]entity.LeaderboardEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, limit)
+	ret0, _ := ret[0].([]entity.LeaderboardEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockLeaderboardRepositoryMockRecorder) List(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockLeaderboardRepository)(nil).List), ctx, limit)
+}
+
+// Stats mocks base method.
+func (m *MockLeaderboardRepository) Stats(ctx context.Context) (entity.LeaderboardStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stats", ctx)
+	ret0, _ := ret[0].(entity.LeaderboardStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stats indicates an expected call of Stats.
+func (mr *MockLeaderboardRepositoryMockRecorder) Stats(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockLeaderboardRepository)(nil).Stats), ctx)
+}
+
+// MockFeedRepository is a mock of FeedRepository interface.
+type MockFeedRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockFeedRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockFeedRepositoryMockRecorder is the mock recorder for MockFeedRepository.
+type MockFeedRepositoryMockRecorder struct {
+	mock *MockFeedRepository
+}
+
+// NewMockFeedRepository creates a new mock instance.
+func NewMockFeedRepository(ctrl *gomock.Controller) *MockFeedRepository {
+	mock := &MockFeedRepository{ctrl: ctrl}
+	mock.recorder = &MockFeedRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFeedRepository) EXPECT() *MockFeedRepositoryMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockFeedRepository) List(ctx context.Context) ([]entity.FeedPost, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]entity.FeedPost)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockFeedRepositoryMockRecorder) List(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockFeedRepository)(nil).List), ctx)
+}
@@ -0,0 +1,67 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/repo"
+)
+
+const (
+	_defaultLimit = 50
+	_maxLimit     = 200
+)
+
+// ErrNotFound when the user has no such notification.
+var ErrNotFound = errors.New("notification not found")
+
+// UseCase delivers in-app notifications to users.
+type UseCase struct {
+	repo repo.NotificationRepository
+}
+
+// New constructs UseCase.
+func New(repo repo.NotificationRepository) *UseCase {
+	return &UseCase{repo: repo}
+}
+
+// Notify stores notifications for their users.
+func (uc *UseCase) Notify(ctx context.Context, notifications []entity.Notification) error {
+	if err := uc.repo.Create(ctx, notifications); err != nil {
+		return fmt.Errorf("notification - Create: %w", err)
+	}
+
+	return nil
+}
+
+// List returns a user's most recent notifications, newest first. A limit
+// outside 1 to 200 falls back to 50.
+func (uc *UseCase) List(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit int) ([]entity.Notification, error) {
+	if limit < 1 || limit > _maxLimit {
+		limit = _defaultLimit
+	}
+
+	notifications, err := uc.repo.ListByUser(ctx, userID, unreadOnly, limit)
+	if err != nil {
+		return nil, fmt.Errorf("notification - ListByUser: %w", err)
+	}
+
+	return notifications, nil
+}
+
+// MarkRead marks one of a user's notifications read.
+func (uc *UseCase) MarkRead(ctx context.Context, userID, id uuid.UUID) error {
+	err := uc.repo.MarkRead(ctx, userID, id)
+	if errors.Is(err, repo.ErrNotFound) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("notification - MarkRead: %w", err)
+	}
+
+	return nil
+}
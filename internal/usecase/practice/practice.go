@@ -2,6 +2,7 @@ package practice
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -9,29 +10,105 @@ import (
 
 	"github.com/evrone/go-clean-template/internal/entity"
 	"github.com/evrone/go-clean-template/internal/repo"
+	"github.com/evrone/go-clean-template/internal/usecase/localization"
+	"github.com/evrone/go-clean-template/internal/usecase/media"
+	"github.com/evrone/go-clean-template/internal/usecase/revision"
+	"github.com/evrone/go-clean-template/internal/usecase/scoring"
+)
+
+var (
+	// ErrExamRequired when neither the request nor the user profile names an exam.
+	ErrExamRequired = errors.New("exam is required")
+	// ErrNoQuestions when the blueprint matches no active questions at all.
+	ErrNoQuestions = errors.New("no questions match the requested filters")
+	// ErrSessionClosed when a completed, abandoned or expired session is modified.
+	ErrSessionClosed = errors.New("session is no longer in progress")
+	// ErrNotFound when the session, or the question within it, does not exist.
+	ErrNotFound = errors.New("session not found")
+	// ErrForbidden when the session belongs to another user.
+	ErrForbidden = errors.New("session belongs to another user")
+	// ErrAlreadyAnswered when a different answer is sent for an answered question.
+	ErrAlreadyAnswered = errors.New("question already answered")
+	// ErrInvalidAnswer when the answer does not fit the question type.
+	ErrInvalidAnswer = errors.New("answer does not match the question type")
 )
 
 // UseCase orchestrates practice flows.
 type UseCase struct {
-	sessions repo.PracticeSessionRepository
+	sessions  repo.PracticeSessionRepository
+	questions repo.QuestionRepository
+	exams     repo.ExamRepository
+	settings  repo.AISettingsRepository
+	revisions repo.RevisionRepository
+	media     *media.UseCase
+	localizer *localization.UseCase
 }
 
 // New constructs UseCase.
-func New(sessions repo.PracticeSessionRepository) *UseCase {
-	return &UseCase{sessions: sessions}
+func New(
+	sessions repo.PracticeSessionRepository,
+	questions repo.QuestionRepository,
+	exams repo.ExamRepository,
+	settings repo.AISettingsRepository,
+	revisions repo.RevisionRepository,
+	media *media.UseCase,
+	localizer *localization.UseCase,
+) *UseCase {
+	return &UseCase{
+		sessions:  sessions,
+		questions: questions,
+		exams:     exams,
+		settings:  settings,
+		revisions: revisions,
+		media:     media,
+		localizer: localizer,
+	}
 }
 
-// CreateSession starts a new session.
+// CreateSession selects questions for the requested blueprint and starts a new
+// session. Smart sessions pick questions from the user's topic mastery instead.
 func (uc *UseCase) CreateSession(ctx context.Context, userID uuid.UUID, req entity.PracticeSessionCreateRequest) (entity.PracticeSession, error) {
+	if req.Exam == "" {
+		return entity.PracticeSession{}, ErrExamRequired
+	}
+
+	now := time.Now().UTC()
+	b := newBlueprint(userID, req)
+
+	var questions []entity.PracticeSessionQuestion
+	if req.Mode == entity.PracticeModeSmart {
+		picks, err := uc.selectSmart(ctx, b, now)
+		if err != nil {
+			return entity.PracticeSession{}, fmt.Errorf("practice - selectSmart: %w", err)
+		}
+		questions = picks
+	} else {
+		ids, err := uc.selectQuestions(ctx, b, now)
+		if err != nil {
+			return entity.PracticeSession{}, fmt.Errorf("practice - selectQuestions: %w", err)
+		}
+		for _, id := range ids {
+			questions = append(questions, entity.PracticeSessionQuestion{Question: entity.Question{ID: id}})
+		}
+	}
+
+	if len(questions) == 0 {
+		return entity.PracticeSession{}, ErrNoQuestions
+	}
+
+	planned := len(questions)
 	session := entity.PracticeSession{
-		ID:        uuid.New(),
-		Mode:      req.Mode,
-		Exam:      req.Exam,
-		Status:    entity.PracticeStatusInProgress,
-		StartedAt: time.Now().UTC(),
+		ID:                    uuid.New(),
+		UserID:                userID,
+		Mode:                  req.Mode,
+		Exam:                  req.Exam,
+		Status:                entity.PracticeStatusInProgress,
+		TotalQuestionsPlanned: &planned,
+		TimeLimitMinutes:      req.TimeLimitMinutes,
+		StartedAt:             now,
 	}
 
-	created, err := uc.sessions.CreateSession(ctx, session)
+	created, err := uc.sessions.CreateSession(ctx, session, questions)
 	if err != nil {
 		return entity.PracticeSession{}, fmt.Errorf("practice - CreateSession: %w", err)
 	}
@@ -51,9 +128,9 @@ func (uc *UseCase) ListSessions(ctx context.Context, userID uuid.UUID) ([]entity
 
 // GetSessionDetail returns session questions.
 func (uc *UseCase) GetSessionDetail(ctx context.Context, sessionID uuid.UUID, userID uuid.UUID) (entity.PracticeSessionDetail, error) {
-	session, err := uc.sessions.GetSession(ctx, sessionID)
+	session, err := uc.ownedSession(ctx, sessionID, userID)
 	if err != nil {
-		return entity.PracticeSessionDetail{}, fmt.Errorf("practice - GetSession: %w", err)
+		return entity.PracticeSessionDetail{}, err
 	}
 
 	questions, err := uc.sessions.ListSessionQuestions(ctx, sessionID)
@@ -61,25 +138,281 @@ func (uc *UseCase) GetSessionDetail(ctx context.Context, sessionID uuid.UUID, us
 		return entity.PracticeSessionDetail{}, fmt.Errorf("practice - ListSessionQuestions: %w", err)
 	}
 
+	if err := uc.attachMedia(ctx, questions); err != nil {
+		return entity.PracticeSessionDetail{}, err
+	}
+
+	if err := uc.localize(ctx, userID, questions); err != nil {
+		return entity.PracticeSessionDetail{}, err
+	}
+
 	return entity.PracticeSessionDetail{Session: session, Questions: questions}, nil
 }
 
-// AnswerQuestion records response.
+// AnswerQuestion records the first answer to a session question, logs it as an
+// attempt and queues it for revision when it was missed or guessed.
 func (uc *UseCase) AnswerQuestion(ctx context.Context, sessionID uuid.UUID, req entity.PracticeAnswerRequest, userID uuid.UUID) (entity.PracticeSessionQuestion, error) {
-	question, err := uc.sessions.GetSessionQuestion(ctx, req.SessionQuestionID)
+	session, err := uc.ownedSession(ctx, sessionID, userID)
+	if err != nil {
+		return entity.PracticeSessionQuestion{}, err
+	}
+
+	question, err := uc.sessions.GetSessionQuestion(ctx, sessionID, req.SessionQuestionID)
+	if errors.Is(err, repo.ErrNotFound) {
+		return entity.PracticeSessionQuestion{}, ErrNotFound
+	}
 	if err != nil {
 		return entity.PracticeSessionQuestion{}, fmt.Errorf("practice - GetSessionQuestion: %w", err)
 	}
 
-	correct := question.Question.CorrectOption == req.SelectedOption
-	question.SelectedOption = &req.SelectedOption
+	answer := answerFromRequest(req)
+
+	// Retries of an answer that was already stored succeed even after the
+	// session closed, so clients can safely resend on timeouts.
+	if question.AnsweredAt != nil {
+		return replayAnswer(question, answer)
+	}
+
+	if err := scoring.Validate(question.Question, answer); err != nil {
+		return entity.PracticeSessionQuestion{}, ErrInvalidAnswer
+	}
+
+	if err := uc.ensureOpen(ctx, session); err != nil {
+		return entity.PracticeSessionQuestion{}, err
+	}
+
+	category, err := uc.exams.GetCategory(ctx, question.Question.Exam)
+	if err != nil {
+		return entity.PracticeSessionQuestion{}, fmt.Errorf("practice - GetCategory: %w", err)
+	}
+
+	settings, err := uc.settings.Get(ctx)
+	if err != nil {
+		return entity.PracticeSessionQuestion{}, fmt.Errorf("practice - AISettings.Get: %w", err)
+	}
+
+	now := time.Now().UTC()
+	score, correct := scoring.Grade(question.Question, answer, category.MultiSelectScoring)
+
+	var queued *entity.RevisionItem
+	if item, ok := revision.FirstReview(settings, correct, req.IsGuess, now); ok {
+		if settings.RevisionLoadSmoothing {
+			schedule, err := uc.revisions.ListSchedule(ctx, userID, revision.SmoothingHorizon(item, now))
+			if err != nil {
+				return entity.PracticeSessionQuestion{}, fmt.Errorf("practice - ListSchedule: %w", err)
+			}
+			item = revision.Balance(item, schedule, now)
+		}
+		queued = &item
+	}
+
+	if len(answer.SelectedOptions) > 0 {
+		question.SelectedOption = &answer.SelectedOptions[0]
+	}
+	question.SelectedOptions = answer.SelectedOptions
+	question.NumericAnswer = answer.NumericAnswer
+	question.MatrixAnswer = answer.MatrixAnswer
 	question.IsCorrect = &correct
+	question.Score = &score
 	question.TimeTakenMs = req.TimeTakenMs
+	question.IsGuess = req.IsGuess
+	question.AnsweredAt = &now
 
-	updated, err := uc.sessions.UpdateSessionQuestion(ctx, question)
+	answered, err := uc.sessions.AnswerSessionQuestion(ctx, sessionID, userID, question, queued)
+	if errors.Is(err, repo.ErrNotFound) {
+		// A concurrent submission stored its answer first.
+		current, err := uc.sessions.GetSessionQuestion(ctx, sessionID, req.SessionQuestionID)
+		if err != nil {
+			return entity.PracticeSessionQuestion{}, fmt.Errorf("practice - GetSessionQuestion: %w", err)
+		}
+
+		return replayAnswer(current, answer)
+	}
 	if err != nil {
-		return entity.PracticeSessionQuestion{}, fmt.Errorf("practice - UpdateSessionQuestion: %w", err)
+		return entity.PracticeSessionQuestion{}, fmt.Errorf("practice - AnswerSessionQuestion: %w", err)
+	}
+
+	if session.Mode == entity.PracticeModeSmart {
+		if err := uc.adapt(ctx, session, answered); err != nil {
+			return entity.PracticeSessionQuestion{}, err
+		}
+	}
+
+	// The answer reveals the explanation, which may have images of its own.
+	answeredQuestions := []entity.PracticeSessionQuestion{answered}
+	if err := uc.attachMedia(ctx, answeredQuestions); err != nil {
+		return entity.PracticeSessionQuestion{}, err
+	}
+
+	if err := uc.localize(ctx, userID, answeredQuestions); err != nil {
+		return entity.PracticeSessionQuestion{}, err
+	}
+
+	return answeredQuestions[0], nil
+}
+
+// attachMedia loads the images of the questions with fresh download links.
+func (uc *UseCase) attachMedia(ctx context.Context, questions []entity.PracticeSessionQuestion) error {
+	ids := make([]uuid.UUID, len(questions))
+	for i := range questions {
+		ids[i] = questions[i].Question.ID
+	}
+
+	byQuestion, err := uc.media.ForQuestions(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("practice - media.ForQuestions: %w", err)
+	}
+
+	for i := range questions {
+		questions[i].Question.Media = byQuestion[questions[i].Question.ID]
+	}
+
+	return nil
+}
+
+// localize serves the questions in the user's preferred language where a
+// translation of the served version exists.
+func (uc *UseCase) localize(ctx context.Context, userID uuid.UUID, questions []entity.PracticeSessionQuestion) error {
+	served := make([]*entity.Question, len(questions))
+	for i := range questions {
+		served[i] = &questions[i].Question
+	}
+
+	if err := uc.localizer.Localize(ctx, userID, served); err != nil {
+		return fmt.Errorf("practice - localizer.Localize: %w", err)
+	}
+
+	return nil
+}
+
+// CompleteSession marks the session completed and returns its result summary.
+func (uc *UseCase) CompleteSession(ctx context.Context, sessionID uuid.UUID, userID uuid.UUID) (entity.PracticeSessionResult, error) {
+	return uc.finish(ctx, sessionID, userID, entity.PracticeStatusCompleted)
+}
+
+// AbandonSession marks the session abandoned and returns its result summary.
+func (uc *UseCase) AbandonSession(ctx context.Context, sessionID uuid.UUID, userID uuid.UUID) (entity.PracticeSessionResult, error) {
+	return uc.finish(ctx, sessionID, userID, entity.PracticeStatusAbandoned)
+}
+
+// GetSessionResult returns the result summary of a session in any state.
+func (uc *UseCase) GetSessionResult(ctx context.Context, sessionID uuid.UUID, userID uuid.UUID) (entity.PracticeSessionResult, error) {
+	session, err := uc.ownedSession(ctx, sessionID, userID)
+	if err != nil {
+		return entity.PracticeSessionResult{}, err
+	}
+
+	return uc.result(ctx, session)
+}
+
+// ExpireSessions abandons every timed session whose time limit has passed.
+func (uc *UseCase) ExpireSessions(ctx context.Context) (int, error) {
+	expired, err := uc.sessions.ExpireSessions(ctx, time.Now().UTC())
+	if err != nil {
+		return 0, fmt.Errorf("practice - ExpireSessions: %w", err)
+	}
+
+	return expired, nil
+}
+
+func (uc *UseCase) finish(ctx context.Context, sessionID, userID uuid.UUID, status entity.PracticeSessionStatus) (entity.PracticeSessionResult, error) {
+	session, err := uc.ownedSession(ctx, sessionID, userID)
+	if err != nil {
+		return entity.PracticeSessionResult{}, err
+	}
+
+	if err := uc.ensureOpen(ctx, session); err != nil {
+		return entity.PracticeSessionResult{}, err
+	}
+
+	finished, err := uc.sessions.FinishSession(ctx, sessionID, status, time.Now().UTC())
+	if errors.Is(err, repo.ErrNotFound) {
+		// Lost a race with another finish request or the expiry job.
+		return entity.PracticeSessionResult{}, ErrSessionClosed
+	}
+	if err != nil {
+		return entity.PracticeSessionResult{}, fmt.Errorf("practice - FinishSession: %w", err)
+	}
+
+	return uc.result(ctx, finished)
+}
+
+// ownedSession loads the session and checks that it belongs to userID.
+func (uc *UseCase) ownedSession(ctx context.Context, sessionID, userID uuid.UUID) (entity.PracticeSession, error) {
+	session, err := uc.sessions.GetSession(ctx, sessionID)
+	if errors.Is(err, repo.ErrNotFound) {
+		return entity.PracticeSession{}, ErrNotFound
+	}
+	if err != nil {
+		return entity.PracticeSession{}, fmt.Errorf("practice - GetSession: %w", err)
+	}
+
+	if session.UserID != userID {
+		return entity.PracticeSession{}, ErrForbidden
+	}
+
+	return session, nil
+}
+
+// ensureOpen rejects sessions that are finished, abandoning timed sessions
+// whose deadline passed before the expiry job got to them.
+func (uc *UseCase) ensureOpen(ctx context.Context, session entity.PracticeSession) error {
+	if session.Status != entity.PracticeStatusInProgress {
+		return ErrSessionClosed
+	}
+
+	deadline, timed := sessionDeadline(session)
+	if !timed || time.Now().UTC().Before(deadline) {
+		return nil
+	}
+
+	_, err := uc.sessions.FinishSession(ctx, session.ID, entity.PracticeStatusAbandoned, deadline)
+	if err != nil && !errors.Is(err, repo.ErrNotFound) {
+		return fmt.Errorf("practice - FinishSession: %w", err)
+	}
+
+	return ErrSessionClosed
+}
+
+// replayAnswer returns the stored answer when answer repeats it. Answers are
+// final: a different answer for an answered question is rejected, so the
+// first attempt is what stats and the leaderboard see.
+func replayAnswer(question entity.PracticeSessionQuestion, answer entity.Answer) (entity.PracticeSessionQuestion, error) {
+	stored := entity.Answer{
+		SelectedOptions: question.SelectedOptions,
+		NumericAnswer:   question.NumericAnswer,
+		MatrixAnswer:    question.MatrixAnswer,
+	}
+	if len(stored.SelectedOptions) == 0 && question.SelectedOption != nil {
+		stored.SelectedOptions = []int{*question.SelectedOption}
+	}
+
+	if !scoring.SameAnswer(stored, answer) {
+		return entity.PracticeSessionQuestion{}, ErrAlreadyAnswered
+	}
+
+	return question, nil
+}
+
+// answerFromRequest accepts either the single selectedOption or the
+// selectedOptions set, alongside numeric and matrix answers.
+func answerFromRequest(req entity.PracticeAnswerRequest) entity.Answer {
+	answer := entity.Answer{
+		SelectedOptions: req.SelectedOptions,
+		NumericAnswer:   req.NumericAnswer,
+		MatrixAnswer:    req.MatrixAnswer,
+	}
+	if len(answer.SelectedOptions) == 0 && req.SelectedOption != 0 {
+		answer.SelectedOptions = []int{req.SelectedOption}
+	}
+
+	return scoring.NormalizeAnswer(answer)
+}
+
+func sessionDeadline(session entity.PracticeSession) (time.Time, bool) {
+	if session.TimeLimitMinutes == nil || *session.TimeLimitMinutes <= 0 {
+		return time.Time{}, false
 	}
 
-	return updated, nil
+	return session.StartedAt.Add(time.Duration(*session.TimeLimitMinutes) * time.Minute), true
 }
@@ -0,0 +1,80 @@
+package practice
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/repo"
+)
+
+func (uc *UseCase) result(ctx context.Context, session entity.PracticeSession) (entity.PracticeSessionResult, error) {
+	stats, err := uc.sessions.SessionTopicStats(ctx, session.ID)
+	if err != nil {
+		return entity.PracticeSessionResult{}, fmt.Errorf("practice - SessionTopicStats: %w", err)
+	}
+
+	return summarize(session, stats), nil
+}
+
+// summarize rolls per-topic stats up into subject and session totals.
+// Stats arrive ordered by subject, so subjects are built in a single pass.
+func summarize(session entity.PracticeSession, stats []repo.SessionTopicStat) entity.PracticeSessionResult {
+	res := entity.PracticeSessionResult{
+		Session:  session,
+		Subjects: make([]entity.PracticeBreakdownItem, 0),
+		Topics:   make([]entity.PracticeBreakdownItem, 0, len(stats)),
+	}
+
+	for _, st := range stats {
+		topic := entity.PracticeBreakdownItem{
+			ID:          st.TopicID,
+			Name:        st.TopicName,
+			Total:       st.Total,
+			Answered:    st.Answered,
+			Correct:     st.Correct,
+			TimeTakenMs: st.TimeTakenMs,
+		}
+		topic.Accuracy = accuracy(topic.Correct, topic.Answered)
+		res.Topics = append(res.Topics, topic)
+
+		last := len(res.Subjects) - 1
+		if last < 0 || res.Subjects[last].ID != st.SubjectID {
+			res.Subjects = append(res.Subjects, entity.PracticeBreakdownItem{ID: st.SubjectID, Name: st.SubjectName})
+			last++
+		}
+
+		subject := &res.Subjects[last]
+		subject.Total += st.Total
+		subject.Answered += st.Answered
+		subject.Correct += st.Correct
+		subject.TimeTakenMs += st.TimeTakenMs
+
+		res.TotalQuestions += st.Total
+		res.Answered += st.Answered
+		res.Correct += st.Correct
+		res.Score += st.Score
+		res.TotalTimeMs += st.TimeTakenMs
+	}
+
+	for i := range res.Subjects {
+		res.Subjects[i].Accuracy = accuracy(res.Subjects[i].Correct, res.Subjects[i].Answered)
+	}
+
+	res.Incorrect = res.Answered - res.Correct
+	res.Skipped = res.TotalQuestions - res.Answered
+	res.Score = math.Round(res.Score*100) / 100
+	res.Accuracy = accuracy(res.Correct, res.Answered)
+
+	return res
+}
+
+// accuracy is the percentage of answered questions that were correct, to two decimals.
+func accuracy(correct, answered int) float64 {
+	if answered == 0 {
+		return 0
+	}
+
+	return math.Round(float64(correct)/float64(answered)*10000) / 100
+}
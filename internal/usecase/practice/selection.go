@@ -0,0 +1,218 @@
+package practice
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/repo"
+	"github.com/evrone/go-clean-template/internal/usecase/tag"
+)
+
+const (
+	_defaultNumQuestions = 10
+	// _recentlySeenWindow is how long a served question is kept out of new sessions.
+	_recentlySeenWindow = 7 * 24 * time.Hour
+)
+
+// blueprint is the normalized selection plan derived from a create request.
+type blueprint struct {
+	userID       uuid.UUID
+	exam         entity.ExamCategory
+	subjectIDs   []uuid.UUID
+	topicIDs     []uuid.UUID
+	difficulties []int
+	tags         []string
+	pyq          bool
+	pyqYears     []int
+	total        int
+}
+
+func newBlueprint(userID uuid.UUID, req entity.PracticeSessionCreateRequest) blueprint {
+	total := req.NumQuestions
+	if total <= 0 {
+		total = _defaultNumQuestions
+	}
+
+	return blueprint{
+		userID:       userID,
+		exam:         req.Exam,
+		subjectIDs:   uniqueIDs(req.SubjectIDs),
+		topicIDs:     uniqueIDs(req.TopicIDs),
+		difficulties: req.DifficultyLevels,
+		tags:         tag.NormalizeAll(req.Tags),
+		pyq:          req.Mode == entity.PracticeModePYQ,
+		pyqYears:     req.PYQYears,
+		total:        total,
+	}
+}
+
+// pool is the filter covering the whole blueprint.
+func (b blueprint) pool() repo.QuestionPoolFilter {
+	return repo.QuestionPoolFilter{
+		UserID:           b.userID,
+		Exam:             b.exam,
+		SubjectIDs:       b.subjectIDs,
+		TopicIDs:         b.topicIDs,
+		DifficultyLevels: b.difficulties,
+		Tags:             b.tags,
+		PYQ:              b.pyq,
+		PYQYears:         b.pyqYears,
+	}
+}
+
+// bucket is a slice of the pool with its own share of the question count.
+type bucket struct {
+	filter repo.QuestionPoolFilter
+	quota  int
+}
+
+// buckets splits the blueprint evenly across the requested topics, or subjects
+// when no topics are given, so a single large topic cannot crowd out the rest.
+func (b blueprint) buckets() []bucket {
+	var filters []repo.QuestionPoolFilter
+
+	switch {
+	case len(b.topicIDs) > 1:
+		for _, topicID := range b.topicIDs {
+			f := b.pool()
+			f.TopicIDs = []uuid.UUID{topicID}
+			filters = append(filters, f)
+		}
+	case len(b.topicIDs) == 0 && len(b.subjectIDs) > 1:
+		for _, subjectID := range b.subjectIDs {
+			f := b.pool()
+			f.SubjectIDs = []uuid.UUID{subjectID}
+			filters = append(filters, f)
+		}
+	default:
+		filters = []repo.QuestionPoolFilter{b.pool()}
+	}
+
+	buckets := make([]bucket, 0, len(filters))
+	share, remainder := b.total/len(filters), b.total%len(filters)
+	for i, f := range filters {
+		quota := share
+		if i < remainder {
+			quota++
+		}
+		if quota > 0 {
+			buckets = append(buckets, bucket{filter: f, quota: quota})
+		}
+	}
+
+	return buckets
+}
+
+// selectQuestions fills every bucket, interleaves them into a single ordered
+// list and backfills any shortfall from the whole pool.
+func (uc *UseCase) selectQuestions(ctx context.Context, b blueprint, now time.Time) ([]uuid.UUID, error) {
+	chosen := make(map[uuid.UUID]struct{}, b.total)
+
+	buckets := b.buckets()
+	picked := make([][]uuid.UUID, 0, len(buckets))
+	for _, bk := range buckets {
+		ids, err := uc.fill(ctx, bk.filter, bk.quota, chosen, now)
+		if err != nil {
+			return nil, err
+		}
+		picked = append(picked, ids)
+	}
+
+	ordered := interleave(picked)
+
+	if short := b.total - len(ordered); short > 0 && len(buckets) > 1 {
+		ids, err := uc.fill(ctx, b.pool(), short, chosen, now)
+		if err != nil {
+			return nil, err
+		}
+		ordered = append(ordered, ids...)
+	}
+
+	return ordered, nil
+}
+
+// fill draws up to n questions from the filter, relaxing it step by step:
+// first only questions not seen recently, then any question (least recently
+// seen first), and finally ignoring the difficulty filter.
+func (uc *UseCase) fill(ctx context.Context, filter repo.QuestionPoolFilter, n int, chosen map[uuid.UUID]struct{}, now time.Time) ([]uuid.UUID, error) {
+	cutoff := now.Add(-_recentlySeenWindow)
+
+	unseen := filter
+	unseen.SeenBefore = &cutoff
+
+	stages := []repo.QuestionPoolFilter{unseen, filter}
+	if len(filter.DifficultyLevels) > 0 {
+		anyDifficulty := filter
+		anyDifficulty.DifficultyLevels = nil
+		stages = append(stages, anyDifficulty)
+	}
+
+	out := make([]uuid.UUID, 0, n)
+	for _, stage := range stages {
+		if len(out) >= n {
+			break
+		}
+
+		stage.ExcludeIDs = keys(chosen)
+		stage.Limit = n - len(out)
+
+		ids, err := uc.questions.ListPoolIDs(ctx, stage)
+		if err != nil {
+			return nil, fmt.Errorf("practice - ListPoolIDs: %w", err)
+		}
+
+		for _, id := range ids {
+			if _, ok := chosen[id]; ok {
+				continue
+			}
+			chosen[id] = struct{}{}
+			out = append(out, id)
+		}
+	}
+
+	return out, nil
+}
+
+// interleave merges the bucket picks round-robin so the session mixes topics.
+func interleave[T any](lists [][]T) []T {
+	var out []T
+	for i := 0; ; i++ {
+		added := false
+		for _, list := range lists {
+			if i < len(list) {
+				out = append(out, list[i])
+				added = true
+			}
+		}
+		if !added {
+			return out
+		}
+	}
+}
+
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]struct{}, len(ids))
+	out := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok || id == uuid.Nil {
+			continue
+		}
+		seen[id] = struct{}{}
+		out = append(out, id)
+	}
+
+	return out
+}
+
+func keys(set map[uuid.UUID]struct{}) []uuid.UUID {
+	out := make([]uuid.UUID, 0, len(set))
+	for id := range set {
+		out = append(out, id)
+	}
+
+	return out
+}
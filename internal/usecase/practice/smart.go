@@ -0,0 +1,351 @@
+package practice
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/repo"
+)
+
+// Fallbacks for AISettings fields left at zero.
+const (
+	_defaultWeaknessMinAttempts = 5
+	_defaultWeaknessThreshold   = 60
+	_defaultStrongThreshold     = 80
+)
+
+// Smart session mix, in percent of the question count; unseen questions take
+// the remainder.
+const (
+	_weakSharePercent   = 50
+	_spacedSharePercent = 20
+)
+
+const (
+	_minDifficulty = 1
+	_maxDifficulty = 5
+	// _stepUpStreak is how many correct answers in a row raise the difficulty.
+	_stepUpStreak = 2
+)
+
+type masteryLevel int
+
+const (
+	masteryDeveloping masteryLevel = iota
+	masteryWeak
+	masteryStrong
+)
+
+// thresholds classifies topics by the user's accuracy in them.
+type thresholds struct {
+	minAttempts int
+	weak        int
+	strong      int
+}
+
+func newThresholds(settings entity.AISettings) thresholds {
+	t := thresholds{
+		minAttempts: settings.WeaknessMinAttempts,
+		weak:        settings.WeaknessThresholdPercent,
+		strong:      settings.StrongThresholdPercent,
+	}
+	if t.minAttempts <= 0 {
+		t.minAttempts = _defaultWeaknessMinAttempts
+	}
+	if t.weak <= 0 {
+		t.weak = _defaultWeaknessThreshold
+	}
+	if t.strong <= 0 {
+		t.strong = _defaultStrongThreshold
+	}
+
+	return t
+}
+
+// classify treats topics with too few attempts as developing: there is not
+// enough evidence yet to call them weak or strong.
+func (t thresholds) classify(m repo.TopicMastery) masteryLevel {
+	if m.Attempts < t.minAttempts {
+		return masteryDeveloping
+	}
+
+	switch accuracy := accuracyPercent(m); {
+	case accuracy < t.weak:
+		return masteryWeak
+	case accuracy >= t.strong:
+		return masteryStrong
+	default:
+		return masteryDeveloping
+	}
+}
+
+// selectSmart builds a smart session from the user's topic mastery: questions
+// from weak topics at easier difficulties, questions the user has never seen,
+// and spaced reinforcement of strong topics that have gone longest without
+// practice. Shortfalls are backfilled from the whole pool. The request's
+// difficulty filter is ignored because difficulty follows the user instead.
+func (uc *UseCase) selectSmart(ctx context.Context, b blueprint, now time.Time) ([]entity.PracticeSessionQuestion, error) {
+	settings, err := uc.settings.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("practice - AISettings.Get: %w", err)
+	}
+
+	mastery, err := uc.sessions.TopicMastery(ctx, b.userID, b.exam, b.subjectIDs, b.topicIDs)
+	if err != nil {
+		return nil, fmt.Errorf("practice - TopicMastery: %w", err)
+	}
+
+	t := newThresholds(settings)
+	var weak, strong []repo.TopicMastery
+	for _, m := range mastery {
+		switch t.classify(m) {
+		case masteryWeak:
+			weak = append(weak, m)
+		case masteryStrong:
+			strong = append(strong, m)
+		case masteryDeveloping:
+		}
+	}
+
+	slices.SortFunc(weak, func(a, b repo.TopicMastery) int {
+		return cmp.Compare(accuracyPercent(a), accuracyPercent(b))
+	})
+	slices.SortFunc(strong, func(a, b repo.TopicMastery) int {
+		return a.LastAttemptAt.Compare(b.LastAttemptAt)
+	})
+
+	b.difficulties = nil
+	chosen := make(map[uuid.UUID]struct{}, b.total)
+
+	var weakQuota, spacedQuota int
+	if len(weak) > 0 {
+		weakQuota = b.total * _weakSharePercent / 100
+	}
+	if len(strong) > 0 {
+		spacedQuota = b.total * _spacedSharePercent / 100
+	}
+
+	weakPicks, err := uc.fillTopics(ctx, b, weak, weakQuota, []int{1, 2, 3}, chosen, now, func(m repo.TopicMastery) entity.QuestionRationale {
+		return entity.QuestionRationale{
+			Reason: entity.SelectionWeakTopic,
+			Note:   fmt.Sprintf("You have answered %d%% of %d questions in this topic correctly", accuracyPercent(m), m.Attempts),
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	spacedPicks, err := uc.fillTopics(ctx, b, strong, spacedQuota, []int{3, 4, 5}, chosen, now, func(m repo.TopicMastery) entity.QuestionRationale {
+		return entity.QuestionRationale{
+			Reason: entity.SelectionSpacedReview,
+			Note:   fmt.Sprintf("Reinforcing a strong topic last practised %d days ago", int(now.Sub(m.LastAttemptAt).Hours()/24)),
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	unseen := b.pool()
+	unseen.Unseen = true
+	unseen.DifficultyLevels = []int{2, 3, 4}
+
+	unseenIDs, err := uc.fill(ctx, unseen, b.total-len(weakPicks)-len(spacedPicks), chosen, now)
+	if err != nil {
+		return nil, err
+	}
+	unseenPicks := withRationale(unseenIDs, entity.QuestionRationale{
+		Reason: entity.SelectionUnseen,
+		Note:   "You have not seen this question before",
+	})
+
+	ordered := interleave([][]entity.PracticeSessionQuestion{weakPicks, unseenPicks, spacedPicks})
+
+	if short := b.total - len(ordered); short > 0 {
+		ids, err := uc.fill(ctx, b.pool(), short, chosen, now)
+		if err != nil {
+			return nil, err
+		}
+		ordered = append(ordered, withRationale(ids, entity.QuestionRationale{
+			Reason: entity.SelectionFill,
+			Note:   "Added to complete the session",
+		})...)
+	}
+
+	return ordered, nil
+}
+
+// fillTopics spreads quota over topics in order, preferring the given
+// difficulties, and tags every pick with the rationale of its topic.
+func (uc *UseCase) fillTopics(
+	ctx context.Context,
+	b blueprint,
+	topics []repo.TopicMastery,
+	quota int,
+	difficulties []int,
+	chosen map[uuid.UUID]struct{},
+	now time.Time,
+	rationale func(repo.TopicMastery) entity.QuestionRationale,
+) ([]entity.PracticeSessionQuestion, error) {
+	if quota <= 0 || len(topics) == 0 {
+		return nil, nil
+	}
+
+	topics = topics[:min(len(topics), quota)]
+	share, remainder := quota/len(topics), quota%len(topics)
+
+	picks := make([][]entity.PracticeSessionQuestion, 0, len(topics))
+	for i, m := range topics {
+		n := share
+		if i < remainder {
+			n++
+		}
+
+		f := b.pool()
+		f.TopicIDs = []uuid.UUID{m.TopicID}
+		f.DifficultyLevels = difficulties
+
+		ids, err := uc.fill(ctx, f, n, chosen, now)
+		if err != nil {
+			return nil, err
+		}
+		picks = append(picks, withRationale(ids, rationale(m)))
+	}
+
+	return interleave(picks), nil
+}
+
+// adapt steers the rest of a smart session after an answer: it replaces the
+// next unanswered question with one from the same topic at the difficulty the
+// user's live correctness calls for.
+func (uc *UseCase) adapt(ctx context.Context, session entity.PracticeSession, answered entity.PracticeSessionQuestion) error {
+	questions, err := uc.sessions.ListSessionQuestions(ctx, session.ID)
+	if err != nil {
+		return fmt.Errorf("practice - ListSessionQuestions: %w", err)
+	}
+
+	target, rationale, ok := nextDifficulty(questions, answered)
+	if !ok {
+		return nil
+	}
+
+	next, ok := nextUnanswered(questions, answered.SequenceIndex)
+	if !ok || next.Question.DifficultyLevel == target {
+		return nil
+	}
+
+	exclude := make([]uuid.UUID, 0, len(questions))
+	for _, q := range questions {
+		exclude = append(exclude, q.Question.ID)
+	}
+
+	ids, err := uc.questions.ListPoolIDs(ctx, repo.QuestionPoolFilter{
+		UserID:           session.UserID,
+		Exam:             session.Exam,
+		TopicIDs:         []uuid.UUID{next.Question.TopicID},
+		DifficultyLevels: []int{target},
+		ExcludeIDs:       exclude,
+		Limit:            1,
+	})
+	if err != nil {
+		return fmt.Errorf("practice - ListPoolIDs: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	err = uc.sessions.ReplaceSessionQuestion(ctx, session.ID, next.ID, ids[0], rationale)
+	if err != nil && !errors.Is(err, repo.ErrNotFound) {
+		return fmt.Errorf("practice - ReplaceSessionQuestion: %w", err)
+	}
+
+	return nil
+}
+
+// nextDifficulty steps down one level after a wrong answer and up one level
+// once the user has answered _stepUpStreak questions in a row correctly.
+func nextDifficulty(questions []entity.PracticeSessionQuestion, answered entity.PracticeSessionQuestion) (int, entity.QuestionRationale, bool) {
+	current := answered.Question.DifficultyLevel
+
+	if answered.IsCorrect == nil || !*answered.IsCorrect {
+		target := max(current-1, _minDifficulty)
+
+		return target, entity.QuestionRationale{
+			Reason: entity.SelectionDifficultyDown,
+			Note:   fmt.Sprintf("Eased to difficulty %d after a wrong answer", target),
+		}, target != current
+	}
+
+	history := make([]entity.PracticeSessionQuestion, 0, len(questions))
+	for _, q := range questions {
+		if q.AnsweredAt != nil && q.ID != answered.ID {
+			history = append(history, q)
+		}
+	}
+	slices.SortFunc(history, func(a, b entity.PracticeSessionQuestion) int {
+		return a.AnsweredAt.Compare(*b.AnsweredAt)
+	})
+
+	streak := 1
+	for i := len(history) - 1; i >= 0 && history[i].IsCorrect != nil && *history[i].IsCorrect; i-- {
+		streak++
+	}
+
+	if streak < _stepUpStreak {
+		return 0, entity.QuestionRationale{}, false
+	}
+
+	target := min(current+1, _maxDifficulty)
+
+	return target, entity.QuestionRationale{
+		Reason: entity.SelectionDifficultyUp,
+		Note:   fmt.Sprintf("Raised to difficulty %d after %d correct answers in a row", target, streak),
+	}, target != current
+}
+
+// nextUnanswered returns the first unanswered question after sequenceIndex,
+// wrapping around to earlier skipped questions.
+func nextUnanswered(questions []entity.PracticeSessionQuestion, sequenceIndex int) (entity.PracticeSessionQuestion, bool) {
+	var wrapped *entity.PracticeSessionQuestion
+	for i, q := range questions {
+		if q.AnsweredAt != nil {
+			continue
+		}
+		if q.SequenceIndex > sequenceIndex {
+			return q, true
+		}
+		if wrapped == nil {
+			wrapped = &questions[i]
+		}
+	}
+
+	if wrapped == nil {
+		return entity.PracticeSessionQuestion{}, false
+	}
+
+	return *wrapped, true
+}
+
+func accuracyPercent(m repo.TopicMastery) int {
+	if m.Attempts == 0 {
+		return 0
+	}
+
+	return m.Correct * 100 / m.Attempts
+}
+
+func withRationale(ids []uuid.UUID, rationale entity.QuestionRationale) []entity.PracticeSessionQuestion {
+	out := make([]entity.PracticeSessionQuestion, 0, len(ids))
+	for _, id := range ids {
+		r := rationale
+		out = append(out, entity.PracticeSessionQuestion{Question: entity.Question{ID: id}, Rationale: &r})
+	}
+
+	return out
+}
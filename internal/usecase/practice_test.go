@@ -0,0 +1,680 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/repo"
+	"github.com/evrone/go-clean-template/internal/repo/webapi"
+	"github.com/evrone/go-clean-template/internal/usecase/localization"
+	"github.com/evrone/go-clean-template/internal/usecase/media"
+	"github.com/evrone/go-clean-template/internal/usecase/practice"
+	"github.com/evrone/go-clean-template/pkg/blob"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type practiceMocks struct {
+	sessions      *MockPracticeSessionRepository
+	questions     *MockQuestionRepository
+	exams         *MockExamRepository
+	settings      *MockAISettingsRepository
+	revisions     *MockRevisionRepository
+	media         *MockQuestionMediaRepository
+	localizations *MockLocalizationRepository
+}
+
+func practiceUseCase(t *testing.T) (*practice.UseCase, practiceMocks) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+
+	m := practiceMocks{
+		sessions:      NewMockPracticeSessionRepository(mockCtl),
+		questions:     NewMockQuestionRepository(mockCtl),
+		exams:         NewMockExamRepository(mockCtl),
+		settings:      NewMockAISettingsRepository(mockCtl),
+		revisions:     NewMockRevisionRepository(mockCtl),
+		media:         NewMockQuestionMediaRepository(mockCtl),
+		localizations: NewMockLocalizationRepository(mockCtl),
+	}
+
+	store, err := blob.NewLocal(t.TempDir(), "http://localhost/v1/media", "secret")
+	require.NoError(t, err)
+
+	attachments := media.New(m.media, m.questions, store, media.Options{MaxBytes: 1 << 20, URLTTL: time.Hour})
+
+	localizer := localization.New(m.localizations, webapi.NewDictionary(nil), localization.Options{Languages: []string{"hi"}})
+
+	return practice.New(m.sessions, m.questions, m.exams, m.settings, m.revisions, attachments, localizer), m
+}
+
+// noMedia lets sessions load their questions' media and find none.
+func noMedia(m practiceMocks) {
+	m.media.EXPECT().ListByQuestions(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+}
+
+// inEnglish serves sessions to learners who kept the default language.
+func inEnglish(m practiceMocks) {
+	m.localizations.EXPECT().GetLanguage(gomock.Any(), gomock.Any()).Return(entity.LanguageEnglish, nil).AnyTimes()
+}
+
+func TestCreateSessionSplitsQuotaAcrossTopics(t *testing.T) {
+	t.Parallel()
+
+	uc, m := practiceUseCase(t)
+
+	userID := uuid.New()
+	topicA, topicB := uuid.New(), uuid.New()
+	poolA := []uuid.UUID{uuid.New(), uuid.New()}
+	poolB := []uuid.UUID{uuid.New(), uuid.New()}
+
+	m.questions.EXPECT().ListPoolIDs(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, f repo.QuestionPoolFilter) ([]uuid.UUID, error) {
+			require.Len(t, f.TopicIDs, 1)
+			require.NotNil(t, f.SeenBefore)
+			require.Equal(t, 2, f.Limit)
+
+			if f.TopicIDs[0] == topicA {
+				return poolA, nil
+			}
+
+			return poolB, nil
+		},
+	).Times(2)
+
+	m.sessions.EXPECT().CreateSession(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, s entity.PracticeSession, q []entity.PracticeSessionQuestion) (entity.PracticeSession, error) {
+			require.Equal(t, []uuid.UUID{poolA[0], poolB[0], poolA[1], poolB[1]}, sessionQuestionIDs(q))
+
+			return s, nil
+		})
+
+	session, err := uc.CreateSession(context.Background(), userID, entity.PracticeSessionCreateRequest{
+		Mode:         entity.PracticeModeCustom,
+		Exam:         entity.ExamCategoryNEETPG,
+		TopicIDs:     []uuid.UUID{topicA, topicB},
+		NumQuestions: 4,
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, userID, session.UserID)
+	require.Equal(t, 4, *session.TotalQuestionsPlanned)
+}
+
+func TestCreateSessionRelaxesFilters(t *testing.T) {
+	t.Parallel()
+
+	uc, m := practiceUseCase(t)
+
+	unseen, seen, otherDifficulty := uuid.New(), uuid.New(), uuid.New()
+
+	gomock.InOrder(
+		m.questions.EXPECT().ListPoolIDs(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, f repo.QuestionPoolFilter) ([]uuid.UUID, error) {
+				require.NotNil(t, f.SeenBefore)
+				require.Equal(t, []int{5}, f.DifficultyLevels)
+
+				return []uuid.UUID{unseen}, nil
+			}),
+		m.questions.EXPECT().ListPoolIDs(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, f repo.QuestionPoolFilter) ([]uuid.UUID, error) {
+				require.Nil(t, f.SeenBefore)
+				require.Equal(t, []uuid.UUID{unseen}, f.ExcludeIDs)
+				require.Equal(t, 2, f.Limit)
+
+				return []uuid.UUID{seen}, nil
+			}),
+		m.questions.EXPECT().ListPoolIDs(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, f repo.QuestionPoolFilter) ([]uuid.UUID, error) {
+				require.Empty(t, f.DifficultyLevels)
+				require.Equal(t, 1, f.Limit)
+
+				return []uuid.UUID{otherDifficulty}, nil
+			}),
+	)
+
+	m.sessions.EXPECT().CreateSession(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, s entity.PracticeSession, q []entity.PracticeSessionQuestion) (entity.PracticeSession, error) {
+			require.Equal(t, []uuid.UUID{unseen, seen, otherDifficulty}, sessionQuestionIDs(q))
+
+			return s, nil
+		})
+
+	_, err := uc.CreateSession(context.Background(), uuid.New(), entity.PracticeSessionCreateRequest{
+		Mode:             entity.PracticeModeCustom,
+		Exam:             entity.ExamCategoryNEETPG,
+		DifficultyLevels: []int{5},
+		NumQuestions:     3,
+	})
+
+	require.NoError(t, err)
+}
+
+func TestCreateSessionWithEmptyPool(t *testing.T) {
+	t.Parallel()
+
+	uc, m := practiceUseCase(t)
+
+	m.questions.EXPECT().ListPoolIDs(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+
+	_, err := uc.CreateSession(context.Background(), uuid.New(), entity.PracticeSessionCreateRequest{
+		Mode: entity.PracticeModeCustom,
+		Exam: entity.ExamCategoryNEETPG,
+	})
+	require.ErrorIs(t, err, practice.ErrNoQuestions)
+
+	_, err = uc.CreateSession(context.Background(), uuid.New(), entity.PracticeSessionCreateRequest{
+		Mode: entity.PracticeModeCustom,
+	})
+	require.ErrorIs(t, err, practice.ErrExamRequired)
+}
+
+func TestCreatePYQSession(t *testing.T) {
+	t.Parallel()
+
+	uc, m := practiceUseCase(t)
+
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+
+	m.questions.EXPECT().ListPoolIDs(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, f repo.QuestionPoolFilter) ([]uuid.UUID, error) {
+			require.True(t, f.PYQ)
+			require.Equal(t, []int{2019, 2020}, f.PYQYears)
+			require.Equal(t, []string{"AIIMS"}, f.Tags)
+
+			return ids, nil
+		})
+	m.sessions.EXPECT().CreateSession(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, s entity.PracticeSession, _ []entity.PracticeSessionQuestion) (entity.PracticeSession, error) {
+			return s, nil
+		})
+
+	session, err := uc.CreateSession(context.Background(), uuid.New(), entity.PracticeSessionCreateRequest{
+		Mode:         entity.PracticeModePYQ,
+		Exam:         entity.ExamCategoryNEETPG,
+		Tags:         []string{" AIIMS ", "aiims"},
+		PYQYears:     []int{2019, 2020},
+		NumQuestions: 2,
+	})
+	require.NoError(t, err)
+	require.Equal(t, entity.PracticeModePYQ, session.Mode)
+	require.Equal(t, 2, *session.TotalQuestionsPlanned)
+}
+
+func TestCompleteSessionSummarizes(t *testing.T) {
+	t.Parallel()
+
+	uc, m := practiceUseCase(t)
+
+	session := entity.PracticeSession{ID: uuid.New(), UserID: uuid.New(), Status: entity.PracticeStatusInProgress, StartedAt: time.Now().UTC()}
+	subject := uuid.New()
+
+	m.sessions.EXPECT().GetSession(gomock.Any(), session.ID).Return(session, nil)
+	m.sessions.EXPECT().FinishSession(gomock.Any(), session.ID, entity.PracticeStatusCompleted, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ uuid.UUID, status entity.PracticeSessionStatus, at time.Time) (entity.PracticeSession, error) {
+			finished := session
+			finished.Status = status
+			finished.CompletedAt = &at
+
+			return finished, nil
+		})
+	m.sessions.EXPECT().SessionTopicStats(gomock.Any(), session.ID).Return([]repo.SessionTopicStat{
+		{SubjectID: subject, SubjectName: "Pathology", TopicID: uuid.New(), TopicName: "Neoplasia", Total: 3, Answered: 3, Correct: 2, TimeTakenMs: 3000},
+		{SubjectID: subject, SubjectName: "Pathology", TopicID: uuid.New(), TopicName: "Inflammation", Total: 2, Answered: 1, Correct: 1, TimeTakenMs: 500},
+	}, nil)
+
+	res, err := uc.CompleteSession(context.Background(), session.ID, session.UserID)
+
+	require.NoError(t, err)
+	require.Equal(t, entity.PracticeStatusCompleted, res.Session.Status)
+	require.Equal(t, 5, res.TotalQuestions)
+	require.Equal(t, 3, res.Correct)
+	require.Equal(t, 1, res.Incorrect)
+	require.Equal(t, 1, res.Skipped)
+	require.InDelta(t, 75.0, res.Accuracy, 0.001)
+	require.Len(t, res.Subjects, 1)
+	require.Len(t, res.Topics, 2)
+	require.Equal(t, 3500, res.Subjects[0].TimeTakenMs)
+}
+
+func TestCompleteExpiredSession(t *testing.T) {
+	t.Parallel()
+
+	uc, m := practiceUseCase(t)
+
+	limit := 10
+	session := entity.PracticeSession{
+		ID:               uuid.New(),
+		UserID:           uuid.New(),
+		Status:           entity.PracticeStatusInProgress,
+		TimeLimitMinutes: &limit,
+		StartedAt:        time.Now().UTC().Add(-time.Hour),
+	}
+
+	m.sessions.EXPECT().GetSession(gomock.Any(), session.ID).Return(session, nil)
+	m.sessions.EXPECT().FinishSession(gomock.Any(), session.ID, entity.PracticeStatusAbandoned, session.StartedAt.Add(10*time.Minute)).
+		Return(session, nil)
+
+	_, err := uc.CompleteSession(context.Background(), session.ID, session.UserID)
+	require.ErrorIs(t, err, practice.ErrSessionClosed)
+}
+
+func TestSessionOwnership(t *testing.T) {
+	t.Parallel()
+
+	uc, m := practiceUseCase(t)
+
+	owner := uuid.New()
+	session := entity.PracticeSession{ID: uuid.New(), UserID: owner, Status: entity.PracticeStatusInProgress, StartedAt: time.Now().UTC()}
+	missing := uuid.New()
+
+	m.sessions.EXPECT().GetSession(gomock.Any(), session.ID).Return(session, nil).AnyTimes()
+	m.sessions.EXPECT().GetSession(gomock.Any(), missing).Return(entity.PracticeSession{}, repo.ErrNotFound).AnyTimes()
+
+	_, err := uc.GetSessionDetail(context.Background(), session.ID, uuid.New())
+	require.ErrorIs(t, err, practice.ErrForbidden)
+
+	_, err = uc.GetSessionDetail(context.Background(), missing, owner)
+	require.ErrorIs(t, err, practice.ErrNotFound)
+
+	_, err = uc.AbandonSession(context.Background(), session.ID, uuid.New())
+	require.ErrorIs(t, err, practice.ErrForbidden)
+
+	_, err = uc.AnswerQuestion(context.Background(), session.ID, entity.PracticeAnswerRequest{SessionQuestionID: uuid.New()}, uuid.New())
+	require.ErrorIs(t, err, practice.ErrForbidden)
+}
+
+func TestSessionDetailInPreferredLanguage(t *testing.T) {
+	t.Parallel()
+
+	uc, m := practiceUseCase(t)
+	noMedia(m)
+
+	session := entity.PracticeSession{ID: uuid.New(), UserID: uuid.New(), Status: entity.PracticeStatusInProgress, StartedAt: time.Now().UTC()}
+	translated := entity.Question{ID: uuid.New(), QuestionText: "Which vitamin?", OptionA: "A", OptionB: "B", OptionC: "C", OptionD: "D", Version: 3}
+	edited := entity.Question{ID: uuid.New(), QuestionText: "Which nerve?", OptionA: "A", OptionB: "B", OptionC: "C", OptionD: "D", Version: 4}
+
+	m.sessions.EXPECT().GetSession(gomock.Any(), session.ID).Return(session, nil)
+	m.sessions.EXPECT().ListSessionQuestions(gomock.Any(), session.ID).Return([]entity.PracticeSessionQuestion{
+		{ID: uuid.New(), Question: translated},
+		{ID: uuid.New(), Question: edited},
+	}, nil)
+	m.localizations.EXPECT().GetLanguage(gomock.Any(), session.UserID).Return("hi", nil)
+	m.localizations.EXPECT().ListForQuestions(gomock.Any(), []uuid.UUID{translated.ID, edited.ID}, "hi").
+		Return([]entity.QuestionLocalization{
+			{QuestionID: translated.ID, Language: "hi", QuestionText: "कौन सा विटामिन?", OptionA: "ए", OptionB: "बी", OptionC: "सी", OptionD: "डी", SourceVersion: 3},
+			{QuestionID: edited.ID, Language: "hi", QuestionText: "कौन सी तंत्रिका?", OptionA: "ए", OptionB: "बी", OptionC: "सी", OptionD: "डी", SourceVersion: 3},
+		}, nil)
+
+	detail, err := uc.GetSessionDetail(context.Background(), session.ID, session.UserID)
+	require.NoError(t, err)
+
+	require.Equal(t, "कौन सा विटामिन?", detail.Questions[0].Question.QuestionText)
+	require.Equal(t, "hi", detail.Questions[0].Question.Language)
+
+	// Translated before the last edit: served in English until retranslated.
+	require.Equal(t, "Which nerve?", detail.Questions[1].Question.QuestionText)
+	require.Empty(t, detail.Questions[1].Question.Language)
+}
+
+func TestAnswerQuestionFromAnotherSession(t *testing.T) {
+	t.Parallel()
+
+	uc, m := practiceUseCase(t)
+
+	session := entity.PracticeSession{ID: uuid.New(), UserID: uuid.New(), Status: entity.PracticeStatusInProgress, StartedAt: time.Now().UTC()}
+	foreign := uuid.New()
+
+	m.sessions.EXPECT().GetSession(gomock.Any(), session.ID).Return(session, nil)
+	m.sessions.EXPECT().GetSessionQuestion(gomock.Any(), session.ID, foreign).Return(entity.PracticeSessionQuestion{}, repo.ErrNotFound)
+
+	_, err := uc.AnswerQuestion(context.Background(), session.ID, entity.PracticeAnswerRequest{SessionQuestionID: foreign}, session.UserID)
+	require.ErrorIs(t, err, practice.ErrNotFound)
+}
+
+func TestAnswerQuestionRecordsAttempt(t *testing.T) {
+	t.Parallel()
+
+	uc, m := practiceUseCase(t)
+	noMedia(m)
+	inEnglish(m)
+
+	session := entity.PracticeSession{ID: uuid.New(), UserID: uuid.New(), Status: entity.PracticeStatusInProgress, StartedAt: time.Now().UTC()}
+	question := entity.PracticeSessionQuestion{ID: uuid.New(), Question: entity.Question{ID: uuid.New(), Exam: entity.ExamCategoryNEETPG, CorrectOption: 2}}
+
+	m.sessions.EXPECT().GetSession(gomock.Any(), session.ID).Return(session, nil)
+	m.exams.EXPECT().GetCategory(gomock.Any(), entity.ExamCategoryNEETPG).
+		Return(entity.ExamCategorySettings{MultiSelectScoring: entity.MultiSelectAllOrNothing}, nil)
+	m.sessions.EXPECT().GetSessionQuestion(gomock.Any(), session.ID, question.ID).Return(question, nil)
+	m.settings.EXPECT().Get(gomock.Any()).Return(entity.AISettings{RevisionEnabled: true}, nil)
+	m.sessions.EXPECT().AnswerSessionQuestion(gomock.Any(), session.ID, session.UserID, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _ uuid.UUID, q entity.PracticeSessionQuestion, revisionDue *entity.RevisionItem) (entity.PracticeSessionQuestion, error) {
+			require.NotNil(t, q.AnsweredAt)
+			require.Nil(t, revisionDue)
+
+			return q, nil
+		})
+
+	answered, err := uc.AnswerQuestion(context.Background(), session.ID, entity.PracticeAnswerRequest{
+		SessionQuestionID: question.ID,
+		SelectedOption:    2,
+	}, session.UserID)
+
+	require.NoError(t, err)
+	require.True(t, *answered.IsCorrect)
+}
+
+func TestAnswerQuestionSchedulesRevision(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		selected int
+		guess    bool
+		settings entity.AISettings
+		schedule []time.Time
+		due      time.Duration
+	}{
+		{
+			name:     "wrong answer",
+			selected: 1,
+			settings: entity.AISettings{RevisionEnabled: true, RevisionIntervalsDays: []int{2, 5}},
+			due:      48 * time.Hour,
+		},
+		{
+			name:     "correct guess",
+			selected: 2,
+			guess:    true,
+			settings: entity.AISettings{RevisionEnabled: true, IncludeGuessedCorrect: true},
+			due:      24 * time.Hour,
+		},
+		{
+			name:     "correct guess not included",
+			selected: 2,
+			guess:    true,
+			settings: entity.AISettings{RevisionEnabled: true},
+		},
+		{
+			name:     "revision disabled",
+			selected: 1,
+			settings: entity.AISettings{},
+		},
+		{
+			name:     "smoothed onto a quieter day",
+			selected: 1,
+			settings: entity.AISettings{RevisionEnabled: true, RevisionLoadSmoothing: true},
+			schedule: []time.Time{time.Now().UTC().AddDate(0, 0, 1), time.Now().UTC().AddDate(0, 0, 1)},
+			due:      48 * time.Hour,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			uc, m := practiceUseCase(t)
+			noMedia(m)
+			inEnglish(m)
+
+			session := entity.PracticeSession{ID: uuid.New(), UserID: uuid.New(), Status: entity.PracticeStatusInProgress, StartedAt: time.Now().UTC()}
+			question := entity.PracticeSessionQuestion{ID: uuid.New(), Question: entity.Question{ID: uuid.New(), CorrectOption: 2}}
+
+			m.sessions.EXPECT().GetSession(gomock.Any(), session.ID).Return(session, nil)
+			m.sessions.EXPECT().GetSessionQuestion(gomock.Any(), session.ID, question.ID).Return(question, nil)
+			m.exams.EXPECT().GetCategory(gomock.Any(), gomock.Any()).Return(entity.ExamCategorySettings{}, nil)
+			m.settings.EXPECT().Get(gomock.Any()).Return(tc.settings, nil)
+			if tc.settings.RevisionLoadSmoothing {
+				m.revisions.EXPECT().ListSchedule(gomock.Any(), session.UserID, gomock.Any()).Return(tc.schedule, nil)
+			}
+			m.sessions.EXPECT().AnswerSessionQuestion(gomock.Any(), session.ID, session.UserID, gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, _, _ uuid.UUID, q entity.PracticeSessionQuestion, revisionDue *entity.RevisionItem) (entity.PracticeSessionQuestion, error) {
+					require.Equal(t, tc.guess, q.IsGuess)
+					if tc.due == 0 {
+						require.Nil(t, revisionDue)
+					} else {
+						require.NotNil(t, revisionDue)
+						require.WithinDuration(t, q.AnsweredAt.Add(tc.due), revisionDue.NextReviewAt, time.Second)
+					}
+
+					return q, nil
+				})
+
+			_, err := uc.AnswerQuestion(context.Background(), session.ID, entity.PracticeAnswerRequest{
+				SessionQuestionID: question.ID,
+				SelectedOption:    tc.selected,
+				IsGuess:           tc.guess,
+			}, session.UserID)
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestAnswerQuestionRetry(t *testing.T) {
+	t.Parallel()
+
+	uc, m := practiceUseCase(t)
+
+	session := entity.PracticeSession{ID: uuid.New(), UserID: uuid.New(), Status: entity.PracticeStatusCompleted, StartedAt: time.Now().UTC()}
+	selected, answeredAt := 3, time.Now().UTC()
+	question := entity.PracticeSessionQuestion{ID: uuid.New(), SelectedOption: &selected, AnsweredAt: &answeredAt}
+
+	m.sessions.EXPECT().GetSession(gomock.Any(), session.ID).Return(session, nil).Times(2)
+	m.sessions.EXPECT().GetSessionQuestion(gomock.Any(), session.ID, question.ID).Return(question, nil).Times(2)
+
+	replayed, err := uc.AnswerQuestion(context.Background(), session.ID, entity.PracticeAnswerRequest{
+		SessionQuestionID: question.ID,
+		SelectedOption:    3,
+	}, session.UserID)
+	require.NoError(t, err)
+	require.Equal(t, question, replayed)
+
+	_, err = uc.AnswerQuestion(context.Background(), session.ID, entity.PracticeAnswerRequest{
+		SessionQuestionID: question.ID,
+		SelectedOption:    1,
+	}, session.UserID)
+	require.ErrorIs(t, err, practice.ErrAlreadyAnswered)
+}
+
+func TestAnswerMultiSelectWithPartialCredit(t *testing.T) {
+	t.Parallel()
+
+	uc, m := practiceUseCase(t)
+	noMedia(m)
+	inEnglish(m)
+
+	session := entity.PracticeSession{ID: uuid.New(), UserID: uuid.New(), Status: entity.PracticeStatusInProgress, StartedAt: time.Now().UTC()}
+	question := entity.PracticeSessionQuestion{ID: uuid.New(), Question: entity.Question{
+		ID:             uuid.New(),
+		Exam:           entity.ExamCategoryUPSC,
+		ChoiceType:     entity.ChoiceTypeMulti,
+		CorrectOption:  1,
+		CorrectOptions: []int{1, 3, 4},
+	}}
+
+	m.sessions.EXPECT().GetSession(gomock.Any(), session.ID).Return(session, nil)
+	m.sessions.EXPECT().GetSessionQuestion(gomock.Any(), session.ID, question.ID).Return(question, nil)
+	m.exams.EXPECT().GetCategory(gomock.Any(), entity.ExamCategoryUPSC).
+		Return(entity.ExamCategorySettings{MultiSelectScoring: entity.MultiSelectPartial}, nil)
+	m.settings.EXPECT().Get(gomock.Any()).Return(entity.AISettings{RevisionEnabled: true}, nil)
+	m.sessions.EXPECT().AnswerSessionQuestion(gomock.Any(), session.ID, session.UserID, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _ uuid.UUID, q entity.PracticeSessionQuestion, _ *entity.RevisionItem) (entity.PracticeSessionQuestion, error) {
+			return q, nil
+		})
+
+	answered, err := uc.AnswerQuestion(context.Background(), session.ID, entity.PracticeAnswerRequest{
+		SessionQuestionID: question.ID,
+		SelectedOptions:   []int{4, 1},
+	}, session.UserID)
+
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 4}, answered.SelectedOptions)
+	require.False(t, *answered.IsCorrect)
+	require.InDelta(t, 2.0/3, *answered.Score, 0.0001)
+}
+
+func TestAnswerSingleChoiceWithSeveralOptions(t *testing.T) {
+	t.Parallel()
+
+	uc, m := practiceUseCase(t)
+
+	session := entity.PracticeSession{ID: uuid.New(), UserID: uuid.New(), Status: entity.PracticeStatusInProgress, StartedAt: time.Now().UTC()}
+	question := entity.PracticeSessionQuestion{ID: uuid.New(), Question: entity.Question{ChoiceType: entity.ChoiceTypeSingle, CorrectOption: 2}}
+
+	m.sessions.EXPECT().GetSession(gomock.Any(), session.ID).Return(session, nil)
+	m.sessions.EXPECT().GetSessionQuestion(gomock.Any(), session.ID, question.ID).Return(question, nil)
+
+	_, err := uc.AnswerQuestion(context.Background(), session.ID, entity.PracticeAnswerRequest{
+		SessionQuestionID: question.ID,
+		SelectedOptions:   []int{1, 2},
+	}, session.UserID)
+	require.ErrorIs(t, err, practice.ErrInvalidAnswer)
+}
+
+func TestCreateSmartSessionMixesMastery(t *testing.T) {
+	t.Parallel()
+
+	uc, m := practiceUseCase(t)
+
+	userID := uuid.New()
+	weakTopic, strongTopic := uuid.New(), uuid.New()
+	weakIDs := []uuid.UUID{uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()}
+	spacedIDs := []uuid.UUID{uuid.New(), uuid.New()}
+	unseenIDs := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+
+	m.settings.EXPECT().Get(gomock.Any()).Return(entity.AISettings{}, nil)
+	m.sessions.EXPECT().TopicMastery(gomock.Any(), userID, entity.ExamCategoryNEETPG, gomock.Any(), gomock.Any()).
+		Return([]repo.TopicMastery{
+			{TopicID: weakTopic, Attempts: 10, Correct: 3, LastAttemptAt: time.Now().UTC()},
+			{TopicID: strongTopic, Attempts: 10, Correct: 9, LastAttemptAt: time.Now().UTC().AddDate(0, 0, -3)},
+			{TopicID: uuid.New(), Attempts: 2, Correct: 0, LastAttemptAt: time.Now().UTC()},
+		}, nil)
+
+	m.questions.EXPECT().ListPoolIDs(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, f repo.QuestionPoolFilter) ([]uuid.UUID, error) {
+			switch {
+			case f.Unseen:
+				require.Equal(t, []int{2, 3, 4}, f.DifficultyLevels)
+				require.Equal(t, 3, f.Limit)
+
+				return unseenIDs, nil
+			case f.TopicIDs[0] == weakTopic:
+				require.Equal(t, []int{1, 2, 3}, f.DifficultyLevels)
+				require.Equal(t, 5, f.Limit)
+
+				return weakIDs, nil
+			default:
+				require.Equal(t, []uuid.UUID{strongTopic}, f.TopicIDs)
+				require.Equal(t, 2, f.Limit)
+
+				return spacedIDs, nil
+			}
+		},
+	).Times(3)
+
+	m.sessions.EXPECT().CreateSession(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, s entity.PracticeSession, q []entity.PracticeSessionQuestion) (entity.PracticeSession, error) {
+			require.Equal(t, []uuid.UUID{
+				weakIDs[0], unseenIDs[0], spacedIDs[0],
+				weakIDs[1], unseenIDs[1], spacedIDs[1],
+				weakIDs[2], unseenIDs[2],
+				weakIDs[3], weakIDs[4],
+			}, sessionQuestionIDs(q))
+			require.Equal(t, entity.SelectionWeakTopic, q[0].Rationale.Reason)
+			require.Contains(t, q[0].Rationale.Note, "30%")
+			require.Equal(t, entity.SelectionUnseen, q[1].Rationale.Reason)
+			require.Equal(t, entity.SelectionSpacedReview, q[2].Rationale.Reason)
+
+			return s, nil
+		})
+
+	_, err := uc.CreateSession(context.Background(), userID, entity.PracticeSessionCreateRequest{
+		Mode:             entity.PracticeModeSmart,
+		Exam:             entity.ExamCategoryNEETPG,
+		DifficultyLevels: []int{5},
+		NumQuestions:     10,
+	})
+
+	require.NoError(t, err)
+}
+
+func TestSmartSessionStepsDifficulty(t *testing.T) {
+	t.Parallel()
+
+	uc, m := practiceUseCase(t)
+	noMedia(m)
+	inEnglish(m)
+
+	topicID := uuid.New()
+	earlier, correct := time.Now().UTC().Add(-time.Minute), true
+	session := entity.PracticeSession{
+		ID:        uuid.New(),
+		UserID:    uuid.New(),
+		Mode:      entity.PracticeModeSmart,
+		Exam:      entity.ExamCategoryNEETPG,
+		Status:    entity.PracticeStatusInProgress,
+		StartedAt: time.Now().UTC(),
+	}
+	first := entity.PracticeSessionQuestion{
+		ID: uuid.New(), SequenceIndex: 1, IsCorrect: &correct, AnsweredAt: &earlier,
+		Question: entity.Question{ID: uuid.New(), TopicID: topicID, DifficultyLevel: 3},
+	}
+	current := entity.PracticeSessionQuestion{
+		ID: uuid.New(), SequenceIndex: 2,
+		Question: entity.Question{ID: uuid.New(), Exam: entity.ExamCategoryNEETPG, TopicID: topicID, DifficultyLevel: 3, CorrectOption: 1},
+	}
+	next := entity.PracticeSessionQuestion{
+		ID: uuid.New(), SequenceIndex: 3,
+		Question: entity.Question{ID: uuid.New(), TopicID: topicID, DifficultyLevel: 3},
+	}
+	harder := uuid.New()
+
+	m.sessions.EXPECT().GetSession(gomock.Any(), session.ID).Return(session, nil)
+	m.sessions.EXPECT().GetSessionQuestion(gomock.Any(), session.ID, current.ID).Return(current, nil)
+	m.exams.EXPECT().GetCategory(gomock.Any(), entity.ExamCategoryNEETPG).Return(entity.ExamCategorySettings{}, nil)
+	m.settings.EXPECT().Get(gomock.Any()).Return(entity.AISettings{RevisionEnabled: true}, nil)
+	m.sessions.EXPECT().AnswerSessionQuestion(gomock.Any(), session.ID, session.UserID, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _ uuid.UUID, q entity.PracticeSessionQuestion, _ *entity.RevisionItem) (entity.PracticeSessionQuestion, error) {
+			current = q
+
+			return q, nil
+		})
+	m.sessions.EXPECT().ListSessionQuestions(gomock.Any(), session.ID).DoAndReturn(
+		func(context.Context, uuid.UUID) ([]entity.PracticeSessionQuestion, error) {
+			return []entity.PracticeSessionQuestion{first, current, next}, nil
+		})
+	m.questions.EXPECT().ListPoolIDs(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, f repo.QuestionPoolFilter) ([]uuid.UUID, error) {
+			require.Equal(t, []uuid.UUID{topicID}, f.TopicIDs)
+			require.Equal(t, []int{4}, f.DifficultyLevels)
+			require.ElementsMatch(t, []uuid.UUID{first.Question.ID, current.Question.ID, next.Question.ID}, f.ExcludeIDs)
+
+			return []uuid.UUID{harder}, nil
+		})
+	m.sessions.EXPECT().ReplaceSessionQuestion(gomock.Any(), session.ID, next.ID, harder, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _, _ uuid.UUID, r entity.QuestionRationale) error {
+			require.Equal(t, entity.SelectionDifficultyUp, r.Reason)
+
+			return nil
+		})
+
+	_, err := uc.AnswerQuestion(context.Background(), session.ID, entity.PracticeAnswerRequest{
+		SessionQuestionID: current.ID,
+		SelectedOption:    1,
+	}, session.UserID)
+
+	require.NoError(t, err)
+}
+
+func sessionQuestionIDs(questions []entity.PracticeSessionQuestion) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(questions))
+	for _, q := range questions {
+		ids = append(ids, q.Question.ID)
+	}
+
+	return ids
+}
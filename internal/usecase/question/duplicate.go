@@ -0,0 +1,322 @@
+package question
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/pkg/minhash"
+)
+
+const (
+	// DefaultDuplicateThreshold is the similarity from which questions are
+	// reported as likely duplicates.
+	DefaultDuplicateThreshold = 0.8
+	// _signatureBatchSize is how many questions SignQuestions signs per save.
+	_signatureBatchSize = 500
+	// _signatureGetChunk bounds the IDs loaded per query by DuplicateReport.
+	_signatureGetChunk = 1000
+	// _maxSimilar bounds the matches listed for one question.
+	_maxSimilar = 5
+	// _maxImportDuplicates bounds the duplicate warnings kept; DuplicateRows
+	// still counts all.
+	_maxImportDuplicates = 500
+)
+
+// SignQuestions computes the signatures of new and edited questions until
+// none is left and returns how many it signed. Questions are only matched
+// against each other once signed, so a question created moments ago is not
+// yet reported as a duplicate of the next one.
+func (uc *UseCase) SignQuestions(ctx context.Context) (int, error) {
+	signed := 0
+	for ctx.Err() == nil {
+		stale, err := uc.signatures.ListStale(ctx, _signatureBatchSize)
+		if err != nil {
+			return signed, fmt.Errorf("question - Signatures.ListStale: %w", err)
+		}
+		if len(stale) == 0 {
+			break
+		}
+
+		for i := range stale {
+			sig := minhash.New(stale[i].QuestionText)
+			stale[i].Signature = sig.Bytes()
+			stale[i].BandKeys = sig.BandKeys()
+		}
+
+		if err := uc.signatures.Save(ctx, stale); err != nil {
+			return signed, fmt.Errorf("question - Signatures.Save: %w", err)
+		}
+		signed += len(stale)
+
+		if len(stale) < _signatureBatchSize {
+			break
+		}
+	}
+
+	return signed, nil
+}
+
+// DuplicateReport groups exam's signed questions into clusters whose members
+// are at least threshold similar to another member. Only questions sharing
+// an LSH band are compared, so the whole bank is never compared pairwise.
+func (uc *UseCase) DuplicateReport(ctx context.Context, exam entity.ExamCategory, threshold float64) (entity.QuestionDuplicateReport, error) {
+	if threshold <= 0 || threshold > 1 {
+		return entity.QuestionDuplicateReport{}, ErrInvalidThreshold
+	}
+
+	var groups [][]uuid.UUID
+	ids := make(map[uuid.UUID]struct{})
+
+	err := uc.signatures.Collisions(ctx, exam, func(group []uuid.UUID) error {
+		groups = append(groups, group)
+		for _, id := range group {
+			ids[id] = struct{}{}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.QuestionDuplicateReport{}, fmt.Errorf("question - Signatures.Collisions: %w", err)
+	}
+
+	signatures, err := uc.loadSignatures(ctx, ids)
+	if err != nil {
+		return entity.QuestionDuplicateReport{}, err
+	}
+
+	set := newClusterSet()
+	for _, group := range groups {
+		for i, a := range group {
+			for _, b := range group[i+1:] {
+				sa, okA := signatures[a]
+				sb, okB := signatures[b]
+				if !okA || !okB || set.find(a) == set.find(b) {
+					continue
+				}
+
+				if similarity := sa.sig.Similarity(sb.sig); similarity >= threshold {
+					set.union(a, b, similarity)
+				}
+			}
+		}
+	}
+
+	return entity.QuestionDuplicateReport{
+		Exam:      exam,
+		Threshold: threshold,
+		Compared:  len(signatures),
+		Clusters:  set.clusters(signatures),
+	}, nil
+}
+
+type signedQuestion struct {
+	text string
+	sig  minhash.Signature
+}
+
+func (uc *UseCase) loadSignatures(ctx context.Context, ids map[uuid.UUID]struct{}) (map[uuid.UUID]signedQuestion, error) {
+	out := make(map[uuid.UUID]signedQuestion, len(ids))
+
+	chunk := make([]uuid.UUID, 0, _signatureGetChunk)
+	flush := func() error {
+		signatures, err := uc.signatures.Get(ctx, chunk)
+		if err != nil {
+			return fmt.Errorf("question - Signatures.Get: %w", err)
+		}
+
+		for _, s := range signatures {
+			sig, err := minhash.FromBytes(s.Signature)
+			if err != nil {
+				return fmt.Errorf("question - signature of %s: %w", s.QuestionID, err)
+			}
+			out[s.QuestionID] = signedQuestion{text: s.QuestionText, sig: sig}
+		}
+		chunk = chunk[:0]
+
+		return nil
+	}
+
+	for id := range ids {
+		chunk = append(chunk, id)
+		if len(chunk) == _signatureGetChunk {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(chunk) > 0 {
+		if err := flush(); err != nil {
+			return nil, err
+		}
+	}
+
+	return out, nil
+}
+
+// clusterSet is a union-find over question IDs that remembers, per cluster,
+// the weakest pair that joined it and, per question, its best match.
+type clusterSet struct {
+	parent  map[uuid.UUID]uuid.UUID
+	weakest map[uuid.UUID]float64
+	best    map[uuid.UUID]float64
+}
+
+func newClusterSet() *clusterSet {
+	return &clusterSet{
+		parent:  make(map[uuid.UUID]uuid.UUID),
+		weakest: make(map[uuid.UUID]float64),
+		best:    make(map[uuid.UUID]float64),
+	}
+}
+
+func (s *clusterSet) find(id uuid.UUID) uuid.UUID {
+	parent, ok := s.parent[id]
+	if !ok || parent == id {
+		return id
+	}
+
+	root := s.find(parent)
+	s.parent[id] = root
+
+	return root
+}
+
+func (s *clusterSet) union(a, b uuid.UUID, similarity float64) {
+	ra, rb := s.find(a), s.find(b)
+
+	weakest := similarity
+	for _, root := range []uuid.UUID{ra, rb} {
+		if w, ok := s.weakest[root]; ok && w < weakest {
+			weakest = w
+		}
+	}
+
+	delete(s.weakest, rb)
+	s.parent[ra] = ra
+	s.parent[rb] = ra
+	s.weakest[ra] = weakest
+
+	for _, id := range []uuid.UUID{a, b} {
+		s.best[id] = max(s.best[id], similarity)
+	}
+}
+
+// clusters lists the clusters largest first, their questions by best match.
+func (s *clusterSet) clusters(signatures map[uuid.UUID]signedQuestion) []entity.QuestionDuplicateCluster {
+	members := make(map[uuid.UUID][]entity.SimilarQuestion)
+	for id, similarity := range s.best {
+		root := s.find(id)
+		members[root] = append(members[root], entity.SimilarQuestion{
+			QuestionID:   id,
+			QuestionText: signatures[id].text,
+			Similarity:   similarity,
+		})
+	}
+
+	out := make([]entity.QuestionDuplicateCluster, 0, len(members))
+	for root, questions := range members {
+		slices.SortFunc(questions, compareSimilar)
+		out = append(out, entity.QuestionDuplicateCluster{Questions: questions, Similarity: s.weakest[root]})
+	}
+
+	slices.SortFunc(out, func(a, b entity.QuestionDuplicateCluster) int {
+		return cmp.Or(
+			cmp.Compare(len(b.Questions), len(a.Questions)),
+			cmp.Compare(b.Similarity, a.Similarity),
+			compareSimilar(a.Questions[0], b.Questions[0]),
+		)
+	})
+
+	return out
+}
+
+// duplicateFinder matches signatures against a set of known questions.
+type duplicateFinder struct {
+	index     *minhash.Index[int]
+	questions []entity.SimilarQuestion
+	sigs      []minhash.Signature
+}
+
+func newDuplicateFinder() *duplicateFinder {
+	return &duplicateFinder{index: minhash.NewIndex[int]()}
+}
+
+// signedFinder loads the signed questions of exam that share a band with
+// any of sigs.
+func (uc *UseCase) signedFinder(ctx context.Context, exam entity.ExamCategory, sigs []minhash.Signature) (*duplicateFinder, error) {
+	f := newDuplicateFinder()
+	if len(sigs) == 0 {
+		return f, nil
+	}
+
+	seen := make(map[int64]struct{}, len(sigs)*minhash.Bands)
+	keys := make([]int64, 0, len(sigs)*minhash.Bands)
+	for _, sig := range sigs {
+		for _, key := range sig.BandKeys() {
+			if _, ok := seen[key]; !ok {
+				seen[key] = struct{}{}
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	signed, err := uc.signatures.Candidates(ctx, exam, keys)
+	if err != nil {
+		return nil, fmt.Errorf("question - Signatures.Candidates: %w", err)
+	}
+
+	for _, s := range signed {
+		sig, err := minhash.FromBytes(s.Signature)
+		if err != nil {
+			return nil, fmt.Errorf("question - signature of %s: %w", s.QuestionID, err)
+		}
+		f.add(entity.SimilarQuestion{QuestionID: s.QuestionID, QuestionText: s.QuestionText}, sig)
+	}
+
+	return f, nil
+}
+
+func (f *duplicateFinder) add(q entity.SimilarQuestion, sig minhash.Signature) {
+	f.index.Add(len(f.questions), sig.BandKeys())
+	f.questions = append(f.questions, q)
+	f.sigs = append(f.sigs, sig)
+}
+
+// find returns the known questions other than self that are likely
+// duplicates of sig.
+func (f *duplicateFinder) find(sig minhash.Signature, self uuid.UUID) []entity.SimilarQuestion {
+	var out []entity.SimilarQuestion
+	for _, i := range f.index.Candidates(sig.BandKeys()) {
+		if f.questions[i].QuestionID == self {
+			continue
+		}
+
+		if similarity := sig.Similarity(f.sigs[i]); similarity >= DefaultDuplicateThreshold {
+			match := f.questions[i]
+			match.Similarity = similarity
+			out = append(out, match)
+		}
+	}
+
+	return out
+}
+
+// topSimilar sorts matches best first and keeps the first _maxSimilar.
+func topSimilar(matches []entity.SimilarQuestion) []entity.SimilarQuestion {
+	slices.SortFunc(matches, compareSimilar)
+
+	return matches[:min(len(matches), _maxSimilar)]
+}
+
+func compareSimilar(a, b entity.SimilarQuestion) int {
+	return cmp.Or(
+		cmp.Compare(b.Similarity, a.Similarity),
+		cmp.Compare(a.Row, b.Row),
+		cmp.Compare(a.QuestionID.String(), b.QuestionID.String()),
+	)
+}
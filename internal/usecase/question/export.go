@@ -0,0 +1,136 @@
+package question
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/repo"
+)
+
+// exporter writes questions in one export format. close finishes the file
+// but does not flush the underlying writer.
+type exporter interface {
+	write(q entity.QuestionExport) error
+	close() error
+}
+
+// ExportFile returns the content type and file extension of an export format,
+// or ErrUnsupportedFormat.
+func ExportFile(format entity.QuestionExportFormat) (contentType, extension string, err error) {
+	switch format {
+	case entity.QuestionExportCSV:
+		return "text/csv; charset=utf-8", "csv", nil
+	case entity.QuestionExportNDJSON:
+		return "application/x-ndjson", "ndjson", nil
+	case entity.QuestionExportGIFT:
+		return "text/plain; charset=utf-8", "gift.txt", nil
+	case entity.QuestionExportQTI:
+		return "application/zip", "qti.zip", nil
+	default:
+		return "", "", fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+}
+
+// Export streams the questions matching filter to w, one row at a time, so the
+// bank is never held in memory. A failure after the first write leaves w
+// with a truncated file.
+func (uc *UseCase) Export(
+	ctx context.Context,
+	filter repo.QuestionFilter,
+	format entity.QuestionExportFormat,
+	w io.Writer,
+) error {
+	buf := bufio.NewWriter(w)
+
+	var out exporter
+	switch format {
+	case entity.QuestionExportCSV:
+		out = newCSVExporter(buf)
+	case entity.QuestionExportNDJSON:
+		out = ndjsonExporter{enc: json.NewEncoder(buf)}
+	case entity.QuestionExportGIFT:
+		out = &giftExporter{w: buf}
+	case entity.QuestionExportQTI:
+		out = newQTIExporter(buf)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+
+	names := exportNames{
+		subjects: uc.subjects,
+		topics:   uc.topics,
+		exam:     filter.Exam,
+		topicsOf: make(map[uuid.UUID]bool),
+		names:    make(map[uuid.UUID]string),
+	}
+
+	err := uc.repo.Stream(ctx, filter, func(q entity.Question) error {
+		row := entity.QuestionExport{Question: q}
+
+		var err error
+		if row.SubjectName, row.TopicName, err = names.resolve(ctx, q); err != nil {
+			return err
+		}
+
+		return out.write(row)
+	})
+	if err != nil {
+		return fmt.Errorf("question - Export - Stream: %w", err)
+	}
+
+	if err := out.close(); err != nil {
+		return fmt.Errorf("question - Export - close: %w", err)
+	}
+
+	if err := buf.Flush(); err != nil {
+		return fmt.Errorf("question - Export - Flush: %w", err)
+	}
+
+	return nil
+}
+
+// exportNames looks up subject and topic names as the stream reaches them.
+// Subjects are loaded once; topics one subject at a time.
+type exportNames struct {
+	subjects repo.SubjectRepository
+	topics   repo.TopicRepository
+	exam     *entity.ExamCategory
+
+	loaded   bool
+	topicsOf map[uuid.UUID]bool
+	names    map[uuid.UUID]string
+}
+
+func (n *exportNames) resolve(ctx context.Context, q entity.Question) (subject, topic string, err error) {
+	if !n.loaded {
+		subjects, err := n.subjects.ListByExam(ctx, n.exam)
+		if err != nil {
+			return "", "", fmt.Errorf("subjects: %w", err)
+		}
+
+		for _, s := range subjects {
+			n.names[s.ID] = s.Name
+		}
+		n.loaded = true
+	}
+
+	if !n.topicsOf[q.SubjectID] {
+		topics, err := n.topics.ListBySubject(ctx, q.SubjectID)
+		if err != nil {
+			return "", "", fmt.Errorf("topics: %w", err)
+		}
+
+		for _, t := range topics {
+			n.names[t.ID] = t.Name
+		}
+		n.topicsOf[q.SubjectID] = true
+	}
+
+	return n.names[q.SubjectID], n.names[q.TopicID], nil
+}
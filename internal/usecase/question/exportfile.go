@@ -0,0 +1,253 @@
+package question
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase/scoring"
+)
+
+// exportColumns are the CSV header, named as the importer expects them.
+// The importer ignores tags; set them with the question tags route.
+func exportColumns() []string {
+	return []string{
+		"id", "subject", "topic", "question",
+		"option_a", "option_b", "option_c", "option_d", "correct",
+		"explanation", "reason", "choice_type", "difficulty",
+		"numeric_answer", "numeric_tolerance", "matrix",
+		"is_clinical", "is_image_based", "is_high_yield", "is_active", "tags",
+	}
+}
+
+type csvExporter struct {
+	w      *csv.Writer
+	header bool
+}
+
+func newCSVExporter(w io.Writer) *csvExporter {
+	return &csvExporter{w: csv.NewWriter(w)}
+}
+
+func (e *csvExporter) write(q entity.QuestionExport) error {
+	if !e.header {
+		if err := e.w.Write(exportColumns()); err != nil {
+			return err
+		}
+		e.header = true
+	}
+
+	matrix := ""
+	if q.Matrix != nil {
+		data, err := json.Marshal(q.Matrix)
+		if err != nil {
+			return err
+		}
+		matrix = string(data)
+	}
+
+	return e.w.Write([]string{
+		q.ID.String(), q.SubjectName, q.TopicName, q.QuestionText,
+		q.OptionA, q.OptionB, q.OptionC, q.OptionD, optionLetters(q.Question),
+		deref(q.Explanation), deref(q.ReasonText), string(q.ChoiceType), strconv.Itoa(q.DifficultyLevel),
+		formatFloat(q.NumericAnswer), formatFloat(q.NumericTolerance), matrix,
+		strconv.FormatBool(q.IsClinical), strconv.FormatBool(q.IsImageBased),
+		strconv.FormatBool(q.IsHighYield), strconv.FormatBool(q.IsActive), strings.Join(q.Tags, "; "),
+	})
+}
+
+func (e *csvExporter) close() error {
+	if !e.header {
+		if err := e.w.Write(exportColumns()); err != nil {
+			return err
+		}
+	}
+
+	e.w.Flush()
+
+	return e.w.Error()
+}
+
+type ndjsonExporter struct {
+	enc *json.Encoder
+}
+
+func (e ndjsonExporter) write(q entity.QuestionExport) error {
+	return e.enc.Encode(q)
+}
+
+func (ndjsonExporter) close() error { return nil }
+
+// optionLetters renders the answer key as letters, such as "A,C". Numerical
+// and matrix questions have none.
+func optionLetters(q entity.Question) string {
+	if q.ChoiceType == entity.ChoiceTypeNumerical || q.ChoiceType == entity.ChoiceTypeMatrixMatch {
+		return ""
+	}
+
+	key := scoring.AnswerKey(q)
+	letters := make([]string, len(key))
+	for i, option := range key {
+		letters[i] = optionLetter(option)
+	}
+
+	return strings.Join(letters, ",")
+}
+
+// optionLetter turns a 1-based option into "A" to "D".
+func optionLetter(option int) string {
+	return string(rune('A' + option - 1))
+}
+
+func formatFloat(value *float64) string {
+	if value == nil {
+		return ""
+	}
+
+	return strconv.FormatFloat(*value, 'f', -1, 64)
+}
+
+// giftExporter writes Moodle GIFT. Categories follow subject and topic, which
+// the stream keeps together.
+type giftExporter struct {
+	w        io.Writer
+	category string
+}
+
+func (e *giftExporter) write(q entity.QuestionExport) error {
+	category := giftCategory(q.SubjectName) + "/" + giftCategory(q.TopicName)
+	if category != e.category {
+		if _, err := fmt.Fprintf(e.w, "$CATEGORY: %s\n\n", category); err != nil {
+			return err
+		}
+		e.category = category
+	}
+
+	answers, err := giftAnswers(q.Question)
+	if err != nil {
+		_, err = fmt.Fprintf(e.w, "// question %s skipped: %v\n\n", q.ID, err)
+
+		return err
+	}
+
+	text := q.QuestionText
+	if q.ReasonText != nil {
+		text += "\nReason: " + *q.ReasonText
+	}
+
+	feedback := ""
+	if q.Explanation != nil {
+		feedback = " ####" + giftEscape(*q.Explanation)
+	}
+
+	_, err = fmt.Fprintf(e.w, "::%s::%s {%s%s}\n\n", q.ID, giftEscape(text), answers, feedback)
+
+	return err
+}
+
+func (*giftExporter) close() error { return nil }
+
+// giftAnswers renders the answer block of a question, without the braces.
+func giftAnswers(q entity.Question) (string, error) {
+	switch q.ChoiceType {
+	case entity.ChoiceTypeNumerical:
+		if q.NumericAnswer == nil {
+			return "", errors.New("no numeric answer")
+		}
+
+		tolerance := 0.0
+		if q.NumericTolerance != nil {
+			tolerance = *q.NumericTolerance
+		}
+
+		return fmt.Sprintf("#%s:%s", formatFloat(q.NumericAnswer), formatFloat(&tolerance)), nil
+	case entity.ChoiceTypeMatrixMatch:
+		return giftMatching(q.Matrix)
+	}
+
+	key := scoring.AnswerKey(q)
+	if len(key) == 0 {
+		return "", errors.New("no answer key")
+	}
+
+	var b strings.Builder
+	for i, option := range questionOptions(q) {
+		correct := slices.Contains(key, i+1)
+
+		switch {
+		case q.ChoiceType != entity.ChoiceTypeMulti && correct:
+			b.WriteString(" =")
+		case q.ChoiceType != entity.ChoiceTypeMulti:
+			b.WriteString(" ~")
+		case correct:
+			fmt.Fprintf(&b, " ~%%%s%%", strconv.FormatFloat(100/float64(len(key)), 'f', -1, 64))
+		default:
+			b.WriteString(" ~%-100%")
+		}
+		b.WriteString(giftEscape(option))
+	}
+
+	return b.String(), nil
+}
+
+// giftMatching turns a matrix into a GIFT matching question. GIFT pairs each
+// row with exactly one column, so matrices with other answers are skipped.
+// Columns no row picks are written as distractors.
+func giftMatching(m *entity.MatrixMatch) (string, error) {
+	if m == nil || len(m.Answer) != len(m.Rows) {
+		return "", errors.New("no matrix answer")
+	}
+
+	used := make(map[int]bool, len(m.Columns))
+
+	var b strings.Builder
+	for i, row := range m.Rows {
+		if len(m.Answer[i]) != 1 || m.Answer[i][0] < 1 || m.Answer[i][0] > len(m.Columns) {
+			return "", errors.New("GIFT matching needs exactly one column per row")
+		}
+
+		column := m.Answer[i][0]
+		used[column] = true
+		fmt.Fprintf(&b, " =%s -> %s", giftEscape(row), giftEscape(m.Columns[column-1]))
+	}
+
+	for i, column := range m.Columns {
+		if !used[i+1] {
+			fmt.Fprintf(&b, " = -> %s", giftEscape(column))
+		}
+	}
+
+	return b.String(), nil
+}
+
+// giftEscape escapes GIFT's control characters and keeps text on one line.
+func giftEscape(text string) string {
+	return strings.NewReplacer(
+		`\`, `\\`, `~`, `\~`, `=`, `\=`, `#`, `\#`,
+		`{`, `\{`, `}`, `\}`, `:`, `\:`,
+		"\r\n", `\n`, "\n", `\n`,
+	).Replace(text)
+}
+
+// giftCategory keeps a slash inside a name from starting a subcategory.
+func giftCategory(name string) string {
+	return strings.ReplaceAll(strings.TrimSpace(name), "/", "//")
+}
+
+// questionOptions returns options A to D, stopping at the first empty one.
+func questionOptions(q entity.Question) []string {
+	options := []string{q.OptionA, q.OptionB, q.OptionC, q.OptionD}
+	for i, option := range options {
+		if option == "" {
+			return options[:i]
+		}
+	}
+
+	return options
+}
@@ -0,0 +1,332 @@
+package question
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase/scoring"
+)
+
+const (
+	_qtiResponse   = "RESPONSE"
+	_qtiScore      = "SCORE"
+	_qtiTitleRunes = 80
+)
+
+// qtiExporter writes an IMS QTI 2.1 content package: one item file per
+// question, then the manifest. Only item identifiers are kept until the end.
+type qtiExporter struct {
+	zip   *zip.Writer
+	items []string
+}
+
+func newQTIExporter(w io.Writer) *qtiExporter {
+	return &qtiExporter{zip: zip.NewWriter(w)}
+}
+
+func (e *qtiExporter) write(q entity.QuestionExport) error {
+	item, ok := qtiAssessmentItem(q)
+	if !ok {
+		return nil
+	}
+
+	f, err := e.zip.Create(qtiItemPath(item.Identifier))
+	if err != nil {
+		return err
+	}
+
+	if err := writeXML(f, item); err != nil {
+		return err
+	}
+	e.items = append(e.items, item.Identifier)
+
+	return nil
+}
+
+func (e *qtiExporter) close() error {
+	manifest := qtiManifest{
+		Identifier:    "MANIFEST-questions",
+		Schema:        "QTIv2.1 Package",
+		SchemaVersion: "1.0.0",
+		Resources:     make([]qtiResource, len(e.items)),
+	}
+	for i, id := range e.items {
+		manifest.Resources[i] = qtiResource{
+			Identifier: id,
+			Type:       "imsqti_item_xmlv2p1",
+			Href:       qtiItemPath(id),
+			File:       qtiHref{Href: qtiItemPath(id)},
+		}
+	}
+
+	f, err := e.zip.Create("imsmanifest.xml")
+	if err != nil {
+		return err
+	}
+
+	if err := writeXML(f, manifest); err != nil {
+		return err
+	}
+
+	return e.zip.Close()
+}
+
+// qtiAssessmentItem maps a question to a QTI item. Questions without a usable
+// answer key are left out of the package.
+func qtiAssessmentItem(q entity.QuestionExport) (qtiItem, bool) {
+	item := qtiItem{
+		Identifier: "q-" + q.ID.String(),
+		Title:      truncateRunes(q.QuestionText, _qtiTitleRunes),
+		Response:   qtiDeclaration{Identifier: _qtiResponse, Cardinality: "single", BaseType: "identifier"},
+		Outcome:    qtiDeclaration{Identifier: _qtiScore, Cardinality: "single", BaseType: "float"},
+		Body:       qtiBody{Paragraphs: []qtiParagraph{{Text: q.QuestionText}}},
+		Processing: qtiProcessing{Template: "http://www.imsglobal.org/question/qti_v2p1/rptemplates/match_correct"},
+	}
+	if q.Explanation != nil {
+		item.Body.Rubric = &qtiRubric{View: "scorer", Paragraph: *q.Explanation}
+	}
+	if q.ReasonText != nil {
+		item.Body.Paragraphs = append(item.Body.Paragraphs, qtiParagraph{Text: "Reason: " + *q.ReasonText})
+	}
+
+	switch q.ChoiceType {
+	case entity.ChoiceTypeNumerical:
+		if q.NumericAnswer == nil {
+			return qtiItem{}, false
+		}
+
+		tolerance := 0.0
+		if q.NumericTolerance != nil {
+			tolerance = *q.NumericTolerance
+		}
+
+		item.Response.BaseType = "float"
+		item.Response.Correct = &qtiValues{Values: []string{formatFloat(q.NumericAnswer)}}
+		item.Body.Paragraphs = append(item.Body.Paragraphs, qtiParagraph{
+			Entry: &qtiTextEntry{ResponseIdentifier: _qtiResponse},
+		})
+		item.Processing = qtiProcessing{Condition: &qtiCondition{If: qtiIf{
+			Equal: qtiEqual{
+				ToleranceMode: "absolute",
+				Tolerance:     formatFloat(&tolerance),
+				Variable:      qtiRef{Identifier: _qtiResponse},
+				Correct:       qtiRef{Identifier: _qtiResponse},
+			},
+			Set: qtiSetOutcome{Identifier: _qtiScore, Value: qtiBaseValue{BaseType: "float", Value: "1"}},
+		}}}
+	case entity.ChoiceTypeMatrixMatch:
+		m := q.Matrix
+		if m == nil || len(m.Answer) != len(m.Rows) {
+			return qtiItem{}, false
+		}
+
+		rows := make([]qtiAssociable, len(m.Rows))
+		for i, row := range m.Rows {
+			rows[i] = qtiAssociable{Identifier: fmt.Sprintf("R%d", i+1), MatchMax: len(m.Columns), Text: row}
+		}
+		columns := make([]qtiAssociable, len(m.Columns))
+		for i, column := range m.Columns {
+			columns[i] = qtiAssociable{Identifier: fmt.Sprintf("C%d", i+1), MatchMax: len(m.Rows), Text: column}
+		}
+
+		correct := &qtiValues{}
+		for i, picks := range m.Answer {
+			for _, column := range picks {
+				correct.Values = append(correct.Values, fmt.Sprintf("R%d C%d", i+1, column))
+			}
+		}
+
+		item.Response = qtiDeclaration{
+			Identifier:  _qtiResponse,
+			Cardinality: "multiple",
+			BaseType:    "directedPair",
+			Correct:     correct,
+		}
+		item.Body.Match = &qtiMatch{
+			ResponseIdentifier: _qtiResponse,
+			Sets:               []qtiMatchSet{{Choices: rows}, {Choices: columns}},
+		}
+	default:
+		key := scoring.AnswerKey(q.Question)
+		if len(key) == 0 {
+			return qtiItem{}, false
+		}
+
+		correct := &qtiValues{Values: make([]string, len(key))}
+		for i, option := range key {
+			correct.Values[i] = optionLetter(option)
+		}
+		item.Response.Correct = correct
+
+		choice := &qtiChoice{ResponseIdentifier: _qtiResponse, MaxChoices: 1}
+		if q.ChoiceType == entity.ChoiceTypeMulti {
+			item.Response.Cardinality = "multiple"
+			choice.MaxChoices = 0
+		}
+		for i, option := range questionOptions(q.Question) {
+			choice.Choices = append(choice.Choices, qtiSimpleChoice{Identifier: optionLetter(i + 1), Text: option})
+		}
+		item.Body.Choice = choice
+	}
+
+	return item, true
+}
+
+func qtiItemPath(identifier string) string {
+	return "items/" + identifier + ".xml"
+}
+
+func writeXML(w io.Writer, v any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+
+	return enc.Close()
+}
+
+func truncateRunes(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+
+	return string(runes[:limit-1]) + "…"
+}
+
+type qtiItem struct {
+	XMLName       xml.Name       `xml:"http://www.imsglobal.org/xsd/imsqti_v2p1 assessmentItem"`
+	Identifier    string         `xml:"identifier,attr"`
+	Title         string         `xml:"title,attr"`
+	Adaptive      bool           `xml:"adaptive,attr"`
+	TimeDependent bool           `xml:"timeDependent,attr"`
+	Response      qtiDeclaration `xml:"responseDeclaration"`
+	Outcome       qtiDeclaration `xml:"outcomeDeclaration"`
+	Body          qtiBody        `xml:"itemBody"`
+	Processing    qtiProcessing  `xml:"responseProcessing"`
+}
+
+type qtiDeclaration struct {
+	Identifier  string     `xml:"identifier,attr"`
+	Cardinality string     `xml:"cardinality,attr"`
+	BaseType    string     `xml:"baseType,attr"`
+	Correct     *qtiValues `xml:"correctResponse"`
+}
+
+type qtiValues struct {
+	Values []string `xml:"value"`
+}
+
+type qtiBody struct {
+	Rubric     *qtiRubric     `xml:"rubricBlock"`
+	Paragraphs []qtiParagraph `xml:"p"`
+	Choice     *qtiChoice     `xml:"choiceInteraction"`
+	Match      *qtiMatch      `xml:"matchInteraction"`
+}
+
+type qtiRubric struct {
+	View      string `xml:"view,attr"`
+	Paragraph string `xml:"p"`
+}
+
+type qtiParagraph struct {
+	Text  string        `xml:",chardata"`
+	Entry *qtiTextEntry `xml:"textEntryInteraction"`
+}
+
+type qtiTextEntry struct {
+	ResponseIdentifier string `xml:"responseIdentifier,attr"`
+}
+
+type qtiChoice struct {
+	ResponseIdentifier string            `xml:"responseIdentifier,attr"`
+	Shuffle            bool              `xml:"shuffle,attr"`
+	MaxChoices         int               `xml:"maxChoices,attr"`
+	Choices            []qtiSimpleChoice `xml:"simpleChoice"`
+}
+
+type qtiSimpleChoice struct {
+	Identifier string `xml:"identifier,attr"`
+	Text       string `xml:",chardata"`
+}
+
+type qtiMatch struct {
+	ResponseIdentifier string        `xml:"responseIdentifier,attr"`
+	Shuffle            bool          `xml:"shuffle,attr"`
+	MaxAssociations    int           `xml:"maxAssociations,attr"`
+	Sets               []qtiMatchSet `xml:"simpleMatchSet"`
+}
+
+type qtiMatchSet struct {
+	Choices []qtiAssociable `xml:"simpleAssociableChoice"`
+}
+
+type qtiAssociable struct {
+	Identifier string `xml:"identifier,attr"`
+	MatchMax   int    `xml:"matchMax,attr"`
+	Text       string `xml:",chardata"`
+}
+
+type qtiProcessing struct {
+	Template  string        `xml:"template,attr,omitempty"`
+	Condition *qtiCondition `xml:"responseCondition"`
+}
+
+type qtiCondition struct {
+	If qtiIf `xml:"responseIf"`
+}
+
+type qtiIf struct {
+	Equal qtiEqual      `xml:"equal"`
+	Set   qtiSetOutcome `xml:"setOutcomeValue"`
+}
+
+type qtiEqual struct {
+	ToleranceMode string `xml:"toleranceMode,attr"`
+	Tolerance     string `xml:"tolerance,attr"`
+	Variable      qtiRef `xml:"variable"`
+	Correct       qtiRef `xml:"correct"`
+}
+
+type qtiRef struct {
+	Identifier string `xml:"identifier,attr"`
+}
+
+type qtiSetOutcome struct {
+	Identifier string       `xml:"identifier,attr"`
+	Value      qtiBaseValue `xml:"baseValue"`
+}
+
+type qtiBaseValue struct {
+	BaseType string `xml:"baseType,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type qtiManifest struct {
+	XMLName       xml.Name      `xml:"http://www.imsglobal.org/xsd/imscp_v1p1 manifest"`
+	Identifier    string        `xml:"identifier,attr"`
+	Schema        string        `xml:"metadata>schema"`
+	SchemaVersion string        `xml:"metadata>schemaversion"`
+	Organizations struct{}      `xml:"organizations"`
+	Resources     []qtiResource `xml:"resources>resource"`
+}
+
+type qtiResource struct {
+	Identifier string  `xml:"identifier,attr"`
+	Type       string  `xml:"type,attr"`
+	Href       string  `xml:"href,attr"`
+	File       qtiHref `xml:"file"`
+}
+
+type qtiHref struct {
+	Href string `xml:"href,attr"`
+}
@@ -0,0 +1,490 @@
+package question
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/repo"
+	"github.com/evrone/go-clean-template/pkg/minhash"
+)
+
+const (
+	// _importBatchSize is how many rows are imported between progress saves.
+	_importBatchSize = 200
+	// _maxImportErrors bounds the row errors kept; FailedRows still counts all.
+	_maxImportErrors = 500
+	// _importStaleAfter is how long a running job may go without progress
+	// before another worker takes it over.
+	_importStaleAfter = 10 * time.Minute
+	_importListLimit  = 50
+	// _defaultTopic files questions whose row names no topic, as the MedMCQA
+	// dump does for most of its rows.
+	_defaultTopic = "General"
+)
+
+// DryRunImport validates an import file without writing anything and reports
+// what an import would do. With CreateMissing, unknown subjects and topics
+// are listed as new instead of failing their rows.
+func (uc *UseCase) DryRunImport(ctx context.Context, opts entity.QuestionImportOptions, fileName string, data []byte) (entity.QuestionImportReport, error) {
+	format, err := importFormat(opts.Format, fileName)
+	if err != nil {
+		return entity.QuestionImportReport{}, err
+	}
+
+	records, err := parseImportFile(format, data, uc.opts.ImportLimit)
+	if err != nil {
+		return entity.QuestionImportReport{}, err
+	}
+
+	run := uc.newImportRun(opts, uuid.Nil, true)
+	for start := 0; start < len(records); start += _importBatchSize {
+		if err := run.batch(ctx, records[start:min(start+_importBatchSize, len(records))]); err != nil {
+			return entity.QuestionImportReport{}, err
+		}
+	}
+
+	return entity.QuestionImportReport{
+		Exam:          opts.Exam,
+		Format:        format,
+		TotalRows:     len(records),
+		ValidRows:     run.imported,
+		SkippedRows:   run.skipped,
+		FailedRows:    run.failed,
+		NewSubjects:   run.names.newSubjects,
+		NewTopics:     run.names.newTopics,
+		Errors:        run.errors,
+		DuplicateRows: run.duplicateRows,
+		Duplicates:    run.duplicates,
+	}, nil
+}
+
+// StartImport checks that the file can be read and queues it for the
+// background importer.
+func (uc *UseCase) StartImport(ctx context.Context, createdBy uuid.UUID, opts entity.QuestionImportOptions, fileName string, data []byte) (entity.QuestionImportJob, error) {
+	format, err := importFormat(opts.Format, fileName)
+	if err != nil {
+		return entity.QuestionImportJob{}, err
+	}
+
+	records, err := parseImportFile(format, data, uc.opts.ImportLimit)
+	if err != nil {
+		return entity.QuestionImportJob{}, err
+	}
+
+	job, err := uc.imports.Create(ctx, entity.QuestionImportJob{
+		ID:            uuid.New(),
+		Exam:          opts.Exam,
+		Format:        format,
+		FileName:      fileName,
+		CreateMissing: opts.CreateMissing,
+		TotalRows:     len(records),
+		CreatedBy:     createdBy,
+	}, data)
+	if errors.Is(err, repo.ErrNotFound) {
+		return entity.QuestionImportJob{}, ErrUnknownExam
+	}
+	if err != nil {
+		return entity.QuestionImportJob{}, fmt.Errorf("question - Imports.Create: %w", err)
+	}
+
+	return job, nil
+}
+
+// GetImport returns an import job with its progress.
+func (uc *UseCase) GetImport(ctx context.Context, id uuid.UUID) (entity.QuestionImportJob, error) {
+	job, err := uc.imports.Get(ctx, id)
+	if errors.Is(err, repo.ErrNotFound) {
+		return entity.QuestionImportJob{}, ErrImportNotFound
+	}
+	if err != nil {
+		return entity.QuestionImportJob{}, fmt.Errorf("question - Imports.Get: %w", err)
+	}
+
+	return job, nil
+}
+
+// ListImports returns the most recent import jobs, newest first.
+func (uc *UseCase) ListImports(ctx context.Context) ([]entity.QuestionImportJob, error) {
+	jobs, err := uc.imports.List(ctx, _importListLimit)
+	if err != nil {
+		return nil, fmt.Errorf("question - Imports.List: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// RunImports works through queued import jobs until none is left and
+// returns how many it finished.
+func (uc *UseCase) RunImports(ctx context.Context) (int, error) {
+	finished := 0
+	for ctx.Err() == nil {
+		job, payload, err := uc.imports.ClaimNext(ctx, time.Now().Add(-_importStaleAfter))
+		if errors.Is(err, repo.ErrNotFound) {
+			break
+		}
+		if err != nil {
+			return finished, fmt.Errorf("question - Imports.ClaimNext: %w", err)
+		}
+
+		if err := uc.runImport(ctx, job, payload); err != nil {
+			return finished, err
+		}
+		finished++
+	}
+
+	return finished, nil
+}
+
+// runImport imports a claimed job, saving progress after every batch. A job
+// taken over from a stalled worker resumes after its last saved batch; rows
+// of the interrupted batch that were already written come up as skipped,
+// because row IDs are derived from the job and row number. Rows imported
+// before the takeover are only matched as duplicates once signed.
+func (uc *UseCase) runImport(ctx context.Context, job entity.QuestionImportJob, payload []byte) error {
+	records, err := parseImportFile(job.Format, payload, uc.opts.ImportLimit)
+	if err != nil {
+		return uc.finishImport(ctx, job, err)
+	}
+
+	job.TotalRows = len(records)
+	opts := entity.QuestionImportOptions{Exam: job.Exam, Format: job.Format, CreateMissing: job.CreateMissing}
+
+	run := uc.newImportRun(opts, job.ID, false)
+	run.author = &job.CreatedBy
+	run.imported, run.skipped, run.failed, run.errors = job.ImportedRows, job.SkippedRows, job.FailedRows, job.Errors
+	run.duplicateRows, run.duplicates = job.DuplicateRows, job.Duplicates
+
+	for start := min(job.ProcessedRows, len(records)); start < len(records); start += _importBatchSize {
+		end := min(start+_importBatchSize, len(records))
+		if err := run.batch(ctx, records[start:end]); err != nil {
+			return uc.finishImport(ctx, job, err)
+		}
+
+		job.ProcessedRows = end
+		job.ImportedRows, job.SkippedRows, job.FailedRows, job.Errors = run.imported, run.skipped, run.failed, run.errors
+		job.DuplicateRows, job.Duplicates = run.duplicateRows, run.duplicates
+
+		if end < len(records) {
+			if err := uc.imports.Save(ctx, job); err != nil {
+				return fmt.Errorf("question - Imports.Save: %w", err)
+			}
+		}
+	}
+
+	return uc.finishImport(ctx, job, nil)
+}
+
+// finishImport marks the job completed, or failed with cause.
+func (uc *UseCase) finishImport(ctx context.Context, job entity.QuestionImportJob, cause error) error {
+	now := time.Now().UTC()
+	job.FinishedAt = &now
+	job.Status = entity.QuestionImportCompleted
+	if cause != nil {
+		message := cause.Error()
+		job.Status = entity.QuestionImportFailed
+		job.Error = &message
+	}
+
+	if err := uc.imports.Save(context.WithoutCancel(ctx), job); err != nil {
+		return fmt.Errorf("question - Imports.Save: %w", err)
+	}
+
+	return nil
+}
+
+// importRun validates and, unless dryRun, writes the rows of one import.
+// inFile matches rows against the earlier rows of the same file.
+type importRun struct {
+	uc            *UseCase
+	opts          entity.QuestionImportOptions
+	jobID         uuid.UUID
+	dryRun        bool
+	author        *uuid.UUID
+	names         *nameResolver
+	seen          map[uuid.UUID]struct{}
+	inFile        *duplicateFinder
+	imported      int
+	skipped       int
+	failed        int
+	errors        []entity.QuestionImportRowError
+	duplicateRows int
+	duplicates    []entity.QuestionImportDuplicate
+}
+
+func (uc *UseCase) newImportRun(opts entity.QuestionImportOptions, jobID uuid.UUID, dryRun bool) *importRun {
+	return &importRun{
+		uc:         uc,
+		opts:       opts,
+		jobID:      jobID,
+		dryRun:     dryRun,
+		names:      newNameResolver(uc.subjects, uc.topics, opts, dryRun),
+		seen:       make(map[uuid.UUID]struct{}),
+		inFile:     newDuplicateFinder(),
+		errors:     []entity.QuestionImportRowError{},
+		duplicates: []entity.QuestionImportDuplicate{},
+	}
+}
+
+// batch handles a slice of rows: row problems and likely duplicates are
+// recorded, anything else stops the import.
+func (run *importRun) batch(ctx context.Context, records []importRecord) error {
+	rows := make([]int, 0, len(records))
+	questions := make([]entity.Question, 0, len(records))
+	ids := make([]uuid.UUID, 0, len(records))
+
+	for _, rec := range records {
+		q, err := run.question(ctx, rec)
+		if isRowError(err) {
+			run.reject(rec.row, err)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		rows = append(rows, rec.row)
+		questions = append(questions, q)
+		ids = append(ids, q.ID)
+	}
+
+	existing, err := run.uc.repo.ExistingIDs(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("question - ExistingIDs: %w", err)
+	}
+
+	inBank := make(map[uuid.UUID]struct{}, len(existing))
+	for _, id := range existing {
+		inBank[id] = struct{}{}
+	}
+
+	fresh := make([]int, 0, len(questions))
+	sigs := make([]minhash.Signature, 0, len(questions))
+	for i, q := range questions {
+		if _, ok := inBank[q.ID]; ok {
+			run.skipped++
+			continue
+		}
+
+		fresh = append(fresh, i)
+		sigs = append(sigs, minhash.New(q.QuestionText))
+	}
+
+	signed, err := run.uc.signedFinder(ctx, run.opts.Exam, sigs)
+	if err != nil {
+		return err
+	}
+
+	for n, i := range fresh {
+		q, sig := questions[i], sigs[n]
+		run.checkDuplicates(rows[i], q, sig, signed)
+
+		if !run.dryRun {
+			if _, err := run.uc.repo.Create(ctx, entity.QuestionVersion{Question: q, AuthorID: run.author}); err != nil {
+				return fmt.Errorf("question - Create row %d: %w", rows[i], err)
+			}
+		}
+		run.imported++
+	}
+
+	return nil
+}
+
+// question builds the question of one row and resolves its subject and topic.
+func (run *importRun) question(ctx context.Context, rec importRecord) (entity.Question, error) {
+	q, err := importQuestion(rec, run.opts.Exam)
+	if err != nil {
+		return entity.Question{}, err
+	}
+
+	q.ID, err = run.rowID(rec)
+	if err != nil {
+		return entity.Question{}, err
+	}
+
+	q.SubjectID, q.TopicID, err = run.names.resolve(ctx, rec.subject, rec.topic)
+	if err != nil {
+		return entity.Question{}, err
+	}
+
+	return q, nil
+}
+
+// rowID uses the file's own ID when given. Otherwise real imports derive one
+// from the job and row, so a resumed job does not insert a row twice.
+func (run *importRun) rowID(rec importRecord) (uuid.UUID, error) {
+	var id uuid.UUID
+	switch {
+	case rec.id != "":
+		parsed, err := uuid.Parse(rec.id)
+		if err != nil {
+			return uuid.Nil, rowErrorf("invalid id %q", rec.id)
+		}
+		id = parsed
+	case run.dryRun:
+		return uuid.New(), nil
+	default:
+		id = uuid.NewSHA1(run.jobID, []byte(strconv.Itoa(rec.row)))
+	}
+
+	if _, dup := run.seen[id]; dup {
+		return uuid.Nil, rowErrorf("id %s appears more than once in the file", id)
+	}
+	run.seen[id] = struct{}{}
+
+	return id, nil
+}
+
+// checkDuplicates warns about the row if it looks like a signed question or
+// an earlier row, then makes it known to later rows.
+func (run *importRun) checkDuplicates(row int, q entity.Question, sig minhash.Signature, signed *duplicateFinder) {
+	matches := append(signed.find(sig, q.ID), run.inFile.find(sig, q.ID)...)
+	if len(matches) > 0 {
+		run.duplicateRows++
+		if len(run.duplicates) < _maxImportDuplicates {
+			run.duplicates = append(run.duplicates, entity.QuestionImportDuplicate{Row: row, Similar: topSimilar(matches)})
+		}
+	}
+
+	run.inFile.add(entity.SimilarQuestion{QuestionID: q.ID, QuestionText: q.QuestionText, Row: row}, sig)
+}
+
+func (run *importRun) reject(row int, err error) {
+	run.failed++
+	if len(run.errors) < _maxImportErrors {
+		run.errors = append(run.errors, entity.QuestionImportRowError{Row: row, Message: err.Error()})
+	}
+}
+
+// nameResolver maps subject and topic names to IDs, case-insensitively.
+// Missing ones are created when the import allows it; in a dry run they are
+// only noted, under placeholder IDs.
+type nameResolver struct {
+	subjects      repo.SubjectRepository
+	topics        repo.TopicRepository
+	exam          entity.ExamCategory
+	createMissing bool
+	dryRun        bool
+	bySubject     map[string]entity.Subject
+	byTopic       map[uuid.UUID]map[string]entity.Topic
+	newSubjects   []string
+	newTopics     []string
+}
+
+func newNameResolver(subjects repo.SubjectRepository, topics repo.TopicRepository, opts entity.QuestionImportOptions, dryRun bool) *nameResolver {
+	return &nameResolver{
+		subjects:      subjects,
+		topics:        topics,
+		exam:          opts.Exam,
+		createMissing: opts.CreateMissing,
+		dryRun:        dryRun,
+		byTopic:       make(map[uuid.UUID]map[string]entity.Topic),
+		newSubjects:   []string{},
+		newTopics:     []string{},
+	}
+}
+
+func (n *nameResolver) resolve(ctx context.Context, subjectName, topicName string) (subjectID, topicID uuid.UUID, err error) {
+	if subjectName == "" {
+		return uuid.Nil, uuid.Nil, rowError("subject is required")
+	}
+	if topicName == "" {
+		topicName = _defaultTopic
+	}
+
+	subject, err := n.subject(ctx, subjectName)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+
+	topic, err := n.topic(ctx, subject, topicName)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+
+	return subject.ID, topic.ID, nil
+}
+
+func (n *nameResolver) subject(ctx context.Context, name string) (entity.Subject, error) {
+	if n.bySubject == nil {
+		exam := n.exam
+		list, err := n.subjects.ListByExam(ctx, &exam)
+		if err != nil {
+			return entity.Subject{}, fmt.Errorf("question - Subjects.ListByExam: %w", err)
+		}
+
+		n.bySubject = make(map[string]entity.Subject, len(list))
+		for _, s := range list {
+			n.bySubject[nameKey(s.Name)] = s
+		}
+	}
+
+	if s, ok := n.bySubject[nameKey(name)]; ok {
+		return s, nil
+	}
+	if !n.createMissing {
+		return entity.Subject{}, rowErrorf("unknown subject %q", name)
+	}
+
+	s := entity.Subject{ID: uuid.New(), Exam: n.exam, Name: name, IsActive: true}
+	if !n.dryRun {
+		created, err := n.subjects.Create(ctx, s)
+		if err != nil {
+			return entity.Subject{}, fmt.Errorf("question - Subjects.Create: %w", err)
+		}
+		s = created
+	}
+
+	n.bySubject[nameKey(name)] = s
+	n.byTopic[s.ID] = make(map[string]entity.Topic)
+	n.newSubjects = append(n.newSubjects, name)
+
+	return s, nil
+}
+
+func (n *nameResolver) topic(ctx context.Context, subject entity.Subject, name string) (entity.Topic, error) {
+	topics, ok := n.byTopic[subject.ID]
+	if !ok {
+		list, err := n.topics.ListBySubject(ctx, subject.ID)
+		if err != nil {
+			return entity.Topic{}, fmt.Errorf("question - Topics.ListBySubject: %w", err)
+		}
+
+		topics = make(map[string]entity.Topic, len(list))
+		for _, t := range list {
+			topics[nameKey(t.Name)] = t
+		}
+		n.byTopic[subject.ID] = topics
+	}
+
+	if t, ok := topics[nameKey(name)]; ok {
+		return t, nil
+	}
+	if !n.createMissing {
+		return entity.Topic{}, rowErrorf("unknown topic %q in subject %q", name, subject.Name)
+	}
+
+	t := entity.Topic{ID: uuid.New(), SubjectID: subject.ID, Name: name, IsActive: true}
+	if !n.dryRun {
+		created, err := n.topics.Create(ctx, t)
+		if err != nil {
+			return entity.Topic{}, fmt.Errorf("question - Topics.Create: %w", err)
+		}
+		t = created
+	}
+
+	topics[nameKey(name)] = t
+	n.newTopics = append(n.newTopics, subject.Name+" / "+name)
+
+	return t, nil
+}
+
+func nameKey(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
@@ -0,0 +1,407 @@
+package question
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/pkg/xlsx"
+)
+
+const (
+	// _maxImportLineBytes bounds a single NDJSON line.
+	_maxImportLineBytes = 1 << 20
+	// _defaultImportLimit bounds each decompressed part of an XLSX file when
+	// Options.ImportLimit is not set.
+	_defaultImportLimit = 32 << 20
+)
+
+// importRecord is one row of an import file, still as text. err is set when
+// the row could not even be read, such as malformed JSON.
+type importRecord struct {
+	row              int
+	id               string
+	subject          string
+	topic            string
+	question         string
+	options          [4]string
+	correct          string
+	explanation      string
+	reason           string
+	choiceType       string
+	difficulty       string
+	numericAnswer    string
+	numericTolerance string
+	matrix           string
+	isClinical       string
+	isImageBased     string
+	isHighYield      string
+	err              error
+}
+
+// rowError rejects a single import row without failing the import.
+type rowError string
+
+func (e rowError) Error() string { return string(e) }
+
+func rowErrorf(format string, args ...any) rowError {
+	return rowError(fmt.Sprintf(format, args...))
+}
+
+// importFormat picks the format the admin asked for, or the one the file
+// extension suggests.
+func importFormat(format entity.QuestionImportFormat, fileName string) (entity.QuestionImportFormat, error) {
+	if format != "" {
+		switch format {
+		case entity.QuestionImportCSV, entity.QuestionImportNDJSON, entity.QuestionImportXLSX:
+			return format, nil
+		default:
+			return "", fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+		}
+	}
+
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".csv":
+		return entity.QuestionImportCSV, nil
+	case ".ndjson", ".jsonl", ".json":
+		return entity.QuestionImportNDJSON, nil
+	case ".xlsx":
+		return entity.QuestionImportXLSX, nil
+	default:
+		return "", fmt.Errorf("%w: cannot tell the format of %q", ErrUnsupportedFormat, fileName)
+	}
+}
+
+// parseImportFile reads the records of an import file. limit bounds each
+// decompressed part of an XLSX file.
+func parseImportFile(format entity.QuestionImportFormat, data []byte, limit int64) ([]importRecord, error) {
+	switch format {
+	case entity.QuestionImportCSV:
+		reader := csv.NewReader(bytes.NewReader(data))
+		reader.FieldsPerRecord = -1
+
+		rows, err := reader.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidImportFile, err)
+		}
+
+		return parseTable(rows)
+	case entity.QuestionImportXLSX:
+		rows, err := xlsx.ReadRows(bytes.NewReader(data), int64(len(data)), limit)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidImportFile, err)
+		}
+
+		return parseTable(rows)
+	case entity.QuestionImportNDJSON:
+		return parseNDJSON(data)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+}
+
+// tableColumns maps normalized header names, including MedMCQA's, to setters.
+func tableColumns() map[string]func(*importRecord, string) {
+	return map[string]func(*importRecord, string){
+		"id":               func(r *importRecord, v string) { r.id = v },
+		"subject":          func(r *importRecord, v string) { r.subject = v },
+		"subjectname":      func(r *importRecord, v string) { r.subject = v },
+		"topic":            func(r *importRecord, v string) { r.topic = v },
+		"topicname":        func(r *importRecord, v string) { r.topic = v },
+		"question":         func(r *importRecord, v string) { r.question = v },
+		"questiontext":     func(r *importRecord, v string) { r.question = v },
+		"optiona":          func(r *importRecord, v string) { r.options[0] = v },
+		"opa":              func(r *importRecord, v string) { r.options[0] = v },
+		"optionb":          func(r *importRecord, v string) { r.options[1] = v },
+		"opb":              func(r *importRecord, v string) { r.options[1] = v },
+		"optionc":          func(r *importRecord, v string) { r.options[2] = v },
+		"opc":              func(r *importRecord, v string) { r.options[2] = v },
+		"optiond":          func(r *importRecord, v string) { r.options[3] = v },
+		"opd":              func(r *importRecord, v string) { r.options[3] = v },
+		"correct":          func(r *importRecord, v string) { r.correct = v },
+		"correctoption":    func(r *importRecord, v string) { r.correct = v },
+		"correctoptions":   func(r *importRecord, v string) { r.correct = v },
+		"cop":              func(r *importRecord, v string) { r.correct = v },
+		"explanation":      func(r *importRecord, v string) { r.explanation = v },
+		"exp":              func(r *importRecord, v string) { r.explanation = v },
+		"reason":           func(r *importRecord, v string) { r.reason = v },
+		"reasontext":       func(r *importRecord, v string) { r.reason = v },
+		"choicetype":       func(r *importRecord, v string) { r.choiceType = v },
+		"difficulty":       func(r *importRecord, v string) { r.difficulty = v },
+		"difficultylevel":  func(r *importRecord, v string) { r.difficulty = v },
+		"numericanswer":    func(r *importRecord, v string) { r.numericAnswer = v },
+		"numerictolerance": func(r *importRecord, v string) { r.numericTolerance = v },
+		"matrix":           func(r *importRecord, v string) { r.matrix = v },
+		"isclinical":       func(r *importRecord, v string) { r.isClinical = v },
+		"isimagebased":     func(r *importRecord, v string) { r.isImageBased = v },
+		"ishighyield":      func(r *importRecord, v string) { r.isHighYield = v },
+	}
+}
+
+// parseTable reads CSV and XLSX rows. The first row is the header; unknown
+// columns are ignored and blank rows skipped.
+func parseTable(rows [][]string) ([]importRecord, error) {
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: the file is empty", ErrInvalidImportFile)
+	}
+
+	known := tableColumns()
+	setters := make([]func(*importRecord, string), len(rows[0]))
+	hasQuestion := false
+	for i, name := range rows[0] {
+		key := normalizeHeader(name)
+		setters[i] = known[key]
+		hasQuestion = hasQuestion || key == "question" || key == "questiontext"
+	}
+	if !hasQuestion {
+		return nil, fmt.Errorf("%w: the header has no question column", ErrInvalidImportFile)
+	}
+
+	records := make([]importRecord, 0, len(rows)-1)
+	for i, row := range rows[1:] {
+		if isBlank(row) {
+			continue
+		}
+
+		rec := importRecord{row: i + 2}
+		for j, value := range row {
+			if j < len(setters) && setters[j] != nil {
+				setters[j](&rec, strings.TrimSpace(value))
+			}
+		}
+		records = append(records, rec)
+	}
+
+	return records, nil
+}
+
+// medMCQARecord is one line of a MedMCQA dump; cop is 1-based.
+type medMCQARecord struct {
+	ID          string  `json:"id"`
+	Question    string  `json:"question"`
+	Explanation *string `json:"exp"`
+	Correct     int     `json:"cop"`
+	OptionA     *string `json:"opa"`
+	OptionB     *string `json:"opb"`
+	OptionC     *string `json:"opc"`
+	OptionD     *string `json:"opd"`
+	SubjectName string  `json:"subject_name"`
+	TopicName   *string `json:"topic_name"`
+	ChoiceType  *string `json:"choice_type"`
+}
+
+func parseNDJSON(data []byte) ([]importRecord, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), _maxImportLineBytes)
+
+	var records []importRecord
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+
+		var m medMCQARecord
+		if err := json.Unmarshal(text, &m); err != nil {
+			records = append(records, importRecord{row: line, err: rowErrorf("invalid JSON: %v", err)})
+			continue
+		}
+
+		records = append(records, importRecord{
+			row:         line,
+			id:          m.ID,
+			subject:     strings.TrimSpace(m.SubjectName),
+			topic:       strings.TrimSpace(deref(m.TopicName)),
+			question:    strings.TrimSpace(m.Question),
+			options:     [4]string{deref(m.OptionA), deref(m.OptionB), deref(m.OptionC), deref(m.OptionD)},
+			correct:     strconv.Itoa(m.Correct),
+			explanation: deref(m.Explanation),
+			choiceType:  deref(m.ChoiceType),
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidImportFile, err)
+	}
+
+	return records, nil
+}
+
+// importQuestion turns a record into a question with its answer key checked.
+// Subject and topic are resolved separately.
+func importQuestion(rec importRecord, exam entity.ExamCategory) (entity.Question, error) {
+	if rec.err != nil {
+		return entity.Question{}, rec.err
+	}
+	if rec.question == "" {
+		return entity.Question{}, rowError("question text is required")
+	}
+
+	q := entity.Question{
+		Exam:            exam,
+		QuestionText:    rec.question,
+		OptionA:         rec.options[0],
+		OptionB:         rec.options[1],
+		OptionC:         rec.options[2],
+		OptionD:         rec.options[3],
+		ReasonText:      stringPointer(rec.reason),
+		Explanation:     stringPointer(rec.explanation),
+		ChoiceType:      entity.QuestionChoiceType(strings.ToLower(rec.choiceType)),
+		DifficultyLevel: 1,
+		// Imported questions go through review like any other.
+		Status: entity.QuestionStatusDraft,
+	}
+
+	switch q.ChoiceType {
+	case "", entity.ChoiceTypeSingle, entity.ChoiceTypeMulti, entity.ChoiceTypeNumerical,
+		entity.ChoiceTypeMatrixMatch, entity.ChoiceTypeAssertionReason:
+	default:
+		return entity.Question{}, rowErrorf("unknown choice type %q", rec.choiceType)
+	}
+
+	var err error
+	if rec.difficulty != "" {
+		q.DifficultyLevel, err = strconv.Atoi(rec.difficulty)
+		if err != nil || q.DifficultyLevel < 1 || q.DifficultyLevel > 5 {
+			return entity.Question{}, rowErrorf("difficulty must be 1 to 5, got %q", rec.difficulty)
+		}
+	}
+
+	if q.CorrectOptions, err = parseOptions(rec.correct); err != nil {
+		return entity.Question{}, err
+	}
+	if len(q.CorrectOptions) > 0 {
+		q.CorrectOption = q.CorrectOptions[0]
+	}
+
+	if q.NumericAnswer, err = parseFloat("numeric answer", rec.numericAnswer); err != nil {
+		return entity.Question{}, err
+	}
+	if q.NumericTolerance, err = parseFloat("numeric tolerance", rec.numericTolerance); err != nil {
+		return entity.Question{}, err
+	}
+	if q.NumericTolerance != nil && *q.NumericTolerance < 0 {
+		return entity.Question{}, rowError("numeric tolerance cannot be negative")
+	}
+
+	if rec.matrix != "" {
+		q.Matrix = &entity.MatrixMatch{}
+		if err := json.Unmarshal([]byte(rec.matrix), q.Matrix); err != nil {
+			return entity.Question{}, rowErrorf("invalid matrix JSON: %v", err)
+		}
+	}
+
+	flags := []struct {
+		name  string
+		value string
+		dest  *bool
+		def   bool
+	}{
+		{"isClinical", rec.isClinical, &q.IsClinical, false},
+		{"isImageBased", rec.isImageBased, &q.IsImageBased, false},
+		{"isHighYield", rec.isHighYield, &q.IsHighYield, false},
+	}
+	for _, f := range flags {
+		if *f.dest, err = parseFlag(f.name, f.value, f.def); err != nil {
+			return entity.Question{}, err
+		}
+	}
+
+	if err := setAnswerKey(&q); err != nil {
+		return entity.Question{}, rowError(strings.TrimPrefix(err.Error(), ErrInvalidAnswerKey.Error()+": "))
+	}
+
+	return q, nil
+}
+
+// parseOptions reads an answer key such as "2", "B" or "A,C".
+func parseOptions(value string) ([]int, error) {
+	fields := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ';' || r == '|' || r == ' '
+	})
+
+	options := make([]int, 0, len(fields))
+	for _, field := range fields {
+		option, err := strconv.Atoi(field)
+		if err != nil && len(field) == 1 {
+			option, err = int(strings.ToUpper(field)[0]-'A')+1, nil
+		}
+		if err != nil || option < 1 || option > 4 {
+			return nil, rowErrorf("correct option must be 1 to 4 or A to D, got %q", value)
+		}
+		options = append(options, option)
+	}
+
+	return options, nil
+}
+
+func parseFloat(name, value string) (*float64, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, rowErrorf("invalid %s %q", name, value)
+	}
+
+	return &f, nil
+}
+
+func parseFlag(name, value string, def bool) (bool, error) {
+	switch strings.ToLower(value) {
+	case "":
+		return def, nil
+	case "true", "1", "yes", "y":
+		return true, nil
+	case "false", "0", "no", "n":
+		return false, nil
+	default:
+		return false, rowErrorf("invalid %s %q", name, value)
+	}
+}
+
+// normalizeHeader lowercases a header and drops spaces, dashes and
+// underscores, so "Option A", "option_a" and "optionA" match.
+func normalizeHeader(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '_' || r == '-' {
+			return -1
+		}
+
+		return r
+	}, strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))))
+}
+
+func isBlank(row []string) bool {
+	for _, value := range row {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+func deref(value *string) string {
+	if value == nil {
+		return ""
+	}
+
+	return *value
+}
+
+// isRowError tells row problems, which are reported, from failures that stop
+// the import.
+func isRowError(err error) bool {
+	var rerr rowError
+
+	return errors.As(err, &rerr)
+}
@@ -0,0 +1,129 @@
+package question
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/repo"
+)
+
+const (
+	_defaultListLimit = 50
+	_maxListLimit     = 200
+)
+
+// ListQuery selects a page of the admin question list. Sort defaults to
+// relevance when searching and to newest first otherwise; Cursor is the
+// NextCursor of the previous page.
+type ListQuery struct {
+	Sort   entity.QuestionSort
+	Desc   *bool
+	Limit  int
+	Cursor string
+}
+
+// listCursor is what a NextCursor encodes. The sort is kept so a cursor
+// cannot be replayed against a differently sorted list.
+type listCursor struct {
+	Sort entity.QuestionSort `json:"s"`
+	Desc bool                `json:"d"`
+	Key  string              `json:"k"`
+	ID   uuid.UUID           `json:"id"`
+}
+
+// AdminList returns a page of the questions matching filter, with the total
+// number of matches.
+func (uc *UseCase) AdminList(ctx context.Context, filter repo.QuestionFilter, query ListQuery) (entity.QuestionList, error) {
+	page, err := listPage(filter, query)
+	if err != nil {
+		return entity.QuestionList{}, err
+	}
+
+	// One extra row tells whether there is a next page.
+	page.Limit++
+	items, err := uc.repo.List(ctx, filter, page)
+	if err != nil {
+		return entity.QuestionList{}, fmt.Errorf("question - List: %w", err)
+	}
+	page.Limit--
+
+	total, err := uc.repo.Count(ctx, filter)
+	if err != nil {
+		return entity.QuestionList{}, fmt.Errorf("question - Count: %w", err)
+	}
+
+	meta := entity.QuestionListMeta{Total: total, Limit: page.Limit, Sort: page.Sort, Desc: page.Desc}
+	if len(items) > page.Limit {
+		items = items[:page.Limit]
+		last := items[len(items)-1]
+		meta.NextCursor = encodeCursor(listCursor{Sort: page.Sort, Desc: page.Desc, Key: last.SortKey, ID: last.ID})
+	}
+	if items == nil {
+		items = []entity.QuestionListItem{}
+	}
+
+	return entity.QuestionList{Items: items, Meta: meta}, nil
+}
+
+func listPage(filter repo.QuestionFilter, query ListQuery) (repo.QuestionPage, error) {
+	page := repo.QuestionPage{Sort: query.Sort, Limit: query.Limit}
+
+	switch page.Sort {
+	case "":
+		page.Sort, page.Desc = entity.QuestionSortCreatedAt, true
+		if filter.Search != "" {
+			page.Sort = entity.QuestionSortRelevance
+		}
+	case entity.QuestionSortCreatedAt, entity.QuestionSortDifficulty, entity.QuestionSortText:
+	case entity.QuestionSortRelevance:
+		if filter.Search == "" {
+			return repo.QuestionPage{}, fmt.Errorf("%w: relevance needs a search", ErrInvalidSort)
+		}
+		page.Desc = true
+	default:
+		return repo.QuestionPage{}, fmt.Errorf("%w: %s", ErrInvalidSort, page.Sort)
+	}
+	if query.Desc != nil {
+		page.Desc = *query.Desc
+	}
+
+	if page.Limit <= 0 {
+		page.Limit = _defaultListLimit
+	}
+	page.Limit = min(page.Limit, _maxListLimit)
+
+	if query.Cursor != "" {
+		cursor, err := decodeCursor(query.Cursor)
+		if err != nil || cursor.Sort != page.Sort || cursor.Desc != page.Desc {
+			return repo.QuestionPage{}, ErrInvalidCursor
+		}
+		page.After = &repo.QuestionCursor{Key: cursor.Key, ID: cursor.ID}
+	}
+
+	return page, nil
+}
+
+func encodeCursor(c listCursor) string {
+	data, _ := json.Marshal(c) //nolint:errchkjson // plain strings and a UUID always marshal
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(s string) (listCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return listCursor{}, err
+	}
+
+	var c listCursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return listCursor{}, err
+	}
+
+	return c, nil
+}
@@ -2,67 +2,156 @@ package question
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/google/uuid"
 
 	"github.com/evrone/go-clean-template/internal/entity"
 	"github.com/evrone/go-clean-template/internal/repo"
+	"github.com/evrone/go-clean-template/internal/usecase/scoring"
+	"github.com/evrone/go-clean-template/pkg/minhash"
+)
+
+var (
+	// ErrNotFound when the question does not exist.
+	ErrNotFound = errors.New("question not found")
+	// ErrInvalidAnswerKey when the options or answer key do not fit the choice type.
+	ErrInvalidAnswerKey = errors.New("invalid answer key")
+	// ErrUnsupportedFormat when an import or export format is not supported.
+	ErrUnsupportedFormat = errors.New("unsupported format")
+	// ErrInvalidImportFile when an import file cannot be read at all.
+	ErrInvalidImportFile = errors.New("invalid import file")
+	// ErrUnknownExam when an import targets an exam that does not exist.
+	ErrUnknownExam = errors.New("unknown exam")
+	// ErrImportNotFound when the import job does not exist.
+	ErrImportNotFound = errors.New("import job not found")
+	// ErrVersionNotFound when the question has no such version.
+	ErrVersionNotFound = errors.New("question version not found")
+	// ErrVersionConflict when the question changed since the edit was based on it.
+	ErrVersionConflict = errors.New("question was changed by someone else")
+	// ErrInvalidSort when a question list sort is unknown or needs a search.
+	ErrInvalidSort = errors.New("invalid sort")
+	// ErrInvalidCursor when a list cursor is malformed or from another sort.
+	ErrInvalidCursor = errors.New("invalid cursor")
+	// ErrInvalidThreshold when a duplicate threshold is not in (0, 1].
+	ErrInvalidThreshold = errors.New("threshold must be above 0 and at most 1")
+	// ErrInvalidTransition when the workflow does not allow the status change.
+	ErrInvalidTransition = errors.New("invalid status transition")
+	// ErrNotReviewer when someone other than the assigned reviewer approves or publishes.
+	ErrNotReviewer = errors.New("only the assigned reviewer can do this")
+	// ErrReviewerRequired when a question is sent for review without a reviewer.
+	ErrReviewerRequired = errors.New("a reviewer is required")
+	// ErrSelfReview when the reviewer wrote the version under review.
+	ErrSelfReview = errors.New("authors cannot review their own changes")
+	// ErrUnknownReviewer when the reviewer is not an admin who can sign in.
+	ErrUnknownReviewer = errors.New("reviewer is not a known admin")
+	// ErrCommentNotFound when the review comment does not exist.
+	ErrCommentNotFound = errors.New("review comment not found")
+	// ErrInvalidField when a review comment names a field questions do not have.
+	ErrInvalidField = errors.New("invalid question field")
 )
 
 // UseCase for admin question management.
 type UseCase struct {
-	repo repo.QuestionRepository
+	repo       repo.QuestionRepository
+	subjects   repo.SubjectRepository
+	topics     repo.TopicRepository
+	imports    repo.QuestionImportRepository
+	signatures repo.QuestionSignatureRepository
+	reviews    repo.QuestionReviewRepository
+	opts       Options
 }
 
-// New constructs UseCase.
-func New(repo repo.QuestionRepository) *UseCase {
-	return &UseCase{repo: repo}
+// Options configure the review workflow and imports. Reviewers are the admins
+// who can review questions. SelfReview lets them review their own changes,
+// for deployments with a single admin. ImportLimit bounds each decompressed
+// part of an XLSX import, normally the upload limit; 32 MiB when 0.
+type Options struct {
+	Reviewers   []uuid.UUID
+	SelfReview  bool
+	ImportLimit int64
 }
 
-// AdminList returns questions filtered.
-func (uc *UseCase) AdminList(ctx context.Context, filter repo.QuestionFilter) ([]entity.Question, error) {
-	questions, err := uc.repo.List(ctx, filter)
-	if err != nil {
-		return nil, fmt.Errorf("question - List: %w", err)
+// New constructs UseCase.
+func New(
+	repo repo.QuestionRepository,
+	subjects repo.SubjectRepository,
+	topics repo.TopicRepository,
+	imports repo.QuestionImportRepository,
+	signatures repo.QuestionSignatureRepository,
+	reviews repo.QuestionReviewRepository,
+	opts Options,
+) *UseCase {
+	if opts.ImportLimit <= 0 {
+		opts.ImportLimit = _defaultImportLimit
 	}
 
-	return questions, nil
+	return &UseCase{
+		repo: repo, subjects: subjects, topics: topics, imports: imports, signatures: signatures, reviews: reviews,
+		opts: opts,
+	}
 }
 
-// AdminCreate persists a new question.
-func (uc *UseCase) AdminCreate(ctx context.Context, req entity.QuestionCreateRequest) (entity.Question, error) {
+// AdminCreate persists a new question as a draft of its first version; it
+// reaches learners once reviewed and published. Signed questions
+// of the same exam that look like it are returned as warnings; they do not
+// stop the create.
+func (uc *UseCase) AdminCreate(ctx context.Context, authorID uuid.UUID, req entity.QuestionCreateRequest) (entity.QuestionCreateResult, error) {
 	question := entity.Question{
-		ID:              uuid.New(),
-		Exam:            req.Exam,
-		SubjectID:       req.SubjectID,
-		TopicID:         req.TopicID,
-		QuestionText:    req.QuestionText,
-		OptionA:         req.OptionA,
-		OptionB:         req.OptionB,
-		OptionC:         req.OptionC,
-		OptionD:         req.OptionD,
-		CorrectOption:   req.CorrectOption,
-		Explanation:     stringPointer(req.Explanation),
-		DifficultyLevel: req.DifficultyLevel,
-		ChoiceType:      req.ChoiceType,
-		IsClinical:      req.IsClinical,
-		IsImageBased:    req.IsImageBased,
-		IsHighYield:     req.IsHighYield,
-		IsActive:        req.IsActive,
-	}
-
-	created, err := uc.repo.Create(ctx, question)
+		ID:               uuid.New(),
+		Exam:             req.Exam,
+		SubjectID:        req.SubjectID,
+		TopicID:          req.TopicID,
+		QuestionText:     req.QuestionText,
+		OptionA:          req.OptionA,
+		OptionB:          req.OptionB,
+		OptionC:          req.OptionC,
+		OptionD:          req.OptionD,
+		CorrectOption:    req.CorrectOption,
+		CorrectOptions:   req.CorrectOptions,
+		ReasonText:       stringPointer(req.ReasonText),
+		NumericAnswer:    req.NumericAnswer,
+		NumericTolerance: req.NumericTolerance,
+		Matrix:           req.Matrix,
+		Explanation:      stringPointer(req.Explanation),
+		DifficultyLevel:  req.DifficultyLevel,
+		ChoiceType:       req.ChoiceType,
+		IsClinical:       req.IsClinical,
+		IsImageBased:     req.IsImageBased,
+		IsHighYield:      req.IsHighYield,
+		Status:           entity.QuestionStatusDraft,
+	}
+
+	if err := setAnswerKey(&question); err != nil {
+		return entity.QuestionCreateResult{}, err
+	}
+
+	sig := minhash.New(question.QuestionText)
+	finder, err := uc.signedFinder(ctx, question.Exam, []minhash.Signature{sig})
 	if err != nil {
-		return entity.Question{}, fmt.Errorf("question - Create: %w", err)
+		return entity.QuestionCreateResult{}, err
 	}
 
-	return created, nil
+	created, err := uc.repo.Create(ctx, entity.QuestionVersion{Question: question, AuthorID: &authorID})
+	if err != nil {
+		return entity.QuestionCreateResult{}, fmt.Errorf("question - Create: %w", err)
+	}
+
+	duplicates := topSimilar(finder.find(sig, created.ID))
+	if duplicates == nil {
+		duplicates = []entity.SimilarQuestion{}
+	}
+
+	return entity.QuestionCreateResult{Question: created, Duplicates: duplicates}, nil
 }
 
 // AdminGet returns a question by ID.
 func (uc *UseCase) AdminGet(ctx context.Context, id uuid.UUID) (entity.Question, error) {
 	question, err := uc.repo.GetByID(ctx, id)
+	if errors.Is(err, repo.ErrNotFound) {
+		return entity.Question{}, ErrNotFound
+	}
 	if err != nil {
 		return entity.Question{}, fmt.Errorf("question - GetByID: %w", err)
 	}
@@ -70,12 +159,19 @@ func (uc *UseCase) AdminGet(ctx context.Context, id uuid.UUID) (entity.Question,
 	return question, nil
 }
 
-// AdminUpdate updates question fields.
-func (uc *UseCase) AdminUpdate(ctx context.Context, id uuid.UUID, req entity.QuestionUpdateRequest) (entity.Question, error) {
-	question, err := uc.repo.GetByID(ctx, id)
+// AdminUpdate stores the changed fields as a new version. An update that
+// changes nothing keeps the current version. Difficulty is not versioned
+// content, so changing only it updates the question in place.
+func (uc *UseCase) AdminUpdate(ctx context.Context, authorID, id uuid.UUID, req entity.QuestionUpdateRequest) (entity.Question, error) {
+	current, err := uc.AdminGet(ctx, id)
 	if err != nil {
-		return entity.Question{}, fmt.Errorf("question - GetByID: %w", err)
+		return entity.Question{}, err
 	}
+	if req.Version != nil && *req.Version != current.Version {
+		return entity.Question{}, ErrVersionConflict
+	}
+
+	question := current
 
 	if req.SubjectID != nil {
 		question.SubjectID = *req.SubjectID
@@ -100,12 +196,31 @@ func (uc *UseCase) AdminUpdate(ctx context.Context, id uuid.UUID, req entity.Que
 	}
 	if req.CorrectOption != nil {
 		question.CorrectOption = *req.CorrectOption
+		question.CorrectOptions = nil
+	}
+	if req.CorrectOptions != nil {
+		question.CorrectOptions = req.CorrectOptions
+	}
+	if req.ReasonText != nil {
+		question.ReasonText = stringPointer(*req.ReasonText)
+	}
+	if req.NumericAnswer != nil {
+		question.NumericAnswer = req.NumericAnswer
+	}
+	if req.NumericTolerance != nil {
+		question.NumericTolerance = req.NumericTolerance
+	}
+	if req.Matrix != nil {
+		question.Matrix = req.Matrix
 	}
 	if req.Explanation != nil {
 		question.Explanation = req.Explanation
 	}
-	if req.DifficultyLevel != nil {
-		question.DifficultyLevel = *req.DifficultyLevel
+	// Difficulty is not versioned: a new level is written with the content
+	// but does not make a version on its own.
+	var difficulty *int
+	if req.DifficultyLevel != nil && *req.DifficultyLevel != current.DifficultyLevel {
+		difficulty = req.DifficultyLevel
 	}
 	if req.ChoiceType != nil {
 		question.ChoiceType = *req.ChoiceType
@@ -119,16 +234,12 @@ func (uc *UseCase) AdminUpdate(ctx context.Context, id uuid.UUID, req entity.Que
 	if req.IsHighYield != nil {
 		question.IsHighYield = *req.IsHighYield
 	}
-	if req.IsActive != nil {
-		question.IsActive = *req.IsActive
-	}
 
-	updated, err := uc.repo.Update(ctx, question)
-	if err != nil {
-		return entity.Question{}, fmt.Errorf("question - Update: %w", err)
+	if err := setAnswerKey(&question); err != nil {
+		return entity.Question{}, err
 	}
 
-	return updated, nil
+	return uc.saveVersion(ctx, entity.QuestionVersion{Question: question, AuthorID: &authorID}, current, difficulty)
 }
 
 // AdminDelete removes a question.
@@ -140,6 +251,113 @@ func (uc *UseCase) AdminDelete(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
+// setAnswerKey checks that the options and answer key fit the choice type
+// and clears fields other types use. Option-based keys are normalized and
+// CorrectOption, which single-choice clients still read, points at the first.
+func setAnswerKey(question *entity.Question) error {
+	if question.ChoiceType == "" {
+		question.ChoiceType = entity.ChoiceTypeSingle
+	}
+
+	if question.ChoiceType != entity.ChoiceTypeAssertionReason {
+		question.ReasonText = nil
+	}
+	if question.ChoiceType != entity.ChoiceTypeNumerical {
+		question.NumericAnswer, question.NumericTolerance = nil, nil
+	}
+	if question.ChoiceType != entity.ChoiceTypeMatrixMatch {
+		question.Matrix = nil
+	}
+
+	switch question.ChoiceType {
+	case entity.ChoiceTypeNumerical:
+		return setNumericKey(question)
+	case entity.ChoiceTypeMatrixMatch:
+		return setMatrixKey(question)
+	case entity.ChoiceTypeAssertionReason:
+		if question.ReasonText == nil {
+			return fmt.Errorf("%w: assertion-reason questions need reasonText", ErrInvalidAnswerKey)
+		}
+
+		setAssertionReasonOptions(question)
+	}
+
+	if question.OptionA == "" || question.OptionB == "" || question.OptionC == "" || question.OptionD == "" {
+		return fmt.Errorf("%w: %s questions need options A to D", ErrInvalidAnswerKey, question.ChoiceType)
+	}
+
+	key := scoring.AnswerKey(*question)
+	if len(key) == 0 || (question.ChoiceType != entity.ChoiceTypeMulti && len(key) != 1) {
+		return fmt.Errorf("%w: %s questions need %s", ErrInvalidAnswerKey, question.ChoiceType, keyHint(question.ChoiceType))
+	}
+
+	question.CorrectOptions = key
+	question.CorrectOption = key[0]
+
+	return nil
+}
+
+func setNumericKey(question *entity.Question) error {
+	if question.NumericAnswer == nil {
+		return fmt.Errorf("%w: numerical questions need numericAnswer", ErrInvalidAnswerKey)
+	}
+
+	if question.NumericTolerance == nil {
+		exact := 0.0
+		question.NumericTolerance = &exact
+	}
+
+	question.CorrectOption, question.CorrectOptions = 0, nil
+
+	return nil
+}
+
+func setMatrixKey(question *entity.Question) error {
+	m := question.Matrix
+	if m == nil || len(m.Rows) < 2 || len(m.Columns) < 2 || len(m.Answer) != len(m.Rows) {
+		return fmt.Errorf("%w: matrix-match questions need rows, columns and one answer entry per row", ErrInvalidAnswerKey)
+	}
+
+	for i, row := range m.Answer {
+		if len(row) == 0 {
+			return fmt.Errorf("%w: matrix row %d has no matching column", ErrInvalidAnswerKey, i+1)
+		}
+
+		for _, column := range row {
+			if column < 1 || column > len(m.Columns) {
+				return fmt.Errorf("%w: matrix row %d points at unknown column %d", ErrInvalidAnswerKey, i+1, column)
+			}
+		}
+
+		m.Answer[i] = scoring.Normalize(row)
+	}
+
+	question.CorrectOption, question.CorrectOptions = 0, nil
+
+	return nil
+}
+
+// setAssertionReasonOptions fills in the standard assertion-reason options
+// when the author left them blank.
+func setAssertionReasonOptions(question *entity.Question) {
+	if question.OptionA != "" || question.OptionB != "" || question.OptionC != "" || question.OptionD != "" {
+		return
+	}
+
+	question.OptionA = "Both A and R are true, and R is the correct explanation of A"
+	question.OptionB = "Both A and R are true, but R is not the correct explanation of A"
+	question.OptionC = "A is true, but R is false"
+	question.OptionD = "A is false, but R is true"
+}
+
+func keyHint(choiceType entity.QuestionChoiceType) string {
+	if choiceType == entity.ChoiceTypeMulti {
+		return "at least one correct option"
+	}
+
+	return "exactly one correct option"
+}
+
 func stringPointer(value string) *string {
 	if value == "" {
 		return nil
@@ -0,0 +1,184 @@
+package question
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/google/uuid"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/repo"
+)
+
+// ListVersions returns the versions of a question, newest first.
+func (uc *UseCase) ListVersions(ctx context.Context, id uuid.UUID) ([]entity.QuestionVersion, error) {
+	versions, err := uc.repo.ListVersions(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("question - ListVersions: %w", err)
+	}
+
+	// Every question has at least its first version.
+	if len(versions) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return versions, nil
+}
+
+// GetVersion returns one version of a question.
+func (uc *UseCase) GetVersion(ctx context.Context, id uuid.UUID, version int) (entity.QuestionVersion, error) {
+	v, err := uc.repo.GetVersion(ctx, id, version)
+	if errors.Is(err, repo.ErrNotFound) {
+		return entity.QuestionVersion{}, ErrVersionNotFound
+	}
+	if err != nil {
+		return entity.QuestionVersion{}, fmt.Errorf("question - GetVersion: %w", err)
+	}
+
+	return v, nil
+}
+
+// DiffVersions lists the fields that changed from one version to another.
+func (uc *UseCase) DiffVersions(ctx context.Context, id uuid.UUID, from, to int) (entity.QuestionVersionDiff, error) {
+	before, err := uc.GetVersion(ctx, id, from)
+	if err != nil {
+		return entity.QuestionVersionDiff{}, err
+	}
+
+	after, err := uc.GetVersion(ctx, id, to)
+	if err != nil {
+		return entity.QuestionVersionDiff{}, err
+	}
+
+	return entity.QuestionVersionDiff{
+		QuestionID: id,
+		From:       from,
+		To:         to,
+		Changes:    questionChanges(before.Question, after.Question),
+	}, nil
+}
+
+// Rollback restores the content of an earlier version as a new version, so
+// the history in between is kept. Rolling back to content identical to the
+// current version changes nothing.
+func (uc *UseCase) Rollback(ctx context.Context, authorID, id uuid.UUID, version int) (entity.Question, error) {
+	current, err := uc.AdminGet(ctx, id)
+	if err != nil {
+		return entity.Question{}, err
+	}
+
+	target, err := uc.GetVersion(ctx, id, version)
+	if err != nil {
+		return entity.Question{}, err
+	}
+
+	restored := target.Question
+	restored.Version = current.Version
+
+	return uc.saveVersion(ctx, entity.QuestionVersion{
+		Question:     restored,
+		AuthorID:     &authorID,
+		RestoredFrom: &version,
+	}, current, nil)
+}
+
+// saveVersion stores next.Question as a new version if it differs from
+// current, recording which fields changed, and sets difficulty in the same
+// write unless it is nil. The workflow state is carried over, except that
+// changed content of an approved or published question goes back to its
+// reviewer, or to draft if it has none.
+func (uc *UseCase) saveVersion(ctx context.Context, next entity.QuestionVersion, current entity.Question, difficulty *int) (entity.Question, error) {
+	changes := questionChanges(current, next.Question)
+	if len(changes) == 0 && difficulty == nil {
+		return current, nil
+	}
+
+	next.Question.Status, next.Question.ReviewerID = current.Status, current.ReviewerID
+	if len(changes) > 0 && (current.Status == entity.QuestionStatusApproved || current.Status == entity.QuestionStatusPublished) {
+		next.Question.Status = entity.QuestionStatusInReview
+		if current.ReviewerID == nil {
+			next.Question.Status = entity.QuestionStatusDraft
+		}
+	}
+
+	next.ChangedFields = make([]string, len(changes))
+	for i, change := range changes {
+		next.ChangedFields[i] = change.Field
+	}
+
+	updated, err := uc.repo.Update(ctx, next, difficulty)
+	switch {
+	case errors.Is(err, repo.ErrNotFound):
+		return entity.Question{}, ErrNotFound
+	case errors.Is(err, repo.ErrConflict):
+		return entity.Question{}, ErrVersionConflict
+	case err != nil:
+		return entity.Question{}, fmt.Errorf("question - Update: %w", err)
+	}
+
+	return updated, nil
+}
+
+type questionField struct {
+	name  string
+	value any
+}
+
+// questionFields lists the versioned fields of a question under their JSON
+// names; difficulty is calibrated in place and left out. Pointers are
+// dereferenced and empty option sets read as nil, so equal content compares
+// equal however it was loaded.
+func questionFields(q entity.Question) []questionField {
+	var options any
+	if len(q.CorrectOptions) > 0 {
+		options = q.CorrectOptions
+	}
+
+	return []questionField{
+		{"subjectId", q.SubjectID},
+		{"topicId", q.TopicID},
+		{"questionText", q.QuestionText},
+		{"optionA", q.OptionA},
+		{"optionB", q.OptionB},
+		{"optionC", q.OptionC},
+		{"optionD", q.OptionD},
+		{"correctOptions", options},
+		{"reasonText", pointerValue(q.ReasonText)},
+		{"numericAnswer", pointerValue(q.NumericAnswer)},
+		{"numericTolerance", pointerValue(q.NumericTolerance)},
+		{"matrix", pointerValue(q.Matrix)},
+		{"explanation", pointerValue(q.Explanation)},
+		{"choiceType", q.ChoiceType},
+		{"isClinical", q.IsClinical},
+		{"isImageBased", q.IsImageBased},
+		{"isHighYield", q.IsHighYield},
+	}
+}
+
+// questionChanges lists the fields whose values differ from before to after.
+func questionChanges(before, after entity.Question) []entity.QuestionFieldChange {
+	from, to := questionFields(before), questionFields(after)
+
+	changes := []entity.QuestionFieldChange{}
+	for i := range from {
+		if !reflect.DeepEqual(from[i].value, to[i].value) {
+			changes = append(changes, entity.QuestionFieldChange{
+				Field: from[i].name,
+				From:  from[i].value,
+				To:    to[i].value,
+			})
+		}
+	}
+
+	return changes
+}
+
+func pointerValue[T any](value *T) any {
+	if value == nil {
+		return nil
+	}
+
+	return *value
+}
@@ -0,0 +1,180 @@
+package question
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/repo"
+)
+
+// transitions lists the states each workflow state can move to.
+func transitions() map[entity.QuestionStatus][]entity.QuestionStatus {
+	return map[entity.QuestionStatus][]entity.QuestionStatus{
+		entity.QuestionStatusDraft:     {entity.QuestionStatusInReview},
+		entity.QuestionStatusInReview:  {entity.QuestionStatusApproved, entity.QuestionStatusDraft},
+		entity.QuestionStatusApproved:  {entity.QuestionStatusPublished, entity.QuestionStatusDraft},
+		entity.QuestionStatusPublished: {entity.QuestionStatusRetired},
+		entity.QuestionStatusRetired:   {entity.QuestionStatusDraft},
+	}
+}
+
+// Transition moves a question through the workflow. Sending it for review
+// needs a known reviewer other than the author of the current version, unless
+// self-review is on; only that reviewer can then approve and publish it,
+// whatever the role of whoever else asks. Any admin can send a question back
+// to draft or retire it.
+func (uc *UseCase) Transition(ctx context.Context, actorID, id uuid.UUID, req entity.QuestionTransitionRequest) (entity.Question, error) {
+	current, err := uc.AdminGet(ctx, id)
+	if err != nil {
+		return entity.Question{}, err
+	}
+	if req.Version != nil && *req.Version != current.Version {
+		return entity.Question{}, ErrVersionConflict
+	}
+	if !slices.Contains(transitions()[current.Status], req.Status) {
+		return entity.Question{}, ErrInvalidTransition
+	}
+
+	reviewerID := current.ReviewerID
+
+	switch req.Status {
+	case entity.QuestionStatusInReview:
+		if req.ReviewerID != nil {
+			reviewerID = req.ReviewerID
+		}
+		if reviewerID == nil {
+			return entity.Question{}, ErrReviewerRequired
+		}
+		if err := uc.checkReviewer(ctx, current, *reviewerID); err != nil {
+			return entity.Question{}, err
+		}
+	case entity.QuestionStatusApproved:
+		if reviewerID == nil || *reviewerID != actorID {
+			return entity.Question{}, ErrNotReviewer
+		}
+		// The author may have been reassigned as reviewer since.
+		if err := uc.checkReviewer(ctx, current, actorID); err != nil {
+			return entity.Question{}, err
+		}
+	case entity.QuestionStatusPublished:
+		if reviewerID == nil || *reviewerID != actorID {
+			return entity.Question{}, ErrNotReviewer
+		}
+	}
+
+	return uc.transition(ctx, current, req.Status, reviewerID)
+}
+
+// AssignReviewer sets or changes the reviewer of a question that is not yet
+// approved.
+func (uc *UseCase) AssignReviewer(ctx context.Context, id, reviewerID uuid.UUID) (entity.Question, error) {
+	current, err := uc.AdminGet(ctx, id)
+	if err != nil {
+		return entity.Question{}, err
+	}
+	if current.Status != entity.QuestionStatusDraft && current.Status != entity.QuestionStatusInReview {
+		return entity.Question{}, ErrInvalidTransition
+	}
+	if err := uc.checkReviewer(ctx, current, reviewerID); err != nil {
+		return entity.Question{}, err
+	}
+
+	return uc.transition(ctx, current, current.Status, &reviewerID)
+}
+
+// AddComment records a review comment on the current version of a question.
+func (uc *UseCase) AddComment(ctx context.Context, authorID, id uuid.UUID, req entity.QuestionReviewCommentRequest) (entity.QuestionReviewComment, error) {
+	if req.Field != "" && !slices.ContainsFunc(questionFields(entity.Question{}), func(f questionField) bool {
+		return f.name == req.Field
+	}) {
+		return entity.QuestionReviewComment{}, ErrInvalidField
+	}
+
+	current, err := uc.AdminGet(ctx, id)
+	if err != nil {
+		return entity.QuestionReviewComment{}, err
+	}
+
+	comment, err := uc.reviews.CreateComment(ctx, entity.QuestionReviewComment{
+		QuestionID: id,
+		Version:    current.Version,
+		Field:      req.Field,
+		Body:       req.Body,
+		AuthorID:   authorID,
+	})
+	if errors.Is(err, repo.ErrNotFound) {
+		return entity.QuestionReviewComment{}, ErrNotFound
+	}
+	if err != nil {
+		return entity.QuestionReviewComment{}, fmt.Errorf("question - CreateComment: %w", err)
+	}
+
+	return comment, nil
+}
+
+// ListComments returns the review comments of a question, oldest first.
+func (uc *UseCase) ListComments(ctx context.Context, id uuid.UUID, unresolvedOnly bool) ([]entity.QuestionReviewComment, error) {
+	if _, err := uc.AdminGet(ctx, id); err != nil {
+		return nil, err
+	}
+
+	comments, err := uc.reviews.ListComments(ctx, id, unresolvedOnly)
+	if err != nil {
+		return nil, fmt.Errorf("question - ListComments: %w", err)
+	}
+
+	return comments, nil
+}
+
+// ResolveComment marks a review comment resolved.
+func (uc *UseCase) ResolveComment(ctx context.Context, actorID, id, commentID uuid.UUID) (entity.QuestionReviewComment, error) {
+	comment, err := uc.reviews.ResolveComment(ctx, id, commentID, actorID)
+	if errors.Is(err, repo.ErrNotFound) {
+		return entity.QuestionReviewComment{}, ErrCommentNotFound
+	}
+	if err != nil {
+		return entity.QuestionReviewComment{}, fmt.Errorf("question - ResolveComment: %w", err)
+	}
+
+	return comment, nil
+}
+
+// checkReviewer rejects an unknown reviewer, and one who wrote the current
+// version unless self-review is on.
+func (uc *UseCase) checkReviewer(ctx context.Context, current entity.Question, reviewerID uuid.UUID) error {
+	if !slices.Contains(uc.opts.Reviewers, reviewerID) {
+		return ErrUnknownReviewer
+	}
+	if uc.opts.SelfReview {
+		return nil
+	}
+
+	version, err := uc.GetVersion(ctx, current.ID, current.Version)
+	if err != nil {
+		return err
+	}
+	if version.AuthorID != nil && *version.AuthorID == reviewerID {
+		return ErrSelfReview
+	}
+
+	return nil
+}
+
+func (uc *UseCase) transition(ctx context.Context, current entity.Question, to entity.QuestionStatus, reviewerID *uuid.UUID) (entity.Question, error) {
+	updated, err := uc.repo.Transition(ctx, current.ID, current.Status, to, reviewerID)
+	switch {
+	case errors.Is(err, repo.ErrNotFound):
+		return entity.Question{}, ErrNotFound
+	case errors.Is(err, repo.ErrConflict):
+		return entity.Question{}, ErrVersionConflict
+	case err != nil:
+		return entity.Question{}, fmt.Errorf("question - Transition: %w", err)
+	}
+
+	return updated, nil
+}
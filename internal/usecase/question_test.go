@@ -0,0 +1,907 @@
+package usecase_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"context"
+	"hash/crc32"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/repo"
+	"github.com/evrone/go-clean-template/internal/usecase/question"
+	"github.com/evrone/go-clean-template/pkg/minhash"
+	"github.com/evrone/go-clean-template/pkg/xlsx"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type questionMocks struct {
+	questions  *MockQuestionRepository
+	subjects   *MockSubjectRepository
+	topics     *MockTopicRepository
+	imports    *MockQuestionImportRepository
+	signatures *MockQuestionSignatureRepository
+	reviews    *MockQuestionReviewRepository
+}
+
+func questionUseCase(t *testing.T) (*question.UseCase, questionMocks) {
+	t.Helper()
+
+	return questionUseCaseWith(t, question.Options{})
+}
+
+func questionUseCaseWith(t *testing.T, opts question.Options) (*question.UseCase, questionMocks) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+
+	m := questionMocks{
+		questions:  NewMockQuestionRepository(mockCtl),
+		subjects:   NewMockSubjectRepository(mockCtl),
+		topics:     NewMockTopicRepository(mockCtl),
+		imports:    NewMockQuestionImportRepository(mockCtl),
+		signatures: NewMockQuestionSignatureRepository(mockCtl),
+		reviews:    NewMockQuestionReviewRepository(mockCtl),
+	}
+
+	return question.New(m.questions, m.subjects, m.topics, m.imports, m.signatures, m.reviews, opts), m
+}
+
+// noSignedQuestions makes every duplicate lookup come back empty.
+func noSignedQuestions(m questionMocks) {
+	m.signatures.EXPECT().Candidates(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+}
+
+func signedQuestion(exam entity.ExamCategory, text string) entity.QuestionSignature {
+	sig := minhash.New(text)
+
+	return entity.QuestionSignature{
+		QuestionID:   uuid.New(),
+		Exam:         exam,
+		Version:      1,
+		QuestionText: text,
+		Signature:    sig.Bytes(),
+		BandKeys:     sig.BandKeys(),
+	}
+}
+
+func echoCreatedQuestion(repo *MockQuestionRepository) {
+	repo.EXPECT().Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, v entity.QuestionVersion) (entity.Question, error) {
+			return v.Question, nil
+		})
+}
+
+func TestAdminCreateQuestionTypes(t *testing.T) {
+	t.Parallel()
+
+	base := entity.QuestionCreateRequest{
+		Exam:         entity.ExamCategoryJEE,
+		SubjectID:    uuid.New(),
+		TopicID:      uuid.New(),
+		QuestionText: "Q",
+	}
+
+	t.Run("numerical defaults to exact match", func(t *testing.T) {
+		t.Parallel()
+
+		uc, m := questionUseCase(t)
+		noSignedQuestions(m)
+		echoCreatedQuestion(m.questions)
+
+		answer := 42.0
+		req := base
+		req.ChoiceType = entity.ChoiceTypeNumerical
+		req.NumericAnswer = &answer
+		req.CorrectOption = 2
+
+		created, err := uc.AdminCreate(context.Background(), uuid.New(), req)
+		require.NoError(t, err)
+		require.Zero(t, *created.NumericTolerance)
+		require.Zero(t, created.CorrectOption)
+		require.Empty(t, created.CorrectOptions)
+	})
+
+	t.Run("assertion-reason fills standard options", func(t *testing.T) {
+		t.Parallel()
+
+		uc, m := questionUseCase(t)
+		noSignedQuestions(m)
+		echoCreatedQuestion(m.questions)
+
+		req := base
+		req.ChoiceType = entity.ChoiceTypeAssertionReason
+		req.ReasonText = "R"
+		req.CorrectOption = 1
+
+		created, err := uc.AdminCreate(context.Background(), uuid.New(), req)
+		require.NoError(t, err)
+		require.NotEmpty(t, created.OptionD)
+		require.Equal(t, []int{1}, created.CorrectOptions)
+	})
+
+	t.Run("invalid keys", func(t *testing.T) {
+		t.Parallel()
+
+		uc, _ := questionUseCase(t)
+
+		numerical := base
+		numerical.ChoiceType = entity.ChoiceTypeNumerical
+
+		matrix := base
+		matrix.ChoiceType = entity.ChoiceTypeMatrixMatch
+		matrix.Matrix = &entity.MatrixMatch{Rows: []string{"A", "B"}, Columns: []string{"P", "Q"}, Answer: [][]int{{1}, {3}}}
+
+		single := base
+		single.OptionA, single.OptionB, single.OptionC, single.OptionD = "a", "b", "c", "d"
+		single.CorrectOptions = []int{1, 2}
+
+		for _, req := range []entity.QuestionCreateRequest{numerical, matrix, single} {
+			_, err := uc.AdminCreate(context.Background(), uuid.New(), req)
+			require.ErrorIs(t, err, question.ErrInvalidAnswerKey)
+		}
+	})
+}
+
+func TestAdminListQuestionsPages(t *testing.T) {
+	t.Parallel()
+
+	uc, m := questionUseCase(t)
+
+	items := make([]entity.QuestionListItem, 3)
+	for i := range items {
+		items[i] = entity.QuestionListItem{
+			Question: entity.Question{ID: uuid.New()},
+			SortKey:  "2025-12-0" + strconv.Itoa(3-i) + " 00:00:00+00",
+		}
+	}
+
+	m.questions.EXPECT().List(gomock.Any(), gomock.Any(), repo.QuestionPage{
+		Sort:  entity.QuestionSortCreatedAt,
+		Desc:  true,
+		Limit: 3,
+	}).Return(items, nil)
+	m.questions.EXPECT().Count(gomock.Any(), gomock.Any()).Return(5, nil).Times(2)
+
+	first, err := uc.AdminList(context.Background(), repo.QuestionFilter{}, question.ListQuery{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, first.Items, 2)
+	require.Equal(t, 5, first.Meta.Total)
+	require.NotEmpty(t, first.Meta.NextCursor)
+
+	m.questions.EXPECT().List(gomock.Any(), gomock.Any(), repo.QuestionPage{
+		Sort:  entity.QuestionSortCreatedAt,
+		Desc:  true,
+		Limit: 3,
+		After: &repo.QuestionCursor{Key: items[1].SortKey, ID: items[1].ID},
+	}).Return(items[2:], nil)
+
+	second, err := uc.AdminList(context.Background(), repo.QuestionFilter{}, question.ListQuery{Limit: 2, Cursor: first.Meta.NextCursor})
+	require.NoError(t, err)
+	require.Equal(t, items[2:], second.Items)
+	require.Empty(t, second.Meta.NextCursor)
+
+	asc := false
+	_, err = uc.AdminList(context.Background(), repo.QuestionFilter{}, question.ListQuery{Desc: &asc, Cursor: first.Meta.NextCursor})
+	require.ErrorIs(t, err, question.ErrInvalidCursor)
+
+	_, err = uc.AdminList(context.Background(), repo.QuestionFilter{}, question.ListQuery{Cursor: "not a cursor"})
+	require.ErrorIs(t, err, question.ErrInvalidCursor)
+}
+
+func TestAdminListQuestionsSearch(t *testing.T) {
+	t.Parallel()
+
+	uc, m := questionUseCase(t)
+
+	filter := repo.QuestionFilter{Search: "brachial plexus"}
+
+	m.questions.EXPECT().List(gomock.Any(), filter, repo.QuestionPage{
+		Sort:  entity.QuestionSortRelevance,
+		Desc:  true,
+		Limit: 51,
+	}).Return(nil, nil)
+	m.questions.EXPECT().Count(gomock.Any(), filter).Return(0, nil)
+
+	list, err := uc.AdminList(context.Background(), filter, question.ListQuery{})
+	require.NoError(t, err)
+	require.Empty(t, list.Items)
+	require.NotNil(t, list.Items)
+	require.Equal(t, entity.QuestionSortRelevance, list.Meta.Sort)
+
+	_, err = uc.AdminList(context.Background(), repo.QuestionFilter{}, question.ListQuery{Sort: entity.QuestionSortRelevance})
+	require.ErrorIs(t, err, question.ErrInvalidSort)
+
+	_, err = uc.AdminList(context.Background(), repo.QuestionFilter{}, question.ListQuery{Sort: "popularity"})
+	require.ErrorIs(t, err, question.ErrInvalidSort)
+}
+
+func TestAdminUpdateQuestionVersions(t *testing.T) {
+	t.Parallel()
+
+	stored := entity.Question{
+		ID: uuid.New(), Exam: entity.ExamCategoryNEETPG, SubjectID: uuid.New(), TopicID: uuid.New(),
+		QuestionText: "Q", OptionA: "a", OptionB: "b", OptionC: "c", OptionD: "d",
+		CorrectOption: 1, CorrectOptions: []int{1}, ChoiceType: entity.ChoiceTypeSingle,
+		DifficultyLevel: 1, IsActive: true, Version: 3,
+	}
+	author := uuid.New()
+
+	t.Run("records the changed fields", func(t *testing.T) {
+		t.Parallel()
+
+		uc, m := questionUseCase(t)
+		m.questions.EXPECT().GetByID(gomock.Any(), stored.ID).Return(stored, nil)
+		m.questions.EXPECT().Update(gomock.Any(), gomock.Any(), nil).
+			DoAndReturn(func(_ context.Context, v entity.QuestionVersion, _ *int) (entity.Question, error) {
+				require.Equal(t, []string{"questionText", "correctOptions"}, v.ChangedFields)
+				require.Equal(t, &author, v.AuthorID)
+				require.Equal(t, 3, v.Question.Version)
+
+				v.Question.Version++
+
+				return v.Question, nil
+			})
+
+		text, correct := "Q, corrected", 2
+		updated, err := uc.AdminUpdate(context.Background(), author, stored.ID, entity.QuestionUpdateRequest{
+			QuestionText:  &text,
+			CorrectOption: &correct,
+		})
+		require.NoError(t, err)
+		require.Equal(t, 4, updated.Version)
+	})
+
+	t.Run("an update without changes keeps the version", func(t *testing.T) {
+		t.Parallel()
+
+		uc, m := questionUseCase(t)
+		m.questions.EXPECT().GetByID(gomock.Any(), stored.ID).Return(stored, nil)
+
+		same := "Q"
+		updated, err := uc.AdminUpdate(context.Background(), author, stored.ID, entity.QuestionUpdateRequest{QuestionText: &same})
+		require.NoError(t, err)
+		require.Equal(t, stored, updated)
+	})
+
+	t.Run("a stale version is rejected", func(t *testing.T) {
+		t.Parallel()
+
+		uc, m := questionUseCase(t)
+		m.questions.EXPECT().GetByID(gomock.Any(), stored.ID).Return(stored, nil)
+
+		stale := 2
+		_, err := uc.AdminUpdate(context.Background(), author, stored.ID, entity.QuestionUpdateRequest{Version: &stale})
+		require.ErrorIs(t, err, question.ErrVersionConflict)
+	})
+
+	t.Run("a concurrent update is a conflict", func(t *testing.T) {
+		t.Parallel()
+
+		uc, m := questionUseCase(t)
+		m.questions.EXPECT().GetByID(gomock.Any(), stored.ID).Return(stored, nil)
+		m.questions.EXPECT().Update(gomock.Any(), gomock.Any(), nil).Return(entity.Question{}, repo.ErrConflict)
+
+		text := "Q, reworded"
+		_, err := uc.AdminUpdate(context.Background(), author, stored.ID, entity.QuestionUpdateRequest{QuestionText: &text})
+		require.ErrorIs(t, err, question.ErrVersionConflict)
+	})
+
+	t.Run("difficulty changes in place", func(t *testing.T) {
+		t.Parallel()
+
+		uc, m := questionUseCase(t)
+		m.questions.EXPECT().GetByID(gomock.Any(), stored.ID).Return(stored, nil)
+
+		level := 4
+		m.questions.EXPECT().Update(gomock.Any(), gomock.Any(), &level).
+			DoAndReturn(func(_ context.Context, v entity.QuestionVersion, level *int) (entity.Question, error) {
+				require.Empty(t, v.ChangedFields)
+				require.Equal(t, 3, v.Question.Version)
+
+				v.Question.DifficultyLevel = *level
+
+				return v.Question, nil
+			})
+
+		updated, err := uc.AdminUpdate(context.Background(), author, stored.ID, entity.QuestionUpdateRequest{DifficultyLevel: &level})
+		require.NoError(t, err)
+		require.Equal(t, 3, updated.Version)
+		require.Equal(t, level, updated.DifficultyLevel)
+	})
+}
+
+func TestQuestionVersionDiffAndRollback(t *testing.T) {
+	t.Parallel()
+
+	uc, m := questionUseCase(t)
+
+	explanation := "Because."
+	v1 := entity.Question{
+		ID: uuid.New(), Exam: entity.ExamCategoryNEETPG, QuestionText: "Q",
+		OptionA: "a", OptionB: "b", OptionC: "c", OptionD: "d",
+		CorrectOption: 1, CorrectOptions: []int{1}, ChoiceType: entity.ChoiceTypeSingle,
+		DifficultyLevel: 1, IsActive: true, Version: 1,
+	}
+	v2 := v1
+	v2.CorrectOption, v2.CorrectOptions, v2.Explanation, v2.Version = 2, []int{2}, &explanation, 2
+
+	m.questions.EXPECT().GetVersion(gomock.Any(), v1.ID, 1).Return(entity.QuestionVersion{Question: v1}, nil).Times(3)
+	m.questions.EXPECT().GetVersion(gomock.Any(), v1.ID, 2).Return(entity.QuestionVersion{Question: v2}, nil)
+	m.questions.EXPECT().GetVersion(gomock.Any(), v1.ID, 9).Return(entity.QuestionVersion{}, repo.ErrNotFound)
+
+	diff, err := uc.DiffVersions(context.Background(), v1.ID, 1, 2)
+	require.NoError(t, err)
+	require.Equal(t, []entity.QuestionFieldChange{
+		{Field: "correctOptions", From: []int{1}, To: []int{2}},
+		{Field: "explanation", From: nil, To: "Because."},
+	}, diff.Changes)
+
+	_, err = uc.DiffVersions(context.Background(), v1.ID, 1, 9)
+	require.ErrorIs(t, err, question.ErrVersionNotFound)
+
+	m.questions.EXPECT().GetByID(gomock.Any(), v1.ID).Return(v2, nil)
+	m.questions.EXPECT().Update(gomock.Any(), gomock.Any(), nil).
+		DoAndReturn(func(_ context.Context, v entity.QuestionVersion, _ *int) (entity.Question, error) {
+			require.Equal(t, 2, v.Question.Version)
+			require.Equal(t, 1, *v.RestoredFrom)
+			require.Equal(t, []string{"correctOptions", "explanation"}, v.ChangedFields)
+			require.Nil(t, v.Question.Explanation)
+
+			v.Question.Version = 3
+
+			return v.Question, nil
+		})
+
+	restored, err := uc.Rollback(context.Background(), uuid.New(), v1.ID, 1)
+	require.NoError(t, err)
+	require.Equal(t, 3, restored.Version)
+	require.Equal(t, 1, restored.CorrectOption)
+}
+
+func TestDryRunImportCSV(t *testing.T) {
+	t.Parallel()
+
+	uc, m := questionUseCase(t)
+
+	subject := entity.Subject{ID: uuid.New(), Exam: entity.ExamCategoryNEETPG, Name: "Physiology"}
+	general := entity.Topic{ID: uuid.New(), SubjectID: subject.ID, Name: "General"}
+	existing := uuid.New()
+
+	m.subjects.EXPECT().ListByExam(gomock.Any(), gomock.Any()).Return([]entity.Subject{subject}, nil)
+	m.topics.EXPECT().ListBySubject(gomock.Any(), subject.ID).Return([]entity.Topic{general}, nil)
+	noSignedQuestions(m)
+	m.questions.EXPECT().ExistingIDs(gomock.Any(), gomock.Any()).Return([]uuid.UUID{existing}, nil)
+
+	file := "Subject,Topic,Question,Option A,Option B,Option C,Option D,Correct,Id\n" +
+		"physiology,,Q1,a,b,c,d,B,\n" +
+		"Physiology,Renal,Q2,a,b,c,d,1,\n" +
+		"Physiology,General,Q3,a,b,c,d,5,\n" +
+		"Physiology,General,Q4,a,b,c,d,\"A,C\",\n" +
+		"\n" +
+		"Physiology,General,Q5,a,b,c,d,A," + existing.String() + "\n"
+
+	report, err := uc.DryRunImport(context.Background(), entity.QuestionImportOptions{Exam: entity.ExamCategoryNEETPG}, "bank.csv", []byte(file))
+	require.NoError(t, err)
+
+	require.Equal(t, entity.QuestionImportCSV, report.Format)
+	require.Equal(t, 5, report.TotalRows)
+	require.Equal(t, 1, report.ValidRows)
+	require.Equal(t, 1, report.SkippedRows)
+	require.Equal(t, 3, report.FailedRows)
+	require.Equal(t, []entity.QuestionImportRowError{
+		{Row: 3, Message: `unknown topic "Renal" in subject "Physiology"`},
+		{Row: 4, Message: `correct option must be 1 to 4 or A to D, got "5"`},
+		{Row: 5, Message: "single questions need exactly one correct option"},
+	}, report.Errors)
+}
+
+func TestDryRunImportNDJSONCreateMissing(t *testing.T) {
+	t.Parallel()
+
+	uc, m := questionUseCase(t)
+
+	m.subjects.EXPECT().ListByExam(gomock.Any(), gomock.Any()).Return(nil, nil)
+	noSignedQuestions(m)
+	m.questions.EXPECT().ExistingIDs(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+	file := `{"question":"Q1","cop":1,"opa":"a","opb":"b","opc":"c","opd":"d","subject_name":"Anatomy","topic_name":null,"id":"` + uuid.NewString() + `"}
+{"question":"Q2","cop":2,"opa":"a","opb":"b","opc":"c","opd":"d","subject_name":"Anatomy","topic_name":"Thorax"}
+not json
+`
+
+	report, err := uc.DryRunImport(context.Background(), entity.QuestionImportOptions{
+		Exam:          entity.ExamCategoryNEETPG,
+		CreateMissing: true,
+	}, "medmcqa.jsonl", []byte(file))
+	require.NoError(t, err)
+
+	require.Equal(t, entity.QuestionImportNDJSON, report.Format)
+	require.Equal(t, 2, report.ValidRows)
+	require.Equal(t, 1, report.FailedRows)
+	require.Equal(t, 3, report.Errors[0].Row)
+	require.Equal(t, []string{"Anatomy"}, report.NewSubjects)
+	require.Equal(t, []string{"Anatomy / General", "Anatomy / Thorax"}, report.NewTopics)
+}
+
+func TestStartImportRejectsUnreadableFile(t *testing.T) {
+	t.Parallel()
+
+	uc, _ := questionUseCase(t)
+	opts := entity.QuestionImportOptions{Exam: entity.ExamCategoryNEETPG}
+
+	_, err := uc.StartImport(context.Background(), uuid.New(), opts, "bank.csv", []byte("subject,topic\nA,B\n"))
+	require.ErrorIs(t, err, question.ErrInvalidImportFile)
+
+	_, err = uc.StartImport(context.Background(), uuid.New(), opts, "bank.pdf", nil)
+	require.ErrorIs(t, err, question.ErrUnsupportedFormat)
+}
+
+func TestRunImportsXLSX(t *testing.T) {
+	t.Parallel()
+
+	uc, m := questionUseCase(t)
+
+	job := entity.QuestionImportJob{
+		ID:            uuid.New(),
+		Exam:          entity.ExamCategoryJEE,
+		Format:        entity.QuestionImportXLSX,
+		CreateMissing: true,
+		Status:        entity.QuestionImportRunning,
+	}
+	payload := testWorkbook(t, [][]string{
+		{"subject", "topic", "question", "choice_type", "numeric_answer", "difficulty"},
+		{"Physics", "Optics", "Focal length?", "numerical", "12.5", "3"},
+		{"Physics", "Optics", "Refractive index?", "numerical", "", "2"},
+	})
+
+	m.imports.EXPECT().ClaimNext(gomock.Any(), gomock.Any()).Return(job, payload, nil)
+	m.imports.EXPECT().ClaimNext(gomock.Any(), gomock.Any()).Return(entity.QuestionImportJob{}, nil, repo.ErrNotFound)
+	m.subjects.EXPECT().ListByExam(gomock.Any(), gomock.Any()).Return(nil, nil)
+	m.subjects.EXPECT().Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, s entity.Subject) (entity.Subject, error) {
+			require.Equal(t, "Physics", s.Name)
+			require.Equal(t, entity.ExamCategoryJEE, s.Exam)
+
+			return s, nil
+		})
+	m.topics.EXPECT().Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, topic entity.Topic) (entity.Topic, error) {
+			return topic, nil
+		})
+	noSignedQuestions(m)
+	m.questions.EXPECT().ExistingIDs(gomock.Any(), gomock.Any()).Return(nil, nil)
+	m.questions.EXPECT().Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, v entity.QuestionVersion) (entity.Question, error) {
+			require.Equal(t, 12.5, *v.Question.NumericAnswer)
+			require.Equal(t, 3, v.Question.DifficultyLevel)
+			require.Equal(t, entity.QuestionStatusDraft, v.Question.Status)
+			require.Equal(t, &job.CreatedBy, v.AuthorID)
+
+			return v.Question, nil
+		})
+	m.imports.EXPECT().Save(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, saved entity.QuestionImportJob) error {
+			require.Equal(t, entity.QuestionImportCompleted, saved.Status)
+			require.Equal(t, 2, saved.TotalRows)
+			require.Equal(t, 2, saved.ProcessedRows)
+			require.Equal(t, 1, saved.ImportedRows)
+			require.Equal(t, 1, saved.FailedRows)
+			require.Equal(t, 3, saved.Errors[0].Row)
+			require.NotNil(t, saved.FinishedAt)
+
+			return nil
+		})
+
+	finished, err := uc.RunImports(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, finished)
+}
+
+// exportBank streams a small mixed bank through the question mocks.
+func exportBank(m questionMocks, subject entity.Subject, topic entity.Topic) []entity.Question {
+	answer, tolerance := 9.81, 0.05
+	explanation := "Use g = 9.81 {m/s²}"
+
+	bank := []entity.Question{
+		{
+			ID: uuid.New(), Exam: subject.Exam, SubjectID: subject.ID, TopicID: topic.ID,
+			QuestionText: "Pick the vectors", OptionA: "force", OptionB: "mass", OptionC: "velocity", OptionD: "time",
+			CorrectOption: 1, CorrectOptions: []int{1, 3}, ChoiceType: entity.ChoiceTypeMulti,
+			DifficultyLevel: 2, IsActive: true, Tags: []string{"AIIMS", "PYQ-2019"},
+		},
+		{
+			ID: uuid.New(), Exam: subject.Exam, SubjectID: subject.ID, TopicID: topic.ID,
+			QuestionText: "Value of g?", NumericAnswer: &answer, NumericTolerance: &tolerance,
+			Explanation: &explanation, ChoiceType: entity.ChoiceTypeNumerical, DifficultyLevel: 1, IsActive: true,
+		},
+		{
+			ID: uuid.New(), Exam: subject.Exam, SubjectID: subject.ID, TopicID: topic.ID,
+			QuestionText: "Match the units", ChoiceType: entity.ChoiceTypeMatrixMatch, DifficultyLevel: 3,
+			Matrix: &entity.MatrixMatch{
+				Rows:    []string{"force", "energy"},
+				Columns: []string{"newton", "joule", "watt"},
+				Answer:  [][]int{{1}, {2}},
+			},
+		},
+	}
+
+	m.questions.EXPECT().Stream(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ repo.QuestionFilter, fn func(entity.Question) error) error {
+			for _, q := range bank {
+				if err := fn(q); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	m.subjects.EXPECT().ListByExam(gomock.Any(), gomock.Any()).Return([]entity.Subject{subject}, nil)
+	m.topics.EXPECT().ListBySubject(gomock.Any(), subject.ID).Return([]entity.Topic{topic}, nil)
+
+	return bank
+}
+
+func TestExportCSVReimports(t *testing.T) {
+	t.Parallel()
+
+	uc, m := questionUseCase(t)
+
+	subject := entity.Subject{ID: uuid.New(), Exam: entity.ExamCategoryJEE, Name: "Physics"}
+	topic := entity.Topic{ID: uuid.New(), SubjectID: subject.ID, Name: "Mechanics"}
+	exportBank(m, subject, topic)
+
+	var out bytes.Buffer
+	require.NoError(t, uc.Export(context.Background(), repo.QuestionFilter{}, entity.QuestionExportCSV, &out))
+	require.Contains(t, out.String(), ",AIIMS; PYQ-2019\n")
+
+	m.subjects.EXPECT().ListByExam(gomock.Any(), gomock.Any()).Return([]entity.Subject{subject}, nil)
+	m.topics.EXPECT().ListBySubject(gomock.Any(), subject.ID).Return([]entity.Topic{topic}, nil)
+	noSignedQuestions(m)
+	m.questions.EXPECT().ExistingIDs(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+	report, err := uc.DryRunImport(context.Background(), entity.QuestionImportOptions{Exam: entity.ExamCategoryJEE}, "export.csv", out.Bytes())
+	require.NoError(t, err)
+	require.Empty(t, report.Errors)
+	require.Equal(t, 3, report.ValidRows)
+}
+
+func TestExportGIFT(t *testing.T) {
+	t.Parallel()
+
+	uc, m := questionUseCase(t)
+
+	subject := entity.Subject{ID: uuid.New(), Exam: entity.ExamCategoryJEE, Name: "Physics"}
+	topic := entity.Topic{ID: uuid.New(), SubjectID: subject.ID, Name: "Mechanics"}
+	bank := exportBank(m, subject, topic)
+
+	var out bytes.Buffer
+	require.NoError(t, uc.Export(context.Background(), repo.QuestionFilter{}, entity.QuestionExportGIFT, &out))
+
+	require.Equal(t, "$CATEGORY: Physics/Mechanics\n\n"+
+		"::"+bank[0].ID.String()+"::Pick the vectors { ~%50%force ~%-100%mass ~%50%velocity ~%-100%time}\n\n"+
+		"::"+bank[1].ID.String()+"::Value of g? {#9.81:0.05 ####Use g \\= 9.81 \\{m/s²\\}}\n\n"+
+		"::"+bank[2].ID.String()+"::Match the units { =force -> newton =energy -> joule = -> watt}\n\n",
+		out.String())
+}
+
+func TestExportQTI(t *testing.T) {
+	t.Parallel()
+
+	uc, m := questionUseCase(t)
+
+	subject := entity.Subject{ID: uuid.New(), Exam: entity.ExamCategoryJEE, Name: "Physics"}
+	topic := entity.Topic{ID: uuid.New(), SubjectID: subject.ID, Name: "Mechanics"}
+	bank := exportBank(m, subject, topic)
+
+	var out bytes.Buffer
+	require.NoError(t, uc.Export(context.Background(), repo.QuestionFilter{}, entity.QuestionExportQTI, &out))
+
+	archive, err := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	require.NoError(t, err)
+
+	files := make(map[string]string, len(archive.File))
+	for _, f := range archive.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+
+		var content bytes.Buffer
+		_, err = content.ReadFrom(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+
+		files[f.Name] = content.String()
+	}
+
+	require.Len(t, files, 4)
+	require.Equal(t, "imsmanifest.xml", archive.File[3].Name)
+	require.Contains(t, files["imsmanifest.xml"], `href="items/q-`+bank[2].ID.String()+`.xml"`)
+
+	multi := files["items/q-"+bank[0].ID.String()+".xml"]
+	require.Contains(t, multi, `cardinality="multiple" baseType="identifier"`)
+	require.Contains(t, multi, "<value>A</value>\n      <value>C</value>")
+
+	numeric := files["items/q-"+bank[1].ID.String()+".xml"]
+	require.Contains(t, numeric, `<equal toleranceMode="absolute" tolerance="0.05">`)
+	require.Contains(t, numeric, "<value>9.81</value>")
+
+	matrix := files["items/q-"+bank[2].ID.String()+".xml"]
+	require.Contains(t, matrix, "<value>R1 C1</value>")
+	require.Contains(t, matrix, "<value>R2 C2</value>")
+}
+
+func TestExportRejectsUnknownFormat(t *testing.T) {
+	t.Parallel()
+
+	uc, _ := questionUseCase(t)
+
+	err := uc.Export(context.Background(), repo.QuestionFilter{}, "docx", &bytes.Buffer{})
+	require.ErrorIs(t, err, question.ErrUnsupportedFormat)
+}
+
+func TestAdminCreateWarnsOfDuplicates(t *testing.T) {
+	t.Parallel()
+
+	uc, m := questionUseCase(t)
+
+	copied := signedQuestion(entity.ExamCategoryNEETPG, "Which nerve supplies the deltoid muscle?")
+	unrelated := signedQuestion(entity.ExamCategoryNEETPG, "Name the largest organ of the human body.")
+
+	m.signatures.EXPECT().Candidates(gomock.Any(), entity.ExamCategoryNEETPG, gomock.Len(minhash.Bands)).
+		Return([]entity.QuestionSignature{copied, unrelated}, nil)
+	echoCreatedQuestion(m.questions)
+
+	created, err := uc.AdminCreate(context.Background(), uuid.New(), entity.QuestionCreateRequest{
+		Exam:          entity.ExamCategoryNEETPG,
+		SubjectID:     uuid.New(),
+		TopicID:       uuid.New(),
+		QuestionText:  "  which NERVE supplies the Deltoid muscle ",
+		OptionA:       "Axillary",
+		OptionB:       "Radial",
+		OptionC:       "Median",
+		OptionD:       "Ulnar",
+		CorrectOption: 1,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []entity.SimilarQuestion{{
+		QuestionID:   copied.QuestionID,
+		QuestionText: copied.QuestionText,
+		Similarity:   1,
+	}}, created.Duplicates)
+}
+
+func TestDryRunImportFlagsDuplicates(t *testing.T) {
+	t.Parallel()
+
+	uc, m := questionUseCase(t)
+
+	subject := entity.Subject{ID: uuid.New(), Exam: entity.ExamCategoryNEETPG, Name: "Anatomy"}
+	general := entity.Topic{ID: uuid.New(), SubjectID: subject.ID, Name: "General"}
+	signed := signedQuestion(entity.ExamCategoryNEETPG, "Which nerve supplies the deltoid muscle?")
+
+	m.subjects.EXPECT().ListByExam(gomock.Any(), gomock.Any()).Return([]entity.Subject{subject}, nil)
+	m.topics.EXPECT().ListBySubject(gomock.Any(), subject.ID).Return([]entity.Topic{general}, nil)
+	m.questions.EXPECT().ExistingIDs(gomock.Any(), gomock.Any()).Return(nil, nil)
+	m.signatures.EXPECT().Candidates(gomock.Any(), entity.ExamCategoryNEETPG, gomock.Any()).
+		Return([]entity.QuestionSignature{signed}, nil)
+
+	file := "Subject,Question,Option A,Option B,Option C,Option D,Correct\n" +
+		"Anatomy,Which nerve supplies the deltoid muscle?,a,b,c,d,A\n" +
+		"Anatomy,What is the normal adult resting heart rate?,a,b,c,d,A\n" +
+		"Anatomy,What is the normal adult resting heart-rate,a,b,c,d,B\n"
+
+	report, err := uc.DryRunImport(context.Background(), entity.QuestionImportOptions{Exam: entity.ExamCategoryNEETPG}, "bank.csv", []byte(file))
+	require.NoError(t, err)
+
+	require.Equal(t, 3, report.ValidRows)
+	require.Equal(t, 2, report.DuplicateRows)
+	require.Len(t, report.Duplicates, 2)
+
+	require.Equal(t, 2, report.Duplicates[0].Row)
+	require.Equal(t, signed.QuestionID, report.Duplicates[0].Similar[0].QuestionID)
+	require.Zero(t, report.Duplicates[0].Similar[0].Row)
+
+	require.Equal(t, 4, report.Duplicates[1].Row)
+	require.Equal(t, 3, report.Duplicates[1].Similar[0].Row)
+	require.InDelta(t, 1.0, report.Duplicates[1].Similar[0].Similarity, 0.001)
+}
+
+func TestSignQuestions(t *testing.T) {
+	t.Parallel()
+
+	uc, m := questionUseCase(t)
+
+	stale := []entity.QuestionSignature{
+		{QuestionID: uuid.New(), Exam: entity.ExamCategoryJEE, Version: 2, QuestionText: "Focal length of a convex lens?"},
+		{QuestionID: uuid.New(), Exam: entity.ExamCategoryJEE, Version: 1, QuestionText: "Refractive index of glass?"},
+	}
+
+	m.signatures.EXPECT().ListStale(gomock.Any(), gomock.Any()).Return(stale, nil)
+	m.signatures.EXPECT().Save(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, saved []entity.QuestionSignature) error {
+			require.Len(t, saved, 2)
+			for _, s := range saved {
+				sig, err := minhash.FromBytes(s.Signature)
+				require.NoError(t, err)
+				require.Equal(t, minhash.New(s.QuestionText), sig)
+				require.Len(t, s.BandKeys, minhash.Bands)
+			}
+
+			return nil
+		})
+
+	signed, err := uc.SignQuestions(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, signed)
+}
+
+func TestDuplicateReport(t *testing.T) {
+	t.Parallel()
+
+	uc, m := questionUseCase(t)
+
+	a := signedQuestion(entity.ExamCategoryJEE, "A ball is thrown vertically upwards with a speed of 20 m/s.")
+	b := signedQuestion(entity.ExamCategoryJEE, "A ball is thrown vertically upwards with a speed of 20 m/s")
+	c := signedQuestion(entity.ExamCategoryJEE, "a BALL is thrown vertically upwards, with a speed of 20 m/s!")
+	d := signedQuestion(entity.ExamCategoryJEE, "Find the moment of inertia of a thin uniform rod.")
+
+	m.signatures.EXPECT().Collisions(gomock.Any(), entity.ExamCategoryJEE, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ entity.ExamCategory, fn func([]uuid.UUID) error) error {
+			for _, group := range [][]uuid.UUID{{a.QuestionID, b.QuestionID}, {b.QuestionID, c.QuestionID, d.QuestionID}} {
+				if err := fn(group); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	m.signatures.EXPECT().Get(gomock.Any(), gomock.Len(4)).Return([]entity.QuestionSignature{a, b, c, d}, nil)
+
+	report, err := uc.DuplicateReport(context.Background(), entity.ExamCategoryJEE, question.DefaultDuplicateThreshold)
+	require.NoError(t, err)
+	require.Equal(t, 4, report.Compared)
+	require.Len(t, report.Clusters, 1)
+
+	cluster := report.Clusters[0]
+	require.InDelta(t, 1.0, cluster.Similarity, 0.001)
+
+	ids := make([]uuid.UUID, len(cluster.Questions))
+	for i, q := range cluster.Questions {
+		ids[i] = q.QuestionID
+	}
+	require.ElementsMatch(t, []uuid.UUID{a.QuestionID, b.QuestionID, c.QuestionID}, ids)
+
+	_, err = uc.DuplicateReport(context.Background(), entity.ExamCategoryJEE, 1.5)
+	require.ErrorIs(t, err, question.ErrInvalidThreshold)
+}
+
+// testWorkbook builds a minimal single-sheet XLSX file with inline strings.
+func testWorkbook(t *testing.T, rows [][]string) []byte {
+	t.Helper()
+
+	var sheet bytes.Buffer
+	for _, row := range rows {
+		sheet.WriteString("<row>")
+		for _, value := range row {
+			sheet.WriteString(`<c t="inlineStr"><is><t>` + value + `</t></is></c>`)
+		}
+		sheet.WriteString("</row>")
+	}
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	writeWorkbookParts(t, w)
+	f, err := w.Create("xl/worksheets/sheet1.xml")
+	require.NoError(t, err)
+	_, err = f.Write([]byte(testSheet(sheet.String())))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	return buf.Bytes()
+}
+
+// testSheet wraps rows of cell XML in a worksheet.
+func testSheet(rows string) string {
+	return `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` +
+		rows + "</sheetData></worksheet>"
+}
+
+// writeWorkbookParts writes the workbook and its relationships, which point
+// at xl/worksheets/sheet1.xml.
+func writeWorkbookParts(t *testing.T, w *zip.Writer) {
+	t.Helper()
+
+	files := map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+			`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="Questions" sheetId="1" r:id="rId1"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Target="worksheets/sheet1.xml"/></Relationships>`,
+	}
+
+	for name, content := range files {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+	}
+}
+
+func TestImportRejectsColumnsPastXFD(t *testing.T) {
+	t.Parallel()
+
+	uc, _ := questionUseCase(t)
+	opts := entity.QuestionImportOptions{Exam: entity.ExamCategoryNEETPG}
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	writeWorkbookParts(t, w)
+	f, err := w.Create("xl/worksheets/sheet1.xml")
+	require.NoError(t, err)
+	_, err = f.Write([]byte(testSheet(`<row><c r="ZZZZZZZZZZZZ1" t="inlineStr"><is><t>question</t></is></c></row>`)))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	_, err = uc.StartImport(context.Background(), uuid.New(), opts, "bank.xlsx", buf.Bytes())
+	require.ErrorIs(t, err, question.ErrInvalidImportFile)
+	require.ErrorContains(t, err, "past the last column")
+}
+
+func TestImportRejectsOversizedWorkbookParts(t *testing.T) {
+	t.Parallel()
+
+	uc, _ := questionUseCaseWith(t, question.Options{ImportLimit: 4 << 10})
+	opts := entity.QuestionImportOptions{Exam: entity.ExamCategoryNEETPG}
+	sheet := []byte(testSheet(strings.Repeat("<row></row>", 4<<10)))
+
+	t.Run("by the size in the zip header", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		w := zip.NewWriter(&buf)
+		writeWorkbookParts(t, w)
+		f, err := w.Create("xl/worksheets/sheet1.xml")
+		require.NoError(t, err)
+		_, err = f.Write(sheet)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+
+		_, err = uc.StartImport(context.Background(), uuid.New(), opts, "bank.xlsx", buf.Bytes())
+		require.ErrorIs(t, err, question.ErrInvalidImportFile)
+		require.ErrorIs(t, err, xlsx.ErrTooLarge)
+	})
+
+	t.Run("when the zip header understates it", func(t *testing.T) {
+		t.Parallel()
+
+		var compressed bytes.Buffer
+		fw, err := flate.NewWriter(&compressed, flate.BestCompression)
+		require.NoError(t, err)
+		_, err = fw.Write(sheet)
+		require.NoError(t, err)
+		require.NoError(t, fw.Close())
+
+		var buf bytes.Buffer
+		w := zip.NewWriter(&buf)
+		writeWorkbookParts(t, w)
+		f, err := w.CreateRaw(&zip.FileHeader{
+			Name:               "xl/worksheets/sheet1.xml",
+			Method:             zip.Deflate,
+			CRC32:              crc32.ChecksumIEEE(sheet),
+			CompressedSize64:   uint64(compressed.Len()),
+			UncompressedSize64: 100,
+		})
+		require.NoError(t, err)
+		_, err = f.Write(compressed.Bytes())
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+
+		_, err = uc.StartImport(context.Background(), uuid.New(), opts, "bank.xlsx", buf.Bytes())
+		require.ErrorIs(t, err, question.ErrInvalidImportFile)
+	})
+}
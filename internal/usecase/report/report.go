@@ -0,0 +1,182 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/repo"
+)
+
+const (
+	_defaultPageSize = 20
+	_maxPageSize     = 100
+)
+
+var (
+	// ErrQuestionNotFound when the reported question does not exist.
+	ErrQuestionNotFound = errors.New("question not found")
+	// ErrAlreadyReported when the user already has an open report on the question.
+	ErrAlreadyReported = errors.New("you have already reported this question")
+	// ErrRateLimited when the user filed too many reports in the last hour.
+	ErrRateLimited = errors.New("too many reports, try again later")
+	// ErrNoOpenReports when a question has no open reports to resolve.
+	ErrNoOpenReports = errors.New("question has no open reports")
+)
+
+// Options tune reporting.
+type Options struct {
+	// HourlyLimit is how many reports a user can file per rolling hour;
+	// 0 means no limit.
+	HourlyLimit int
+	// AutoRetireAt retires a published question once it has this many open
+	// reports; 0 turns it off.
+	AutoRetireAt int
+}
+
+// UseCase lets learners report errors in questions and admins triage them.
+type UseCase struct {
+	repo      repo.QuestionReportRepository
+	questions repo.QuestionRepository
+	opts      Options
+}
+
+// New constructs UseCase.
+func New(repo repo.QuestionReportRepository, questions repo.QuestionRepository, opts Options) *UseCase {
+	return &UseCase{repo: repo, questions: questions, opts: opts}
+}
+
+// Report files a learner's report against the current version of a
+// question, and retires the question if that brings its open reports to
+// the AutoRetireAt threshold.
+func (uc *UseCase) Report(ctx context.Context, userID, questionID uuid.UUID, req entity.QuestionReportRequest) (entity.QuestionReport, error) {
+	report, err := uc.repo.Create(ctx, entity.QuestionReport{
+		QuestionID: questionID,
+		UserID:     userID,
+		Reason:     req.Reason,
+		Details:    req.Details,
+	}, uc.opts.HourlyLimit)
+	switch {
+	case errors.Is(err, repo.ErrLimitReached):
+		return entity.QuestionReport{}, ErrRateLimited
+	case errors.Is(err, repo.ErrNotFound):
+		return entity.QuestionReport{}, ErrQuestionNotFound
+	case errors.Is(err, repo.ErrConflict):
+		return entity.QuestionReport{}, ErrAlreadyReported
+	case err != nil:
+		return entity.QuestionReport{}, fmt.Errorf("report - Create: %w", err)
+	}
+
+	if err := uc.autoRetire(ctx, questionID); err != nil {
+		return entity.QuestionReport{}, err
+	}
+
+	return report, nil
+}
+
+// ListGroups pages through the questions with reports in a status, most
+// reported first.
+func (uc *UseCase) ListGroups(ctx context.Context, status entity.QuestionReportStatus, page, pageSize int) (entity.QuestionReportGroupList, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > _maxPageSize {
+		pageSize = _defaultPageSize
+	}
+
+	groups, total, err := uc.repo.ListGroups(ctx, status, page, pageSize)
+	if err != nil {
+		return entity.QuestionReportGroupList{}, fmt.Errorf("report - ListGroups: %w", err)
+	}
+
+	return entity.QuestionReportGroupList{
+		Items: groups,
+		Meta:  entity.AdminUsersMeta{Page: page, PageSize: pageSize, Total: total},
+	}, nil
+}
+
+// ListByQuestion returns the reports of a question, newest first.
+func (uc *UseCase) ListByQuestion(ctx context.Context, questionID uuid.UUID, status *entity.QuestionReportStatus) ([]entity.QuestionReport, error) {
+	reports, err := uc.repo.ListByQuestion(ctx, questionID, status)
+	if err != nil {
+		return nil, fmt.Errorf("report - ListByQuestion: %w", err)
+	}
+
+	return reports, nil
+}
+
+// Resolve closes the open reports of a question as resolved or dismissed
+// and, in the same transaction, tells each reporter the outcome. Fixing or
+// retiring the question itself is done through the review workflow.
+func (uc *UseCase) Resolve(ctx context.Context, adminID, questionID uuid.UUID, req entity.QuestionReportResolveRequest) (entity.QuestionReportResolution, error) {
+	closed, err := uc.repo.Close(ctx, questionID, req.Status, req.Note, adminID, outcome)
+	if err != nil {
+		return entity.QuestionReportResolution{}, fmt.Errorf("report - Close: %w", err)
+	}
+	if len(closed) == 0 {
+		return entity.QuestionReportResolution{}, ErrNoOpenReports
+	}
+
+	return entity.QuestionReportResolution{QuestionID: questionID, Status: req.Status, Closed: len(closed)}, nil
+}
+
+// autoRetire takes a published question out of circulation once enough
+// learners have open reports on it.
+func (uc *UseCase) autoRetire(ctx context.Context, questionID uuid.UUID) error {
+	if uc.opts.AutoRetireAt <= 0 {
+		return nil
+	}
+
+	open, err := uc.repo.CountOpen(ctx, questionID)
+	if err != nil {
+		return fmt.Errorf("report - CountOpen: %w", err)
+	}
+	if open < uc.opts.AutoRetireAt {
+		return nil
+	}
+
+	question, err := uc.questions.GetByID(ctx, questionID)
+	if err != nil {
+		return fmt.Errorf("report - Questions.GetByID: %w", err)
+	}
+	if question.Status != entity.QuestionStatusPublished {
+		return nil
+	}
+
+	_, err = uc.questions.Transition(ctx, questionID, entity.QuestionStatusPublished, entity.QuestionStatusRetired, question.ReviewerID)
+	// Someone else moved it first.
+	if errors.Is(err, repo.ErrConflict) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("report - Questions.Transition: %w", err)
+	}
+
+	return nil
+}
+
+// outcome is the notification telling a reporter what became of a report.
+func outcome(report entity.QuestionReport) entity.Notification {
+	n := entity.Notification{
+		UserID: report.UserID,
+		Kind:   entity.NotificationReportResolved,
+		Title:  "Question corrected",
+		Body:   "Thanks for reporting a question. We have corrected it.",
+		Data:   map[string]string{"questionId": report.QuestionID.String(), "reportId": report.ID.String()},
+	}
+
+	if report.Status == entity.QuestionReportDismissed {
+		n.Kind = entity.NotificationReportDismissed
+		n.Title = "Question report reviewed"
+		n.Body = "Thanks for reporting a question. We checked it and found it correct as it stands."
+	}
+
+	if report.ResolutionNote != "" {
+		n.Body += "\n\n" + report.ResolutionNote
+	}
+
+	return n
+}
@@ -0,0 +1,145 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/repo"
+	"github.com/evrone/go-clean-template/internal/usecase/report"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type reportMocks struct {
+	reports   *MockQuestionReportRepository
+	questions *MockQuestionRepository
+}
+
+func reportUseCase(t *testing.T, opts report.Options) (*report.UseCase, reportMocks) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+
+	m := reportMocks{
+		reports:   NewMockQuestionReportRepository(mockCtl),
+		questions: NewMockQuestionRepository(mockCtl),
+	}
+
+	return report.New(m.reports, m.questions, opts), m
+}
+
+func TestReportQuestion(t *testing.T) {
+	t.Parallel()
+
+	userID, questionID := uuid.New(), uuid.New()
+	req := entity.QuestionReportRequest{Reason: entity.QuestionReportWrongAnswer, Details: "The key says B; it is C."}
+
+	t.Run("files a report", func(t *testing.T) {
+		t.Parallel()
+
+		uc, m := reportUseCase(t, report.Options{HourlyLimit: 3})
+		m.reports.EXPECT().Create(gomock.Any(), gomock.Any(), 3).
+			DoAndReturn(func(_ context.Context, r entity.QuestionReport, _ int) (entity.QuestionReport, error) {
+				require.Equal(t, questionID, r.QuestionID)
+				require.Equal(t, entity.QuestionReportWrongAnswer, r.Reason)
+
+				r.Status = entity.QuestionReportOpen
+
+				return r, nil
+			})
+
+		filed, err := uc.Report(context.Background(), userID, questionID, req)
+		require.NoError(t, err)
+		require.Equal(t, entity.QuestionReportOpen, filed.Status)
+	})
+
+	t.Run("is rate limited", func(t *testing.T) {
+		t.Parallel()
+
+		uc, m := reportUseCase(t, report.Options{HourlyLimit: 3})
+		m.reports.EXPECT().Create(gomock.Any(), gomock.Any(), 3).Return(entity.QuestionReport{}, repo.ErrLimitReached)
+
+		_, err := uc.Report(context.Background(), userID, questionID, req)
+		require.ErrorIs(t, err, report.ErrRateLimited)
+	})
+
+	t.Run("one open report per question", func(t *testing.T) {
+		t.Parallel()
+
+		uc, m := reportUseCase(t, report.Options{})
+		m.reports.EXPECT().Create(gomock.Any(), gomock.Any(), 0).Return(entity.QuestionReport{}, repo.ErrConflict)
+
+		_, err := uc.Report(context.Background(), userID, questionID, req)
+		require.ErrorIs(t, err, report.ErrAlreadyReported)
+	})
+
+	t.Run("retires a question at the threshold", func(t *testing.T) {
+		t.Parallel()
+
+		uc, m := reportUseCase(t, report.Options{AutoRetireAt: 5})
+		reviewer := uuid.New()
+
+		m.reports.EXPECT().Create(gomock.Any(), gomock.Any(), 0).Return(entity.QuestionReport{ID: uuid.New()}, nil)
+		m.reports.EXPECT().CountOpen(gomock.Any(), questionID).Return(5, nil)
+		m.questions.EXPECT().GetByID(gomock.Any(), questionID).
+			Return(entity.Question{ID: questionID, Status: entity.QuestionStatusPublished, ReviewerID: &reviewer}, nil)
+		m.questions.EXPECT().Transition(gomock.Any(), questionID, entity.QuestionStatusPublished, entity.QuestionStatusRetired, &reviewer).
+			Return(entity.Question{}, nil)
+
+		_, err := uc.Report(context.Background(), userID, questionID, req)
+		require.NoError(t, err)
+	})
+
+	t.Run("below the threshold the question stays", func(t *testing.T) {
+		t.Parallel()
+
+		uc, m := reportUseCase(t, report.Options{AutoRetireAt: 5})
+		m.reports.EXPECT().Create(gomock.Any(), gomock.Any(), 0).Return(entity.QuestionReport{ID: uuid.New()}, nil)
+		m.reports.EXPECT().CountOpen(gomock.Any(), questionID).Return(4, nil)
+
+		_, err := uc.Report(context.Background(), userID, questionID, req)
+		require.NoError(t, err)
+	})
+}
+
+func TestResolveQuestionReports(t *testing.T) {
+	t.Parallel()
+
+	uc, m := reportUseCase(t, report.Options{})
+
+	adminID, questionID := uuid.New(), uuid.New()
+	first, second := uuid.New(), uuid.New()
+
+	m.reports.EXPECT().Close(gomock.Any(), questionID, entity.QuestionReportDismissed, "Option C is outdated, not wrong.", adminID, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ uuid.UUID, _ entity.QuestionReportStatus, _ string, _ uuid.UUID,
+			notify func(entity.QuestionReport) entity.Notification,
+		) ([]entity.QuestionReport, error) {
+			closed := []entity.QuestionReport{
+				{ID: uuid.New(), QuestionID: questionID, UserID: first, Status: entity.QuestionReportDismissed, ResolutionNote: "Option C is outdated, not wrong."},
+				{ID: uuid.New(), QuestionID: questionID, UserID: second, Status: entity.QuestionReportDismissed, ResolutionNote: "Option C is outdated, not wrong."},
+			}
+
+			n := notify(closed[1])
+			require.Equal(t, second, n.UserID)
+			require.Equal(t, entity.NotificationReportDismissed, n.Kind)
+			require.Contains(t, n.Body, "Option C is outdated")
+			require.Equal(t, questionID.String(), n.Data["questionId"])
+			require.Equal(t, closed[1].ID.String(), n.Data["reportId"])
+
+			return closed, nil
+		})
+
+	resolution, err := uc.Resolve(context.Background(), adminID, questionID, entity.QuestionReportResolveRequest{
+		Status: entity.QuestionReportDismissed,
+		Note:   "Option C is outdated, not wrong.",
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, resolution.Closed)
+
+	m.reports.EXPECT().Close(gomock.Any(), questionID, entity.QuestionReportResolved, "", adminID, gomock.Any()).Return(nil, nil)
+
+	_, err = uc.Resolve(context.Background(), adminID, questionID, entity.QuestionReportResolveRequest{Status: entity.QuestionReportResolved})
+	require.ErrorIs(t, err, report.ErrNoOpenReports)
+}
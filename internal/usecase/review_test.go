@@ -0,0 +1,244 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase/auth"
+	"github.com/evrone/go-clean-template/internal/usecase/question"
+	"github.com/evrone/go-clean-template/pkg/jwt"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestQuestionReviewWorkflow(t *testing.T) {
+	t.Parallel()
+
+	author, reviewer, superAdmin := uuid.New(), uuid.New(), uuid.New()
+	opts := question.Options{Reviewers: []uuid.UUID{author, reviewer, superAdmin}}
+	draft := entity.Question{ID: uuid.New(), Status: entity.QuestionStatusDraft, Version: 2}
+	written := entity.QuestionVersion{Question: draft, AuthorID: &author}
+
+	inStatus := func(status entity.QuestionStatus) entity.Question {
+		q := draft
+		q.Status, q.ReviewerID = status, &reviewer
+
+		return q
+	}
+
+	t.Run("submitting needs a reviewer", func(t *testing.T) {
+		t.Parallel()
+
+		uc, m := questionUseCaseWith(t, opts)
+		m.questions.EXPECT().GetByID(gomock.Any(), draft.ID).Return(draft, nil)
+
+		_, err := uc.Transition(context.Background(), author, draft.ID, entity.QuestionTransitionRequest{Status: entity.QuestionStatusInReview})
+		require.ErrorIs(t, err, question.ErrReviewerRequired)
+	})
+
+	t.Run("authors cannot review themselves", func(t *testing.T) {
+		t.Parallel()
+
+		uc, m := questionUseCaseWith(t, opts)
+		m.questions.EXPECT().GetByID(gomock.Any(), draft.ID).Return(draft, nil)
+		m.questions.EXPECT().GetVersion(gomock.Any(), draft.ID, 2).Return(written, nil)
+
+		_, err := uc.Transition(context.Background(), author, draft.ID, entity.QuestionTransitionRequest{
+			Status:     entity.QuestionStatusInReview,
+			ReviewerID: &author,
+		})
+		require.ErrorIs(t, err, question.ErrSelfReview)
+	})
+
+	t.Run("reviewers must be known admins", func(t *testing.T) {
+		t.Parallel()
+
+		uc, m := questionUseCaseWith(t, opts)
+		m.questions.EXPECT().GetByID(gomock.Any(), draft.ID).Return(draft, nil)
+
+		stranger := uuid.New()
+		_, err := uc.Transition(context.Background(), author, draft.ID, entity.QuestionTransitionRequest{
+			Status:     entity.QuestionStatusInReview,
+			ReviewerID: &stranger,
+		})
+		require.ErrorIs(t, err, question.ErrUnknownReviewer)
+	})
+
+	t.Run("submits with a reviewer", func(t *testing.T) {
+		t.Parallel()
+
+		uc, m := questionUseCaseWith(t, opts)
+		m.questions.EXPECT().GetByID(gomock.Any(), draft.ID).Return(draft, nil)
+		m.questions.EXPECT().GetVersion(gomock.Any(), draft.ID, 2).Return(written, nil)
+		m.questions.EXPECT().Transition(gomock.Any(), draft.ID, entity.QuestionStatusDraft, entity.QuestionStatusInReview, &reviewer).
+			Return(inStatus(entity.QuestionStatusInReview), nil)
+
+		submitted, err := uc.Transition(context.Background(), author, draft.ID, entity.QuestionTransitionRequest{
+			Status:     entity.QuestionStatusInReview,
+			ReviewerID: &reviewer,
+		})
+		require.NoError(t, err)
+		require.Equal(t, entity.QuestionStatusInReview, submitted.Status)
+	})
+
+	t.Run("only the reviewer approves and publishes", func(t *testing.T) {
+		t.Parallel()
+
+		uc, m := questionUseCaseWith(t, opts)
+		m.questions.EXPECT().GetByID(gomock.Any(), draft.ID).Return(inStatus(entity.QuestionStatusInReview), nil)
+
+		_, err := uc.Transition(context.Background(), superAdmin, draft.ID, entity.QuestionTransitionRequest{Status: entity.QuestionStatusApproved})
+		require.ErrorIs(t, err, question.ErrNotReviewer)
+
+		m.questions.EXPECT().GetByID(gomock.Any(), draft.ID).Return(inStatus(entity.QuestionStatusApproved), nil)
+
+		_, err = uc.Transition(context.Background(), author, draft.ID, entity.QuestionTransitionRequest{Status: entity.QuestionStatusPublished})
+		require.ErrorIs(t, err, question.ErrNotReviewer)
+
+		m.questions.EXPECT().GetByID(gomock.Any(), draft.ID).Return(inStatus(entity.QuestionStatusApproved), nil)
+		m.questions.EXPECT().Transition(gomock.Any(), draft.ID, entity.QuestionStatusApproved, entity.QuestionStatusPublished, &reviewer).
+			Return(inStatus(entity.QuestionStatusPublished), nil)
+
+		published, err := uc.Transition(context.Background(), reviewer, draft.ID, entity.QuestionTransitionRequest{Status: entity.QuestionStatusPublished})
+		require.NoError(t, err)
+		require.Equal(t, entity.QuestionStatusPublished, published.Status)
+	})
+
+	t.Run("a non-reviewer author cannot publish", func(t *testing.T) {
+		t.Parallel()
+
+		uc, m := questionUseCaseWith(t, question.Options{Reviewers: []uuid.UUID{reviewer}, SelfReview: true})
+		m.questions.EXPECT().GetByID(gomock.Any(), draft.ID).Return(draft, nil)
+
+		_, err := uc.Transition(context.Background(), author, draft.ID, entity.QuestionTransitionRequest{
+			Status:     entity.QuestionStatusInReview,
+			ReviewerID: &author,
+		})
+		require.ErrorIs(t, err, question.ErrUnknownReviewer)
+
+		m.questions.EXPECT().GetByID(gomock.Any(), draft.ID).Return(inStatus(entity.QuestionStatusApproved), nil)
+
+		_, err = uc.Transition(context.Background(), author, draft.ID, entity.QuestionTransitionRequest{Status: entity.QuestionStatusPublished})
+		require.ErrorIs(t, err, question.ErrNotReviewer)
+	})
+
+	t.Run("steps cannot be skipped", func(t *testing.T) {
+		t.Parallel()
+
+		uc, m := questionUseCaseWith(t, opts)
+		m.questions.EXPECT().GetByID(gomock.Any(), draft.ID).Return(inStatus(entity.QuestionStatusDraft), nil)
+
+		_, err := uc.Transition(context.Background(), reviewer, draft.ID, entity.QuestionTransitionRequest{Status: entity.QuestionStatusPublished})
+		require.ErrorIs(t, err, question.ErrInvalidTransition)
+	})
+
+	t.Run("editing published content sends it back to review", func(t *testing.T) {
+		t.Parallel()
+
+		uc, m := questionUseCaseWith(t, opts)
+
+		published := inStatus(entity.QuestionStatusPublished)
+		published.IsActive = true
+		published.QuestionText, published.OptionA, published.OptionB, published.OptionC, published.OptionD = "Q", "a", "b", "c", "d"
+		published.CorrectOption, published.CorrectOptions = 1, []int{1}
+
+		m.questions.EXPECT().GetByID(gomock.Any(), draft.ID).Return(published, nil)
+		m.questions.EXPECT().Update(gomock.Any(), gomock.Any(), nil).
+			DoAndReturn(func(_ context.Context, v entity.QuestionVersion, _ *int) (entity.Question, error) {
+				require.Equal(t, entity.QuestionStatusInReview, v.Question.Status)
+				require.Equal(t, &reviewer, v.Question.ReviewerID)
+
+				return v.Question, nil
+			})
+
+		text := "Q, reworded"
+		_, err := uc.AdminUpdate(context.Background(), author, draft.ID, entity.QuestionUpdateRequest{QuestionText: &text})
+		require.NoError(t, err)
+	})
+}
+
+// TestQuestionReviewSingleAdmin publishes a question written by the console
+// admin, under the identity its login token carries.
+func TestQuestionReviewSingleAdmin(t *testing.T) {
+	t.Parallel()
+
+	creds := auth.AdminCredentials{Username: "admin", Password: "changeme", UserID: uuid.New()}
+	adminJWT := jwt.NewService("adminsecret", "test", time.Hour)
+	login, err := auth.New(NewMockUserRepository(gomock.NewController(t)), nil, adminJWT, creds).
+		AdminLogin(context.Background(), entity.AdminLoginRequest{Username: "admin", Password: "changeme"})
+	require.NoError(t, err)
+
+	claims, err := adminJWT.Parse(login.AccessToken)
+	require.NoError(t, err)
+	adminID := uuid.MustParse(claims.UserID)
+
+	draft := entity.Question{ID: uuid.New(), Status: entity.QuestionStatusDraft, Version: 1}
+	written := entity.QuestionVersion{Question: draft, AuthorID: &adminID}
+	submit := entity.QuestionTransitionRequest{Status: entity.QuestionStatusInReview, ReviewerID: &adminID}
+
+	t.Run("without self-review the admin cannot review", func(t *testing.T) {
+		t.Parallel()
+
+		uc, m := questionUseCaseWith(t, question.Options{Reviewers: []uuid.UUID{creds.UserID}})
+		m.questions.EXPECT().GetByID(gomock.Any(), draft.ID).Return(draft, nil)
+		m.questions.EXPECT().GetVersion(gomock.Any(), draft.ID, 1).Return(written, nil)
+
+		_, err := uc.Transition(context.Background(), adminID, draft.ID, submit)
+		require.ErrorIs(t, err, question.ErrSelfReview)
+	})
+
+	t.Run("with self-review the admin publishes", func(t *testing.T) {
+		t.Parallel()
+
+		uc, m := questionUseCaseWith(t, question.Options{Reviewers: []uuid.UUID{creds.UserID}, SelfReview: true})
+
+		status := draft
+		m.questions.EXPECT().GetByID(gomock.Any(), draft.ID).DoAndReturn(
+			func(context.Context, uuid.UUID) (entity.Question, error) { return status, nil }).Times(3)
+		m.questions.EXPECT().Transition(gomock.Any(), draft.ID, gomock.Any(), gomock.Any(), &adminID).
+			DoAndReturn(func(_ context.Context, _ uuid.UUID, from, to entity.QuestionStatus, reviewerID *uuid.UUID) (entity.Question, error) {
+				require.Equal(t, status.Status, from)
+				status.Status, status.ReviewerID = to, reviewerID
+
+				return status, nil
+			}).Times(3)
+
+		for _, req := range []entity.QuestionTransitionRequest{
+			submit,
+			{Status: entity.QuestionStatusApproved},
+			{Status: entity.QuestionStatusPublished},
+		} {
+			_, err := uc.Transition(context.Background(), adminID, draft.ID, req)
+			require.NoError(t, err)
+		}
+		require.Equal(t, entity.QuestionStatusPublished, status.Status)
+	})
+}
+
+func TestQuestionReviewComments(t *testing.T) {
+	t.Parallel()
+
+	uc, m := questionUseCase(t)
+
+	reviewer := uuid.New()
+	q := entity.Question{ID: uuid.New(), Status: entity.QuestionStatusInReview, Version: 4}
+
+	_, err := uc.AddComment(context.Background(), reviewer, q.ID, entity.QuestionReviewCommentRequest{Field: "footnote", Body: "?"})
+	require.ErrorIs(t, err, question.ErrInvalidField)
+
+	m.questions.EXPECT().GetByID(gomock.Any(), q.ID).Return(q, nil)
+	m.reviews.EXPECT().CreateComment(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, c entity.QuestionReviewComment) (entity.QuestionReviewComment, error) {
+			require.Equal(t, 4, c.Version)
+			require.Equal(t, "optionB", c.Field)
+			require.Equal(t, reviewer, c.AuthorID)
+
+			return c, nil
+		})
+
+	_, err = uc.AddComment(context.Background(), reviewer, q.ID, entity.QuestionReviewCommentRequest{Field: "optionB", Body: "Two options are correct."})
+	require.NoError(t, err)
+}
@@ -0,0 +1,57 @@
+package revision
+
+import (
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+)
+
+// fixed walks a ladder of intervals: one step up per correct review, staying
+// on the last step once reached, and back to the first step after a miss.
+type fixed struct {
+	intervals []int
+}
+
+func newFixed(intervals []int) fixed {
+	if len(intervals) == 0 {
+		intervals = []int{1, 3, 7, 14, 30}
+	}
+
+	return fixed{intervals: intervals}
+}
+
+func (s fixed) Algorithm() entity.RevisionAlgorithm { return entity.RevisionAlgorithmFixed }
+
+func (s fixed) Init(now time.Time) entity.RevisionItem {
+	return entity.RevisionItem{
+		Algorithm:    entity.RevisionAlgorithmFixed,
+		IntervalDays: s.intervals[0],
+		NextReviewAt: now.AddDate(0, 0, s.intervals[0]),
+	}
+}
+
+func (s fixed) Review(item entity.RevisionItem, result entity.RevisionResult, now time.Time) entity.RevisionItem {
+	if item.Algorithm != entity.RevisionAlgorithmFixed {
+		item.IntervalIndex = s.stepFor(item.IntervalDays)
+	}
+
+	if result == entity.RevisionResultCorrect {
+		item.IntervalIndex = min(item.IntervalIndex+1, len(s.intervals)-1)
+	} else {
+		item.IntervalIndex = 0
+	}
+
+	return reviewed(item, entity.RevisionAlgorithmFixed, result, s.intervals[item.IntervalIndex], now)
+}
+
+// stepFor returns the highest step whose interval does not exceed days.
+func (s fixed) stepFor(days int) int {
+	step := 0
+	for i, interval := range s.intervals {
+		if interval <= days {
+			step = i
+		}
+	}
+
+	return step
+}
@@ -0,0 +1,119 @@
+package revision
+
+import (
+	"math"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+)
+
+// FSRS-4.5 forgetting curve constants: R(t, S) = (1 + _fsrsFactor*t/S)^_fsrsDecay,
+// chosen so that R(S, S) = 0.9.
+const (
+	_fsrsDecay  = -0.5
+	_fsrsFactor = 19.0 / 81.0
+)
+
+// FSRS ratings used for the binary review outcome.
+const (
+	_fsrsAgain = 1
+	_fsrsGood  = 3
+)
+
+// fsrsDefaultWeights are the published FSRS-4.5 default parameters.
+func fsrsDefaultWeights() []float64 {
+	return []float64{
+		0.4872, 1.4003, 3.7145, 13.8206, 5.1618, 1.2298, 0.8975, 0.031, 1.6474,
+		0.1367, 1.0461, 2.1072, 0.0793, 0.3246, 1.587, 0.2272, 2.8755,
+	}
+}
+
+// fsrs is the Free Spaced Repetition Scheduler: it tracks memory stability
+// (days until recall probability falls to 90%) and item difficulty (1–10),
+// and picks the interval at which recall is expected to drop to the
+// requested retention.
+type fsrs struct {
+	params entity.FSRSParams
+	w      []float64
+}
+
+func newFSRS(params entity.FSRSParams) fsrs {
+	if params.RequestRetention == 0 {
+		params.RequestRetention = 0.9
+	}
+	if params.MaximumIntervalDays == 0 {
+		params.MaximumIntervalDays = 36500
+	}
+
+	w := params.Weights
+	if len(w) != len(fsrsDefaultWeights()) {
+		w = fsrsDefaultWeights()
+	}
+
+	return fsrs{params: params, w: w}
+}
+
+func (s fsrs) Algorithm() entity.RevisionAlgorithm { return entity.RevisionAlgorithmFSRS }
+
+// Init treats the missed practice answer as the first review, rated Again.
+func (s fsrs) Init(now time.Time) entity.RevisionItem {
+	item := entity.RevisionItem{
+		Algorithm:      entity.RevisionAlgorithmFSRS,
+		Stability:      s.w[_fsrsAgain-1],
+		Difficulty:     s.initialDifficulty(_fsrsAgain),
+		LastReviewedAt: &now,
+	}
+	item.IntervalDays = s.interval(item.Stability)
+	item.NextReviewAt = now.AddDate(0, 0, item.IntervalDays)
+
+	return item
+}
+
+func (s fsrs) Review(item entity.RevisionItem, result entity.RevisionResult, now time.Time) entity.RevisionItem {
+	if item.Stability == 0 {
+		// Adopt items scheduled by another algorithm: their last interval was
+		// meant to keep recall around the target, which is what stability means.
+		item.Stability = float64(max(item.IntervalDays, 1))
+		item.Difficulty = s.initialDifficulty(_fsrsGood)
+	}
+
+	rating := _fsrsAgain
+	if result == entity.RevisionResultCorrect {
+		rating = _fsrsGood
+	}
+
+	retrievability := math.Pow(1+_fsrsFactor*elapsedDays(item, now)/item.Stability, _fsrsDecay)
+
+	if rating == _fsrsAgain {
+		item.Stability = min(item.Stability, s.w[11]*
+			math.Pow(item.Difficulty, -s.w[12])*
+			(math.Pow(item.Stability+1, s.w[13])-1)*
+			math.Exp(s.w[14]*(1-retrievability)))
+	} else {
+		item.Stability *= 1 + math.Exp(s.w[8])*
+			(11-item.Difficulty)*
+			math.Pow(item.Stability, -s.w[9])*
+			(math.Exp(s.w[10]*(1-retrievability))-1)
+	}
+
+	next := item.Difficulty - s.w[6]*float64(rating-3)
+	item.Difficulty = clampDifficulty(s.w[7]*s.initialDifficulty(_fsrsGood) + (1-s.w[7])*next)
+
+	return reviewed(item, entity.RevisionAlgorithmFSRS, result, s.interval(item.Stability), now)
+}
+
+func (s fsrs) initialDifficulty(rating int) float64 {
+	return clampDifficulty(s.w[4] - float64(rating-3)*s.w[5])
+}
+
+// interval is the number of days until recall is expected to fall to the
+// requested retention.
+func (s fsrs) interval(stability float64) int {
+	days := stability / _fsrsFactor * (math.Pow(s.params.RequestRetention, 1/_fsrsDecay) - 1)
+
+	return min(max(int(math.Round(days)), 1), s.params.MaximumIntervalDays)
+}
+
+func clampDifficulty(d float64) float64 {
+	return min(max(d, 1), 10)
+}
@@ -0,0 +1,66 @@
+package revision
+
+import (
+	"math"
+
+	"github.com/google/uuid"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/repo"
+)
+
+// Replay runs logged reviews through s from a fresh queue entry per item. It
+// cannot know what a review the user never took would have shown, so
+// retention is measured on the reviews that did happen: overall, and for
+// those the scheduler would have had due by then.
+func Replay(s Scheduler, history []repo.RevisionReviewLog) entity.RevisionReplayReport {
+	report := entity.RevisionReplayReport{Algorithm: s.Algorithm()}
+
+	var (
+		item                      entity.RevisionItem
+		itemID                    uuid.UUID
+		correct, dueCorrect       int
+		intervalSum, intervalRuns int
+	)
+
+	for _, review := range history {
+		if report.Items == 0 || review.ItemID != itemID {
+			itemID = review.ItemID
+			item = s.Init(review.QueuedAt)
+			report.Items++
+			intervalSum += item.IntervalDays
+			intervalRuns++
+		}
+
+		isCorrect := review.Result == entity.RevisionResultCorrect
+		report.Reviews++
+		if isCorrect {
+			correct++
+		}
+
+		if !review.ReviewedAt.Before(item.NextReviewAt) {
+			report.DueReviews++
+			if isCorrect {
+				dueCorrect++
+			}
+		}
+
+		item = s.Review(item, review.Result, review.ReviewedAt)
+		intervalSum += item.IntervalDays
+		intervalRuns++
+	}
+
+	report.ObservedRetention = ratio(correct, report.Reviews)
+	report.RetentionWhenDue = ratio(dueCorrect, report.DueReviews)
+	report.AverageIntervalDays = ratio(intervalSum, intervalRuns)
+
+	return report
+}
+
+func ratio(n, d int) float64 {
+	if d == 0 {
+		return 0
+	}
+
+	return math.Round(float64(n)/float64(d)*1000) / 1000
+}
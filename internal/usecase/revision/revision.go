@@ -2,7 +2,9 @@ package revision
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -10,22 +12,232 @@ import (
 	"github.com/evrone/go-clean-template/internal/repo"
 )
 
+var (
+	// ErrDisabled when revision is switched off in AI settings.
+	ErrDisabled = errors.New("revision is disabled")
+	// ErrNotFound when the revision item does not exist.
+	ErrNotFound = errors.New("revision item not found")
+	// ErrForbidden when the revision item belongs to another user.
+	ErrForbidden = errors.New("revision item belongs to another user")
+	// ErrDailyCapReached when the user has already reviewed their daily cap today.
+	ErrDailyCapReached = errors.New("daily revision cap reached")
+	// ErrUserNotFound when the user does not exist.
+	ErrUserNotFound = errors.New("user not found")
+)
+
+const (
+	_defaultForecastDays = 14
+	_maxForecastDays     = 90
+)
+
 // UseCase manages revision queue.
 type UseCase struct {
 	revisions repo.RevisionRepository
+	settings  repo.AISettingsRepository
 }
 
 // New constructs UseCase.
-func New(revisions repo.RevisionRepository) *UseCase {
-	return &UseCase{revisions: revisions}
+func New(revisions repo.RevisionRepository, settings repo.AISettingsRepository) *UseCase {
+	return &UseCase{revisions: revisions, settings: settings}
 }
 
-// GetQueue returns due items.
+// GetQueue returns due items, or none while revision is disabled. With a
+// daily cap, the user's own or the global one, only the oldest items still
+// allowed today are returned; the rest stay queued for the following days.
 func (uc *UseCase) GetQueue(ctx context.Context, userID uuid.UUID) ([]entity.RevisionItem, error) {
+	settings, err := uc.settings.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("revision - AISettings.Get: %w", err)
+	}
+
+	if !settings.RevisionEnabled {
+		return []entity.RevisionItem{}, nil
+	}
+
 	items, err := uc.revisions.ListDue(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("revision - ListDue: %w", err)
 	}
 
+	dailyCap, err := uc.dailyCap(ctx, settings, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewedToday, err := uc.reviewedToday(ctx, dailyCap, userID, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	if remaining := remainingToday(dailyCap, reviewedToday); remaining >= 0 && len(items) > remaining {
+		items = items[:remaining]
+	}
+
 	return items, nil
 }
+
+// Forecast returns the user's revision workload for each of the next days,
+// today included, after the daily cap defers overflow.
+func (uc *UseCase) Forecast(ctx context.Context, userID uuid.UUID, days int) ([]entity.RevisionForecastDay, error) {
+	if days <= 0 {
+		days = _defaultForecastDays
+	}
+	days = min(days, _maxForecastDays)
+
+	settings, err := uc.settings.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("revision - AISettings.Get: %w", err)
+	}
+
+	now := time.Now().UTC()
+
+	var schedule []time.Time
+	if settings.RevisionEnabled {
+		schedule, err = uc.revisions.ListSchedule(ctx, userID, dayStart(now).AddDate(0, 0, days))
+		if err != nil {
+			return nil, fmt.Errorf("revision - ListSchedule: %w", err)
+		}
+	}
+
+	dailyCap, err := uc.dailyCap(ctx, settings, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewedToday, err := uc.reviewedToday(ctx, dailyCap, userID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return Forecast(dailyCap, schedule, reviewedToday, days, now), nil
+}
+
+// GetDailyCap returns the user's daily cap next to the global one.
+func (uc *UseCase) GetDailyCap(ctx context.Context, userID uuid.UUID) (entity.RevisionDailyCap, error) {
+	settings, err := uc.settings.Get(ctx)
+	if err != nil {
+		return entity.RevisionDailyCap{}, fmt.Errorf("revision - AISettings.Get: %w", err)
+	}
+
+	own, err := uc.revisions.GetDailyCap(ctx, userID)
+	if err != nil {
+		return entity.RevisionDailyCap{}, fmt.Errorf("revision - GetDailyCap: %w", err)
+	}
+
+	return entity.RevisionDailyCap{
+		DailyCap:  own,
+		Default:   settings.RevisionDailyCap,
+		Effective: DailyCap(settings, own),
+	}, nil
+}
+
+// SetDailyCap sets the user's own daily cap; nil restores the global one.
+func (uc *UseCase) SetDailyCap(ctx context.Context, userID uuid.UUID, dailyCap *int) (entity.RevisionDailyCap, error) {
+	err := uc.revisions.SetDailyCap(ctx, userID, dailyCap)
+	if errors.Is(err, repo.ErrNotFound) {
+		return entity.RevisionDailyCap{}, ErrUserNotFound
+	}
+	if err != nil {
+		return entity.RevisionDailyCap{}, fmt.Errorf("revision - SetDailyCap: %w", err)
+	}
+
+	return uc.GetDailyCap(ctx, userID)
+}
+
+// Review records the outcome of reviewing an item and schedules its next
+// review. Once the user has reviewed their daily cap today further reviews
+// wait for the next UTC day.
+func (uc *UseCase) Review(ctx context.Context, userID, itemID uuid.UUID, result entity.RevisionResult) (entity.RevisionItem, error) {
+	settings, err := uc.settings.Get(ctx)
+	if err != nil {
+		return entity.RevisionItem{}, fmt.Errorf("revision - AISettings.Get: %w", err)
+	}
+
+	if !settings.RevisionEnabled {
+		return entity.RevisionItem{}, ErrDisabled
+	}
+
+	item, err := uc.revisions.Get(ctx, itemID)
+	if errors.Is(err, repo.ErrNotFound) {
+		return entity.RevisionItem{}, ErrNotFound
+	}
+	if err != nil {
+		return entity.RevisionItem{}, fmt.Errorf("revision - Get: %w", err)
+	}
+
+	if item.UserID != userID {
+		return entity.RevisionItem{}, ErrForbidden
+	}
+
+	dailyCap, err := uc.dailyCap(ctx, settings, userID)
+	if err != nil {
+		return entity.RevisionItem{}, err
+	}
+
+	now := time.Now().UTC()
+
+	reviewed, err := uc.revisions.Review(ctx, NewScheduler(settings).Review(item, result, now), dailyCap, dayStart(now))
+	switch {
+	case errors.Is(err, repo.ErrNotFound):
+		return entity.RevisionItem{}, ErrNotFound
+	case errors.Is(err, repo.ErrLimitReached):
+		return entity.RevisionItem{}, ErrDailyCapReached
+	case err != nil:
+		return entity.RevisionItem{}, fmt.Errorf("revision - Review: %w", err)
+	}
+
+	return reviewed, nil
+}
+
+// CompareSchedulers replays a user's review history through the configured
+// scheduler and through the candidate in req, so admins can compare them
+// before switching.
+func (uc *UseCase) CompareSchedulers(ctx context.Context, req entity.RevisionReplayRequest) (entity.RevisionReplayComparison, error) {
+	settings, err := uc.settings.Get(ctx)
+	if err != nil {
+		return entity.RevisionReplayComparison{}, fmt.Errorf("revision - AISettings.Get: %w", err)
+	}
+
+	history, err := uc.revisions.ListReviewHistory(ctx, req.UserID)
+	if err != nil {
+		return entity.RevisionReplayComparison{}, fmt.Errorf("revision - ListReviewHistory: %w", err)
+	}
+
+	candidate := settings
+	candidate.RevisionAlgorithm = req.Algorithm
+	if req.SM2 != nil {
+		candidate.SM2 = *req.SM2
+	}
+	if req.FSRS != nil {
+		candidate.FSRS = *req.FSRS
+	}
+
+	return entity.RevisionReplayComparison{
+		Current:   Replay(NewScheduler(settings), history),
+		Candidate: Replay(NewScheduler(candidate), history),
+	}, nil
+}
+
+// dailyCap is the cap applied to the user, 0 for unlimited.
+func (uc *UseCase) dailyCap(ctx context.Context, settings entity.AISettings, userID uuid.UUID) (int, error) {
+	own, err := uc.revisions.GetDailyCap(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("revision - GetDailyCap: %w", err)
+	}
+
+	return DailyCap(settings, own), nil
+}
+
+// reviewedToday counts today's reviews, which only matter under a daily cap.
+func (uc *UseCase) reviewedToday(ctx context.Context, dailyCap int, userID uuid.UUID, now time.Time) (int, error) {
+	if dailyCap <= 0 {
+		return 0, nil
+	}
+
+	count, err := uc.revisions.CountReviewsSince(ctx, userID, dayStart(now))
+	if err != nil {
+		return 0, fmt.Errorf("revision - CountReviewsSince: %w", err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,71 @@
+package revision
+
+import (
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+)
+
+// Scheduler decides when a revision item is due next. Implementations keep
+// their own state on the item and must accept items last scheduled by a
+// different algorithm, deriving their state from IntervalDays when missing.
+type Scheduler interface {
+	Algorithm() entity.RevisionAlgorithm
+	// Init returns the state of an item queued at now after a missed answer.
+	Init(now time.Time) entity.RevisionItem
+	// Review returns the state of item after a review at now.
+	Review(item entity.RevisionItem, result entity.RevisionResult, now time.Time) entity.RevisionItem
+}
+
+// NewScheduler returns the scheduler configured in settings, defaulting to
+// the fixed interval ladder.
+func NewScheduler(settings entity.AISettings) Scheduler {
+	switch settings.RevisionAlgorithm {
+	case entity.RevisionAlgorithmSM2:
+		return newSM2(settings.SM2)
+	case entity.RevisionAlgorithmFSRS:
+		return newFSRS(settings.FSRS)
+	default:
+		return newFixed(settings.RevisionIntervalsDays)
+	}
+}
+
+// FirstReview tells whether a freshly answered question enters the revision
+// queue and returns its initial state. Wrong answers always do; correct
+// answers only when flagged as a guess and IncludeGuessedCorrect is on.
+func FirstReview(settings entity.AISettings, correct, guessed bool, now time.Time) (entity.RevisionItem, bool) {
+	if !settings.RevisionEnabled {
+		return entity.RevisionItem{}, false
+	}
+
+	if correct && (!guessed || !settings.IncludeGuessedCorrect) {
+		return entity.RevisionItem{}, false
+	}
+
+	return NewScheduler(settings).Init(now), true
+}
+
+// reviewed stamps the bookkeeping every scheduler shares.
+func reviewed(item entity.RevisionItem, algorithm entity.RevisionAlgorithm, result entity.RevisionResult, intervalDays int, now time.Time) entity.RevisionItem {
+	item.Algorithm = algorithm
+	item.IntervalDays = intervalDays
+	item.NextReviewAt = now.AddDate(0, 0, intervalDays)
+	item.TimesReviewed++
+	item.LastResult = &result
+	item.LastReviewedAt = &now
+	if result == entity.RevisionResultIncorrect {
+		item.Lapses++
+	}
+
+	return item
+}
+
+// elapsedDays is the time since the item was last seen, in days.
+func elapsedDays(item entity.RevisionItem, now time.Time) float64 {
+	since := item.NextReviewAt.AddDate(0, 0, -item.IntervalDays)
+	if item.LastReviewedAt != nil {
+		since = *item.LastReviewedAt
+	}
+
+	return max(now.Sub(since).Hours()/24, 0)
+}
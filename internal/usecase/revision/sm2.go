@@ -0,0 +1,83 @@
+package revision
+
+import (
+	"math"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+)
+
+// SM-2 quality grades for the binary review outcome.
+const (
+	_sm2QualityCorrect   = 4
+	_sm2QualityIncorrect = 1
+)
+
+// sm2 is the SuperMemo-2 algorithm: intervals of 1 and 6 days, then the
+// previous interval times the ease factor, which drifts with answer quality.
+// IntervalIndex holds the count of consecutive correct reviews.
+type sm2 struct {
+	params entity.SM2Params
+}
+
+func newSM2(params entity.SM2Params) sm2 {
+	if params.InitialEase == 0 {
+		params.InitialEase = 2.5
+	}
+	if params.MinEase == 0 {
+		params.MinEase = 1.3
+	}
+	if params.FirstIntervalDays == 0 {
+		params.FirstIntervalDays = 1
+	}
+	if params.SecondIntervalDays == 0 {
+		params.SecondIntervalDays = 6
+	}
+
+	return sm2{params: params}
+}
+
+func (s sm2) Algorithm() entity.RevisionAlgorithm { return entity.RevisionAlgorithmSM2 }
+
+func (s sm2) Init(now time.Time) entity.RevisionItem {
+	return entity.RevisionItem{
+		Algorithm:    entity.RevisionAlgorithmSM2,
+		Ease:         s.params.InitialEase,
+		IntervalDays: s.params.FirstIntervalDays,
+		NextReviewAt: now.AddDate(0, 0, s.params.FirstIntervalDays),
+	}
+}
+
+func (s sm2) Review(item entity.RevisionItem, result entity.RevisionResult, now time.Time) entity.RevisionItem {
+	if item.Ease == 0 {
+		item.Ease = s.params.InitialEase
+	}
+	if item.Algorithm != entity.RevisionAlgorithmSM2 {
+		item.IntervalIndex = min(item.TimesReviewed, 2)
+	}
+
+	quality := _sm2QualityIncorrect
+	if result == entity.RevisionResultCorrect {
+		quality = _sm2QualityCorrect
+	}
+
+	miss := float64(5 - quality)
+	item.Ease = max(item.Ease+0.1-miss*(0.08+miss*0.02), s.params.MinEase)
+
+	interval := s.params.FirstIntervalDays
+	if result == entity.RevisionResultCorrect {
+		switch item.IntervalIndex {
+		case 0:
+			interval = s.params.FirstIntervalDays
+		case 1:
+			interval = s.params.SecondIntervalDays
+		default:
+			interval = int(math.Round(float64(max(item.IntervalDays, 1)) * item.Ease))
+		}
+		item.IntervalIndex++
+	} else {
+		item.IntervalIndex = 0
+	}
+
+	return reviewed(item, entity.RevisionAlgorithmSM2, result, interval, now)
+}
@@ -0,0 +1,126 @@
+package revision
+
+import (
+	"math"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+)
+
+const (
+	_day = 24 * time.Hour
+	// _smoothingSpread is the share of an interval an item may move to level the load.
+	_smoothingSpread = 0.15
+)
+
+// dayStart truncates t to the start of its UTC day.
+func dayStart(t time.Time) time.Time {
+	return t.UTC().Truncate(_day)
+}
+
+// dayOffset is the number of UTC days from now's day to t's, with overdue
+// times counting as today.
+func dayOffset(t, now time.Time) int {
+	return max(int(dayStart(t).Sub(dayStart(now))/_day), 0)
+}
+
+// DailyCap is the cap applied to a learner: their own when set, otherwise
+// the global one. 0 means unlimited.
+func DailyCap(settings entity.AISettings, own *int) int {
+	if own != nil {
+		return *own
+	}
+
+	return settings.RevisionDailyCap
+}
+
+// remainingToday is how many more items dailyCap lets through today, or -1
+// without a cap.
+func remainingToday(dailyCap, reviewedToday int) int {
+	if dailyCap <= 0 {
+		return -1
+	}
+
+	return max(dailyCap-reviewedToday, 0)
+}
+
+// Forecast spreads the schedule over days starting today. Items beyond
+// dailyCap are deferred to the next day ahead of that day's own items, so
+// due order is preserved.
+func Forecast(dailyCap int, schedule []time.Time, reviewedToday, days int, now time.Time) []entity.RevisionForecastDay {
+	scheduled := make([]int, days)
+	for _, due := range schedule {
+		if offset := dayOffset(due, now); offset < days {
+			scheduled[offset]++
+		}
+	}
+
+	forecast := make([]entity.RevisionForecastDay, days)
+	carry := 0
+	for d := range days {
+		available := carry + scheduled[d]
+
+		capacity := dailyCap
+		if d == 0 {
+			capacity = remainingToday(dailyCap, reviewedToday)
+		}
+
+		due := available
+		if dailyCap > 0 {
+			due = min(available, capacity)
+		}
+		carry = available - due
+
+		forecast[d] = entity.RevisionForecastDay{
+			Date:      dayStart(now).AddDate(0, 0, d).Format(time.DateOnly),
+			Scheduled: scheduled[d],
+			Due:       due,
+			Deferred:  carry,
+		}
+	}
+
+	return forecast
+}
+
+// SmoothingHorizon is how far ahead Balance needs the schedule for item.
+func SmoothingHorizon(item entity.RevisionItem, now time.Time) time.Time {
+	return dayStart(now).AddDate(0, 0, item.IntervalDays+smoothingSpread(item.IntervalDays)+1)
+}
+
+// Balance moves item's due date within a window around its interval to the
+// least loaded day of the schedule, preferring days closer to the interval
+// the scheduler picked.
+func Balance(item entity.RevisionItem, schedule []time.Time, now time.Time) entity.RevisionItem {
+	interval := item.IntervalDays
+	spread := smoothingSpread(interval)
+
+	load := make(map[int]int)
+	for _, due := range schedule {
+		load[dayOffset(due, now)]++
+	}
+
+	best := interval
+	for d := max(interval-spread, 1); d <= interval+spread; d++ {
+		closer := abs(d-interval) < abs(best-interval)
+		if load[d] < load[best] || (load[d] == load[best] && closer) {
+			best = d
+		}
+	}
+
+	item.IntervalDays = best
+	item.NextReviewAt = now.AddDate(0, 0, best)
+
+	return item
+}
+
+func smoothingSpread(intervalDays int) int {
+	return max(int(math.Round(float64(intervalDays)*_smoothingSpread)), 1)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
@@ -0,0 +1,282 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/repo"
+	"github.com/evrone/go-clean-template/internal/usecase/revision"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func revisionUseCase(t *testing.T) (*revision.UseCase, *MockRevisionRepository, *MockAISettingsRepository) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+
+	revisions := NewMockRevisionRepository(mockCtl)
+	settings := NewMockAISettingsRepository(mockCtl)
+
+	return revision.New(revisions, settings), revisions, settings
+}
+
+func TestFixedScheduler(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+	settings := entity.AISettings{RevisionEnabled: true, RevisionIntervalsDays: []int{1, 3, 7}}
+
+	tests := []struct {
+		name      string
+		index     int
+		result    entity.RevisionResult
+		wantIndex int
+		wantDays  int
+	}{
+		{"correct advances", 0, entity.RevisionResultCorrect, 1, 3},
+		{"correct stays on last step", 2, entity.RevisionResultCorrect, 2, 7},
+		{"incorrect restarts", 2, entity.RevisionResultIncorrect, 0, 1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			item := revision.NewScheduler(settings).Review(entity.RevisionItem{
+				Algorithm:     entity.RevisionAlgorithmFixed,
+				IntervalIndex: tc.index,
+				TimesReviewed: 4,
+			}, tc.result, now)
+
+			require.Equal(t, tc.wantIndex, item.IntervalIndex)
+			require.Equal(t, now.AddDate(0, 0, tc.wantDays), item.NextReviewAt)
+			require.Equal(t, 5, item.TimesReviewed)
+			require.Equal(t, tc.result, *item.LastResult)
+		})
+	}
+}
+
+func TestSM2Scheduler(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+	s := revision.NewScheduler(entity.AISettings{RevisionAlgorithm: entity.RevisionAlgorithmSM2})
+
+	item := s.Init(now)
+	require.InDelta(t, 2.5, item.Ease, 0.0001)
+	require.Equal(t, 1, item.IntervalDays)
+
+	var days []int
+	for range 3 {
+		now = item.NextReviewAt
+		item = s.Review(item, entity.RevisionResultCorrect, now)
+		days = append(days, item.IntervalDays)
+	}
+	require.Equal(t, []int{1, 6, 15}, days)
+
+	item = s.Review(item, entity.RevisionResultIncorrect, item.NextReviewAt)
+	require.Equal(t, 1, item.IntervalDays)
+	require.Equal(t, 0, item.IntervalIndex)
+	require.Equal(t, 1, item.Lapses)
+	require.InDelta(t, 1.96, item.Ease, 0.0001)
+}
+
+func TestFSRSScheduler(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+	s := revision.NewScheduler(entity.AISettings{RevisionAlgorithm: entity.RevisionAlgorithmFSRS})
+
+	item := s.Init(now)
+	require.Equal(t, 1, item.IntervalDays)
+
+	previous := item.IntervalDays
+	for range 3 {
+		item = s.Review(item, entity.RevisionResultCorrect, item.NextReviewAt)
+		require.Greater(t, item.IntervalDays, previous)
+		previous = item.IntervalDays
+	}
+
+	stability := item.Stability
+	item = s.Review(item, entity.RevisionResultIncorrect, item.NextReviewAt)
+	require.Less(t, item.Stability, stability)
+	require.Less(t, item.IntervalDays, previous)
+	require.Equal(t, 1, item.Lapses)
+
+	// Items scheduled by another algorithm are adopted from their last interval.
+	adopted := s.Review(entity.RevisionItem{
+		Algorithm:    entity.RevisionAlgorithmFixed,
+		IntervalDays: 7,
+		NextReviewAt: now,
+	}, entity.RevisionResultCorrect, now)
+	require.Equal(t, entity.RevisionAlgorithmFSRS, adopted.Algorithm)
+	require.Greater(t, adopted.IntervalDays, 7)
+}
+
+func TestReplayReport(t *testing.T) {
+	t.Parallel()
+
+	queued := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+	itemA, itemB := uuid.New(), uuid.New()
+	history := []repo.RevisionReviewLog{
+		{ItemID: itemA, QueuedAt: queued, Result: entity.RevisionResultCorrect, ReviewedAt: queued.AddDate(0, 0, 1)},
+		{ItemID: itemA, QueuedAt: queued, Result: entity.RevisionResultIncorrect, ReviewedAt: queued.AddDate(0, 0, 2)},
+		{ItemID: itemB, QueuedAt: queued, Result: entity.RevisionResultCorrect, ReviewedAt: queued.AddDate(0, 0, 3)},
+	}
+
+	report := revision.Replay(revision.NewScheduler(entity.AISettings{RevisionIntervalsDays: []int{1, 3}}), history)
+
+	require.Equal(t, entity.RevisionAlgorithmFixed, report.Algorithm)
+	require.Equal(t, 2, report.Items)
+	require.Equal(t, 3, report.Reviews)
+	require.InDelta(t, 0.667, report.ObservedRetention, 0.001)
+	// The second review of A came a day into a 3-day interval, so only two were due.
+	require.Equal(t, 2, report.DueReviews)
+	require.InDelta(t, 1.0, report.RetentionWhenDue, 0.001)
+}
+
+func TestReviewRevisionItem(t *testing.T) {
+	t.Parallel()
+
+	uc, revisions, settings := revisionUseCase(t)
+
+	userID := uuid.New()
+	item := entity.RevisionItem{ID: uuid.New(), UserID: userID}
+
+	settings.EXPECT().Get(gomock.Any()).Return(entity.AISettings{RevisionEnabled: true}, nil).Times(2)
+	revisions.EXPECT().Get(gomock.Any(), item.ID).Return(item, nil).Times(2)
+	revisions.EXPECT().GetDailyCap(gomock.Any(), userID).Return(nil, nil)
+	revisions.EXPECT().Review(gomock.Any(), gomock.Any(), 0, gomock.Any()).
+		DoAndReturn(func(_ context.Context, i entity.RevisionItem, _ int, _ time.Time) (entity.RevisionItem, error) {
+			require.Equal(t, 1, i.IntervalIndex)
+
+			return i, nil
+		})
+
+	reviewed, err := uc.Review(context.Background(), userID, item.ID, entity.RevisionResultCorrect)
+	require.NoError(t, err)
+	require.Equal(t, 1, reviewed.TimesReviewed)
+
+	_, err = uc.Review(context.Background(), uuid.New(), item.ID, entity.RevisionResultCorrect)
+	require.ErrorIs(t, err, revision.ErrForbidden)
+}
+
+func TestRevisionDisabled(t *testing.T) {
+	t.Parallel()
+
+	uc, _, settings := revisionUseCase(t)
+
+	settings.EXPECT().Get(gomock.Any()).Return(entity.AISettings{RevisionEnabled: false}, nil).Times(2)
+
+	items, err := uc.GetQueue(context.Background(), uuid.New())
+	require.NoError(t, err)
+	require.Empty(t, items)
+
+	_, err = uc.Review(context.Background(), uuid.New(), uuid.New(), entity.RevisionResultCorrect)
+	require.ErrorIs(t, err, revision.ErrDisabled)
+}
+
+func TestForecastDefersOverflow(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+	schedule := []time.Time{
+		now.AddDate(0, 0, -2),
+		now.AddDate(0, 0, -1),
+		now.Add(time.Hour),
+		now.AddDate(0, 0, 1),
+		now.AddDate(0, 0, 1),
+		now.AddDate(0, 0, 1),
+	}
+
+	forecast := revision.Forecast(2, schedule, 1, 4, now)
+
+	require.Equal(t, []entity.RevisionForecastDay{
+		{Date: "2025-12-01", Scheduled: 3, Due: 1, Deferred: 2},
+		{Date: "2025-12-02", Scheduled: 3, Due: 2, Deferred: 3},
+		{Date: "2025-12-03", Scheduled: 0, Due: 2, Deferred: 1},
+		{Date: "2025-12-04", Scheduled: 0, Due: 1, Deferred: 0},
+	}, forecast)
+}
+
+func TestGetQueueAppliesDailyCap(t *testing.T) {
+	t.Parallel()
+
+	uc, revisions, settings := revisionUseCase(t)
+
+	userID := uuid.New()
+	due := []entity.RevisionItem{{ID: uuid.New()}, {ID: uuid.New()}, {ID: uuid.New()}, {ID: uuid.New()}}
+
+	settings.EXPECT().Get(gomock.Any()).Return(entity.AISettings{RevisionEnabled: true, RevisionDailyCap: 3}, nil)
+	revisions.EXPECT().ListDue(gomock.Any(), userID).Return(due, nil)
+	revisions.EXPECT().GetDailyCap(gomock.Any(), userID).Return(nil, nil)
+	revisions.EXPECT().CountReviewsSince(gomock.Any(), userID, gomock.Any()).Return(1, nil)
+
+	items, err := uc.GetQueue(context.Background(), userID)
+
+	require.NoError(t, err)
+	require.Equal(t, due[:2], items)
+}
+
+func TestGetQueuePrefersUserDailyCap(t *testing.T) {
+	t.Parallel()
+
+	uc, revisions, settings := revisionUseCase(t)
+
+	userID := uuid.New()
+	due := []entity.RevisionItem{{ID: uuid.New()}, {ID: uuid.New()}, {ID: uuid.New()}, {ID: uuid.New()}}
+	own := 1
+
+	settings.EXPECT().Get(gomock.Any()).Return(entity.AISettings{RevisionEnabled: true, RevisionDailyCap: 3}, nil)
+	revisions.EXPECT().ListDue(gomock.Any(), userID).Return(due, nil)
+	revisions.EXPECT().GetDailyCap(gomock.Any(), userID).Return(&own, nil)
+	revisions.EXPECT().CountReviewsSince(gomock.Any(), userID, gomock.Any()).Return(0, nil)
+
+	items, err := uc.GetQueue(context.Background(), userID)
+
+	require.NoError(t, err)
+	require.Equal(t, due[:1], items)
+}
+
+func TestReviewEnforcesDailyCap(t *testing.T) {
+	t.Parallel()
+
+	uc, revisions, settings := revisionUseCase(t)
+
+	userID := uuid.New()
+	item := entity.RevisionItem{ID: uuid.New(), UserID: userID, Algorithm: entity.RevisionAlgorithmFixed}
+	own := 2
+
+	settings.EXPECT().Get(gomock.Any()).Return(entity.AISettings{RevisionEnabled: true}, nil)
+	revisions.EXPECT().Get(gomock.Any(), item.ID).Return(item, nil)
+	revisions.EXPECT().GetDailyCap(gomock.Any(), userID).Return(&own, nil)
+	revisions.EXPECT().Review(gomock.Any(), gomock.Any(), own, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ entity.RevisionItem, _ int, since time.Time) (entity.RevisionItem, error) {
+			require.True(t, since.Equal(since.Truncate(24*time.Hour)), "the cap counts from the start of the UTC day")
+
+			return entity.RevisionItem{}, repo.ErrLimitReached
+		})
+
+	_, err := uc.Review(context.Background(), userID, item.ID, entity.RevisionResultCorrect)
+	require.ErrorIs(t, err, revision.ErrDailyCapReached)
+}
+
+func TestBalanceLevelsLoad(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+	item := revision.NewScheduler(entity.AISettings{RevisionIntervalsDays: []int{10}}).Init(now)
+
+	// Days 9 and 10 are busy, day 11 is quiet.
+	schedule := []time.Time{now.AddDate(0, 0, 9), now.AddDate(0, 0, 10), now.AddDate(0, 0, 10)}
+	balanced := revision.Balance(item, schedule, now)
+	require.Equal(t, 11, balanced.IntervalDays)
+	require.Equal(t, now.AddDate(0, 0, 11), balanced.NextReviewAt)
+
+	// On an even load the scheduler's own pick wins.
+	require.Equal(t, 10, revision.Balance(item, nil, now).IntervalDays)
+}
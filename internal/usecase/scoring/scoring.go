@@ -0,0 +1,184 @@
+// Package scoring grades learner answers against a question's answer key.
+package scoring
+
+import (
+	"errors"
+	"math"
+	"slices"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+)
+
+// _numericEpsilon absorbs float noise so an answer exactly on the tolerance edge counts.
+const _numericEpsilon = 1e-9
+
+// ErrAnswerMismatch when the answer's shape does not fit the question type.
+var ErrAnswerMismatch = errors.New("answer does not match the question type")
+
+// AnswerKey returns the sorted set of correct options, falling back to the
+// single CorrectOption for rows that predate multi-select support.
+func AnswerKey(q entity.Question) []int {
+	if len(q.CorrectOptions) > 0 {
+		return Normalize(q.CorrectOptions)
+	}
+
+	if q.CorrectOption == 0 {
+		return nil
+	}
+
+	return []int{q.CorrectOption}
+}
+
+// Normalize returns options sorted and without duplicates.
+func Normalize(options []int) []int {
+	out := slices.Clone(options)
+	slices.Sort(out)
+
+	return slices.Compact(out)
+}
+
+// NormalizeAnswer sorts and deduplicates every option set in answer so that
+// equal answers compare equal.
+func NormalizeAnswer(answer entity.Answer) entity.Answer {
+	out := entity.Answer{NumericAnswer: answer.NumericAnswer}
+
+	if len(answer.SelectedOptions) > 0 {
+		out.SelectedOptions = Normalize(answer.SelectedOptions)
+	}
+
+	if len(answer.MatrixAnswer) > 0 {
+		out.MatrixAnswer = make([][]int, len(answer.MatrixAnswer))
+		for i, row := range answer.MatrixAnswer {
+			out.MatrixAnswer[i] = Normalize(row)
+		}
+	}
+
+	return out
+}
+
+// SameAnswer reports whether a and b are the same response.
+func SameAnswer(a, b entity.Answer) bool {
+	a, b = NormalizeAnswer(a), NormalizeAnswer(b)
+
+	if (a.NumericAnswer == nil) != (b.NumericAnswer == nil) {
+		return false
+	}
+	if a.NumericAnswer != nil && *a.NumericAnswer != *b.NumericAnswer {
+		return false
+	}
+
+	return slices.Equal(a.SelectedOptions, b.SelectedOptions) &&
+		slices.EqualFunc(a.MatrixAnswer, b.MatrixAnswer, slices.Equal[[]int])
+}
+
+// Validate checks that answer has the shape the question type expects.
+func Validate(q entity.Question, answer entity.Answer) error {
+	switch q.ChoiceType {
+	case entity.ChoiceTypeNumerical:
+		if answer.NumericAnswer == nil || len(answer.SelectedOptions) > 0 || len(answer.MatrixAnswer) > 0 {
+			return ErrAnswerMismatch
+		}
+	case entity.ChoiceTypeMatrixMatch:
+		if q.Matrix == nil || len(answer.MatrixAnswer) != len(q.Matrix.Rows) || answer.NumericAnswer != nil {
+			return ErrAnswerMismatch
+		}
+		for _, row := range answer.MatrixAnswer {
+			for _, column := range row {
+				if column < 1 || column > len(q.Matrix.Columns) {
+					return ErrAnswerMismatch
+				}
+			}
+		}
+	case entity.ChoiceTypeMulti:
+		if len(answer.SelectedOptions) == 0 || answer.NumericAnswer != nil || len(answer.MatrixAnswer) > 0 {
+			return ErrAnswerMismatch
+		}
+	default:
+		if len(Normalize(answer.SelectedOptions)) != 1 || answer.NumericAnswer != nil || len(answer.MatrixAnswer) > 0 {
+			return ErrAnswerMismatch
+		}
+	}
+
+	return nil
+}
+
+// Grade returns the credit in [0, 1] earned by answer and whether it is
+// fully correct. mode only matters for question types that allow partial
+// credit: multi-select earns the share of correct options picked unless a
+// wrong one is picked too, and matrix-match earns the share of rows matched
+// exactly.
+func Grade(q entity.Question, answer entity.Answer, mode entity.MultiSelectScoring) (float64, bool) {
+	switch q.ChoiceType {
+	case entity.ChoiceTypeNumerical:
+		return gradeNumerical(q, answer.NumericAnswer)
+	case entity.ChoiceTypeMatrixMatch:
+		return gradeMatrix(q, answer.MatrixAnswer, mode)
+	default:
+		return gradeOptions(q, answer.SelectedOptions, mode)
+	}
+}
+
+func gradeOptions(q entity.Question, selected []int, mode entity.MultiSelectScoring) (float64, bool) {
+	key := AnswerKey(q)
+	picked := Normalize(selected)
+
+	if len(picked) == 0 || len(key) == 0 {
+		return 0, false
+	}
+
+	if slices.Equal(key, picked) {
+		return 1, true
+	}
+
+	if q.ChoiceType != entity.ChoiceTypeMulti || mode != entity.MultiSelectPartial {
+		return 0, false
+	}
+
+	for _, option := range picked {
+		if !slices.Contains(key, option) {
+			return 0, false
+		}
+	}
+
+	return float64(len(picked)) / float64(len(key)), false
+}
+
+func gradeNumerical(q entity.Question, given *float64) (float64, bool) {
+	if given == nil || q.NumericAnswer == nil {
+		return 0, false
+	}
+
+	tolerance := 0.0
+	if q.NumericTolerance != nil {
+		tolerance = *q.NumericTolerance
+	}
+
+	if math.Abs(*given-*q.NumericAnswer) <= tolerance+_numericEpsilon {
+		return 1, true
+	}
+
+	return 0, false
+}
+
+func gradeMatrix(q entity.Question, given [][]int, mode entity.MultiSelectScoring) (float64, bool) {
+	if q.Matrix == nil || len(q.Matrix.Answer) == 0 || len(given) != len(q.Matrix.Answer) {
+		return 0, false
+	}
+
+	matched := 0
+	for i, row := range q.Matrix.Answer {
+		if slices.Equal(Normalize(row), Normalize(given[i])) {
+			matched++
+		}
+	}
+
+	if matched == len(q.Matrix.Answer) {
+		return 1, true
+	}
+
+	if mode != entity.MultiSelectPartial {
+		return 0, false
+	}
+
+	return float64(matched) / float64(len(q.Matrix.Answer)), false
+}
@@ -0,0 +1,84 @@
+package usecase_test
+
+import (
+	"testing"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase/scoring"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGrade(t *testing.T) {
+	t.Parallel()
+
+	single := entity.Question{ChoiceType: entity.ChoiceTypeSingle, CorrectOption: 2}
+	multi := entity.Question{ChoiceType: entity.ChoiceTypeMulti, CorrectOption: 1, CorrectOptions: []int{1, 2, 4}}
+
+	tests := []struct {
+		name     string
+		question entity.Question
+		selected []int
+		mode     entity.MultiSelectScoring
+		credit   float64
+		correct  bool
+	}{
+		{"single correct", single, []int{2}, entity.MultiSelectPartial, 1, true},
+		{"single wrong", single, []int{3}, entity.MultiSelectPartial, 0, false},
+		{"multi exact in any order", multi, []int{4, 2, 1}, entity.MultiSelectAllOrNothing, 1, true},
+		{"multi subset all-or-nothing", multi, []int{1, 2}, entity.MultiSelectAllOrNothing, 0, false},
+		{"multi subset partial", multi, []int{1, 2}, entity.MultiSelectPartial, 2.0 / 3, false},
+		{"multi with wrong option partial", multi, []int{1, 3}, entity.MultiSelectPartial, 0, false},
+		{"nothing selected", multi, nil, entity.MultiSelectPartial, 0, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			credit, correct := scoring.Grade(tc.question, entity.Answer{SelectedOptions: tc.selected}, tc.mode)
+			require.InDelta(t, tc.credit, credit, 0.0001)
+			require.Equal(t, tc.correct, correct)
+		})
+	}
+}
+
+func TestGradeNumerical(t *testing.T) {
+	t.Parallel()
+
+	answer, tolerance := 9.81, 0.05
+	question := entity.Question{ChoiceType: entity.ChoiceTypeNumerical, NumericAnswer: &answer, NumericTolerance: &tolerance}
+
+	for given, want := range map[float64]bool{9.81: true, 9.86: true, 9.76: true, 9.87: false, 0: false} {
+		_, correct := scoring.Grade(question, entity.Answer{NumericAnswer: &given}, entity.MultiSelectAllOrNothing)
+		require.Equal(t, want, correct, "given %v", given)
+	}
+}
+
+func TestGradeMatrixMatch(t *testing.T) {
+	t.Parallel()
+
+	question := entity.Question{
+		ChoiceType: entity.ChoiceTypeMatrixMatch,
+		Matrix: &entity.MatrixMatch{
+			Rows:    []string{"A", "B", "C", "D"},
+			Columns: []string{"P", "Q", "R", "S"},
+			Answer:  [][]int{{1}, {2, 3}, {4}, {1}},
+		},
+	}
+
+	credit, correct := scoring.Grade(question, entity.Answer{MatrixAnswer: [][]int{{1}, {3, 2}, {4}, {1}}}, entity.MultiSelectAllOrNothing)
+	require.True(t, correct)
+	require.InDelta(t, 1.0, credit, 0.0001)
+
+	partial := entity.Answer{MatrixAnswer: [][]int{{1}, {2}, {4}, {2}}}
+
+	credit, correct = scoring.Grade(question, partial, entity.MultiSelectAllOrNothing)
+	require.False(t, correct)
+	require.Zero(t, credit)
+
+	credit, _ = scoring.Grade(question, partial, entity.MultiSelectPartial)
+	require.InDelta(t, 0.5, credit, 0.0001)
+
+	require.ErrorIs(t, scoring.Validate(question, entity.Answer{MatrixAnswer: [][]int{{1}, {5}, {4}, {1}}}), scoring.ErrAnswerMismatch)
+	require.ErrorIs(t, scoring.Validate(question, entity.Answer{SelectedOptions: []int{1}}), scoring.ErrAnswerMismatch)
+}
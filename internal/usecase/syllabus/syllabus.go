@@ -0,0 +1,422 @@
+package syllabus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/repo"
+)
+
+var (
+	// ErrSubjectNotFound when the subject does not exist.
+	ErrSubjectNotFound = errors.New("subject not found")
+	// ErrTopicNotFound when the topic does not exist.
+	ErrTopicNotFound = errors.New("topic not found")
+	// ErrExamNotFound when the exam of a new subject does not exist.
+	ErrExamNotFound = errors.New("exam not found")
+	// ErrNameTaken when a sibling subject, or a topic of the same subject,
+	// already has the name.
+	ErrNameTaken = errors.New("name already taken")
+	// ErrInUse when deleting a subject that still has topics, or a topic that
+	// still has questions, podcasts or subtopics.
+	ErrInUse = errors.New("still in use")
+	// ErrInvalidParent when a topic would move under itself, one of its
+	// subtopics, or a topic of another subject.
+	ErrInvalidParent = errors.New("invalid parent topic")
+	// ErrInvalidOrder when a reorder does not list every sibling exactly once.
+	ErrInvalidOrder = errors.New("order must list every sibling exactly once")
+	// ErrInvalidMerge when a topic is merged into itself or its own subtree.
+	ErrInvalidMerge = errors.New("cannot merge a topic into itself or its subtopics")
+	// ErrBlankName when a name is empty after trimming.
+	ErrBlankName = errors.New("name is required")
+)
+
+// UseCase manages the subject and topic tree of each exam.
+type UseCase struct {
+	subjects repo.SubjectRepository
+	topics   repo.TopicRepository
+}
+
+// New constructs UseCase.
+func New(subjects repo.SubjectRepository, topics repo.TopicRepository) *UseCase {
+	return &UseCase{subjects: subjects, topics: topics}
+}
+
+// ListSubjects returns every subject, active or not, in display order.
+func (uc *UseCase) ListSubjects(ctx context.Context, exam *entity.ExamCategory) ([]entity.Subject, error) {
+	subjects, err := uc.subjects.ListByExam(ctx, exam)
+	if err != nil {
+		return nil, fmt.Errorf("syllabus - ListSubjects: %w", err)
+	}
+
+	return subjects, nil
+}
+
+// CreateSubject adds an active subject after the exam's existing ones.
+func (uc *UseCase) CreateSubject(ctx context.Context, exam entity.ExamCategory, name string) (entity.Subject, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return entity.Subject{}, ErrBlankName
+	}
+
+	created, err := uc.subjects.Create(ctx, entity.Subject{ID: uuid.New(), Exam: exam, Name: name, IsActive: true})
+	switch {
+	case errors.Is(err, repo.ErrNotFound):
+		return entity.Subject{}, ErrExamNotFound
+	case errors.Is(err, repo.ErrConflict):
+		return entity.Subject{}, ErrNameTaken
+	case err != nil:
+		return entity.Subject{}, fmt.Errorf("syllabus - CreateSubject: %w", err)
+	}
+
+	return created, nil
+}
+
+// UpdateSubject renames or (de)activates a subject. Learners never see an
+// inactive subject, and practice skips its questions.
+func (uc *UseCase) UpdateSubject(ctx context.Context, id uuid.UUID, req entity.SubjectUpdateRequest) (entity.Subject, error) {
+	subject, err := uc.subjects.Get(ctx, id)
+	if errors.Is(err, repo.ErrNotFound) {
+		return entity.Subject{}, ErrSubjectNotFound
+	}
+	if err != nil {
+		return entity.Subject{}, fmt.Errorf("syllabus - UpdateSubject - Get: %w", err)
+	}
+
+	if req.Name != nil {
+		subject.Name = strings.TrimSpace(*req.Name)
+		if subject.Name == "" {
+			return entity.Subject{}, ErrBlankName
+		}
+	}
+	if req.IsActive != nil {
+		subject.IsActive = *req.IsActive
+	}
+
+	updated, err := uc.subjects.Update(ctx, subject)
+	switch {
+	case errors.Is(err, repo.ErrNotFound):
+		return entity.Subject{}, ErrSubjectNotFound
+	case errors.Is(err, repo.ErrConflict):
+		return entity.Subject{}, ErrNameTaken
+	case err != nil:
+		return entity.Subject{}, fmt.Errorf("syllabus - UpdateSubject: %w", err)
+	}
+
+	return updated, nil
+}
+
+// ReorderSubjects sets the display order of an exam's subjects.
+func (uc *UseCase) ReorderSubjects(ctx context.Context, req entity.SubjectReorderRequest) ([]entity.Subject, error) {
+	subjects, err := uc.subjects.ListByExam(ctx, &req.Exam)
+	if err != nil {
+		return nil, fmt.Errorf("syllabus - ReorderSubjects - ListByExam: %w", err)
+	}
+
+	siblings := make([]uuid.UUID, 0, len(subjects))
+	for _, s := range subjects {
+		siblings = append(siblings, s.ID)
+	}
+	if !samePermutation(siblings, req.IDs) {
+		return nil, ErrInvalidOrder
+	}
+
+	if err := uc.subjects.Reorder(ctx, req.IDs); err != nil {
+		return nil, fmt.Errorf("syllabus - ReorderSubjects: %w", err)
+	}
+
+	return uc.ListSubjects(ctx, &req.Exam)
+}
+
+// DeleteSubject removes a subject whose topics have all been deleted or
+// merged elsewhere.
+func (uc *UseCase) DeleteSubject(ctx context.Context, id uuid.UUID) error {
+	err := uc.subjects.Delete(ctx, id)
+	switch {
+	case errors.Is(err, repo.ErrNotFound):
+		return ErrSubjectNotFound
+	case errors.Is(err, repo.ErrConflict):
+		return ErrInUse
+	case err != nil:
+		return fmt.Errorf("syllabus - DeleteSubject: %w", err)
+	}
+
+	return nil
+}
+
+// ListTopics returns every topic of a subject, active or not, in display
+// order. Subtopics carry their parent's ID.
+func (uc *UseCase) ListTopics(ctx context.Context, subjectID uuid.UUID) ([]entity.Topic, error) {
+	topics, err := uc.topics.ListBySubject(ctx, subjectID)
+	if err != nil {
+		return nil, fmt.Errorf("syllabus - ListTopics: %w", err)
+	}
+
+	return topics, nil
+}
+
+// CreateTopic adds an active topic, or a subtopic when parentID is set, after
+// its existing siblings.
+func (uc *UseCase) CreateTopic(ctx context.Context, subjectID uuid.UUID, parentID *uuid.UUID, name string) (entity.Topic, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return entity.Topic{}, ErrBlankName
+	}
+
+	if _, err := uc.subjects.Get(ctx, subjectID); err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return entity.Topic{}, ErrSubjectNotFound
+		}
+
+		return entity.Topic{}, fmt.Errorf("syllabus - CreateTopic - Subjects.Get: %w", err)
+	}
+
+	if parentID != nil {
+		parent, err := uc.topics.Get(ctx, *parentID)
+		if errors.Is(err, repo.ErrNotFound) || (err == nil && parent.SubjectID != subjectID) {
+			return entity.Topic{}, ErrInvalidParent
+		}
+		if err != nil {
+			return entity.Topic{}, fmt.Errorf("syllabus - CreateTopic - Topics.Get: %w", err)
+		}
+	}
+
+	topic := entity.Topic{ID: uuid.New(), SubjectID: subjectID, ParentID: parentID, Name: name, IsActive: true}
+
+	created, err := uc.topics.Create(ctx, topic)
+	if errors.Is(err, repo.ErrConflict) {
+		return entity.Topic{}, ErrNameTaken
+	}
+	if err != nil {
+		return entity.Topic{}, fmt.Errorf("syllabus - CreateTopic: %w", err)
+	}
+
+	return created, nil
+}
+
+// UpdateTopic renames, moves or (de)activates a topic. A move keeps the topic
+// in its subject; use MergeTopics to carry content across subjects.
+func (uc *UseCase) UpdateTopic(ctx context.Context, id uuid.UUID, req entity.TopicUpdateRequest) (entity.Topic, error) {
+	topic, err := uc.topics.Get(ctx, id)
+	if errors.Is(err, repo.ErrNotFound) {
+		return entity.Topic{}, ErrTopicNotFound
+	}
+	if err != nil {
+		return entity.Topic{}, fmt.Errorf("syllabus - UpdateTopic - Get: %w", err)
+	}
+
+	if req.Name != nil {
+		topic.Name = strings.TrimSpace(*req.Name)
+		if topic.Name == "" {
+			return entity.Topic{}, ErrBlankName
+		}
+	}
+	if req.IsActive != nil {
+		topic.IsActive = *req.IsActive
+	}
+
+	switch {
+	case req.MakeRoot:
+		topic.ParentID = nil
+	case req.ParentID != nil:
+		topics, err := uc.topics.ListBySubject(ctx, topic.SubjectID)
+		if err != nil {
+			return entity.Topic{}, fmt.Errorf("syllabus - UpdateTopic - ListBySubject: %w", err)
+		}
+
+		if !contains(topics, *req.ParentID) || inSubtree(topics, id, *req.ParentID) {
+			return entity.Topic{}, ErrInvalidParent
+		}
+
+		topic.ParentID = req.ParentID
+	}
+
+	updated, err := uc.topics.Update(ctx, topic)
+	switch {
+	case errors.Is(err, repo.ErrNotFound):
+		return entity.Topic{}, ErrTopicNotFound
+	case errors.Is(err, repo.ErrConflict):
+		return entity.Topic{}, ErrNameTaken
+	case err != nil:
+		return entity.Topic{}, fmt.Errorf("syllabus - UpdateTopic: %w", err)
+	}
+
+	return updated, nil
+}
+
+// ReorderTopics sets the display order of the children of one parent, or of
+// the top-level topics of a subject.
+func (uc *UseCase) ReorderTopics(ctx context.Context, req entity.TopicReorderRequest) ([]entity.Topic, error) {
+	topics, err := uc.topics.ListBySubject(ctx, req.SubjectID)
+	if err != nil {
+		return nil, fmt.Errorf("syllabus - ReorderTopics - ListBySubject: %w", err)
+	}
+
+	var siblings []uuid.UUID
+	for _, t := range topics {
+		if sameParent(t.ParentID, req.ParentID) {
+			siblings = append(siblings, t.ID)
+		}
+	}
+	if !samePermutation(siblings, req.IDs) {
+		return nil, ErrInvalidOrder
+	}
+
+	if err := uc.topics.Reorder(ctx, req.IDs); err != nil {
+		return nil, fmt.Errorf("syllabus - ReorderTopics: %w", err)
+	}
+
+	return uc.ListTopics(ctx, req.SubjectID)
+}
+
+// DeleteTopic removes an unused leaf topic. Topics with content are merged
+// into another topic instead.
+func (uc *UseCase) DeleteTopic(ctx context.Context, id uuid.UUID) error {
+	err := uc.topics.Delete(ctx, id)
+	switch {
+	case errors.Is(err, repo.ErrNotFound):
+		return ErrTopicNotFound
+	case errors.Is(err, repo.ErrConflict):
+		return ErrInUse
+	case err != nil:
+		return fmt.Errorf("syllabus - DeleteTopic: %w", err)
+	}
+
+	return nil
+}
+
+// MergeTopics moves every question, podcast and subtopic of sourceID into the
+// target topic and deletes the source, atomically. The target may sit in
+// another subject of the same exam.
+func (uc *UseCase) MergeTopics(ctx context.Context, sourceID uuid.UUID, req entity.TopicMergeRequest) (entity.TopicMergeResult, error) {
+	if sourceID == req.TargetID {
+		return entity.TopicMergeResult{}, ErrInvalidMerge
+	}
+
+	source, err := uc.topics.Get(ctx, sourceID)
+	if errors.Is(err, repo.ErrNotFound) {
+		return entity.TopicMergeResult{}, ErrTopicNotFound
+	}
+	if err != nil {
+		return entity.TopicMergeResult{}, fmt.Errorf("syllabus - MergeTopics - Get: %w", err)
+	}
+
+	target, err := uc.topics.Get(ctx, req.TargetID)
+	if errors.Is(err, repo.ErrNotFound) {
+		return entity.TopicMergeResult{}, ErrTopicNotFound
+	}
+	if err != nil {
+		return entity.TopicMergeResult{}, fmt.Errorf("syllabus - MergeTopics - Get: %w", err)
+	}
+
+	if source.SubjectID == target.SubjectID {
+		topics, err := uc.topics.ListBySubject(ctx, source.SubjectID)
+		if err != nil {
+			return entity.TopicMergeResult{}, fmt.Errorf("syllabus - MergeTopics - ListBySubject: %w", err)
+		}
+		if inSubtree(topics, source.ID, target.ID) {
+			return entity.TopicMergeResult{}, ErrInvalidMerge
+		}
+	} else if err := uc.sameExam(ctx, source.SubjectID, target.SubjectID); err != nil {
+		return entity.TopicMergeResult{}, err
+	}
+
+	result, err := uc.topics.Merge(ctx, source.ID, target.ID)
+	switch {
+	case errors.Is(err, repo.ErrNotFound):
+		return entity.TopicMergeResult{}, ErrTopicNotFound
+	case errors.Is(err, repo.ErrConflict):
+		return entity.TopicMergeResult{}, ErrNameTaken
+	case err != nil:
+		return entity.TopicMergeResult{}, fmt.Errorf("syllabus - MergeTopics: %w", err)
+	}
+
+	return result, nil
+}
+
+// sameExam rejects merges that would move questions to another exam.
+func (uc *UseCase) sameExam(ctx context.Context, a, b uuid.UUID) error {
+	first, err := uc.subjects.Get(ctx, a)
+	if err != nil {
+		return fmt.Errorf("syllabus - MergeTopics - Subjects.Get: %w", err)
+	}
+
+	second, err := uc.subjects.Get(ctx, b)
+	if err != nil {
+		return fmt.Errorf("syllabus - MergeTopics - Subjects.Get: %w", err)
+	}
+
+	if first.Exam != second.Exam {
+		return ErrInvalidMerge
+	}
+
+	return nil
+}
+
+// inSubtree reports whether id is root or one of its descendants.
+func inSubtree(topics []entity.Topic, root, id uuid.UUID) bool {
+	parents := make(map[uuid.UUID]*uuid.UUID, len(topics))
+	for _, t := range topics {
+		parents[t.ID] = t.ParentID
+	}
+
+	// The walk is bounded by the topic count in case the stored tree is
+	// already cyclic.
+	for range len(topics) + 1 {
+		if id == root {
+			return true
+		}
+
+		parent, ok := parents[id]
+		if !ok || parent == nil {
+			return false
+		}
+		id = *parent
+	}
+
+	return false
+}
+
+func contains(topics []entity.Topic, id uuid.UUID) bool {
+	for _, t := range topics {
+		if t.ID == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+func sameParent(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
+
+// samePermutation reports whether ids lists every element of want exactly once.
+func samePermutation(want, ids []uuid.UUID) bool {
+	if len(want) != len(ids) {
+		return false
+	}
+
+	seen := make(map[uuid.UUID]bool, len(want))
+	for _, id := range want {
+		seen[id] = false
+	}
+	for _, id := range ids {
+		done, ok := seen[id]
+		if !ok || done {
+			return false
+		}
+		seen[id] = true
+	}
+
+	return true
+}
@@ -0,0 +1,258 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/repo"
+	"github.com/evrone/go-clean-template/internal/usecase/syllabus"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type syllabusMocks struct {
+	subjects *MockSubjectRepository
+	topics   *MockTopicRepository
+}
+
+func syllabusUseCase(t *testing.T) (*syllabus.UseCase, syllabusMocks) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+
+	m := syllabusMocks{
+		subjects: NewMockSubjectRepository(mockCtl),
+		topics:   NewMockTopicRepository(mockCtl),
+	}
+
+	return syllabus.New(m.subjects, m.topics), m
+}
+
+// topicTree returns a subject with cardio > heart failure > acute heart
+// failure, and a separate root topic renal.
+func topicTree() (uuid.UUID, []entity.Topic) {
+	subjectID := uuid.New()
+	cardio := entity.Topic{ID: uuid.New(), SubjectID: subjectID, Name: "Cardio", IsActive: true}
+	failure := entity.Topic{ID: uuid.New(), SubjectID: subjectID, ParentID: &cardio.ID, Name: "Heart failure", IsActive: true}
+	acute := entity.Topic{ID: uuid.New(), SubjectID: subjectID, ParentID: &failure.ID, Name: "Acute heart failure", IsActive: true}
+	renal := entity.Topic{ID: uuid.New(), SubjectID: subjectID, Name: "Renal", IsActive: true, SortOrder: 1}
+
+	return subjectID, []entity.Topic{cardio, failure, acute, renal}
+}
+
+func TestCreateSubtopic(t *testing.T) {
+	t.Parallel()
+
+	subjectID, topics := topicTree()
+	cardio := topics[0]
+
+	t.Run("under a topic of the subject", func(t *testing.T) {
+		t.Parallel()
+
+		uc, m := syllabusUseCase(t)
+		m.subjects.EXPECT().Get(gomock.Any(), subjectID).Return(entity.Subject{ID: subjectID}, nil)
+		m.topics.EXPECT().Get(gomock.Any(), cardio.ID).Return(cardio, nil)
+		m.topics.EXPECT().Create(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, t entity.Topic) (entity.Topic, error) { return t, nil })
+
+		created, err := uc.CreateTopic(context.Background(), subjectID, &cardio.ID, "  Arrhythmia ")
+		require.NoError(t, err)
+		require.Equal(t, "Arrhythmia", created.Name)
+		require.Equal(t, &cardio.ID, created.ParentID)
+		require.True(t, created.IsActive)
+	})
+
+	t.Run("not under another subject's topic", func(t *testing.T) {
+		t.Parallel()
+
+		uc, m := syllabusUseCase(t)
+		other := uuid.New()
+		m.subjects.EXPECT().Get(gomock.Any(), other).Return(entity.Subject{ID: other}, nil)
+		m.topics.EXPECT().Get(gomock.Any(), cardio.ID).Return(cardio, nil)
+
+		_, err := uc.CreateTopic(context.Background(), other, &cardio.ID, "Arrhythmia")
+		require.ErrorIs(t, err, syllabus.ErrInvalidParent)
+	})
+
+	t.Run("names are unique in the subject", func(t *testing.T) {
+		t.Parallel()
+
+		uc, m := syllabusUseCase(t)
+		m.subjects.EXPECT().Get(gomock.Any(), subjectID).Return(entity.Subject{ID: subjectID}, nil)
+		m.topics.EXPECT().Create(gomock.Any(), gomock.Any()).Return(entity.Topic{}, repo.ErrConflict)
+
+		_, err := uc.CreateTopic(context.Background(), subjectID, nil, "Renal")
+		require.ErrorIs(t, err, syllabus.ErrNameTaken)
+	})
+}
+
+func TestMoveTopic(t *testing.T) {
+	t.Parallel()
+
+	subjectID, topics := topicTree()
+	cardio, failure, acute, renal := topics[0], topics[1], topics[2], topics[3]
+
+	t.Run("under its own subtopic", func(t *testing.T) {
+		t.Parallel()
+
+		uc, m := syllabusUseCase(t)
+		m.topics.EXPECT().Get(gomock.Any(), cardio.ID).Return(cardio, nil)
+		m.topics.EXPECT().ListBySubject(gomock.Any(), subjectID).Return(topics, nil)
+
+		_, err := uc.UpdateTopic(context.Background(), cardio.ID, entity.TopicUpdateRequest{ParentID: &acute.ID})
+		require.ErrorIs(t, err, syllabus.ErrInvalidParent)
+	})
+
+	t.Run("under a sibling", func(t *testing.T) {
+		t.Parallel()
+
+		uc, m := syllabusUseCase(t)
+		m.topics.EXPECT().Get(gomock.Any(), failure.ID).Return(failure, nil)
+		m.topics.EXPECT().ListBySubject(gomock.Any(), subjectID).Return(topics, nil)
+		m.topics.EXPECT().Update(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, t entity.Topic) (entity.Topic, error) { return t, nil })
+
+		moved, err := uc.UpdateTopic(context.Background(), failure.ID, entity.TopicUpdateRequest{ParentID: &renal.ID})
+		require.NoError(t, err)
+		require.Equal(t, &renal.ID, moved.ParentID)
+	})
+
+	t.Run("to the top level", func(t *testing.T) {
+		t.Parallel()
+
+		uc, m := syllabusUseCase(t)
+		m.topics.EXPECT().Get(gomock.Any(), acute.ID).Return(acute, nil)
+		m.topics.EXPECT().Update(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, t entity.Topic) (entity.Topic, error) { return t, nil })
+
+		moved, err := uc.UpdateTopic(context.Background(), acute.ID, entity.TopicUpdateRequest{MakeRoot: true})
+		require.NoError(t, err)
+		require.Nil(t, moved.ParentID)
+	})
+}
+
+func TestReorderTopics(t *testing.T) {
+	t.Parallel()
+
+	subjectID, topics := topicTree()
+	cardio, failure, renal := topics[0], topics[1], topics[3]
+
+	t.Run("top-level siblings", func(t *testing.T) {
+		t.Parallel()
+
+		uc, m := syllabusUseCase(t)
+		m.topics.EXPECT().ListBySubject(gomock.Any(), subjectID).Return(topics, nil).Times(2)
+		m.topics.EXPECT().Reorder(gomock.Any(), []uuid.UUID{renal.ID, cardio.ID}).Return(nil)
+
+		_, err := uc.ReorderTopics(context.Background(), entity.TopicReorderRequest{
+			SubjectID: subjectID,
+			IDs:       []uuid.UUID{renal.ID, cardio.ID},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("every sibling exactly once", func(t *testing.T) {
+		t.Parallel()
+
+		for _, ids := range [][]uuid.UUID{
+			{renal.ID},
+			{renal.ID, renal.ID},
+			{renal.ID, failure.ID},
+		} {
+			uc, m := syllabusUseCase(t)
+			m.topics.EXPECT().ListBySubject(gomock.Any(), subjectID).Return(topics, nil)
+
+			_, err := uc.ReorderTopics(context.Background(), entity.TopicReorderRequest{SubjectID: subjectID, IDs: ids})
+			require.ErrorIs(t, err, syllabus.ErrInvalidOrder)
+		}
+	})
+}
+
+func TestDeleteTopicInUse(t *testing.T) {
+	t.Parallel()
+
+	uc, m := syllabusUseCase(t)
+
+	id := uuid.New()
+	m.topics.EXPECT().Delete(gomock.Any(), id).Return(repo.ErrConflict)
+
+	require.ErrorIs(t, uc.DeleteTopic(context.Background(), id), syllabus.ErrInUse)
+}
+
+func TestMergeTopics(t *testing.T) {
+	t.Parallel()
+
+	subjectID, topics := topicTree()
+	cardio, failure, acute, renal := topics[0], topics[1], topics[2], topics[3]
+
+	t.Run("into a topic of the same subject", func(t *testing.T) {
+		t.Parallel()
+
+		uc, m := syllabusUseCase(t)
+		m.topics.EXPECT().Get(gomock.Any(), failure.ID).Return(failure, nil)
+		m.topics.EXPECT().Get(gomock.Any(), renal.ID).Return(renal, nil)
+		m.topics.EXPECT().ListBySubject(gomock.Any(), subjectID).Return(topics, nil)
+		m.topics.EXPECT().Merge(gomock.Any(), failure.ID, renal.ID).
+			Return(entity.TopicMergeResult{Target: renal, Questions: 12, Subtopics: 1}, nil)
+
+		result, err := uc.MergeTopics(context.Background(), failure.ID, entity.TopicMergeRequest{TargetID: renal.ID})
+		require.NoError(t, err)
+		require.Equal(t, 12, result.Questions)
+		require.Equal(t, 1, result.Subtopics)
+	})
+
+	t.Run("not into its own subtopic", func(t *testing.T) {
+		t.Parallel()
+
+		uc, m := syllabusUseCase(t)
+		m.topics.EXPECT().Get(gomock.Any(), cardio.ID).Return(cardio, nil)
+		m.topics.EXPECT().Get(gomock.Any(), acute.ID).Return(acute, nil)
+		m.topics.EXPECT().ListBySubject(gomock.Any(), subjectID).Return(topics, nil)
+
+		_, err := uc.MergeTopics(context.Background(), cardio.ID, entity.TopicMergeRequest{TargetID: acute.ID})
+		require.ErrorIs(t, err, syllabus.ErrInvalidMerge)
+	})
+
+	t.Run("not into itself", func(t *testing.T) {
+		t.Parallel()
+
+		uc, _ := syllabusUseCase(t)
+
+		_, err := uc.MergeTopics(context.Background(), cardio.ID, entity.TopicMergeRequest{TargetID: cardio.ID})
+		require.ErrorIs(t, err, syllabus.ErrInvalidMerge)
+	})
+
+	t.Run("not across exams", func(t *testing.T) {
+		t.Parallel()
+
+		uc, m := syllabusUseCase(t)
+		otherSubject := uuid.New()
+		other := entity.Topic{ID: uuid.New(), SubjectID: otherSubject, Name: "Cardiology"}
+
+		m.topics.EXPECT().Get(gomock.Any(), cardio.ID).Return(cardio, nil)
+		m.topics.EXPECT().Get(gomock.Any(), other.ID).Return(other, nil)
+		m.subjects.EXPECT().Get(gomock.Any(), subjectID).Return(entity.Subject{ID: subjectID, Exam: entity.ExamCategoryNEETUG}, nil)
+		m.subjects.EXPECT().Get(gomock.Any(), otherSubject).Return(entity.Subject{ID: otherSubject, Exam: entity.ExamCategoryNEETPG}, nil)
+
+		_, err := uc.MergeTopics(context.Background(), cardio.ID, entity.TopicMergeRequest{TargetID: other.ID})
+		require.ErrorIs(t, err, syllabus.ErrInvalidMerge)
+	})
+
+	t.Run("moved subtopic names must be free in the target subject", func(t *testing.T) {
+		t.Parallel()
+
+		uc, m := syllabusUseCase(t)
+		otherSubject := uuid.New()
+		other := entity.Topic{ID: uuid.New(), SubjectID: otherSubject, Name: "Cardiology"}
+
+		m.topics.EXPECT().Get(gomock.Any(), cardio.ID).Return(cardio, nil)
+		m.topics.EXPECT().Get(gomock.Any(), other.ID).Return(other, nil)
+		m.subjects.EXPECT().Get(gomock.Any(), gomock.Any()).Return(entity.Subject{Exam: entity.ExamCategoryNEETUG}, nil).Times(2)
+		m.topics.EXPECT().Merge(gomock.Any(), cardio.ID, other.ID).Return(entity.TopicMergeResult{}, repo.ErrConflict)
+
+		_, err := uc.MergeTopics(context.Background(), cardio.ID, entity.TopicMergeRequest{TargetID: other.ID})
+		require.ErrorIs(t, err, syllabus.ErrNameTaken)
+	})
+}
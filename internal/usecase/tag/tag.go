@@ -0,0 +1,189 @@
+package tag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/repo"
+)
+
+const (
+	_defaultPageSize = 50
+	_maxPageSize     = 200
+)
+
+var (
+	// ErrNotFound when the tag does not exist.
+	ErrNotFound = errors.New("tag not found")
+	// ErrQuestionNotFound when the tagged question does not exist.
+	ErrQuestionNotFound = errors.New("question not found")
+	// ErrNameTaken when another tag has the name, in any case.
+	ErrNameTaken = errors.New("a tag with this name already exists")
+	// ErrInvalidTag when a name is blank or a pyq tag has no year.
+	ErrInvalidTag = errors.New("invalid tag")
+)
+
+// UseCase manages the tag vocabulary and the tags of questions.
+type UseCase struct {
+	repo      repo.TagRepository
+	questions repo.QuestionRepository
+}
+
+// New constructs UseCase.
+func New(repo repo.TagRepository, questions repo.QuestionRepository) *UseCase {
+	return &UseCase{repo: repo, questions: questions}
+}
+
+// List pages through the tags by kind, newest year first, then name.
+func (uc *UseCase) List(ctx context.Context, filter repo.TagFilter) (entity.TagList, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 || filter.PageSize > _maxPageSize {
+		filter.PageSize = _defaultPageSize
+	}
+	filter.Search = strings.TrimSpace(filter.Search)
+
+	tags, total, err := uc.repo.List(ctx, filter)
+	if err != nil {
+		return entity.TagList{}, fmt.Errorf("tag - List: %w", err)
+	}
+
+	return entity.TagList{
+		Items: tags,
+		Meta:  entity.AdminUsersMeta{Page: filter.Page, PageSize: filter.PageSize, Total: total},
+	}, nil
+}
+
+// Create adds a tag to the vocabulary.
+func (uc *UseCase) Create(ctx context.Context, req entity.TagCreateRequest) (entity.Tag, error) {
+	tag := entity.Tag{Name: Normalize(req.Name), Kind: req.Kind, Year: req.Year}
+	if err := validate(tag); err != nil {
+		return entity.Tag{}, err
+	}
+
+	created, err := uc.repo.Create(ctx, tag)
+	if errors.Is(err, repo.ErrConflict) {
+		return entity.Tag{}, ErrNameTaken
+	}
+	if err != nil {
+		return entity.Tag{}, fmt.Errorf("tag - Create: %w", err)
+	}
+
+	return created, nil
+}
+
+// Update renames a tag or changes its kind or year, e.g. to promote a
+// free-form tag into the controlled vocabulary. Questions keep the tag.
+func (uc *UseCase) Update(ctx context.Context, id uuid.UUID, req entity.TagUpdateRequest) (entity.Tag, error) {
+	tag, err := uc.repo.Get(ctx, id)
+	if errors.Is(err, repo.ErrNotFound) {
+		return entity.Tag{}, ErrNotFound
+	}
+	if err != nil {
+		return entity.Tag{}, fmt.Errorf("tag - Get: %w", err)
+	}
+
+	if req.Name != nil {
+		tag.Name = Normalize(*req.Name)
+	}
+	if req.Kind != nil {
+		tag.Kind = *req.Kind
+	}
+	if req.Year != nil {
+		tag.Year = req.Year
+	}
+	if err := validate(tag); err != nil {
+		return entity.Tag{}, err
+	}
+
+	updated, err := uc.repo.Update(ctx, tag)
+	switch {
+	case errors.Is(err, repo.ErrNotFound):
+		return entity.Tag{}, ErrNotFound
+	case errors.Is(err, repo.ErrConflict):
+		return entity.Tag{}, ErrNameTaken
+	case err != nil:
+		return entity.Tag{}, fmt.Errorf("tag - Update: %w", err)
+	}
+
+	return updated, nil
+}
+
+// Delete removes a tag from the vocabulary and from every question.
+func (uc *UseCase) Delete(ctx context.Context, id uuid.UUID) error {
+	err := uc.repo.Delete(ctx, id)
+	if errors.Is(err, repo.ErrNotFound) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("tag - Delete: %w", err)
+	}
+
+	return nil
+}
+
+// SetQuestionTags replaces the tags of a question. Names match existing tags
+// in any case; unknown ones are created as free tags.
+func (uc *UseCase) SetQuestionTags(ctx context.Context, questionID uuid.UUID, req entity.QuestionTagsRequest) (entity.Question, error) {
+	names := NormalizeAll(req.Tags)
+	for _, name := range names {
+		if name == "" {
+			return entity.Question{}, fmt.Errorf("%w: blank name", ErrInvalidTag)
+		}
+	}
+
+	err := uc.repo.SetQuestionTags(ctx, questionID, names)
+	if errors.Is(err, repo.ErrNotFound) {
+		return entity.Question{}, ErrQuestionNotFound
+	}
+	if err != nil {
+		return entity.Question{}, fmt.Errorf("tag - SetQuestionTags: %w", err)
+	}
+
+	question, err := uc.questions.GetByID(ctx, questionID)
+	if err != nil {
+		return entity.Question{}, fmt.Errorf("tag - Questions.GetByID: %w", err)
+	}
+
+	return question, nil
+}
+
+// Normalize trims a tag name and collapses runs of whitespace.
+func Normalize(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// NormalizeAll normalizes names and drops duplicates in any case, keeping
+// the first spelling.
+func NormalizeAll(names []string) []string {
+	seen := make(map[string]struct{}, len(names))
+	out := make([]string, 0, len(names))
+	for _, name := range names {
+		name = Normalize(name)
+		key := strings.ToLower(name)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		out = append(out, name)
+	}
+
+	return out
+}
+
+func validate(tag entity.Tag) error {
+	if tag.Name == "" {
+		return fmt.Errorf("%w: blank name", ErrInvalidTag)
+	}
+	if tag.Kind == entity.TagKindPYQ && tag.Year == nil {
+		return fmt.Errorf("%w: pyq tags need a year", ErrInvalidTag)
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/repo"
+	"github.com/evrone/go-clean-template/internal/usecase/tag"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type tagMocks struct {
+	tags      *MockTagRepository
+	questions *MockQuestionRepository
+}
+
+func tagUseCase(t *testing.T) (*tag.UseCase, tagMocks) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+
+	m := tagMocks{
+		tags:      NewMockTagRepository(mockCtl),
+		questions: NewMockQuestionRepository(mockCtl),
+	}
+
+	return tag.New(m.tags, m.questions), m
+}
+
+func TestCreateTag(t *testing.T) {
+	t.Parallel()
+
+	year := 2019
+
+	t.Run("normalizes the name", func(t *testing.T) {
+		t.Parallel()
+
+		uc, m := tagUseCase(t)
+		m.tags.EXPECT().Create(gomock.Any(), entity.Tag{Name: "PYQ 2019", Kind: entity.TagKindPYQ, Year: &year}).
+			Return(entity.Tag{ID: uuid.New(), Name: "PYQ 2019", Kind: entity.TagKindPYQ, Year: &year}, nil)
+
+		created, err := uc.Create(context.Background(), entity.TagCreateRequest{Name: "  PYQ   2019 ", Kind: entity.TagKindPYQ, Year: &year})
+		require.NoError(t, err)
+		require.Equal(t, "PYQ 2019", created.Name)
+	})
+
+	t.Run("pyq tags need a year", func(t *testing.T) {
+		t.Parallel()
+
+		uc, _ := tagUseCase(t)
+
+		_, err := uc.Create(context.Background(), entity.TagCreateRequest{Name: "PYQ-2019", Kind: entity.TagKindPYQ})
+		require.ErrorIs(t, err, tag.ErrInvalidTag)
+	})
+
+	t.Run("names are unique", func(t *testing.T) {
+		t.Parallel()
+
+		uc, m := tagUseCase(t)
+		m.tags.EXPECT().Create(gomock.Any(), gomock.Any()).Return(entity.Tag{}, repo.ErrConflict)
+
+		_, err := uc.Create(context.Background(), entity.TagCreateRequest{Name: "aiims", Kind: entity.TagKindSource})
+		require.ErrorIs(t, err, tag.ErrNameTaken)
+	})
+}
+
+func TestUpdateTagPromotesFreeTag(t *testing.T) {
+	t.Parallel()
+
+	uc, m := tagUseCase(t)
+
+	id := uuid.New()
+	kind := entity.TagKindConcept
+
+	m.tags.EXPECT().Get(gomock.Any(), id).Return(entity.Tag{ID: id, Name: "one-liner", Kind: entity.TagKindFree}, nil)
+	m.tags.EXPECT().Update(gomock.Any(), entity.Tag{ID: id, Name: "one-liner", Kind: entity.TagKindConcept}).
+		DoAndReturn(func(_ context.Context, t entity.Tag) (entity.Tag, error) { return t, nil })
+
+	updated, err := uc.Update(context.Background(), id, entity.TagUpdateRequest{Kind: &kind})
+	require.NoError(t, err)
+	require.Equal(t, entity.TagKindConcept, updated.Kind)
+
+	pyq := entity.TagKindPYQ
+	m.tags.EXPECT().Get(gomock.Any(), id).Return(entity.Tag{ID: id, Name: "one-liner", Kind: entity.TagKindFree}, nil)
+
+	_, err = uc.Update(context.Background(), id, entity.TagUpdateRequest{Kind: &pyq})
+	require.ErrorIs(t, err, tag.ErrInvalidTag)
+}
+
+func TestSetQuestionTags(t *testing.T) {
+	t.Parallel()
+
+	uc, m := tagUseCase(t)
+
+	id := uuid.New()
+
+	m.tags.EXPECT().SetQuestionTags(gomock.Any(), id, []string{"PYQ-2019", "Harrison", "one-liner"}).Return(nil)
+	m.questions.EXPECT().GetByID(gomock.Any(), id).
+		Return(entity.Question{ID: id, Tags: []string{"Harrison", "one-liner", "PYQ-2019"}}, nil)
+
+	question, err := uc.SetQuestionTags(context.Background(), id, entity.QuestionTagsRequest{
+		Tags: []string{"PYQ-2019", " Harrison", "harrison", "one-liner"},
+	})
+	require.NoError(t, err)
+	require.Len(t, question.Tags, 3)
+
+	m.tags.EXPECT().SetQuestionTags(gomock.Any(), id, []string{}).Return(repo.ErrNotFound)
+
+	_, err = uc.SetQuestionTags(context.Background(), id, entity.QuestionTagsRequest{})
+	require.ErrorIs(t, err, tag.ErrQuestionNotFound)
+}
@@ -37,56 +37,37 @@ func (uc *UseCase) Me(ctx context.Context, userID uuid.UUID) (entity.MeResponse,
 	return entity.MeResponse{User: user, ExamProfile: profile}, nil
 }
 
-// ListSubjects returns subjects.
+// ListSubjects returns the active subjects in display order.
 func (uc *UseCase) ListSubjects(ctx context.Context, exam *entity.ExamCategory) ([]entity.Subject, error) {
 	subjects, err := uc.subjects.ListByExam(ctx, exam)
 	if err != nil {
 		return nil, fmt.Errorf("user - ListSubjects: %w", err)
 	}
 
-	return subjects, nil
+	active := make([]entity.Subject, 0, len(subjects))
+	for _, s := range subjects {
+		if s.IsActive {
+			active = append(active, s)
+		}
+	}
+
+	return active, nil
 }
 
-// ListTopics returns topics for a subject.
+// ListTopics returns the active topics and subtopics of a subject in display
+// order.
 func (uc *UseCase) ListTopics(ctx context.Context, subjectID uuid.UUID) ([]entity.Topic, error) {
 	topics, err := uc.topics.ListBySubject(ctx, subjectID)
 	if err != nil {
 		return nil, fmt.Errorf("user - ListTopics: %w", err)
 	}
 
-	return topics, nil
-}
-
-// CreateSubject registers a new subject.
-func (uc *UseCase) CreateSubject(ctx context.Context, exam entity.ExamCategory, name string) (entity.Subject, error) {
-	subject := entity.Subject{
-		ID:       uuid.New(),
-		Exam:     exam,
-		Name:     name,
-		IsActive: true,
-	}
-
-	created, err := uc.subjects.Create(ctx, subject)
-	if err != nil {
-		return entity.Subject{}, fmt.Errorf("user - CreateSubject: %w", err)
-	}
-
-	return created, nil
-}
-
-// CreateTopic registers a new topic.
-func (uc *UseCase) CreateTopic(ctx context.Context, subjectID uuid.UUID, name string) (entity.Topic, error) {
-	topic := entity.Topic{
-		ID:        uuid.New(),
-		SubjectID: subjectID,
-		Name:      name,
-		IsActive:  true,
-	}
-
-	created, err := uc.topics.Create(ctx, topic)
-	if err != nil {
-		return entity.Topic{}, fmt.Errorf("user - CreateTopic: %w", err)
+	active := make([]entity.Topic, 0, len(topics))
+	for _, t := range topics {
+		if t.IsActive {
+			active = append(active, t)
+		}
 	}
 
-	return created, nil
+	return active, nil
 }
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS question_pool_idx;
+DROP INDEX IF EXISTS user_question_attempt_user_question_idx;
+DROP INDEX IF EXISTS practice_session_question_question_idx;
+DROP INDEX IF EXISTS practice_session_user_idx;
+ALTER TABLE practice_session_question DROP CONSTRAINT IF EXISTS practice_session_question_sequence_uniq;
+ALTER TABLE practice_session DROP COLUMN IF EXISTS time_limit_minutes;
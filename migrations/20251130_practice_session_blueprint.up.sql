@@ -0,0 +1,10 @@
+-- Lets practice sessions persist their time limit and speeds up question selection.
+ALTER TABLE practice_session ADD COLUMN IF NOT EXISTS time_limit_minutes INT;
+
+ALTER TABLE practice_session_question
+  ADD CONSTRAINT practice_session_question_sequence_uniq UNIQUE (session_id, sequence_index);
+
+CREATE INDEX IF NOT EXISTS practice_session_user_idx ON practice_session (user_id, started_at DESC);
+CREATE INDEX IF NOT EXISTS practice_session_question_question_idx ON practice_session_question (question_id);
+CREATE INDEX IF NOT EXISTS user_question_attempt_user_question_idx ON user_question_attempt (user_id, question_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS question_pool_idx ON question (exam_type_id, subject_id, topic_id, difficulty_level) WHERE is_active;
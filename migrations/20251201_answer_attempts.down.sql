@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS user_question_attempt_session_question_uniq;
+ALTER TABLE user_question_attempt DROP COLUMN IF EXISTS session_question_id;
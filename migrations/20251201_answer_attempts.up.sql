@@ -0,0 +1,6 @@
+-- Links practice attempts to the session question they answer so each answer is logged exactly once.
+ALTER TABLE user_question_attempt
+  ADD COLUMN IF NOT EXISTS session_question_id UUID REFERENCES practice_session_question(id) ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX IF NOT EXISTS user_question_attempt_session_question_uniq
+  ON user_question_attempt (session_question_id) WHERE session_question_id IS NOT NULL;
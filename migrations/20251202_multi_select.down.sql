@@ -0,0 +1,5 @@
+ALTER TABLE exam_type_lookup DROP COLUMN IF EXISTS multi_select_scoring;
+ALTER TABLE user_question_attempt DROP COLUMN IF EXISTS score, DROP COLUMN IF EXISTS selected_options;
+ALTER TABLE practice_session_question DROP COLUMN IF EXISTS score, DROP COLUMN IF EXISTS selected_options;
+ALTER TABLE question DROP CONSTRAINT IF EXISTS question_correct_options_check;
+ALTER TABLE question DROP COLUMN IF EXISTS correct_options;
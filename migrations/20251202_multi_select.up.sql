@@ -0,0 +1,32 @@
+-- Multi-select answers: correct-answer sets on questions, selected-option sets and
+-- credit on answers, and the per-exam scoring mode. Single-choice rows are backfilled
+-- with one-element sets so they keep grading the same way.
+ALTER TABLE question ADD COLUMN IF NOT EXISTS correct_options SMALLINT[];
+UPDATE question SET correct_options = ARRAY[correct_option] WHERE correct_options IS NULL;
+ALTER TABLE question
+  ALTER COLUMN correct_options SET NOT NULL,
+  ADD CONSTRAINT question_correct_options_check CHECK (
+    cardinality(correct_options) BETWEEN 1 AND 4
+    AND correct_options <@ ARRAY[1, 2, 3, 4]::SMALLINT[]
+    AND (choice_type = 'multi' OR cardinality(correct_options) = 1)
+  );
+
+ALTER TABLE practice_session_question
+  ADD COLUMN IF NOT EXISTS selected_options SMALLINT[],
+  ADD COLUMN IF NOT EXISTS score DOUBLE PRECISION;
+UPDATE practice_session_question
+SET selected_options = ARRAY[selected_option],
+    score = CASE WHEN is_correct THEN 1 ELSE 0 END
+WHERE selected_option IS NOT NULL AND selected_options IS NULL;
+
+ALTER TABLE user_question_attempt
+  ADD COLUMN IF NOT EXISTS selected_options SMALLINT[],
+  ADD COLUMN IF NOT EXISTS score DOUBLE PRECISION;
+UPDATE user_question_attempt
+SET selected_options = ARRAY[selected_option],
+    score = CASE WHEN is_correct THEN 1 ELSE 0 END
+WHERE selected_option IS NOT NULL AND selected_options IS NULL;
+
+ALTER TABLE exam_type_lookup
+  ADD COLUMN IF NOT EXISTS multi_select_scoring TEXT NOT NULL DEFAULT 'all_or_nothing'
+  CHECK (multi_select_scoring IN ('all_or_nothing', 'partial'));
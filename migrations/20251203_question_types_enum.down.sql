@@ -0,0 +1 @@
+-- PostgreSQL cannot drop enum values; 20251204 down moves every row off them.
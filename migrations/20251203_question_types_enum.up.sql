@@ -0,0 +1,5 @@
+-- New enum values must be committed before they can be used, so the columns that
+-- depend on them live in the next migration.
+ALTER TYPE question_choice_type ADD VALUE IF NOT EXISTS 'numerical';
+ALTER TYPE question_choice_type ADD VALUE IF NOT EXISTS 'matrix_match';
+ALTER TYPE question_choice_type ADD VALUE IF NOT EXISTS 'assertion_reason';
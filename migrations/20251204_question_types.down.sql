@@ -0,0 +1,22 @@
+ALTER TABLE user_question_attempt DROP COLUMN IF EXISTS matrix_answer, DROP COLUMN IF EXISTS numeric_answer;
+ALTER TABLE practice_session_question DROP COLUMN IF EXISTS matrix_answer, DROP COLUMN IF EXISTS numeric_answer;
+
+-- Questions of the new types cannot be represented any more; park them inactive.
+UPDATE question
+SET choice_type = 'single', correct_option = 1, correct_options = ARRAY[1]::SMALLINT[], is_active = FALSE
+WHERE choice_type IN ('numerical', 'matrix_match');
+UPDATE question SET choice_type = 'single' WHERE choice_type = 'assertion_reason';
+
+ALTER TABLE question DROP CONSTRAINT IF EXISTS question_answer_key_check;
+ALTER TABLE question
+  DROP COLUMN IF EXISTS matrix,
+  DROP COLUMN IF EXISTS numeric_tolerance,
+  DROP COLUMN IF EXISTS numeric_answer,
+  DROP COLUMN IF EXISTS reason_text,
+  ALTER COLUMN correct_options SET NOT NULL,
+  ALTER COLUMN correct_option SET NOT NULL,
+  ADD CONSTRAINT question_correct_options_check CHECK (
+    cardinality(correct_options) BETWEEN 1 AND 4
+    AND correct_options <@ ARRAY[1, 2, 3, 4]::SMALLINT[]
+    AND (choice_type = 'multi' OR cardinality(correct_options) = 1)
+  );
@@ -0,0 +1,32 @@
+-- Numerical, matrix-match and assertion-reason questions. Only option-based types
+-- keep an option key; the others store their own answer key.
+ALTER TABLE question
+  ALTER COLUMN correct_option DROP NOT NULL,
+  ALTER COLUMN correct_options DROP NOT NULL,
+  ADD COLUMN IF NOT EXISTS reason_text TEXT,
+  ADD COLUMN IF NOT EXISTS numeric_answer DOUBLE PRECISION,
+  ADD COLUMN IF NOT EXISTS numeric_tolerance DOUBLE PRECISION CHECK (numeric_tolerance >= 0),
+  ADD COLUMN IF NOT EXISTS matrix JSONB;
+
+ALTER TABLE question DROP CONSTRAINT IF EXISTS question_correct_options_check;
+ALTER TABLE question ADD CONSTRAINT question_answer_key_check CHECK (
+  CASE choice_type
+    WHEN 'numerical' THEN numeric_answer IS NOT NULL
+    WHEN 'matrix_match' THEN matrix IS NOT NULL
+    WHEN 'multi' THEN correct_options IS NOT NULL
+      AND cardinality(correct_options) BETWEEN 1 AND 4
+      AND correct_options <@ ARRAY[1, 2, 3, 4]::SMALLINT[]
+    ELSE correct_option IS NOT NULL
+      AND correct_options IS NOT NULL
+      AND cardinality(correct_options) = 1
+      AND correct_options <@ ARRAY[1, 2, 3, 4]::SMALLINT[]
+  END
+);
+
+ALTER TABLE practice_session_question
+  ADD COLUMN IF NOT EXISTS numeric_answer DOUBLE PRECISION,
+  ADD COLUMN IF NOT EXISTS matrix_answer JSONB;
+
+ALTER TABLE user_question_attempt
+  ADD COLUMN IF NOT EXISTS numeric_answer DOUBLE PRECISION,
+  ADD COLUMN IF NOT EXISTS matrix_answer JSONB;
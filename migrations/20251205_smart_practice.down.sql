@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS ai_settings;
+
+ALTER TABLE practice_session_question
+  DROP COLUMN IF EXISTS selection_note,
+  DROP COLUMN IF EXISTS selection_reason;
@@ -0,0 +1,18 @@
+-- Smart practice: per-question selection rationale, and the singleton row of
+-- adaptive settings that drives it.
+ALTER TABLE practice_session_question
+  ADD COLUMN IF NOT EXISTS selection_reason TEXT,
+  ADD COLUMN IF NOT EXISTS selection_note TEXT;
+
+CREATE TABLE IF NOT EXISTS ai_settings (
+  id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+  weakness_min_attempts INT NOT NULL DEFAULT 5 CHECK (weakness_min_attempts >= 0),
+  weakness_threshold_percent INT NOT NULL DEFAULT 60 CHECK (weakness_threshold_percent BETWEEN 0 AND 100),
+  strong_threshold_percent INT NOT NULL DEFAULT 80 CHECK (strong_threshold_percent BETWEEN 0 AND 100),
+  revision_intervals_days INT[] NOT NULL DEFAULT '{1,3,7,14,30}',
+  include_guessed_correct BOOLEAN NOT NULL DEFAULT FALSE,
+  revision_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+INSERT INTO ai_settings (id) VALUES (TRUE) ON CONFLICT (id) DO NOTHING;
@@ -0,0 +1,5 @@
+ALTER TABLE user_question_attempt DROP COLUMN IF EXISTS is_guess;
+ALTER TABLE practice_session_question DROP COLUMN IF EXISTS is_guess;
+
+DROP TABLE IF EXISTS revision_item;
+DROP TYPE IF EXISTS revision_result;
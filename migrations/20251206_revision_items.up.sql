@@ -0,0 +1,27 @@
+-- Revision queue: one item per user and question, scheduled when a practice
+-- answer is missed or (optionally) guessed, and advanced by reviews.
+DO $$
+BEGIN
+  CREATE TYPE revision_result AS ENUM ('CORRECT', 'INCORRECT');
+EXCEPTION
+  WHEN duplicate_object THEN NULL;
+END $$;
+
+CREATE TABLE IF NOT EXISTS revision_item (
+  id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id         UUID NOT NULL REFERENCES "user"(id),
+  exam_type_id    INT NOT NULL REFERENCES exam_type_lookup(id),
+  question_id     UUID NOT NULL REFERENCES question(id),
+  next_review_at  TIMESTAMPTZ NOT NULL,
+  interval_index  INT NOT NULL DEFAULT 0,
+  times_reviewed  INT NOT NULL DEFAULT 0,
+  last_result     revision_result,
+  created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (user_id, question_id)
+);
+
+CREATE INDEX IF NOT EXISTS revision_item_due_idx ON revision_item (user_id, next_review_at);
+
+ALTER TABLE practice_session_question ADD COLUMN IF NOT EXISTS is_guess BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE user_question_attempt ADD COLUMN IF NOT EXISTS is_guess BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,15 @@
+ALTER TABLE ai_settings
+  DROP COLUMN IF EXISTS fsrs_params,
+  DROP COLUMN IF EXISTS sm2_params,
+  DROP COLUMN IF EXISTS revision_algorithm;
+
+DROP TABLE IF EXISTS revision_review;
+
+ALTER TABLE revision_item
+  DROP COLUMN IF EXISTS last_reviewed_at,
+  DROP COLUMN IF EXISTS lapses,
+  DROP COLUMN IF EXISTS difficulty,
+  DROP COLUMN IF EXISTS stability,
+  DROP COLUMN IF EXISTS ease,
+  DROP COLUMN IF EXISTS interval_days,
+  DROP COLUMN IF EXISTS algorithm;
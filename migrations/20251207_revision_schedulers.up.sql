@@ -0,0 +1,28 @@
+-- Pluggable revision schedulers: per-item scheduler state, a review log for
+-- replaying history through another scheduler, and the admin's choice of
+-- algorithm with its parameters.
+ALTER TABLE revision_item
+  ADD COLUMN IF NOT EXISTS algorithm TEXT NOT NULL DEFAULT 'fixed' CHECK (algorithm IN ('fixed', 'sm2', 'fsrs')),
+  ADD COLUMN IF NOT EXISTS interval_days INT NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS ease DOUBLE PRECISION NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS stability DOUBLE PRECISION NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS difficulty DOUBLE PRECISION NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS lapses INT NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS last_reviewed_at TIMESTAMPTZ;
+
+CREATE TABLE IF NOT EXISTS revision_review (
+  id            UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  item_id       UUID NOT NULL REFERENCES revision_item(id) ON DELETE CASCADE,
+  user_id       UUID NOT NULL REFERENCES "user"(id),
+  result        revision_result NOT NULL,
+  algorithm     TEXT NOT NULL,
+  interval_days INT NOT NULL,
+  reviewed_at   TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS revision_review_user_idx ON revision_review (user_id, item_id, reviewed_at);
+
+ALTER TABLE ai_settings
+  ADD COLUMN IF NOT EXISTS revision_algorithm TEXT NOT NULL DEFAULT 'fixed' CHECK (revision_algorithm IN ('fixed', 'sm2', 'fsrs')),
+  ADD COLUMN IF NOT EXISTS sm2_params JSONB NOT NULL DEFAULT '{}',
+  ADD COLUMN IF NOT EXISTS fsrs_params JSONB NOT NULL DEFAULT '{}';
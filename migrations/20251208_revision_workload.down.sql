@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS revision_review_user_time_idx;
+
+ALTER TABLE ai_settings
+  DROP COLUMN IF EXISTS revision_load_smoothing,
+  DROP COLUMN IF EXISTS revision_daily_cap;
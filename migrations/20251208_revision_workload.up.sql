@@ -0,0 +1,6 @@
+-- Revision workload controls: a per-user daily cap and load smoothing of new items.
+ALTER TABLE ai_settings
+  ADD COLUMN IF NOT EXISTS revision_daily_cap INT NOT NULL DEFAULT 0 CHECK (revision_daily_cap >= 0),
+  ADD COLUMN IF NOT EXISTS revision_load_smoothing BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS revision_review_user_time_idx ON revision_review (user_id, reviewed_at);
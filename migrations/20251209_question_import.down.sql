@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS question_import_job;
+DROP TYPE IF EXISTS question_import_status;
@@ -0,0 +1,32 @@
+-- Bulk question import jobs. The uploaded file is kept until the job finishes
+-- so a restarted worker can pick the job up again.
+DO $$
+BEGIN
+  CREATE TYPE question_import_status AS ENUM ('queued', 'running', 'completed', 'failed');
+EXCEPTION
+  WHEN duplicate_object THEN NULL;
+END $$;
+
+CREATE TABLE IF NOT EXISTS question_import_job (
+  id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  exam_type_id    INT NOT NULL REFERENCES exam_type_lookup(id),
+  format          TEXT NOT NULL,
+  file_name       TEXT NOT NULL DEFAULT '',
+  create_missing  BOOLEAN NOT NULL DEFAULT FALSE,
+  payload         BYTEA,
+  status          question_import_status NOT NULL DEFAULT 'queued',
+  total_rows      INT NOT NULL DEFAULT 0,
+  processed_rows  INT NOT NULL DEFAULT 0,
+  imported_rows   INT NOT NULL DEFAULT 0,
+  skipped_rows    INT NOT NULL DEFAULT 0,
+  failed_rows     INT NOT NULL DEFAULT 0,
+  errors          JSONB NOT NULL DEFAULT '[]',
+  error           TEXT,
+  created_by      UUID NOT NULL,
+  created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+  started_at      TIMESTAMPTZ,
+  finished_at     TIMESTAMPTZ,
+  updated_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS question_import_job_status_idx ON question_import_job (status, created_at);
@@ -0,0 +1,7 @@
+ALTER TABLE practice_session_question
+  DROP CONSTRAINT IF EXISTS practice_session_question_version_fk,
+  DROP COLUMN IF EXISTS question_version;
+
+DROP TABLE IF EXISTS question_version;
+
+ALTER TABLE question DROP COLUMN IF EXISTS version;
@@ -0,0 +1,57 @@
+-- Immutable question versions. Every create, update and rollback stores the full
+-- question as the next version, and practice_session_question records the version
+-- the learner was served. Existing questions become version 1.
+ALTER TABLE question ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
+
+CREATE TABLE IF NOT EXISTS question_version (
+  question_id        UUID NOT NULL REFERENCES question(id) ON DELETE CASCADE,
+  version            INT NOT NULL,
+  subject_id         UUID NOT NULL REFERENCES subject(id),
+  topic_id           UUID NOT NULL REFERENCES topic(id),
+  question_text      TEXT NOT NULL,
+  option_a           TEXT NOT NULL,
+  option_b           TEXT NOT NULL,
+  option_c           TEXT NOT NULL,
+  option_d           TEXT NOT NULL,
+  correct_option     SMALLINT,
+  correct_options    SMALLINT[],
+  reason_text        TEXT,
+  numeric_answer     DOUBLE PRECISION,
+  numeric_tolerance  DOUBLE PRECISION,
+  matrix             JSONB,
+  explanation        TEXT,
+  difficulty_level   SMALLINT NOT NULL,
+  choice_type        question_choice_type NOT NULL,
+  is_clinical        BOOLEAN NOT NULL,
+  is_image_based     BOOLEAN NOT NULL,
+  is_high_yield      BOOLEAN NOT NULL,
+  is_active          BOOLEAN NOT NULL,
+  changed_fields     TEXT[] NOT NULL DEFAULT '{}',
+  restored_from      INT,
+  author_id          UUID,
+  created_at         TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (question_id, version)
+);
+
+INSERT INTO question_version (
+  question_id, version, subject_id, topic_id, question_text,
+  option_a, option_b, option_c, option_d, correct_option,
+  correct_options, reason_text, numeric_answer, numeric_tolerance, matrix,
+  explanation, difficulty_level, choice_type,
+  is_clinical, is_image_based, is_high_yield, is_active, created_at
+)
+SELECT
+  id, version, subject_id, topic_id, question_text,
+  option_a, option_b, option_c, option_d, correct_option,
+  correct_options, reason_text, numeric_answer, numeric_tolerance, matrix,
+  explanation, difficulty_level, choice_type,
+  is_clinical, is_image_based, is_high_yield, is_active, created_at
+FROM question
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE practice_session_question ADD COLUMN IF NOT EXISTS question_version INT;
+UPDATE practice_session_question SET question_version = 1 WHERE question_version IS NULL;
+ALTER TABLE practice_session_question
+  ALTER COLUMN question_version SET NOT NULL,
+  ADD CONSTRAINT practice_session_question_version_fk
+    FOREIGN KEY (question_id, question_version) REFERENCES question_version (question_id, version);
@@ -0,0 +1,5 @@
+ALTER TABLE question_import_job
+  DROP COLUMN IF EXISTS duplicates,
+  DROP COLUMN IF EXISTS duplicate_rows;
+
+DROP TABLE IF EXISTS question_signature;
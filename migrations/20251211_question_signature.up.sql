@@ -0,0 +1,18 @@
+-- MinHash signatures of question texts for near-duplicate detection. A
+-- background job keeps them in step with question.version; band_keys are the
+-- locality-sensitive hashes that candidate lookups match on.
+CREATE TABLE IF NOT EXISTS question_signature (
+  question_id   UUID PRIMARY KEY REFERENCES question(id) ON DELETE CASCADE,
+  exam_type_id  INT NOT NULL REFERENCES exam_type_lookup(id),
+  version       INT NOT NULL,
+  signature     BYTEA NOT NULL,
+  band_keys     BIGINT[] NOT NULL,
+  updated_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS question_signature_band_keys_idx ON question_signature USING GIN (band_keys);
+CREATE INDEX IF NOT EXISTS question_signature_exam_idx ON question_signature (exam_type_id);
+
+ALTER TABLE question_import_job
+  ADD COLUMN IF NOT EXISTS duplicate_rows INT NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS duplicates JSONB NOT NULL DEFAULT '[]';
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS question_difficulty_idx;
+DROP INDEX IF EXISTS question_created_at_idx;
+DROP INDEX IF EXISTS question_search_idx;
+
+ALTER TABLE question DROP COLUMN IF EXISTS search;
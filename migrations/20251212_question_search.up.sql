@@ -0,0 +1,11 @@
+-- Full-text search and keyset pagination for the admin question list. The stem
+-- weighs most, then the options, then the reason and explanation.
+ALTER TABLE question ADD COLUMN IF NOT EXISTS search tsvector GENERATED ALWAYS AS (
+  setweight(to_tsvector('english', question_text), 'A') ||
+  setweight(to_tsvector('english', option_a || ' ' || option_b || ' ' || option_c || ' ' || option_d), 'B') ||
+  setweight(to_tsvector('english', coalesce(reason_text, '') || ' ' || coalesce(explanation, '')), 'C')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS question_search_idx ON question USING GIN (search);
+CREATE INDEX IF NOT EXISTS question_created_at_idx ON question (created_at, id);
+CREATE INDEX IF NOT EXISTS question_difficulty_idx ON question (difficulty_level, id);
@@ -0,0 +1,19 @@
+-- Images attached to questions. The files live in blob storage under
+-- blob_key and thumbnail_key; deleting a question drops its rows here but
+-- leaves the blobs behind.
+CREATE TABLE IF NOT EXISTS question_media (
+  id            UUID PRIMARY KEY,
+  question_id   UUID NOT NULL REFERENCES question(id) ON DELETE CASCADE,
+  slot          TEXT NOT NULL CHECK (slot IN ('stem', 'option_a', 'option_b', 'option_c', 'option_d', 'explanation')),
+  content_type  TEXT NOT NULL,
+  size_bytes    BIGINT NOT NULL,
+  width         INT NOT NULL,
+  height        INT NOT NULL,
+  alt_text      TEXT NOT NULL DEFAULT '',
+  blob_key      TEXT NOT NULL,
+  thumbnail_key TEXT NOT NULL,
+  created_by    UUID,
+  created_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS question_media_question_idx ON question_media (question_id, created_at);
@@ -0,0 +1,14 @@
+DROP TABLE IF EXISTS question_review_comment;
+
+DROP INDEX IF EXISTS question_status_idx;
+DROP INDEX IF EXISTS question_pool_idx;
+
+ALTER TABLE question DROP COLUMN IF EXISTS is_active;
+ALTER TABLE question ADD COLUMN is_active BOOLEAN NOT NULL DEFAULT TRUE;
+UPDATE question SET is_active = (status = 'published');
+
+ALTER TABLE question
+  DROP COLUMN IF EXISTS reviewer_id,
+  DROP COLUMN IF EXISTS status;
+
+CREATE INDEX IF NOT EXISTS question_pool_idx ON question (exam_type_id, subject_id, topic_id, difficulty_level) WHERE is_active;
@@ -0,0 +1,32 @@
+-- Editorial workflow: questions move draft -> in_review -> approved ->
+-- published -> retired, and only published ones are served. is_active
+-- becomes a mirror of the status so existing readers keep working; active
+-- questions start out published and inactive ones as drafts.
+ALTER TABLE question
+  ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'draft'
+    CHECK (status IN ('draft', 'in_review', 'approved', 'published', 'retired')),
+  ADD COLUMN IF NOT EXISTS reviewer_id UUID;
+
+UPDATE question SET status = 'published' WHERE is_active;
+
+-- Dropping the column also drops question_pool_idx, which filtered on it.
+ALTER TABLE question DROP COLUMN is_active;
+ALTER TABLE question ADD COLUMN is_active BOOLEAN GENERATED ALWAYS AS (status = 'published') STORED;
+
+CREATE INDEX IF NOT EXISTS question_pool_idx ON question (exam_type_id, subject_id, topic_id, difficulty_level) WHERE status = 'published';
+CREATE INDEX IF NOT EXISTS question_status_idx ON question (status, reviewer_id);
+
+-- Review comments, optionally anchored to one field of one version.
+CREATE TABLE IF NOT EXISTS question_review_comment (
+  id           UUID PRIMARY KEY,
+  question_id  UUID NOT NULL REFERENCES question(id) ON DELETE CASCADE,
+  version      INT NOT NULL,
+  field        TEXT NOT NULL DEFAULT '',
+  body         TEXT NOT NULL,
+  author_id    UUID NOT NULL,
+  created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+  resolved_by  UUID,
+  resolved_at  TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS question_review_comment_question_idx ON question_review_comment (question_id, created_at);
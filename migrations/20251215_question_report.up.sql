@@ -0,0 +1,33 @@
+-- Learners' reports of errors in questions, triaged by admins per question.
+CREATE TABLE IF NOT EXISTS question_report (
+  id               UUID PRIMARY KEY,
+  question_id      UUID NOT NULL REFERENCES question(id) ON DELETE CASCADE,
+  question_version INT NOT NULL,
+  user_id          UUID NOT NULL,
+  reason           TEXT NOT NULL
+                   CHECK (reason IN ('wrong_answer', 'wrong_explanation', 'typo', 'unclear', 'outdated', 'other')),
+  details          TEXT NOT NULL DEFAULT '',
+  status           TEXT NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'resolved', 'dismissed')),
+  resolution_note  TEXT NOT NULL DEFAULT '',
+  resolved_by      UUID,
+  resolved_at      TIMESTAMPTZ,
+  created_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- One open report per learner and question.
+CREATE UNIQUE INDEX IF NOT EXISTS question_report_open_uidx ON question_report (question_id, user_id) WHERE status = 'open';
+CREATE INDEX IF NOT EXISTS question_report_status_idx ON question_report (status, question_id);
+CREATE INDEX IF NOT EXISTS question_report_user_idx ON question_report (user_id, created_at);
+
+CREATE TABLE IF NOT EXISTS notification (
+  id          UUID PRIMARY KEY,
+  user_id     UUID NOT NULL,
+  kind        TEXT NOT NULL,
+  title       TEXT NOT NULL,
+  body        TEXT NOT NULL,
+  data        JSONB NOT NULL DEFAULT '{}',
+  created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+  read_at     TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS notification_user_idx ON notification (user_id, created_at DESC);
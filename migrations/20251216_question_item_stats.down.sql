@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS question_item_stats;
+
+ALTER TABLE question DROP COLUMN IF EXISTS difficulty_locked;
@@ -0,0 +1,17 @@
+-- Difficulty is recalibrated from attempts unless an admin locks it.
+ALTER TABLE question ADD COLUMN IF NOT EXISTS difficulty_locked BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Classical item analysis of each attempted question, rebuilt by a job.
+CREATE TABLE IF NOT EXISTS question_item_stats (
+  question_id       UUID PRIMARY KEY REFERENCES question(id) ON DELETE CASCADE,
+  attempts          INT NOT NULL,
+  p_value           DOUBLE PRECISION NOT NULL,
+  discrimination    DOUBLE PRECISION,
+  median_time_ms    INT,
+  options           JSONB NOT NULL DEFAULT '[]',
+  flags             TEXT[] NOT NULL DEFAULT '{}',
+  calibrated_level  SMALLINT,
+  computed_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS question_item_stats_flags_idx ON question_item_stats USING GIN (flags);
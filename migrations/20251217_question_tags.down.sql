@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS question_tag;
+DROP TABLE IF EXISTS tag;
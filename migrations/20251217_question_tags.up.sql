@@ -0,0 +1,21 @@
+-- Tags: a controlled vocabulary (previous-year papers, sources, concepts)
+-- plus free-form tags created as they are first assigned.
+CREATE TABLE IF NOT EXISTS tag (
+  id          UUID PRIMARY KEY,
+  name        TEXT NOT NULL,
+  kind        TEXT NOT NULL DEFAULT 'free' CHECK (kind IN ('pyq', 'source', 'concept', 'free')),
+  year        SMALLINT CHECK (year BETWEEN 1950 AND 2100),
+  created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+  CHECK (kind <> 'pyq' OR year IS NOT NULL)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS tag_name_uidx ON tag (lower(name));
+CREATE INDEX IF NOT EXISTS tag_kind_idx ON tag (kind, year);
+
+CREATE TABLE IF NOT EXISTS question_tag (
+  question_id  UUID NOT NULL REFERENCES question(id) ON DELETE CASCADE,
+  tag_id       UUID NOT NULL REFERENCES tag(id) ON DELETE CASCADE,
+  PRIMARY KEY (question_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS question_tag_tag_idx ON question_tag (tag_id, question_id);
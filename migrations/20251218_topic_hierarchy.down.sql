@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS podcast_episode;
+
+DROP INDEX IF EXISTS topic_parent_idx;
+
+ALTER TABLE topic
+  DROP COLUMN IF EXISTS parent_id,
+  DROP COLUMN IF EXISTS sort_order;
+
+ALTER TABLE subject DROP COLUMN IF EXISTS sort_order;
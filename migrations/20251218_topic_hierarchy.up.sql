@@ -0,0 +1,25 @@
+-- Syllabus management: subtopics, manual ordering, and the podcast table that
+-- topic merges move episodes in.
+ALTER TABLE subject ADD COLUMN IF NOT EXISTS sort_order INT NOT NULL DEFAULT 0;
+
+ALTER TABLE topic
+  ADD COLUMN IF NOT EXISTS parent_id UUID REFERENCES topic(id),
+  ADD COLUMN IF NOT EXISTS sort_order INT NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS topic_parent_idx ON topic (parent_id);
+
+CREATE TABLE IF NOT EXISTS podcast_episode (
+  id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  exam_type_id     INT NOT NULL REFERENCES exam_type_lookup(id),
+  subject_id       UUID REFERENCES subject(id),
+  topic_id         UUID REFERENCES topic(id),
+  title            TEXT NOT NULL,
+  description      TEXT,
+  audio_url        TEXT NOT NULL,
+  duration_seconds INT,
+  tags             JSONB,
+  is_active        BOOLEAN NOT NULL DEFAULT TRUE,
+  created_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS podcast_episode_topic_idx ON podcast_episode (topic_id);
@@ -0,0 +1,3 @@
+ALTER TABLE "user" DROP COLUMN IF EXISTS preferred_language;
+
+DROP TABLE IF EXISTS question_localization;
@@ -0,0 +1,25 @@
+-- Machine-translated question variants, corrected by editors, and the
+-- language learners want them in.
+CREATE TABLE IF NOT EXISTS question_localization (
+  question_id     UUID NOT NULL REFERENCES question(id) ON DELETE CASCADE,
+  language        TEXT NOT NULL CHECK (language ~ '^[a-z]{2,3}$' AND language <> 'en'),
+  question_text   TEXT NOT NULL,
+  option_a        TEXT NOT NULL,
+  option_b        TEXT NOT NULL,
+  option_c        TEXT NOT NULL,
+  option_d        TEXT NOT NULL,
+  reason_text     TEXT,
+  explanation     TEXT,
+  matrix_rows     TEXT[],
+  matrix_columns  TEXT[],
+  source_version  INT NOT NULL,
+  status          TEXT NOT NULL DEFAULT 'machine' CHECK (status IN ('machine', 'reviewed')),
+  reviewer_id     UUID,
+  reviewed_at     TIMESTAMPTZ,
+  updated_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (question_id, language)
+);
+
+CREATE INDEX IF NOT EXISTS question_localization_review_idx ON question_localization (language, status, updated_at DESC);
+
+ALTER TABLE "user" ADD COLUMN IF NOT EXISTS preferred_language TEXT NOT NULL DEFAULT 'en';
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS exam_config;
+DROP TYPE IF EXISTS exam_status;
+DROP TYPE IF EXISTS exam_type;
@@ -0,0 +1,35 @@
+-- Exam and event configuration for the admin exams console.
+DO $$
+BEGIN
+  CREATE TYPE exam_type AS ENUM ('MOCK', 'SUBJECT_TEST', 'REWARD_EVENT', 'DAILY_TEST');
+EXCEPTION
+  WHEN duplicate_object THEN NULL;
+END $$;
+
+DO $$
+BEGIN
+  CREATE TYPE exam_status AS ENUM ('DRAFT', 'SCHEDULED', 'ONGOING', 'COMPLETED');
+EXCEPTION
+  WHEN duplicate_object THEN NULL;
+END $$;
+
+CREATE TABLE IF NOT EXISTS exam_config (
+  id                 UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  exam_type_id       INT NOT NULL REFERENCES exam_type_lookup(id),
+  name               TEXT NOT NULL,
+  type               exam_type NOT NULL,
+  description        TEXT,
+  num_questions      INT NOT NULL CHECK (num_questions > 0),
+  time_limit_minutes INT NOT NULL CHECK (time_limit_minutes > 0),
+  marks_per_correct  NUMERIC(5,2) NOT NULL DEFAULT 4.0 CHECK (marks_per_correct >= 0),
+  negative_per_wrong NUMERIC(5,2) NOT NULL DEFAULT -1.0 CHECK (negative_per_wrong <= 0),
+  entry_fee_cents    INT NOT NULL DEFAULT 0 CHECK (entry_fee_cents >= 0),
+  schedule_start_at  TIMESTAMPTZ,
+  schedule_end_at    TIMESTAMPTZ,
+  status             exam_status NOT NULL DEFAULT 'DRAFT',
+  created_at         TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at         TIMESTAMPTZ NOT NULL DEFAULT now(),
+  CHECK (schedule_end_at > schedule_start_at)
+);
+
+CREATE INDEX IF NOT EXISTS exam_config_schedule_idx ON exam_config (status, schedule_start_at);
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS coupon_redemption;
+DROP TABLE IF EXISTS exam_registration;
+DROP TABLE IF EXISTS coupon;
+DROP TABLE IF EXISTS wallet_transaction;
+DROP TYPE IF EXISTS wallet_tx_type;
+
+ALTER TABLE exam_config
+  DROP COLUMN IF EXISTS register_until,
+  DROP COLUMN IF EXISTS capacity;
@@ -0,0 +1,69 @@
+-- Exam registration: the wallet ledger, coupons with entry passes, and the
+-- registrations they pay for.
+DO $$
+BEGIN
+  CREATE TYPE wallet_tx_type AS ENUM (
+    'REWARD', 'EXAM_ENTRY', 'EXAM_REFUND', 'COUPON', 'ADJUSTMENT', 'REFERRAL', 'SPIN', 'BONUS'
+  );
+EXCEPTION
+  WHEN duplicate_object THEN NULL;
+END $$;
+
+ALTER TYPE wallet_tx_type ADD VALUE IF NOT EXISTS 'EXAM_REFUND';
+
+ALTER TABLE exam_config
+  ADD COLUMN IF NOT EXISTS capacity INT CHECK (capacity > 0),
+  ADD COLUMN IF NOT EXISTS register_until TIMESTAMPTZ;
+
+CREATE TABLE IF NOT EXISTS wallet_transaction (
+  id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id         UUID NOT NULL REFERENCES "user"(id),
+  exam_type_id    INT REFERENCES exam_type_lookup(id),
+  exam_config_id  UUID REFERENCES exam_config(id) ON DELETE SET NULL,
+  amount_cents    INT NOT NULL, -- positive or negative
+  tx_type         wallet_tx_type NOT NULL,
+  description     TEXT,
+  created_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS wallet_transaction_user_idx ON wallet_transaction (user_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS coupon (
+  id                UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  code              TEXT NOT NULL,
+  description       TEXT,
+  type              TEXT NOT NULL CHECK (type IN ('FIXED', 'ENTRY_PASS', 'BONUS')),
+  amount_cents      INT NOT NULL DEFAULT 0 CHECK (amount_cents >= 0),
+  max_uses_total    INT NOT NULL DEFAULT 0, -- 0 for unlimited
+  max_uses_per_user INT NOT NULL DEFAULT 0, -- 0 for unlimited
+  expires_at        TIMESTAMPTZ,
+  is_active         BOOLEAN NOT NULL DEFAULT TRUE,
+  created_at        TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS coupon_code_uidx ON coupon (upper(code));
+
+CREATE TABLE IF NOT EXISTS exam_registration (
+  id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  exam_config_id UUID NOT NULL REFERENCES exam_config(id),
+  user_id        UUID NOT NULL REFERENCES "user"(id),
+  status         TEXT NOT NULL DEFAULT 'REGISTERED' CHECK (status IN ('REGISTERED', 'CANCELLED')),
+  fee_cents      INT NOT NULL DEFAULT 0,  -- debited from the wallet
+  coupon_id      UUID REFERENCES coupon(id) ON DELETE SET NULL,
+  covered_cents  INT NOT NULL DEFAULT 0,  -- paid by the entry pass
+  refund_cents   INT NOT NULL DEFAULT 0,
+  registered_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+  cancelled_at   TIMESTAMPTZ,
+  UNIQUE (exam_config_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS coupon_redemption (
+  id                   UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  coupon_id            UUID NOT NULL REFERENCES coupon(id) ON DELETE CASCADE,
+  user_id              UUID NOT NULL REFERENCES "user"(id),
+  exam_registration_id UUID REFERENCES exam_registration(id) ON DELETE CASCADE,
+  redeemed_at          TIMESTAMPTZ NOT NULL DEFAULT now(),
+  amount_cents         INT
+);
+
+CREATE INDEX IF NOT EXISTS coupon_redemption_coupon_idx ON coupon_redemption (coupon_id, user_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS exam_attempt_question;
+DROP TABLE IF EXISTS exam_attempt;
+DROP TABLE IF EXISTS exam_paper;
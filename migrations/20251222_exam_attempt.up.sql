@@ -0,0 +1,43 @@
+-- Exam attempts: the paper frozen for each exam on its first attempt, one
+-- attempt per registered user, and the answers autosaved into it.
+CREATE TABLE IF NOT EXISTS exam_paper (
+  exam_config_id   UUID NOT NULL REFERENCES exam_config(id) ON DELETE CASCADE,
+  sequence_index   INT NOT NULL,
+  question_id      UUID NOT NULL REFERENCES question(id),
+  question_version INT NOT NULL,
+  PRIMARY KEY (exam_config_id, sequence_index)
+);
+
+CREATE TABLE IF NOT EXISTS exam_attempt (
+  id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  exam_config_id UUID NOT NULL REFERENCES exam_config(id),
+  user_id        UUID NOT NULL REFERENCES "user"(id),
+  status         TEXT NOT NULL DEFAULT 'IN_PROGRESS' CHECK (status IN ('IN_PROGRESS', 'SUBMITTED', 'TIMED_OUT')),
+  started_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+  deadline_at    TIMESTAMPTZ NOT NULL,
+  submitted_at   TIMESTAMPTZ,
+  score          DOUBLE PRECISION, -- set once graded
+  correct        INT NOT NULL DEFAULT 0,
+  incorrect      INT NOT NULL DEFAULT 0,
+  skipped        INT NOT NULL DEFAULT 0,
+  UNIQUE (exam_config_id, user_id),
+  CHECK (deadline_at > started_at)
+);
+
+-- Finds attempts the expiry job has to close or grade.
+CREATE INDEX IF NOT EXISTS exam_attempt_ungraded_idx ON exam_attempt (deadline_at) WHERE score IS NULL;
+
+CREATE TABLE IF NOT EXISTS exam_attempt_question (
+  id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  attempt_id       UUID NOT NULL REFERENCES exam_attempt(id) ON DELETE CASCADE,
+  sequence_index   INT NOT NULL,
+  question_id      UUID NOT NULL REFERENCES question(id),
+  question_version INT NOT NULL,
+  selected_options SMALLINT[],
+  numeric_answer   DOUBLE PRECISION,
+  matrix_answer    JSONB,
+  answered_at      TIMESTAMPTZ,
+  is_correct       BOOLEAN,
+  marks            DOUBLE PRECISION,
+  UNIQUE (attempt_id, sequence_index)
+);
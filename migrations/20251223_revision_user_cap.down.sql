@@ -0,0 +1 @@
+ALTER TABLE "user" DROP COLUMN IF EXISTS revision_daily_cap;
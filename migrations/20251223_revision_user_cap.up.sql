@@ -0,0 +1,2 @@
+-- A learner's own revision daily cap; NULL follows ai_settings.revision_daily_cap.
+ALTER TABLE "user" ADD COLUMN IF NOT EXISTS revision_daily_cap INT CHECK (revision_daily_cap > 0);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS user_question_attempt_question_idx;
+
+DROP TABLE IF EXISTS item_analysis_learner;
+
+ALTER TABLE question_version ADD COLUMN IF NOT EXISTS difficulty_level SMALLINT;
+UPDATE question_version v SET difficulty_level = q.difficulty_level FROM question q WHERE q.id = v.question_id;
+ALTER TABLE question_version ALTER COLUMN difficulty_level SET NOT NULL;
@@ -0,0 +1,14 @@
+-- Difficulty is calibrated from learner data and set by admins in place; it
+-- is not part of a question's versioned content.
+ALTER TABLE question_version DROP COLUMN IF EXISTS difficulty_level;
+
+-- Each learner's first-attempt record, computed once per item analysis run
+-- and read by every page of it.
+CREATE UNLOGGED TABLE IF NOT EXISTS item_analysis_learner (
+  user_id   UUID PRIMARY KEY,
+  attempts  INT NOT NULL,
+  correct   INT NOT NULL
+);
+
+-- Serves the per-page first attempts of item analysis.
+CREATE INDEX IF NOT EXISTS user_question_attempt_question_idx ON user_question_attempt (question_id, user_id, created_at);
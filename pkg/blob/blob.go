@@ -0,0 +1,57 @@
+// Package blob stores binary objects, such as uploaded images, under
+// slash-separated keys and hands out time-limited download URLs for them.
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned when no object is stored under a key.
+	ErrNotFound = errors.New("blob: not found")
+	// ErrInvalidKey is returned for empty keys and keys that leave the store,
+	// such as "../x".
+	ErrInvalidKey = errors.New("blob: invalid key")
+	// ErrInvalidSignature is returned when a download URL was not issued by
+	// the store or has expired.
+	ErrInvalidSignature = errors.New("blob: invalid or expired signature")
+)
+
+// Store saves and serves objects. Implementations must be safe for
+// concurrent use.
+type Store interface {
+	// Put stores the contents of r under key, replacing any existing object.
+	Put(ctx context.Context, key, contentType string, r io.Reader) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes key. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+	// SignedURL returns a URL that downloads key without further
+	// authentication until ttl has passed.
+	SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// Verifier is implemented by stores whose signed URLs point back at this
+// service rather than at the storage provider, so the service must check
+// them before serving the object.
+type Verifier interface {
+	Verify(key string, expires int64, signature string) error
+}
+
+// cleanKey rejects keys that are empty, absolute or escape the store.
+func cleanKey(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return "", ErrInvalidKey
+	}
+
+	clean := path.Clean(key)
+	if clean != key || clean == "." || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", ErrInvalidKey
+	}
+
+	return clean, nil
+}
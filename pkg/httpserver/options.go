@@ -22,6 +22,13 @@ func Prefork(prefork bool) Option {
 	}
 }
 
+// BodyLimit -.
+func BodyLimit(bytes int) Option {
+	return func(s *Server) {
+		s.bodyLimit = bytes
+	}
+}
+
 // ReadTimeout -.
 func ReadTimeout(timeout time.Duration) Option {
 	return func(s *Server) {
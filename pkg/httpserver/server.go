@@ -29,6 +29,7 @@ type Server struct {
 
 	address         string
 	prefork         bool
+	bodyLimit       int
 	readTimeout     time.Duration
 	writeTimeout    time.Duration
 	shutdownTimeout time.Duration
@@ -60,6 +61,7 @@ func New(l logger.Interface, opts ...Option) *Server {
 
 	app := fiber.New(fiber.Config{
 		Prefork:      s.prefork,
+		BodyLimit:    s.bodyLimit,
 		ReadTimeout:  s.readTimeout,
 		WriteTimeout: s.writeTimeout,
 		JSONDecoder:  json.Unmarshal,
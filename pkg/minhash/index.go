@@ -0,0 +1,37 @@
+package minhash
+
+// Index buckets items by band key, so likely matches are found without
+// comparing every pair.
+type Index[K comparable] struct {
+	buckets map[int64][]K
+}
+
+// NewIndex returns an empty index.
+func NewIndex[K comparable]() *Index[K] {
+	return &Index[K]{buckets: make(map[int64][]K)}
+}
+
+// Add files key under each of its band keys.
+func (x *Index[K]) Add(key K, bandKeys []int64) {
+	for _, band := range bandKeys {
+		x.buckets[band] = append(x.buckets[band], key)
+	}
+}
+
+// Candidates returns the keys sharing at least one band key, once each.
+func (x *Index[K]) Candidates(bandKeys []int64) []K {
+	seen := make(map[K]struct{})
+
+	var out []K
+	for _, band := range bandKeys {
+		for _, key := range x.buckets[band] {
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			out = append(out, key)
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,186 @@
+// Package minhash estimates the similarity of short texts with MinHash
+// signatures over character shingles, and finds likely matches with
+// locality-sensitive hashing.
+package minhash
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+)
+
+const (
+	// Size is the number of hash functions in a signature.
+	Size = 128
+	// Bands is the number of LSH bands a signature is split into. With 8
+	// rows per band, pairs above about 0.7 similarity share a band.
+	Bands = 16
+
+	_rowsPerBand  = Size / Bands
+	_shingleRunes = 5
+	// _seed fixes the hash functions. Changing it invalidates every stored
+	// signature.
+	_seed = 0x9e3779b97f4a7c15
+)
+
+// ErrInvalidSignature is returned when stored bytes are not a signature.
+var ErrInvalidSignature = errors.New("minhash: invalid signature")
+
+// Signature is the MinHash signature of a text.
+type Signature [Size]uint32
+
+// Normalize lowercases text, drops punctuation and collapses whitespace, so
+// that formatting differences do not count.
+func Normalize(text string) string {
+	var b strings.Builder
+	b.Grow(len(text))
+
+	space := false
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteRune(r)
+			space = false
+		default:
+			space = true
+		}
+	}
+
+	return b.String()
+}
+
+// New returns the signature of text after normalization. Texts shorter than
+// one shingle are hashed whole.
+func New(text string) Signature {
+	var sig Signature
+	for i := range sig {
+		sig[i] = math.MaxUint32
+	}
+
+	coefficients := hashCoefficients()
+	for _, shingle := range shingles(Normalize(text)) {
+		for i, c := range coefficients {
+			// Multiply-shift hashing: the top 32 bits of a*x+b.
+			if h := uint32((c[0]*shingle + c[1]) >> 32); h < sig[i] {
+				sig[i] = h
+			}
+		}
+	}
+
+	return sig
+}
+
+// Similarity estimates the Jaccard similarity of the texts behind s and o.
+func (s Signature) Similarity(o Signature) float64 {
+	same := 0
+	for i := range s {
+		if s[i] == o[i] {
+			same++
+		}
+	}
+
+	return float64(same) / Size
+}
+
+// BandKeys hashes each band of the signature. Texts that share any key are
+// candidates for a closer look.
+func (s Signature) BandKeys() []int64 {
+	keys := make([]int64, Bands)
+
+	var buf [4 * (_rowsPerBand + 1)]byte
+	for band := range keys {
+		binary.LittleEndian.PutUint32(buf[:], uint32(band))
+		for row := range _rowsPerBand {
+			binary.LittleEndian.PutUint32(buf[4*(row+1):], s[band*_rowsPerBand+row])
+		}
+
+		h := fnv.New64a()
+		_, _ = h.Write(buf[:])
+		keys[band] = int64(h.Sum64())
+	}
+
+	return keys
+}
+
+// Bytes encodes the signature for storage.
+func (s Signature) Bytes() []byte {
+	out := make([]byte, 4*Size)
+	for i, v := range s {
+		binary.LittleEndian.PutUint32(out[4*i:], v)
+	}
+
+	return out
+}
+
+// FromBytes decodes a signature written by Bytes.
+func FromBytes(data []byte) (Signature, error) {
+	var sig Signature
+	if len(data) != 4*Size {
+		return sig, ErrInvalidSignature
+	}
+
+	for i := range sig {
+		sig[i] = binary.LittleEndian.Uint32(data[4*i:])
+	}
+
+	return sig, nil
+}
+
+// shingles hashes the overlapping runs of _shingleRunes runes in text.
+func shingles(text string) []uint64 {
+	runes := []rune(text)
+	if len(runes) == 0 {
+		return nil
+	}
+	if len(runes) < _shingleRunes {
+		return []uint64{hashString(text)}
+	}
+
+	seen := make(map[uint64]struct{}, len(runes))
+	out := make([]uint64, 0, len(runes))
+	for i := 0; i+_shingleRunes <= len(runes); i++ {
+		h := hashString(string(runes[i : i+_shingleRunes]))
+		if _, ok := seen[h]; ok {
+			continue
+		}
+		seen[h] = struct{}{}
+		out = append(out, h)
+	}
+
+	return out
+}
+
+func hashString(s string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(s))
+
+	return h.Sum64()
+}
+
+// hashCoefficients derives the Size (a, b) pairs from _seed with splitmix64.
+// a is odd, as multiply-shift hashing requires.
+func hashCoefficients() [Size][2]uint64 {
+	var out [Size][2]uint64
+
+	state := uint64(_seed)
+	next := func() uint64 {
+		state += 0x9e3779b97f4a7c15
+		z := state
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+
+		return z ^ (z >> 31)
+	}
+
+	for i := range out {
+		out[i] = [2]uint64{next() | 1, next()}
+	}
+
+	return out
+}
@@ -0,0 +1,101 @@
+// Package thumbnail decodes PNG, JPEG and GIF images and scales them down to
+// small JPEG previews, using only the standard library.
+package thumbnail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	// Registered for image.Decode.
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+)
+
+const _quality = 80
+
+// ErrUnsupported is returned for image formats other than PNG, JPEG and GIF.
+var ErrUnsupported = errors.New("thumbnail: unsupported image format")
+
+// Size reads the format and dimensions of an image without decoding it.
+func Size(r io.Reader) (format string, width, height int, err error) {
+	config, format, err := image.DecodeConfig(r)
+	if errors.Is(err, image.ErrFormat) {
+		return "", 0, 0, ErrUnsupported
+	}
+	if err != nil {
+		return "", 0, 0, fmt.Errorf("thumbnail: decode config: %w", err)
+	}
+
+	return format, config.Width, config.Height, nil
+}
+
+// JPEG decodes an image and returns a JPEG that fits in maxSide by maxSide,
+// keeping the aspect ratio. Images already that small are re-encoded at
+// their size. Transparent areas become white.
+func JPEG(r io.Reader, maxSide int) ([]byte, error) {
+	src, _, err := image.Decode(r)
+	if errors.Is(err, image.ErrFormat) {
+		return nil, ErrUnsupported
+	}
+	if err != nil {
+		return nil, fmt.Errorf("thumbnail: decode: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, scale(src, maxSide), &jpeg.Options{Quality: _quality}); err != nil {
+		return nil, fmt.Errorf("thumbnail: encode: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// scale shrinks src with a box filter: every output pixel averages the
+// source pixels it covers, which keeps text and line diagrams legible.
+func scale(src image.Image, maxSide int) *image.RGBA {
+	b := src.Bounds()
+	sw, sh := b.Dx(), b.Dy()
+
+	dw, dh := sw, sh
+	if sw > maxSide || sh > maxSide {
+		if sw >= sh {
+			dw, dh = maxSide, max(1, sh*maxSide/sw)
+		} else {
+			dw, dh = max(1, sw*maxSide/sh), maxSide
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := range dh {
+		y0, y1 := b.Min.Y+y*sh/dh, b.Min.Y+max((y+1)*sh/dh, y*sh/dh+1)
+		for x := range dw {
+			x0, x1 := b.Min.X+x*sw/dw, b.Min.X+max((x+1)*sw/dw, x*sw/dw+1)
+
+			var r, g, bl, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					// RGBA is premultiplied, so adding the uncovered part
+					// composites the pixel over white.
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					white := uint64(0xffff - ca)
+					r += uint64(cr) + white
+					g += uint64(cg) + white
+					bl += uint64(cb) + white
+					n++
+				}
+			}
+
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8(r / n >> 8),
+				G: uint8(g / n >> 8),
+				B: uint8(bl / n >> 8),
+				A: 0xff,
+			})
+		}
+	}
+
+	return dst
+}
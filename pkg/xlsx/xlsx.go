@@ -0,0 +1,256 @@
+// Package xlsx reads cell values from the first worksheet of an XLSX workbook.
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// _maxColumns is the number of columns a worksheet can have, A to XFD.
+const _maxColumns = 16384
+
+var (
+	// ErrNoWorksheet is returned when the workbook has no worksheet to read.
+	ErrNoWorksheet = errors.New("xlsx: workbook has no worksheet")
+	// ErrTooLarge is returned when a part of the workbook decompresses to
+	// more than the limit given to ReadRows.
+	ErrTooLarge = errors.New("xlsx: workbook part is too large")
+)
+
+type workbook struct {
+	Sheets []struct {
+		RelID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type relationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// richText is a shared or inline string: plain text or a list of runs.
+type richText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t richText) String() string {
+	if len(t.Runs) == 0 {
+		return t.Text
+	}
+
+	var b strings.Builder
+	for _, run := range t.Runs {
+		b.WriteString(run.Text)
+	}
+
+	return b.String()
+}
+
+type sharedStrings struct {
+	Items []richText `xml:"si"`
+}
+
+type worksheet struct {
+	Rows []struct {
+		Cells []struct {
+			Ref    string    `xml:"r,attr"`
+			Type   string    `xml:"t,attr"`
+			Value  string    `xml:"v"`
+			Inline *richText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// ReadRows returns the rows of the workbook's first worksheet as text, with
+// gaps left by empty cells filled with empty strings. Numbers are returned as
+// stored, booleans as "TRUE" or "FALSE". Each part of the workbook may
+// decompress to at most limit bytes.
+func ReadRows(r io.ReaderAt, size, limit int64) ([][]string, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("xlsx - ReadRows - zip: %w", err)
+	}
+
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+
+	sheetPath, err := firstSheetPath(files, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var shared sharedStrings
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := decode(f, limit, &shared); err != nil {
+			return nil, err
+		}
+	}
+
+	f, ok := files[sheetPath]
+	if !ok {
+		return nil, ErrNoWorksheet
+	}
+
+	var sheet worksheet
+	if err := decode(f, limit, &sheet); err != nil {
+		return nil, err
+	}
+
+	rows := make([][]string, 0, len(sheet.Rows))
+	for _, row := range sheet.Rows {
+		var values []string
+		for _, c := range row.Cells {
+			column := len(values)
+			if c.Ref != "" {
+				if column, err = columnIndex(c.Ref); err != nil {
+					return nil, err
+				}
+			}
+			for len(values) <= column {
+				values = append(values, "")
+			}
+
+			value, err := cellValue(c.Type, c.Value, c.Inline, shared)
+			if err != nil {
+				return nil, fmt.Errorf("xlsx - ReadRows - cell %s: %w", c.Ref, err)
+			}
+			values[column] = value
+		}
+		rows = append(rows, values)
+	}
+
+	return rows, nil
+}
+
+// firstSheetPath follows the workbook's relationships to its first sheet.
+func firstSheetPath(files map[string]*zip.File, limit int64) (string, error) {
+	var wb workbook
+	if f, ok := files["xl/workbook.xml"]; ok {
+		if err := decode(f, limit, &wb); err != nil {
+			return "", err
+		}
+	}
+	if len(wb.Sheets) == 0 {
+		return "", ErrNoWorksheet
+	}
+
+	var rels relationships
+	if f, ok := files["xl/_rels/workbook.xml.rels"]; ok {
+		if err := decode(f, limit, &rels); err != nil {
+			return "", err
+		}
+	}
+
+	for _, rel := range rels.Relationships {
+		if rel.ID != wb.Sheets[0].RelID {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+
+		return path.Join("xl", rel.Target), nil
+	}
+
+	return "", ErrNoWorksheet
+}
+
+func cellValue(kind, value string, inline *richText, shared sharedStrings) (string, error) {
+	switch kind {
+	case "s":
+		i, err := strconv.Atoi(value)
+		if err != nil || i < 0 || i >= len(shared.Items) {
+			return "", fmt.Errorf("invalid shared string %q", value)
+		}
+
+		return shared.Items[i].String(), nil
+	case "inlineStr":
+		if inline == nil {
+			return "", nil
+		}
+
+		return inline.String(), nil
+	case "b":
+		if value == "1" {
+			return "TRUE", nil
+		}
+
+		return "FALSE", nil
+	default:
+		return value, nil
+	}
+}
+
+// columnIndex turns the letters of a cell reference such as "AB12" into a
+// zero-based column index, up to XFD.
+func columnIndex(ref string) (int, error) {
+	column := 0
+	letters := 0
+	for _, ch := range ref {
+		if ch < 'A' || ch > 'Z' {
+			break
+		}
+		column = column*26 + int(ch-'A'+1)
+		if column > _maxColumns {
+			return 0, fmt.Errorf("xlsx: cell reference %q is past the last column", ref)
+		}
+		letters++
+	}
+	if letters == 0 {
+		return 0, fmt.Errorf("xlsx: invalid cell reference %q", ref)
+	}
+
+	return column - 1, nil
+}
+
+// decode reads the XML of f, which must decompress to at most limit bytes.
+// The size in the zip header is checked first; the reader is capped too, so
+// the limit holds whatever the header claims.
+func decode(f *zip.File, limit int64, v any) error {
+	if f.UncompressedSize64 > uint64(limit) {
+		return fmt.Errorf("xlsx - open %s: %w", f.Name, ErrTooLarge)
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("xlsx - open %s: %w", f.Name, err)
+	}
+	defer rc.Close()
+
+	r := &limitedReader{r: io.LimitReader(rc, limit+1), limit: limit}
+	if err := xml.NewDecoder(r).Decode(v); err != nil {
+		return fmt.Errorf("xlsx - decode %s: %w", f.Name, err)
+	}
+
+	return nil
+}
+
+// limitedReader fails with ErrTooLarge once more than limit bytes are read.
+type limitedReader struct {
+	r     io.Reader
+	read  int64
+	limit int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.read += int64(n)
+	if l.read > l.limit {
+		return n, ErrTooLarge
+	}
+
+	return n, err
+}
@@ -1,3 +1,7 @@
+// Command gen_medmcqa_dev_migration generated the 20251124_medmcqa_dev seed
+// migration from a MedMCQA dump. It is kept for reference only: new content is
+// uploaded through POST /v1/admin/questions/import, which reads the same
+// NDJSON shape.
 package main
 
 import (